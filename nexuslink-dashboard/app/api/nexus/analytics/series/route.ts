@@ -0,0 +1,47 @@
+import { NextResponse } from 'next/server';
+import { sessionHeader } from '@/lib/nexus-auth';
+
+const API_BASE = process.env.NEXUS_API_BASE!;
+const API_KEY = process.env.NEXUS_API_KEY!;
+
+// GET /api/nexus/analytics/series?alias=docs&granularity=hour&dimension=country&from=...&to=...
+// Without alias = all links
+export async function GET(request: Request) {
+  const { searchParams } = new URL(request.url);
+
+  const params = new URLSearchParams();
+  for (const key of ['alias', 'domain', 'granularity', 'dimension', 'from', 'to', 'top']) {
+    const value = searchParams.get(key);
+    if (value) {
+      params.set(key, value);
+    }
+  }
+
+  try {
+    const res = await fetch(`${API_BASE}/analytics/series?${params.toString()}`, {
+      headers: {
+        'X-Nexus-Api-Key': API_KEY,
+        ...(await sessionHeader()),
+      },
+      cache: 'no-store',
+    });
+
+    if (!res.ok) {
+      const text = await res.text();
+      console.error('Backend /analytics/series GET error:', res.status, text);
+      return NextResponse.json(
+        { error: text || 'Failed to fetch analytics series' },
+        { status: res.status === 400 ? 400 : 500 },
+      );
+    }
+
+    const data = await res.json();
+    return NextResponse.json(data);
+  } catch (err) {
+    console.error('Error calling backend /analytics/series:', err);
+    return NextResponse.json(
+      { error: 'Failed to fetch analytics series' },
+      { status: 500 },
+    );
+  }
+}
@@ -0,0 +1,45 @@
+import { NextRequest, NextResponse } from 'next/server';
+import { sessionHeader } from '@/lib/nexus-auth';
+
+const API_BASE = process.env.NEXUS_API_BASE || 'http://localhost:8080';
+const API_KEY = process.env.NEXUS_API_KEY || '';
+
+// GET /api/nexus/audit?actor=&action=&targetType=&targetId=&since=&until=&limit=&format=jsonl
+// Audit log perubahan admin (owner only). format=jsonl di-stream apa adanya sebagai file download.
+export async function GET(request: NextRequest) {
+  try {
+    const query = request.nextUrl.searchParams.toString();
+    const res = await fetch(`${API_BASE}/admin/audit${query ? `?${query}` : ''}`, {
+      headers: {
+        'X-Nexus-Api-Key': API_KEY,
+        ...(await sessionHeader()),
+      },
+      cache: 'no-store',
+    });
+
+    if (!res.ok) {
+      const text = await res.text();
+      return NextResponse.json(
+        { error: text || 'Failed to fetch audit log' },
+        { status: res.status }
+      );
+    }
+
+    if (request.nextUrl.searchParams.get('format') === 'jsonl') {
+      return new NextResponse(res.body, {
+        headers: {
+          'Content-Type': res.headers.get('Content-Type') || 'application/x-ndjson',
+          'Content-Disposition': res.headers.get('Content-Disposition') || 'attachment; filename="nexuslink-audit.jsonl"',
+        },
+      });
+    }
+
+    return NextResponse.json(await res.json());
+  } catch (error) {
+    console.error('Audit fetch error:', error);
+    return NextResponse.json(
+      { error: 'Internal server error' },
+      { status: 500 }
+    );
+  }
+}
@@ -1,4 +1,5 @@
 import { NextResponse } from 'next/server';
+import { sessionHeader } from '@/lib/nexus-auth';
 
 const API_BASE = process.env.NEXUS_API_BASE!;
 const API_KEY = process.env.NEXUS_API_KEY!;
@@ -10,6 +11,7 @@ export async function GET(request: Request) {
   const alias = searchParams.get('alias');
   const page = searchParams.get('page') || '1';
   const limit = searchParams.get('limit') || '10';
+  const domain = searchParams.get('domain');
 
   try {
     let url: string;
@@ -17,6 +19,9 @@ export async function GET(request: Request) {
     if (alias) {
       // Get clicks for specific link
       url = `${API_BASE}/analytics/clicks?alias=${encodeURIComponent(alias)}&page=${page}&limit=${limit}`;
+      if (domain) {
+        url += `&domain=${encodeURIComponent(domain)}`;
+      }
     } else {
       // Get all clicks (for dashboard)
       url = `${API_BASE}/analytics/clicks/all?page=${page}&limit=${limit}`;
@@ -25,6 +30,7 @@ export async function GET(request: Request) {
     const res = await fetch(url, {
       headers: {
         'X-Nexus-Api-Key': API_KEY,
+        ...(await sessionHeader()),
       },
       cache: 'no-store',
     });
@@ -1,4 +1,5 @@
 import { NextResponse } from 'next/server';
+import { sessionHeader } from '@/lib/nexus-auth';
 
 const NEXUS_API_BASE = process.env.NEXUS_API_BASE || 'http://localhost:8080';
 const NEXUS_API_KEY = process.env.NEXUS_API_KEY || 'your-secret-key';
@@ -9,6 +10,7 @@ export async function GET() {
       method: 'GET',
       headers: {
         'X-Nexus-Api-Key': NEXUS_API_KEY,
+        ...(await sessionHeader()),
       },
       cache: 'no-store',
     });
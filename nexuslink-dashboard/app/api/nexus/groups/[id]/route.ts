@@ -1,4 +1,5 @@
 import { NextRequest, NextResponse } from 'next/server';
+import { sessionHeader } from '@/lib/nexus-auth';
 
 const API_BASE = process.env.NEXUS_API_BASE || 'http://localhost:8080';
 const API_KEY = process.env.NEXUS_API_KEY || '';
@@ -12,6 +13,7 @@ export async function GET(
     const res = await fetch(`${API_BASE}/admin/groups/${id}`, {
       headers: {
         'X-Nexus-Api-Key': API_KEY,
+        ...(await sessionHeader()),
       },
       cache: 'no-store',
     });
@@ -48,6 +50,7 @@ export async function PUT(
       headers: {
         'Content-Type': 'application/json',
         'X-Nexus-Api-Key': API_KEY,
+        ...(await sessionHeader()),
       },
       body: JSON.stringify(body),
     });
@@ -81,6 +84,7 @@ export async function DELETE(
       method: 'DELETE',
       headers: {
         'X-Nexus-Api-Key': API_KEY,
+        ...(await sessionHeader()),
       },
     });
 
@@ -1,4 +1,5 @@
 import { NextResponse } from 'next/server';
+import { sessionHeader } from '@/lib/nexus-auth';
 
 const API_BASE = process.env.NEXUS_API_BASE!;
 const API_KEY = process.env.NEXUS_API_KEY!;
@@ -8,6 +9,7 @@ export async function GET() {
     const res = await fetch(`${API_BASE}/admin/link-stats`, {
       headers: {
         'X-Nexus-Api-Key': API_KEY,
+        ...(await sessionHeader()),
       },
       cache: 'no-store',
     });
@@ -1,4 +1,6 @@
 import { NextRequest, NextResponse } from 'next/server';
+import { sessionHeader } from '@/lib/nexus-auth';
+import { backendQuery } from '@/lib/link-key';
 
 const NEXUS_API_BASE = process.env.NEXUS_API_BASE || 'http://localhost:8080';
 const NEXUS_API_KEY = process.env.NEXUS_API_KEY || '';
@@ -12,11 +14,12 @@ export async function GET(
     const { searchParams } = new URL(request.url);
     const size = searchParams.get('size') || '256';
 
-    const backendUrl = `${NEXUS_API_BASE}/links/${encodeURIComponent(alias)}/qr?size=${size}`;
+    const backendUrl = `${NEXUS_API_BASE}/links/${encodeURIComponent(alias)}/qr${backendQuery(request, { size })}`;
     
     const res = await fetch(backendUrl, {
       headers: {
         'X-Nexus-Api-Key': NEXUS_API_KEY,
+        ...(await sessionHeader()),
       },
     });
 
@@ -34,7 +37,7 @@ export async function GET(
     return new NextResponse(imageBuffer, {
       headers: {
         'Content-Type': 'image/png',
-        'Cache-Control': 'public, max-age=86400',
+        'Cache-Control': 'public, max-age=300',
       },
     });
   } catch (error) {
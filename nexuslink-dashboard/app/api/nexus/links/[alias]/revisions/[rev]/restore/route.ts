@@ -0,0 +1,43 @@
+import { NextRequest, NextResponse } from 'next/server';
+import { sessionHeader } from '@/lib/nexus-auth';
+import { backendQuery } from '@/lib/link-key';
+
+const NEXUS_API_BASE = process.env.NEXUS_API_BASE || 'http://localhost:8080';
+const NEXUS_API_KEY = process.env.NEXUS_API_KEY || '';
+
+// POST /api/nexus/links/:alias/revisions/:rev/restore - Jadikan revision ini versi aktif
+export async function POST(
+  request: NextRequest,
+  context: { params: Promise<{ alias: string; rev: string }> }
+) {
+  try {
+    const { alias, rev } = await context.params;
+
+    const response = await fetch(
+      `${NEXUS_API_BASE}/links/${encodeURIComponent(alias)}/revisions/${encodeURIComponent(rev)}/restore${backendQuery(request)}`,
+      {
+        method: 'POST',
+        headers: {
+          'X-Nexus-Api-Key': NEXUS_API_KEY,
+          ...(await sessionHeader()),
+        },
+      }
+    );
+
+    if (!response.ok) {
+      const error = await response.text();
+      return NextResponse.json(
+        { error: error || 'Failed to restore revision' },
+        { status: response.status }
+      );
+    }
+
+    return NextResponse.json(await response.json());
+  } catch (error) {
+    console.error('Error restoring revision:', error);
+    return NextResponse.json(
+      { error: 'Internal server error' },
+      { status: 500 }
+    );
+  }
+}
@@ -0,0 +1,51 @@
+import { NextRequest, NextResponse } from 'next/server';
+import { sessionHeader } from '@/lib/nexus-auth';
+
+const NEXUS_API_BASE = process.env.NEXUS_API_BASE || 'http://localhost:8080';
+const NEXUS_API_KEY = process.env.NEXUS_API_KEY || '';
+
+// GET /api/nexus/links/:alias/revisions/diff?from=&to= - Diff field per field dua revision
+export async function GET(
+  request: NextRequest,
+  context: { params: Promise<{ alias: string }> }
+) {
+  try {
+    const { alias } = await context.params;
+    const { searchParams } = new URL(request.url);
+    const query = new URLSearchParams();
+    query.set('from', searchParams.get('from') || '');
+    if (searchParams.get('to')) {
+      query.set('to', searchParams.get('to') as string);
+    }
+    if (searchParams.get('domain')) {
+      query.set('domain', searchParams.get('domain') as string);
+    }
+
+    const response = await fetch(
+      `${NEXUS_API_BASE}/links/${encodeURIComponent(alias)}/revisions/diff?${query.toString()}`,
+      {
+        headers: {
+          'X-Nexus-Api-Key': NEXUS_API_KEY,
+          ...(await sessionHeader()),
+        },
+        cache: 'no-store',
+      }
+    );
+
+    if (!response.ok) {
+      const error = await response.text();
+      return NextResponse.json(
+        { error: error || 'Failed to diff revisions' },
+        { status: response.status }
+      );
+    }
+
+    return NextResponse.json(await response.json());
+  } catch (error) {
+    console.error('Error diffing revisions:', error);
+    return NextResponse.json(
+      { error: 'Internal server error' },
+      { status: 500 }
+    );
+  }
+}
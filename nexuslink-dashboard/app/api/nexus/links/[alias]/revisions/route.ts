@@ -0,0 +1,43 @@
+import { NextRequest, NextResponse } from 'next/server';
+import { sessionHeader } from '@/lib/nexus-auth';
+import { backendQuery } from '@/lib/link-key';
+
+const NEXUS_API_BASE = process.env.NEXUS_API_BASE || 'http://localhost:8080';
+const NEXUS_API_KEY = process.env.NEXUS_API_KEY || '';
+
+// GET /api/nexus/links/:alias/revisions - Riwayat versi link (index 0 = versi aktif)
+export async function GET(
+  request: NextRequest,
+  context: { params: Promise<{ alias: string }> }
+) {
+  try {
+    const { alias } = await context.params;
+
+    const response = await fetch(
+      `${NEXUS_API_BASE}/links/${encodeURIComponent(alias)}/revisions${backendQuery(request)}`,
+      {
+        headers: {
+          'X-Nexus-Api-Key': NEXUS_API_KEY,
+          ...(await sessionHeader()),
+        },
+        cache: 'no-store',
+      }
+    );
+
+    if (!response.ok) {
+      const error = await response.text();
+      return NextResponse.json(
+        { error: error || 'Failed to fetch revisions' },
+        { status: response.status }
+      );
+    }
+
+    return NextResponse.json(await response.json());
+  } catch (error) {
+    console.error('Error fetching revisions:', error);
+    return NextResponse.json(
+      { error: 'Internal server error' },
+      { status: 500 }
+    );
+  }
+}
@@ -1,4 +1,6 @@
 import { NextResponse } from 'next/server';
+import { sessionHeader } from '@/lib/nexus-auth';
+import { backendQuery } from '@/lib/link-key';
 
 const API_BASE = process.env.NEXUS_API_BASE!;
 const API_KEY = process.env.NEXUS_API_KEY!;
@@ -12,11 +14,12 @@ export async function PUT(
     const { alias } = await params;
     const body = await request.json();
 
-    const res = await fetch(`${API_BASE}/links/${encodeURIComponent(alias)}`, {
+    const res = await fetch(`${API_BASE}/links/${encodeURIComponent(alias)}${backendQuery(request)}`, {
       method: 'PUT',
       headers: {
         'Content-Type': 'application/json',
         'X-Nexus-Api-Key': API_KEY,
+        ...(await sessionHeader()),
       },
       body: JSON.stringify(body),
     });
@@ -49,10 +52,11 @@ export async function DELETE(
   try {
     const { alias } = await params;
 
-    const res = await fetch(`${API_BASE}/links/${encodeURIComponent(alias)}`, {
+    const res = await fetch(`${API_BASE}/links/${encodeURIComponent(alias)}${backendQuery(request)}`, {
       method: 'DELETE',
       headers: {
         'X-Nexus-Api-Key': API_KEY,
+        ...(await sessionHeader()),
       },
     });
 
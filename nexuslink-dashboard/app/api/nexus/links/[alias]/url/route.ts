@@ -0,0 +1,46 @@
+import { NextRequest, NextResponse } from 'next/server';
+import { sessionHeader } from '@/lib/nexus-auth';
+import { backendQuery } from '@/lib/link-key';
+
+const NEXUS_API_BASE = process.env.NEXUS_API_BASE || 'http://localhost:8080';
+const NEXUS_API_KEY = process.env.NEXUS_API_KEY || '';
+
+// GET /api/nexus/links/:alias/url - Canonical short URL (domain di node yang sehat)
+export async function GET(
+  request: NextRequest,
+  context: { params: Promise<{ alias: string }> }
+) {
+  try {
+    const { alias } = await context.params;
+    const { searchParams } = new URL(request.url);
+    const region = searchParams.get('region') || '';
+
+    const response = await fetch(
+      `${NEXUS_API_BASE}/links/${encodeURIComponent(alias)}/url${backendQuery(request, { region })}`,
+      {
+        headers: {
+          'X-Nexus-Api-Key': NEXUS_API_KEY,
+          ...(await sessionHeader()),
+        },
+        cache: 'no-store',
+      }
+    );
+
+    if (!response.ok) {
+      const error = await response.text();
+      return NextResponse.json(
+        { error: error || 'Failed to resolve short URL' },
+        { status: response.status }
+      );
+    }
+
+    const data = await response.json();
+    return NextResponse.json(data);
+  } catch (error) {
+    console.error('Error resolving short URL:', error);
+    return NextResponse.json(
+      { error: 'Internal server error' },
+      { status: 500 }
+    );
+  }
+}
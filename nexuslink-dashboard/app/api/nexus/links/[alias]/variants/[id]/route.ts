@@ -1,4 +1,6 @@
 import { NextRequest, NextResponse } from 'next/server';
+import { sessionHeader } from '@/lib/nexus-auth';
+import { backendQuery } from '@/lib/link-key';
 
 const NEXUS_API_BASE = process.env.NEXUS_API_BASE || 'http://localhost:8080';
 const NEXUS_API_KEY = process.env.NEXUS_API_KEY || '';
@@ -22,11 +24,12 @@ export async function PUT(
       }
     }
 
-    const response = await fetch(`${NEXUS_API_BASE}/links/${alias}/variants/${id}`, {
+    const response = await fetch(`${NEXUS_API_BASE}/links/${alias}/variants/${id}${backendQuery(request)}`, {
       method: 'PUT',
       headers: {
         'Content-Type': 'application/json',
         'X-Nexus-Api-Key': NEXUS_API_KEY,
+        ...(await sessionHeader()),
       },
       body: JSON.stringify(body),
     });
@@ -58,10 +61,11 @@ export async function DELETE(
   try {
     const { alias, id } = await params;
 
-    const response = await fetch(`${NEXUS_API_BASE}/links/${alias}/variants/${id}`, {
+    const response = await fetch(`${NEXUS_API_BASE}/links/${alias}/variants/${id}${backendQuery(request)}`, {
       method: 'DELETE',
       headers: {
         'X-Nexus-Api-Key': NEXUS_API_KEY,
+        ...(await sessionHeader()),
       },
     });
 
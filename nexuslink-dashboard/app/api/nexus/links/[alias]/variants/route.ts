@@ -1,4 +1,6 @@
 import { NextRequest, NextResponse } from 'next/server';
+import { sessionHeader } from '@/lib/nexus-auth';
+import { backendQuery } from '@/lib/link-key';
 
 const NEXUS_API_BASE = process.env.NEXUS_API_BASE || 'http://localhost:8080';
 const NEXUS_API_KEY = process.env.NEXUS_API_KEY || '';
@@ -11,9 +13,10 @@ export async function GET(
   try {
     const { alias } = await params;
     
-    const response = await fetch(`${NEXUS_API_BASE}/links/${alias}/variants`, {
+    const response = await fetch(`${NEXUS_API_BASE}/links/${alias}/variants${backendQuery(request)}`, {
       headers: {
         'X-Nexus-Api-Key': NEXUS_API_KEY,
+        ...(await sessionHeader()),
       },
       cache: 'no-store',
     });
@@ -68,11 +71,12 @@ export async function POST(
       );
     }
 
-    const response = await fetch(`${NEXUS_API_BASE}/links/${alias}/variants`, {
+    const response = await fetch(`${NEXUS_API_BASE}/links/${alias}/variants${backendQuery(request)}`, {
       method: 'POST',
       headers: {
         'Content-Type': 'application/json',
         'X-Nexus-Api-Key': NEXUS_API_KEY,
+        ...(await sessionHeader()),
       },
       body: JSON.stringify({
         label: body.label,
@@ -1,4 +1,5 @@
 import { NextRequest, NextResponse } from 'next/server';
+import { sessionHeader } from '@/lib/nexus-auth';
 
 const API_BASE = process.env.NEXUS_API_BASE || 'http://localhost:8080';
 const API_KEY = process.env.NEXUS_API_KEY || '';
@@ -12,6 +13,7 @@ export async function POST(request: NextRequest) {
       headers: {
         'Content-Type': 'application/json',
         'X-Nexus-Api-Key': API_KEY,
+        ...(await sessionHeader()),
       },
       body: JSON.stringify(body),
     });
@@ -1,4 +1,5 @@
 import { NextResponse } from 'next/server';
+import { sessionHeader } from '@/lib/nexus-auth';
 
 const API_BASE = process.env.NEXUS_API_BASE!;
 const API_KEY = process.env.NEXUS_API_KEY!;
@@ -13,6 +14,7 @@ export async function GET(request: Request) {
     const res = await fetch(`${API_BASE}/links?page=${page}&limit=${limit}`, {
       headers: {
         'X-Nexus-Api-Key': API_KEY,
+        ...(await sessionHeader()),
       },
       cache: 'no-store',
     });
@@ -47,6 +49,7 @@ export async function POST(request: Request) {
       headers: {
         'Content-Type': 'application/json',
         'X-Nexus-Api-Key': API_KEY,
+        ...(await sessionHeader()),
       },
       body: JSON.stringify(body), // body sudah termasuk nodeId dari frontend
     });
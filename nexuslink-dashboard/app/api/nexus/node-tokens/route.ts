@@ -1,11 +1,12 @@
 import { NextResponse } from 'next/server';
+import { sessionHeader } from '@/lib/nexus-auth';
 
 const API_BASE = process.env.NEXUS_API_BASE!;
 const API_KEY = process.env.NEXUS_API_KEY!;
 
 export async function GET() {
   const res = await fetch(`${API_BASE}/admin/node-tokens`, {
-    headers: { 'X-Nexus-Api-Key': API_KEY },
+    headers: { 'X-Nexus-Api-Key': API_KEY, ...(await sessionHeader()) },
     cache: 'no-store',
   });
 
@@ -29,6 +30,7 @@ export async function POST(request: Request) {
     headers: {
       'Content-Type': 'application/json',
       'X-Nexus-Api-Key': API_KEY,
+      ...(await sessionHeader()),
     },
     body: JSON.stringify(body),
   });
@@ -0,0 +1,65 @@
+import { NextResponse } from 'next/server';
+import { sessionHeader } from '@/lib/nexus-auth';
+
+const API_BASE = process.env.NEXUS_API_BASE!;
+const API_KEY = process.env.NEXUS_API_KEY!;
+
+// GET    /api/nexus/nodes/:id/credential - Status API key node
+// POST   /api/nexus/nodes/:id/credential - Minta agent merotasi key (lewat heartbeat berikutnya)
+// DELETE /api/nexus/nodes/:id/credential - Revoke key node
+async function proxy(method: string, id: string) {
+  try {
+    const res = await fetch(`${API_BASE}/admin/nodes/${id}/credential`, {
+      method,
+      headers: {
+        'X-Nexus-Api-Key': API_KEY,
+        ...(await sessionHeader()),
+      },
+      cache: 'no-store',
+    });
+
+    if (!res.ok) {
+      const text = await res.text();
+      if (res.status !== 404) {
+        console.error(`Backend ${method} /admin/nodes/:id/credential error:`, res.status, text);
+      }
+      return NextResponse.json(
+        { error: text || 'Failed to load credential' },
+        { status: res.status }
+      );
+    }
+
+    const data = await res.json();
+    return NextResponse.json(data, { status: 200 });
+  } catch (err) {
+    console.error('Error proxying node credential:', err);
+    return NextResponse.json(
+      { error: 'Internal dashboard error' },
+      { status: 500 }
+    );
+  }
+}
+
+export async function GET(
+  _request: Request,
+  { params }: { params: Promise<{ id: string }> }
+) {
+  const { id } = await params;
+  return proxy('GET', id);
+}
+
+export async function POST(
+  _request: Request,
+  { params }: { params: Promise<{ id: string }> }
+) {
+  const { id } = await params;
+  return proxy('POST', id);
+}
+
+export async function DELETE(
+  _request: Request,
+  { params }: { params: Promise<{ id: string }> }
+) {
+  const { id } = await params;
+  return proxy('DELETE', id);
+}
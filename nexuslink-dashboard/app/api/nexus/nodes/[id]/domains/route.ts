@@ -1,4 +1,5 @@
 import { NextResponse } from 'next/server';
+import { sessionHeader } from '@/lib/nexus-auth';
 
 const API_BASE = process.env.NEXUS_API_BASE!;
 const API_KEY = process.env.NEXUS_API_KEY!;
@@ -25,6 +26,7 @@ export async function POST(
       headers: {
         'Content-Type': 'application/json',
         'X-Nexus-Api-Key': API_KEY,
+        ...(await sessionHeader()),
       },
       body: JSON.stringify({ domain: domain.trim() }),
     });
@@ -71,6 +73,7 @@ export async function DELETE(
         method: 'DELETE',
         headers: {
           'X-Nexus-Api-Key': API_KEY,
+          ...(await sessionHeader()),
         },
       }
     );
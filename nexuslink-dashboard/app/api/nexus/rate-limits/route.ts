@@ -1,4 +1,5 @@
 import { NextRequest, NextResponse } from 'next/server';
+import { sessionHeader } from '@/lib/nexus-auth';
 
 const NEXUS_API_BASE = process.env.NEXUS_API_BASE || 'http://localhost:8080';
 const NEXUS_API_KEY = process.env.NEXUS_API_KEY || '';
@@ -16,6 +17,7 @@ export async function GET() {
       method: 'GET',
       headers: {
         'X-Nexus-Api-Key': NEXUS_API_KEY,
+        ...(await sessionHeader()),
       },
       cache: 'no-store',
     });
@@ -55,6 +57,7 @@ export async function DELETE(request: NextRequest) {
       method: 'DELETE',
       headers: {
         'X-Nexus-Api-Key': NEXUS_API_KEY,
+        ...(await sessionHeader()),
         'Content-Type': 'application/json',
       },
       body: JSON.stringify(body),
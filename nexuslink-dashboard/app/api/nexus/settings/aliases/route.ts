@@ -0,0 +1,70 @@
+import { NextRequest, NextResponse } from 'next/server';
+import { sessionHeader } from '@/lib/nexus-auth';
+
+const NEXUS_API_BASE = process.env.NEXUS_API_BASE || 'http://localhost:8080';
+const NEXUS_API_KEY = process.env.NEXUS_API_KEY || '';
+
+// GET /api/nexus/settings/aliases - Get alias generation settings
+export async function GET() {
+  try {
+    const response = await fetch(`${NEXUS_API_BASE}/admin/settings/aliases`, {
+      method: 'GET',
+      headers: {
+        'X-Nexus-Api-Key': NEXUS_API_KEY,
+        ...(await sessionHeader()),
+      },
+      cache: 'no-store',
+    });
+
+    if (!response.ok) {
+      const error = await response.text();
+      return NextResponse.json(
+        { error: error || 'Failed to fetch alias settings' },
+        { status: response.status }
+      );
+    }
+
+    const data = await response.json();
+    return NextResponse.json(data);
+  } catch (error) {
+    console.error('Error fetching alias settings:', error);
+    return NextResponse.json(
+      { error: 'Internal server error' },
+      { status: 500 }
+    );
+  }
+}
+
+// PUT /api/nexus/settings/aliases - Update alias generation settings
+export async function PUT(request: NextRequest) {
+  try {
+    const body = await request.json();
+
+    const response = await fetch(`${NEXUS_API_BASE}/admin/settings/aliases`, {
+      method: 'PUT',
+      headers: {
+        'X-Nexus-Api-Key': NEXUS_API_KEY,
+        ...(await sessionHeader()),
+        'Content-Type': 'application/json',
+      },
+      body: JSON.stringify(body),
+    });
+
+    if (!response.ok) {
+      const error = await response.text();
+      return NextResponse.json(
+        { error: error || 'Failed to update alias settings' },
+        { status: response.status }
+      );
+    }
+
+    const data = await response.json();
+    return NextResponse.json(data);
+  } catch (error) {
+    console.error('Error updating alias settings:', error);
+    return NextResponse.json(
+      { error: 'Internal server error' },
+      { status: 500 }
+    );
+  }
+}
@@ -1,4 +1,5 @@
 import { NextRequest, NextResponse } from 'next/server';
+import { sessionHeader } from '@/lib/nexus-auth';
 
 const NEXUS_API_BASE = process.env.NEXUS_API_BASE || 'http://localhost:8080';
 const NEXUS_API_KEY = process.env.NEXUS_API_KEY || '';
@@ -10,6 +11,7 @@ export async function GET() {
       method: 'GET',
       headers: {
         'X-Nexus-Api-Key': NEXUS_API_KEY,
+        ...(await sessionHeader()),
       },
       cache: 'no-store',
     });
@@ -58,6 +60,7 @@ export async function PUT(request: NextRequest) {
       method: 'PUT',
       headers: {
         'X-Nexus-Api-Key': NEXUS_API_KEY,
+        ...(await sessionHeader()),
         'Content-Type': 'application/json',
       },
       body: JSON.stringify(body),
@@ -1,4 +1,5 @@
 import { NextRequest, NextResponse } from 'next/server';
+import { sessionHeader } from '@/lib/nexus-auth';
 
 const NEXUS_API_BASE = process.env.NEXUS_API_BASE || 'http://localhost:8080';
 const NEXUS_API_KEY = process.env.NEXUS_API_KEY || '';
@@ -9,6 +10,7 @@ export async function GET() {
       method: 'GET',
       headers: {
         'X-Nexus-Api-Key': NEXUS_API_KEY,
+        ...(await sessionHeader()),
       },
       cache: 'no-store',
     });
@@ -42,6 +44,7 @@ export async function PUT(request: NextRequest) {
       headers: {
         'Content-Type': 'application/json',
         'X-Nexus-Api-Key': NEXUS_API_KEY,
+        ...(await sessionHeader()),
       },
       body: JSON.stringify(body),
     });
@@ -0,0 +1,67 @@
+import { NextRequest, NextResponse } from 'next/server';
+import { sessionHeader } from '@/lib/nexus-auth';
+
+const API_BASE = process.env.NEXUS_API_BASE || 'http://localhost:8080';
+const API_KEY = process.env.NEXUS_API_KEY || '';
+
+// GET  /api/nexus/users - List user dashboard (owner only)
+// POST /api/nexus/users - Buat user baru { username, password, role }
+export async function GET() {
+  try {
+    const res = await fetch(`${API_BASE}/admin/users`, {
+      headers: {
+        'X-Nexus-Api-Key': API_KEY,
+        ...(await sessionHeader()),
+      },
+      cache: 'no-store',
+    });
+
+    if (!res.ok) {
+      const text = await res.text();
+      return NextResponse.json(
+        { error: text || 'Failed to fetch users' },
+        { status: res.status }
+      );
+    }
+
+    return NextResponse.json(await res.json());
+  } catch (error) {
+    console.error('Users fetch error:', error);
+    return NextResponse.json(
+      { error: 'Internal server error' },
+      { status: 500 }
+    );
+  }
+}
+
+export async function POST(request: NextRequest) {
+  try {
+    const body = await request.json();
+
+    const res = await fetch(`${API_BASE}/admin/users`, {
+      method: 'POST',
+      headers: {
+        'Content-Type': 'application/json',
+        'X-Nexus-Api-Key': API_KEY,
+        ...(await sessionHeader()),
+      },
+      body: JSON.stringify(body),
+    });
+
+    if (!res.ok) {
+      const text = await res.text();
+      return NextResponse.json(
+        { error: text || 'Failed to create user' },
+        { status: res.status }
+      );
+    }
+
+    return NextResponse.json(await res.json(), { status: 201 });
+  } catch (error) {
+    console.error('User create error:', error);
+    return NextResponse.json(
+      { error: 'Internal server error' },
+      { status: 500 }
+    );
+  }
+}
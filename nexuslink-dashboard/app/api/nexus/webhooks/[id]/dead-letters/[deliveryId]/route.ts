@@ -0,0 +1,39 @@
+import { NextRequest, NextResponse } from 'next/server';
+import { sessionHeader } from '@/lib/nexus-auth';
+
+const API_BASE = process.env.NEXUS_API_BASE || 'http://localhost:8080';
+const API_KEY = process.env.NEXUS_API_KEY || '';
+
+// DELETE /api/nexus/webhooks/:id/dead-letters/:deliveryId - Buang dead letter tanpa replay
+export async function DELETE(
+  _request: NextRequest,
+  { params }: { params: Promise<{ id: string; deliveryId: string }> }
+) {
+  try {
+    const { id, deliveryId } = await params;
+
+    const res = await fetch(`${API_BASE}/admin/webhooks/${id}/dead-letters/${encodeURIComponent(deliveryId)}`, {
+      method: 'DELETE',
+      headers: {
+        'X-Nexus-Api-Key': API_KEY,
+        ...(await sessionHeader()),
+      },
+    });
+
+    if (!res.ok) {
+      const text = await res.text();
+      return NextResponse.json(
+        { error: text || 'Failed to delete dead letter' },
+        { status: res.status }
+      );
+    }
+
+    return new NextResponse(null, { status: 204 });
+  } catch (error) {
+    console.error('Error deleting webhook dead letter:', error);
+    return NextResponse.json(
+      { error: 'Internal server error' },
+      { status: 500 }
+    );
+  }
+}
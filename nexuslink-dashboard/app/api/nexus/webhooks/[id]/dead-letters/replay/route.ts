@@ -0,0 +1,40 @@
+import { NextRequest, NextResponse } from 'next/server';
+import { sessionHeader } from '@/lib/nexus-auth';
+
+const API_BASE = process.env.NEXUS_API_BASE || 'http://localhost:8080';
+const API_KEY = process.env.NEXUS_API_KEY || '';
+
+// POST /api/nexus/webhooks/:id/dead-letters/replay?event=&since=&until= - Kirim ulang dead letter di range (background)
+export async function POST(
+  request: NextRequest,
+  { params }: { params: Promise<{ id: string }> }
+) {
+  try {
+    const { id } = await params;
+    const query = request.nextUrl.searchParams.toString();
+
+    const res = await fetch(`${API_BASE}/admin/webhooks/${id}/dead-letters/replay${query ? `?${query}` : ''}`, {
+      method: 'POST',
+      headers: {
+        'X-Nexus-Api-Key': API_KEY,
+        ...(await sessionHeader()),
+      },
+    });
+
+    if (!res.ok) {
+      const text = await res.text();
+      return NextResponse.json(
+        { error: text || 'Failed to replay dead letters' },
+        { status: res.status }
+      );
+    }
+
+    return NextResponse.json(await res.json(), { status: res.status });
+  } catch (error) {
+    console.error('Error replaying webhook dead letters:', error);
+    return NextResponse.json(
+      { error: 'Internal server error' },
+      { status: 500 }
+    );
+  }
+}
@@ -0,0 +1,39 @@
+import { NextRequest, NextResponse } from 'next/server';
+import { sessionHeader } from '@/lib/nexus-auth';
+
+const API_BASE = process.env.NEXUS_API_BASE || 'http://localhost:8080';
+const API_KEY = process.env.NEXUS_API_KEY || '';
+
+// POST /api/nexus/webhooks/:id/deliveries/:deliveryId/replay - Kirim ulang satu delivery (returns delivery baru)
+export async function POST(
+  _request: NextRequest,
+  { params }: { params: Promise<{ id: string; deliveryId: string }> }
+) {
+  try {
+    const { id, deliveryId } = await params;
+
+    const res = await fetch(`${API_BASE}/admin/webhooks/${id}/deliveries/${encodeURIComponent(deliveryId)}/replay`, {
+      method: 'POST',
+      headers: {
+        'X-Nexus-Api-Key': API_KEY,
+        ...(await sessionHeader()),
+      },
+    });
+
+    if (!res.ok) {
+      const text = await res.text();
+      return NextResponse.json(
+        { error: text || 'Failed to replay delivery' },
+        { status: res.status }
+      );
+    }
+
+    return NextResponse.json(await res.json());
+  } catch (error) {
+    console.error('Error replaying webhook delivery:', error);
+    return NextResponse.json(
+      { error: 'Internal server error' },
+      { status: 500 }
+    );
+  }
+}
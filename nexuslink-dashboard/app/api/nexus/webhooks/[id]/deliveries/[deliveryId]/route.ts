@@ -0,0 +1,39 @@
+import { NextRequest, NextResponse } from 'next/server';
+import { sessionHeader } from '@/lib/nexus-auth';
+
+const API_BASE = process.env.NEXUS_API_BASE || 'http://localhost:8080';
+const API_KEY = process.env.NEXUS_API_KEY || '';
+
+// GET /api/nexus/webhooks/:id/deliveries/:deliveryId - Detail delivery (payload + attempts)
+export async function GET(
+  _request: NextRequest,
+  { params }: { params: Promise<{ id: string; deliveryId: string }> }
+) {
+  try {
+    const { id, deliveryId } = await params;
+
+    const res = await fetch(`${API_BASE}/admin/webhooks/${id}/deliveries/${encodeURIComponent(deliveryId)}`, {
+      headers: {
+        'X-Nexus-Api-Key': API_KEY,
+        ...(await sessionHeader()),
+      },
+      cache: 'no-store',
+    });
+
+    if (!res.ok) {
+      const text = await res.text();
+      return NextResponse.json(
+        { error: text || 'Failed to fetch delivery' },
+        { status: res.status }
+      );
+    }
+
+    return NextResponse.json(await res.json());
+  } catch (error) {
+    console.error('Error fetching webhook delivery:', error);
+    return NextResponse.json(
+      { error: 'Internal server error' },
+      { status: 500 }
+    );
+  }
+}
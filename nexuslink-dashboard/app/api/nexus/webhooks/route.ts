@@ -1,4 +1,5 @@
 import { NextRequest, NextResponse } from 'next/server';
+import { sessionHeader } from '@/lib/nexus-auth';
 
 const API_BASE = process.env.NEXUS_API_BASE || 'http://localhost:8080';
 const API_KEY = process.env.NEXUS_API_KEY || '';
@@ -8,6 +9,7 @@ export async function GET() {
     const res = await fetch(`${API_BASE}/admin/webhooks`, {
       headers: {
         'X-Nexus-Api-Key': API_KEY,
+        ...(await sessionHeader()),
       },
       cache: 'no-store',
     });
@@ -40,6 +42,7 @@ export async function POST(request: NextRequest) {
       headers: {
         'Content-Type': 'application/json',
         'X-Nexus-Api-Key': API_KEY,
+        ...(await sessionHeader()),
       },
       body: JSON.stringify(body),
     });
@@ -80,6 +83,7 @@ export async function PUT(request: NextRequest) {
       headers: {
         'Content-Type': 'application/json',
         'X-Nexus-Api-Key': API_KEY,
+        ...(await sessionHeader()),
       },
       body: JSON.stringify(updateData),
     });
@@ -119,6 +123,7 @@ export async function DELETE(request: NextRequest) {
       method: 'DELETE',
       headers: {
         'X-Nexus-Api-Key': API_KEY,
+        ...(await sessionHeader()),
       },
     });
 
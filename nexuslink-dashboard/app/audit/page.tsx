@@ -0,0 +1,307 @@
+'use client';
+
+import { useEffect, useState, FormEvent, useCallback } from 'react';
+import { useToast } from '@/components/Toast';
+import { LoadingSpinner } from '@/components/Loading';
+import { Table, Column } from '@/components/Table';
+
+type AuditChange = {
+  field: string;
+  before?: unknown;
+  after?: unknown;
+};
+
+type AuditRecord = {
+  id: string;
+  at: string;
+  actor: string;
+  actorRole?: string;
+  sourceIp?: string;
+  proxyIp?: string;
+  action: string;
+  targetType: string;
+  targetId: string;
+  changes?: AuditChange[];
+};
+
+type Filters = {
+  actor: string;
+  action: string;
+  targetType: string;
+  targetId: string;
+  since: string;
+  until: string;
+};
+
+const emptyFilters: Filters = { actor: '', action: '', targetType: '', targetId: '', since: '', until: '' };
+
+const targetTypes = ['link', 'variant', 'group', 'webhook', 'node', 'node_token', 'settings', 'rate_limit', 'user'];
+
+const inputClass =
+  'h-10 w-full rounded-xl border border-slate-700 bg-slate-950/50 px-3 text-slate-50 outline-none placeholder:text-slate-600 focus:border-blue-500 focus:ring-1 focus:ring-blue-500/20 transition-all';
+
+// buildQuery mengubah filter form ke query string API (datetime-local → RFC3339)
+function buildQuery(filters: Filters, extra: Record<string, string> = {}): string {
+  const params = new URLSearchParams();
+  (['actor', 'action', 'targetType', 'targetId'] as const).forEach((key) => {
+    if (filters[key].trim()) params.set(key, filters[key].trim());
+  });
+  if (filters.since) params.set('since', new Date(filters.since).toISOString());
+  if (filters.until) params.set('until', new Date(filters.until).toISOString());
+  Object.entries(extra).forEach(([k, v]) => params.set(k, v));
+  return params.toString();
+}
+
+function formatValue(value: unknown): string {
+  if (value === undefined || value === null) return '∅';
+  if (typeof value === 'string') return value;
+  return JSON.stringify(value);
+}
+
+export default function AuditPage() {
+  const { showToast } = useToast();
+  const [records, setRecords] = useState<AuditRecord[]>([]);
+  const [loading, setLoading] = useState(true);
+  const [forbidden, setForbidden] = useState(false);
+  const [filters, setFilters] = useState<Filters>(emptyFilters);
+  const [applied, setApplied] = useState<Filters>(emptyFilters);
+  const [selected, setSelected] = useState<AuditRecord | null>(null);
+
+  const loadRecords = useCallback(async () => {
+    setLoading(true);
+    try {
+      const res = await fetch(`/api/nexus/audit?${buildQuery(applied, { limit: '500' })}`, { cache: 'no-store' });
+      if (res.status === 403) {
+        setForbidden(true);
+        return;
+      }
+      if (!res.ok) {
+        throw new Error('Failed to load audit log');
+      }
+      const data: AuditRecord[] = await res.json();
+      setRecords(data);
+    } catch (err) {
+      console.error(err);
+      showToast('Failed to load audit log', 'error');
+    } finally {
+      setLoading(false);
+    }
+  }, [applied, showToast]);
+
+  useEffect(() => {
+    loadRecords();
+  }, [loadRecords]);
+
+  function handleFilter(e: FormEvent<HTMLFormElement>) {
+    e.preventDefault();
+    setApplied(filters);
+  }
+
+  function resetFilters() {
+    setFilters(emptyFilters);
+    setApplied(emptyFilters);
+  }
+
+  const columns: Column<AuditRecord>[] = [
+    {
+      header: 'Time',
+      accessorKey: 'at',
+      sortable: true,
+      render: (rec) => <span className="text-slate-300">{new Date(rec.at).toLocaleString()}</span>,
+    },
+    {
+      header: 'Actor',
+      accessorKey: 'actor',
+      sortable: true,
+      render: (rec) => (
+        <div>
+          <div className="font-medium text-slate-200">{rec.actor}</div>
+          {rec.actorRole && <div className="text-xs capitalize text-slate-500">{rec.actorRole}</div>}
+        </div>
+      ),
+    },
+    {
+      header: 'Action',
+      accessorKey: 'action',
+      sortable: true,
+      render: (rec) => (
+        <span className="rounded-full bg-sky-500/10 px-2.5 py-1 font-mono text-xs text-sky-400">{rec.action}</span>
+      ),
+    },
+    {
+      header: 'Target',
+      accessorKey: 'targetId',
+      sortable: true,
+      render: (rec) => (
+        <span className="text-slate-300">
+          <span className="text-slate-500">{rec.targetType}/</span>
+          {rec.targetId}
+        </span>
+      ),
+    },
+    {
+      header: 'Source IP',
+      accessorKey: 'sourceIp',
+      sortable: false,
+      render: (rec) => (
+        <span className="font-mono text-xs text-slate-400" title={rec.proxyIp ? `via ${rec.proxyIp}` : undefined}>
+          {rec.sourceIp || '-'}
+        </span>
+      ),
+    },
+    {
+      header: 'Changes',
+      accessorKey: 'id',
+      sortable: false,
+      render: (rec) => (
+        <button
+          onClick={() => setSelected(rec)}
+          disabled={!rec.changes?.length}
+          className="rounded-lg bg-slate-800 px-3 py-1.5 text-xs font-medium text-slate-300 hover:bg-slate-700 disabled:opacity-40 transition-colors"
+        >
+          {rec.changes?.length ? `${rec.changes.length} field${rec.changes.length > 1 ? 's' : ''}` : 'None'}
+        </button>
+      ),
+    },
+  ];
+
+  if (forbidden) {
+    return (
+      <div className="rounded-2xl border border-slate-800 bg-slate-900/50 p-6 text-sm text-slate-400">
+        Only owners can view the audit log.
+      </div>
+    );
+  }
+
+  return (
+    <div className="space-y-6">
+      <header className="flex items-center justify-between">
+        <div>
+          <h1 className="text-xl font-semibold text-slate-50">Audit Log</h1>
+          <p className="text-sm text-slate-400">
+            Who changed what, when and from where
+          </p>
+        </div>
+        <a
+          href={`/api/nexus/audit?${buildQuery(applied, { format: 'jsonl' })}`}
+          className="flex h-9 items-center rounded-lg bg-sky-500 px-4 text-sm font-medium text-white hover:bg-sky-600 transition-colors"
+        >
+          Export JSONL
+        </a>
+      </header>
+
+      {/* Filters */}
+      <form
+        onSubmit={handleFilter}
+        className="grid gap-4 rounded-2xl border border-slate-800 bg-slate-900/50 p-6 md:grid-cols-3"
+      >
+        <input
+          className={inputClass}
+          placeholder="Actor (username)"
+          value={filters.actor}
+          onChange={(e) => setFilters({ ...filters, actor: e.target.value })}
+        />
+        <input
+          className={inputClass}
+          placeholder="Action (e.g. link or link.update)"
+          value={filters.action}
+          onChange={(e) => setFilters({ ...filters, action: e.target.value })}
+        />
+        <select
+          className={inputClass}
+          value={filters.targetType}
+          onChange={(e) => setFilters({ ...filters, targetType: e.target.value })}
+        >
+          <option value="">All targets</option>
+          {targetTypes.map((t) => (
+            <option key={t} value={t}>{t}</option>
+          ))}
+        </select>
+        <input
+          className={inputClass}
+          placeholder="Target ID (e.g. link alias)"
+          value={filters.targetId}
+          onChange={(e) => setFilters({ ...filters, targetId: e.target.value })}
+        />
+        <input
+          type="datetime-local"
+          className={inputClass}
+          value={filters.since}
+          onChange={(e) => setFilters({ ...filters, since: e.target.value })}
+          aria-label="Since"
+        />
+        <input
+          type="datetime-local"
+          className={inputClass}
+          value={filters.until}
+          onChange={(e) => setFilters({ ...filters, until: e.target.value })}
+          aria-label="Until"
+        />
+        <div className="flex justify-end gap-3 md:col-span-3">
+          <button
+            type="button"
+            onClick={resetFilters}
+            className="rounded-lg px-4 py-2 text-sm font-medium text-slate-400 hover:bg-slate-800 hover:text-slate-200 transition-colors"
+          >
+            Reset
+          </button>
+          <button
+            type="submit"
+            className="rounded-lg bg-sky-500 px-6 py-2 text-sm font-medium text-white hover:bg-sky-400 transition-colors"
+          >
+            Apply Filters
+          </button>
+        </div>
+      </form>
+
+      {/* Records Table */}
+      {loading ? (
+        <div className="flex items-center justify-center py-12">
+          <LoadingSpinner size="md" />
+        </div>
+      ) : (
+        <Table
+          data={records}
+          pageSize={20}
+          emptyMessage="No audit records match these filters."
+          columns={columns}
+        />
+      )}
+
+      {/* Changes Modal */}
+      {selected && (
+        <div
+          className="fixed inset-0 z-50 flex items-center justify-center bg-black/60 backdrop-blur-sm"
+          onClick={() => setSelected(null)}
+        >
+          <div
+            className="relative max-h-[80vh] w-full max-w-2xl overflow-y-auto rounded-xl border border-slate-700 bg-slate-900 p-6 shadow-xl"
+            onClick={(e) => e.stopPropagation()}
+          >
+            <h2 className="mb-1 text-lg font-semibold text-slate-50">{selected.action}</h2>
+            <p className="mb-4 text-xs text-slate-500">
+              {selected.targetType}/{selected.targetId} · {selected.actor} · {new Date(selected.at).toLocaleString()}
+            </p>
+            <div className="space-y-3">
+              {selected.changes?.map((c) => (
+                <div key={c.field} className="rounded-lg border border-slate-800 bg-slate-950/60 p-3">
+                  <div className="mb-2 font-mono text-xs text-slate-400">{c.field}</div>
+                  <div className="grid gap-2 text-xs md:grid-cols-2">
+                    <pre className="overflow-x-auto whitespace-pre-wrap break-all rounded bg-rose-500/5 p-2 text-rose-300">{formatValue(c.before)}</pre>
+                    <pre className="overflow-x-auto whitespace-pre-wrap break-all rounded bg-emerald-500/5 p-2 text-emerald-300">{formatValue(c.after)}</pre>
+                  </div>
+                </div>
+              ))}
+            </div>
+            <button
+              onClick={() => setSelected(null)}
+              className="mt-6 w-full rounded-lg border border-slate-600 bg-slate-800 px-4 py-2 text-sm font-medium text-slate-200 hover:bg-slate-700"
+            >
+              Close
+            </button>
+          </div>
+        </div>
+      )}
+    </div>
+  );
+}
@@ -1,7 +1,7 @@
 "use client";
 
 import { useEffect, useState } from "react";
-import { useParams, useRouter } from "next/navigation";
+import { useParams, useRouter, useSearchParams } from "next/navigation";
 import {
   LineChart,
   Line,
@@ -43,6 +43,7 @@ export default function LinkAnalyticsPage() {
   const params = useParams();
   const router = useRouter();
   const alias = decodeURIComponent(params.alias as string);
+  const domain = useSearchParams().get("domain");
 
   const [events, setEvents] = useState<ClickEvent[]>([]);
   const [loading, setLoading] = useState(true);
@@ -61,7 +62,7 @@ export default function LinkAnalyticsPage() {
       setError(null);
       try {
         const res = await fetch(
-          `/api/nexus/clicks?alias=${encodeURIComponent(alias)}&page=${currentPage}&limit=${itemsPerPage}`,
+          `/api/nexus/clicks?alias=${encodeURIComponent(alias)}&page=${currentPage}&limit=${itemsPerPage}${domain ? `&domain=${encodeURIComponent(domain)}` : ""}`,
           { cache: "no-store" }
         );
 
@@ -101,7 +102,7 @@ export default function LinkAnalyticsPage() {
     }
 
     load();
-  }, [alias, currentPage, itemsPerPage]);
+  }, [alias, domain, currentPage, itemsPerPage]);
 
   function formatDate(value?: string) {
     if (!value) return "—";
@@ -185,7 +186,7 @@ export default function LinkAnalyticsPage() {
       <header className="flex flex-col gap-2 md:flex-row md:items-center md:justify-between">
         <div>
           <h1 className="text-xl font-semibold text-slate-50">
-            Analytics for <span className="font-mono">/r/{alias}</span>
+            Analytics for <span className="font-mono">{domain ? `${domain}/${alias}` : `/r/${alias}`}</span>
           </h1>
           <p className="text-sm text-slate-400">
             Detailed click events with GeoIP information.
@@ -0,0 +1,276 @@
+'use client';
+
+import { useCallback, useEffect, useState } from 'react';
+import { useParams, useRouter, useSearchParams } from 'next/navigation';
+import { useToast } from '@/components/Toast';
+import { LoadingSpinner } from '@/components/Loading';
+import { domainQuery } from '@/lib/link-key';
+
+type Revision = {
+  linkId: string;
+  revision: number;
+  author?: string;
+  createdAt: string;
+  restoredFrom?: number;
+  link: {
+    targetUrl: string;
+    isActive: boolean;
+  };
+  variants?: { id: string; label: string }[];
+};
+
+type Change = {
+  field: string;
+  before?: unknown;
+  after?: unknown;
+};
+
+function formatValue(value: unknown): string {
+  if (value === undefined || value === null) return '∅';
+  if (typeof value === 'string') return value;
+  return JSON.stringify(value, null, 2);
+}
+
+export default function RevisionsPage() {
+  const params = useParams();
+  const router = useRouter();
+  const { showToast } = useToast();
+  const alias = params.alias as string;
+  const domain = useSearchParams().get('domain');
+  const dq = domainQuery(domain);
+
+  const [revisions, setRevisions] = useState<Revision[]>([]);
+  const [current, setCurrent] = useState(0);
+  const [loading, setLoading] = useState(true);
+
+  // Diff state: bandingkan revision terpilih (from) dengan to
+  const [from, setFrom] = useState<number | null>(null);
+  const [to, setTo] = useState<number | null>(null);
+  const [changes, setChanges] = useState<Change[] | null>(null);
+  const [diffLoading, setDiffLoading] = useState(false);
+
+  const [restoreRev, setRestoreRev] = useState<number | null>(null);
+  const [restoring, setRestoring] = useState(false);
+
+  const loadRevisions = useCallback(async () => {
+    setLoading(true);
+    try {
+      const res = await fetch(`/api/nexus/links/${encodeURIComponent(alias)}/revisions${dq}`, {
+        cache: 'no-store',
+      });
+      if (!res.ok) throw new Error('Failed to fetch revisions');
+
+      const data = await res.json();
+      setRevisions(data.revisions || []);
+      setCurrent(data.current || 0);
+    } catch (error) {
+      console.error('Error loading revisions:', error);
+      showToast('Failed to load revisions', 'error');
+    } finally {
+      setLoading(false);
+    }
+  }, [alias, dq, showToast]);
+
+  useEffect(() => {
+    loadRevisions();
+  }, [loadRevisions]);
+
+  async function showDiff(fromRev: number, toRev: number) {
+    setFrom(fromRev);
+    setTo(toRev);
+    setDiffLoading(true);
+    try {
+      const res = await fetch(
+        `/api/nexus/links/${encodeURIComponent(alias)}/revisions/diff?from=${fromRev}&to=${toRev}${domain ? `&domain=${encodeURIComponent(domain)}` : ''}`,
+        { cache: 'no-store' }
+      );
+      if (!res.ok) throw new Error('Failed to diff revisions');
+
+      const data = await res.json();
+      setChanges(data.changes || []);
+    } catch (error) {
+      console.error('Error diffing revisions:', error);
+      showToast('Failed to load diff', 'error');
+      setChanges(null);
+    } finally {
+      setDiffLoading(false);
+    }
+  }
+
+  async function handleRestore() {
+    if (restoreRev === null) return;
+
+    setRestoring(true);
+    try {
+      const res = await fetch(
+        `/api/nexus/links/${encodeURIComponent(alias)}/revisions/${restoreRev}/restore${dq}`,
+        { method: 'POST' }
+      );
+      if (!res.ok) {
+        const data = await res.json().catch(() => ({}));
+        throw new Error(data.error || 'Failed to restore revision');
+      }
+
+      showToast(`Revision ${restoreRev} restored`, 'success');
+      setRestoreRev(null);
+      setChanges(null);
+      await loadRevisions();
+    } catch (error: unknown) {
+      console.error(error);
+      showToast(error instanceof Error ? error.message : 'Failed to restore revision', 'error');
+    } finally {
+      setRestoring(false);
+    }
+  }
+
+  if (loading) {
+    return (
+      <div className="flex min-h-screen items-center justify-center">
+        <LoadingSpinner size="md" />
+      </div>
+    );
+  }
+
+  return (
+    <div className="min-h-screen bg-gradient-to-br from-slate-950 via-slate-900 to-slate-950 p-6">
+      <div className="mx-auto max-w-6xl">
+        {/* Header */}
+        <div className="mb-6">
+          <button
+            onClick={() => router.push('/links')}
+            className="mb-2 text-sm text-slate-400 hover:text-slate-300"
+          >
+            ← Back to Links
+          </button>
+          <h1 className="text-3xl font-bold text-white">Revision History</h1>
+          <p className="mt-1 text-slate-400">Link: {domain ? `${domain}/${alias}` : `/${alias}`} · current revision {current}</p>
+        </div>
+
+        <div className="grid gap-6 lg:grid-cols-2">
+          {/* Revision list */}
+          <div className="space-y-3">
+            {revisions.map((rev) => (
+              <div
+                key={rev.revision}
+                className={`rounded-lg border p-4 ${
+                  from === rev.revision
+                    ? 'border-sky-500 bg-sky-500/5'
+                    : 'border-slate-700 bg-slate-900/50'
+                }`}
+              >
+                <div className="flex items-start justify-between gap-3">
+                  <div className="min-w-0">
+                    <div className="flex items-center gap-2">
+                      <span className="font-semibold text-white">#{rev.revision}</span>
+                      {rev.revision === current && (
+                        <span className="rounded-full bg-emerald-500/10 px-2 py-0.5 text-xs text-emerald-400">current</span>
+                      )}
+                      {rev.restoredFrom ? (
+                        <span className="rounded-full bg-amber-500/10 px-2 py-0.5 text-xs text-amber-400">
+                          restored from #{rev.restoredFrom}
+                        </span>
+                      ) : null}
+                    </div>
+                    <p className="mt-1 truncate text-sm text-slate-300" title={rev.link.targetUrl}>
+                      {rev.link.targetUrl}
+                    </p>
+                    <p className="mt-1 text-xs text-slate-500">
+                      {rev.author || 'unknown'} · {new Date(rev.createdAt).toLocaleString()}
+                      {rev.variants?.length ? ` · ${rev.variants.length} variants` : ''}
+                    </p>
+                  </div>
+                  {rev.revision !== current && (
+                    <div className="flex shrink-0 gap-2">
+                      <button
+                        onClick={() => showDiff(rev.revision, current)}
+                        className="rounded-lg bg-sky-500/10 px-3 py-1.5 text-xs font-medium text-sky-400 hover:bg-sky-500/20 transition-colors"
+                      >
+                        Compare
+                      </button>
+                      <button
+                        onClick={() => setRestoreRev(rev.revision)}
+                        className="rounded-lg bg-amber-500/10 px-3 py-1.5 text-xs font-medium text-amber-400 hover:bg-amber-500/20 transition-colors"
+                      >
+                        Restore
+                      </button>
+                    </div>
+                  )}
+                </div>
+              </div>
+            ))}
+          </div>
+
+          {/* Diff panel */}
+          <div className="rounded-lg border border-slate-700 bg-slate-900/50 p-4">
+            {from === null ? (
+              <p className="text-sm text-slate-400">Select a revision and click Compare to see what changed.</p>
+            ) : diffLoading ? (
+              <div className="flex justify-center py-8">
+                <LoadingSpinner size="md" />
+              </div>
+            ) : (
+              <>
+                <h2 className="mb-4 text-lg font-semibold text-white">
+                  #{from} → #{to}
+                </h2>
+                {changes && changes.length === 0 ? (
+                  <p className="text-sm text-slate-400">No differences.</p>
+                ) : (
+                  <div className="space-y-3">
+                    {changes?.map((c) => (
+                      <div key={c.field} className="rounded-lg border border-slate-800 bg-slate-950/60 p-3">
+                        <div className="mb-2 font-mono text-xs text-slate-400">{c.field}</div>
+                        <div className="grid gap-2 text-xs md:grid-cols-2">
+                          <pre className="overflow-x-auto whitespace-pre-wrap break-all rounded bg-rose-500/5 p-2 text-rose-300">{formatValue(c.before)}</pre>
+                          <pre className="overflow-x-auto whitespace-pre-wrap break-all rounded bg-emerald-500/5 p-2 text-emerald-300">{formatValue(c.after)}</pre>
+                        </div>
+                      </div>
+                    ))}
+                  </div>
+                )}
+              </>
+            )}
+          </div>
+        </div>
+
+        {/* Restore Confirmation Modal */}
+        {restoreRev !== null && (
+          <div
+            className="fixed inset-0 z-50 flex items-center justify-center bg-black/60 backdrop-blur-sm"
+            onClick={() => setRestoreRev(null)}
+          >
+            <div
+              className="relative w-full max-w-md rounded-xl border border-slate-700 bg-slate-900 p-6 shadow-xl"
+              onClick={(e) => e.stopPropagation()}
+            >
+              <h2 className="mb-4 text-lg font-semibold text-slate-50">Restore Revision</h2>
+              <p className="mb-6 text-sm text-slate-300">
+                Restore <span className="font-bold text-amber-400">#{restoreRev}</span> as the current version of /{alias}?
+                <br />
+                <span className="text-slate-500">
+                  Targets, rules, schedule and A/B variants are replaced. The current version stays in the history.
+                </span>
+              </p>
+              <div className="flex gap-3">
+                <button
+                  onClick={() => setRestoreRev(null)}
+                  className="flex-1 rounded-lg border border-slate-600 bg-slate-800 px-4 py-2 text-sm font-medium text-slate-200 hover:bg-slate-700"
+                >
+                  Cancel
+                </button>
+                <button
+                  onClick={handleRestore}
+                  disabled={restoring}
+                  className="flex flex-1 items-center justify-center gap-2 rounded-lg bg-amber-500 px-4 py-2 text-sm font-medium text-white hover:bg-amber-400 disabled:opacity-60"
+                >
+                  {restoring && <LoadingSpinner size="sm" />}
+                  Restore
+                </button>
+              </div>
+            </div>
+          </div>
+        )}
+      </div>
+    </div>
+  );
+}
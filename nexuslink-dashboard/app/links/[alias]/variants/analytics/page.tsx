@@ -1,9 +1,10 @@
 'use client';
 
 import { useEffect, useState } from 'react';
-import { useParams, useRouter } from 'next/navigation';
+import { useParams, useRouter, useSearchParams } from 'next/navigation';
 import { useToast } from '@/components/Toast';
 import { LoadingSpinner } from '@/components/Loading';
+import { domainQuery } from '@/lib/link-key';
 
 type Variant = {
   id: string;
@@ -22,18 +23,20 @@ export default function VariantsAnalyticsPage() {
   const router = useRouter();
   const { showToast } = useToast();
   const alias = params.alias as string;
+  const domain = useSearchParams().get('domain');
+  const dq = domainQuery(domain);
 
   const [variants, setVariants] = useState<Variant[]>([]);
   const [loading, setLoading] = useState(true);
 
   useEffect(() => {
     loadVariants();
-  }, [alias]);
+  }, [alias, domain]);
 
   async function loadVariants() {
     setLoading(true);
     try {
-      const res = await fetch(`/api/nexus/links/${alias}/variants`, {
+      const res = await fetch(`/api/nexus/links/${alias}/variants${dq}`, {
         cache: 'no-store',
       });
 
@@ -90,7 +93,7 @@ export default function VariantsAnalyticsPage() {
         {/* Header */}
         <div className="mb-6">
           <button
-            onClick={() => router.push(`/links/${alias}/variants`)}
+            onClick={() => router.push(`/links/${alias}/variants${dq}`)}
             className="mb-2 text-sm text-slate-400 hover:text-slate-300"
           >
             ← Back to Variants
@@ -98,7 +101,7 @@ export default function VariantsAnalyticsPage() {
           <h1 className="text-3xl font-bold text-white">
             A/B Test Analytics
           </h1>
-          <p className="mt-1 text-slate-400">Link: /{alias}</p>
+          <p className="mt-1 text-slate-400">Link: {domain ? `${domain}/${alias}` : `/${alias}`}</p>
         </div>
 
         {variants.length === 0 ? (
@@ -107,7 +110,7 @@ export default function VariantsAnalyticsPage() {
               No variants to analyze. Create some variants first!
             </p>
             <button
-              onClick={() => router.push(`/links/${alias}/variants`)}
+              onClick={() => router.push(`/links/${alias}/variants${dq}`)}
               className="mt-4 rounded-lg bg-sky-500 px-6 py-2 font-medium text-white hover:bg-sky-600"
             >
               Create Variants
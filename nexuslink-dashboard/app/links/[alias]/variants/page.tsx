@@ -1,9 +1,10 @@
 'use client';
 
 import { useEffect, useState } from 'react';
-import { useParams, useRouter } from 'next/navigation';
+import { useParams, useRouter, useSearchParams } from 'next/navigation';
 import { useToast } from '@/components/Toast';
 import { LoadingSpinner } from '@/components/Loading';
+import { domainQuery } from '@/lib/link-key';
 
 type Variant = {
   id: string;
@@ -22,6 +23,8 @@ export default function VariantsPage() {
   const router = useRouter();
   const { showToast } = useToast();
   const alias = params.alias as string;
+  const domain = useSearchParams().get('domain');
+  const dq = domainQuery(domain);
 
   const [variants, setVariants] = useState<Variant[]>([]);
   const [loading, setLoading] = useState(true);
@@ -39,12 +42,12 @@ export default function VariantsPage() {
 
   useEffect(() => {
     loadVariants();
-  }, [alias]);
+  }, [alias, domain]);
 
   async function loadVariants() {
     setLoading(true);
     try {
-      const res = await fetch(`/api/nexus/links/${alias}/variants`, {
+      const res = await fetch(`/api/nexus/links/${alias}/variants${dq}`, {
         cache: 'no-store',
       });
 
@@ -77,8 +80,8 @@ export default function VariantsPage() {
 
     try {
       const url = editingId
-        ? `/api/nexus/links/${alias}/variants/${editingId}`
-        : `/api/nexus/links/${alias}/variants`;
+        ? `/api/nexus/links/${alias}/variants/${editingId}${dq}`
+        : `/api/nexus/links/${alias}/variants${dq}`;
 
       const method = editingId ? 'PUT' : 'POST';
 
@@ -108,7 +111,7 @@ export default function VariantsPage() {
 
   async function handleDelete(id: string) {
     try {
-      const res = await fetch(`/api/nexus/links/${alias}/variants/${id}`, {
+      const res = await fetch(`/api/nexus/links/${alias}/variants/${id}${dq}`, {
         method: 'DELETE',
       });
 
@@ -165,12 +168,12 @@ export default function VariantsPage() {
             <h1 className="text-3xl font-bold text-white">
               A/B Test Variants
             </h1>
-            <p className="mt-1 text-slate-400">Link: /{alias}</p>
+            <p className="mt-1 text-slate-400">Link: {domain ? `${domain}/${alias}` : `/${alias}`}</p>
           </div>
           <div className="flex gap-3">
             {variants.length > 0 && (
               <button
-                onClick={() => router.push(`/links/${alias}/variants/analytics`)}
+                onClick={() => router.push(`/links/${alias}/variants/analytics${dq}`)}
                 className="rounded-lg border border-slate-700 px-4 py-2 font-medium text-slate-300 hover:bg-slate-800"
               >
                 📊 Analytics
@@ -5,6 +5,7 @@ import { useToast } from '@/components/Toast';
 import { LoadingSpinner } from '@/components/Loading';
 import { MultiSelect } from '@/components/MultiSelect';
 import { Table, Column } from '@/components/Table';
+import { linkKey, linkPath } from '@/lib/link-key';
 import { 
   ExternalLink, 
   Edit2, 
@@ -15,12 +16,14 @@ import {
   Shield, 
   CheckCircle, 
   XCircle,
-  Filter
+  Filter,
+  History
 } from 'lucide-react';
 
 type LinkItem = {
   id: string;
   alias: string;
+  key?: string;
   targetUrl: string;
   nodeId?: string;
   groupId?: string;
@@ -109,6 +112,7 @@ export default function LinksPage() {
    const [expiresAt, setExpiresAt] = useState('');
    const [maxClicks, setMaxClicks] = useState('');
    const [qrModalAlias, setQrModalAlias] = useState<string | null>(null);
+  const [qrShortUrl, setQrShortUrl] = useState<{ shortUrl: string; degraded: boolean } | null>(null);
    const [activeFrom, setActiveFrom] = useState('');
    const [activeUntil, setActiveUntil] = useState('');
   const [deleteConfirmAlias, setDeleteConfirmAlias] = useState<string | null>(null);
@@ -116,6 +120,18 @@ export default function LinksPage() {
 
   
 
+  // Canonical short URL untuk QR modal (domain di node yang sehat)
+  useEffect(() => {
+    if (!qrModalAlias) {
+      setQrShortUrl(null);
+      return;
+    }
+    fetch(`/api/nexus/links/${linkPath(qrModalAlias, '/url')}`, { cache: 'no-store' })
+      .then((res) => (res.ok ? res.json() : null))
+      .then((data) => setQrShortUrl(data))
+      .catch(() => setQrShortUrl(null));
+  }, [qrModalAlias]);
+
   const loadData = useCallback(async () => {
     setLoading(true);
     try {
@@ -228,7 +244,7 @@ export default function LinksPage() {
     setAllowedCountries(link.allowedCountries || []);
     setFallbackUrl(link.fallbackUrl || '');
     setBlockBots(link.blockBots || false);
-    setEditingAlias(link.alias);
+    setEditingAlias(linkKey(link));
     setShowForm(true);
     
     // Scroll to form
@@ -239,7 +255,8 @@ export default function LinksPage() {
     e.preventDefault();
     const aliasTrimmed = alias.trim();
     const targetTrimmed = targetUrl.trim();
-    if (!aliasTrimmed || !targetTrimmed) return;
+    // Alias kosong saat create = digenerate oleh API
+    if ((editingAlias && !aliasTrimmed) || !targetTrimmed) return;
 
     setSaving(true);
 
@@ -265,7 +282,7 @@ export default function LinksPage() {
       let res;
       if (editingAlias) {
         // Update existing link
-        res = await fetch(`/api/nexus/links/${encodeURIComponent(editingAlias)}`, {
+        res = await fetch(`/api/nexus/links/${linkPath(editingAlias)}`, {
           method: 'PUT',
           headers: { 'Content-Type': 'application/json' },
           body: JSON.stringify(payload),
@@ -286,11 +303,14 @@ export default function LinksPage() {
         return;
       }
 
+      const saved = await res.json().catch(() => null);
+      const savedAlias = saved?.alias || aliasTrimmed;
+
       resetForm();
       showToast(
         editingAlias 
-          ? `Link "${aliasTrimmed}" updated successfully!` 
-          : `Link "${aliasTrimmed}" created successfully!`, 
+          ? `Link "${savedAlias}" updated successfully!` 
+          : `Link "${savedAlias}" created successfully!`, 
         'success'
       );
       await loadData();
@@ -347,7 +367,7 @@ export default function LinksPage() {
     if (selectedAliases.size === links.length) {
       setSelectedAliases(new Set());
     } else {
-      setSelectedAliases(new Set(links.map((l) => l.alias)));
+      setSelectedAliases(new Set(links.map((l) => linkKey(l))));
     }
   }
 
@@ -409,7 +429,7 @@ export default function LinksPage() {
     setIsDeleting(true);
     
     try {
-      const res = await fetch(`/api/nexus/links/${encodeURIComponent(alias)}`, {
+      const res = await fetch(`/api/nexus/links/${linkPath(alias)}`, {
         method: 'DELETE',
       });
 
@@ -471,8 +491,8 @@ export default function LinksPage() {
       render: (link: LinkItem) => (
         <input
           type="checkbox"
-          checked={selectedAliases.has(link.alias)}
-          onChange={() => toggleSelection(link.alias)}
+          checked={selectedAliases.has(linkKey(link))}
+          onChange={() => toggleSelection(linkKey(link))}
           className="h-4 w-4 rounded border-slate-600 bg-slate-950 text-blue-500 focus:ring-blue-500/20"
           aria-label={`Select ${link.alias}`}
         />
@@ -545,7 +565,7 @@ export default function LinksPage() {
       accessorKey: 'id', // Dummy accessor
       render: (link: LinkItem) => (
         <span className="font-mono text-slate-200">
-          {getTotalHits(link.alias).toLocaleString()}
+          {getTotalHits(linkKey(link)).toLocaleString()}
         </span>
       ),
     },
@@ -581,7 +601,7 @@ export default function LinksPage() {
       render: (link: LinkItem) => (
         <div className="flex items-center justify-end gap-2">
           <button
-            onClick={() => setQrModalAlias(link.alias)}
+            onClick={() => setQrModalAlias(linkKey(link))}
             className="rounded p-1.5 text-slate-400 hover:bg-slate-800 hover:text-emerald-400 transition-colors"
             title="Show QR Code"
             aria-label="Show QR Code"
@@ -589,7 +609,7 @@ export default function LinksPage() {
             <QrCode size={14} />
           </button>
           <a
-            href={`/links/${encodeURIComponent(link.alias)}/analytics`}
+            href={`/links/${linkPath(linkKey(link), '/analytics')}`}
             className="rounded p-1.5 text-slate-400 hover:bg-slate-800 hover:text-sky-400 transition-colors"
             title="Analytics"
             aria-label="Analytics"
@@ -597,13 +617,21 @@ export default function LinksPage() {
             <BarChart2 size={14} />
           </a>
           <a
-            href={`/links/${encodeURIComponent(link.alias)}/variants`}
+            href={`/links/${linkPath(linkKey(link), '/variants')}`}
             className="rounded p-1.5 text-slate-400 hover:bg-slate-800 hover:text-purple-400 transition-colors"
             title="A/B Variants"
             aria-label="A/B Variants"
           >
             <Split size={14} />
           </a>
+          <a
+            href={`/links/${linkPath(linkKey(link), '/revisions')}`}
+            className="rounded p-1.5 text-slate-400 hover:bg-slate-800 hover:text-amber-400 transition-colors"
+            title="Revision History"
+            aria-label="Revision History"
+          >
+            <History size={14} />
+          </a>
           <button
             onClick={() => startEdit(link)}
             className="rounded p-1.5 text-slate-400 hover:bg-slate-800 hover:text-amber-400 transition-colors"
@@ -613,7 +641,7 @@ export default function LinksPage() {
             <Edit2 size={14} />
           </button>
           <button
-            onClick={() => setDeleteConfirmAlias(link.alias)}
+            onClick={() => setDeleteConfirmAlias(linkKey(link))}
             className="rounded p-1.5 text-slate-400 hover:bg-slate-800 hover:text-rose-400 transition-colors"
             title="Delete"
             aria-label="Delete"
@@ -681,14 +709,16 @@ export default function LinksPage() {
                     <span className="mr-2 text-slate-500 font-mono">/r/</span>
                     <input
                       className="h-10 flex-1 border-none bg-transparent text-slate-50 outline-none placeholder:text-slate-600 disabled:opacity-50 disabled:cursor-not-allowed"
-                      placeholder="my-link"
+                      placeholder={editingAlias ? 'my-link' : 'auto-generate'}
                       value={alias}
                       onChange={(e) => setAlias(e.target.value)}
                       disabled={!!editingAlias}
                     />
                   </div>
-                  {editingAlias && (
+                  {editingAlias ? (
                     <p className="mt-1.5 text-xs text-slate-500">Alias cannot be changed once created.</p>
+                  ) : (
+                    <p className="mt-1.5 text-xs text-slate-500">Leave empty to auto-generate a short alias.</p>
                   )}
                 </div>
 
@@ -740,7 +770,7 @@ export default function LinksPage() {
                       value={selectedDomain}
                       onChange={(e) => setSelectedDomain(e.target.value)}
                       aria-label="Select Domain"
-                      disabled={!selectedNodeId}
+                      disabled={!selectedNodeId || !!editingAlias}
                     >
                       <option value="">{selectedNodeId ? 'Select domain (Optional)' : 'Auto-select'}</option>
                       {selectedNodeId && (() => {
@@ -756,6 +786,9 @@ export default function LinksPage() {
                         ));
                       })()}
                     </select>
+                    {editingAlias && (
+                      <p className="mt-1.5 text-xs text-slate-500">Domain cannot be changed once created.</p>
+                    )}
                   </div>
 
                   <div className="col-span-2">
@@ -1059,22 +1092,31 @@ export default function LinksPage() {
             <div className="flex flex-col items-center gap-4">
               {/* eslint-disable-next-line @next/next/no-img-element */}
               <img
-                src={`/api/nexus/links/${encodeURIComponent(qrModalAlias)}/qr?size=256`}
+                src={`/api/nexus/links/${linkPath(qrModalAlias, '/qr', { size: '256' })}`}
                 alt={`QR code for ${qrModalAlias}`}
                 className="rounded-lg border border-slate-700"
               />
-              
+
+              {qrShortUrl && (
+                <div className="text-center text-xs">
+                  <p className="font-mono text-slate-300 break-all">{qrShortUrl.shortUrl}</p>
+                  {qrShortUrl.degraded && (
+                    <p className="mt-1 text-amber-400">No healthy node serves this link right now</p>
+                  )}
+                </div>
+              )}
+
               <div className="flex gap-2">
                 <a
-                  href={`/api/nexus/links/${encodeURIComponent(qrModalAlias)}/qr?size=512`}
-                  download={`${qrModalAlias}-qr.png`}
+                  href={`/api/nexus/links/${linkPath(qrModalAlias, '/qr', { size: '512' })}`}
+                  download={`${qrModalAlias.replace(/\//g, '_')}-qr.png`}
                   className="rounded-lg bg-sky-500 px-4 py-2 text-sm font-medium text-white hover:bg-sky-400"
                 >
                   Download (512px)
                 </a>
                 <a
-                  href={`/api/nexus/links/${encodeURIComponent(qrModalAlias)}/qr?size=1024`}
-                  download={`${qrModalAlias}-qr.png`}
+                  href={`/api/nexus/links/${linkPath(qrModalAlias, '/qr', { size: '1024' })}`}
+                  download={`${qrModalAlias.replace(/\//g, '_')}-qr.png`}
                   className="rounded-lg bg-emerald-500 px-4 py-2 text-sm font-medium text-white hover:bg-emerald-400"
                 >
                   Download (1024px)
@@ -15,7 +15,8 @@ import {
   Plus, 
   Trash2, 
   Shield,
-  Cpu
+  Cpu,
+  KeyRound
 } from 'lucide-react';
 
 type Node = {
@@ -27,6 +28,21 @@ type Node = {
   isOnline: boolean;
   lastSeenAt: string;
   agentVersion: string;
+  uptime24h?: number | null;
+  uptime7d?: number | null;
+};
+
+function formatUptime(value?: number | null) {
+  if (value === null || value === undefined) return '—';
+  return `${value.toFixed(value >= 99.95 ? 0 : 1)}%`;
+}
+
+type NodeCredential = {
+  nodeId: string;
+  prefix: string;
+  createdAt: string;
+  rotateRequested: boolean;
+  revokedAt?: string;
 };
 
 type NodeToken = {
@@ -52,6 +68,7 @@ export default function NodesPage() {
   const [showDomainModal, setShowDomainModal] = useState(false);
   const [newDomain, setNewDomain] = useState('');
   const [addingDomain, setAddingDomain] = useState(false);
+  const [credential, setCredential] = useState<NodeCredential | null>(null);
 
   const loadNodes = useCallback(async () => {
     setLoadingNodes(true);
@@ -225,10 +242,43 @@ export default function NodesPage() {
     }
   }
 
+  async function loadCredential(nodeId: string) {
+    setCredential(null);
+    try {
+      const res = await fetch(`/api/nexus/nodes/${nodeId}/credential`, { cache: 'no-store' });
+      if (res.ok) setCredential(await res.json());
+    } catch (err) {
+      console.error(err);
+    }
+  }
+
+  async function handleCredential(method: 'POST' | 'DELETE') {
+    if (!selectedNode) return;
+    if (method === 'DELETE' && !confirm('Revoke this node\'s API key? The agent stops working until it is re-registered with a node token.')) return;
+
+    try {
+      const res = await fetch(`/api/nexus/nodes/${selectedNode.id}/credential`, { method });
+      if (!res.ok) {
+        const data = await res.json().catch(() => null);
+        showToast('Failed: ' + (data?.error || res.statusText), 'error');
+        return;
+      }
+      setCredential(await res.json());
+      showToast(
+        method === 'POST' ? 'Rotation requested, the agent picks up a new key on its next heartbeat' : 'API key revoked',
+        'success',
+      );
+    } catch (err) {
+      console.error(err);
+      showToast('Failed to update credential', 'error');
+    }
+  }
+
   function openDomainModal(node: Node) {
     setSelectedNode(node);
     setShowDomainModal(true);
     setNewDomain('');
+    loadCredential(node.id);
   }
 
   const columns: Column<Node>[] = [
@@ -323,6 +373,17 @@ export default function NodesPage() {
         </span>
       ),
     },
+    {
+      header: 'Uptime',
+      accessorKey: 'uptime7d',
+      sortable: true,
+      render: (node) => (
+        <div className="flex flex-col text-xs">
+          <span className="text-slate-200">{formatUptime(node.uptime24h)} <span className="text-slate-500">24h</span></span>
+          <span className="text-slate-400">{formatUptime(node.uptime7d)} <span className="text-slate-500">7d</span></span>
+        </div>
+      ),
+    },
     {
       header: 'Last Seen',
       accessorKey: 'lastSeenAt',
@@ -418,7 +479,7 @@ export default function NodesPage() {
                 </button>
               </div>
               <p className="text-[11px] text-slate-400">
-                Use this token once on your VPS when starting the Nexus Agent.
+                Single-use token for one new node, valid for 7 days.
               </p>
             </div>
           )}
@@ -594,6 +655,51 @@ export default function NodesPage() {
               </div>
             </div>
 
+            {/* API key node (scope agent) */}
+            <div className="space-y-2">
+              <label className="block text-xs font-medium uppercase tracking-wide text-slate-400">
+                Agent API Key
+              </label>
+              {!credential ? (
+                <p className="rounded-xl border border-slate-800 bg-slate-950/40 px-4 py-3 text-sm text-slate-500">
+                  No per-node key. Re-register the agent with a node token to issue one.
+                </p>
+              ) : (
+                <div className="flex items-center justify-between gap-3 rounded-xl border border-slate-800 bg-slate-950/60 px-4 py-3">
+                  <div className="flex items-center gap-3">
+                    <KeyRound size={14} className="text-slate-500" />
+                    <div>
+                      <span className="font-mono text-sm text-slate-200">{credential.prefix}…</span>
+                      <p className="text-xs text-slate-500">
+                        {credential.revokedAt
+                          ? `Revoked ${formatDate(credential.revokedAt)}`
+                          : credential.rotateRequested
+                            ? 'Rotation pending (next heartbeat)'
+                            : `Issued ${formatDate(credential.createdAt)}`}
+                      </p>
+                    </div>
+                  </div>
+                  {!credential.revokedAt && (
+                    <div className="flex gap-2">
+                      <button
+                        onClick={() => handleCredential('POST')}
+                        disabled={credential.rotateRequested}
+                        className="rounded-lg bg-slate-800 px-3 py-1.5 text-xs font-medium text-slate-200 hover:bg-slate-700 disabled:opacity-50 transition-colors"
+                      >
+                        Rotate
+                      </button>
+                      <button
+                        onClick={() => handleCredential('DELETE')}
+                        className="rounded-lg bg-rose-500/10 px-3 py-1.5 text-xs font-medium text-rose-400 hover:bg-rose-500 hover:text-white transition-all"
+                      >
+                        Revoke
+                      </button>
+                    </div>
+                  )}
+                </div>
+              )}
+            </div>
+
             <div className="flex justify-end border-t border-slate-800 pt-4">
               <button
                 onClick={() => setShowDomainModal(false)}
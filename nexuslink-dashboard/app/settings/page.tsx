@@ -13,7 +13,10 @@ import {
   AlertTriangle,
   CheckCircle2,
   Server,
-  UserCog
+  UserCog,
+  Link2,
+  Plus,
+  Trash2
 } from 'lucide-react';
 
 type Settings = {
@@ -31,11 +34,30 @@ type Settings = {
 };
 
 type RateLimitConfig = {
+  enabled: boolean;
+  action: 'reject' | 'fallback' | 'challenge';
+  algorithm: 'sliding_log' | 'sliding_window' | 'gcra';
   ip_limit: number;
   link_limit: number;
   window_seconds: number;
 };
 
+type AliasStrategy = 'random' | 'words' | 'sequential';
+
+type AliasPolicy = {
+  strategy: AliasStrategy;
+  length?: number;
+  salt?: string;
+};
+
+type AliasSettings = {
+  default: AliasPolicy;
+  domains?: Record<string, AliasPolicy>;
+  blocked?: string[];
+};
+
+type DomainPolicyRow = AliasPolicy & { domain: string };
+
 export default function SettingsPage() {
   const { showToast } = useToast();
   const [settings, setSettings] = useState<Settings | null>(null);
@@ -49,8 +71,18 @@ export default function SettingsPage() {
   const [ipLimit, setIpLimit] = useState(60);
   const [linkLimit, setLinkLimit] = useState(120);
   const [windowSeconds, setWindowSeconds] = useState(60);
+  const [rateLimitEnabled, setRateLimitEnabled] = useState(false);
+  const [rateLimitAction, setRateLimitAction] = useState<RateLimitConfig['action']>('reject');
+  const [rateLimitAlgorithm, setRateLimitAlgorithm] = useState<RateLimitConfig['algorithm']>('sliding_log');
 
   // Form state
+  // Alias Generation
+  const [loadingAliases, setLoadingAliases] = useState(true);
+  const [savingAliases, setSavingAliases] = useState(false);
+  const [defaultPolicy, setDefaultPolicy] = useState<AliasPolicy>({ strategy: 'random' });
+  const [domainPolicies, setDomainPolicies] = useState<DomainPolicyRow[]>([]);
+  const [blockedWords, setBlockedWords] = useState('');
+
   const [enableProxyCheck, setEnableProxyCheck] = useState(false);
   const [proxyCheckApiKey, setProxyCheckApiKey] = useState('');
   const [enableIpQualityScore, setEnableIpQualityScore] = useState(false);
@@ -107,6 +139,9 @@ export default function SettingsPage() {
       setIpLimit(data.ip_limit);
       setLinkLimit(data.link_limit);
       setWindowSeconds(data.window_seconds);
+      setRateLimitEnabled(data.enabled);
+      setRateLimitAction(data.action || 'reject');
+      setRateLimitAlgorithm(data.algorithm || 'sliding_log');
     } catch (err) {
       console.error(err);
       // Don't show toast here to avoid double toasts on page load
@@ -115,9 +150,31 @@ export default function SettingsPage() {
     }
   }
 
+  async function loadAliasSettings() {
+    setLoadingAliases(true);
+    try {
+      const res = await fetch('/api/nexus/settings/aliases', { cache: 'no-store' });
+      if (!res.ok) {
+        throw new Error('Failed to load alias settings');
+      }
+
+      const data: AliasSettings = await res.json();
+      setDefaultPolicy(data.default || { strategy: 'random' });
+      setDomainPolicies(
+        Object.entries(data.domains || {}).map(([domain, policy]) => ({ domain, ...policy }))
+      );
+      setBlockedWords((data.blocked || []).join('\n'));
+    } catch (err) {
+      console.error(err);
+    } finally {
+      setLoadingAliases(false);
+    }
+  }
+
   useEffect(() => {
     loadSettings();
     loadRateLimitSettings();
+    loadAliasSettings();
   }, []); // eslint-disable-line react-hooks/exhaustive-deps
 
   async function handleSave(e: FormEvent<HTMLFormElement>) {
@@ -172,6 +229,9 @@ export default function SettingsPage() {
         ip_limit: ipLimit,
         link_limit: linkLimit,
         window_seconds: windowSeconds,
+        enabled: rateLimitEnabled,
+        action: rateLimitAction,
+        algorithm: rateLimitAlgorithm,
       };
 
       const res = await fetch('/api/nexus/settings/rate-limit', {
@@ -187,7 +247,7 @@ export default function SettingsPage() {
 
       const updated: RateLimitConfig = await res.json();
       setRateLimitConfig(updated);
-      showToast('Rate limits saved! Restart Agent to apply.', 'success');
+      showToast('Rate limits saved!', 'success');
     } catch (err: unknown) {
       console.error(err);
       const message = err instanceof Error ? err.message : 'Failed to update rate limit settings';
@@ -197,6 +257,48 @@ export default function SettingsPage() {
     }
   }
 
+  async function handleSaveAliases() {
+    setSavingAliases(true);
+
+    try {
+      const domains: Record<string, AliasPolicy> = {};
+      for (const row of domainPolicies) {
+        const domain = row.domain.trim().toLowerCase();
+        if (!domain) continue;
+        domains[domain] = { strategy: row.strategy, length: row.length || undefined, salt: row.salt || undefined };
+      }
+      const payload: AliasSettings = {
+        default: { ...defaultPolicy, length: defaultPolicy.length || undefined, salt: defaultPolicy.salt || undefined },
+        domains,
+        blocked: blockedWords.split(/[\n,]/).map((w) => w.trim()).filter(Boolean),
+      };
+
+      const res = await fetch('/api/nexus/settings/aliases', {
+        method: 'PUT',
+        headers: { 'Content-Type': 'application/json' },
+        body: JSON.stringify(payload),
+      });
+
+      if (!res.ok) {
+        const text = await res.text();
+        throw new Error(text || 'Failed to update alias settings');
+      }
+
+      await loadAliasSettings();
+      showToast('Alias settings saved!', 'success');
+    } catch (err: unknown) {
+      console.error(err);
+      const message = err instanceof Error ? err.message : 'Failed to update alias settings';
+      showToast(message, 'error');
+    } finally {
+      setSavingAliases(false);
+    }
+  }
+
+  function updateDomainPolicy(index: number, patch: Partial<DomainPolicyRow>) {
+    setDomainPolicies((rows) => rows.map((row, i) => (i === index ? { ...row, ...patch } : row)));
+  }
+
   async function handleUpdateCredentials() {
     if (!newUsername || !newPassword) {
       showToast('Username and password are required', 'error');
@@ -421,6 +523,38 @@ export default function SettingsPage() {
               </div>
             ) : (
               <div className="space-y-6">
+                <div className="flex items-center justify-between gap-4">
+                  <label className="flex items-center gap-3 text-sm text-slate-200">
+                    <input
+                      type="checkbox"
+                      checked={rateLimitEnabled}
+                      onChange={(e) => setRateLimitEnabled(e.target.checked)}
+                      className="h-4 w-4 rounded border-slate-700 bg-slate-950 text-amber-500 focus:ring-amber-500/20"
+                    />
+                    Enforce on redirects
+                  </label>
+                  <select
+                    value={rateLimitAction}
+                    onChange={(e) => setRateLimitAction(e.target.value as RateLimitConfig['action'])}
+                    className="h-9 rounded-lg border border-slate-700 bg-slate-950/50 px-3 text-sm text-slate-50 outline-none focus:border-amber-500"
+                    aria-label="Action when limit is exceeded"
+                  >
+                    <option value="reject">Reject (429)</option>
+                    <option value="fallback">Redirect to fallback URL</option>
+                    <option value="challenge">Browser challenge</option>
+                  </select>
+                  <select
+                    value={rateLimitAlgorithm}
+                    onChange={(e) => setRateLimitAlgorithm(e.target.value as RateLimitConfig['algorithm'])}
+                    className="h-9 rounded-lg border border-slate-700 bg-slate-950/50 px-3 text-sm text-slate-50 outline-none focus:border-amber-500"
+                    aria-label="Rate limit algorithm"
+                  >
+                    <option value="sliding_log">Sliding log (exact)</option>
+                    <option value="sliding_window">Sliding window (counter)</option>
+                    <option value="gcra">GCRA (token bucket)</option>
+                  </select>
+                </div>
+
                 <div className="grid grid-cols-3 gap-4">
                   <div className="space-y-2">
                     <label className="text-xs font-medium text-slate-400 uppercase">IP Limit</label>
@@ -469,14 +603,16 @@ export default function SettingsPage() {
                 {rateLimitConfig && (
                   <div className="flex items-center gap-2 rounded-lg border border-slate-800 bg-slate-950/30 p-3 text-xs text-slate-400">
                     <CheckCircle2 size={14} className="text-emerald-500" />
-                    <span>Active: <strong>{rateLimitConfig.ip_limit}</strong> IP/min, <strong>{rateLimitConfig.link_limit}</strong> Link/min</span>
+                    <span>
+                      {rateLimitConfig.enabled ? 'Enforced' : 'Not enforced'}: <strong>{rateLimitConfig.ip_limit}</strong> per IP, <strong>{rateLimitConfig.link_limit}</strong> per link every {rateLimitConfig.window_seconds}s
+                    </span>
                   </div>
                 )}
 
                 <div className="flex items-center justify-between pt-2">
                   <div className="flex items-center gap-2 text-xs text-amber-500/80">
                     <AlertTriangle size={14} />
-                    <span>Restart Agent to apply</span>
+                    <span>Per-link overrides take precedence</span>
                   </div>
                   <button
                     onClick={handleSaveRateLimit}
@@ -491,6 +627,157 @@ export default function SettingsPage() {
             )}
           </section>
 
+          {/* Alias Generation */}
+          <section className="rounded-2xl border border-slate-800 bg-slate-900/60 p-6 backdrop-blur-sm shadow-lg">
+            <div className="flex items-center justify-between mb-6">
+              <div className="flex items-center gap-3">
+                <div className="flex h-10 w-10 items-center justify-center rounded-xl bg-violet-500/10 text-violet-400">
+                  <Link2 size={20} />
+                </div>
+                <div>
+                  <h2 className="text-base font-semibold text-slate-50">Alias Generation</h2>
+                  <p className="text-xs text-slate-400">Used when a link is created without an alias</p>
+                </div>
+              </div>
+              <button
+                onClick={loadAliasSettings}
+                className="p-2 rounded-lg text-slate-400 hover:bg-slate-800 hover:text-slate-200 transition-colors"
+                title="Refresh"
+              >
+                <RefreshCw size={16} className={loadingAliases ? "animate-spin" : ""} />
+              </button>
+            </div>
+
+            {loadingAliases ? (
+              <div className="py-8 flex justify-center">
+                <LoadingSpinner size="md" />
+              </div>
+            ) : (
+              <div className="space-y-6">
+                <div className="space-y-2">
+                  <label className="text-xs font-medium text-slate-400 uppercase">Default Strategy</label>
+                  <div className="grid grid-cols-3 gap-3">
+                    <select
+                      value={defaultPolicy.strategy}
+                      onChange={(e) => setDefaultPolicy({ ...defaultPolicy, strategy: e.target.value as AliasStrategy })}
+                      className="h-10 rounded-xl border border-slate-700 bg-slate-950/50 px-3 text-sm text-slate-50 outline-none focus:border-violet-500"
+                      aria-label="Default alias strategy"
+                    >
+                      <option value="random">Random (base62)</option>
+                      <option value="words">Pronounceable</option>
+                      <option value="sequential">Sequential (hashids)</option>
+                    </select>
+                    <input
+                      type="number"
+                      min="3"
+                      max="32"
+                      placeholder="Length"
+                      value={defaultPolicy.length || ''}
+                      onChange={(e) => setDefaultPolicy({ ...defaultPolicy, length: parseInt(e.target.value) || undefined })}
+                      className="h-10 rounded-xl border border-slate-700 bg-slate-950/50 px-3 text-sm text-slate-50 outline-none focus:border-violet-500"
+                      aria-label="Default alias length"
+                    />
+                    <input
+                      placeholder="Salt"
+                      value={defaultPolicy.salt || ''}
+                      onChange={(e) => setDefaultPolicy({ ...defaultPolicy, salt: e.target.value })}
+                      disabled={defaultPolicy.strategy !== 'sequential'}
+                      className="h-10 rounded-xl border border-slate-700 bg-slate-950/50 px-3 text-sm text-slate-50 outline-none focus:border-violet-500 disabled:opacity-50"
+                      aria-label="Default sequential salt"
+                    />
+                  </div>
+                </div>
+
+                <div className="space-y-2">
+                  <div className="flex items-center justify-between">
+                    <label className="text-xs font-medium text-slate-400 uppercase">Per-Domain Overrides</label>
+                    <button
+                      onClick={() => setDomainPolicies([...domainPolicies, { domain: '', strategy: 'random' }])}
+                      className="flex items-center gap-1 text-xs text-violet-400 hover:text-violet-300"
+                    >
+                      <Plus size={14} /> Add domain
+                    </button>
+                  </div>
+                  {domainPolicies.length === 0 && (
+                    <p className="text-xs text-slate-500">All domains use the default strategy.</p>
+                  )}
+                  {domainPolicies.map((row, i) => (
+                    <div key={i} className="grid grid-cols-[2fr_1.5fr_1fr_1.5fr_auto] gap-2">
+                      <input
+                        placeholder="go.example.com"
+                        value={row.domain}
+                        onChange={(e) => updateDomainPolicy(i, { domain: e.target.value })}
+                        className="h-9 rounded-lg border border-slate-700 bg-slate-950/50 px-3 text-sm text-slate-50 outline-none focus:border-violet-500"
+                        aria-label="Domain"
+                      />
+                      <select
+                        value={row.strategy}
+                        onChange={(e) => updateDomainPolicy(i, { strategy: e.target.value as AliasStrategy })}
+                        className="h-9 rounded-lg border border-slate-700 bg-slate-950/50 px-2 text-sm text-slate-50 outline-none focus:border-violet-500"
+                        aria-label="Domain alias strategy"
+                      >
+                        <option value="random">Random</option>
+                        <option value="words">Pronounceable</option>
+                        <option value="sequential">Sequential</option>
+                      </select>
+                      <input
+                        type="number"
+                        min="3"
+                        max="32"
+                        placeholder="Len"
+                        value={row.length || ''}
+                        onChange={(e) => updateDomainPolicy(i, { length: parseInt(e.target.value) || undefined })}
+                        className="h-9 rounded-lg border border-slate-700 bg-slate-950/50 px-2 text-sm text-slate-50 outline-none focus:border-violet-500"
+                        aria-label="Domain alias length"
+                      />
+                      <input
+                        placeholder="Salt"
+                        value={row.salt || ''}
+                        onChange={(e) => updateDomainPolicy(i, { salt: e.target.value })}
+                        disabled={row.strategy !== 'sequential'}
+                        className="h-9 rounded-lg border border-slate-700 bg-slate-950/50 px-2 text-sm text-slate-50 outline-none focus:border-violet-500 disabled:opacity-50"
+                        aria-label="Domain sequential salt"
+                      />
+                      <button
+                        onClick={() => setDomainPolicies(domainPolicies.filter((_, j) => j !== i))}
+                        className="p-2 rounded-lg text-slate-400 hover:bg-slate-800 hover:text-red-400"
+                        title="Remove"
+                      >
+                        <Trash2 size={14} />
+                      </button>
+                    </div>
+                  ))}
+                </div>
+
+                <div className="space-y-2">
+                  <label className="text-xs font-medium text-slate-400 uppercase">Blocked Words</label>
+                  <textarea
+                    rows={3}
+                    placeholder="One word per line"
+                    value={blockedWords}
+                    onChange={(e) => setBlockedWords(e.target.value)}
+                    className="w-full rounded-xl border border-slate-700 bg-slate-950/50 px-3 py-2 text-sm text-slate-50 outline-none focus:border-violet-500"
+                    aria-label="Blocked words"
+                  />
+                  <p className="text-xs text-slate-500">
+                    Custom aliases matching a blocked word are rejected; generated aliases containing one are skipped.
+                  </p>
+                </div>
+
+                <div className="flex justify-end pt-2">
+                  <button
+                    onClick={handleSaveAliases}
+                    disabled={savingAliases}
+                    className="flex h-9 items-center gap-2 rounded-lg bg-violet-500 px-4 text-sm font-medium text-white hover:bg-violet-400 disabled:cursor-not-allowed disabled:opacity-60 shadow-lg shadow-violet-500/20 transition-all hover:-translate-y-0.5"
+                  >
+                    {savingAliases && <LoadingSpinner size="sm" />}
+                    Save Alias Settings
+                  </button>
+                </div>
+              </div>
+            )}
+          </section>
+
           {/* Admin Credentials */}
           <section className="rounded-2xl border border-slate-800 bg-slate-900/60 p-6 backdrop-blur-sm shadow-lg">
             <div className="flex items-center gap-3 mb-6">
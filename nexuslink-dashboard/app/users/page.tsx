@@ -0,0 +1,399 @@
+'use client';
+
+import { useEffect, useState, FormEvent, useCallback } from 'react';
+import { useToast } from '@/components/Toast';
+import { LoadingSpinner } from '@/components/Loading';
+import { Table, Column } from '@/components/Table';
+
+type Role = 'owner' | 'editor' | 'analyst';
+
+type User = {
+  id: string;
+  username: string;
+  role: Role;
+  createdAt: string;
+  updatedAt: string;
+  lastLoginAt?: string;
+};
+
+const roles: { value: Role; label: string; description: string }[] = [
+  { value: 'owner', label: 'Owner', description: 'Full access, including nodes, settings and users' },
+  { value: 'editor', label: 'Editor', description: 'Manage links, groups, variants and webhooks' },
+  { value: 'analyst', label: 'Analyst', description: 'Read-only access to links, analytics and nodes' },
+];
+
+const roleBadge: Record<Role, string> = {
+  owner: 'bg-amber-500/10 text-amber-400',
+  editor: 'bg-sky-500/10 text-sky-400',
+  analyst: 'bg-emerald-500/10 text-emerald-400',
+};
+
+const inputClass =
+  'h-10 w-full rounded-xl border border-slate-700 bg-slate-950/50 px-3 text-slate-50 outline-none placeholder:text-slate-600 focus:border-blue-500 focus:ring-1 focus:ring-blue-500/20 transition-all';
+
+export default function UsersPage() {
+  const { showToast } = useToast();
+  const [users, setUsers] = useState<User[]>([]);
+  const [loading, setLoading] = useState(true);
+  const [forbidden, setForbidden] = useState(false);
+  const [saving, setSaving] = useState(false);
+
+  // Form state
+  const [username, setUsername] = useState('');
+  const [password, setPassword] = useState('');
+  const [role, setRole] = useState<Role>('analyst');
+
+  // Edit state
+  const [editingUser, setEditingUser] = useState<User | null>(null);
+  const [deleteConfirm, setDeleteConfirm] = useState<User | null>(null);
+  const [showForm, setShowForm] = useState(false);
+
+  const loadUsers = useCallback(async () => {
+    setLoading(true);
+    try {
+      const res = await fetch('/api/nexus/users', { cache: 'no-store' });
+      if (res.status === 403) {
+        setForbidden(true);
+        return;
+      }
+      if (!res.ok) {
+        throw new Error('Failed to load users');
+      }
+      const data: User[] = await res.json();
+      setUsers(data);
+    } catch (err) {
+      console.error(err);
+      showToast('Failed to load users', 'error');
+    } finally {
+      setLoading(false);
+    }
+  }, [showToast]);
+
+  useEffect(() => {
+    loadUsers();
+  }, [loadUsers]);
+
+  async function handleSubmit(e: FormEvent<HTMLFormElement>) {
+    e.preventDefault();
+
+    if (!editingUser && !username.trim()) {
+      showToast('Username is required', 'error');
+      return;
+    }
+    if ((!editingUser || password) && password.length < 8) {
+      showToast('Password must be at least 8 characters', 'error');
+      return;
+    }
+
+    setSaving(true);
+    try {
+      let res;
+      if (editingUser) {
+        // Update role / reset password (kosong = password tidak berubah)
+        res = await fetch(`/api/nexus/users/${editingUser.id}`, {
+          method: 'PUT',
+          headers: { 'Content-Type': 'application/json' },
+          body: JSON.stringify({ role, password }),
+        });
+      } else {
+        res = await fetch('/api/nexus/users', {
+          method: 'POST',
+          headers: { 'Content-Type': 'application/json' },
+          body: JSON.stringify({ username: username.trim(), password, role }),
+        });
+      }
+
+      if (!res.ok) {
+        const data = await res.json().catch(() => ({}));
+        throw new Error(data.error || 'Failed to save user');
+      }
+
+      showToast(
+        editingUser ? 'User updated successfully!' : 'User created successfully!',
+        'success'
+      );
+
+      resetForm();
+      await loadUsers();
+    } catch (err: unknown) {
+      console.error(err);
+      showToast(err instanceof Error ? err.message : 'Failed to save user', 'error');
+    } finally {
+      setSaving(false);
+    }
+  }
+
+  function resetForm() {
+    setUsername('');
+    setPassword('');
+    setRole('analyst');
+    setEditingUser(null);
+    setShowForm(false);
+  }
+
+  function startEdit(user: User) {
+    setEditingUser(user);
+    setUsername(user.username);
+    setPassword('');
+    setRole(user.role);
+    setShowForm(true);
+    window.scrollTo({ top: 0, behavior: 'smooth' });
+  }
+
+  async function handleDelete() {
+    if (!deleteConfirm) return;
+
+    try {
+      const res = await fetch(`/api/nexus/users/${deleteConfirm.id}`, {
+        method: 'DELETE',
+      });
+
+      if (!res.ok) {
+        const data = await res.json().catch(() => ({}));
+        throw new Error(data.error || 'Failed to delete user');
+      }
+
+      showToast('User deleted successfully!', 'success');
+      setDeleteConfirm(null);
+      await loadUsers();
+    } catch (err: unknown) {
+      console.error(err);
+      showToast(err instanceof Error ? err.message : 'Failed to delete user', 'error');
+    }
+  }
+
+  const columns: Column<User>[] = [
+    {
+      header: 'User',
+      accessorKey: 'username',
+      sortable: true,
+      render: (user) => <span className="font-medium text-slate-200">{user.username}</span>,
+    },
+    {
+      header: 'Role',
+      accessorKey: 'role',
+      sortable: true,
+      render: (user) => (
+        <span className={`rounded-full px-2.5 py-1 text-xs font-medium capitalize ${roleBadge[user.role]}`}>
+          {user.role}
+        </span>
+      ),
+    },
+    {
+      header: 'Last Login',
+      accessorKey: 'lastLoginAt',
+      sortable: true,
+      render: (user) => (
+        <span className="text-slate-300">
+          {user.lastLoginAt ? new Date(user.lastLoginAt).toLocaleString() : <span className="text-slate-600">Never</span>}
+        </span>
+      ),
+    },
+    {
+      header: 'Created',
+      accessorKey: 'createdAt',
+      sortable: true,
+      render: (user) => (
+        <span className="text-slate-300">{new Date(user.createdAt).toLocaleDateString()}</span>
+      ),
+    },
+    {
+      header: 'Actions',
+      accessorKey: 'id',
+      sortable: false,
+      render: (user) => (
+        <div className="flex gap-2">
+          <button
+            onClick={() => startEdit(user)}
+            className="rounded-lg bg-sky-500/10 px-3 py-1.5 text-xs font-medium text-sky-400 hover:bg-sky-500/20 transition-colors"
+            aria-label={`Edit ${user.username}`}
+          >
+            Edit
+          </button>
+          <button
+            onClick={() => setDeleteConfirm(user)}
+            className="rounded-lg bg-rose-500/10 px-3 py-1.5 text-xs font-medium text-rose-400 hover:bg-rose-500/20 transition-colors"
+            aria-label={`Delete ${user.username}`}
+          >
+            Delete
+          </button>
+        </div>
+      ),
+    },
+  ];
+
+  if (forbidden) {
+    return (
+      <div className="rounded-2xl border border-slate-800 bg-slate-900/50 p-6 text-sm text-slate-400">
+        Only owners can manage dashboard users.
+      </div>
+    );
+  }
+
+  return (
+    <div className="space-y-6">
+      <header className="flex items-center justify-between">
+        <div>
+          <h1 className="text-xl font-semibold text-slate-50">Users</h1>
+          <p className="text-sm text-slate-400">
+            Dashboard accounts and their roles
+          </p>
+        </div>
+        {!showForm && !editingUser && (
+          <button
+            onClick={() => setShowForm(true)}
+            className="h-9 rounded-lg bg-sky-500 px-4 text-sm font-medium text-white hover:bg-sky-600 transition-colors"
+          >
+            + Add User
+          </button>
+        )}
+      </header>
+
+      {/* Create/Edit Form */}
+      {(showForm || editingUser) && (
+        <div className="rounded-2xl border border-slate-800 bg-slate-900/50 p-6 backdrop-blur-sm shadow-lg">
+          <form onSubmit={handleSubmit} className="space-y-6">
+            <div className="flex items-center justify-between border-b border-slate-800 pb-4">
+              <h2 className="text-lg font-semibold text-slate-50">
+                {editingUser ? `Edit ${editingUser.username}` : 'Create New User'}
+              </h2>
+              <button
+                type="button"
+                onClick={resetForm}
+                className="rounded-lg p-2 text-slate-400 hover:bg-slate-800 hover:text-slate-200 transition-colors"
+                aria-label="Close form"
+              >
+                ✕
+              </button>
+            </div>
+
+            <div className="grid gap-6 md:grid-cols-2">
+              <div className="space-y-4">
+                <div>
+                  <label htmlFor="user-username" className="mb-1.5 block text-xs font-medium uppercase tracking-wide text-slate-400">
+                    Username *
+                  </label>
+                  <input
+                    id="user-username"
+                    className={`${inputClass} disabled:opacity-60`}
+                    placeholder="intern"
+                    value={username}
+                    onChange={(e) => setUsername(e.target.value)}
+                    disabled={!!editingUser}
+                    required
+                  />
+                </div>
+
+                <div>
+                  <label htmlFor="user-password" className="mb-1.5 block text-xs font-medium uppercase tracking-wide text-slate-400">
+                    {editingUser ? 'New Password' : 'Password *'}
+                  </label>
+                  <input
+                    id="user-password"
+                    type="password"
+                    className={inputClass}
+                    placeholder={editingUser ? 'Leave empty to keep current password' : 'At least 8 characters'}
+                    value={password}
+                    onChange={(e) => setPassword(e.target.value)}
+                    required={!editingUser}
+                  />
+                </div>
+              </div>
+
+              <div>
+                <label className="mb-1.5 block text-xs font-medium uppercase tracking-wide text-slate-400">
+                  Role
+                </label>
+                <div className="space-y-2">
+                  {roles.map((r) => (
+                    <button
+                      key={r.value}
+                      type="button"
+                      onClick={() => setRole(r.value)}
+                      className={`w-full rounded-xl border px-4 py-3 text-left transition-all ${
+                        role === r.value
+                          ? 'border-sky-500 bg-sky-500/10'
+                          : 'border-slate-700 bg-slate-950/60 hover:border-slate-600 hover:bg-slate-800'
+                      }`}
+                    >
+                      <div className="text-sm font-medium text-slate-200">{r.label}</div>
+                      <div className="text-xs text-slate-500">{r.description}</div>
+                    </button>
+                  ))}
+                </div>
+              </div>
+            </div>
+
+            <div className="flex items-center justify-end gap-3 border-t border-slate-800 pt-4">
+              <button
+                type="button"
+                onClick={resetForm}
+                className="rounded-lg px-4 py-2 text-sm font-medium text-slate-400 hover:bg-slate-800 hover:text-slate-200 transition-colors"
+              >
+                Cancel
+              </button>
+              <button
+                type="submit"
+                disabled={saving}
+                className="flex items-center gap-2 rounded-lg bg-sky-500 px-6 py-2 text-sm font-medium text-white hover:bg-sky-400 disabled:cursor-not-allowed disabled:opacity-60 shadow-lg shadow-sky-500/20 transition-all hover:shadow-sky-500/30 hover:-translate-y-0.5"
+              >
+                {saving && <LoadingSpinner size="sm" />}
+                {saving ? 'Saving...' : editingUser ? 'Update User' : 'Create User'}
+              </button>
+            </div>
+          </form>
+        </div>
+      )}
+
+      {/* Users Table */}
+      {loading ? (
+        <div className="flex items-center justify-center py-12">
+          <LoadingSpinner size="md" />
+        </div>
+      ) : (
+        <Table
+          data={users}
+          searchable
+          searchKeys={['username', 'role']}
+          pageSize={10}
+          emptyMessage="No users yet."
+          columns={columns}
+        />
+      )}
+
+      {/* Delete Confirmation Modal */}
+      {deleteConfirm && (
+        <div
+          className="fixed inset-0 z-50 flex items-center justify-center bg-black/60 backdrop-blur-sm"
+          onClick={() => setDeleteConfirm(null)}
+        >
+          <div
+            className="relative w-full max-w-md rounded-xl border border-slate-700 bg-slate-900 p-6 shadow-xl"
+            onClick={(e) => e.stopPropagation()}
+          >
+            <h2 className="mb-4 text-lg font-semibold text-slate-50">Delete User</h2>
+            <p className="mb-6 text-sm text-slate-300">
+              Are you sure you want to delete <span className="font-bold text-rose-400">{deleteConfirm.username}</span>?
+              <br />
+              <span className="text-slate-500">All sessions of this user will be logged out.</span>
+            </p>
+            <div className="flex gap-3">
+              <button
+                onClick={() => setDeleteConfirm(null)}
+                className="flex-1 rounded-lg border border-slate-600 bg-slate-800 px-4 py-2 text-sm font-medium text-slate-200 hover:bg-slate-700"
+              >
+                Cancel
+              </button>
+              <button
+                onClick={handleDelete}
+                className="flex-1 rounded-lg bg-rose-500 px-4 py-2 text-sm font-medium text-white hover:bg-rose-400"
+              >
+                Delete User
+              </button>
+            </div>
+          </div>
+        </div>
+      )}
+    </div>
+  );
+}
@@ -0,0 +1,418 @@
+'use client';
+
+import { useEffect, useState, FormEvent, useCallback } from 'react';
+import { useParams, useRouter } from 'next/navigation';
+import { useToast } from '@/components/Toast';
+import { LoadingSpinner } from '@/components/Loading';
+import { Table, Column } from '@/components/Table';
+import { ArrowLeft, RotateCcw, Trash2 } from 'lucide-react';
+
+type WebhookAttempt = {
+  attempt: number;
+  requestId: string;
+  at: string;
+  statusCode?: number;
+  responseBody?: string;
+  error?: string;
+  latencyMs: number;
+};
+
+type WebhookDelivery = {
+  id: string;
+  webhookId: string;
+  event: string;
+  url: string;
+  payload: unknown;
+  status: 'pending' | 'success' | 'failed' | 'replayed';
+  attempts: WebhookAttempt[];
+  replayOf?: string;
+  batch?: number;
+  createdAt: string;
+  updatedAt: string;
+};
+
+type Tab = 'deliveries' | 'dead-letters';
+
+type Filters = {
+  status: string;
+  event: string;
+  since: string;
+  until: string;
+};
+
+const emptyFilters: Filters = { status: '', event: '', since: '', until: '' };
+
+const statusStyles: Record<WebhookDelivery['status'], string> = {
+  pending: 'bg-amber-500/10 text-amber-300 ring-amber-500/40',
+  success: 'bg-emerald-500/10 text-emerald-300 ring-emerald-500/40',
+  failed: 'bg-rose-500/10 text-rose-300 ring-rose-500/40',
+  replayed: 'bg-slate-800 text-slate-400 ring-slate-700',
+};
+
+const inputClass =
+  'h-10 w-full rounded-xl border border-slate-700 bg-slate-950/50 px-3 text-slate-50 outline-none placeholder:text-slate-600 focus:border-blue-500 focus:ring-1 focus:ring-blue-500/20 transition-all';
+
+// buildQuery mengubah filter form ke query string API (datetime-local → RFC3339)
+function buildQuery(filters: Filters, tab: Tab, extra: Record<string, string> = {}): string {
+  const params = new URLSearchParams();
+  if (tab === 'deliveries' && filters.status) params.set('status', filters.status);
+  if (filters.event.trim()) params.set('event', filters.event.trim());
+  if (filters.since) params.set('since', new Date(filters.since).toISOString());
+  if (filters.until) params.set('until', new Date(filters.until).toISOString());
+  Object.entries(extra).forEach(([k, v]) => params.set(k, v));
+  return params.toString();
+}
+
+export default function WebhookDeliveriesPage() {
+  const params = useParams();
+  const router = useRouter();
+  const { showToast } = useToast();
+  const webhookId = params.id as string;
+
+  const [tab, setTab] = useState<Tab>('deliveries');
+  const [deliveries, setDeliveries] = useState<WebhookDelivery[]>([]);
+  const [loading, setLoading] = useState(true);
+  const [filters, setFilters] = useState<Filters>(emptyFilters);
+  const [applied, setApplied] = useState<Filters>(emptyFilters);
+  const [selected, setSelected] = useState<WebhookDelivery | null>(null);
+  const [replaying, setReplaying] = useState<string | null>(null);
+
+  const loadDeliveries = useCallback(async () => {
+    setLoading(true);
+    try {
+      const res = await fetch(
+        `/api/nexus/webhooks/${webhookId}/${tab}?${buildQuery(applied, tab, { limit: '500' })}`,
+        { cache: 'no-store' }
+      );
+      if (!res.ok) {
+        throw new Error('Failed to load deliveries');
+      }
+      const data: WebhookDelivery[] = await res.json();
+      setDeliveries(Array.isArray(data) ? data : []);
+    } catch (err) {
+      console.error(err);
+      showToast('Failed to load deliveries', 'error');
+      setDeliveries([]);
+    } finally {
+      setLoading(false);
+    }
+  }, [webhookId, tab, applied, showToast]);
+
+  useEffect(() => {
+    loadDeliveries();
+  }, [loadDeliveries]);
+
+  function handleFilter(e: FormEvent<HTMLFormElement>) {
+    e.preventDefault();
+    setApplied(filters);
+  }
+
+  function resetFilters() {
+    setFilters(emptyFilters);
+    setApplied(emptyFilters);
+  }
+
+  async function handleReplay(delivery: WebhookDelivery) {
+    setReplaying(delivery.id);
+    try {
+      const res = await fetch(`/api/nexus/webhooks/${webhookId}/deliveries/${delivery.id}/replay`, {
+        method: 'POST',
+      });
+      if (!res.ok) {
+        throw new Error('Failed to replay delivery');
+      }
+      const replayed: WebhookDelivery = await res.json();
+      showToast(
+        replayed.status === 'success' ? 'Delivery replayed successfully!' : 'Replay failed, moved to dead letters',
+        replayed.status === 'success' ? 'success' : 'error'
+      );
+      loadDeliveries();
+    } catch (err) {
+      console.error(err);
+      showToast('Failed to replay delivery', 'error');
+    } finally {
+      setReplaying(null);
+    }
+  }
+
+  async function handleReplayAll() {
+    setReplaying('all');
+    try {
+      const res = await fetch(`/api/nexus/webhooks/${webhookId}/dead-letters/replay?${buildQuery(applied, 'dead-letters')}`, {
+        method: 'POST',
+      });
+      if (!res.ok) {
+        throw new Error('Failed to replay dead letters');
+      }
+      const data = await res.json();
+      showToast(`${data.queued} dead letter(s) queued for replay`, 'success');
+    } catch (err) {
+      console.error(err);
+      showToast('Failed to replay dead letters', 'error');
+    } finally {
+      setReplaying(null);
+    }
+  }
+
+  async function handleDiscard(delivery: WebhookDelivery) {
+    try {
+      const res = await fetch(`/api/nexus/webhooks/${webhookId}/dead-letters/${delivery.id}`, {
+        method: 'DELETE',
+      });
+      if (!res.ok) {
+        throw new Error('Failed to delete dead letter');
+      }
+      showToast('Dead letter discarded', 'success');
+      loadDeliveries();
+    } catch (err) {
+      console.error(err);
+      showToast('Failed to delete dead letter', 'error');
+    }
+  }
+
+  const columns: Column<WebhookDelivery>[] = [
+    {
+      header: 'Time',
+      accessorKey: 'createdAt',
+      sortable: true,
+      render: (d) => <span className="text-slate-300">{new Date(d.createdAt).toLocaleString()}</span>,
+    },
+    {
+      header: 'Event',
+      accessorKey: 'event',
+      sortable: true,
+      render: (d) => (
+        <span className="rounded-full bg-sky-500/10 px-2.5 py-1 font-mono text-xs text-sky-400">
+          {d.event}
+          {d.batch ? <span className="ml-1 text-slate-500">×{d.batch}</span> : null}
+        </span>
+      ),
+    },
+    {
+      header: 'Status',
+      accessorKey: 'status',
+      sortable: true,
+      render: (d) => (
+        <span className={`rounded-full px-2.5 py-1 text-xs font-medium capitalize ring-1 ${statusStyles[d.status] || statusStyles.pending}`}>
+          {d.status}
+          {d.replayOf && <span className="ml-1 text-slate-500">(replay)</span>}
+        </span>
+      ),
+    },
+    {
+      header: 'Attempts',
+      accessorKey: 'attempts',
+      sortable: false,
+      render: (d) => {
+        const last = d.attempts?.[d.attempts.length - 1];
+        return (
+          <span className="text-xs text-slate-400">
+            {d.attempts?.length ?? 0}
+            {last && (
+              <span className="ml-2 font-mono">
+                {last.statusCode || 'ERR'} · {last.latencyMs}ms
+              </span>
+            )}
+          </span>
+        );
+      },
+    },
+    {
+      header: 'Actions',
+      accessorKey: 'id',
+      sortable: false,
+      render: (d) => (
+        <div className="flex items-center gap-2">
+          <button
+            onClick={() => setSelected(d)}
+            className="rounded-lg bg-slate-800 px-3 py-1.5 text-xs font-medium text-slate-300 hover:bg-slate-700 transition-colors"
+          >
+            Inspect
+          </button>
+          <button
+            onClick={() => handleReplay(d)}
+            disabled={replaying !== null}
+            className="flex items-center gap-1.5 rounded-lg bg-blue-500/10 px-3 py-1.5 text-xs font-medium text-blue-400 hover:bg-blue-500/20 transition-colors disabled:opacity-50"
+            title="Send this payload again"
+          >
+            {replaying === d.id ? <LoadingSpinner size="sm" /> : <RotateCcw size={12} />}
+            Replay
+          </button>
+          {tab === 'dead-letters' && (
+            <button
+              onClick={() => handleDiscard(d)}
+              className="rounded-lg bg-rose-500/10 p-2 text-rose-400 hover:bg-rose-500/20 transition-colors"
+              title="Discard dead letter"
+            >
+              <Trash2 size={14} />
+            </button>
+          )}
+        </div>
+      ),
+    },
+  ];
+
+  return (
+    <div className="space-y-6">
+      <header className="flex items-center justify-between">
+        <div>
+          <button
+            onClick={() => router.push('/webhooks')}
+            className="mb-2 flex items-center gap-1.5 text-xs text-slate-400 hover:text-slate-200"
+          >
+            <ArrowLeft size={12} /> Back to webhooks
+          </button>
+          <h1 className="text-xl font-semibold text-slate-50">Webhook Deliveries</h1>
+          <p className="text-sm text-slate-400">
+            Every delivery attempt for this webhook; failed deliveries land in dead letters until replayed
+          </p>
+        </div>
+        {tab === 'dead-letters' && (
+          <button
+            onClick={handleReplayAll}
+            disabled={replaying !== null || deliveries.length === 0}
+            className="flex h-9 items-center gap-2 rounded-lg bg-sky-500 px-4 text-sm font-medium text-white hover:bg-sky-600 transition-colors disabled:opacity-50"
+          >
+            {replaying === 'all' ? <LoadingSpinner size="sm" /> : <RotateCcw size={14} />}
+            Replay All in Range
+          </button>
+        )}
+      </header>
+
+      {/* Tabs */}
+      <div className="flex gap-2">
+        {(['deliveries', 'dead-letters'] as Tab[]).map((t) => (
+          <button
+            key={t}
+            onClick={() => setTab(t)}
+            className={`rounded-lg px-4 py-2 text-sm font-medium transition-colors ${
+              tab === t ? 'bg-slate-800 text-slate-50' : 'text-slate-400 hover:bg-slate-800/50 hover:text-slate-200'
+            }`}
+          >
+            {t === 'deliveries' ? 'All Deliveries' : 'Dead Letters'}
+          </button>
+        ))}
+      </div>
+
+      {/* Filters */}
+      <form
+        onSubmit={handleFilter}
+        className="grid gap-4 rounded-2xl border border-slate-800 bg-slate-900/50 p-6 md:grid-cols-4"
+      >
+        <select
+          className={inputClass}
+          value={filters.status}
+          onChange={(e) => setFilters({ ...filters, status: e.target.value })}
+          disabled={tab === 'dead-letters'}
+          aria-label="Status"
+        >
+          <option value="">All statuses</option>
+          <option value="success">Success</option>
+          <option value="failed">Failed</option>
+          <option value="pending">Pending</option>
+          <option value="replayed">Replayed</option>
+        </select>
+        <input
+          className={inputClass}
+          placeholder="Event (e.g. click.created)"
+          value={filters.event}
+          onChange={(e) => setFilters({ ...filters, event: e.target.value })}
+        />
+        <input
+          type="datetime-local"
+          className={inputClass}
+          value={filters.since}
+          onChange={(e) => setFilters({ ...filters, since: e.target.value })}
+          aria-label="Since"
+        />
+        <input
+          type="datetime-local"
+          className={inputClass}
+          value={filters.until}
+          onChange={(e) => setFilters({ ...filters, until: e.target.value })}
+          aria-label="Until"
+        />
+        <div className="flex justify-end gap-3 md:col-span-4">
+          <button
+            type="button"
+            onClick={resetFilters}
+            className="rounded-lg px-4 py-2 text-sm font-medium text-slate-400 hover:bg-slate-800 hover:text-slate-200 transition-colors"
+          >
+            Reset
+          </button>
+          <button
+            type="submit"
+            className="rounded-lg bg-sky-500 px-6 py-2 text-sm font-medium text-white hover:bg-sky-400 transition-colors"
+          >
+            Apply Filters
+          </button>
+        </div>
+      </form>
+
+      {loading ? (
+        <div className="flex items-center justify-center py-12">
+          <LoadingSpinner size="md" />
+        </div>
+      ) : (
+        <Table
+          data={deliveries}
+          pageSize={20}
+          emptyMessage={tab === 'deliveries' ? 'No deliveries match these filters.' : 'No dead letters. 🎉'}
+          columns={columns}
+        />
+      )}
+
+      {/* Delivery Detail Modal */}
+      {selected && (
+        <div
+          className="fixed inset-0 z-50 flex items-center justify-center bg-black/60 backdrop-blur-sm"
+          onClick={() => setSelected(null)}
+        >
+          <div
+            className="relative max-h-[80vh] w-full max-w-2xl overflow-y-auto rounded-xl border border-slate-700 bg-slate-900 p-6 shadow-xl"
+            onClick={(e) => e.stopPropagation()}
+          >
+            <h2 className="mb-1 text-lg font-semibold text-slate-50">{selected.event}</h2>
+            <p className="mb-4 font-mono text-xs text-slate-500">
+              {selected.id} · {selected.url}
+              {selected.replayOf && <> · replay of {selected.replayOf}</>}
+              {selected.batch ? <> · batch of {selected.batch} events</> : null}
+            </p>
+
+            <div className="mb-2 text-xs font-medium uppercase tracking-wide text-slate-400">Payload</div>
+            <pre className="mb-4 max-h-64 overflow-auto whitespace-pre-wrap break-all rounded-lg border border-slate-800 bg-slate-950/60 p-3 text-xs text-slate-300">
+              {JSON.stringify(selected.payload, null, 2)}
+            </pre>
+
+            <div className="mb-2 text-xs font-medium uppercase tracking-wide text-slate-400">Attempts</div>
+            <div className="space-y-3">
+              {selected.attempts?.map((a) => (
+                <div key={a.attempt} className="rounded-lg border border-slate-800 bg-slate-950/60 p-3 text-xs">
+                  <div className="mb-1 flex justify-between text-slate-400">
+                    <span>
+                      #{a.attempt} · {new Date(a.at).toLocaleString()} · {a.latencyMs}ms
+                    </span>
+                    <span className={a.statusCode && a.statusCode < 300 ? 'text-emerald-400' : 'text-rose-400'}>
+                      {a.statusCode || 'no response'}
+                    </span>
+                  </div>
+                  <div className="font-mono text-slate-500">request {a.requestId}</div>
+                  {a.error && <div className="mt-1 text-rose-300">{a.error}</div>}
+                  {a.responseBody && (
+                    <pre className="mt-2 overflow-x-auto whitespace-pre-wrap break-all rounded bg-slate-900 p-2 text-slate-300">{a.responseBody}</pre>
+                  )}
+                </div>
+              ))}
+            </div>
+            <button
+              onClick={() => setSelected(null)}
+              className="mt-6 w-full rounded-lg border border-slate-600 bg-slate-800 px-4 py-2 text-sm font-medium text-slate-200 hover:bg-slate-700"
+            >
+              Close
+            </button>
+          </div>
+        </div>
+      )}
+    </div>
+  );
+}
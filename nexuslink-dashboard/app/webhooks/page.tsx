@@ -1,6 +1,7 @@
 'use client';
 
 import { useEffect, useState, FormEvent, useCallback } from 'react';
+import Link from 'next/link';
 import { useToast } from '@/components/Toast';
 import { LoadingSpinner } from '@/components/Loading';
 import { Table, Column } from '@/components/Table';
@@ -15,7 +16,9 @@ import {
   XCircle,
   Eye,
   EyeOff,
-  Calendar
+  Calendar,
+  History,
+  KeyRound
 } from 'lucide-react';
 
 type Webhook = {
@@ -26,11 +29,103 @@ type Webhook = {
   isActive: boolean;
   createdAt: string;
   updatedAt: string;
+  secondarySecret?: string;
+  secondarySecretExpiresAt?: string;
+  filter?: WebhookFilter;
+  format?: string;
+  template?: string;
+  batch?: WebhookBatch;
 };
 
+type WebhookBatch = {
+  size?: number;
+  window?: number;
+  encoding?: 'json' | 'ndjson';
+};
+
+type WebhookFilter = {
+  aliases?: string[];
+  groupIds?: string[];
+  countries?: string[];
+  nodeIds?: string[];
+  reasons?: string[];
+  isBot?: boolean;
+};
+
+// Field filter di form: daftar dipisah koma
+type FilterForm = {
+  aliases: string;
+  groupIds: string;
+  countries: string;
+  nodeIds: string;
+  reasons: string;
+  isBot: '' | 'humans' | 'bots';
+};
+
+const EMPTY_FILTER: FilterForm = { aliases: '', groupIds: '', countries: '', nodeIds: '', reasons: '', isBot: '' };
+
+const FILTER_FIELDS: { key: Exclude<keyof FilterForm, 'isBot'>; label: string; placeholder: string }[] = [
+  { key: 'aliases', label: 'Aliases', placeholder: 'promo, launch' },
+  { key: 'groupIds', label: 'Group IDs', placeholder: 'group-id-1, group-id-2' },
+  { key: 'countries', label: 'Countries', placeholder: 'ID, SG' },
+  { key: 'nodeIds', label: 'Node IDs', placeholder: 'node-id-1' },
+  { key: 'reasons', label: 'Block Reasons', placeholder: 'rate_limited, blocked_ip' },
+];
+
+const FORMATS = [
+  { value: 'json', label: 'JSON (default payload)' },
+  { value: 'slack', label: 'Slack' },
+  { value: 'discord', label: 'Discord' },
+  { value: 'template', label: 'Custom template' },
+];
+
+const BATCH_ENCODINGS = [
+  { value: 'json', label: 'JSON array' },
+  { value: 'ndjson', label: 'NDJSON (one event per line)' },
+];
+
+const TEMPLATE_EXAMPLE = '{"text": {{json (summary .)}}, "alias": {{json .Data.alias}}}';
+
+function splitList(value: string): string[] | undefined {
+  const items = value.split(',').map((v) => v.trim()).filter(Boolean);
+  return items.length > 0 ? items : undefined;
+}
+
+function toFilter(form: FilterForm): WebhookFilter | undefined {
+  const filter: WebhookFilter = {
+    aliases: splitList(form.aliases),
+    groupIds: splitList(form.groupIds),
+    countries: splitList(form.countries),
+    nodeIds: splitList(form.nodeIds),
+    reasons: splitList(form.reasons),
+    isBot: form.isBot === '' ? undefined : form.isBot === 'bots',
+  };
+  return Object.values(filter).some((v) => v !== undefined) ? filter : undefined;
+}
+
+function fromFilter(filter?: WebhookFilter): FilterForm {
+  if (!filter) return EMPTY_FILTER;
+  return {
+    aliases: (filter.aliases ?? []).join(', '),
+    groupIds: (filter.groupIds ?? []).join(', '),
+    countries: (filter.countries ?? []).join(', '),
+    nodeIds: (filter.nodeIds ?? []).join(', '),
+    reasons: (filter.reasons ?? []).join(', '),
+    isBot: filter.isBot === undefined ? '' : filter.isBot ? 'bots' : 'humans',
+  };
+}
+
+const ROLL_OVERLAPS = [
+  { value: '0s', label: 'Immediately' },
+  { value: '1h', label: '1 hour' },
+  { value: '24h', label: '24 hours' },
+  { value: '168h', label: '7 days' },
+];
+
 const ALL_EVENTS = [
   { value: 'click.created', label: 'Click Created', color: 'bg-blue-500' },
   { value: 'node.offline', label: 'Node Offline', color: 'bg-red-500' },
+  { value: 'node.online', label: 'Node Online', color: 'bg-emerald-500' },
   { value: 'traffic.blocked', label: 'Traffic Blocked', color: 'bg-yellow-500' },
   { value: 'link.expired', label: 'Link Expired', color: 'bg-orange-500' },
   { value: 'link.maxclicks', label: 'Link Max Clicks', color: 'bg-purple-500' },
@@ -53,11 +148,24 @@ export default function WebhooksPage() {
   const [isActive, setIsActive] = useState(true);
   const [showSecret, setShowSecret] = useState(false);
   const [showForm, setShowForm] = useState(false);
+  const [format, setFormat] = useState('json');
+  const [template, setTemplate] = useState('');
+  const [filter, setFilter] = useState<FilterForm>(EMPTY_FILTER);
+  const [batchEnabled, setBatchEnabled] = useState(false);
+  const [batchSize, setBatchSize] = useState('100');
+  const [batchWindow, setBatchWindow] = useState('10');
+  const [batchEncoding, setBatchEncoding] = useState<'json' | 'ndjson'>('json');
   
   // Edit state
   const [editingWebhook, setEditingWebhook] = useState<Webhook | null>(null);
   const [deleteConfirm, setDeleteConfirm] = useState<Webhook | null>(null);
 
+  // Secret rotation state
+  const [rollTarget, setRollTarget] = useState<Webhook | null>(null);
+  const [rollOverlap, setRollOverlap] = useState('24h');
+  const [rolling, setRolling] = useState(false);
+  const [rolledSecret, setRolledSecret] = useState<string | null>(null);
+
   const loadWebhooks = useCallback(async () => {
     setLoading(true);
     try {
@@ -100,6 +208,13 @@ export default function WebhooksPage() {
     setSelectedEvents([]);
     setSecret('');
     setIsActive(true);
+    setFormat('json');
+    setTemplate('');
+    setFilter(EMPTY_FILTER);
+    setBatchEnabled(false);
+    setBatchSize('100');
+    setBatchWindow('10');
+    setBatchEncoding('json');
     setEditingWebhook(null);
     setShowForm(false);
   }
@@ -122,6 +237,16 @@ export default function WebhooksPage() {
       return;
     }
 
+    if (format === 'template' && !template.trim()) {
+      showToast('Template is required for custom template format', 'error');
+      return;
+    }
+
+    if (batchEnabled && (format === 'slack' || format === 'discord')) {
+      showToast('Batching is not supported for Slack or Discord formats', 'error');
+      return;
+    }
+
     setSaving(true);
     try {
       const body = {
@@ -129,6 +254,16 @@ export default function WebhooksPage() {
         events: selectedEvents,
         secret: secret.trim(),
         isActive,
+        format,
+        template: format === 'template' ? template : undefined,
+        filter: toFilter(filter),
+        batch: batchEnabled
+          ? {
+              size: parseInt(batchSize, 10) || undefined,
+              window: parseInt(batchWindow, 10) || undefined,
+              encoding: batchEncoding,
+            }
+          : undefined,
       };
 
       let res;
@@ -174,6 +309,13 @@ export default function WebhooksPage() {
     setSelectedEvents(webhook.events);
     setSecret(webhook.secret);
     setIsActive(webhook.isActive);
+    setFormat(webhook.format || 'json');
+    setTemplate(webhook.template || '');
+    setFilter(fromFilter(webhook.filter));
+    setBatchEnabled(!!webhook.batch);
+    setBatchSize(String(webhook.batch?.size || 100));
+    setBatchWindow(String(webhook.batch?.window || 10));
+    setBatchEncoding(webhook.batch?.encoding || 'json');
     setShowForm(true);
     window.scrollTo({ top: 0, behavior: 'smooth' });
   }
@@ -218,6 +360,38 @@ export default function WebhooksPage() {
     }
   }
 
+  async function handleRollSecret() {
+    if (!rollTarget) return;
+    setRolling(true);
+    try {
+      const res = await fetch(`/api/nexus/webhooks/${rollTarget.id}/secret/roll`, {
+        method: 'POST',
+        headers: { 'Content-Type': 'application/json' },
+        body: JSON.stringify({ overlap: rollOverlap }),
+      });
+
+      if (!res.ok) {
+        throw new Error('Failed to roll secret');
+      }
+
+      const updated: Webhook = await res.json();
+      setRolledSecret(updated.secret);
+      showToast('Webhook secret rolled', 'success');
+      loadWebhooks();
+    } catch (err) {
+      console.error(err);
+      showToast('Failed to roll webhook secret', 'error');
+    } finally {
+      setRolling(false);
+    }
+  }
+
+  function closeRollModal() {
+    setRollTarget(null);
+    setRolledSecret(null);
+    setRollOverlap('24h');
+  }
+
   async function handleToggleActive(webhook: Webhook) {
     try {
       const res = await fetch('/api/nexus/webhooks', {
@@ -324,6 +498,26 @@ export default function WebhooksPage() {
             {testing === webhook.id ? <LoadingSpinner size="sm" /> : <Play size={12} />}
             {testing === webhook.id ? 'Testing...' : 'Test'}
           </button>
+          <Link
+            href={`/webhooks/${webhook.id}/deliveries`}
+            className="rounded-lg bg-sky-500/10 p-2 text-sky-400 hover:bg-sky-500/20 transition-colors"
+            title="Delivery log & dead letters"
+          >
+            <History size={14} />
+          </Link>
+          <button
+            onClick={() => setRollTarget(webhook)}
+            className="rounded-lg bg-violet-500/10 p-2 text-violet-400 hover:bg-violet-500/20 transition-colors"
+            title={
+              webhook.secondarySecret
+                ? `Roll secret (previous secret valid until ${
+                    webhook.secondarySecretExpiresAt ? new Date(webhook.secondarySecretExpiresAt).toLocaleString() : 'next roll'
+                  })`
+                : 'Roll secret'
+            }
+          >
+            <KeyRound size={14} />
+          </button>
           <button
             onClick={() => handleEdit(webhook)}
             className="rounded-lg bg-amber-500/10 p-2 text-amber-400 hover:bg-amber-500/20 transition-colors"
@@ -458,6 +652,145 @@ export default function WebhooksPage() {
               </p>
             </div>
 
+            <div>
+              <label className="mb-1.5 block text-xs font-medium uppercase tracking-wide text-slate-400">
+                Filters
+              </label>
+              <div className="grid grid-cols-1 md:grid-cols-3 gap-3">
+                {FILTER_FIELDS.map((field) => (
+                  <div key={field.key}>
+                    <label htmlFor={`filter-${field.key}`} className="mb-1 block text-xs text-slate-500">
+                      {field.label}
+                    </label>
+                    <input
+                      type="text"
+                      id={`filter-${field.key}`}
+                      value={filter[field.key]}
+                      onChange={(e) => setFilter({ ...filter, [field.key]: e.target.value })}
+                      placeholder={field.placeholder}
+                      className="h-10 w-full rounded-xl border border-slate-700 bg-slate-950/50 px-3 text-slate-50 outline-none placeholder:text-slate-600 focus:border-blue-500 focus:ring-1 focus:ring-blue-500/20 transition-all"
+                    />
+                  </div>
+                ))}
+                <div>
+                  <label htmlFor="filter-isBot" className="mb-1 block text-xs text-slate-500">
+                    Visitors
+                  </label>
+                  <select
+                    id="filter-isBot"
+                    value={filter.isBot}
+                    onChange={(e) => setFilter({ ...filter, isBot: e.target.value as FilterForm['isBot'] })}
+                    className="h-10 w-full rounded-xl border border-slate-700 bg-slate-950/50 px-3 text-slate-50 outline-none focus:border-blue-500"
+                  >
+                    <option value="">Humans and bots</option>
+                    <option value="humans">Humans only</option>
+                    <option value="bots">Bots only</option>
+                  </select>
+                </div>
+              </div>
+              <p className="mt-1.5 text-xs text-slate-500">
+                Comma-separated, case-insensitive. Empty fields match everything; a filter only applies to events that carry that field.
+              </p>
+            </div>
+
+            <div>
+              <label htmlFor="format" className="mb-1.5 block text-xs font-medium uppercase tracking-wide text-slate-400">
+                Payload Format
+              </label>
+              <select
+                id="format"
+                value={format}
+                onChange={(e) => setFormat(e.target.value)}
+                className="h-10 w-full rounded-xl border border-slate-700 bg-slate-950/50 px-3 text-slate-50 outline-none focus:border-blue-500"
+              >
+                {FORMATS.map((f) => (
+                  <option key={f.value} value={f.value}>
+                    {f.label}
+                  </option>
+                ))}
+              </select>
+              {format === 'template' && (
+                <>
+                  <textarea
+                    value={template}
+                    onChange={(e) => setTemplate(e.target.value)}
+                    placeholder={TEMPLATE_EXAMPLE}
+                    rows={5}
+                    className="mt-3 w-full rounded-xl border border-slate-700 bg-slate-950/50 p-3 font-mono text-sm text-slate-50 outline-none placeholder:text-slate-600 focus:border-blue-500 focus:ring-1 focus:ring-blue-500/20 transition-all"
+                  />
+                  <p className="mt-1.5 text-xs text-slate-500">
+                    Go text/template with .Event, .Timestamp and .Data fields, plus json and summary helpers. Output must be valid JSON.
+                  </p>
+                </>
+              )}
+            </div>
+
+            <div>
+              <label className="flex items-center gap-3 cursor-pointer p-2 rounded-lg hover:bg-slate-800/50 transition-colors">
+                <input
+                  type="checkbox"
+                  checked={batchEnabled}
+                  onChange={(e) => setBatchEnabled(e.target.checked)}
+                  className="h-4 w-4 rounded border-slate-600 bg-slate-950 text-blue-500 focus:ring-blue-500/20"
+                />
+                <span className="text-sm text-slate-200">Batch click events</span>
+              </label>
+              {batchEnabled && (
+                <>
+                  <div className="mt-2 grid grid-cols-1 md:grid-cols-3 gap-3">
+                    <div>
+                      <label htmlFor="batch-size" className="mb-1 block text-xs text-slate-500">
+                        Max events per batch
+                      </label>
+                      <input
+                        type="number"
+                        id="batch-size"
+                        min={1}
+                        max={1000}
+                        value={batchSize}
+                        onChange={(e) => setBatchSize(e.target.value)}
+                        className="h-10 w-full rounded-xl border border-slate-700 bg-slate-950/50 px-3 text-slate-50 outline-none placeholder:text-slate-600 focus:border-blue-500 focus:ring-1 focus:ring-blue-500/20 transition-all"
+                      />
+                    </div>
+                    <div>
+                      <label htmlFor="batch-window" className="mb-1 block text-xs text-slate-500">
+                        Max wait (seconds)
+                      </label>
+                      <input
+                        type="number"
+                        id="batch-window"
+                        min={1}
+                        max={300}
+                        value={batchWindow}
+                        onChange={(e) => setBatchWindow(e.target.value)}
+                        className="h-10 w-full rounded-xl border border-slate-700 bg-slate-950/50 px-3 text-slate-50 outline-none placeholder:text-slate-600 focus:border-blue-500 focus:ring-1 focus:ring-blue-500/20 transition-all"
+                      />
+                    </div>
+                    <div>
+                      <label htmlFor="batch-encoding" className="mb-1 block text-xs text-slate-500">
+                        Encoding
+                      </label>
+                      <select
+                        id="batch-encoding"
+                        value={batchEncoding}
+                        onChange={(e) => setBatchEncoding(e.target.value as 'json' | 'ndjson')}
+                        className="h-10 w-full rounded-xl border border-slate-700 bg-slate-950/50 px-3 text-slate-50 outline-none focus:border-blue-500"
+                      >
+                        {BATCH_ENCODINGS.map((enc) => (
+                          <option key={enc.value} value={enc.value}>
+                            {enc.label}
+                          </option>
+                        ))}
+                      </select>
+                    </div>
+                  </div>
+                  <p className="mt-1.5 text-xs text-slate-500">
+                    click.created events are sent together when the batch is full or the wait time has passed. Other events are still sent one by one.
+                  </p>
+                </>
+              )}
+            </div>
+
             <div className="flex items-center">
               <label className="flex items-center gap-3 cursor-pointer p-2 rounded-lg hover:bg-slate-800/50 transition-colors">
                 <input
@@ -507,6 +840,72 @@ export default function WebhooksPage() {
         />
       )}
 
+      {/* Roll Secret Modal */}
+      {rollTarget && (
+        <div
+          className="fixed inset-0 z-50 flex items-center justify-center bg-black/60 backdrop-blur-sm"
+          onClick={closeRollModal}
+        >
+          <div
+            className="relative w-full max-w-md rounded-xl border border-slate-700 bg-slate-900 p-6 shadow-xl animate-in zoom-in-95 duration-200"
+            onClick={(e) => e.stopPropagation()}
+          >
+            <h2 className="mb-4 text-lg font-semibold text-slate-50">Roll Secret</h2>
+            <code className="mb-4 block rounded-lg bg-slate-950 p-3 font-mono text-xs text-sky-400 border border-slate-800">
+              {rollTarget.url}
+            </code>
+            {rolledSecret ? (
+              <>
+                <p className="mb-2 text-sm text-slate-300">New secret (update your receiver before the old one expires):</p>
+                <code className="mb-6 block break-all rounded-lg bg-slate-950 p-3 font-mono text-xs text-emerald-400 border border-slate-800">
+                  {rolledSecret}
+                </code>
+                <button
+                  onClick={closeRollModal}
+                  className="w-full rounded-lg border border-slate-600 bg-slate-800 px-4 py-2 text-sm font-medium text-slate-200 hover:bg-slate-700"
+                >
+                  Done
+                </button>
+              </>
+            ) : (
+              <>
+                <p className="mb-4 text-sm text-slate-300">
+                  A new secret is generated. Requests are signed with both secrets until the old one expires.
+                </p>
+                <label className="mb-1.5 block text-sm font-medium text-slate-300">Keep old secret valid for</label>
+                <select
+                  value={rollOverlap}
+                  onChange={(e) => setRollOverlap(e.target.value)}
+                  className="mb-6 h-10 w-full rounded-xl border border-slate-700 bg-slate-950/50 px-3 text-slate-50 outline-none focus:border-blue-500"
+                >
+                  {ROLL_OVERLAPS.map((o) => (
+                    <option key={o.value} value={o.value}>
+                      {o.label}
+                    </option>
+                  ))}
+                </select>
+                <div className="flex gap-3">
+                  <button
+                    onClick={closeRollModal}
+                    className="flex-1 rounded-lg border border-slate-600 bg-slate-800 px-4 py-2 text-sm font-medium text-slate-200 hover:bg-slate-700"
+                  >
+                    Cancel
+                  </button>
+                  <button
+                    onClick={handleRollSecret}
+                    disabled={rolling}
+                    className="flex flex-1 items-center justify-center gap-2 rounded-lg bg-violet-500 px-4 py-2 text-sm font-medium text-white hover:bg-violet-400 disabled:opacity-50"
+                  >
+                    {rolling && <LoadingSpinner size="sm" />}
+                    Roll Secret
+                  </button>
+                </div>
+              </>
+            )}
+          </div>
+        </div>
+      )}
+
       {/* Delete Confirmation Modal */}
       {deleteConfirm && (
         <div
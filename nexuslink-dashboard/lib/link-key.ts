@@ -0,0 +1,31 @@
+// Alias unik per domain. Key link = alias (link tanpa domain / link lama) atau "domain/alias".
+
+export function linkKey(link: { key?: string; alias: string }): string {
+  return link.key || link.alias;
+}
+
+// Path link untuk API/halaman: alias di path, domain sebagai ?domain=
+// e.g. linkPath('go.brand-a.com/promo', '/qr', { size: '256' }) → 'promo/qr?domain=go.brand-a.com&size=256'
+export function linkPath(key: string, suffix = '', query: Record<string, string> = {}): string {
+  const i = key.lastIndexOf('/');
+  const alias = i < 0 ? key : key.slice(i + 1);
+  const params = new URLSearchParams();
+  if (i >= 0) params.set('domain', key.slice(0, i));
+  for (const [k, v] of Object.entries(query)) params.set(k, v);
+  const qs = params.toString();
+  return `${encodeURIComponent(alias)}${suffix}${qs ? `?${qs}` : ''}`;
+}
+
+// Query ?domain= untuk diteruskan halaman detail link ke proxy API
+export function domainQuery(domain: string | null): string {
+  return domain ? `?domain=${encodeURIComponent(domain)}` : '';
+}
+
+// Query untuk proxy ke backend: param tambahan + ?domain= dari request dashboard
+export function backendQuery(request: Request, query: Record<string, string> = {}): string {
+  const domain = new URL(request.url).searchParams.get('domain');
+  const params = new URLSearchParams(query);
+  if (domain) params.set('domain', domain);
+  const qs = params.toString();
+  return qs ? `?${qs}` : '';
+}
@@ -0,0 +1,20 @@
+import { cookies, headers } from 'next/headers';
+
+// Teruskan session login dashboard ke API supaya role user (owner/editor/analyst) ikut dicek.
+// Tanpa cookie, API hanya melihat NEXUS_API_KEY (dianggap owner).
+// IP browser ikut dikirim lewat X-Forwarded-For untuk audit log.
+export async function sessionHeader(): Promise<Record<string, string>> {
+  const cookieStore = await cookies();
+  const sessionToken = cookieStore.get('nexus_session')?.value;
+  const result: Record<string, string> = sessionToken ? { Authorization: `Bearer ${sessionToken}` } : {};
+
+  const headerStore = await headers();
+  const clientIP =
+    headerStore.get('x-forwarded-for')?.split(',')[0].trim() ||
+    headerStore.get('x-real-ip') ||
+    '';
+  if (clientIP) {
+    result['X-Forwarded-For'] = clientIP;
+  }
+  return result;
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/afuzapratama/nexuslink/internal/config"
+	"github.com/afuzapratama/nexuslink/internal/nodeauth"
+)
+
+// Key API agent. Setelah registrasi agent memakai key per node (scope agent saja) yang disimpan
+// di <NEXUS_AGENT_CACHE_DIR>/credentials.json; NEXUS_AGENT_API_KEY hanya fallback untuk setup lama.
+var (
+	credMu          sync.RWMutex
+	agentAPIKey     string
+	credentialsPath string
+)
+
+type storedCredentials struct {
+	NodeID string `json:"nodeId"`
+	APIKey string `json:"apiKey"`
+}
+
+// currentAPIKey returns key yang dipakai untuk semua request ke API
+func currentAPIKey() string {
+	credMu.RLock()
+	defer credMu.RUnlock()
+	return agentAPIKey
+}
+
+// loadCredentials: fallbackKey dipakai kalau belum ada key node di disk.
+// Key node dari disk hanya dipakai kalau node ID-nya cocok dengan NEXUS_NODE_ID (kalau di-set).
+func loadCredentials(fallbackKey string) {
+	credentialsPath = filepath.Join(config.GetEnv("NEXUS_AGENT_CACHE_DIR", "./data"), "credentials.json")
+	agentAPIKey = fallbackKey
+
+	b, err := os.ReadFile(credentialsPath)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Printf("credentials: read %s failed: %v", credentialsPath, err)
+		}
+		return
+	}
+
+	var stored storedCredentials
+	if err := json.Unmarshal(b, &stored); err != nil || stored.APIKey == "" {
+		log.Printf("credentials: ignoring invalid %s", credentialsPath)
+		return
+	}
+	if currentNodeID != "" && currentNodeID != stored.NodeID {
+		log.Printf("credentials: %s belongs to node %s, not %s", credentialsPath, stored.NodeID, currentNodeID)
+		return
+	}
+
+	currentNodeID = stored.NodeID
+	agentAPIKey = stored.APIKey
+	log.Printf("credentials: using node key for nodeID=%s", stored.NodeID)
+}
+
+// setCredentials menyimpan key node baru (registrasi / rotasi) di memory dan disk
+func setCredentials(nodeID, apiKey string) {
+	credMu.Lock()
+	agentAPIKey = apiKey
+	credMu.Unlock()
+
+	b, _ := json.Marshal(storedCredentials{NodeID: nodeID, APIKey: apiKey})
+	if err := os.MkdirAll(filepath.Dir(credentialsPath), 0o700); err != nil {
+		log.Printf("credentials: save failed: %v", err)
+		return
+	}
+	tmp := credentialsPath + ".tmp"
+	if err := os.WriteFile(tmp, b, 0o600); err != nil {
+		log.Printf("credentials: save failed: %v", err)
+		return
+	}
+	if err := os.Rename(tmp, credentialsPath); err != nil {
+		log.Printf("credentials: save failed: %v", err)
+	}
+}
+
+// domainFeedSecret: feed domain untuk key node ditandatangani dengan hash key node itu,
+// selain itu pakai secret bersama (NEXUS_DOMAIN_PUSH_SECRET / NEXUS_AGENT_API_KEY)
+func domainFeedSecret(apiKey string) string {
+	if nodeauth.IsNodeKey(apiKey) {
+		return nodeauth.HashKey(apiKey)
+	}
+	return domainPushSecret
+}
+
+// rotateCredentials meminta key baru ke API (dipicu heartbeat saat admin minta rotasi)
+func rotateCredentials(apiBase string) error {
+	req, err := http.NewRequest(http.MethodPost, apiBase+"/nodes/credentials/rotate", nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("X-Nexus-Api-Key", currentAPIKey())
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("unexpected status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var out storedCredentials
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return err
+	}
+	if out.APIKey == "" || out.NodeID != currentNodeID {
+		return fmt.Errorf("invalid rotate response for node %s", out.NodeID)
+	}
+
+	setCredentials(out.NodeID, out.APIKey)
+	log.Printf("credentials: API key rotated for nodeID=%s", out.NodeID)
+	return nil
+}
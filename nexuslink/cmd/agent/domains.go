@@ -0,0 +1,102 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"time"
+
+	"github.com/afuzapratama/nexuslink/internal/config"
+	"github.com/afuzapratama/nexuslink/internal/edge"
+)
+
+// Whitelist domain node ini. Diisi dari cache di disk saat start, lalu di-push API lewat long-poll.
+var (
+	domainRegistry    = edge.NewDomainRegistry()
+	domainsCachePath  string
+	domainPushSecret  string
+	domainWatchClient = &http.Client{Timeout: 40 * time.Second} // > timeout long-poll di API (25s)
+)
+
+// loadDomainCache supaya agent yang cold start bisa langsung serve sebelum API bisa dihubungi
+func loadDomainCache() {
+	domainsCachePath = filepath.Join(config.GetEnv("NEXUS_AGENT_CACHE_DIR", "./data"), "domains.json")
+
+	if err := domainRegistry.Load(domainsCachePath); err != nil {
+		log.Printf("domains: load cache %s failed: %v", domainsCachePath, err)
+	} else if domains := domainRegistry.Domains(); len(domains) > 0 {
+		log.Printf("domains: loaded %v from %s", domains, domainsCachePath)
+	}
+}
+
+// startDomainWatch long-poll GET /nodes/domains terus-menerus; feed diverifikasi HMAC sebelum dipakai
+func startDomainWatch(apiBase string) {
+	domainPushSecret = config.GetEnv("NEXUS_DOMAIN_PUSH_SECRET", config.GetEnv("NEXUS_AGENT_API_KEY", ""))
+
+	go func() {
+		for {
+			if err := watchDomains(apiBase); err != nil {
+				log.Printf("domains: watch failed (serving %v): %v", domainRegistry.Domains(), err)
+				time.Sleep(5 * time.Second)
+			}
+		}
+	}()
+}
+
+// watchDomains satu putaran long-poll. 304 = tidak ada perubahan sampai timeout.
+func watchDomains(apiBase string) error {
+	// Key yang sama dipakai untuk request dan verifikasi (bisa dirotasi di tengah jalan)
+	apiKey := currentAPIKey()
+	secret := domainFeedSecret(apiKey)
+	if secret == "" {
+		return fmt.Errorf("no domain feed secret (set NEXUS_DOMAIN_PUSH_SECRET or register with a node token)")
+	}
+
+	urlStr := fmt.Sprintf("%s/nodes/domains?nodeId=%s&version=%d",
+		apiBase, url.QueryEscape(currentNodeID), domainRegistry.Version(currentNodeID))
+
+	req, err := http.NewRequest(http.MethodGet, urlStr, nil)
+	if err != nil {
+		return err
+	}
+	if apiKey != "" {
+		req.Header.Set("X-Nexus-Api-Key", apiKey)
+	}
+
+	resp, err := domainWatchClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified {
+		return nil
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %d: %s", resp.StatusCode, string(body))
+	}
+
+	feed, err := edge.VerifyDomainFeed(secret, body, resp.Header.Get(edge.DomainSignatureHeader))
+	if err != nil {
+		return err
+	}
+	if feed.NodeID != currentNodeID {
+		return fmt.Errorf("feed is for node %s, expected %s", feed.NodeID, currentNodeID)
+	}
+
+	changed := domainRegistry.Apply(currentNodeID, feed)
+	if err := domainRegistry.Save(domainsCachePath); err != nil {
+		log.Printf("domains: save cache failed: %v", err)
+	}
+	if changed {
+		log.Printf("Domain whitelist updated: %v (nodeID=%s, version=%d)", domainRegistry.Domains(), currentNodeID, feed.Version)
+	}
+	return nil
+}
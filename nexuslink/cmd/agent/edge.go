@@ -0,0 +1,207 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/afuzapratama/nexuslink/internal/config"
+	"github.com/afuzapratama/nexuslink/internal/edge"
+	"github.com/afuzapratama/nexuslink/internal/geoip"
+	"github.com/afuzapratama/nexuslink/internal/metrics"
+	"github.com/afuzapratama/nexuslink/internal/models"
+	"github.com/afuzapratama/nexuslink/internal/rules"
+	"github.com/afuzapratama/nexuslink/internal/ua"
+)
+
+// Edge cache: snapshot link lokal + click shipper (nil kalau NEXUS_AGENT_EDGE_CACHE=false)
+var (
+	linkStore    *edge.Store
+	clickShipper *edge.ClickShipper
+	snapshotPath string
+)
+
+// startEdgeCache load snapshot dari disk, lalu sync delta feed dari API secara berkala
+func startEdgeCache(apiBase string) {
+	if config.GetEnv("NEXUS_AGENT_EDGE_CACHE", "true") == "false" {
+		log.Printf("Edge cache disabled, every redirect is resolved by API")
+		return
+	}
+
+	interval, err := time.ParseDuration(config.GetEnv("NEXUS_AGENT_SYNC_INTERVAL", "10s"))
+	if err != nil || interval <= 0 {
+		interval = 10 * time.Second
+	}
+
+	linkStore = edge.NewStore()
+	snapshotPath = filepath.Join(config.GetEnv("NEXUS_AGENT_CACHE_DIR", "./data"), "links.json")
+
+	if err := linkStore.Load(snapshotPath); err != nil {
+		log.Printf("edge: load snapshot %s failed: %v", snapshotPath, err)
+	} else if linkStore.Ready() {
+		log.Printf("edge: loaded %d links from %s", linkStore.Len(), snapshotPath)
+	}
+
+	clickShipper = edge.NewClickShipper(apiBase, currentAPIKey)
+	clickShipper.Start()
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			if err := syncLinks(apiBase); err != nil {
+				log.Printf("edge: sync failed (serving %d cached links): %v", linkStore.Len(), err)
+			}
+			<-ticker.C
+		}
+	}()
+}
+
+// syncLinks ambil delta feed GET /sync/links?since=<version> dan simpan snapshot ke disk kalau berubah
+func syncLinks(apiBase string) error {
+	urlStr := fmt.Sprintf("%s/sync/links?since=%d", apiBase, linkStore.Version())
+
+	req, err := http.NewRequest(http.MethodGet, urlStr, nil)
+	if err != nil {
+		return err
+	}
+	if apiKey := currentAPIKey(); apiKey != "" {
+		req.Header.Set("X-Nexus-Api-Key", apiKey)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("status=%d body=%s", resp.StatusCode, string(body))
+	}
+
+	var feed models.LinkFeed
+	if err := json.NewDecoder(resp.Body).Decode(&feed); err != nil {
+		return err
+	}
+
+	if linkStore.Apply(&feed) {
+		if feed.Full {
+			log.Printf("edge: full snapshot applied (%d links)", len(feed.Links))
+		} else {
+			log.Printf("edge: delta applied (%d changed, %d deleted)", len(feed.Links), len(feed.Deleted))
+		}
+		if err := linkStore.Save(snapshotPath); err != nil {
+			log.Printf("edge: save snapshot failed: %v", err)
+		}
+	}
+
+	return nil
+}
+
+// serveLocal resolve link dari snapshot lokal (tanpa API) dan antrikan click event ke API
+func serveLocal(w http.ResponseWriter, r *http.Request, snap *models.LinkSnapshot, domain, ip, userAgent, referer, acceptLanguage string) {
+	now := time.Now()
+
+	osName, deviceType, browserName, isBot, botType := ua.Parse(userAgent)
+	country, city := geoip.Lookup(ip)
+	query, _ := url.ParseQuery(r.URL.RawQuery)
+
+	visitor := rules.Visitor{
+		Country:  country,
+		ASN:      geoip.LookupASN(ip),
+		Device:   deviceType,
+		OS:       osName,
+		Browser:  browserName,
+		Referrer: referer,
+		Language: rules.PrimaryLanguage(acceptLanguage),
+		Query:    query,
+		IsBot:    isBot,
+		Time:     now,
+	}
+
+	d := edge.Evaluate(snap, visitor, domain, now)
+
+	if d.Counted {
+		clickShipper.Enqueue(models.ClickEvent{
+			ID:          uuid.NewString(),
+			Alias:       snap.Link.DataKey(),
+			NodeID:      currentNodeID,
+			IP:          ip,
+			Country:     country,
+			City:        city,
+			OS:          osName,
+			Device:      deviceType,
+			Browser:     browserName,
+			IsBot:       isBot,
+			BotType:     botType,
+			RuleID:      d.RuleID,
+			VariantID:   d.VariantID,
+			BlockReason: d.Reason,
+			UserAgent:   userAgent,
+			Referrer:    referer,
+			CreatedAt:   now.UTC(),
+		})
+	}
+
+	if d.Disabled {
+		recordRedirect("link_disabled")
+		renderDisabledPage(w, d.Status, d.Message)
+		return
+	}
+
+	if d.TargetURL == "" {
+		reason := d.Reason
+		if reason == "" {
+			reason = "denied"
+		}
+		recordRedirect(reason)
+		if d.Status == http.StatusForbidden {
+			http.Error(w, "access forbidden", http.StatusForbidden)
+			return
+		}
+		http.Error(w, http.StatusText(d.Status), d.Status)
+		return
+	}
+
+	if d.Reason != "" {
+		log.Printf("Using fallback URL for %s: reason=%s, target=%s (edge)", snap.Link.Alias, d.Reason, d.TargetURL)
+	}
+
+	recordRedirect(d.Reason)
+	http.Redirect(w, r, d.TargetURL, http.StatusFound)
+}
+
+// recordRedirect mencatat hasil redirect di /metrics agent (reason kosong = redirect normal,
+// selain itu dihitung sebagai blocked walaupun visitor diarahkan ke fallback URL)
+func recordRedirect(reason string) {
+	if reason == "" {
+		metrics.GetMetrics().IncrementRedirects(currentNodeID)
+		return
+	}
+	metrics.GetMetrics().IncrementBlockedRedirects(currentNodeID, reason)
+}
+
+// serveUnavailable dipanggil kalau API tidak bisa dihubungi: pakai snapshot lokal kalau ada
+func serveUnavailable(w http.ResponseWriter, r *http.Request, snap *models.LinkSnapshot, domain, ip, userAgent, referer, acceptLanguage string) {
+	if snap != nil {
+		serveLocal(w, r, snap, domain, ip, userAgent, referer, acceptLanguage)
+		return
+	}
+
+	// Snapshot lengkap tapi alias tidak ada → link memang tidak ada
+	if linkStore != nil && linkStore.Ready() {
+		http.NotFound(w, r)
+		return
+	}
+
+	http.Error(w, "upstream error", http.StatusBadGateway)
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"html/template"
 	"io"
 	"log"
 	"net"
@@ -13,6 +14,11 @@ import (
 	"time"
 
 	"github.com/afuzapratama/nexuslink/internal/config"
+	"github.com/afuzapratama/nexuslink/internal/edge"
+	"github.com/afuzapratama/nexuslink/internal/metrics"
+	"github.com/afuzapratama/nexuslink/internal/models"
+	"github.com/afuzapratama/nexuslink/internal/nodeauth"
+	"github.com/afuzapratama/nexuslink/internal/ratelimit"
 )
 
 type Link struct {
@@ -30,12 +36,7 @@ type Node struct {
 	AgentVersion string   `json:"agentVersion"`
 }
 
-var (
-	currentNodeID     string
-	allowedDomains    []string
-	domainsLastUpdate time.Time
-	domainsCacheTTL   = 30 * time.Second
-)
+var currentNodeID string
 
 func main() {
 	// load .env
@@ -43,7 +44,6 @@ func main() {
 
 	apiBase := config.GetEnv("NEXUS_API_BASE", "http://localhost:8080")
 	addr := config.GetEnv("NEXUS_AGENT_PORT", ":9090")
-	apiKey := config.GetEnv("NEXUS_AGENT_API_KEY", "")
 
 	nodeName := config.GetEnv("NEXUS_NODE_NAME", "Local Dev Node")
 	nodeRegion := config.GetEnv("NEXUS_NODE_REGION", "ID-JKT")
@@ -52,12 +52,23 @@ func main() {
 	// Mode lama: baca node ID langsung dari env (fallback)
 	currentNodeID = config.GetEnv("NEXUS_NODE_ID", "")
 
+	// Key node hasil registrasi sebelumnya; NEXUS_AGENT_API_KEY hanya fallback
+	loadCredentials(config.GetEnv("NEXUS_AGENT_API_KEY", ""))
+
 	// Mode baru: token + domain
 	token := config.GetEnv("NEXUS_NODE_TOKEN", "")
 	domain := config.GetEnv("NEXUS_NODE_DOMAIN", "")
 
-	if token != "" && domain != "" {
-		if err := registerNodeWithToken(apiBase, apiKey, token, domain, nodeRegion, nodePublicURL, nodeName); err != nil {
+	// Whitelist domain dari cache dulu, supaya tetap bisa serve walau API belum bisa dihubungi
+	loadDomainCache()
+
+	// Token registrasi sekali pakai: agent yang sudah punya key node (credentials.json) tidak registrasi ulang
+	if nodeauth.IsNodeKey(currentAPIKey()) {
+		if token != "" {
+			log.Printf("node key found for nodeID=%s, skipping registration (NEXUS_NODE_TOKEN not used)", currentNodeID)
+		}
+	} else if token != "" && domain != "" {
+		if err := registerNodeWithToken(apiBase, token, domain, nodeRegion, nodePublicURL, nodeName); err != nil {
 			log.Printf("node register via token failed: %v", err)
 		}
 	}
@@ -72,7 +83,13 @@ func main() {
 	}
 
 	// Start heartbeat loop
-	startHeartbeat(apiBase, apiKey, nodeName, nodeRegion, nodePublicURL)
+	startHeartbeat(apiBase, nodeName, nodeRegion, nodePublicURL)
+
+	// Start edge cache (snapshot link lokal + delta sync)
+	startEdgeCache(apiBase)
+
+	// Domain whitelist di-push API (long-poll, HMAC)
+	startDomainWatch(apiBase)
 
 	mux := http.NewServeMux()
 
@@ -80,21 +97,35 @@ func main() {
 		w.Write([]byte("OK - Nexus Agent is running"))
 	})
 
-	// Redirect handler WITHOUT local rate limiting
-	// Rate limiting is handled centrally by API server
+	mux.Handle("/metrics", metrics.MetricsHandler())
+
+	// Redirect handler. Rate limiting dilakukan terpusat di API (/links/resolve),
+	// link yang kena rate limit selalu di-resolve lewat API (lihat edge.NeedsCentral)
 	mux.HandleFunc("/r/", func(w http.ResponseWriter, r *http.Request) {
-		redirectHandler(w, r, apiBase, apiKey)
+		redirectHandler(w, r, apiBase)
 	})
 
+	handler := metrics.Middleware(mux)
+
+	// HTTPS bawaan via ACME (opsional). Listener HTTP tetap jalan untuk challenge HTTP-01.
+	certManager, err := newCertManager()
+	if err != nil {
+		log.Fatalf("agent TLS setup error: %v", err)
+	}
+	if certManager != nil {
+		startTLS(certManager, handler)
+		handler = certManager.HTTPHandler(handler)
+	}
+
 	log.Printf("Nexus Agent listening on %s (API: %s, nodeID=%s)\n",
 		addr, apiBase, currentNodeID)
-	if err := http.ListenAndServe(addr, mux); err != nil {
+	if err := http.ListenAndServe(addr, handler); err != nil {
 		log.Fatalf("agent server error: %v", err)
 	}
 }
 
-// registerNodeWithToken → panggil /nodes/register di API pakai token
-func registerNodeWithToken(apiBase, apiKey, token, domain, region, publicURL, name string) error {
+// registerNodeWithToken → panggil /nodes/register di API pakai token, simpan key node dari response
+func registerNodeWithToken(apiBase, token, domain, region, publicURL, name string) error {
 	body := map[string]string{
 		"token":        token,
 		"domain":       domain,
@@ -111,7 +142,8 @@ func registerNodeWithToken(apiBase, apiKey, token, domain, region, publicURL, na
 		return err
 	}
 	req.Header.Set("Content-Type", "application/json")
-	if apiKey != "" {
+	// API baru cukup pakai token; key tetap dikirim untuk API versi lama
+	if apiKey := currentAPIKey(); apiKey != "" {
 		req.Header.Set("X-Nexus-Api-Key", apiKey)
 	}
 
@@ -131,6 +163,7 @@ func registerNodeWithToken(apiBase, apiKey, token, domain, region, publicURL, na
 		Name      string `json:"name"`
 		Region    string `json:"region"`
 		PublicURL string `json:"publicUrl"`
+		APIKey    string `json:"apiKey"`
 	}
 	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
 		return err
@@ -140,115 +173,24 @@ func registerNodeWithToken(apiBase, apiKey, token, domain, region, publicURL, na
 		currentNodeID = out.NodeID
 		log.Printf("Registered as nodeID=%s domain=%s", out.NodeID, domain)
 
-		// Initialize allowed domains with registration domain
-		allowedDomains = []string{domain}
-		domainsLastUpdate = time.Now()
-
-		// Fetch full node info including all domains
-		go refreshAllowedDomains(apiBase, apiKey)
-	}
-
-	return nil
-}
-
-// refreshAllowedDomains fetches current node info from API to get updated domain list
-func refreshAllowedDomains(apiBase, apiKey string) {
-	if currentNodeID == "" {
-		log.Printf("refreshAllowedDomains: skipped (currentNodeID is empty)")
-		return
-	}
-
-	urlStr := fmt.Sprintf("%s/admin/nodes/%s", apiBase, url.QueryEscape(currentNodeID))
-	log.Printf("refreshAllowedDomains: fetching %s", urlStr)
-
-	req, err := http.NewRequest(http.MethodGet, urlStr, nil)
-	if err != nil {
-		log.Printf("refreshAllowedDomains: error creating request: %v", err)
-		return
-	}
-
-	if apiKey != "" {
-		req.Header.Set("X-Nexus-Api-Key", apiKey)
-	}
-
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		log.Printf("refreshAllowedDomains: error fetching node info: %v", err)
-		return
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		log.Printf("refreshAllowedDomains: unexpected status %d, body: %s", resp.StatusCode, string(body))
-		return
-	}
-
-	var node Node
-	if err := json.NewDecoder(resp.Body).Decode(&node); err != nil {
-		log.Printf("refreshAllowedDomains: error decoding response: %v", err)
-		return
-	}
-
-	// Update allowed domains with PublicURL + Domains array
-	newDomains := []string{}
-
-	// Add PublicURL domain (extract domain from URL, strip port)
-	if node.PublicURL != "" {
-		if u, err := url.Parse(node.PublicURL); err == nil && u.Host != "" {
-			host := u.Host
-			// Strip port if exists
-			if idx := strings.Index(host, ":"); idx != -1 {
-				host = host[:idx]
-			}
-			newDomains = append(newDomains, host)
+		if out.APIKey != "" {
+			setCredentials(out.NodeID, out.APIKey)
 		}
-	}
 
-	// Add all registered domains (strip port from each)
-	for _, d := range node.Domains {
-		domain := strings.TrimSpace(d)
-		// Strip port if exists
-		if idx := strings.Index(domain, ":"); idx != -1 {
-			domain = domain[:idx]
-		}
-		if domain != "" {
-			newDomains = append(newDomains, domain)
-		}
+		// Tanpa cache: whitelist awal = domain registrasi, daftar lengkap datang dari domain watch
+		domainRegistry.Seed(out.NodeID, domain)
 	}
 
-	allowedDomains = newDomains
-	domainsLastUpdate = time.Now()
-
-	log.Printf("Domain whitelist updated: %v (nodeID=%s)", allowedDomains, currentNodeID)
+	return nil
 }
 
 // isDomainAllowed checks if the request domain is in the allowed list
 func isDomainAllowed(domain string) bool {
-	// Refresh cache if expired
-	if time.Since(domainsLastUpdate) > domainsCacheTTL {
-		// Non-blocking refresh (use cached while refreshing)
-		// This will be called in background via goroutine in redirect handler
-	}
-
-	if len(allowedDomains) == 0 {
-		// No domain restrictions (backward compatibility or fresh node)
-		return true
-	}
-
-	domain = strings.ToLower(strings.TrimSpace(domain))
-
-	for _, allowed := range allowedDomains {
-		if strings.EqualFold(allowed, domain) {
-			return true
-		}
-	}
-
-	return false
+	return domainRegistry.Allowed(domain)
 }
 
 // startHeartbeat → kirim status node berkala ke /nodes/heartbeat
-func startHeartbeat(apiBase, apiKey, nodeName, nodeRegion, nodePublicURL string) {
+func startHeartbeat(apiBase, nodeName, nodeRegion, nodePublicURL string) {
 	node := Node{
 		ID:           currentNodeID,
 		Name:         nodeName,
@@ -272,7 +214,7 @@ func startHeartbeat(apiBase, apiKey, nodeName, nodeRegion, nodePublicURL string)
 				log.Printf("heartbeat: error creating request: %v", err)
 			} else {
 				req.Header.Set("Content-Type", "application/json")
-				if apiKey != "" {
+				if apiKey := currentAPIKey(); apiKey != "" {
 					req.Header.Set("X-Nexus-Api-Key", apiKey)
 				}
 
@@ -280,23 +222,32 @@ func startHeartbeat(apiBase, apiKey, nodeName, nodeRegion, nodePublicURL string)
 				if err != nil {
 					log.Printf("heartbeat: error sending: %v", err)
 				} else {
-					resp.Body.Close()
-					if resp.StatusCode != http.StatusNoContent {
+					var out struct {
+						RotateCredential bool `json:"rotateCredential"`
+					}
+					if resp.StatusCode == http.StatusOK {
+						json.NewDecoder(resp.Body).Decode(&out)
+					} else if resp.StatusCode != http.StatusNoContent {
 						log.Printf("heartbeat: unexpected status %d", resp.StatusCode)
 					}
+					resp.Body.Close()
+
+					// Admin minta rotasi key dari dashboard
+					if out.RotateCredential {
+						if err := rotateCredentials(apiBase); err != nil {
+							log.Printf("heartbeat: credential rotation failed: %v", err)
+						}
+					}
 				}
 			}
 
-			// Refresh allowed domains every heartbeat (30s)
-			refreshAllowedDomains(apiBase, apiKey)
-
 			<-ticker.C
 		}
 	}()
 }
 
 // redirectHandler → handle /r/{alias}
-func redirectHandler(w http.ResponseWriter, r *http.Request, apiBase, apiKey string) {
+func redirectHandler(w http.ResponseWriter, r *http.Request, apiBase string) {
 	if r.Method != http.MethodGet {
 		w.WriteHeader(http.StatusMethodNotAllowed)
 		return
@@ -321,16 +272,12 @@ func redirectHandler(w http.ResponseWriter, r *http.Request, apiBase, apiKey str
 	// Security: Validate domain is allowed for this node
 	if !isDomainAllowed(currentDomain) {
 		log.Printf("Access denied: domain=%s not in whitelist %v (alias=%s)",
-			currentDomain, allowedDomains, alias)
+			currentDomain, domainRegistry.Domains(), alias)
+		recordRedirect("domain_not_allowed")
 		http.Error(w, "This domain is not authorized to serve links from this node", http.StatusForbidden)
 		return
 	}
 
-	// Trigger background domain cache refresh if needed (non-blocking)
-	if time.Since(domainsLastUpdate) > domainsCacheTTL {
-		go refreshAllowedDomains(apiBase, apiKey)
-	}
-
 	// ambil info visitor (IP, UA, referer)
 	// Prioritas: CF-Connecting-IP (Cloudflare) > X-Real-IP (nginx/proxy) > X-Forwarded-For > RemoteAddr
 	visitorIP := r.Header.Get("CF-Connecting-IP")
@@ -355,6 +302,18 @@ func redirectHandler(w http.ResponseWriter, r *http.Request, apiBase, apiKey str
 
 	visitorUA := r.Header.Get("User-Agent")
 	visitorRef := r.Referer()
+	visitorLang := r.Header.Get("Accept-Language")
+
+	// Edge cache: link yang ada di snapshot lokal di-resolve tanpa API.
+	// Link yang butuh data terpusat (max clicks, IP check) tetap lewat API, snapshot jadi fallback kalau API down.
+	var snap *models.LinkSnapshot
+	if linkStore != nil {
+		snap = linkStore.Lookup(currentDomain, alias)
+		if snap != nil && !edge.NeedsCentral(snap, linkStore.IPCheckEnabled(), linkStore.RateLimitEnabled()) {
+			serveLocal(w, r, snap, currentDomain, visitorIP, visitorUA, visitorRef, visitorLang)
+			return
+		}
+	}
 
 	// Include domain in API request for domain-specific link resolution
 	apiURL := fmt.Sprintf("%s/links/resolve?alias=%s&nodeId=%s&domain=%s",
@@ -370,7 +329,7 @@ func redirectHandler(w http.ResponseWriter, r *http.Request, apiBase, apiKey str
 		http.Error(w, "upstream error", http.StatusBadGateway)
 		return
 	}
-	if apiKey != "" {
+	if apiKey := currentAPIKey(); apiKey != "" {
 		req.Header.Set("X-Nexus-Api-Key", apiKey)
 	}
 
@@ -384,27 +343,80 @@ func redirectHandler(w http.ResponseWriter, r *http.Request, apiBase, apiKey str
 	if visitorRef != "" {
 		req.Header.Set("X-Visitor-Referer", visitorRef)
 	}
+	if visitorLang != "" {
+		req.Header.Set("X-Visitor-Accept-Language", visitorLang)
+	}
+	// query string asli (dipakai routing rules berbasis query param)
+	if r.URL.RawQuery != "" {
+		req.Header.Set("X-Visitor-Query", r.URL.RawQuery)
+	}
+	// token rate limit challenge yang sudah diselesaikan browser
+	if c, err := r.Cookie(challengeCookie); err == nil && c.Value != "" {
+		req.Header.Set("X-Visitor-Challenge", c.Value)
+	}
 
 	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
 		log.Printf("error calling API: %v", err)
-		http.Error(w, "upstream error", http.StatusBadGateway)
+		serveUnavailable(w, r, snap, currentDomain, visitorIP, visitorUA, visitorRef, visitorLang)
 		return
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode == http.StatusNotFound {
-		http.NotFound(w, r)
+	if resp.StatusCode >= http.StatusInternalServerError {
+		body, _ := io.ReadAll(resp.Body)
+		log.Printf("API returned status %d body=%s", resp.StatusCode, string(body))
+		serveUnavailable(w, r, snap, currentDomain, visitorIP, visitorUA, visitorRef, visitorLang)
+		return
+	}
+
+	if resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone {
+		// Link disabled → API kirim {"reason":"link_disabled","message":"..."}
+		var disabled struct {
+			Reason  string `json:"reason"`
+			Message string `json:"message"`
+		}
+		body, _ := io.ReadAll(resp.Body)
+		if json.Unmarshal(body, &disabled) == nil && disabled.Reason == "link_disabled" {
+			log.Printf("Link disabled: alias=%s status=%d", alias, resp.StatusCode)
+			recordRedirect("link_disabled")
+			renderDisabledPage(w, resp.StatusCode, disabled.Message)
+			return
+		}
+
+		http.Error(w, http.StatusText(resp.StatusCode), resp.StatusCode)
 		return
 	}
 
 	if resp.StatusCode == http.StatusForbidden {
 		body, _ := io.ReadAll(resp.Body)
 		log.Printf("Access forbidden: %s", string(body))
+		recordRedirect("access_forbidden")
 		http.Error(w, "access forbidden", http.StatusForbidden)
 		return
 	}
 
+	if resp.StatusCode == http.StatusTooManyRequests {
+		var limited struct {
+			Reason    string `json:"reason"`
+			Challenge string `json:"challenge"`
+		}
+		body, _ := io.ReadAll(resp.Body)
+		_ = json.Unmarshal(body, &limited)
+		log.Printf("Rate limited: alias=%s ip=%s challenge=%v", alias, visitorIP, limited.Challenge != "")
+		recordRedirect("rate_limited")
+
+		if retry := resp.Header.Get("Retry-After"); retry != "" {
+			w.Header().Set("Retry-After", retry)
+		}
+		if limited.Challenge != "" {
+			renderChallengePage(w, limited.Challenge)
+			return
+		}
+		http.Error(w, "too many requests", http.StatusTooManyRequests)
+		return
+	}
+
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
 		log.Printf("API returned status %d body=%s", resp.StatusCode, string(body))
@@ -434,5 +446,96 @@ func redirectHandler(w http.ResponseWriter, r *http.Request, apiBase, apiKey str
 		return
 	}
 
+	recordRedirect(link.Reason)
 	http.Redirect(w, r, targetURL, http.StatusFound)
 }
+
+var disabledPageTmpl = template.Must(template.New("disabled").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<meta name="robots" content="noindex">
+<title>Link unavailable</title>
+<style>
+body{margin:0;min-height:100vh;display:flex;align-items:center;justify-content:center;font-family:system-ui,-apple-system,sans-serif;background:#f8fafc;color:#0f172a}
+main{max-width:480px;padding:2rem;text-align:center}
+h1{font-size:1.5rem;margin:0 0 .75rem}
+p{color:#475569;line-height:1.5;white-space:pre-line}
+</style>
+</head>
+<body>
+<main>
+<h1>This link is no longer available</h1>
+{{if .}}<p>{{.}}</p>{{else}}<p>The link you followed has been disabled by its owner.</p>{{end}}
+</main>
+</body>
+</html>
+`))
+
+// renderDisabledPage → landing page untuk link yang di-disable (pesan per-link dari API)
+func renderDisabledPage(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	if err := disabledPageTmpl.Execute(w, message); err != nil {
+		log.Printf("render disabled page: %v", err)
+	}
+}
+
+// challengeCookie menyimpan token challenge rate limit di browser visitor
+const challengeCookie = "nexus_challenge"
+
+var challengePageTmpl = template.Must(template.New("challenge").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<meta name="robots" content="noindex">
+<title>Checking your browser</title>
+<style>
+body{margin:0;min-height:100vh;display:flex;align-items:center;justify-content:center;font-family:system-ui,-apple-system,sans-serif;background:#f8fafc;color:#0f172a}
+main{max-width:480px;padding:2rem;text-align:center}
+h1{font-size:1.5rem;margin:0 0 .75rem}
+p{color:#475569;line-height:1.5}
+</style>
+</head>
+<body>
+<main>
+<h1>Checking your browser</h1>
+<p id="msg">You will be redirected in a moment.</p>
+<noscript><p>Please enable JavaScript to continue.</p></noscript>
+</main>
+<script>
+(function () {
+  var key = "nexus_challenge_attempts";
+  var attempts = parseInt(sessionStorage.getItem(key) || "0", 10);
+  if (attempts >= 3) {
+    sessionStorage.removeItem(key);
+    document.getElementById("msg").textContent = "Too many requests. Please try again later.";
+    return;
+  }
+  sessionStorage.setItem(key, String(attempts + 1));
+  document.cookie = "{{.Cookie}}=" + {{.Token}} + "; path=/r/; max-age={{.MaxAge}}; SameSite=Lax";
+  setTimeout(function () { location.reload(); }, 1000);
+})();
+</script>
+</body>
+</html>
+`))
+
+// renderChallengePage → halaman JS challenge saat visitor kena rate limit dengan action "challenge".
+// Browser menyimpan token di cookie lalu reload; token di-forward ke API lewat X-Visitor-Challenge.
+func renderChallengePage(w http.ResponseWriter, token string) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusTooManyRequests)
+	err := challengePageTmpl.Execute(w, map[string]interface{}{
+		"Cookie": challengeCookie,
+		"Token":  token,
+		"MaxAge": int(ratelimit.ChallengeTTL.Seconds()),
+	})
+	if err != nil {
+		log.Printf("render challenge page: %v", err)
+	}
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"golang.org/x/crypto/acme"
+	"golang.org/x/crypto/acme/autocert"
+
+	"github.com/afuzapratama/nexuslink/internal/config"
+)
+
+// TLS bawaan agent (opsional, NEXUS_AGENT_TLS=true). Sertifikat per domain diterbitkan lewat ACME
+// (HTTP-01 dilayani listener HTTP agent sendiri) dan disimpan di NEXUS_AGENT_CERT_DIR.
+// Hanya domain yang ada di daftar domain node (domainRegistry, dari API) yang boleh diterbitkan.
+
+// newCertManager returns nil kalau TLS tidak diaktifkan
+func newCertManager() (*autocert.Manager, error) {
+	if config.GetEnv("NEXUS_AGENT_TLS", "false") != "true" {
+		return nil, nil
+	}
+
+	certDir := config.GetEnv("NEXUS_AGENT_CERT_DIR",
+		filepath.Join(config.GetEnv("NEXUS_AGENT_CACHE_DIR", "./data"), "certs"))
+	if err := os.MkdirAll(certDir, 0o700); err != nil {
+		return nil, err
+	}
+
+	// Directory alternatif, misalnya Pebble untuk testing atau staging Let's Encrypt
+	client := &acme.Client{
+		DirectoryURL: config.GetEnv("NEXUS_AGENT_ACME_DIRECTORY", autocert.DefaultACMEDirectory),
+	}
+
+	// CA tambahan untuk koneksi ke ACME directory (Pebble pakai sertifikat self-signed)
+	if caPath := config.GetEnv("NEXUS_AGENT_ACME_CA_CERT", ""); caPath != "" {
+		pem, err := os.ReadFile(caPath)
+		if err != nil {
+			return nil, fmt.Errorf("read ACME CA cert: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil || pool == nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in %s", caPath)
+		}
+		client.HTTPClient = &http.Client{
+			Timeout:   30 * time.Second,
+			Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: pool}},
+		}
+	}
+
+	return &autocert.Manager{
+		Prompt:     autocert.AcceptTOS,
+		Cache:      autocert.DirCache(certDir),
+		HostPolicy: certHostPolicy,
+		Client:     client,
+		Email:      config.GetEnv("NEXUS_AGENT_ACME_EMAIL", ""),
+	}, nil
+}
+
+// certHostPolicy menolak domain yang tidak terdaftar di node ini (whitelist kosong = tolak semua)
+func certHostPolicy(_ context.Context, host string) error {
+	if !domainRegistry.Contains(host) {
+		return fmt.Errorf("domain %q is not assigned to node %s", host, currentNodeID)
+	}
+	return nil
+}
+
+// startTLS menjalankan listener HTTPS di NEXUS_AGENT_TLS_PORT (default :443)
+func startTLS(m *autocert.Manager, handler http.Handler) {
+	addr := config.GetEnv("NEXUS_AGENT_TLS_PORT", ":443")
+
+	srv := &http.Server{
+		Addr:      addr,
+		Handler:   handler,
+		TLSConfig: m.TLSConfig(),
+	}
+
+	go func() {
+		log.Printf("Nexus Agent TLS listening on %s (ACME: %s)", addr, m.Client.DirectoryURL)
+		if err := srv.ListenAndServeTLS("", ""); err != nil {
+			log.Fatalf("agent TLS server error: %v", err)
+		}
+	}()
+}
@@ -3,24 +3,37 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
 	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
 	"strconv"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/google/uuid"
 	"golang.org/x/crypto/bcrypt"
 
+	aliaspkg "github.com/afuzapratama/nexuslink/internal/alias"
 	"github.com/afuzapratama/nexuslink/internal/config"
 	"github.com/afuzapratama/nexuslink/internal/database"
 	"github.com/afuzapratama/nexuslink/internal/geoip"
 	"github.com/afuzapratama/nexuslink/internal/handler"
+	"github.com/afuzapratama/nexuslink/internal/ingest"
+	"github.com/afuzapratama/nexuslink/internal/metrics"
 	"github.com/afuzapratama/nexuslink/internal/models"
+	"github.com/afuzapratama/nexuslink/internal/nodeauth"
+	"github.com/afuzapratama/nexuslink/internal/nodemonitor"
 	"github.com/afuzapratama/nexuslink/internal/ratelimit"
 	"github.com/afuzapratama/nexuslink/internal/repository"
+	"github.com/afuzapratama/nexuslink/internal/rollup"
+	"github.com/afuzapratama/nexuslink/internal/steering"
+	"github.com/afuzapratama/nexuslink/internal/storage"
 	"github.com/afuzapratama/nexuslink/internal/util"
 	"github.com/afuzapratama/nexuslink/internal/webhook"
 )
@@ -60,32 +73,33 @@ func main() {
 
 	log.Println("Nexus API starting...")
 
-	log.Println("Ensuring DynamoDB tables...")
-	if err := database.EnsureTables(context.Background()); err != nil {
-		log.Fatalf("failed to ensure tables: %v", err)
+	// Storage backend: NEXUS_STORAGE=dynamodb (default) | sqlite | postgres
+	stores, err := storage.Open(context.Background())
+	if err != nil {
+		log.Fatalf("failed to open storage: %v", err)
 	}
-	log.Println("DynamoDB tables ready")
+	defer stores.Close()
 
 	// Initialize Redis for rate limiting analytics
 	redisClient := database.GetRedisClient()
-	var rateLimiter *ratelimit.Limiter
+	rateLimiter := ratelimit.NewLimiter(redisClient)
 	if redisClient != nil {
-		rateLimiter = ratelimit.NewLimiter(redisClient)
-		log.Println("Rate limiter initialized for analytics")
+		log.Println("Rate limiter initialized")
 	} else {
-		log.Println("Redis not available, rate limit analytics disabled")
+		log.Println("Redis not available, rate limiting uses in-process fallback (per instance, no analytics)")
 	}
 
 	// Initialize repositories
-	nodeRepo := repository.NewNodeRepository()
-	statsRepo := repository.NewLinkStatsRepository()
-	linkRepo := repository.NewLinkRepository()
-	nodeTokenRepo := repository.NewNodeTokenRepository()
-	clickRepo := repository.NewClickRepository()
-	settingsRepo := repository.NewSettingsRepository()
-	groupRepo := repository.NewLinkGroupRepository()
-	webhookRepo := repository.NewWebhookRepository(database.Client(), database.WebhooksTableName)
-	variantRepo := repository.NewLinkVariantRepository(database.Client())
+	nodeRepo := stores.Nodes
+	nodeEventRepo := stores.NodeEvents
+	statsRepo := stores.Stats
+	linkRepo := stores.Links
+	nodeTokenRepo := stores.Tokens
+	clickRepo := stores.Clicks
+	settingsRepo := stores.Settings
+	groupRepo := stores.Groups
+	webhookRepo := stores.Webhooks
+	variantRepo := stores.Variants
 
 	// Initialize default settings if not exists
 	ctx := context.Background()
@@ -105,14 +119,64 @@ func main() {
 		log.Println("Settings already exist")
 	}
 
-	// Initialize webhook sender
-	webhookSender := webhook.NewSender()
+	// Initialize webhook sender (setiap attempt dicatat, delivery gagal final masuk dead letter)
+	webhookSender := webhook.NewSender(stores.Deliveries)
+
+	// Event webhook ditulis ke outbox di request, dikirim worker pool (tahan restart)
+	webhookWorkers, _ := strconv.Atoi(config.GetEnv("NEXUS_WEBHOOK_WORKERS", ""))
+	webhookPerEndpoint, _ := strconv.Atoi(config.GetEnv("NEXUS_WEBHOOK_ENDPOINT_CONCURRENCY", ""))
+	webhookHorizon, err := time.ParseDuration(config.GetEnv("NEXUS_WEBHOOK_RETRY_HORIZON", "24h"))
+	if err != nil {
+		webhookHorizon = webhook.DefaultRetryHorizon
+	}
+	webhookDispatcher := webhook.NewDispatcher(webhookSender, webhookRepo, stores.Outbox, webhookWorkers, webhookPerEndpoint, webhookHorizon)
+	// Handler CRUD webhook memakai variabel lokal bernama webhook (menutupi package)
+	validateWebhook := webhook.Validate
+	// Cache webhook di Publish dibaca ulang setelah CRUD, bukan di redirect berikutnya
+	refreshWebhooks := func(ctx context.Context) {
+		if err := webhookDispatcher.Refresh(ctx); err != nil {
+			log.Printf("webhook subscriptions refresh failed: %v", err)
+		}
+	}
+
+	// Click ingestion pipeline: queue in-memory → BatchWriteItem, overflow di-spill ke disk
+	clickOpts := ingest.DefaultOptions()
+	if n, err := strconv.Atoi(config.GetEnv("NEXUS_CLICK_QUEUE_SIZE", "")); err == nil && n > 0 {
+		clickOpts.QueueSize = n
+	}
+	if n, err := strconv.Atoi(config.GetEnv("NEXUS_CLICK_BATCH_SIZE", "")); err == nil && n > 0 {
+		clickOpts.BatchSize = n
+	}
+	clickOpts.SpillPath = filepath.Join(config.GetEnv("NEXUS_CLICK_SPILL_DIR", "./data"), "clicks-spill.jsonl")
+	rollupRepo := stores.Rollups
+	clickPipeline := ingest.NewClickPipeline(&rollup.Writer{Clicks: clickRepo, Rollups: rollupRepo}, clickOpts)
+	clickPipeline.Start()
+
+	// Audit log perubahan admin (append-only)
+	auditor := handler.NewAuditor(stores.Audit)
+	auditHandler := handler.NewAuditHandler(stores.Audit)
 
 	// Initialize handlers
-	linkHandler := handler.NewLinkHandler(linkRepo, statsRepo, clickRepo, webhookRepo, webhookSender)
-	resolverHandler := handler.NewResolverHandler(linkRepo, statsRepo, clickRepo, settingsRepo, webhookRepo, webhookSender, variantRepo)
-	variantHandler := handler.NewVariantHandler(variantRepo, linkRepo)
-	authHandler := handler.NewAuthHandler(settingsRepo)
+	linkHandler := handler.NewLinkHandler(linkRepo, statsRepo, clickRepo, webhookDispatcher, nodeRepo, variantRepo, stores.Revisions, settingsRepo, auditor)
+	resolverHandler := handler.NewResolverHandler(linkRepo, statsRepo, clickPipeline, settingsRepo, webhookDispatcher, variantRepo, stores.ClickDedup, rateLimiter)
+	variantHandler := handler.NewVariantHandler(variantRepo, linkRepo, auditor)
+	authHandler := handler.NewAuthHandler(stores.Users, stores.Sessions)
+	userHandler := handler.NewUserHandler(stores.Users, authHandler, auditor)
+
+	// Migrasi login lama: user owner pertama dibuat dari AdminUsername/AdminPassword di Settings
+	if err := authHandler.Bootstrap(ctx, settingsRepo.GetOrDefault(ctx)); err != nil {
+		log.Printf("Warning: failed to bootstrap owner user: %v", err)
+	}
+
+	// Scope auth: admin (NEXUS_API_KEY / session dashboard) vs agent (key per node dari /nodes/register)
+	nodeCredRepo := stores.NodeCredentials
+	auth := handler.NewAuthenticator(config.GetEnv("NEXUS_API_KEY", ""), authHandler, nodeCredRepo)
+	analyticsHandler := handler.NewAnalyticsHandler(rollupRepo, linkRepo)
+	deliveryHandler := handler.NewWebhookDeliveryHandler(webhookRepo, stores.Deliveries, webhookSender, auditor)
+
+	// Push domain ke agent: feed ditandatangani HMAC (default pakai NEXUS_API_KEY)
+	domainPushSecret := config.GetEnv("NEXUS_DOMAIN_PUSH_SECRET", config.GetEnv("NEXUS_API_KEY", ""))
+	domainFeedHandler := handler.NewDomainFeedHandler(nodeRepo, domainPushSecret)
 
 	mux := http.NewServeMux()
 
@@ -123,15 +187,18 @@ func main() {
 		w.Write([]byte("OK - Nexus API is running"))
 	})
 
+	// Prometheus metrics (tanpa auth, sama seperti /health; batasi di reverse proxy)
+	mux.Handle("/metrics", metrics.MetricsHandler())
+
 	// Auth endpoints (no WithAgentAuth - these use session token)
 	mux.HandleFunc("/auth/login", authHandler.HandleLogin)
 	mux.HandleFunc("/auth/logout", authHandler.HandleLogout)
 	mux.HandleFunc("/auth/session", authHandler.HandleSession)
 
 	// Link endpoints (migrated to handler)
-	mux.HandleFunc("/links", handler.WithAgentAuth(linkHandler.HandleLinks))
-	mux.HandleFunc("/links/bulk/toggle", handler.WithAgentAuth(linkHandler.HandleBulkToggle))
-	mux.HandleFunc("/links/bulk/delete", handler.WithAgentAuth(linkHandler.HandleBulkDelete))
+	mux.HandleFunc("/links", auth.Role(models.RoleAnalyst, models.RoleEditor, linkHandler.HandleLinks))
+	mux.HandleFunc("/links/bulk/toggle", auth.Require(models.RoleEditor, linkHandler.HandleBulkToggle))
+	mux.HandleFunc("/links/bulk/delete", auth.Require(models.RoleEditor, linkHandler.HandleBulkDelete))
 
 	// Combined handler for /links/:alias/* routes (QRCode, variants, convert, update, delete)
 	mux.HandleFunc("/links/", func(w http.ResponseWriter, r *http.Request) {
@@ -140,7 +207,7 @@ func main() {
 
 		// /links/:alias (PUT/DELETE for single link)
 		if len(parts) == 1 && parts[0] != "" {
-			handler.WithAgentAuth(linkHandler.HandleLinkByAlias)(w, r)
+			auth.Role(models.RoleAnalyst, models.RoleEditor, linkHandler.HandleLinkByAlias)(w, r)
 			return
 		}
 
@@ -148,7 +215,7 @@ func main() {
 			// /links/:alias/variants or /links/:alias/variants/:id
 			if parts[1] == "variants" {
 				// Require auth for variant endpoints
-				handler.WithAgentAuth(func(w http.ResponseWriter, r *http.Request) {
+				auth.Role(models.RoleAnalyst, models.RoleEditor, func(w http.ResponseWriter, r *http.Request) {
 					if len(parts) == 2 {
 						// /links/:alias/variants
 						if r.Method == http.MethodGet {
@@ -174,9 +241,15 @@ func main() {
 				return
 			}
 
+			// /links/:alias/revisions[/diff | /:n | /:n/restore]
+			if parts[1] == "revisions" {
+				auth.Role(models.RoleAnalyst, models.RoleEditor, linkHandler.HandleRevisions)(w, r)
+				return
+			}
+
 			// /links/:alias/convert
 			if parts[1] == "convert" {
-				handler.WithAgentAuth(variantHandler.HandleConvert)(w, r)
+				auth.Require(models.RoleEditor, variantHandler.HandleConvert)(w, r)
 				return
 			}
 
@@ -185,6 +258,12 @@ func main() {
 				linkHandler.HandleQRCode(w, r)
 				return
 			}
+
+			// /links/:alias/url (canonical short URL)
+			if parts[1] == "url" {
+				auth.Admin(linkHandler.HandleShortURL)(w, r)
+				return
+			}
 		}
 
 		// Default: not found
@@ -192,10 +271,14 @@ func main() {
 	})
 
 	// Resolver endpoint (migrated to handler)
-	mux.HandleFunc("/links/resolve", handler.WithAgentAuth(resolverHandler.HandleResolve))
+	mux.HandleFunc("/links/resolve", auth.Agent(resolverHandler.HandleResolve))
+
+	// Agent edge cache: delta feed link + batch click dari agent
+	mux.HandleFunc("/sync/links", auth.Agent(resolverHandler.HandleSyncLinks))
+	mux.HandleFunc("/sync/clicks", auth.Agent(resolverHandler.HandleSyncClicks))
 
 	// Countries endpoint - returns list of countries for dropdown
-	mux.HandleFunc("/countries", handler.WithAgentAuth(func(w http.ResponseWriter, r *http.Request) {
+	mux.HandleFunc("/countries", auth.Admin(func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != http.MethodGet {
 			w.WriteHeader(http.StatusMethodNotAllowed)
 			return
@@ -206,10 +289,13 @@ func main() {
 		json.NewEncoder(w).Encode(countries)
 	}))
 
+	// Analytics time-series dari tabel rollup (hourly/daily)
+	mux.HandleFunc("/analytics/series", auth.Admin(analyticsHandler.HandleSeries))
+
 	// ======== TODO: MIGRATE THESE TO HANDLERS LATER ========
 
 	// Analytics endpoints
-	mux.HandleFunc("/analytics/clicks", handler.WithAgentAuth(func(w http.ResponseWriter, r *http.Request) {
+	mux.HandleFunc("/analytics/clicks", auth.Admin(func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != http.MethodGet {
 			w.WriteHeader(http.StatusMethodNotAllowed)
 			return
@@ -221,6 +307,19 @@ func main() {
 			return
 		}
 
+		// Click disimpan per key data link (?domain= untuk link ber-domain)
+		alias, ok := handler.AnalyticsKey(w, r, linkRepo, alias)
+		if !ok {
+			return
+		}
+
+		// Cursor mode: ?cursor=&limit= (terbaru dulu)
+		if cursor, limit, ok := handler.CursorParams(r, 10, 100); ok {
+			events, next, err := clickRepo.ListByAliasPage(r.Context(), alias, limit, cursor)
+			handler.WriteCursorPage(w, events, limit, next, err)
+			return
+		}
+
 		// Parse pagination params
 		page := 1
 		limit := 10
@@ -257,12 +356,19 @@ func main() {
 	}))
 
 	// Get all clicks (for dashboard)
-	mux.HandleFunc("/analytics/clicks/all", handler.WithAgentAuth(func(w http.ResponseWriter, r *http.Request) {
+	mux.HandleFunc("/analytics/clicks/all", auth.Admin(func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != http.MethodGet {
 			w.WriteHeader(http.StatusMethodNotAllowed)
 			return
 		}
 
+		// Cursor mode: ?cursor=&limit=
+		if cursor, limit, ok := handler.CursorParams(r, 100, 1000); ok {
+			events, next, err := clickRepo.ListAllPage(r.Context(), limit, cursor)
+			handler.WriteCursorPage(w, events, limit, next, err)
+			return
+		}
+
 		// Parse pagination params
 		page := 1
 		limit := 100
@@ -299,7 +405,7 @@ func main() {
 	}))
 
 	// Node endpoints
-	mux.HandleFunc("/nodes/heartbeat", handler.WithAgentAuth(func(w http.ResponseWriter, r *http.Request) {
+	mux.HandleFunc("/nodes/heartbeat", auth.Agent(func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != http.MethodPost {
 			w.WriteHeader(http.StatusMethodNotAllowed)
 			return
@@ -314,6 +420,11 @@ func main() {
 			http.Error(w, "id is required", http.StatusBadRequest)
 			return
 		}
+		// Key node hanya boleh heartbeat atas nama node itu sendiri
+		if credNodeID := handler.NodeIDFromContext(r.Context()); credNodeID != "" && credNodeID != n.ID {
+			http.Error(w, "node credential does not match node id", http.StatusForbidden)
+			return
+		}
 
 		// Get IP address
 		ip := r.Header.Get("X-Real-IP")
@@ -333,10 +444,47 @@ func main() {
 			return
 		}
 
+		// Admin minta rotasi key → beri tahu agent, agent memanggil /nodes/credentials/rotate
+		if handler.NodeIDFromContext(r.Context()) != "" {
+			cred, err := auth.Credential(r.Context(), n.ID)
+			if err != nil {
+				log.Printf("heartbeat: load credential for node %s failed: %v", n.ID, err)
+			} else if cred != nil && cred.RotateRequested {
+				w.Header().Set("Content-Type", "application/json")
+				json.NewEncoder(w).Encode(map[string]bool{"rotateCredential": true})
+				return
+			}
+		}
+
 		w.WriteHeader(http.StatusNoContent)
 	}))
 
-	mux.HandleFunc("/nodes/register", handler.WithAgentAuth(func(w http.ResponseWriter, r *http.Request) {
+	// Agent mengganti key-nya sendiri (memakai key lama); key lama langsung tidak berlaku
+	mux.HandleFunc("/nodes/credentials/rotate", auth.Node(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		nodeID := handler.NodeIDFromContext(r.Context())
+		apiKey, cred, err := nodeauth.NewKey(nodeID)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if err := nodeCredRepo.Put(r.Context(), cred); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		auth.Invalidate(nodeID)
+		log.Printf("node %s rotated its API credential (%s)", nodeID, cred.Prefix)
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]string{"nodeId": nodeID, "apiKey": apiKey})
+	}))
+
+	// Registrasi diautentikasi oleh node token dari dashboard (agent baru tidak perlu admin key)
+	mux.HandleFunc("/nodes/register", func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != http.MethodPost {
 			w.WriteHeader(http.StatusMethodNotAllowed)
 			return
@@ -388,43 +536,37 @@ func main() {
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
 		}
-		if nt == nil {
+		if nt == nil || !nt.Usable(time.Now()) {
 			http.Error(w, "invalid token", http.StatusUnauthorized)
 			return
 		}
 
-		// Generate UUID untuk nodeID (tidak pakai domain karena domain ada titik/dot yang bikin routing error)
-		nodeID := uuid.New().String()
-
-		// Cek apakah node dengan domain ini sudah ada
-		existingNodes, err := nodeRepo.List(r.Context())
+		// Registrasi hanya untuk node baru: domain yang sudah punya node tidak bisa di-key ulang
+		// lewat token (itu sama dengan mengambil alih node). Ganti key lewat admin
+		// (POST /admin/nodes/:id/credential) atau hapus node lalu registrasi ulang.
+		existing, err := nodeRepo.GetNodeByDomain(r.Context(), body.Domain)
 		if err != nil {
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
 		}
+		if existing != nil {
+			http.Error(w, "domain is already registered to node "+existing.ID+"; rotate its key from the dashboard or delete the node first", http.StatusConflict)
+			return
+		}
 
-		// Jika sudah ada node dengan domain yang sama DAN nodeID-nya sudah format UUID, pakai yang lama
-		// Jika nodeID masih format lama (node-domain.com), generate UUID baru (migration)
-		for _, n := range existingNodes {
-			// Check if domain exists in n.Domains
-			domainExists := false
-			for _, d := range n.Domains {
-				if d == body.Domain {
-					domainExists = true
-					break
-				}
-			}
-
-			if domainExists {
-				// Cek apakah ID lama adalah UUID (36 chars dengan format xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx)
-				if len(n.ID) == 36 && strings.Count(n.ID, "-") == 4 {
-					nodeID = n.ID // UUID valid, pakai yang lama
-				}
-				// Else: ID lama format "node-domain.com", biarkan pakai UUID baru (migration otomatis)
-				break
+		// Token sekali pakai: klaim atomic sebelum node dibuat, registrasi paralel dengan token yang sama ditolak
+		if err := nodeTokenRepo.MarkUsed(r.Context(), body.Token); err != nil {
+			if errors.Is(err, repository.ErrTokenUnavailable) {
+				http.Error(w, "invalid token", http.StatusUnauthorized)
+				return
 			}
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
 		}
 
+		// Generate UUID untuk nodeID (tidak pakai domain karena domain ada titik/dot yang bikin routing error)
+		nodeID := uuid.New().String()
+
 		node := &models.Node{
 			ID:           nodeID,
 			Name:         nt.Label,
@@ -440,30 +582,42 @@ func main() {
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
 		}
+		domainFeedHandler.Notify(node.ID)
 
-		// tandai token sudah dipakai (opsional, masih boleh dipakai ulang kalau mau)
-		if err := nodeTokenRepo.MarkUsed(r.Context(), body.Token); err != nil {
-			log.Printf("warning: failed to mark token used: %v", err)
+		apiKey, cred, err := nodeauth.NewKey(node.ID)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if err := nodeCredRepo.Put(r.Context(), cred); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
 		}
+		auth.Invalidate(node.ID)
 
 		resp := struct {
 			NodeID    string `json:"nodeId"`
 			Name      string `json:"name"`
 			Region    string `json:"region"`
 			PublicURL string `json:"publicUrl"` // Deprecated but kept for agent compatibility
+			APIKey    string `json:"apiKey"`    // Key node (scope agent), hanya dikirim sekali
 		}{
 			NodeID:    node.ID,
 			Name:      node.Name,
 			Region:    node.Region,
 			PublicURL: body.PublicURL, // Echo back what agent sent
+			APIKey:    apiKey,
 		}
 
 		w.Header().Set("Content-Type", "application/json")
 		json.NewEncoder(w).Encode(resp)
-	}))
+	})
+
+	// Long-poll daftar domain node (agent menunggu sampai DomainsVersion berubah)
+	mux.HandleFunc("/nodes/domains", auth.Agent(domainFeedHandler.HandleWatch))
 
 	// Admin endpoints
-	mux.HandleFunc("/admin/nodes", handler.WithAgentAuth(func(w http.ResponseWriter, r *http.Request) {
+	mux.HandleFunc("/admin/nodes", auth.Admin(func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != http.MethodGet {
 			w.WriteHeader(http.StatusMethodNotAllowed)
 			return
@@ -475,12 +629,49 @@ func main() {
 			return
 		}
 
+		// Uptime 24h/7d dari riwayat NodeEvent (field di-flatten ke objek node)
+		type nodeWithUptime struct {
+			models.Node
+			nodemonitor.NodeUptime
+		}
+		now := time.Now().UTC()
+		out := make([]nodeWithUptime, 0, len(nodes))
+		for _, n := range nodes {
+			up, err := nodemonitor.UptimeFor(r.Context(), nodeEventRepo, n.ID, now)
+			if err != nil {
+				log.Printf("node uptime: node=%s err=%v", n.ID, err)
+			}
+			out = append(out, nodeWithUptime{Node: n, NodeUptime: up})
+		}
+
 		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode(nodes)
+		json.NewEncoder(w).Encode(out)
+	}))
+
+	// Status semua domain node: healthy / degraded (node offline)
+	mux.HandleFunc("/admin/domains", auth.Admin(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		nodes, err := nodeRepo.List(r.Context())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		domains := steering.DomainStatuses(nodes)
+		if domains == nil {
+			domains = []steering.Candidate{}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(domains)
 	}))
 
 	// Node domain management endpoints
-	mux.HandleFunc("/admin/nodes/", handler.WithAgentAuth(func(w http.ResponseWriter, r *http.Request) {
+	mux.HandleFunc("/admin/nodes/", auth.Role(models.RoleAnalyst, models.RoleOwner, func(w http.ResponseWriter, r *http.Request) {
 		// Parse path: /admin/nodes/:id or /admin/nodes/:id/domains
 		path := strings.TrimPrefix(r.URL.Path, "/admin/nodes/")
 		parts := strings.Split(path, "/")
@@ -511,12 +702,53 @@ func main() {
 				json.NewEncoder(w).Encode(node)
 				return
 
+			case http.MethodPatch:
+				// Update bobot steering node
+				var input struct {
+					Weight *int `json:"weight"`
+				}
+				if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+					http.Error(w, "invalid json", http.StatusBadRequest)
+					return
+				}
+				if input.Weight == nil || *input.Weight < 0 || *input.Weight > 1000 {
+					http.Error(w, "weight must be between 0 and 1000", http.StatusBadRequest)
+					return
+				}
+
+				before, err := nodeRepo.GetByID(r.Context(), nodeID)
+				if err != nil {
+					http.Error(w, err.Error(), http.StatusInternalServerError)
+					return
+				}
+				if err := nodeRepo.SetWeight(r.Context(), nodeID, *input.Weight); err != nil {
+					http.Error(w, err.Error(), http.StatusInternalServerError)
+					return
+				}
+				if before != nil {
+					auditor.Record(r, "node.update", "node", nodeID,
+						map[string]int{"weight": before.Weight}, map[string]int{"weight": *input.Weight})
+				}
+
+				w.WriteHeader(http.StatusNoContent)
+				return
+
 			case http.MethodDelete:
 				// Delete node from database
+				before, err := nodeRepo.GetByID(r.Context(), nodeID)
+				if err != nil {
+					http.Error(w, err.Error(), http.StatusInternalServerError)
+					return
+				}
 				if err := nodeRepo.Delete(r.Context(), nodeID); err != nil {
 					http.Error(w, err.Error(), http.StatusInternalServerError)
 					return
 				}
+				auditor.Record(r, "node.delete", "node", nodeID, before, nil)
+				if err := nodeCredRepo.Delete(r.Context(), nodeID); err != nil {
+					log.Printf("warning: failed to delete credential of node %s: %v", nodeID, err)
+				}
+				auth.Invalidate(nodeID)
 
 				w.Header().Set("Content-Type", "application/json")
 				json.NewEncoder(w).Encode(map[string]string{
@@ -531,6 +763,79 @@ func main() {
 			}
 		}
 
+		// GET /admin/nodes/:id/events - Riwayat online/offline (default 7 hari, ?days=N maks 30)
+		if len(parts) == 2 && parts[1] == "events" {
+			if r.Method != http.MethodGet {
+				w.WriteHeader(http.StatusMethodNotAllowed)
+				return
+			}
+
+			days := 7
+			if d, err := strconv.Atoi(r.URL.Query().Get("days")); err == nil && d > 0 && d <= 30 {
+				days = d
+			}
+
+			events, err := nodeEventRepo.ListSince(r.Context(), nodeID, time.Now().UTC().AddDate(0, 0, -days))
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			if events == nil {
+				events = []models.NodeEvent{}
+			}
+
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(events)
+			return
+		}
+
+		// /admin/nodes/:id/credential - GET status key node, POST minta rotasi, DELETE revoke
+		if len(parts) == 2 && parts[1] == "credential" {
+			cred, err := nodeCredRepo.Get(r.Context(), nodeID)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			if cred == nil {
+				http.Error(w, "node has no credential, delete the node and re-register the agent with a new node token", http.StatusNotFound)
+				return
+			}
+
+			before := cred.Info()
+			switch r.Method {
+			case http.MethodGet:
+			case http.MethodPost:
+				if cred.Revoked() {
+					http.Error(w, "credential is revoked, delete the node and re-register the agent with a new node token", http.StatusConflict)
+					return
+				}
+				cred.RotateRequested = true
+			case http.MethodDelete:
+				cred.RevokedAt = time.Now().UTC()
+			default:
+				w.WriteHeader(http.StatusMethodNotAllowed)
+				return
+			}
+
+			if r.Method != http.MethodGet {
+				if err := nodeCredRepo.Put(r.Context(), cred); err != nil {
+					http.Error(w, err.Error(), http.StatusInternalServerError)
+					return
+				}
+				auth.Invalidate(nodeID)
+
+				action := "node.credential_rotate"
+				if r.Method == http.MethodDelete {
+					action = "node.credential_revoke"
+				}
+				auditor.Record(r, action, "node", nodeID, before, cred.Info())
+			}
+
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(cred.Info())
+			return
+		}
+
 		// Domain management: /admin/nodes/:id/domains
 		if len(parts) < 2 || parts[1] != "domains" {
 			http.Error(w, "invalid path", http.StatusBadRequest)
@@ -563,6 +868,8 @@ func main() {
 				http.Error(w, err.Error(), http.StatusInternalServerError)
 				return
 			}
+			domainFeedHandler.Notify(nodeID)
+			auditor.Record(r, "node.domain_add", "node", nodeID, nil, map[string]string{"domain": strings.TrimSpace(input.Domain)})
 
 			w.WriteHeader(http.StatusNoContent)
 
@@ -578,6 +885,8 @@ func main() {
 				http.Error(w, err.Error(), http.StatusInternalServerError)
 				return
 			}
+			domainFeedHandler.Notify(nodeID)
+			auditor.Record(r, "node.domain_remove", "node", nodeID, map[string]string{"domain": domain}, nil)
 
 			w.WriteHeader(http.StatusNoContent)
 
@@ -586,7 +895,7 @@ func main() {
 		}
 	}))
 
-	mux.HandleFunc("/admin/link-stats", handler.WithAgentAuth(func(w http.ResponseWriter, r *http.Request) {
+	mux.HandleFunc("/admin/link-stats", auth.Admin(func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != http.MethodGet {
 			w.WriteHeader(http.StatusMethodNotAllowed)
 			return
@@ -602,7 +911,7 @@ func main() {
 		json.NewEncoder(w).Encode(stats)
 	}))
 
-	mux.HandleFunc("/admin/settings", handler.WithAgentAuth(func(w http.ResponseWriter, r *http.Request) {
+	mux.HandleFunc("/admin/settings", auth.Require(models.RoleOwner, func(w http.ResponseWriter, r *http.Request) {
 		switch r.Method {
 		case http.MethodGet:
 			settings, err := settingsRepo.Get(r.Context())
@@ -645,6 +954,11 @@ func main() {
 
 			// Ambil settings existing untuk preserve CreatedAt dan credentials
 			existing, _ := settingsRepo.Get(r.Context())
+			var before *models.Settings
+			if existing != nil {
+				copied := *existing
+				before = &copied
+			}
 			if existing != nil {
 				input.CreatedAt = existing.CreatedAt
 
@@ -664,12 +978,24 @@ func main() {
 				if strings.HasPrefix(input.IPQualityScoreAPIKey, "****") {
 					input.IPQualityScoreAPIKey = existing.IPQualityScoreAPIKey
 				}
+
+				// Rate limit dikelola lewat /admin/settings/rate-limit, jangan ikut ter-reset
+				input.RateLimitPerIP = existing.RateLimitPerIP
+				input.RateLimitPerLink = existing.RateLimitPerLink
+				input.RateLimitWindow = existing.RateLimitWindow
+				input.EnableRateLimit = existing.EnableRateLimit
+				input.RateLimitAction = existing.RateLimitAction
+				input.RateLimitAlgorithm = existing.RateLimitAlgorithm
+
+				// Alias dikelola lewat /admin/settings/aliases
+				input.Aliases = existing.Aliases
 			}
 
 			if err := settingsRepo.Update(r.Context(), &input); err != nil {
 				http.Error(w, err.Error(), http.StatusInternalServerError)
 				return
 			}
+			auditor.Record(r, "settings.update", "settings", "global", before, &input)
 
 			w.Header().Set("Content-Type", "application/json")
 			json.NewEncoder(w).Encode(input)
@@ -679,7 +1005,7 @@ func main() {
 		}
 	}))
 
-	mux.HandleFunc("/admin/node-tokens", handler.WithAgentAuth(func(w http.ResponseWriter, r *http.Request) {
+	mux.HandleFunc("/admin/node-tokens", auth.Require(models.RoleOwner, func(w http.ResponseWriter, r *http.Request) {
 		switch r.Method {
 		case http.MethodGet:
 			tokens, err := nodeTokenRepo.List(r.Context())
@@ -713,6 +1039,7 @@ func main() {
 				http.Error(w, err.Error(), http.StatusInternalServerError)
 				return
 			}
+			auditor.Record(r, "node_token.create", "node_token", nt.Label, nil, nt)
 
 			w.Header().Set("Content-Type", "application/json")
 			json.NewEncoder(w).Encode(nt)
@@ -723,7 +1050,7 @@ func main() {
 	}))
 
 	// Link Groups endpoints
-	mux.HandleFunc("/admin/groups", handler.WithAgentAuth(func(w http.ResponseWriter, r *http.Request) {
+	mux.HandleFunc("/admin/groups", auth.Role(models.RoleAnalyst, models.RoleEditor, func(w http.ResponseWriter, r *http.Request) {
 		switch r.Method {
 		case http.MethodGet:
 			groups, err := groupRepo.List(r.Context())
@@ -750,6 +1077,7 @@ func main() {
 				http.Error(w, err.Error(), http.StatusInternalServerError)
 				return
 			}
+			auditor.Record(r, "group.create", "group", input.ID, nil, &input)
 
 			w.Header().Set("Content-Type", "application/json")
 			w.WriteHeader(http.StatusCreated)
@@ -760,7 +1088,7 @@ func main() {
 		}
 	}))
 
-	mux.HandleFunc("/admin/groups/", handler.WithAgentAuth(func(w http.ResponseWriter, r *http.Request) {
+	mux.HandleFunc("/admin/groups/", auth.Role(models.RoleAnalyst, models.RoleEditor, func(w http.ResponseWriter, r *http.Request) {
 		// Extract group ID from path: /admin/groups/{id}
 		path := strings.TrimPrefix(r.URL.Path, "/admin/groups/")
 		groupID := strings.TrimSpace(path)
@@ -791,20 +1119,33 @@ func main() {
 				return
 			}
 
+			before, err := groupRepo.Get(r.Context(), groupID)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+
 			input.ID = groupID
 			if err := groupRepo.Update(r.Context(), &input); err != nil {
 				http.Error(w, err.Error(), http.StatusInternalServerError)
 				return
 			}
+			auditor.Record(r, "group.update", "group", groupID, before, &input)
 
 			w.Header().Set("Content-Type", "application/json")
 			json.NewEncoder(w).Encode(input)
 
 		case http.MethodDelete:
+			before, err := groupRepo.Get(r.Context(), groupID)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
 			if err := groupRepo.Delete(r.Context(), groupID); err != nil {
 				http.Error(w, err.Error(), http.StatusInternalServerError)
 				return
 			}
+			auditor.Record(r, "group.delete", "group", groupID, before, nil)
 			w.WriteHeader(http.StatusNoContent)
 
 		default:
@@ -813,7 +1154,7 @@ func main() {
 	}))
 
 	// Webhook endpoints
-	mux.HandleFunc("/admin/webhooks", handler.WithAgentAuth(func(w http.ResponseWriter, r *http.Request) {
+	mux.HandleFunc("/admin/webhooks", auth.Require(models.RoleEditor, func(w http.ResponseWriter, r *http.Request) {
 		switch r.Method {
 		case http.MethodGet:
 			webhooks, err := webhookRepo.GetAll(r.Context())
@@ -844,6 +1185,10 @@ func main() {
 				http.Error(w, "secret is required", http.StatusBadRequest)
 				return
 			}
+			if err := validateWebhook(&webhook); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
 
 			// Set defaults
 			webhook.ID = uuid.NewString()
@@ -857,6 +1202,8 @@ func main() {
 				http.Error(w, err.Error(), http.StatusInternalServerError)
 				return
 			}
+			auditor.Record(r, "webhook.create", "webhook", webhook.ID, nil, &webhook)
+			refreshWebhooks(r.Context())
 
 			w.Header().Set("Content-Type", "application/json")
 			w.WriteHeader(http.StatusCreated)
@@ -867,7 +1214,7 @@ func main() {
 		}
 	}))
 
-	mux.HandleFunc("/admin/webhooks/", handler.WithAgentAuth(func(w http.ResponseWriter, r *http.Request) {
+	mux.HandleFunc("/admin/webhooks/", auth.Require(models.RoleEditor, func(w http.ResponseWriter, r *http.Request) {
 		// Extract webhook ID from path: /admin/webhooks/{id} or /admin/webhooks/{id}/test
 		path := strings.TrimPrefix(r.URL.Path, "/admin/webhooks/")
 		parts := strings.Split(path, "/")
@@ -878,6 +1225,80 @@ func main() {
 			return
 		}
 
+		// Log delivery, dead letter dan replay
+		if len(parts) > 1 && (parts[1] == "deliveries" || parts[1] == "dead-letters") {
+			deliveryHandler.Handle(w, r)
+			return
+		}
+
+		// Rotasi secret: secret baru jadi primary, secret lama tetap ikut ditandatangani selama overlap
+		if len(parts) > 2 && parts[1] == "secret" && parts[2] == "roll" {
+			if r.Method != http.MethodPost {
+				w.WriteHeader(http.StatusMethodNotAllowed)
+				return
+			}
+
+			var input struct {
+				Secret  string `json:"secret"`  // kosong = dibuat otomatis
+				Overlap string `json:"overlap"` // default 24h; "0s" = secret lama langsung tidak dipakai
+			}
+			if r.ContentLength != 0 {
+				if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+					http.Error(w, "invalid json", http.StatusBadRequest)
+					return
+				}
+			}
+			overlap := 24 * time.Hour
+			if input.Overlap != "" {
+				d, err := time.ParseDuration(input.Overlap)
+				if err != nil || d < 0 {
+					http.Error(w, "invalid overlap duration", http.StatusBadRequest)
+					return
+				}
+				overlap = d
+			}
+			secret := strings.TrimSpace(input.Secret)
+			if secret == "" {
+				generated, err := util.RandomToken(32)
+				if err != nil {
+					http.Error(w, "failed to generate secret", http.StatusInternalServerError)
+					return
+				}
+				secret = generated
+			}
+
+			webhook, err := webhookRepo.GetByID(r.Context(), webhookID)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			before := *webhook
+			if secret == webhook.Secret {
+				http.Error(w, "new secret must differ from the current secret", http.StatusBadRequest)
+				return
+			}
+
+			now := time.Now()
+			webhook.SecondarySecret, webhook.SecondarySecretExpiresAt = "", nil
+			if overlap > 0 {
+				expiresAt := now.Add(overlap)
+				webhook.SecondarySecret = webhook.Secret
+				webhook.SecondarySecretExpiresAt = &expiresAt
+			}
+			webhook.Secret = secret
+			webhook.UpdatedAt = now
+			if err := webhookRepo.Update(r.Context(), webhook); err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			auditor.Record(r, "webhook.secret.roll", "webhook", webhookID, &before, webhook)
+			refreshWebhooks(r.Context())
+
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(webhook)
+			return
+		}
+
 		// Check if this is a test request
 		if len(parts) > 1 && parts[1] == "test" && r.Method == http.MethodPost {
 			// Test webhook delivery
@@ -935,21 +1356,44 @@ func main() {
 				return
 			}
 
+			if err := validateWebhook(&webhook); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+
+			before, err := webhookRepo.GetByID(r.Context(), webhookID)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+
 			webhook.ID = webhookID
 			webhook.UpdatedAt = time.Now()
+			// Secret lama hanya diatur lewat /secret/roll
+			webhook.SecondarySecret = before.SecondarySecret
+			webhook.SecondarySecretExpiresAt = before.SecondarySecretExpiresAt
 			if err := webhookRepo.Update(r.Context(), &webhook); err != nil {
 				http.Error(w, err.Error(), http.StatusInternalServerError)
 				return
 			}
+			auditor.Record(r, "webhook.update", "webhook", webhookID, before, &webhook)
+			refreshWebhooks(r.Context())
 
 			w.Header().Set("Content-Type", "application/json")
 			json.NewEncoder(w).Encode(webhook)
 
 		case http.MethodDelete:
+			before, err := webhookRepo.GetByID(r.Context(), webhookID)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
 			if err := webhookRepo.Delete(r.Context(), webhookID); err != nil {
 				http.Error(w, err.Error(), http.StatusInternalServerError)
 				return
 			}
+			auditor.Record(r, "webhook.delete", "webhook", webhookID, before, nil)
+			refreshWebhooks(r.Context())
 			w.WriteHeader(http.StatusNoContent)
 
 		default:
@@ -957,7 +1401,7 @@ func main() {
 		}
 	}))
 
-	// Settings endpoints - Auth (change username/password)
+	// Settings endpoints - Auth (user yang login ganti username/password sendiri)
 	mux.HandleFunc("/admin/settings/auth", authHandler.WithAuth(func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != http.MethodPut {
 			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
@@ -974,11 +1418,27 @@ func main() {
 			return
 		}
 
+		input.Username = strings.ToLower(strings.TrimSpace(input.Username))
 		if input.Username == "" || input.Password == "" {
 			http.Error(w, "Username and password are required", http.StatusBadRequest)
 			return
 		}
 
+		// Copy supaya user di cache session tidak ikut berubah sebelum tersimpan
+		before := *handler.UserFromContext(r.Context())
+		user := before
+		if input.Username != user.Username {
+			existing, err := stores.Users.GetByUsername(r.Context(), input.Username)
+			if err != nil {
+				http.Error(w, "Failed to update credentials", http.StatusInternalServerError)
+				return
+			}
+			if existing != nil {
+				http.Error(w, "Username already exists", http.StatusConflict)
+				return
+			}
+		}
+
 		// Hash password dengan bcrypt
 		hashedPassword, err := bcrypt.GenerateFromPassword([]byte(input.Password), bcrypt.DefaultCost)
 		if err != nil {
@@ -986,16 +1446,21 @@ func main() {
 			return
 		}
 
-		// Get existing settings
-		settings := settingsRepo.GetOrDefault(r.Context())
-		settings.AdminUsername = input.Username
-		settings.AdminPassword = string(hashedPassword)
+		user.Username = input.Username
+		user.PasswordHash = string(hashedPassword)
+		user.UpdatedAt = time.Now().UTC()
 
 		// Save
-		if err := settingsRepo.Update(r.Context(), settings); err != nil {
+		if err := stores.Users.Update(r.Context(), &user); err != nil {
 			http.Error(w, "Failed to update credentials", http.StatusInternalServerError)
 			return
 		}
+		auditor.Record(r, "settings.auth", "user", before.Username, &before, &user)
+
+		// Password berubah: semua session user ini (termasuk yang sekarang) harus login ulang
+		if err := authHandler.RevokeUser(r.Context(), user.ID); err != nil {
+			log.Printf("warning: failed to revoke sessions of user %s: %v", user.ID, err)
+		}
 
 		w.Header().Set("Content-Type", "application/json")
 		json.NewEncoder(w).Encode(map[string]interface{}{
@@ -1004,12 +1469,79 @@ func main() {
 		})
 	}))
 
+	// Audit log: filter + export JSONL (owner only)
+	mux.HandleFunc("/admin/audit", auth.Require(models.RoleOwner, auditHandler.HandleList))
+
+	// User management (owner only)
+	mux.HandleFunc("/admin/users", auth.Require(models.RoleOwner, userHandler.HandleUsers))
+	mux.HandleFunc("/admin/users/", auth.Require(models.RoleOwner, userHandler.HandleUserByID))
+
+	// Settings endpoints - Alias generation (strategi per domain + kata blocked)
+	mux.HandleFunc("/admin/settings/aliases", auth.Require(models.RoleOwner, func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			aliases := settingsRepo.GetOrDefault(r.Context()).Aliases
+			if aliases == nil {
+				aliases = &models.AliasSettings{Default: models.AliasPolicy{Strategy: models.AliasStrategyRandom}}
+			}
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(aliases)
+
+		case http.MethodPut:
+			var input models.AliasSettings
+			if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+				http.Error(w, "invalid JSON", http.StatusBadRequest)
+				return
+			}
+
+			var err error
+			if input.Default, err = aliaspkg.NormalizePolicy(input.Default); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			domains := make(map[string]models.AliasPolicy, len(input.Domains))
+			for domain, policy := range input.Domains {
+				domain = strings.ToLower(strings.TrimSpace(domain))
+				if domain == "" {
+					continue
+				}
+				if domains[domain], err = aliaspkg.NormalizePolicy(policy); err != nil {
+					http.Error(w, domain+": "+err.Error(), http.StatusBadRequest)
+					return
+				}
+			}
+			input.Domains = domains
+			blocked := input.Blocked[:0]
+			for _, word := range input.Blocked {
+				if word = strings.ToLower(strings.TrimSpace(word)); word != "" {
+					blocked = append(blocked, word)
+				}
+			}
+			input.Blocked = blocked
+
+			settings := settingsRepo.GetOrDefault(r.Context())
+			before := settings.Aliases
+			settings.Aliases = &input
+			if err := settingsRepo.Update(r.Context(), settings); err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			auditor.Record(r, "settings.aliases", "settings", "aliases", before, &input)
+
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(input)
+
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	}))
+
 	// Settings endpoints - Rate Limit Configuration
-	mux.HandleFunc("/admin/settings/rate-limit", handler.WithAgentAuth(func(w http.ResponseWriter, r *http.Request) {
+	mux.HandleFunc("/admin/settings/rate-limit", auth.Require(models.RoleOwner, func(w http.ResponseWriter, r *http.Request) {
 		switch r.Method {
 		case http.MethodGet:
 			// Return rate limit config
-			config := settingsRepo.GetRateLimitConfig()
+			config := repository.GetRateLimitConfig(settingsRepo)
 			w.Header().Set("Content-Type", "application/json")
 			json.NewEncoder(w).Encode(config)
 
@@ -1035,14 +1567,33 @@ func main() {
 				window = int(val)
 			}
 
+			var enabled *bool
+			if val, ok := input["enabled"].(bool); ok {
+				enabled = &val
+			}
+
+			action, _ := input["action"].(string)
+			if !ratelimit.ValidAction(action) {
+				http.Error(w, "action must be reject, fallback or challenge", http.StatusBadRequest)
+				return
+			}
+
+			algorithm, _ := input["algorithm"].(string)
+			if !ratelimit.ValidAlgorithm(algorithm) {
+				http.Error(w, "algorithm must be sliding_log, sliding_window or gcra", http.StatusBadRequest)
+				return
+			}
+
 			// Update settings
-			if err := settingsRepo.UpdateRateLimitConfig(ipLimit, linkLimit, window); err != nil {
+			before := repository.GetRateLimitConfig(settingsRepo)
+			if err := repository.UpdateRateLimitConfig(settingsRepo, ipLimit, linkLimit, window, enabled, action, algorithm); err != nil {
 				http.Error(w, err.Error(), http.StatusInternalServerError)
 				return
 			}
 
 			// Return updated config
-			config := settingsRepo.GetRateLimitConfig()
+			config := repository.GetRateLimitConfig(settingsRepo)
+			auditor.Record(r, "settings.rate_limit", "settings", "rate-limit", before, config)
 			w.Header().Set("Content-Type", "application/json")
 			json.NewEncoder(w).Encode(config)
 
@@ -1052,16 +1603,10 @@ func main() {
 	}))
 
 	// Rate Limit Analytics endpoints
-	mux.HandleFunc("/admin/rate-limits", handler.WithAgentAuth(func(w http.ResponseWriter, r *http.Request) {
+	mux.HandleFunc("/admin/rate-limits", auth.Role(models.RoleEditor, models.RoleOwner, func(w http.ResponseWriter, r *http.Request) {
 		switch r.Method {
 		case http.MethodGet:
 			// Get all active rate limits
-			if rateLimiter == nil {
-				w.Header().Set("Content-Type", "application/json")
-				json.NewEncoder(w).Encode([]ratelimit.RateLimitInfo{})
-				return
-			}
-
 			limits, err := rateLimiter.GetAllRateLimits(r.Context())
 			if err != nil {
 				http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -1073,11 +1618,6 @@ func main() {
 
 		case http.MethodDelete:
 			// Reset rate limit for specific key
-			if rateLimiter == nil {
-				w.WriteHeader(http.StatusNoContent)
-				return
-			}
-
 			var input struct {
 				Key string `json:"key"`
 			}
@@ -1095,6 +1635,7 @@ func main() {
 				http.Error(w, err.Error(), http.StatusInternalServerError)
 				return
 			}
+			auditor.Record(r, "rate_limit.reset", "rate_limit", input.Key, nil, nil)
 
 			w.WriteHeader(http.StatusNoContent)
 
@@ -1106,38 +1647,60 @@ func main() {
 	addr := config.GetEnv("NEXUS_HTTP_ADDR", ":8080")
 	log.Printf("Nexus API listening on %s\n", addr)
 
-	if err := http.ListenAndServe(addr, mux); err != nil {
-		log.Fatalf("server error: %v", err)
+	// Node liveness: flip IsOnline, catat transisi, kirim node.offline / node.online
+	grace, err := time.ParseDuration(config.GetEnv("NEXUS_NODE_OFFLINE_GRACE", "90s"))
+	if err != nil {
+		grace = nodemonitor.DefaultGracePeriod
 	}
-}
-
-// triggerWebhooks triggers all active webhooks subscribed to an event
-func triggerWebhooks(ctx context.Context, webhookRepo *repository.WebhookRepository, webhookSender *webhook.Sender, event string, data map[string]interface{}) {
-	webhooks, err := webhookRepo.GetByEvent(ctx, event)
+	monitorInterval, err := time.ParseDuration(config.GetEnv("NEXUS_NODE_MONITOR_INTERVAL", "30s"))
 	if err != nil {
-		log.Printf("Failed to get webhooks for event %s: %v", event, err)
-		return
+		monitorInterval = nodemonitor.DefaultInterval
 	}
+	monitorCtx, stopMonitor := context.WithCancel(context.Background())
+	defer stopMonitor()
+	nodeMonitor := nodemonitor.New(nodeRepo, nodeEventRepo, func(ctx context.Context, event string, data map[string]interface{}) {
+		if err := webhookDispatcher.Publish(ctx, event, data); err != nil {
+			log.Printf("Failed to enqueue webhooks for event %s: %v", event, err)
+		}
+	}, grace, monitorInterval)
+	go nodeMonitor.Run(monitorCtx)
 
-	if len(webhooks) == 0 {
-		return // No webhooks subscribed to this event
-	}
+	dispatcherCtx, stopDispatcher := context.WithCancel(context.Background())
+	dispatcherDone := make(chan struct{})
+	go func() {
+		webhookDispatcher.Run(dispatcherCtx)
+		close(dispatcherDone)
+	}()
+
+	srv := &http.Server{Addr: addr, Handler: metrics.Middleware(mux)}
+
+	go func() {
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Fatalf("server error: %v", err)
+		}
+	}()
+
+	// Graceful shutdown: selesaikan request yang jalan, lalu flush click pipeline
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
+	<-stop
 
-	payload := &models.WebhookPayload{
-		Event:     event,
-		Timestamp: time.Now(),
-		Data:      data,
+	log.Println("Nexus API shutting down...")
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	if err := srv.Shutdown(ctx); err != nil {
+		log.Printf("server shutdown error: %v", err)
+	}
+	if err := clickPipeline.Close(ctx); err != nil {
+		log.Printf("click pipeline close error: %v", err)
 	}
 
-	// Send webhooks in background
-	for _, wh := range webhooks {
-		go func(w models.Webhook) {
-			result, err := webhookSender.SendWebhook(ctx, &w, payload)
-			if err != nil {
-				log.Printf("Webhook error: event=%s url=%s error=%v", event, w.URL, err)
-			} else if !result.Success {
-				log.Printf("Webhook failed: event=%s url=%s status=%d", event, w.URL, result.StatusCode)
-			}
-		}(wh)
+	// Sisa outbox dikirim setelah start berikutnya
+	stopDispatcher()
+	select {
+	case <-dispatcherDone:
+	case <-ctx.Done():
+		log.Printf("webhook dispatcher shutdown: %v", ctx.Err())
 	}
 }
@@ -143,7 +143,7 @@ build_binaries() {
     
     if [ "$INSTALL_TYPE" = "all" ] || [ "$INSTALL_TYPE" = "agent" ]; then
         echo "Building Agent..."
-        go build -o agent cmd/agent/main.go
+        go build -o agent ./cmd/agent
         chmod +x agent
     fi
     
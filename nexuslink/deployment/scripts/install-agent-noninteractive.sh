@@ -81,7 +81,7 @@ cd "$INSTALL_DIR/nexuslink"
 # Build Agent
 # ============================================
 echo "🔨 Building agent..."
-/usr/local/go/bin/go build -o agent cmd/agent/main.go
+/usr/local/go/bin/go build -o agent ./cmd/agent
 chmod +x agent
 
 # ============================================
@@ -224,7 +224,7 @@ echo -e "${YELLOW}════════════════════
 echo ""
 
 echo "🔨 Building agent..."
-/usr/local/go/bin/go build -o agent cmd/agent/main.go
+/usr/local/go/bin/go build -o agent ./cmd/agent
 chmod +x agent
 echo "✅ Agent binary built: $(pwd)/agent"
 
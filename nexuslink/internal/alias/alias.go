@@ -0,0 +1,159 @@
+// Package alias membuat dan memvalidasi alias short link.
+package alias
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/afuzapratama/nexuslink/internal/models"
+)
+
+const (
+	base62 = "0123456789abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ"
+
+	// Alphabet hashids untuk alias: lowercase supaya dua angka berbeda tidak pernah
+	// menghasilkan alias yang sama secara case-insensitive
+	sequentialAlphabet = "abcdefghijklmnopqrstuvwxyz0123456789"
+
+	consonants = "bdfgklmnprstvz"
+	vowels     = "aeiou"
+
+	MaxLength = 64
+
+	defaultRandomLength     = 7
+	defaultWordsLength      = 8
+	defaultSequentialLength = 4
+	maxGeneratedLength      = 32
+)
+
+// reserved bentrok dengan route API / agent / file umum
+var reserved = map[string]bool{
+	"admin": true, "api": true, "auth": true, "links": true, "bulk": true, "resolve": true,
+	"r": true, "health": true, "metrics": true, "sync": true, "nodes": true, "analytics": true,
+	"login": true, "logout": true, "static": true, "assets": true, "favicon.ico": true,
+	"robots.txt": true, ".well-known": true,
+}
+
+// Key adalah bentuk alias untuk cek keunikan (case-insensitive)
+func Key(alias string) string {
+	return strings.ToLower(alias)
+}
+
+// Validate mengecek alias custom: panjang, karakter, reserved dan blocked (sama persis)
+func Validate(alias string, blocked []string) error {
+	if alias == "" || len(alias) > MaxLength {
+		return fmt.Errorf("alias must be 1-%d characters", MaxLength)
+	}
+	for _, c := range alias {
+		if c <= ' ' || c == 0x7f || strings.ContainsRune("/?#%\\", c) {
+			return fmt.Errorf("alias contains invalid character %q", c)
+		}
+	}
+	if IsReserved(alias) {
+		return fmt.Errorf("alias %q is reserved", alias)
+	}
+	for _, w := range blocked {
+		if w != "" && strings.EqualFold(alias, w) {
+			return fmt.Errorf("alias %q is blocked", alias)
+		}
+	}
+	return nil
+}
+
+// IsReserved true kalau alias dipakai sistem
+func IsReserved(alias string) bool {
+	return reserved[Key(alias)]
+}
+
+// ContainsBlocked true kalau alias mengandung salah satu kata blocked (untuk alias hasil generate)
+func ContainsBlocked(alias string, blocked []string) bool {
+	key := Key(alias)
+	for _, w := range blocked {
+		if w = Key(strings.TrimSpace(w)); w != "" && strings.Contains(key, w) {
+			return true
+		}
+	}
+	return false
+}
+
+// NormalizePolicy mengisi default dan memvalidasi policy
+func NormalizePolicy(p models.AliasPolicy) (models.AliasPolicy, error) {
+	p.Strategy = strings.ToLower(strings.TrimSpace(p.Strategy))
+	switch p.Strategy {
+	case "", models.AliasStrategyRandom:
+		p.Strategy = models.AliasStrategyRandom
+		if p.Length == 0 {
+			p.Length = defaultRandomLength
+		}
+	case models.AliasStrategyWords:
+		if p.Length == 0 {
+			p.Length = defaultWordsLength
+		}
+	case models.AliasStrategySequential:
+		if p.Length == 0 {
+			p.Length = defaultSequentialLength
+		}
+	default:
+		return p, fmt.Errorf("unknown alias strategy %q (use random, words or sequential)", p.Strategy)
+	}
+	if p.Length < 3 || p.Length > maxGeneratedLength {
+		return p, fmt.Errorf("alias length must be between 3 and %d", maxGeneratedLength)
+	}
+	return p, nil
+}
+
+// Generate membuat satu kandidat alias. next dipanggil untuk strategi sequential (counter atomic).
+// grow menambah panjang (random/words) supaya retry setelah banyak collision makin jarang bentrok.
+func Generate(p models.AliasPolicy, grow int, next func() (int64, error)) (string, error) {
+	p, err := NormalizePolicy(p)
+	if err != nil {
+		return "", err
+	}
+
+	switch p.Strategy {
+	case models.AliasStrategyWords:
+		return Pronounceable(p.Length + grow)
+	case models.AliasStrategySequential:
+		n, err := next()
+		if err != nil {
+			return "", err
+		}
+		h, err := NewHashids(p.Salt, p.Length, sequentialAlphabet)
+		if err != nil {
+			return "", err
+		}
+		return h.Encode(n)
+	default:
+		return Random(p.Length + grow)
+	}
+}
+
+// Random returns string base62 acak (crypto/rand)
+func Random(n int) (string, error) {
+	return pick(n, func(int) string { return base62 })
+}
+
+// Pronounceable returns huruf konsonan-vokal bergantian, mis. "tomaribu"
+func Pronounceable(n int) (string, error) {
+	return pick(n, func(i int) string {
+		if i%2 == 0 {
+			return consonants
+		}
+		return vowels
+	})
+}
+
+func pick(n int, charset func(i int) string) (string, error) {
+	b := make([]byte, n)
+	for i := range b {
+		set := charset(i)
+		idx, err := rand.Int(rand.Reader, big.NewInt(int64(len(set))))
+		if err != nil {
+			return "", err
+		}
+		b[i] = set[idx.Int64()]
+	}
+	return string(b), nil
+}
@@ -0,0 +1,59 @@
+package alias
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/afuzapratama/nexuslink/internal/models"
+)
+
+func TestGenerate(t *testing.T) {
+	s, err := Generate(models.AliasPolicy{Strategy: models.AliasStrategyRandom, Length: 9}, 0, nil)
+	if err != nil || len(s) != 9 {
+		t.Errorf("random: %q %v", s, err)
+	}
+
+	s, err = Generate(models.AliasPolicy{Strategy: models.AliasStrategyWords, Length: 6}, 2, nil)
+	if err != nil || len(s) != 8 || !strings.ContainsRune(vowels, rune(s[1])) {
+		t.Errorf("words: %q %v", s, err)
+	}
+
+	// Sequential: angka berbeda → alias berbeda (case-insensitive), panjang minimum terpenuhi
+	seq := int64(0)
+	next := func() (int64, error) { seq++; return seq, nil }
+	seen := make(map[string]bool)
+	for i := 0; i < 500; i++ {
+		s, err := Generate(models.AliasPolicy{Strategy: models.AliasStrategySequential, Length: 5, Salt: "brand-a"}, 0, next)
+		if err != nil {
+			t.Fatalf("sequential: %v", err)
+		}
+		if len(s) < 5 || s != strings.ToLower(s) || seen[s] {
+			t.Fatalf("sequential #%d: bad or duplicate alias %q", seq, s)
+		}
+		seen[s] = true
+	}
+
+	if _, err := Generate(models.AliasPolicy{Strategy: "emoji"}, 0, nil); err == nil {
+		t.Error("expected unknown strategy error")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	blocked := []string{"casino"}
+	for alias, ok := range map[string]bool{
+		"promo-2026": true,
+		"Admin":      false, // reserved, case-insensitive
+		"CASINO":     false,
+		"a/b":        false,
+		"has space":  false,
+		"":           false,
+	} {
+		if err := Validate(alias, blocked); (err == nil) != ok {
+			t.Errorf("Validate(%q) = %v, want ok=%v", alias, err, ok)
+		}
+	}
+
+	if !ContainsBlocked("xCasinOx", blocked) || ContainsBlocked("promo", blocked) {
+		t.Error("ContainsBlocked mismatch")
+	}
+}
@@ -0,0 +1,192 @@
+package alias
+
+import (
+	"errors"
+	"math"
+)
+
+// Implementasi hashids (https://hashids.org) — kompatibel dengan library resmi,
+// jadi alias sequential bisa di-decode ulang di tool lain dengan salt yang sama.
+
+const (
+	hashidsSeps       = "cfhistuCFHISTU"
+	hashidsMinAlpha   = 16
+	hashidsSepDiv     = 3.5
+	hashidsGuardDiv   = 12.0
+	hashidsDefaultABC = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ1234567890"
+)
+
+// Hashids meng-encode angka non-negatif jadi string pendek yang tidak berurutan
+type Hashids struct {
+	salt      []rune
+	minLength int
+	alphabet  []rune
+	seps      []rune
+	guards    []rune
+}
+
+// NewHashids membuat encoder. alphabet kosong = alphabet default hashids.
+func NewHashids(salt string, minLength int, alphabet string) (*Hashids, error) {
+	if alphabet == "" {
+		alphabet = hashidsDefaultABC
+	}
+
+	// Alphabet unik, urutan dipertahankan
+	var abc []rune
+	seen := make(map[rune]bool)
+	for _, c := range alphabet {
+		if c == ' ' {
+			return nil, errors.New("hashids: alphabet must not contain spaces")
+		}
+		if !seen[c] {
+			seen[c] = true
+			abc = append(abc, c)
+		}
+	}
+	if len(abc) < hashidsMinAlpha {
+		return nil, errors.New("hashids: alphabet must contain at least 16 unique characters")
+	}
+
+	// Separator = karakter seps yang ada di alphabet; dikeluarkan dari alphabet
+	var seps []rune
+	for _, c := range hashidsSeps {
+		if seen[c] {
+			seps = append(seps, c)
+		}
+	}
+	sepSet := make(map[rune]bool, len(seps))
+	for _, c := range seps {
+		sepSet[c] = true
+	}
+	filtered := abc[:0:0]
+	for _, c := range abc {
+		if !sepSet[c] {
+			filtered = append(filtered, c)
+		}
+	}
+	abc = filtered
+
+	saltRunes := []rune(salt)
+	seps = consistentShuffle(seps, saltRunes)
+
+	if len(seps) == 0 || float64(len(abc))/float64(len(seps)) > hashidsSepDiv {
+		sepsLength := int(math.Ceil(float64(len(abc)) / hashidsSepDiv))
+		if sepsLength == 1 {
+			sepsLength++
+		}
+		if sepsLength > len(seps) {
+			diff := sepsLength - len(seps)
+			seps = append(seps, abc[:diff]...)
+			abc = abc[diff:]
+		} else {
+			seps = seps[:sepsLength]
+		}
+	}
+
+	abc = consistentShuffle(abc, saltRunes)
+
+	guardCount := int(math.Ceil(float64(len(abc)) / hashidsGuardDiv))
+	var guards []rune
+	if len(abc) < 3 {
+		guards = append(guards, seps[:guardCount]...)
+		seps = seps[guardCount:]
+	} else {
+		guards = append(guards, abc[:guardCount]...)
+		abc = abc[guardCount:]
+	}
+
+	return &Hashids{
+		salt:      saltRunes,
+		minLength: minLength,
+		alphabet:  abc,
+		seps:      seps,
+		guards:    guards,
+	}, nil
+}
+
+// Encode meng-encode satu atau lebih angka non-negatif
+func (h *Hashids) Encode(numbers ...int64) (string, error) {
+	if len(numbers) == 0 {
+		return "", errors.New("hashids: no numbers to encode")
+	}
+
+	var numbersID int64
+	for i, n := range numbers {
+		if n < 0 {
+			return "", errors.New("hashids: negative numbers are not supported")
+		}
+		numbersID += n % int64(i+100)
+	}
+
+	alphabet := append([]rune(nil), h.alphabet...)
+	lottery := alphabet[numbersID%int64(len(alphabet))]
+	ret := []rune{lottery}
+
+	for i, n := range numbers {
+		buffer := append([]rune{lottery}, h.salt...)
+		buffer = append(buffer, alphabet...)
+		alphabet = consistentShuffle(alphabet, buffer[:len(alphabet)])
+		last := toAlphabet(n, alphabet)
+		ret = append(ret, last...)
+
+		if i+1 < len(numbers) {
+			n %= int64(last[0]) + int64(i)
+			ret = append(ret, h.seps[n%int64(len(h.seps))])
+		}
+	}
+
+	if len(ret) < h.minLength {
+		guardIndex := (numbersID + int64(ret[0])) % int64(len(h.guards))
+		ret = append([]rune{h.guards[guardIndex]}, ret...)
+
+		if len(ret) < h.minLength {
+			guardIndex = (numbersID + int64(ret[2])) % int64(len(h.guards))
+			ret = append(ret, h.guards[guardIndex])
+		}
+	}
+
+	half := len(alphabet) / 2
+	for len(ret) < h.minLength {
+		alphabet = consistentShuffle(alphabet, append([]rune(nil), alphabet...))
+		next := append([]rune(nil), alphabet[half:]...)
+		next = append(next, ret...)
+		next = append(next, alphabet[:half]...)
+		ret = next
+
+		if excess := len(ret) - h.minLength; excess > 0 {
+			start := excess / 2
+			ret = ret[start : start+h.minLength]
+		}
+	}
+
+	return string(ret), nil
+}
+
+func consistentShuffle(alphabet, salt []rune) []rune {
+	out := append([]rune(nil), alphabet...)
+	if len(salt) == 0 {
+		return out
+	}
+
+	sum := 0
+	for i, v := len(out)-1, 0; i > 0; i, v = i-1, v+1 {
+		v %= len(salt)
+		integer := int(salt[v])
+		sum += integer
+		j := (integer + v + sum) % i
+		out[i], out[j] = out[j], out[i]
+	}
+	return out
+}
+
+func toAlphabet(n int64, alphabet []rune) []rune {
+	var id []rune
+	size := int64(len(alphabet))
+	for {
+		id = append([]rune{alphabet[n%size]}, id...)
+		n /= size
+		if n == 0 {
+			return id
+		}
+	}
+}
@@ -0,0 +1,32 @@
+package alias
+
+import "testing"
+
+// Vector dari test suite hashids.js supaya output kompatibel
+func TestHashidsEncode(t *testing.T) {
+	cases := []struct {
+		salt      string
+		minLength int
+		numbers   []int64
+		want      string
+	}{
+		{"this is my salt", 0, []int64{12345}, "NkK9"},
+		{"this is my salt", 0, []int64{683, 94108, 123, 5}, "aBMswoO2UB3Sj"},
+		{"this is my salt", 8, []int64{1}, "gB0NV05e"},
+		{"", 0, []int64{1, 2, 3}, "o2fXhV"},
+	}
+
+	for _, c := range cases {
+		h, err := NewHashids(c.salt, c.minLength, "")
+		if err != nil {
+			t.Fatalf("NewHashids: %v", err)
+		}
+		got, err := h.Encode(c.numbers...)
+		if err != nil {
+			t.Fatalf("Encode(%v): %v", c.numbers, err)
+		}
+		if got != c.want {
+			t.Errorf("Encode(%v) salt=%q min=%d = %q, want %q", c.numbers, c.salt, c.minLength, got, c.want)
+		}
+	}
+}
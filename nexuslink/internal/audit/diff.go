@@ -0,0 +1,86 @@
+// Package audit menghitung diff before/after untuk audit log perubahan admin.
+package audit
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/afuzapratama/nexuslink/internal/models"
+)
+
+// Redacted menggantikan nilai field sensitif (password, secret, API key, token)
+const Redacted = "[redacted]"
+
+// ignoredFields berubah di setiap update, tidak informatif di audit
+var ignoredFields = map[string]bool{
+	"updatedAt": true,
+	"version":   true,
+}
+
+// Diff membandingkan field top-level before dan after (lewat bentuk JSON-nya).
+// before nil = create, after nil = delete. Hasil diurutkan per nama field.
+func Diff(before, after interface{}) []models.AuditChange {
+	b, a := toMap(before), toMap(after)
+
+	keys := make([]string, 0, len(b)+len(a))
+	seen := make(map[string]bool, len(b)+len(a))
+	for _, m := range []map[string]interface{}{b, a} {
+		for k := range m {
+			if !seen[k] && !ignoredFields[k] {
+				seen[k] = true
+				keys = append(keys, k)
+			}
+		}
+	}
+	sort.Strings(keys)
+
+	var changes []models.AuditChange
+	for _, k := range keys {
+		bv, av := b[k], a[k]
+		if reflect.DeepEqual(bv, av) {
+			continue
+		}
+		if sensitive(k) {
+			bv, av = redact(bv), redact(av)
+		}
+		changes = append(changes, models.AuditChange{Field: k, Before: bv, After: av})
+	}
+	return changes
+}
+
+func toMap(v interface{}) map[string]interface{} {
+	if v == nil || (reflect.ValueOf(v).Kind() == reflect.Ptr && reflect.ValueOf(v).IsNil()) {
+		return nil
+	}
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal(raw, &m); err != nil {
+		// Bukan object (mis. slice / string): simpan sebagai satu field
+		var val interface{}
+		json.Unmarshal(raw, &val)
+		return map[string]interface{}{"value": val}
+	}
+	return m
+}
+
+func sensitive(field string) bool {
+	f := strings.ToLower(field)
+	for _, s := range []string{"password", "secret", "apikey", "token", "keyhash"} {
+		if strings.Contains(f, s) {
+			return true
+		}
+	}
+	return false
+}
+
+func redact(v interface{}) interface{} {
+	if v == nil || v == "" {
+		return v
+	}
+	return Redacted
+}
@@ -0,0 +1,46 @@
+package audit
+
+import (
+	"testing"
+
+	"github.com/afuzapratama/nexuslink/internal/models"
+)
+
+func TestDiff(t *testing.T) {
+	before := &models.Link{ID: "1", Alias: "promo", TargetURL: "https://a.example", Version: 1}
+	after := &models.Link{ID: "1", Alias: "promo", TargetURL: "https://b.example", Version: 2, BlockBots: true}
+
+	changes := Diff(before, after)
+	if len(changes) != 2 {
+		t.Fatalf("expected 2 changes, got %+v", changes)
+	}
+	if changes[0].Field != "blockBots" || changes[1].Field != "targetUrl" {
+		t.Errorf("unexpected fields: %+v", changes)
+	}
+	if changes[1].Before != "https://a.example" || changes[1].After != "https://b.example" {
+		t.Errorf("unexpected target change: %+v", changes[1])
+	}
+}
+
+func TestDiffCreateDeleteAndRedact(t *testing.T) {
+	wh := &models.Webhook{ID: "w1", URL: "https://hook.example", Secret: "s3cret"}
+
+	created := Diff(nil, wh)
+	var secret *models.AuditChange
+	for i := range created {
+		if created[i].Before != nil {
+			t.Errorf("create should have no before values: %+v", created[i])
+		}
+		if created[i].Field == "secret" {
+			secret = &created[i]
+		}
+	}
+	if secret == nil || secret.After != Redacted {
+		t.Errorf("expected redacted secret, got %+v", secret)
+	}
+
+	var nilLink *models.Link
+	if got := Diff(wh, nilLink); len(got) == 0 || got[0].After != nil {
+		t.Errorf("expected delete diff with only before values, got %+v", got)
+	}
+}
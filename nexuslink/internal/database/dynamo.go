@@ -3,8 +3,10 @@ package database
 import (
 	"context"
 	"errors"
+	"fmt"
 	"log"
 	"sync"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
@@ -29,8 +31,37 @@ const (
 	SettingsTableName    = "NexusSettings"
 	LinkGroupsTableName  = "NexusLinkGroups"
 	WebhooksTableName    = "NexusWebhooks"
+
+	LinkTombstonesTableName  = "NexusLinkTombstones"
+	ClickRollupsTableName    = "NexusClickRollups"
+	NodeDomainsTableName     = "NexusNodeDomains"
+	NodeEventsTableName      = "NexusNodeEvents"
+	NodeCredentialsTableName = "NexusNodeCredentials"
+	UsersTableName           = "NexusUsers"
+	SessionsTableName        = "NexusSessions"
+	AuditLogTableName        = "NexusAuditLog"
+	LinkRevisionsTableName   = "NexusLinkRevisions"
+	LinkAliasesTableName     = "NexusLinkAliases"
+	ClickDedupTableName      = "NexusClickDedup"
+
+	WebhookDeliveriesTableName  = "NexusWebhookDeliveries"
+	WebhookDeadLettersTableName = "NexusWebhookDeadLetters"
+	WebhookOutboxTableName      = "NexusWebhookOutbox"
+)
+
+// Secondary indexes
+const (
+	LinksAliasLowerIndex   = "aliasLower-index"      // NexusLinks: alias lowercase, lookup case-insensitive
+	ClickEventsAliasIndex  = "alias-createdAt-index" // NexusClickEvents: alias + createdAt
+	LinksFeedIndex         = "feed-createdAt-index"  // NexusLinks: feed (konstan) + createdAt, untuk list semua link
+	ClickEventsFeedIndex   = "feed-createdAt-index"  // NexusClickEvents: feed (shard) + createdAt, untuk list semua click
+	LinksVersionIndex      = "feed-version-index"    // NexusLinks: feed (konstan) + version, delta feed agent
+	TombstonesVersionIndex = "feed-version-index"    // NexusLinkTombstones: feed (konstan) + version, delta feed agent
 )
 
+// indexPollInterval: interval cek status GSI yang sedang dibuat / backfill
+const indexPollInterval = 10 * time.Second
+
 // Client mengembalikan singleton DynamoDB client
 func Client() *dynamodb.Client {
 	once.Do(func() {
@@ -96,6 +127,9 @@ func Client() *dynamodb.Client {
 			}
 		}
 
+		// Setiap call Dynamo dicatat ke /metrics (operation, status, latency)
+		cfg.APIOptions = append(cfg.APIOptions, addMetricsMiddleware)
+
 		client = dynamodb.NewFromConfig(cfg)
 	})
 
@@ -402,6 +436,622 @@ func EnsureTables(ctx context.Context) error {
 		log.Println("NexusLink: table already exists:", WebhooksTableName)
 	}
 
+	// ---- Tabel LinkTombstones (delta feed agent) ----
+	log.Println("NexusLink: checking table", LinkTombstonesTableName)
+	_, err = c.DescribeTable(ctx, &dynamodb.DescribeTableInput{
+		TableName: aws.String(LinkTombstonesTableName),
+	})
+	if err != nil {
+		var rnfe *types.ResourceNotFoundException
+		if !errors.As(err, &rnfe) {
+			return err
+		}
+
+		log.Println("NexusLink: table not found, creating...", LinkTombstonesTableName)
+
+		_, err = c.CreateTable(ctx, &dynamodb.CreateTableInput{
+			TableName: aws.String(LinkTombstonesTableName),
+			AttributeDefinitions: []types.AttributeDefinition{
+				{
+					AttributeName: aws.String("id"),
+					AttributeType: types.ScalarAttributeTypeS,
+				},
+			},
+			KeySchema: []types.KeySchemaElement{
+				{
+					AttributeName: aws.String("id"),
+					KeyType:       types.KeyTypeHash,
+				},
+			},
+			BillingMode: types.BillingModePayPerRequest,
+		})
+		if err != nil {
+			return err
+		}
+
+		// Tombstone otomatis dihapus DynamoDB setelah expiresAt
+		if err := enableTTL(ctx, c, LinkTombstonesTableName, "expiresAt"); err != nil {
+			log.Printf("NexusLink: warning: failed to enable TTL on %s: %v", LinkTombstonesTableName, err)
+		}
+		log.Println("NexusLink: table created:", LinkTombstonesTableName)
+	} else {
+		log.Println("NexusLink: table already exists:", LinkTombstonesTableName)
+	}
+
+	// ---- Tabel ClickDedup (ID click dari agent yang sudah diproses, retry /sync/clicks) ----
+	log.Println("NexusLink: checking table", ClickDedupTableName)
+	_, err = c.DescribeTable(ctx, &dynamodb.DescribeTableInput{
+		TableName: aws.String(ClickDedupTableName),
+	})
+	if err != nil {
+		var rnfe *types.ResourceNotFoundException
+		if !errors.As(err, &rnfe) {
+			return err
+		}
+
+		log.Println("NexusLink: table not found, creating...", ClickDedupTableName)
+
+		_, err = c.CreateTable(ctx, &dynamodb.CreateTableInput{
+			TableName: aws.String(ClickDedupTableName),
+			AttributeDefinitions: []types.AttributeDefinition{
+				{
+					AttributeName: aws.String("id"),
+					AttributeType: types.ScalarAttributeTypeS,
+				},
+			},
+			KeySchema: []types.KeySchemaElement{
+				{
+					AttributeName: aws.String("id"),
+					KeyType:       types.KeyTypeHash,
+				},
+			},
+			BillingMode: types.BillingModePayPerRequest,
+		})
+		if err != nil {
+			return err
+		}
+
+		if err := enableTTL(ctx, c, ClickDedupTableName, "expiresAt"); err != nil {
+			log.Printf("NexusLink: warning: failed to enable TTL on %s: %v", ClickDedupTableName, err)
+		}
+		log.Println("NexusLink: table created:", ClickDedupTableName)
+	} else {
+		log.Println("NexusLink: table already exists:", ClickDedupTableName)
+	}
+
+	// ---- Tabel ClickRollups (analytics time-series) ----
+	log.Println("NexusLink: checking table", ClickRollupsTableName)
+	_, err = c.DescribeTable(ctx, &dynamodb.DescribeTableInput{
+		TableName: aws.String(ClickRollupsTableName),
+	})
+	if err != nil {
+		var rnfe *types.ResourceNotFoundException
+		if !errors.As(err, &rnfe) {
+			return err
+		}
+
+		log.Println("NexusLink: table not found, creating...", ClickRollupsTableName)
+
+		_, err = c.CreateTable(ctx, &dynamodb.CreateTableInput{
+			TableName: aws.String(ClickRollupsTableName),
+			AttributeDefinitions: []types.AttributeDefinition{
+				{
+					AttributeName: aws.String("pk"),
+					AttributeType: types.ScalarAttributeTypeS,
+				},
+				{
+					AttributeName: aws.String("sk"),
+					AttributeType: types.ScalarAttributeTypeS,
+				},
+			},
+			KeySchema: []types.KeySchemaElement{
+				{
+					AttributeName: aws.String("pk"),
+					KeyType:       types.KeyTypeHash,
+				},
+				{
+					AttributeName: aws.String("sk"),
+					KeyType:       types.KeyTypeRange,
+				},
+			},
+			BillingMode: types.BillingModePayPerRequest,
+		})
+		if err != nil {
+			return err
+		}
+		log.Println("NexusLink: table created:", ClickRollupsTableName)
+	} else {
+		log.Println("NexusLink: table already exists:", ClickRollupsTableName)
+	}
+
+	// ---- Tabel NodeDomains (domain → nodeId) ----
+	// Node.Domains adalah list, DynamoDB GSI tidak bisa index elemen list,
+	// jadi lookup domain pakai tabel mapping yang di-maintain NodeRepository.
+	log.Println("NexusLink: checking table", NodeDomainsTableName)
+	_, err = c.DescribeTable(ctx, &dynamodb.DescribeTableInput{
+		TableName: aws.String(NodeDomainsTableName),
+	})
+	if err != nil {
+		var rnfe *types.ResourceNotFoundException
+		if !errors.As(err, &rnfe) {
+			return err
+		}
+
+		log.Println("NexusLink: table not found, creating...", NodeDomainsTableName)
+
+		_, err = c.CreateTable(ctx, &dynamodb.CreateTableInput{
+			TableName: aws.String(NodeDomainsTableName),
+			AttributeDefinitions: []types.AttributeDefinition{
+				{
+					AttributeName: aws.String("domain"),
+					AttributeType: types.ScalarAttributeTypeS,
+				},
+			},
+			KeySchema: []types.KeySchemaElement{
+				{
+					AttributeName: aws.String("domain"),
+					KeyType:       types.KeyTypeHash,
+				},
+			},
+			BillingMode: types.BillingModePayPerRequest,
+		})
+		if err != nil {
+			return err
+		}
+		// Backfill mapping jalan langsung setelah EnsureTables, tunggu tabel ACTIVE
+		waiter := dynamodb.NewTableExistsWaiter(c)
+		if err := waiter.Wait(ctx, &dynamodb.DescribeTableInput{TableName: aws.String(NodeDomainsTableName)}, 2*time.Minute); err != nil {
+			return err
+		}
+		log.Println("NexusLink: table created:", NodeDomainsTableName)
+	} else {
+		log.Println("NexusLink: table already exists:", NodeDomainsTableName)
+	}
+
+	// ---- Tabel NodeEvents (riwayat online/offline per node, TTL expiresAt) ----
+	log.Println("NexusLink: checking table", NodeEventsTableName)
+	_, err = c.DescribeTable(ctx, &dynamodb.DescribeTableInput{
+		TableName: aws.String(NodeEventsTableName),
+	})
+	if err != nil {
+		var rnfe *types.ResourceNotFoundException
+		if !errors.As(err, &rnfe) {
+			return err
+		}
+
+		log.Println("NexusLink: table not found, creating...", NodeEventsTableName)
+
+		_, err = c.CreateTable(ctx, &dynamodb.CreateTableInput{
+			TableName: aws.String(NodeEventsTableName),
+			AttributeDefinitions: []types.AttributeDefinition{
+				{
+					AttributeName: aws.String("nodeId"),
+					AttributeType: types.ScalarAttributeTypeS,
+				},
+				{
+					AttributeName: aws.String("ts"),
+					AttributeType: types.ScalarAttributeTypeN,
+				},
+			},
+			KeySchema: []types.KeySchemaElement{
+				{
+					AttributeName: aws.String("nodeId"),
+					KeyType:       types.KeyTypeHash,
+				},
+				{
+					AttributeName: aws.String("ts"),
+					KeyType:       types.KeyTypeRange,
+				},
+			},
+			BillingMode: types.BillingModePayPerRequest,
+		})
+		if err != nil {
+			return err
+		}
+		if err := enableTTL(ctx, c, NodeEventsTableName, "expiresAt"); err != nil {
+			log.Printf("NexusLink: warning: failed to enable TTL on %s: %v", NodeEventsTableName, err)
+		}
+		log.Println("NexusLink: table created:", NodeEventsTableName)
+	} else {
+		log.Println("NexusLink: table already exists:", NodeEventsTableName)
+	}
+
+	// ---- Tabel NodeCredentials (API key per node) ----
+	log.Println("NexusLink: checking table", NodeCredentialsTableName)
+	_, err = c.DescribeTable(ctx, &dynamodb.DescribeTableInput{
+		TableName: aws.String(NodeCredentialsTableName),
+	})
+	if err != nil {
+		var rnfe *types.ResourceNotFoundException
+		if !errors.As(err, &rnfe) {
+			return err
+		}
+
+		log.Println("NexusLink: table not found, creating...", NodeCredentialsTableName)
+
+		_, err = c.CreateTable(ctx, &dynamodb.CreateTableInput{
+			TableName: aws.String(NodeCredentialsTableName),
+			AttributeDefinitions: []types.AttributeDefinition{
+				{
+					AttributeName: aws.String("nodeId"),
+					AttributeType: types.ScalarAttributeTypeS,
+				},
+			},
+			KeySchema: []types.KeySchemaElement{
+				{
+					AttributeName: aws.String("nodeId"),
+					KeyType:       types.KeyTypeHash,
+				},
+			},
+			BillingMode: types.BillingModePayPerRequest,
+		})
+		if err != nil {
+			return err
+		}
+		log.Println("NexusLink: table created:", NodeCredentialsTableName)
+	} else {
+		log.Println("NexusLink: table already exists:", NodeCredentialsTableName)
+	}
+
+	// ---- Tabel Users (akun admin dashboard) ----
+	log.Println("NexusLink: checking table", UsersTableName)
+	_, err = c.DescribeTable(ctx, &dynamodb.DescribeTableInput{
+		TableName: aws.String(UsersTableName),
+	})
+	if err != nil {
+		var rnfe *types.ResourceNotFoundException
+		if !errors.As(err, &rnfe) {
+			return err
+		}
+
+		log.Println("NexusLink: table not found, creating...", UsersTableName)
+
+		_, err = c.CreateTable(ctx, &dynamodb.CreateTableInput{
+			TableName: aws.String(UsersTableName),
+			AttributeDefinitions: []types.AttributeDefinition{
+				{
+					AttributeName: aws.String("id"),
+					AttributeType: types.ScalarAttributeTypeS,
+				},
+			},
+			KeySchema: []types.KeySchemaElement{
+				{
+					AttributeName: aws.String("id"),
+					KeyType:       types.KeyTypeHash,
+				},
+			},
+			BillingMode: types.BillingModePayPerRequest,
+		})
+		if err != nil {
+			return err
+		}
+		log.Println("NexusLink: table created:", UsersTableName)
+	} else {
+		log.Println("NexusLink: table already exists:", UsersTableName)
+	}
+
+	// ---- Tabel Sessions (session login, TTL expiresAtUnix) ----
+	log.Println("NexusLink: checking table", SessionsTableName)
+	_, err = c.DescribeTable(ctx, &dynamodb.DescribeTableInput{
+		TableName: aws.String(SessionsTableName),
+	})
+	if err != nil {
+		var rnfe *types.ResourceNotFoundException
+		if !errors.As(err, &rnfe) {
+			return err
+		}
+
+		log.Println("NexusLink: table not found, creating...", SessionsTableName)
+
+		_, err = c.CreateTable(ctx, &dynamodb.CreateTableInput{
+			TableName: aws.String(SessionsTableName),
+			AttributeDefinitions: []types.AttributeDefinition{
+				{
+					AttributeName: aws.String("tokenHash"),
+					AttributeType: types.ScalarAttributeTypeS,
+				},
+			},
+			KeySchema: []types.KeySchemaElement{
+				{
+					AttributeName: aws.String("tokenHash"),
+					KeyType:       types.KeyTypeHash,
+				},
+			},
+			BillingMode: types.BillingModePayPerRequest,
+		})
+		if err != nil {
+			return err
+		}
+		if err := enableTTL(ctx, c, SessionsTableName, "expiresAtUnix"); err != nil {
+			log.Printf("NexusLink: warning: failed to enable TTL on %s: %v", SessionsTableName, err)
+		}
+		log.Println("NexusLink: table created:", SessionsTableName)
+	} else {
+		log.Println("NexusLink: table already exists:", SessionsTableName)
+	}
+
+	// ---- Tabel AuditLog (perubahan admin, append-only, partisi per hari) ----
+	log.Println("NexusLink: checking table", AuditLogTableName)
+	_, err = c.DescribeTable(ctx, &dynamodb.DescribeTableInput{
+		TableName: aws.String(AuditLogTableName),
+	})
+	if err != nil {
+		var rnfe *types.ResourceNotFoundException
+		if !errors.As(err, &rnfe) {
+			return err
+		}
+
+		log.Println("NexusLink: table not found, creating...", AuditLogTableName)
+
+		_, err = c.CreateTable(ctx, &dynamodb.CreateTableInput{
+			TableName: aws.String(AuditLogTableName),
+			AttributeDefinitions: []types.AttributeDefinition{
+				{
+					AttributeName: aws.String("day"),
+					AttributeType: types.ScalarAttributeTypeS,
+				},
+				{
+					AttributeName: aws.String("sk"),
+					AttributeType: types.ScalarAttributeTypeS,
+				},
+			},
+			KeySchema: []types.KeySchemaElement{
+				{
+					AttributeName: aws.String("day"),
+					KeyType:       types.KeyTypeHash,
+				},
+				{
+					AttributeName: aws.String("sk"),
+					KeyType:       types.KeyTypeRange,
+				},
+			},
+			BillingMode: types.BillingModePayPerRequest,
+		})
+		if err != nil {
+			return err
+		}
+		log.Println("NexusLink: table created:", AuditLogTableName)
+	} else {
+		log.Println("NexusLink: table already exists:", AuditLogTableName)
+	}
+
+	// ---- Tabel LinkRevisions (snapshot versi lama link) ----
+	log.Println("NexusLink: checking table", LinkRevisionsTableName)
+	_, err = c.DescribeTable(ctx, &dynamodb.DescribeTableInput{
+		TableName: aws.String(LinkRevisionsTableName),
+	})
+	if err != nil {
+		var rnfe *types.ResourceNotFoundException
+		if !errors.As(err, &rnfe) {
+			return err
+		}
+
+		log.Println("NexusLink: table not found, creating...", LinkRevisionsTableName)
+
+		_, err = c.CreateTable(ctx, &dynamodb.CreateTableInput{
+			TableName: aws.String(LinkRevisionsTableName),
+			AttributeDefinitions: []types.AttributeDefinition{
+				{
+					AttributeName: aws.String("linkId"),
+					AttributeType: types.ScalarAttributeTypeS,
+				},
+				{
+					AttributeName: aws.String("revision"),
+					AttributeType: types.ScalarAttributeTypeN,
+				},
+			},
+			KeySchema: []types.KeySchemaElement{
+				{
+					AttributeName: aws.String("linkId"),
+					KeyType:       types.KeyTypeHash,
+				},
+				{
+					AttributeName: aws.String("revision"),
+					KeyType:       types.KeyTypeRange,
+				},
+			},
+			BillingMode: types.BillingModePayPerRequest,
+		})
+		if err != nil {
+			return err
+		}
+		log.Println("NexusLink: table created:", LinkRevisionsTableName)
+	} else {
+		log.Println("NexusLink: table already exists:", LinkRevisionsTableName)
+	}
+
+	// ---- Tabel LinkAliases (reservasi alias unik + counter alias sequential) ----
+	log.Println("NexusLink: checking table", LinkAliasesTableName)
+	_, err = c.DescribeTable(ctx, &dynamodb.DescribeTableInput{
+		TableName: aws.String(LinkAliasesTableName),
+	})
+	if err != nil {
+		var rnfe *types.ResourceNotFoundException
+		if !errors.As(err, &rnfe) {
+			return err
+		}
+
+		log.Println("NexusLink: table not found, creating...", LinkAliasesTableName)
+
+		_, err = c.CreateTable(ctx, &dynamodb.CreateTableInput{
+			TableName: aws.String(LinkAliasesTableName),
+			AttributeDefinitions: []types.AttributeDefinition{
+				{
+					AttributeName: aws.String("aliasKey"),
+					AttributeType: types.ScalarAttributeTypeS,
+				},
+			},
+			KeySchema: []types.KeySchemaElement{
+				{
+					AttributeName: aws.String("aliasKey"),
+					KeyType:       types.KeyTypeHash,
+				},
+			},
+			BillingMode: types.BillingModePayPerRequest,
+		})
+		if err != nil {
+			return err
+		}
+		// Backfill reservasi alias jalan langsung setelah EnsureTables, tunggu tabel ACTIVE
+		waiter := dynamodb.NewTableExistsWaiter(c)
+		if err := waiter.Wait(ctx, &dynamodb.DescribeTableInput{TableName: aws.String(LinkAliasesTableName)}, 2*time.Minute); err != nil {
+			return err
+		}
+		log.Println("NexusLink: table created:", LinkAliasesTableName)
+	} else {
+		log.Println("NexusLink: table already exists:", LinkAliasesTableName)
+	}
+
+	// ---- Tabel WebhookDeliveries (log delivery per webhook, expire via TTL) ----
+	log.Println("NexusLink: checking table", WebhookDeliveriesTableName)
+	_, err = c.DescribeTable(ctx, &dynamodb.DescribeTableInput{
+		TableName: aws.String(WebhookDeliveriesTableName),
+	})
+	if err != nil {
+		var rnfe *types.ResourceNotFoundException
+		if !errors.As(err, &rnfe) {
+			return err
+		}
+
+		log.Println("NexusLink: table not found, creating...", WebhookDeliveriesTableName)
+
+		_, err = c.CreateTable(ctx, &dynamodb.CreateTableInput{
+			TableName: aws.String(WebhookDeliveriesTableName),
+			AttributeDefinitions: []types.AttributeDefinition{
+				{
+					AttributeName: aws.String("webhookId"),
+					AttributeType: types.ScalarAttributeTypeS,
+				},
+				{
+					AttributeName: aws.String("id"),
+					AttributeType: types.ScalarAttributeTypeS,
+				},
+			},
+			KeySchema: []types.KeySchemaElement{
+				{
+					AttributeName: aws.String("webhookId"),
+					KeyType:       types.KeyTypeHash,
+				},
+				{
+					AttributeName: aws.String("id"),
+					KeyType:       types.KeyTypeRange,
+				},
+			},
+			BillingMode: types.BillingModePayPerRequest,
+		})
+		if err != nil {
+			return err
+		}
+		if err := enableTTL(ctx, c, WebhookDeliveriesTableName, "expiresAtUnix"); err != nil {
+			log.Printf("NexusLink: warning: failed to enable TTL on %s: %v", WebhookDeliveriesTableName, err)
+		}
+		log.Println("NexusLink: table created:", WebhookDeliveriesTableName)
+	} else {
+		log.Println("NexusLink: table already exists:", WebhookDeliveriesTableName)
+	}
+
+	// ---- Tabel WebhookDeadLetters (delivery gagal final, sampai di-replay) ----
+	log.Println("NexusLink: checking table", WebhookDeadLettersTableName)
+	_, err = c.DescribeTable(ctx, &dynamodb.DescribeTableInput{
+		TableName: aws.String(WebhookDeadLettersTableName),
+	})
+	if err != nil {
+		var rnfe *types.ResourceNotFoundException
+		if !errors.As(err, &rnfe) {
+			return err
+		}
+
+		log.Println("NexusLink: table not found, creating...", WebhookDeadLettersTableName)
+
+		_, err = c.CreateTable(ctx, &dynamodb.CreateTableInput{
+			TableName: aws.String(WebhookDeadLettersTableName),
+			AttributeDefinitions: []types.AttributeDefinition{
+				{
+					AttributeName: aws.String("webhookId"),
+					AttributeType: types.ScalarAttributeTypeS,
+				},
+				{
+					AttributeName: aws.String("id"),
+					AttributeType: types.ScalarAttributeTypeS,
+				},
+			},
+			KeySchema: []types.KeySchemaElement{
+				{
+					AttributeName: aws.String("webhookId"),
+					KeyType:       types.KeyTypeHash,
+				},
+				{
+					AttributeName: aws.String("id"),
+					KeyType:       types.KeyTypeRange,
+				},
+			},
+			BillingMode: types.BillingModePayPerRequest,
+		})
+		if err != nil {
+			return err
+		}
+		log.Println("NexusLink: table created:", WebhookDeadLettersTableName)
+	} else {
+		log.Println("NexusLink: table already exists:", WebhookDeadLettersTableName)
+	}
+
+	// ---- Tabel WebhookOutbox (event webhook yang belum terkirim) ----
+	log.Println("NexusLink: checking table", WebhookOutboxTableName)
+	_, err = c.DescribeTable(ctx, &dynamodb.DescribeTableInput{
+		TableName: aws.String(WebhookOutboxTableName),
+	})
+	if err != nil {
+		var rnfe *types.ResourceNotFoundException
+		if !errors.As(err, &rnfe) {
+			return err
+		}
+
+		log.Println("NexusLink: table not found, creating...", WebhookOutboxTableName)
+
+		_, err = c.CreateTable(ctx, &dynamodb.CreateTableInput{
+			TableName: aws.String(WebhookOutboxTableName),
+			AttributeDefinitions: []types.AttributeDefinition{
+				{
+					AttributeName: aws.String("id"),
+					AttributeType: types.ScalarAttributeTypeS,
+				},
+			},
+			KeySchema: []types.KeySchemaElement{
+				{
+					AttributeName: aws.String("id"),
+					KeyType:       types.KeyTypeHash,
+				},
+			},
+			BillingMode: types.BillingModePayPerRequest,
+		})
+		if err != nil {
+			return err
+		}
+		log.Println("NexusLink: table created:", WebhookOutboxTableName)
+	} else {
+		log.Println("NexusLink: table already exists:", WebhookOutboxTableName)
+	}
+
+	// ---- Secondary indexes ----
+	if err := ensureIndex(ctx, c, LinksTableName, LinksAliasLowerIndex, "aliasLower", ""); err != nil {
+		return err
+	}
+	if err := ensureIndex(ctx, c, ClickEventsTableName, ClickEventsAliasIndex, "alias", "createdAt"); err != nil {
+		return err
+	}
+	if err := ensureIndex(ctx, c, LinksTableName, LinksFeedIndex, "feed", "createdAt"); err != nil {
+		return err
+	}
+	if err := ensureIndex(ctx, c, ClickEventsTableName, ClickEventsFeedIndex, "feed", "createdAt"); err != nil {
+		return err
+	}
+	if err := ensureTypedIndex(ctx, c, LinksTableName, LinksVersionIndex, "feed", "version", types.ScalarAttributeTypeN); err != nil {
+		return err
+	}
+	if err := ensureTypedIndex(ctx, c, LinkTombstonesTableName, TombstonesVersionIndex, "feed", "version", types.ScalarAttributeTypeN); err != nil {
+		return err
+	}
+
 	// ---- Tabel Link Variants (A/B Testing) ----
 	variantsTableName := "NexusLinkVariants"
 	log.Println("NexusLink: checking table", variantsTableName)
@@ -450,3 +1100,110 @@ func EnsureTables(ctx context.Context) error {
 
 	return nil
 }
+
+// ensureIndex membuat GSI (projection ALL) kalau belum ada di tabel, lalu menunggu sampai ACTIVE.
+// Query ke index yang masih CREATING (backfill item lama) gagal, jadi startup ditahan sampai index siap.
+// Tabel lama ikut ter-backfill otomatis oleh DynamoDB; sortKey kosong = hash-only index.
+func ensureIndex(ctx context.Context, c *dynamodb.Client, tableName, indexName, hashKey, sortKey string) error {
+	return ensureTypedIndex(ctx, c, tableName, indexName, hashKey, sortKey, types.ScalarAttributeTypeS)
+}
+
+// ensureTypedIndex seperti ensureIndex, dengan tipe sort key sendiri (mis. N untuk version)
+func ensureTypedIndex(ctx context.Context, c *dynamodb.Client, tableName, indexName, hashKey, sortKey string, sortType types.ScalarAttributeType) error {
+	waiter := dynamodb.NewTableExistsWaiter(c)
+	if err := waiter.Wait(ctx, &dynamodb.DescribeTableInput{TableName: aws.String(tableName)}, 2*time.Minute); err != nil {
+		return err
+	}
+
+	desc, err := c.DescribeTable(ctx, &dynamodb.DescribeTableInput{TableName: aws.String(tableName)})
+	if err != nil {
+		return err
+	}
+	for _, gsi := range desc.Table.GlobalSecondaryIndexes {
+		if aws.ToString(gsi.IndexName) == indexName {
+			if gsi.IndexStatus == types.IndexStatusActive {
+				return nil
+			}
+			return waitIndexActive(ctx, c, tableName, indexName)
+		}
+	}
+
+	log.Printf("NexusLink: creating index %s on %s", indexName, tableName)
+
+	attrs := []types.AttributeDefinition{
+		{AttributeName: aws.String(hashKey), AttributeType: types.ScalarAttributeTypeS},
+	}
+	keys := []types.KeySchemaElement{
+		{AttributeName: aws.String(hashKey), KeyType: types.KeyTypeHash},
+	}
+	if sortKey != "" {
+		attrs = append(attrs, types.AttributeDefinition{AttributeName: aws.String(sortKey), AttributeType: sortType})
+		keys = append(keys, types.KeySchemaElement{AttributeName: aws.String(sortKey), KeyType: types.KeyTypeRange})
+	}
+
+	_, err = c.UpdateTable(ctx, &dynamodb.UpdateTableInput{
+		TableName:            aws.String(tableName),
+		AttributeDefinitions: attrs,
+		GlobalSecondaryIndexUpdates: []types.GlobalSecondaryIndexUpdate{
+			{
+				Create: &types.CreateGlobalSecondaryIndexAction{
+					IndexName:  aws.String(indexName),
+					KeySchema:  keys,
+					Projection: &types.Projection{ProjectionType: types.ProjectionTypeAll},
+				},
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	return waitIndexActive(ctx, c, tableName, indexName)
+}
+
+// waitIndexActive polling DescribeTable sampai GSI ACTIVE (backfill tabel besar bisa makan beberapa menit)
+func waitIndexActive(ctx context.Context, c *dynamodb.Client, tableName, indexName string) error {
+	start := time.Now()
+	for {
+		desc, err := c.DescribeTable(ctx, &dynamodb.DescribeTableInput{TableName: aws.String(tableName)})
+		if err != nil {
+			return err
+		}
+		status := types.IndexStatus("")
+		for _, gsi := range desc.Table.GlobalSecondaryIndexes {
+			if aws.ToString(gsi.IndexName) == indexName {
+				status = gsi.IndexStatus
+			}
+		}
+		switch status {
+		case types.IndexStatusActive:
+			log.Printf("NexusLink: index %s on %s is active (%s)", indexName, tableName, time.Since(start).Round(time.Second))
+			return nil
+		case "", types.IndexStatusDeleting:
+			return fmt.Errorf("index %s on %s disappeared while waiting (status %q)", indexName, tableName, status)
+		}
+
+		log.Printf("NexusLink: waiting for index %s on %s (status %s)", indexName, tableName, status)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(indexPollInterval):
+		}
+	}
+}
+
+// enableTTL mengaktifkan DynamoDB TTL pada attribute tertentu (tunggu table ACTIVE dulu)
+func enableTTL(ctx context.Context, c *dynamodb.Client, tableName, attribute string) error {
+	waiter := dynamodb.NewTableExistsWaiter(c)
+	if err := waiter.Wait(ctx, &dynamodb.DescribeTableInput{TableName: aws.String(tableName)}, 30*time.Second); err != nil {
+		return err
+	}
+
+	_, err := c.UpdateTimeToLive(ctx, &dynamodb.UpdateTimeToLiveInput{
+		TableName: aws.String(tableName),
+		TimeToLiveSpecification: &types.TimeToLiveSpecification{
+			AttributeName: aws.String(attribute),
+			Enabled:       aws.Bool(true),
+		},
+	})
+	return err
+}
@@ -0,0 +1,38 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	awsmiddleware "github.com/aws/aws-sdk-go-v2/aws/middleware"
+	"github.com/aws/smithy-go"
+	"github.com/aws/smithy-go/middleware"
+
+	"github.com/afuzapratama/nexuslink/internal/metrics"
+)
+
+// addMetricsMiddleware memasang middleware di step Initialize, jadi durasi
+// yang tercatat sudah termasuk retry SDK.
+func addMetricsMiddleware(stack *middleware.Stack) error {
+	return stack.Initialize.Add(middleware.InitializeMiddlewareFunc("NexusMetrics",
+		func(ctx context.Context, in middleware.InitializeInput, next middleware.InitializeHandler) (middleware.InitializeOutput, middleware.Metadata, error) {
+			start := time.Now()
+			out, md, err := next.HandleInitialize(ctx, in)
+
+			metrics.GetMetrics().RecordDynamoCall(awsmiddleware.GetOperationName(ctx), callStatus(err), time.Since(start))
+			return out, md, err
+		}), middleware.After)
+}
+
+// callStatus: "ok", error code dari AWS (mis. ConditionalCheckFailedException), atau "error"
+func callStatus(err error) string {
+	if err == nil {
+		return "ok"
+	}
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.ErrorCode()
+	}
+	return "error"
+}
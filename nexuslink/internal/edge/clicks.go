@@ -0,0 +1,156 @@
+package edge
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/afuzapratama/nexuslink/internal/models"
+)
+
+const (
+	defaultClickQueueSize  = 10000
+	defaultClickBatchSize  = 100
+	defaultClickFlushEvery = 2 * time.Second
+)
+
+// ClickShipper mengantrikan click event hasil resolve lokal dan mengirimnya batch ke POST /sync/clicks.
+// Kalau API tidak reachable, event tetap di queue dan dicoba lagi di flush berikutnya.
+type ClickShipper struct {
+	mu      sync.Mutex
+	queue   []models.ClickEvent
+	max     int
+	dropped int64
+
+	url    string
+	apiKey func() string // key bisa dirotasi saat agent berjalan
+	client *http.Client
+
+	batchSize  int
+	flushEvery time.Duration
+	flushNow   chan struct{}
+}
+
+// NewClickShipper creates a shipper that posts to {apiBase}/sync/clicks
+func NewClickShipper(apiBase string, apiKey func() string) *ClickShipper {
+	return &ClickShipper{
+		max:        defaultClickQueueSize,
+		url:        apiBase + "/sync/clicks",
+		apiKey:     apiKey,
+		client:     &http.Client{Timeout: 10 * time.Second},
+		batchSize:  defaultClickBatchSize,
+		flushEvery: defaultClickFlushEvery,
+		flushNow:   make(chan struct{}, 1),
+	}
+}
+
+// Enqueue menambahkan click ke queue. Kalau queue penuh, event paling lama dibuang.
+func (s *ClickShipper) Enqueue(ev models.ClickEvent) {
+	s.mu.Lock()
+	if len(s.queue) >= s.max {
+		s.queue = s.queue[1:]
+		s.dropped++
+		if s.dropped%1000 == 1 {
+			log.Printf("edge: click queue full (%d), dropped %d events so far", s.max, s.dropped)
+		}
+	}
+	s.queue = append(s.queue, ev)
+	full := len(s.queue) >= s.batchSize
+	s.mu.Unlock()
+
+	if full {
+		select {
+		case s.flushNow <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// Pending returns the number of queued click events
+func (s *ClickShipper) Pending() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.queue)
+}
+
+// Start menjalankan loop flush di background
+func (s *ClickShipper) Start() {
+	go func() {
+		ticker := time.NewTicker(s.flushEvery)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+			case <-s.flushNow:
+			}
+			s.Flush()
+		}
+	}()
+}
+
+// Flush mengirim semua event yang ada di queue (per batch). Berhenti di error pertama.
+func (s *ClickShipper) Flush() {
+	for {
+		s.mu.Lock()
+		n := len(s.queue)
+		if n == 0 {
+			s.mu.Unlock()
+			return
+		}
+		if n > s.batchSize {
+			n = s.batchSize
+		}
+		batch := make([]models.ClickEvent, n)
+		copy(batch, s.queue[:n])
+		droppedBefore := s.dropped
+		s.mu.Unlock()
+
+		if err := s.send(batch); err != nil {
+			log.Printf("edge: ship %d clicks failed (will retry): %v", len(batch), err)
+			return
+		}
+
+		// Buang batch yang sudah terkirim. Drop selama send selalu dari depan queue,
+		// jadi bagian batch yang sudah hilang tidak perlu dibuang lagi.
+		s.mu.Lock()
+		remaining := n - int(s.dropped-droppedBefore)
+		if remaining > 0 {
+			s.queue = s.queue[remaining:]
+		}
+		s.mu.Unlock()
+	}
+}
+
+func (s *ClickShipper) send(batch []models.ClickEvent) error {
+	body, err := json.Marshal(batch)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if key := s.apiKey(); key != "" {
+		req.Header.Set("X-Nexus-Api-Key", key)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		data, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("status=%d body=%s", resp.StatusCode, string(data))
+	}
+	return nil
+}
@@ -0,0 +1,204 @@
+package edge
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/afuzapratama/nexuslink/internal/models"
+)
+
+// DomainSignatureHeader membawa HMAC-SHA256 body NodeDomainFeed ("sha256=<hex>")
+const DomainSignatureHeader = "X-Nexus-Signature"
+
+// SignDomainFeed menandatangani body feed (dipakai API)
+func SignDomainFeed(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyDomainFeed memverifikasi signature lalu decode feed (dipakai agent)
+func VerifyDomainFeed(secret string, body []byte, signature string) (*models.NodeDomainFeed, error) {
+	if secret == "" {
+		return nil, errors.New("domain feed secret is not configured")
+	}
+	if !hmac.Equal([]byte(SignDomainFeed(secret, body)), []byte(strings.TrimSpace(signature))) {
+		return nil, errors.New("invalid domain feed signature")
+	}
+
+	var feed models.NodeDomainFeed
+	if err := json.Unmarshal(body, &feed); err != nil {
+		return nil, err
+	}
+	return &feed, nil
+}
+
+// DomainRegistry adalah daftar domain yang boleh dilayani agent ini (concurrency-safe)
+type DomainRegistry struct {
+	mu      sync.RWMutex
+	nodeID  string // node pemilik versi di bawah
+	domains map[string]bool
+	list    []string
+	version int64
+}
+
+// NewDomainRegistry creates an empty registry (kosong = semua domain diizinkan)
+func NewDomainRegistry() *DomainRegistry {
+	return &DomainRegistry{domains: make(map[string]bool)}
+}
+
+// Apply menerapkan feed dari API untuk node ini. Feed untuk node lain atau versi lebih lama
+// diabaikan (feed lama yang di-replay tidak bisa mengembalikan domain yang sudah dihapus).
+// Returns true kalau daftar domain berubah.
+func (d *DomainRegistry) Apply(nodeID string, feed *models.NodeDomainFeed) bool {
+	if feed.NodeID != nodeID {
+		return false
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.nodeID == nodeID && feed.Version < d.version {
+		return false
+	}
+	d.nodeID = nodeID
+	return d.set(feed.Version, feed.Domains)
+}
+
+// Seed mengisi registry dengan domain registrasi kalau belum punya feed untuk node ini
+// (kosong, atau cache milik node lain)
+func (d *DomainRegistry) Seed(nodeID string, domains ...string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.nodeID != nodeID {
+		d.nodeID = ""
+		d.set(0, domains)
+	}
+}
+
+func (d *DomainRegistry) set(version int64, domains []string) bool {
+	next := make(map[string]bool, len(domains))
+	var list []string
+	for _, domain := range domains {
+		domain = normalizeHost(domain)
+		if domain == "" || next[domain] {
+			continue
+		}
+		next[domain] = true
+		list = append(list, domain)
+	}
+
+	changed := len(next) != len(d.domains)
+	for domain := range next {
+		if !d.domains[domain] {
+			changed = true
+		}
+	}
+
+	d.domains, d.list, d.version = next, list, version
+	return changed
+}
+
+// Allowed true kalau domain boleh dilayani (registry kosong = tidak ada restriksi)
+func (d *DomainRegistry) Allowed(domain string) bool {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	if len(d.domains) == 0 {
+		return true
+	}
+	return d.domains[normalizeHost(domain)]
+}
+
+// Contains true hanya kalau domain benar-benar ada di daftar (registry kosong = false).
+// Dipakai untuk keputusan yang harus eksplisit, misalnya penerbitan sertifikat TLS.
+func (d *DomainRegistry) Contains(domain string) bool {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.domains[normalizeHost(domain)]
+}
+
+// Domains returns salinan daftar domain saat ini
+func (d *DomainRegistry) Domains() []string {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return append([]string(nil), d.list...)
+}
+
+// Version returns versi feed terakhir yang diterapkan untuk node ini
+// (-1 = belum pernah menerima feed node ini, API langsung mengirim feed)
+func (d *DomainRegistry) Version(nodeID string) int64 {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	if d.nodeID == "" || d.nodeID != nodeID {
+		return -1
+	}
+	return d.version
+}
+
+// persistedDomains adalah format file cache domain di disk
+type persistedDomains struct {
+	NodeID  string   `json:"nodeId"`
+	Version int64    `json:"version"`
+	Domains []string `json:"domains"`
+}
+
+// Save menulis registry ke file (atomic rename)
+func (d *DomainRegistry) Save(path string) error {
+	d.mu.RLock()
+	data := persistedDomains{NodeID: d.nodeID, Version: d.version, Domains: d.list}
+	d.mu.RUnlock()
+
+	b, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, b, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// Load membaca cache domain supaya agent yang cold start bisa langsung serve.
+// File tidak ada bukan error.
+func (d *DomainRegistry) Load(path string) error {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	var data persistedDomains
+	if err := json.Unmarshal(b, &data); err != nil {
+		return err
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.nodeID = data.NodeID
+	d.set(data.Version, data.Domains)
+	return nil
+}
+
+// normalizeHost lowercase + buang port
+func normalizeHost(host string) string {
+	host = strings.ToLower(strings.TrimSpace(host))
+	if idx := strings.Index(host, ":"); idx != -1 {
+		host = host[:idx]
+	}
+	return host
+}
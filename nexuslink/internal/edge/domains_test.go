@@ -0,0 +1,92 @@
+package edge
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/afuzapratama/nexuslink/internal/models"
+)
+
+func TestVerifyDomainFeed(t *testing.T) {
+	body, _ := json.Marshal(models.NodeDomainFeed{NodeID: "n1", Version: 5, Domains: []string{"go.example.com"}})
+	sig := SignDomainFeed("secret", body)
+
+	feed, err := VerifyDomainFeed("secret", body, sig)
+	if err != nil || feed.NodeID != "n1" || feed.Version != 5 {
+		t.Fatalf("verify: feed=%+v err=%v", feed, err)
+	}
+
+	if _, err := VerifyDomainFeed("other", body, sig); err == nil {
+		t.Errorf("wrong secret should be rejected")
+	}
+	tampered := []byte(string(body[:len(body)-1]) + " }")
+	if _, err := VerifyDomainFeed("secret", tampered, sig); err == nil {
+		t.Errorf("tampered body should be rejected")
+	}
+	if _, err := VerifyDomainFeed("", body, SignDomainFeed("", body)); err == nil {
+		t.Errorf("empty secret should be rejected")
+	}
+}
+
+func TestDomainRegistryApply(t *testing.T) {
+	d := NewDomainRegistry()
+	if !d.Allowed("anything.example.com") || d.Contains("anything.example.com") || d.Version("n1") != -1 {
+		t.Fatalf("empty registry should allow all, contain nothing and have no version")
+	}
+
+	d.Seed("n1", "Go.Example.com")
+	if !d.Allowed("go.example.com:443") || d.Allowed("evil.example.com") {
+		t.Errorf("seeded domain: %v", d.Domains())
+	}
+
+	if !d.Apply("n1", &models.NodeDomainFeed{NodeID: "n1", Version: 10, Domains: []string{"go.example.com", "id.example.com"}}) {
+		t.Errorf("new domain should report a change")
+	}
+	if !d.Allowed("id.example.com") || d.Version("n1") != 10 {
+		t.Errorf("after apply: %v v=%d", d.Domains(), d.Version("n1"))
+	}
+
+	// Replay feed lama tidak boleh mengembalikan domain yang sudah dihapus
+	d.Apply("n1", &models.NodeDomainFeed{NodeID: "n1", Version: 20, Domains: []string{"go.example.com"}})
+	d.Apply("n1", &models.NodeDomainFeed{NodeID: "n1", Version: 10, Domains: []string{"go.example.com", "id.example.com"}})
+	if d.Allowed("id.example.com") {
+		t.Errorf("older feed must be ignored")
+	}
+
+	// Feed untuk node lain diabaikan
+	if d.Apply("n1", &models.NodeDomainFeed{NodeID: "n2", Version: 30, Domains: []string{"evil.example.com"}}) || d.Allowed("evil.example.com") {
+		t.Errorf("feed for another node must be ignored")
+	}
+}
+
+func TestDomainRegistrySaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "domains.json")
+
+	d := NewDomainRegistry()
+	d.Apply("n1", &models.NodeDomainFeed{NodeID: "n1", Version: 7, Domains: []string{"go.example.com"}})
+	if err := d.Save(path); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded := NewDomainRegistry()
+	if err := loaded.Load(path); err != nil {
+		t.Fatal(err)
+	}
+	if loaded.Version("n1") != 7 || !loaded.Allowed("go.example.com") || loaded.Allowed("x.example.com") {
+		t.Errorf("loaded registry: %v v=%d", loaded.Domains(), loaded.Version("n1"))
+	}
+
+	// Cache milik node lain: versi tidak dipakai, seed domain registrasi menggantikannya
+	if loaded.Version("n2") != -1 {
+		t.Errorf("version for another node should be -1")
+	}
+	loaded.Seed("n2", "sg.example.com")
+	if loaded.Allowed("go.example.com") || !loaded.Allowed("sg.example.com") {
+		t.Errorf("seed should replace cache of another node: %v", loaded.Domains())
+	}
+
+	if err := NewDomainRegistry().Load(filepath.Join(t.TempDir(), "missing.json")); err != nil {
+		t.Errorf("missing cache file should not be an error: %v", err)
+	}
+}
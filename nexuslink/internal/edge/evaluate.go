@@ -0,0 +1,116 @@
+package edge
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/afuzapratama/nexuslink/internal/models"
+	"github.com/afuzapratama/nexuslink/internal/rules"
+	"github.com/afuzapratama/nexuslink/internal/util"
+)
+
+// Decision adalah hasil resolve lokal di agent (tanpa API)
+type Decision struct {
+	TargetURL string // kosong = jangan redirect, kirim Status + Message
+	Reason    string // reason fallback / block (kosong untuk redirect normal)
+	Status    int
+	Message   string
+	Disabled  bool // render landing page link disabled
+
+	RuleID    string
+	VariantID string
+
+	// Counted = visitor lolos pre-check (disabled/domain/schedule/expiry), click harus dikirim ke API
+	Counted bool
+}
+
+// Evaluate menjalankan check yang sama dengan ResolverHandler.HandleResolve,
+// kecuali IP quality check dan max clicks yang butuh data terpusat.
+func Evaluate(snap *models.LinkSnapshot, v rules.Visitor, domain string, now time.Time) Decision {
+	link := &snap.Link
+
+	if !link.IsActive {
+		if link.DisabledBehavior == models.DisabledBehaviorFallback && strings.TrimSpace(link.FallbackURL) != "" {
+			return Decision{TargetURL: link.FallbackURL, Reason: "link_disabled"}
+		}
+		status := http.StatusNotFound
+		if link.DisabledBehavior == models.DisabledBehaviorGone {
+			status = http.StatusGone
+		}
+		return Decision{Status: status, Message: link.DisabledMessage, Reason: "link_disabled", Disabled: true}
+	}
+
+	if !link.ServesDomain(domain) {
+		return deny(link, "domain_not_allowed", http.StatusForbidden, "link not available on this domain")
+	}
+	if link.ActiveFrom != nil && now.Before(*link.ActiveFrom) {
+		return deny(link, "not_yet_active", http.StatusForbidden, "link is not yet active")
+	}
+	if link.ActiveUntil != nil && now.After(*link.ActiveUntil) {
+		return deny(link, "schedule_ended", http.StatusGone, "link schedule has ended")
+	}
+	if link.ExpiresAt != nil && now.After(*link.ExpiresAt) {
+		return deny(link, "expired", http.StatusGone, "link expired")
+	}
+
+	chain := append(rules.LegacyRules(link), link.Rules...)
+	matched := rules.Evaluate(chain, v)
+
+	d := Decision{TargetURL: link.TargetURL, Counted: true}
+
+	if matched != nil {
+		d.RuleID = matched.ID
+
+		switch matched.Action.Type {
+		case models.RuleActionBlock:
+			reason := matched.Action.Reason
+			if reason == "" {
+				reason = "blocked_by_rule"
+			}
+			blocked := deny(link, reason, http.StatusForbidden, "access blocked")
+			blocked.RuleID = matched.ID
+			blocked.Counted = true
+			return blocked
+
+		case models.RuleActionRedirect:
+			d.TargetURL = matched.Action.URL
+
+		case models.RuleActionVariant:
+			for i := range snap.Variants {
+				if snap.Variants[i].ID == matched.Action.VariantID {
+					d.TargetURL = snap.Variants[i].TargetURL
+					d.VariantID = snap.Variants[i].ID
+					break
+				}
+			}
+		}
+		return d
+	}
+
+	if selected := util.SelectVariantByWeight(snap.Variants); selected != nil {
+		d.TargetURL = selected.TargetURL
+		d.VariantID = selected.ID
+	}
+
+	return d
+}
+
+func deny(link *models.Link, reason string, status int, message string) Decision {
+	if strings.TrimSpace(link.FallbackURL) != "" {
+		return Decision{TargetURL: link.FallbackURL, Reason: reason}
+	}
+	return Decision{Reason: reason, Status: status, Message: message}
+}
+
+// NeedsCentral true kalau link butuh data yang hanya ada di API (max clicks, IP quality check, rate limit).
+// Agent tetap resolve lewat API untuk link seperti ini selama API reachable.
+func NeedsCentral(snap *models.LinkSnapshot, ipCheckEnabled, rateLimitEnabled bool) bool {
+	if ipCheckEnabled || snap.Link.MaxClicks != nil {
+		return true
+	}
+	if rl := snap.Link.RateLimit; rl != nil {
+		return !rl.Disabled
+	}
+	return rateLimitEnabled
+}
@@ -0,0 +1,200 @@
+package edge
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/afuzapratama/nexuslink/internal/models"
+)
+
+// Store menyimpan snapshot definisi link di memory agent (concurrency-safe)
+type Store struct {
+	mu               sync.RWMutex
+	links            map[string]*models.LinkSnapshot // link ID -> snapshot
+	byAlias          map[string]map[string]bool      // lowercase alias -> link ID (alias unik per domain)
+	version          int64
+	ipCheckEnabled   bool
+	rateLimitEnabled bool
+	syncedAt         time.Time
+}
+
+// NewStore creates an empty link store
+func NewStore() *Store {
+	return &Store{
+		links:   make(map[string]*models.LinkSnapshot),
+		byAlias: make(map[string]map[string]bool),
+	}
+}
+
+// Apply menerapkan delta feed dari API. Feed.Full = replace seluruh snapshot.
+// Returns true kalau ada perubahan.
+func (s *Store) Apply(feed *models.LinkFeed) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	changed := feed.Full || len(feed.Links) > 0 || len(feed.Deleted) > 0 ||
+		s.ipCheckEnabled != feed.IPCheckEnabled || s.rateLimitEnabled != feed.RateLimitEnabled
+
+	if feed.Full {
+		s.links = make(map[string]*models.LinkSnapshot, len(feed.Links))
+		s.byAlias = make(map[string]map[string]bool, len(feed.Links))
+	}
+
+	for i := range feed.Links {
+		snap := feed.Links[i]
+		if old, ok := s.links[snap.Link.ID]; ok {
+			s.unindex(old)
+		}
+		s.links[snap.Link.ID] = &snap
+		s.index(&snap)
+	}
+
+	for _, id := range feed.Deleted {
+		if old, ok := s.links[id]; ok {
+			s.unindex(old)
+			delete(s.links, id)
+		}
+	}
+
+	s.version = feed.Version
+	s.ipCheckEnabled = feed.IPCheckEnabled
+	s.rateLimitEnabled = feed.RateLimitEnabled
+	s.syncedAt = time.Now()
+
+	return changed
+}
+
+func (s *Store) index(snap *models.LinkSnapshot) {
+	alias := strings.ToLower(snap.Link.Alias)
+	if s.byAlias[alias] == nil {
+		s.byAlias[alias] = make(map[string]bool)
+	}
+	s.byAlias[alias][snap.Link.ID] = true
+}
+
+func (s *Store) unindex(snap *models.LinkSnapshot) {
+	alias := strings.ToLower(snap.Link.Alias)
+	delete(s.byAlias[alias], snap.Link.ID)
+	if len(s.byAlias[alias]) == 0 {
+		delete(s.byAlias, alias)
+	}
+}
+
+// Lookup returns the snapshot for an alias di domain request (nil kalau tidak ada).
+// Urutan pilihan sama dengan API (models.PickForDomain): Domain sama, pool, lalu link tanpa domain.
+func (s *Store) Lookup(domain, alias string) *models.LinkSnapshot {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var best *models.LinkSnapshot
+	bestRank := -1
+	for id := range s.byAlias[strings.ToLower(alias)] {
+		snap := s.links[id]
+		rank := snap.Link.DomainRank(domain)
+		// ID sebagai tie-breaker supaya hasil tidak tergantung urutan map
+		if rank > bestRank || (rank == bestRank && snap.Link.ID < best.Link.ID) {
+			best, bestRank = snap, rank
+		}
+	}
+	return best
+}
+
+// Version returns the last applied feed version (0 = belum pernah sync)
+func (s *Store) Version() int64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.version
+}
+
+// IPCheckEnabled returns whether the API has IP quality checks enabled
+func (s *Store) IPCheckEnabled() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.ipCheckEnabled
+}
+
+// RateLimitEnabled returns whether the API enforces rate limits globally
+func (s *Store) RateLimitEnabled() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.rateLimitEnabled
+}
+
+// Ready true kalau store sudah punya snapshot (dari API atau dari file cache)
+func (s *Store) Ready() bool {
+	return s.Version() > 0
+}
+
+// Len returns the number of cached links
+func (s *Store) Len() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.links)
+}
+
+// persistedStore adalah format file cache di disk
+type persistedStore struct {
+	Version          int64                 `json:"version"`
+	IPCheckEnabled   bool                  `json:"ipCheckEnabled"`
+	RateLimitEnabled bool                  `json:"rateLimitEnabled"`
+	Links            []models.LinkSnapshot `json:"links"`
+}
+
+// Save menulis snapshot ke file (atomic rename) supaya agent yang restart bisa langsung serve
+func (s *Store) Save(path string) error {
+	s.mu.RLock()
+	data := persistedStore{
+		Version:          s.version,
+		IPCheckEnabled:   s.ipCheckEnabled,
+		RateLimitEnabled: s.rateLimitEnabled,
+		Links:            make([]models.LinkSnapshot, 0, len(s.links)),
+	}
+	for _, snap := range s.links {
+		data.Links = append(data.Links, *snap)
+	}
+	s.mu.RUnlock()
+
+	b, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, b, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// Load membaca snapshot dari file cache. File tidak ada bukan error.
+func (s *Store) Load(path string) error {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	var data persistedStore
+	if err := json.Unmarshal(b, &data); err != nil {
+		return err
+	}
+
+	s.Apply(&models.LinkFeed{
+		Version:          data.Version,
+		Full:             true,
+		Links:            data.Links,
+		IPCheckEnabled:   data.IPCheckEnabled,
+		RateLimitEnabled: data.RateLimitEnabled,
+	})
+	return nil
+}
@@ -0,0 +1,131 @@
+package edge
+
+import (
+	"net/http"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/afuzapratama/nexuslink/internal/models"
+	"github.com/afuzapratama/nexuslink/internal/rules"
+)
+
+func TestStoreApplyDelta(t *testing.T) {
+	s := NewStore()
+	s.Apply(&models.LinkFeed{
+		Version: 1,
+		Full:    true,
+		Links: []models.LinkSnapshot{
+			{Link: models.Link{ID: "1", Alias: "Promo", TargetURL: "https://a.example"}},
+			{Link: models.Link{ID: "2", Alias: "docs", TargetURL: "https://b.example"}},
+		},
+	})
+
+	if snap := s.Lookup("", "promo"); snap == nil || snap.Link.ID != "1" {
+		t.Fatalf("expected case-insensitive lookup of promo, got %+v", snap)
+	}
+
+	// Rename alias link 1, hapus link 2
+	s.Apply(&models.LinkFeed{
+		Version: 2,
+		Links:   []models.LinkSnapshot{{Link: models.Link{ID: "1", Alias: "sale", TargetURL: "https://a.example"}}},
+		Deleted: []string{"2"},
+	})
+
+	if s.Lookup("", "promo") != nil {
+		t.Errorf("old alias should be removed after rename")
+	}
+	if s.Lookup("", "sale") == nil {
+		t.Errorf("renamed alias should resolve")
+	}
+	if s.Lookup("", "docs") != nil {
+		t.Errorf("deleted link should be removed")
+	}
+	if s.Version() != 2 {
+		t.Errorf("version mismatch: got %d, want 2", s.Version())
+	}
+}
+
+func TestStoreLookupByDomain(t *testing.T) {
+	s := NewStore()
+	s.Apply(&models.LinkFeed{
+		Version: 1,
+		Full:    true,
+		Links: []models.LinkSnapshot{
+			{Link: models.Link{ID: "1", Alias: "promo", Key: "promo"}},
+			{Link: models.Link{ID: "2", Alias: "promo", Domain: "go.brand-a.com", Key: "go.brand-a.com/promo"}},
+			{Link: models.Link{ID: "3", Alias: "promo", Domain: "go.brand-b.com", Key: "go.brand-b.com/promo"}},
+		},
+	})
+
+	testCases := []struct {
+		domain string
+		want   string
+	}{
+		{"go.brand-a.com", "2"},
+		{"GO.BRAND-B.COM", "3"},
+		{"other.example", "1"}, // fallback ke link tanpa domain
+	}
+	for _, tc := range testCases {
+		if snap := s.Lookup(tc.domain, "promo"); snap == nil || snap.Link.ID != tc.want {
+			t.Errorf("Lookup(%q): got %+v, want link %s", tc.domain, snap, tc.want)
+		}
+	}
+
+	// Link ber-domain dihapus → domain itu jatuh ke link tanpa domain
+	s.Apply(&models.LinkFeed{Version: 2, Deleted: []string{"2"}})
+	if snap := s.Lookup("go.brand-a.com", "promo"); snap == nil || snap.Link.ID != "1" {
+		t.Errorf("expected fallback to domain-less link after delete, got %+v", snap)
+	}
+}
+
+func TestStoreSaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "links.json")
+
+	s := NewStore()
+	s.Apply(&models.LinkFeed{
+		Version:        42,
+		Full:           true,
+		IPCheckEnabled: true,
+		Links:          []models.LinkSnapshot{{Link: models.Link{ID: "1", Alias: "promo"}}},
+	})
+	if err := s.Save(path); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+
+	loaded := NewStore()
+	if err := loaded.Load(path); err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if loaded.Version() != 42 || !loaded.IPCheckEnabled() || loaded.Lookup("", "promo") == nil {
+		t.Errorf("loaded store mismatch: version=%d ipCheck=%v", loaded.Version(), loaded.IPCheckEnabled())
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	now := time.Now()
+	past := now.Add(-time.Hour)
+
+	testCases := []struct {
+		name    string
+		link    models.Link
+		visitor rules.Visitor
+		target  string
+		status  int
+		counted bool
+	}{
+		{"redirect", models.Link{IsActive: true, TargetURL: "https://t.example"}, rules.Visitor{}, "https://t.example", 0, true},
+		{"disabled gone", models.Link{DisabledBehavior: models.DisabledBehaviorGone}, rules.Visitor{}, "", http.StatusGone, false},
+		{"expired fallback", models.Link{IsActive: true, ExpiresAt: &past, FallbackURL: "https://f.example"}, rules.Visitor{}, "https://f.example", 0, false},
+		{"bot blocked", models.Link{IsActive: true, BlockBots: true, TargetURL: "https://t.example"}, rules.Visitor{IsBot: true}, "", http.StatusForbidden, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			d := Evaluate(&models.LinkSnapshot{Link: tc.link}, tc.visitor, "", now)
+			if d.TargetURL != tc.target || d.Status != tc.status || d.Counted != tc.counted {
+				t.Errorf("decision mismatch: got %+v", d)
+			}
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package geoip
 
 import (
+	"fmt"
 	"log"
 	"net"
 	"os"
@@ -13,6 +14,9 @@ var (
 	db      *geoip2.Reader
 	once    sync.Once
 	loadErr error
+
+	asnDB   *geoip2.Reader
+	asnOnce sync.Once
 )
 
 func initDB() {
@@ -29,6 +33,19 @@ func initDB() {
 	}
 }
 
+func initASNDB() {
+	path := os.Getenv("NEXUS_MAXMIND_ASN_DB_PATH")
+	if path == "" {
+		return
+	}
+
+	var err error
+	asnDB, err = geoip2.Open(path)
+	if err != nil {
+		log.Printf("geoip: failed to open MaxMind ASN DB: %v", err)
+	}
+}
+
 // Lookup mengembalikan countryCode + city (boleh kosong kalau gagal)
 func Lookup(ipStr string) (countryCode, city string) {
 	once.Do(initDB)
@@ -55,3 +72,24 @@ func Lookup(ipStr string) (countryCode, city string) {
 	}
 	return
 }
+
+// LookupASN mengembalikan ASN dalam format "AS7713" (kosong kalau DB ASN tidak diset)
+func LookupASN(ipStr string) string {
+	asnOnce.Do(initASNDB)
+
+	if asnDB == nil {
+		return ""
+	}
+
+	ip := net.ParseIP(ipStr)
+	if ip == nil {
+		return ""
+	}
+
+	record, err := asnDB.ASN(ip)
+	if err != nil || record.AutonomousSystemNumber == 0 {
+		return ""
+	}
+
+	return fmt.Sprintf("AS%d", record.AutonomousSystemNumber)
+}
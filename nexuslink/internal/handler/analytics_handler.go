@@ -0,0 +1,198 @@
+package handler
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/afuzapratama/nexuslink/internal/models"
+	"github.com/afuzapratama/nexuslink/internal/repository"
+	"github.com/afuzapratama/nexuslink/internal/rollup"
+)
+
+// maxSeriesBuckets membatasi panjang range per request (e.g. 2000 jam ≈ 83 hari)
+const maxSeriesBuckets = 2000
+
+type AnalyticsHandler struct {
+	rollupRepo repository.RollupStore
+	linkRepo   repository.LinkStore
+}
+
+func NewAnalyticsHandler(rollupRepo repository.RollupStore, linkRepo repository.LinkStore) *AnalyticsHandler {
+	return &AnalyticsHandler{
+		rollupRepo: rollupRepo,
+		linkRepo:   linkRepo,
+	}
+}
+
+// seriesLine adalah satu garis di chart: count per bucket untuk satu value dimensi
+type seriesLine struct {
+	Value  string  `json:"value"`
+	Total  int64   `json:"total"`
+	Points []int64 `json:"points"` // sejajar dengan response.Buckets
+}
+
+// GET /analytics/series?alias=&domain=&granularity=hour|day&dimension=country&from=&to=&top=10
+// Dibaca dari tabel rollup, tanpa scan click events. alias kosong = semua link.
+func (h *AnalyticsHandler) HandleSeries(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	q := r.URL.Query()
+
+	alias := strings.TrimSpace(q.Get("alias"))
+	if alias == "" {
+		alias = models.RollupAllLinks
+	} else {
+		// Rollup disimpan per key data link ("domain/alias" untuk link ber-domain)
+		key, ok := AnalyticsKey(w, r, h.linkRepo, alias)
+		if !ok {
+			return
+		}
+		alias = key
+	}
+
+	granularity := q.Get("granularity")
+	if granularity == "" {
+		granularity = models.RollupHour
+	}
+	if !rollup.ValidGranularity(granularity) {
+		http.Error(w, "granularity must be hour or day", http.StatusBadRequest)
+		return
+	}
+
+	dimension := q.Get("dimension")
+	if dimension == "" {
+		dimension = models.RollupDimTotal
+	}
+	if !rollup.ValidDimension(dimension) {
+		http.Error(w, "invalid dimension, use one of: "+strings.Join(rollup.Dimensions, ", "), http.StatusBadRequest)
+		return
+	}
+
+	to := time.Now().UTC()
+	if v := q.Get("to"); v != "" {
+		t, err := parseSeriesTime(v)
+		if err != nil {
+			http.Error(w, "invalid to (use RFC3339 or YYYY-MM-DD)", http.StatusBadRequest)
+			return
+		}
+		to = t
+	}
+
+	from := to.Add(-24 * time.Hour)
+	if granularity == models.RollupDay {
+		from = to.AddDate(0, 0, -30)
+	}
+	if v := q.Get("from"); v != "" {
+		t, err := parseSeriesTime(v)
+		if err != nil {
+			http.Error(w, "invalid from (use RFC3339 or YYYY-MM-DD)", http.StatusBadRequest)
+			return
+		}
+		from = t
+	}
+
+	from = rollup.Truncate(from, granularity)
+	to = rollup.Truncate(to, granularity)
+	if to.Before(from) {
+		http.Error(w, "from must be before to", http.StatusBadRequest)
+		return
+	}
+
+	step := rollup.Step(granularity)
+	count := int(to.Sub(from)/step) + 1
+	if count > maxSeriesBuckets {
+		http.Error(w, "range too large for granularity", http.StatusBadRequest)
+		return
+	}
+
+	top := 10
+	if v := q.Get("top"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			top = n
+		}
+	}
+
+	rows, err := h.rollupRepo.Query(r.Context(),
+		rollup.PK(alias, granularity, dimension),
+		rollup.Bucket(from, granularity),
+		rollup.Bucket(to, granularity),
+	)
+	if err != nil {
+		log.Printf("rollupRepo.Query error: %v", err)
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+
+	// Index bucket → posisi di array points
+	buckets := make([]string, count)
+	index := make(map[string]int, count)
+	for i := 0; i < count; i++ {
+		t := from.Add(time.Duration(i) * step)
+		buckets[i] = t.Format(time.RFC3339)
+		index[rollup.Bucket(t, granularity)] = i
+	}
+
+	lines := make(map[string]*seriesLine)
+	for _, row := range rows {
+		i, ok := index[row.Bucket]
+		if !ok {
+			continue
+		}
+		line, ok := lines[row.Value]
+		if !ok {
+			line = &seriesLine{Value: row.Value, Points: make([]int64, count)}
+			lines[row.Value] = line
+		}
+		line.Points[i] += row.Count
+		line.Total += row.Count
+	}
+
+	series := make([]*seriesLine, 0, len(lines))
+	for _, line := range lines {
+		series = append(series, line)
+	}
+	sort.Slice(series, func(i, j int) bool {
+		if series[i].Total != series[j].Total {
+			return series[i].Total > series[j].Total
+		}
+		return series[i].Value < series[j].Value
+	})
+
+	// Value di luar top N digabung jadi "other"
+	if len(series) > top {
+		other := &seriesLine{Value: "other", Points: make([]int64, count)}
+		for _, line := range series[top:] {
+			for i, c := range line.Points {
+				other.Points[i] += c
+			}
+			other.Total += line.Total
+		}
+		series = append(series[:top], other)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"alias":       alias,
+		"granularity": granularity,
+		"dimension":   dimension,
+		"from":        from.Format(time.RFC3339),
+		"to":          to.Format(time.RFC3339),
+		"buckets":     buckets,
+		"series":      series,
+	})
+}
+
+func parseSeriesTime(v string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, v); err == nil {
+		return t.UTC(), nil
+	}
+	return time.Parse("2006-01-02", v)
+}
@@ -0,0 +1,162 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/afuzapratama/nexuslink/internal/audit"
+	"github.com/afuzapratama/nexuslink/internal/models"
+	"github.com/afuzapratama/nexuslink/internal/repository"
+	"github.com/google/uuid"
+)
+
+const (
+	auditDefaultLimit = 100
+	auditMaxLimit     = 1000
+)
+
+// Auditor mencatat perubahan administratif ke audit store (append-only).
+// Nil-safe: handler tanpa auditor tetap jalan (mis. di test).
+type Auditor struct {
+	store repository.AuditStore
+}
+
+func NewAuditor(store repository.AuditStore) *Auditor {
+	return &Auditor{store: store}
+}
+
+// Record mencatat satu perubahan. before nil = create, after nil = delete.
+// Gagal simpan hanya di-log; perubahan yang sudah terjadi tidak di-rollback.
+func (a *Auditor) Record(r *http.Request, action, targetType, targetID string, before, after interface{}) {
+	if a == nil || a.store == nil {
+		return
+	}
+
+	rec := &models.AuditRecord{
+		ID:         uuid.NewString(),
+		At:         time.Now().UTC(),
+		Action:     action,
+		TargetType: targetType,
+		TargetID:   targetID,
+		Changes:    audit.Diff(before, after),
+	}
+	rec.Actor, rec.ActorRole = actorFromRequest(r)
+	rec.SourceIP, rec.ProxyIP = sourceIP(r)
+
+	// Jangan pakai r.Context(): audit tetap ditulis walau client sudah disconnect
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := a.store.Append(ctx, rec); err != nil {
+		log.Printf("audit: append %s %s/%s failed: %v", action, targetType, targetID, err)
+	}
+}
+
+// actorFromRequest: user session, key node, atau admin key (tanpa session)
+func actorFromRequest(r *http.Request) (string, string) {
+	if u := UserFromContext(r.Context()); u != nil {
+		return u.Username, u.Role
+	}
+	if nodeID := NodeIDFromContext(r.Context()); nodeID != "" {
+		return "node:" + nodeID, ""
+	}
+	return "api-key", models.RoleOwner
+}
+
+// sourceIP returns IP client asli. Di belakang dashboard (BFF) IP browser dikirim lewat
+// X-Forwarded-For; RemoteAddr lalu dicatat sebagai proxy.
+func sourceIP(r *http.Request) (string, string) {
+	remote, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		remote = r.RemoteAddr
+	}
+	if xff := r.Header.Get("X-Forwarded-For"); xff != "" {
+		if ip := strings.TrimSpace(strings.Split(xff, ",")[0]); ip != "" {
+			return ip, remote
+		}
+	}
+	return remote, ""
+}
+
+// AuditHandler serves GET /admin/audit
+type AuditHandler struct {
+	store repository.AuditStore
+}
+
+func NewAuditHandler(store repository.AuditStore) *AuditHandler {
+	return &AuditHandler{store: store}
+}
+
+// HandleList handles GET /admin/audit?actor=&action=&targetType=&targetId=&since=&until=&limit=&format=jsonl
+// since/until RFC3339. format=jsonl meng-export semua record yang cocok (tanpa limit) sebagai JSON Lines.
+func (h *AuditHandler) HandleList(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	q := r.URL.Query()
+	f := models.AuditFilter{
+		Actor:      strings.TrimSpace(q.Get("actor")),
+		Action:     strings.TrimSpace(q.Get("action")),
+		TargetType: strings.TrimSpace(q.Get("targetType")),
+		TargetID:   strings.TrimSpace(q.Get("targetId")),
+		Limit:      auditDefaultLimit,
+	}
+	for _, p := range []struct {
+		name string
+		dst  *time.Time
+	}{{"since", &f.Since}, {"until", &f.Until}} {
+		if v := q.Get(p.name); v != "" {
+			t, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				http.Error(w, "invalid "+p.name+" format (use RFC3339)", http.StatusBadRequest)
+				return
+			}
+			*p.dst = t
+		}
+	}
+	if v := q.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 || n > auditMaxLimit {
+			http.Error(w, "limit must be between 1 and 1000", http.StatusBadRequest)
+			return
+		}
+		f.Limit = n
+	}
+
+	export := q.Get("format") == "jsonl"
+	if export {
+		f.Limit = 0
+	}
+
+	records, err := h.store.List(r.Context(), f)
+	if err != nil {
+		log.Printf("audit: list failed: %v", err)
+		http.Error(w, "failed to list audit records", http.StatusInternalServerError)
+		return
+	}
+
+	if export {
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		w.Header().Set("Content-Disposition", `attachment; filename="nexuslink-audit-`+time.Now().UTC().Format("20060102-150405")+`.jsonl"`)
+		enc := json.NewEncoder(w)
+		for i := range records {
+			if err := enc.Encode(records[i]); err != nil {
+				return
+			}
+		}
+		return
+	}
+
+	if records == nil {
+		records = []models.AuditRecord{}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(records)
+}
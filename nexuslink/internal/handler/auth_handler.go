@@ -3,26 +3,43 @@ package handler
 import (
 	"context"
 	"crypto/rand"
+	"crypto/sha256"
 	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
+	"log"
 	"net/http"
+	"strings"
 	"sync"
 	"time"
 
+	"github.com/afuzapratama/nexuslink/internal/models"
 	"github.com/afuzapratama/nexuslink/internal/repository"
+	"github.com/google/uuid"
 	"golang.org/x/crypto/bcrypt"
 )
 
+// sessionCacheTTL: session → user di-cache sebentar supaya tiap request admin tidak query store.
+// Logout / hapus user di instance lain berlaku paling lambat setelah TTL ini.
+const sessionCacheTTL = 30 * time.Second
+
+// sessionTouchInterval: expiry session diperpanjang paling sering sekali per interval ini
+const sessionTouchInterval = time.Hour
+
+// AuthHandler menangani login dashboard. User dan session disimpan di store
+// (bukan memory), jadi session tetap valid setelah restart dan antar replica API.
 type AuthHandler struct {
-	settingsRepo *repository.SettingsRepository
-	sessions     map[string]*Session // sessionToken -> Session
-	mu           sync.RWMutex
+	users    repository.UserStore
+	sessions repository.SessionStore
+
+	mu    sync.Mutex
+	cache map[string]cachedSession // tokenHash -> session + user
 }
 
-type Session struct {
-	Username  string
-	CreatedAt time.Time
-	ExpiresAt time.Time
+type cachedSession struct {
+	user      *models.User
+	expiresAt time.Time
+	fetchedAt time.Time
 }
 
 type LoginRequest struct {
@@ -39,18 +56,41 @@ type LoginResponse struct {
 type SessionResponse struct {
 	Authenticated bool   `json:"authenticated"`
 	Username      string `json:"username,omitempty"`
+	Role          string `json:"role,omitempty"`
 }
 
-func NewAuthHandler(settingsRepo *repository.SettingsRepository) *AuthHandler {
-	h := &AuthHandler{
-		settingsRepo: settingsRepo,
-		sessions:     make(map[string]*Session),
+func NewAuthHandler(users repository.UserStore, sessions repository.SessionStore) *AuthHandler {
+	return &AuthHandler{
+		users:    users,
+		sessions: sessions,
+		cache:    make(map[string]cachedSession),
 	}
+}
 
-	// Cleanup expired sessions setiap 1 jam
-	go h.cleanupExpiredSessions()
+// Bootstrap membuat user owner dari kredensial lama di Settings kalau belum ada user sama sekali
+func (h *AuthHandler) Bootstrap(ctx context.Context, settings *models.Settings) error {
+	users, err := h.users.List(ctx)
+	if err != nil {
+		return err
+	}
+	if len(users) > 0 || settings == nil || settings.AdminUsername == "" || settings.AdminPassword == "" {
+		return nil
+	}
 
-	return h
+	now := time.Now().UTC()
+	owner := &models.User{
+		ID:           uuid.NewString(),
+		Username:     settings.AdminUsername,
+		PasswordHash: settings.AdminPassword, // sudah bcrypt
+		Role:         models.RoleOwner,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+	if err := h.users.Create(ctx, owner); err != nil {
+		return err
+	}
+	log.Printf("✅ Owner user %q created from settings credentials", owner.Username)
+	return nil
 }
 
 // HandleLogin handles POST /auth/login
@@ -71,23 +111,16 @@ func (h *AuthHandler) HandleLogin(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Get settings from DB
-	ctx := context.Background()
-	settings := h.settingsRepo.GetOrDefault(ctx)
-
-	// Verify username
-	if req.Username != settings.AdminUsername {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusUnauthorized)
-		json.NewEncoder(w).Encode(LoginResponse{
-			Success: false,
-			Message: "Invalid username or password",
-		})
+	ctx := r.Context()
+	user, err := h.users.GetByUsername(ctx, req.Username)
+	if err != nil {
+		log.Printf("auth: load user %q failed: %v", req.Username, err)
+		http.Error(w, "internal error", http.StatusInternalServerError)
 		return
 	}
 
-	// Verify password (bcrypt compare)
-	if err := bcrypt.CompareHashAndPassword([]byte(settings.AdminPassword), []byte(req.Password)); err != nil {
+	// Verify username + password (bcrypt compare)
+	if user == nil || bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(req.Password)) != nil {
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusUnauthorized)
 		json.NewEncoder(w).Encode(LoginResponse{
@@ -97,17 +130,25 @@ func (h *AuthHandler) HandleLogin(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Generate session token
-	token := h.generateSessionToken()
-	session := &Session{
-		Username:  req.Username,
-		CreatedAt: time.Now().UTC(),
-		ExpiresAt: time.Now().UTC().Add(24 * time.Hour), // 24 jam
+	// Generate session token (yang disimpan hanya hash-nya)
+	token := generateSessionToken()
+	now := time.Now().UTC()
+	session := &models.Session{
+		TokenHash: hashSessionToken(token),
+		UserID:    user.ID,
+		CreatedAt: now,
+		ExpiresAt: now.Add(models.SessionTTL),
+	}
+	if err := h.sessions.Create(ctx, session); err != nil {
+		log.Printf("auth: create session for %q failed: %v", user.Username, err)
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
 	}
 
-	h.mu.Lock()
-	h.sessions[token] = session
-	h.mu.Unlock()
+	user.LastLoginAt = now
+	if err := h.users.Update(ctx, user); err != nil {
+		log.Printf("auth: update last login for %q failed: %v", user.Username, err)
+	}
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(LoginResponse{
@@ -124,27 +165,16 @@ func (h *AuthHandler) HandleLogout(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Get token dari header Authorization
-	token := r.Header.Get("Authorization")
-	if token == "" {
-		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode(map[string]interface{}{
-			"success": true,
-			"message": "Logged out",
-		})
-		return
-	}
-
-	// Remove "Bearer " prefix jika ada
-	if len(token) > 7 && token[:7] == "Bearer " {
-		token = token[7:]
+	if token := bearerToken(r); token != "" {
+		tokenHash := hashSessionToken(token)
+		if err := h.sessions.Delete(r.Context(), tokenHash); err != nil {
+			log.Printf("auth: delete session failed: %v", err)
+			http.Error(w, "internal error", http.StatusInternalServerError)
+			return
+		}
+		h.evict(tokenHash)
 	}
 
-	// Delete session
-	h.mu.Lock()
-	delete(h.sessions, token)
-	h.mu.Unlock()
-
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(map[string]interface{}{
 		"success": true,
@@ -159,112 +189,118 @@ func (h *AuthHandler) HandleSession(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	token := r.Header.Get("Authorization")
-	if token == "" {
-		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode(SessionResponse{
-			Authenticated: false,
-		})
-		return
-	}
-
-	// Remove "Bearer " prefix
-	if len(token) > 7 && token[:7] == "Bearer " {
-		token = token[7:]
-	}
-
-	session := h.getSession(token)
-	if session == nil {
-		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode(SessionResponse{
-			Authenticated: false,
-		})
-		return
-	}
-
-	// Check if expired
-	if time.Now().UTC().After(session.ExpiresAt) {
-		h.mu.Lock()
-		delete(h.sessions, token)
-		h.mu.Unlock()
-
-		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode(SessionResponse{
-			Authenticated: false,
-		})
-		return
+	var resp SessionResponse
+	if token := bearerToken(r); token != "" {
+		user, err := h.userForToken(r.Context(), token)
+		if err != nil {
+			log.Printf("auth: session lookup failed: %v", err)
+		}
+		if user != nil {
+			resp = SessionResponse{Authenticated: true, Username: user.Username, Role: user.Role}
+		}
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(SessionResponse{
-		Authenticated: true,
-		Username:      session.Username,
-	})
+	json.NewEncoder(w).Encode(resp)
 }
 
-// WithAuth middleware untuk protect endpoint
+// WithAuth middleware untuk endpoint yang butuh session login (user ada di context)
 func (h *AuthHandler) WithAuth(next http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		token := r.Header.Get("Authorization")
+		token := bearerToken(r)
 		if token == "" {
 			http.Error(w, "Unauthorized", http.StatusUnauthorized)
 			return
 		}
 
-		// Remove "Bearer " prefix
-		if len(token) > 7 && token[:7] == "Bearer " {
-			token = token[7:]
+		user, err := h.userForToken(r.Context(), token)
+		if err != nil {
+			log.Printf("auth: session lookup failed: %v", err)
+			http.Error(w, "internal error", http.StatusInternalServerError)
+			return
 		}
-
-		session := h.getSession(token)
-		if session == nil {
+		if user == nil {
 			http.Error(w, "Unauthorized", http.StatusUnauthorized)
 			return
 		}
 
-		// Check if expired
-		if time.Now().UTC().After(session.ExpiresAt) {
-			h.mu.Lock()
-			delete(h.sessions, token)
-			h.mu.Unlock()
+		next(w, r.WithContext(context.WithValue(r.Context(), userCtxKey, user)))
+	}
+}
 
-			http.Error(w, "Session expired", http.StatusUnauthorized)
-			return
+// InvalidateUser membuang cache session milik user (panggil setelah role user berubah)
+func (h *AuthHandler) InvalidateUser(userID string) {
+	h.mu.Lock()
+	for tokenHash, c := range h.cache {
+		if c.user.ID == userID {
+			delete(h.cache, tokenHash)
 		}
+	}
+	h.mu.Unlock()
+}
 
-		// Extend session expiry (activity-based)
-		h.mu.Lock()
-		session.ExpiresAt = time.Now().UTC().Add(24 * time.Hour)
-		h.mu.Unlock()
+// RevokeUser menghapus semua session user (dipanggil saat user dihapus / ganti password)
+func (h *AuthHandler) RevokeUser(ctx context.Context, userID string) error {
+	h.InvalidateUser(userID)
+	return h.sessions.DeleteByUser(ctx, userID)
+}
+
+// userForToken returns user pemilik session (nil kalau session tidak ada / expired / user sudah dihapus).
+// Expiry session ikut diperpanjang (activity-based).
+func (h *AuthHandler) userForToken(ctx context.Context, token string) (*models.User, error) {
+	tokenHash := hashSessionToken(token)
+	now := time.Now().UTC()
+
+	h.mu.Lock()
+	c, ok := h.cache[tokenHash]
+	h.mu.Unlock()
+	if ok && now.Sub(c.fetchedAt) < sessionCacheTTL && now.Before(c.expiresAt) {
+		return c.user, nil
+	}
 
-		next(w, r)
+	session, err := h.sessions.Get(ctx, tokenHash)
+	if err != nil || session == nil {
+		h.evict(tokenHash)
+		return nil, err
+	}
+	user, err := h.users.Get(ctx, session.UserID)
+	if err != nil || user == nil {
+		h.evict(tokenHash)
+		return nil, err
+	}
+
+	// Perpanjang expiry, tapi jangan tulis ke store di setiap request
+	if session.ExpiresAt.Sub(now) < models.SessionTTL-sessionTouchInterval {
+		session.ExpiresAt = now.Add(models.SessionTTL)
+		if err := h.sessions.Touch(ctx, tokenHash, session.ExpiresAt); err != nil {
+			log.Printf("auth: extend session failed: %v", err)
+		}
 	}
+
+	h.mu.Lock()
+	h.cache[tokenHash] = cachedSession{user: user, expiresAt: session.ExpiresAt, fetchedAt: now}
+	h.mu.Unlock()
+	return user, nil
 }
 
-func (h *AuthHandler) getSession(token string) *Session {
-	h.mu.RLock()
-	defer h.mu.RUnlock()
-	return h.sessions[token]
+func (h *AuthHandler) evict(tokenHash string) {
+	h.mu.Lock()
+	delete(h.cache, tokenHash)
+	h.mu.Unlock()
 }
 
-func (h *AuthHandler) generateSessionToken() string {
+// bearerToken ambil token dari header Authorization ("Bearer " prefix opsional)
+func bearerToken(r *http.Request) string {
+	return strings.TrimSpace(strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer "))
+}
+
+func generateSessionToken() string {
 	b := make([]byte, 32)
 	rand.Read(b)
 	return base64.URLEncoding.EncodeToString(b)
 }
 
-func (h *AuthHandler) cleanupExpiredSessions() {
-	ticker := time.NewTicker(1 * time.Hour)
-	defer ticker.Stop()
-
-	for range ticker.C {
-		now := time.Now().UTC()
-		h.mu.Lock()
-		for token, session := range h.sessions {
-			if now.After(session.ExpiresAt) {
-				delete(h.sessions, token)
-			}
-		}
-		h.mu.Unlock()
-	}
+func hashSessionToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
 }
@@ -0,0 +1,176 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/afuzapratama/nexuslink/internal/edge"
+	"github.com/afuzapratama/nexuslink/internal/models"
+	"github.com/afuzapratama/nexuslink/internal/nodeauth"
+	"github.com/afuzapratama/nexuslink/internal/repository"
+)
+
+// Long-poll: request ditahan maksimal domainWatchTimeout. Store dicek ulang tiap
+// domainWatchRecheck supaya perubahan dari instance API lain tetap terlihat.
+const (
+	domainWatchTimeout = 25 * time.Second
+	domainWatchRecheck = 5 * time.Second
+)
+
+// DomainFeedHandler mem-push daftar domain node ke agent lewat long-poll (ditandatangani HMAC)
+type DomainFeedHandler struct {
+	nodeRepo repository.NodeStore
+	secret   string
+
+	mu      sync.Mutex
+	waiters map[string][]chan struct{} // nodeID → long-poll yang sedang menunggu
+}
+
+func NewDomainFeedHandler(nodeRepo repository.NodeStore, secret string) *DomainFeedHandler {
+	return &DomainFeedHandler{
+		nodeRepo: nodeRepo,
+		secret:   secret,
+		waiters:  make(map[string][]chan struct{}),
+	}
+}
+
+// Notify membangunkan long-poll agent node ini (panggil setelah domain node berubah)
+func (h *DomainFeedHandler) Notify(nodeID string) {
+	h.mu.Lock()
+	waiters := h.waiters[nodeID]
+	delete(h.waiters, nodeID)
+	h.mu.Unlock()
+
+	for _, ch := range waiters {
+		close(ch)
+	}
+}
+
+func (h *DomainFeedHandler) subscribe(nodeID string) chan struct{} {
+	ch := make(chan struct{})
+	h.mu.Lock()
+	h.waiters[nodeID] = append(h.waiters[nodeID], ch)
+	h.mu.Unlock()
+	return ch
+}
+
+func (h *DomainFeedHandler) unsubscribe(nodeID string, ch chan struct{}) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	list := h.waiters[nodeID]
+	for i, c := range list {
+		if c == ch {
+			h.waiters[nodeID] = append(list[:i], list[i+1:]...)
+			break
+		}
+	}
+	if len(h.waiters[nodeID]) == 0 {
+		delete(h.waiters, nodeID)
+	}
+}
+
+// GET /nodes/domains?nodeId=X&version=N
+// 200 + feed bertanda tangan kalau DomainsVersion node > N (langsung, atau begitu berubah;
+// agent tanpa feed mengirim version=-1),
+// 304 kalau tidak ada perubahan sampai timeout, 404 kalau node tidak ada.
+func (h *DomainFeedHandler) HandleWatch(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	nodeID := r.URL.Query().Get("nodeId")
+	if nodeID == "" {
+		http.Error(w, "nodeId is required", http.StatusBadRequest)
+		return
+	}
+	if credNodeID := NodeIDFromContext(r.Context()); credNodeID != "" && credNodeID != nodeID {
+		http.Error(w, "node credential does not match nodeId", http.StatusForbidden)
+		return
+	}
+	since, err := strconv.ParseInt(r.URL.Query().Get("version"), 10, 64)
+	if err != nil {
+		since = -1
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), domainWatchTimeout)
+	defer cancel()
+
+	recheck := time.NewTicker(domainWatchRecheck)
+	defer recheck.Stop()
+
+	for {
+		// Subscribe dulu baru baca store, supaya Notify di antaranya tidak terlewat
+		ch := h.subscribe(nodeID)
+
+		node, err := h.nodeRepo.GetByID(ctx, nodeID)
+		if err != nil {
+			h.unsubscribe(nodeID, ch)
+			if ctx.Err() != nil {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			log.Printf("domain feed: node=%s err=%v", nodeID, err)
+			http.Error(w, "internal error", http.StatusInternalServerError)
+			return
+		}
+		if node == nil {
+			h.unsubscribe(nodeID, ch)
+			http.Error(w, "node not found", http.StatusNotFound)
+			return
+		}
+
+		if node.DomainsVersion > since {
+			h.unsubscribe(nodeID, ch)
+			h.writeFeed(w, node, h.signingSecret(r))
+			return
+		}
+
+		select {
+		case <-ch:
+		case <-recheck.C:
+			h.unsubscribe(nodeID, ch)
+		case <-ctx.Done():
+			h.unsubscribe(nodeID, ch)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+	}
+}
+
+// signingSecret: request dengan key node ditandatangani hash key itu (agent bisa hitung sendiri,
+// tidak perlu secret bersama); selain itu pakai secret bersama
+func (h *DomainFeedHandler) signingSecret(r *http.Request) string {
+	if NodeIDFromContext(r.Context()) != "" {
+		return nodeauth.HashKey(r.Header.Get("X-Nexus-Api-Key"))
+	}
+	return h.secret
+}
+
+func (h *DomainFeedHandler) writeFeed(w http.ResponseWriter, node *models.Node, secret string) {
+	domains := node.Domains
+	if domains == nil {
+		domains = []string{}
+	}
+
+	body, err := json.Marshal(models.NodeDomainFeed{
+		NodeID:   node.ID,
+		Version:  node.DomainsVersion,
+		Domains:  domains,
+		IssuedAt: time.Now().UTC(),
+	})
+	if err != nil {
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set(edge.DomainSignatureHeader, edge.SignDomainFeed(secret, body))
+	w.Write(body)
+}
@@ -3,6 +3,7 @@ package handler
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
@@ -10,33 +11,50 @@ import (
 	"strings"
 	"time"
 
+	aliaspkg "github.com/afuzapratama/nexuslink/internal/alias"
 	"github.com/afuzapratama/nexuslink/internal/models"
+	"github.com/afuzapratama/nexuslink/internal/ratelimit"
 	"github.com/afuzapratama/nexuslink/internal/repository"
+	"github.com/afuzapratama/nexuslink/internal/rules"
+	"github.com/afuzapratama/nexuslink/internal/steering"
 	"github.com/afuzapratama/nexuslink/internal/webhook"
+	"github.com/google/uuid"
 	"github.com/skip2/go-qrcode"
 )
 
 type LinkHandler struct {
-	linkRepo      *repository.LinkRepository
-	statsRepo     *repository.LinkStatsRepository
-	clickRepo     *repository.ClickRepository
-	webhookRepo   *repository.WebhookRepository
-	webhookSender *webhook.Sender
+	linkRepo     repository.LinkStore
+	statsRepo    repository.StatsStore
+	clickRepo    repository.ClickStore
+	webhooks     *webhook.Dispatcher
+	nodeRepo     repository.NodeStore
+	variantRepo  repository.VariantStore
+	revisions    repository.LinkRevisionStore
+	settingsRepo repository.SettingsStore
+	audit        *Auditor
 }
 
 func NewLinkHandler(
-	linkRepo *repository.LinkRepository,
-	statsRepo *repository.LinkStatsRepository,
-	clickRepo *repository.ClickRepository,
-	webhookRepo *repository.WebhookRepository,
-	webhookSender *webhook.Sender,
+	linkRepo repository.LinkStore,
+	statsRepo repository.StatsStore,
+	clickRepo repository.ClickStore,
+	webhooks *webhook.Dispatcher,
+	nodeRepo repository.NodeStore,
+	variantRepo repository.VariantStore,
+	revisions repository.LinkRevisionStore,
+	settingsRepo repository.SettingsStore,
+	auditor *Auditor,
 ) *LinkHandler {
 	return &LinkHandler{
-		linkRepo:      linkRepo,
-		statsRepo:     statsRepo,
-		clickRepo:     clickRepo,
-		webhookRepo:   webhookRepo,
-		webhookSender: webhookSender,
+		linkRepo:     linkRepo,
+		statsRepo:    statsRepo,
+		clickRepo:    clickRepo,
+		webhooks:     webhooks,
+		nodeRepo:     nodeRepo,
+		variantRepo:  variantRepo,
+		revisions:    revisions,
+		settingsRepo: settingsRepo,
+		audit:        auditor,
 	}
 }
 
@@ -53,6 +71,15 @@ func (h *LinkHandler) HandleLinks(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *LinkHandler) listLinks(w http.ResponseWriter, r *http.Request) {
+	groupID := r.URL.Query().Get("groupId")
+
+	// Cursor mode: ?cursor=&limit= (groupId difilter di DynamoDB)
+	if cursor, limit, ok := CursorParams(r, 10, 100); ok {
+		links, next, err := h.linkRepo.ListPage(r.Context(), groupID, limit, cursor)
+		WriteCursorPage(w, links, limit, next, err)
+		return
+	}
+
 	// Parse pagination params
 	page := 1
 	limit := 10
@@ -74,7 +101,6 @@ func (h *LinkHandler) listLinks(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Filter by groupId if provided (note: filtering after pagination, untuk simplicity)
-	groupID := r.URL.Query().Get("groupId")
 	if groupID != "" {
 		var filtered []models.Link
 		for _, link := range links {
@@ -103,11 +129,12 @@ func (h *LinkHandler) listLinks(w http.ResponseWriter, r *http.Request) {
 
 func (h *LinkHandler) createLink(w http.ResponseWriter, r *http.Request) {
 	var input struct {
-		Alias     string `json:"alias"`
-		TargetURL string `json:"targetUrl"`
-		NodeID    string `json:"nodeId"`
-		GroupID   string `json:"groupId"`
-		Domain    string `json:"domain"`
+		Alias     string   `json:"alias"`
+		TargetURL string   `json:"targetUrl"`
+		NodeID    string   `json:"nodeId"`
+		GroupID   string   `json:"groupId"`
+		Domain    string   `json:"domain"`
+		Domains   []string `json:"domains"`
 
 		AllowedOS        []string `json:"allowedOs"`
 		AllowedDevices   []string `json:"allowedDevices"`
@@ -119,6 +146,13 @@ func (h *LinkHandler) createLink(w http.ResponseWriter, r *http.Request) {
 		MaxClicks        *int     `json:"maxClicks"`
 		ActiveFrom       *string  `json:"activeFrom"`
 		ActiveUntil      *string  `json:"activeUntil"`
+
+		DisabledBehavior string `json:"disabledBehavior"`
+		DisabledMessage  string `json:"disabledMessage"`
+
+		Rules []models.LinkRule `json:"rules"`
+
+		RateLimit *models.LinkRateLimit `json:"rateLimit"`
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
@@ -129,17 +163,31 @@ func (h *LinkHandler) createLink(w http.ResponseWriter, r *http.Request) {
 	alias := strings.TrimSpace(input.Alias)
 	target := strings.TrimSpace(input.TargetURL)
 
-	if alias == "" || target == "" {
-		http.Error(w, "alias and targetUrl required", http.StatusBadRequest)
+	if target == "" {
+		http.Error(w, "targetUrl required", http.StatusBadRequest)
 		return
 	}
 
+	// Alias kosong = di-generate sesuai policy domain (lihat createWithGeneratedAlias)
+	aliasSettings := h.settingsRepo.GetOrDefault(r.Context()).Aliases
+	var blocked []string
+	if aliasSettings != nil {
+		blocked = aliasSettings.Blocked
+	}
+	if alias != "" {
+		if err := aliaspkg.Validate(alias, blocked); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
 	link := &models.Link{
 		Alias:     alias,
 		TargetURL: target,
 		NodeID:    strings.TrimSpace(input.NodeID),
 		GroupID:   strings.TrimSpace(input.GroupID),
 		Domain:    strings.TrimSpace(input.Domain),
+		Domains:   normalizeDomains(input.Domains),
 
 		AllowedOS:        input.AllowedOS,
 		AllowedDevices:   input.AllowedDevices,
@@ -147,8 +195,33 @@ func (h *LinkHandler) createLink(w http.ResponseWriter, r *http.Request) {
 		AllowedCountries: input.AllowedCountries,
 		BlockBots:        input.BlockBots,
 		FallbackURL:      strings.TrimSpace(input.FallbackURL),
+
+		DisabledBehavior: strings.TrimSpace(input.DisabledBehavior),
+		DisabledMessage:  strings.TrimSpace(input.DisabledMessage),
+
+		RateLimit: input.RateLimit,
+	}
+	if link.RateLimit != nil && *link.RateLimit == (models.LinkRateLimit{}) {
+		link.RateLimit = nil
+	}
+
+	if err := validateDisabledBehavior(link.DisabledBehavior); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
 	}
 
+	if err := validateRateLimit(link.RateLimit); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	linkRules, err := normalizeRules(input.Rules)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	link.Rules = linkRules
+
 	log.Printf("Creating link: alias=%s, allowedCountries=%v, len=%d", alias, input.AllowedCountries, len(input.AllowedCountries))
 	log.Printf("Link struct allowedCountries=%v", link.AllowedCountries)
 
@@ -193,15 +266,29 @@ func (h *LinkHandler) createLink(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.linkRepo.Create(r.Context(), link); err != nil {
+	link.Revision = 1
+	link.UpdatedBy, _ = actorFromRequest(r)
+
+	if alias != "" {
+		err = h.linkRepo.Create(r.Context(), link)
+	} else {
+		err = h.createWithGeneratedAlias(r.Context(), link, aliasSettings)
+	}
+	if errors.Is(err, repository.ErrAliasTaken) {
+		http.Error(w, "alias already taken on this domain", http.StatusConflict)
+		return
+	}
+	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
+	h.audit.Record(r, "link.create", "link", link.DataKey(), nil, link)
 
 	// Trigger link.created webhook
-	go h.triggerWebhook(r.Context(), models.EventLinkCreated, map[string]interface{}{
+	h.triggerWebhook(r.Context(), models.EventLinkCreated, map[string]interface{}{
 		"linkId":    link.ID,
 		"alias":     link.Alias,
+		"domain":    link.Domain,
 		"targetUrl": link.TargetURL,
 		"groupId":   link.GroupID,
 		"timestamp": time.Now().Format(time.RFC3339),
@@ -212,6 +299,157 @@ func (h *LinkHandler) createLink(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(link)
 }
 
+// maxAliasAttempts: batas retry generate alias kalau bentrok / kena kata blocked
+const maxAliasAttempts = 10
+
+// createWithGeneratedAlias generate alias sesuai policy domain link lalu Create (atomic di store).
+// Alias yang bentrok dicoba ulang; random/words makin panjang setiap 3 kali bentrok.
+func (h *LinkHandler) createWithGeneratedAlias(ctx context.Context, link *models.Link, settings *models.AliasSettings) error {
+	policy := settings.PolicyFor(link.Domain)
+	var blocked []string
+	if settings != nil {
+		blocked = settings.Blocked
+	}
+	next := func() (int64, error) {
+		return h.linkRepo.NextAliasSequence(ctx, strings.ToLower(link.Domain))
+	}
+
+	for attempt := 0; attempt < maxAliasAttempts; attempt++ {
+		candidate, err := aliaspkg.Generate(policy, attempt/3, next)
+		if err != nil {
+			return err
+		}
+		if aliaspkg.IsReserved(candidate) || aliaspkg.ContainsBlocked(candidate, blocked) {
+			continue
+		}
+
+		link.Alias = candidate
+		err = h.linkRepo.Create(ctx, link)
+		if errors.Is(err, repository.ErrAliasTaken) {
+			continue
+		}
+		return err
+	}
+	return fmt.Errorf("could not generate a free alias after %d attempts", maxAliasAttempts)
+}
+
+// normalizeRules memvalidasi rules dari request dan mengisi default (ID, match mode)
+func normalizeRules(linkRules []models.LinkRule) ([]models.LinkRule, error) {
+	if err := rules.Validate(linkRules); err != nil {
+		return nil, err
+	}
+
+	for i := range linkRules {
+		if linkRules[i].ID == "" {
+			linkRules[i].ID = uuid.NewString()
+		}
+		if linkRules[i].Match == "" {
+			linkRules[i].Match = models.RuleMatchAll
+		}
+		linkRules[i].Action.URL = strings.TrimSpace(linkRules[i].Action.URL)
+	}
+
+	return linkRules, nil
+}
+
+// validateDisabledBehavior memastikan disabledBehavior salah satu dari 404, 410, fallback (kosong = 404)
+func validateDisabledBehavior(behavior string) error {
+	switch behavior {
+	case "", models.DisabledBehaviorNotFound, models.DisabledBehaviorGone, models.DisabledBehaviorFallback:
+		return nil
+	}
+	return fmt.Errorf("disabledBehavior must be one of %s, %s, %s",
+		models.DisabledBehaviorNotFound, models.DisabledBehaviorGone, models.DisabledBehaviorFallback)
+}
+
+// validateRateLimit memvalidasi override rate limit per link (nil = ikut global)
+func validateRateLimit(rl *models.LinkRateLimit) error {
+	if rl == nil {
+		return nil
+	}
+	if rl.PerIP < 0 || rl.PerLink < 0 || rl.Window < 0 {
+		return fmt.Errorf("rateLimit values must not be negative")
+	}
+	rl.Action = strings.TrimSpace(rl.Action)
+	if !ratelimit.ValidAction(rl.Action) {
+		return fmt.Errorf("rateLimit.action must be one of %s, %s, %s",
+			models.RateLimitActionReject, models.RateLimitActionFallback, models.RateLimitActionChallenge)
+	}
+	rl.Algorithm = strings.TrimSpace(rl.Algorithm)
+	if !ratelimit.ValidAlgorithm(rl.Algorithm) {
+		return fmt.Errorf("rateLimit.algorithm must be one of %s, %s, %s",
+			ratelimit.AlgorithmSlidingLog, ratelimit.AlgorithmSlidingWindow, ratelimit.AlgorithmGCRA)
+	}
+	return nil
+}
+
+// normalizeDomains trim + lowercase, buang yang kosong / duplikat
+func normalizeDomains(domains []string) []string {
+	var out []string
+	seen := make(map[string]bool)
+	for _, d := range domains {
+		d = strings.ToLower(strings.TrimSpace(d))
+		if d == "" || seen[d] {
+			continue
+		}
+		seen[d] = true
+		out = append(out, d)
+	}
+	return out
+}
+
+// canonicalURL memilih domain sehat untuk link (region opsional, kode negara / region node)
+func (h *LinkHandler) canonicalURL(ctx context.Context, link *models.Link, region string) (steering.Candidate, bool, []steering.Candidate, error) {
+	nodes, err := h.nodeRepo.List(ctx)
+	if err != nil {
+		return steering.Candidate{}, false, nil, err
+	}
+	cands := steering.Candidates(link, nodes)
+	picked, ok := steering.Pick(cands, region, nil)
+	return picked, ok, cands, nil
+}
+
+// GET /links/:alias/url?region=ID - Canonical short URL di node yang sehat
+func (h *LinkHandler) HandleShortURL(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(parts) < 3 || parts[0] != "links" || parts[2] != "url" {
+		http.NotFound(w, r)
+		return
+	}
+	alias := parts[1]
+
+	link, ok := linkFromRequest(w, r, h.linkRepo, alias)
+	if !ok {
+		return
+	}
+
+	picked, ok, cands, err := h.canonicalURL(r.Context(), link, r.URL.Query().Get("region"))
+	if err != nil {
+		log.Printf("canonical url: alias=%s err=%v", alias, err)
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+	if picked.Domain == "" {
+		http.Error(w, "no domain available for this link", http.StatusServiceUnavailable)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"shortUrl":   steering.ShortURL(picked.Domain, link.Alias),
+		"domain":     picked.Domain,
+		"nodeId":     picked.NodeID,
+		"region":     picked.Region,
+		"degraded":   !ok, // tidak ada node sehat di pool
+		"candidates": cands,
+	})
+}
+
 // GET /links/:alias/qr - Generate QR code for link
 func (h *LinkHandler) HandleQRCode(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
@@ -229,13 +467,8 @@ func (h *LinkHandler) HandleQRCode(w http.ResponseWriter, r *http.Request) {
 	alias := parts[1]
 
 	// Check if link exists
-	link, err := h.linkRepo.GetByAlias(r.Context(), alias)
-	if err != nil {
-		http.Error(w, "internal error", http.StatusInternalServerError)
-		return
-	}
-	if link == nil {
-		http.NotFound(w, r)
+	link, ok := linkFromRequest(w, r, h.linkRepo, alias)
+	if !ok {
 		return
 	}
 
@@ -248,8 +481,18 @@ func (h *LinkHandler) HandleQRCode(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	// Build short URL (hardcoded for now, should be from config)
-	shortURL := fmt.Sprintf("https://yourdomain.com/r/%s", alias)
+	// QR mengarah ke canonical short URL (domain di node yang sehat)
+	picked, _, _, err := h.canonicalURL(r.Context(), link, r.URL.Query().Get("region"))
+	if err != nil {
+		log.Printf("qr: canonical url alias=%s err=%v", alias, err)
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+	if picked.Domain == "" {
+		http.Error(w, "no domain available for this link", http.StatusServiceUnavailable)
+		return
+	}
+	shortURL := steering.ShortURL(picked.Domain, link.Alias)
 
 	// Generate QR code
 	png, err := qrcode.Encode(shortURL, qrcode.Medium, size)
@@ -259,7 +502,7 @@ func (h *LinkHandler) HandleQRCode(w http.ResponseWriter, r *http.Request) {
 	}
 
 	w.Header().Set("Content-Type", "image/png")
-	w.Header().Set("Cache-Control", "public, max-age=86400") // Cache for 1 day
+	w.Header().Set("Cache-Control", "public, max-age=300") // pendek: domain bisa berpindah kalau node down
 	w.Write(png)
 }
 
@@ -270,6 +513,7 @@ func (h *LinkHandler) HandleBulkToggle(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// aliases berisi key data link (alias, atau "domain/alias" untuk link ber-domain)
 	var input struct {
 		Aliases  []string `json:"aliases"`
 		IsActive bool     `json:"isActive"`
@@ -289,7 +533,7 @@ func (h *LinkHandler) HandleBulkToggle(w http.ResponseWriter, r *http.Request) {
 	updated := 0
 	failed := 0
 	for _, alias := range input.Aliases {
-		if err := h.toggleLink(r.Context(), alias, input.IsActive); err != nil {
+		if err := h.toggleLink(r, alias, input.IsActive); err != nil {
 			failed++
 			log.Printf("Failed to toggle link %s: %v", alias, err)
 		} else {
@@ -305,16 +549,24 @@ func (h *LinkHandler) HandleBulkToggle(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
-func (h *LinkHandler) toggleLink(ctx context.Context, alias string, isActive bool) error {
-	link, err := h.linkRepo.GetByAlias(ctx, alias)
-	if err != nil || link == nil {
-		return fmt.Errorf("link not found: %s", alias)
+func (h *LinkHandler) toggleLink(r *http.Request, key string, isActive bool) error {
+	link, err := findLinkByKey(r.Context(), h.linkRepo, key)
+	if err != nil {
+		return err
+	}
+	if link == nil {
+		return fmt.Errorf("link not found: %s", key)
 	}
 
+	before := *link
 	link.IsActive = isActive
-	link.UpdatedAt = time.Now()
+	link.RestoredFrom = 0
 
-	return h.linkRepo.Update(ctx, link)
+	if err := h.commitRevision(r, link, &before, nil); err != nil {
+		return err
+	}
+	h.audit.Record(r, "link.bulk_toggle", "link", link.DataKey(), &before, link)
+	return nil
 }
 
 // POST /links/bulk/delete - Bulk delete links
@@ -324,6 +576,7 @@ func (h *LinkHandler) HandleBulkDelete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// aliases berisi key data link (lihat HandleBulkToggle)
 	var input struct {
 		Aliases []string `json:"aliases"`
 	}
@@ -342,7 +595,7 @@ func (h *LinkHandler) HandleBulkDelete(w http.ResponseWriter, r *http.Request) {
 	deleted := 0
 	failed := 0
 	for _, alias := range input.Aliases {
-		link, err := h.linkRepo.GetByAlias(r.Context(), alias)
+		link, err := findLinkByKey(r.Context(), h.linkRepo, alias)
 		if err != nil || link == nil {
 			failed++
 			log.Printf("Failed to find link for deletion %s: %v", alias, err)
@@ -354,6 +607,10 @@ func (h *LinkHandler) HandleBulkDelete(w http.ResponseWriter, r *http.Request) {
 			log.Printf("Failed to delete link %s: %v", alias, err)
 			continue
 		}
+		h.audit.Record(r, "link.bulk_delete", "link", link.DataKey(), link, nil)
+		if err := h.revisions.DeleteByLink(r.Context(), link.ID); err != nil {
+			log.Printf("Warning: failed to delete revisions for link %s: %v", alias, err)
+		}
 		deleted++
 	}
 
@@ -365,42 +622,17 @@ func (h *LinkHandler) HandleBulkDelete(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
-// triggerWebhook triggers all active webhooks subscribed to an event
+// triggerWebhook menulis event ke outbox webhook; pengiriman + retry oleh webhook.Dispatcher
 func (h *LinkHandler) triggerWebhook(ctx context.Context, event string, data map[string]interface{}) {
-	webhooks, err := h.webhookRepo.GetByEvent(ctx, event)
-	if err != nil {
-		log.Printf("Failed to get webhooks for event %s: %v", event, err)
-		return
-	}
-
-	if len(webhooks) == 0 {
-		return // No webhooks subscribed to this event
-	}
-
-	payload := &models.WebhookPayload{
-		Event:     event,
-		Timestamp: time.Now(),
-		Data:      data,
-	}
-
-	// Send webhooks in background
-	for _, wh := range webhooks {
-		go func(w models.Webhook) {
-			result, err := h.webhookSender.SendWebhook(ctx, &w, payload)
-			if err != nil {
-				log.Printf("Webhook error: event=%s url=%s error=%v", event, w.URL, err)
-			} else if !result.Success {
-				log.Printf("Webhook failed: event=%s url=%s status=%d", event, w.URL, result.StatusCode)
-			} else {
-				log.Printf("Webhook sent: event=%s url=%s status=%d", event, w.URL, result.StatusCode)
-			}
-		}(wh)
+	if err := h.webhooks.Publish(ctx, event, data); err != nil {
+		log.Printf("Failed to enqueue webhooks for event %s: %v", event, err)
 	}
 }
 
 // HandleLinkByAlias handles PUT and DELETE for individual links
-// PUT /links/:alias - Update link
-// DELETE /links/:alias - Delete link
+// PUT /links/:alias?domain= - Update link
+// DELETE /links/:alias?domain= - Delete link
+// ?domain= wajib kalau alias dipakai di lebih dari satu domain.
 func (h *LinkHandler) HandleLinkByAlias(w http.ResponseWriter, r *http.Request) {
 	// Extract alias from URL path: /links/ALIAS
 	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
@@ -422,32 +654,34 @@ func (h *LinkHandler) HandleLinkByAlias(w http.ResponseWriter, r *http.Request)
 
 func (h *LinkHandler) updateLink(w http.ResponseWriter, r *http.Request, alias string) {
 	// Get existing link
-	existingLink, err := h.linkRepo.GetByAlias(r.Context(), alias)
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
-	}
-	if existingLink == nil {
-		http.Error(w, "link not found", http.StatusNotFound)
+	existingLink, ok := linkFromRequest(w, r, h.linkRepo, alias)
+	if !ok {
 		return
 	}
+	before := *existingLink
 
 	// Parse update request
 	var input struct {
-		TargetURL        string   `json:"targetUrl"`
-		NodeID           string   `json:"nodeId"`
-		GroupID          string   `json:"groupId"`
-		Domain           string   `json:"domain"`
-		AllowedOS        []string `json:"allowedOs"`
-		AllowedDevices   []string `json:"allowedDevices"`
-		AllowedBrowsers  []string `json:"allowedBrowsers"`
-		AllowedCountries []string `json:"allowedCountries"`
-		BlockBots        bool     `json:"blockBots"`
-		FallbackURL      string   `json:"fallbackUrl"`
-		ExpiresAt        *string  `json:"expiresAt"`
-		MaxClicks        *int     `json:"maxClicks"`
-		ActiveFrom       *string  `json:"activeFrom"`
-		ActiveUntil      *string  `json:"activeUntil"`
+		TargetURL        string             `json:"targetUrl"`
+		NodeID           string             `json:"nodeId"`
+		GroupID          string             `json:"groupId"`
+		Domain           string             `json:"domain"`
+		Domains          *[]string          `json:"domains"`
+		AllowedOS        []string           `json:"allowedOs"`
+		AllowedDevices   []string           `json:"allowedDevices"`
+		AllowedBrowsers  []string           `json:"allowedBrowsers"`
+		AllowedCountries []string           `json:"allowedCountries"`
+		BlockBots        bool               `json:"blockBots"`
+		FallbackURL      string             `json:"fallbackUrl"`
+		ExpiresAt        *string            `json:"expiresAt"`
+		MaxClicks        *int               `json:"maxClicks"`
+		ActiveFrom       *string            `json:"activeFrom"`
+		ActiveUntil      *string            `json:"activeUntil"`
+		DisabledBehavior *string            `json:"disabledBehavior"`
+		DisabledMessage  *string            `json:"disabledMessage"`
+		Rules            *[]models.LinkRule `json:"rules"`
+
+		RateLimit *models.LinkRateLimit `json:"rateLimit"`
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
@@ -455,6 +689,35 @@ func (h *LinkHandler) updateLink(w http.ResponseWriter, r *http.Request, alias s
 		return
 	}
 
+	// Domain bagian dari identitas link (alias unik per domain), sama seperti alias tidak bisa diganti
+	if domain := strings.TrimSpace(input.Domain); domain != "" && !strings.EqualFold(domain, existingLink.Domain) {
+		http.Error(w, "domain cannot be changed once created", http.StatusBadRequest)
+		return
+	}
+
+	// rules tidak dikirim = rules lama dipertahankan; kirim [] untuk menghapus semua rules
+	var linkRules []models.LinkRule
+	if input.Rules != nil {
+		var err error
+		if linkRules, err = normalizeRules(*input.Rules); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	// disabledBehavior / disabledMessage tidak dikirim = nilai lama dipertahankan
+	if input.DisabledBehavior != nil {
+		if err := validateDisabledBehavior(strings.TrimSpace(*input.DisabledBehavior)); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	if err := validateRateLimit(input.RateLimit); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
 	// Update fields
 	target := strings.TrimSpace(input.TargetURL)
 	if target == "" {
@@ -465,13 +728,32 @@ func (h *LinkHandler) updateLink(w http.ResponseWriter, r *http.Request, alias s
 	existingLink.TargetURL = target
 	existingLink.NodeID = strings.TrimSpace(input.NodeID)
 	existingLink.GroupID = strings.TrimSpace(input.GroupID)
-	existingLink.Domain = strings.TrimSpace(input.Domain)
+	// domains tidak dikirim = pool lama dipertahankan
+	if input.Domains != nil {
+		existingLink.Domains = normalizeDomains(*input.Domains)
+	}
 	existingLink.AllowedOS = input.AllowedOS
 	existingLink.AllowedDevices = input.AllowedDevices
 	existingLink.AllowedBrowsers = input.AllowedBrowsers
 	existingLink.AllowedCountries = input.AllowedCountries
 	existingLink.BlockBots = input.BlockBots
 	existingLink.FallbackURL = strings.TrimSpace(input.FallbackURL)
+	if input.Rules != nil {
+		existingLink.Rules = linkRules
+	}
+	if input.DisabledBehavior != nil {
+		existingLink.DisabledBehavior = strings.TrimSpace(*input.DisabledBehavior)
+	}
+	if input.DisabledMessage != nil {
+		existingLink.DisabledMessage = strings.TrimSpace(*input.DisabledMessage)
+	}
+	// rateLimit tidak dikirim = override lama dipertahankan; kirim {} untuk menghapus override
+	if input.RateLimit != nil {
+		existingLink.RateLimit = input.RateLimit
+		if *input.RateLimit == (models.LinkRateLimit{}) {
+			existingLink.RateLimit = nil
+		}
+	}
 
 	// Parse expiration
 	if input.ExpiresAt != nil && *input.ExpiresAt != "" {
@@ -522,18 +804,26 @@ func (h *LinkHandler) updateLink(w http.ResponseWriter, r *http.Request, alias s
 		return
 	}
 
-	// Update in database
-	if err := h.linkRepo.Update(r.Context(), existingLink); err != nil {
+	// Update in database (versi sebelumnya disimpan sebagai revision)
+	existingLink.RestoredFrom = 0
+	if err := h.commitRevision(r, existingLink, &before, nil); err != nil {
+		if errors.Is(err, repository.ErrRevisionConflict) {
+			http.Error(w, "link was modified by someone else, reload and try again", http.StatusConflict)
+			return
+		}
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
+	h.audit.Record(r, "link.update", "link", existingLink.DataKey(), &before, existingLink)
 
 	// Trigger link.updated webhook
-	go h.triggerWebhook(r.Context(), "link.updated", map[string]interface{}{
+	h.triggerWebhook(r.Context(), "link.updated", map[string]interface{}{
 		"linkId":    existingLink.ID,
 		"alias":     existingLink.Alias,
+		"domain":    existingLink.Domain,
 		"targetUrl": existingLink.TargetURL,
 		"groupId":   existingLink.GroupID,
+		"revision":  existingLink.Revision,
 		"timestamp": time.Now().Format(time.RFC3339),
 	})
 
@@ -543,37 +833,40 @@ func (h *LinkHandler) updateLink(w http.ResponseWriter, r *http.Request, alias s
 
 func (h *LinkHandler) deleteLink(w http.ResponseWriter, r *http.Request, alias string) {
 	// Check if link exists
-	existingLink, err := h.linkRepo.GetByAlias(r.Context(), alias)
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
-	}
-	if existingLink == nil {
-		http.Error(w, "link not found", http.StatusNotFound)
+	existingLink, ok := linkFromRequest(w, r, h.linkRepo, alias)
+	if !ok {
 		return
 	}
+	key := existingLink.DataKey()
 
 	// Delete link by ID (repository expects ID not alias)
 	if err := h.linkRepo.Delete(r.Context(), existingLink.ID); err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
+	h.audit.Record(r, "link.delete", "link", key, existingLink, nil)
 
 	// Delete associated analytics data (LinkStats and ClickEvents)
-	if err := h.statsRepo.DeleteByLinkAlias(r.Context(), alias); err != nil {
+	if err := h.statsRepo.DeleteByLinkAlias(r.Context(), key); err != nil {
 		// Log error but don't fail the request
-		log.Printf("Warning: failed to delete stats for link %s: %v", alias, err)
+		log.Printf("Warning: failed to delete stats for link %s: %v", key, err)
 	}
 
-	if err := h.clickRepo.DeleteByLinkAlias(r.Context(), alias); err != nil {
+	if err := h.clickRepo.DeleteByLinkAlias(r.Context(), key); err != nil {
 		// Log error but don't fail the request
-		log.Printf("Warning: failed to delete click events for link %s: %v", alias, err)
+		log.Printf("Warning: failed to delete click events for link %s: %v", key, err)
+	}
+
+	if err := h.revisions.DeleteByLink(r.Context(), existingLink.ID); err != nil {
+		log.Printf("Warning: failed to delete revisions for link %s: %v", key, err)
 	}
 
 	// Trigger link.deleted webhook
-	go h.triggerWebhook(r.Context(), "link.deleted", map[string]interface{}{
+	h.triggerWebhook(r.Context(), "link.deleted", map[string]interface{}{
 		"linkId":    existingLink.ID,
 		"alias":     existingLink.Alias,
+		"domain":    existingLink.Domain,
+		"groupId":   existingLink.GroupID,
 		"timestamp": time.Now().Format(time.RFC3339),
 	})
 
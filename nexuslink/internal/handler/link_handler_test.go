@@ -0,0 +1,52 @@
+package handler
+
+import (
+	"context"
+	"testing"
+
+	aliaspkg "github.com/afuzapratama/nexuslink/internal/alias"
+	"github.com/afuzapratama/nexuslink/internal/models"
+	"github.com/afuzapratama/nexuslink/internal/repository/sqlstore"
+)
+
+func TestCreateWithGeneratedAliasRetriesOnCollision(t *testing.T) {
+	ctx := context.Background()
+	links := sqlstore.NewLinkRepository(openTestDB(t))
+	h := &LinkHandler{linkRepo: links}
+
+	// Policy sequential supaya kandidat bisa ditebak: sequence di DB baru mulai dari 1
+	policy := models.AliasPolicy{Strategy: models.AliasStrategySequential, Salt: "test"}
+	settings := &models.AliasSettings{Default: policy}
+	candidate := func(n int64) string {
+		s, err := aliaspkg.Generate(policy, 0, func() (int64, error) { return n, nil })
+		if err != nil {
+			t.Fatalf("generate: %v", err)
+		}
+		return s
+	}
+
+	// Kandidat pertama sudah dipakai link custom
+	taken := candidate(1)
+	if err := links.Create(ctx, &models.Link{Alias: taken, TargetURL: "https://taken.example"}); err != nil {
+		t.Fatalf("create taken: %v", err)
+	}
+
+	link := &models.Link{TargetURL: "https://target.example"}
+	if err := h.createWithGeneratedAlias(ctx, link, settings); err != nil {
+		t.Fatalf("createWithGeneratedAlias: %v", err)
+	}
+	if want := candidate(2); link.Alias != want {
+		t.Errorf("alias = %q, want next candidate %q (taken %q)", link.Alias, want, taken)
+	}
+	if link.Key != models.LinkKey("", link.Alias) {
+		t.Errorf("key = %q, want key of fresh alias %q", link.Key, link.Alias)
+	}
+
+	got, err := links.GetByAlias(ctx, "", link.Alias)
+	if err != nil || got == nil || got.TargetURL != "https://target.example" {
+		t.Fatalf("stored link = %+v, err %v", got, err)
+	}
+	if orig, _ := links.GetByAlias(ctx, "", taken); orig == nil || orig.TargetURL != "https://taken.example" {
+		t.Errorf("existing link overwritten: %+v", orig)
+	}
+}
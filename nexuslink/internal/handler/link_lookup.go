@@ -0,0 +1,85 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/afuzapratama/nexuslink/internal/models"
+	"github.com/afuzapratama/nexuslink/internal/repository"
+)
+
+// errAmbiguousAlias: alias dipakai di beberapa domain dan request tidak menyebut domain
+var errAmbiguousAlias = errors.New("alias exists on multiple domains, specify ?domain=")
+
+// findLink mencari link yang dialamatkan admin API (/links/:alias?domain=).
+// Tanpa domain: link dengan key data = alias (link tanpa domain / link lama), lalu alias yang unik di semua domain.
+func findLink(ctx context.Context, links repository.LinkStore, domain, alias string) (*models.Link, error) {
+	if domain = strings.TrimSpace(domain); domain != "" {
+		return links.GetByAlias(ctx, domain, alias)
+	}
+
+	link, err := links.GetByKey(ctx, alias)
+	if err != nil || link != nil {
+		return link, err
+	}
+
+	all, err := links.ListByAlias(ctx, alias)
+	if err != nil {
+		return nil, err
+	}
+	switch len(all) {
+	case 0:
+		return nil, nil
+	case 1:
+		return &all[0], nil
+	}
+	return nil, errAmbiguousAlias
+}
+
+// findLinkByKey mencari link dari key data ("domain/alias" atau alias), dipakai bulk operation
+func findLinkByKey(ctx context.Context, links repository.LinkStore, key string) (*models.Link, error) {
+	domain, alias := models.SplitLinkKey(key)
+	return findLink(ctx, links, domain, alias)
+}
+
+// linkFromRequest = findLink dengan ?domain= dari request; response error sudah ditulis kalau ok = false
+func linkFromRequest(w http.ResponseWriter, r *http.Request, links repository.LinkStore, alias string) (*models.Link, bool) {
+	link, err := findLink(r.Context(), links, r.URL.Query().Get("domain"), alias)
+	if errors.Is(err, errAmbiguousAlias) {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return nil, false
+	}
+	if err != nil {
+		log.Printf("link lookup %s failed: %v", alias, err)
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return nil, false
+	}
+	if link == nil {
+		http.Error(w, "link not found", http.StatusNotFound)
+		return nil, false
+	}
+	return link, true
+}
+
+// AnalyticsKey returns key data untuk query analytics ?alias=&domain=.
+// Link yang sudah dihapus tetap bisa dibaca lewat key hasil models.LinkKey.
+func AnalyticsKey(w http.ResponseWriter, r *http.Request, links repository.LinkStore, alias string) (string, bool) {
+	domain := strings.TrimSpace(r.URL.Query().Get("domain"))
+	link, err := findLink(r.Context(), links, domain, alias)
+	if errors.Is(err, errAmbiguousAlias) {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return "", false
+	}
+	if err != nil {
+		log.Printf("link lookup %s failed: %v", alias, err)
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return "", false
+	}
+	if link == nil {
+		return models.LinkKey(domain, alias), true
+	}
+	return link.DataKey(), true
+}
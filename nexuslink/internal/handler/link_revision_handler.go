@@ -0,0 +1,281 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/afuzapratama/nexuslink/internal/audit"
+	"github.com/afuzapratama/nexuslink/internal/models"
+	"github.com/afuzapratama/nexuslink/internal/repository"
+)
+
+// revisionMetaFields: bookkeeping revision, bukan isi link (tidak ikut di diff)
+var revisionMetaFields = map[string]bool{
+	"revision":     true,
+	"updatedBy":    true,
+	"restoredFrom": true,
+	"createdAt":    true,
+}
+
+// variantConfig adalah bagian variant yang di-versi (tanpa counter)
+type variantConfig struct {
+	Label     string `json:"label"`
+	TargetURL string `json:"targetUrl"`
+	Weight    int    `json:"weight"`
+}
+
+// HandleRevisions handles /links/:alias/revisions/* (?domain= seperti HandleLinkByAlias)
+//
+//	GET  /links/:alias/revisions                  - list revision (terbaru dulu, index 0 = versi aktif)
+//	GET  /links/:alias/revisions/diff?from=&to=   - diff dua revision (to default = versi aktif)
+//	GET  /links/:alias/revisions/:n               - detail satu revision
+//	POST /links/:alias/revisions/:n/restore       - jadikan revision n versi aktif
+func (h *LinkHandler) HandleRevisions(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/links/"), "/"), "/")
+	if len(parts) < 2 || parts[1] != "revisions" {
+		http.NotFound(w, r)
+		return
+	}
+	alias := parts[0]
+
+	link, ok := linkFromRequest(w, r, h.linkRepo, alias)
+	if !ok {
+		return
+	}
+
+	switch {
+	case len(parts) == 2 && r.Method == http.MethodGet:
+		h.listRevisions(w, r, link)
+	case len(parts) == 3 && parts[2] == "diff" && r.Method == http.MethodGet:
+		h.diffRevisions(w, r, link)
+	case len(parts) == 3 && r.Method == http.MethodGet:
+		n, err := strconv.Atoi(parts[2])
+		if err != nil {
+			http.NotFound(w, r)
+			return
+		}
+		rev, ok := h.loadRevision(w, r, link, n)
+		if !ok {
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(rev)
+	case len(parts) == 4 && parts[3] == "restore" && r.Method == http.MethodPost:
+		n, err := strconv.Atoi(parts[2])
+		if err != nil {
+			http.NotFound(w, r)
+			return
+		}
+		h.restoreRevision(w, r, link, n)
+	case len(parts) <= 4:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (h *LinkHandler) listRevisions(w http.ResponseWriter, r *http.Request, link *models.Link) {
+	head, err := h.headRevision(r.Context(), link)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	stored, err := h.revisions.List(r.Context(), link.ID)
+	if err != nil {
+		log.Printf("revisions: list %s failed: %v", link.Alias, err)
+		http.Error(w, "failed to list revisions", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"current":   head.Revision,
+		"revisions": append([]models.LinkRevision{*head}, stored...),
+	})
+}
+
+func (h *LinkHandler) diffRevisions(w http.ResponseWriter, r *http.Request, link *models.Link) {
+	q := r.URL.Query()
+	from, err := strconv.Atoi(q.Get("from"))
+	if err != nil {
+		http.Error(w, "from revision is required", http.StatusBadRequest)
+		return
+	}
+	to := link.CurrentRevision()
+	if v := q.Get("to"); v != "" {
+		if to, err = strconv.Atoi(v); err != nil {
+			http.Error(w, "invalid to revision", http.StatusBadRequest)
+			return
+		}
+	}
+
+	fromRev, ok := h.loadRevision(w, r, link, from)
+	if !ok {
+		return
+	}
+	toRev, ok := h.loadRevision(w, r, link, to)
+	if !ok {
+		return
+	}
+
+	changes := revisionDiff(fromRev, toRev)
+	if changes == nil {
+		changes = []models.AuditChange{}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"from":    from,
+		"to":      to,
+		"changes": changes,
+	})
+}
+
+// restoreRevision menjadikan isi revision n (link + variant) versi baru; versi aktif disnapshot dulu
+func (h *LinkHandler) restoreRevision(w http.ResponseWriter, r *http.Request, link *models.Link, n int) {
+	if n == link.CurrentRevision() {
+		http.Error(w, "revision is already the current version", http.StatusBadRequest)
+		return
+	}
+	rev, ok := h.loadRevision(w, r, link, n)
+	if !ok {
+		return
+	}
+
+	before := *link
+	restored := rev.Link
+	restored.ID = link.ID
+	restored.Alias = link.Alias
+	restored.Domain = link.Domain
+	restored.Key = link.Key
+	restored.CreatedAt = link.CreatedAt
+	restored.RestoredFrom = n
+
+	// Snapshot tanpa variant = semua variant sekarang dihapus
+	variants := make([]models.LinkVariant, 0, len(rev.Variants))
+	for _, v := range rev.Variants {
+		v.LinkID = link.DataKey()
+		variants = append(variants, v)
+	}
+
+	if err := h.commitRevision(r, &restored, &before, variants); err != nil {
+		if errors.Is(err, repository.ErrRevisionConflict) {
+			http.Error(w, "link was modified by someone else, reload and try again", http.StatusConflict)
+			return
+		}
+		log.Printf("revisions: restore %s to %d failed: %v", link.Alias, n, err)
+		http.Error(w, "failed to restore revision", http.StatusInternalServerError)
+		return
+	}
+	h.audit.Record(r, "link.restore", "link", link.DataKey(), &before, &restored)
+
+	h.triggerWebhook(r.Context(), models.EventLinkUpdated, map[string]interface{}{
+		"linkId":       restored.ID,
+		"alias":        restored.Alias,
+		"domain":       restored.Domain,
+		"targetUrl":    restored.TargetURL,
+		"groupId":      restored.GroupID,
+		"revision":     restored.Revision,
+		"restoredFrom": n,
+		"timestamp":    time.Now().Format(time.RFC3339),
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(restored)
+}
+
+// commitRevision menyimpan link sebagai revision baru; prev (versi di store) disnapshot beserta
+// variant-nya. variants non-nil = konfigurasi variant ikut diganti (restore).
+func (h *LinkHandler) commitRevision(r *http.Request, link, prev *models.Link, variants []models.LinkVariant) error {
+	current, err := h.variantRepo.GetByLinkID(r.Context(), prev.DataKey())
+	if err != nil {
+		return err
+	}
+	link.UpdatedBy, _ = actorFromRequest(r)
+	return h.revisions.Commit(r.Context(), link, models.NewLinkRevision(prev, current), variants)
+}
+
+// headRevision returns versi aktif link dalam bentuk revision
+func (h *LinkHandler) headRevision(ctx context.Context, link *models.Link) (*models.LinkRevision, error) {
+	variants, err := h.variantRepo.GetByLinkID(ctx, link.DataKey())
+	if err != nil {
+		return nil, err
+	}
+	return models.NewLinkRevision(link, variants), nil
+}
+
+// loadRevision returns revision n (versi aktif kalau n = revision link); response error sudah ditulis kalau false
+func (h *LinkHandler) loadRevision(w http.ResponseWriter, r *http.Request, link *models.Link, n int) (*models.LinkRevision, bool) {
+	var (
+		rev *models.LinkRevision
+		err error
+	)
+	if n == link.CurrentRevision() {
+		rev, err = h.headRevision(r.Context(), link)
+	} else {
+		rev, err = h.revisions.Get(r.Context(), link.ID, n)
+	}
+	if err != nil {
+		log.Printf("revisions: get %s#%d failed: %v", link.Alias, n, err)
+		http.Error(w, "failed to load revision", http.StatusInternalServerError)
+		return nil, false
+	}
+	if rev == nil {
+		http.Error(w, "revision not found", http.StatusNotFound)
+		return nil, false
+	}
+	return rev, true
+}
+
+// revisionDiff membandingkan isi link per field plus variant per ID ("variants.<id>")
+func revisionDiff(from, to *models.LinkRevision) []models.AuditChange {
+	var changes []models.AuditChange
+	for _, c := range audit.Diff(&from.Link, &to.Link) {
+		if !revisionMetaFields[c.Field] {
+			changes = append(changes, c)
+		}
+	}
+
+	before, after := variantConfigs(from.Variants), variantConfigs(to.Variants)
+	ids := make([]string, 0, len(before)+len(after))
+	for id := range before {
+		ids = append(ids, id)
+	}
+	for id := range after {
+		if _, ok := before[id]; !ok {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		b, a := before[id], after[id]
+		if reflect.DeepEqual(b, a) {
+			continue
+		}
+		change := models.AuditChange{Field: "variants." + id}
+		if b != nil {
+			change.Before = b
+		}
+		if a != nil {
+			change.After = a
+		}
+		changes = append(changes, change)
+	}
+	return changes
+}
+
+func variantConfigs(variants []models.LinkVariant) map[string]*variantConfig {
+	m := make(map[string]*variantConfig, len(variants))
+	for _, v := range variants {
+		m[v.ID] = &variantConfig{Label: v.Label, TargetURL: v.TargetURL, Weight: v.Weight}
+	}
+	return m
+}
@@ -1,23 +1,240 @@
 package handler
 
 import (
+	"context"
+	"crypto/subtle"
+	"log"
 	"net/http"
-	"os"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/afuzapratama/nexuslink/internal/models"
+	"github.com/afuzapratama/nexuslink/internal/nodeauth"
+	"github.com/afuzapratama/nexuslink/internal/repository"
 )
 
-// WithAgentAuth wraps handler with API key authentication
-func WithAgentAuth(next http.HandlerFunc) http.HandlerFunc {
-	expectedKey := os.Getenv("NEXUS_API_KEY")
-	if expectedKey == "" {
-		expectedKey = "your-secret-api-key-here"
+// credentialCacheTTL: credential node di-cache sebentar supaya resolve tidak query store tiap request.
+// Rotasi/revoke di instance lain berlaku paling lambat setelah TTL ini.
+const credentialCacheTTL = 30 * time.Second
+
+// credentialRecheckAfter: key node yang tidak cocok hanya memicu baca ulang store kalau credential
+// di cache lebih tua dari ini, jadi key salah berulang tidak membanjiri store
+const credentialRecheckAfter = 5 * time.Second
+
+type ctxKey int
+
+const (
+	nodeIDCtxKey ctxKey = iota
+	userCtxKey
+)
+
+// NodeIDFromContext returns node ID kalau request diautentikasi dengan key node
+// ("" = admin key / session)
+func NodeIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(nodeIDCtxKey).(string)
+	return id
+}
+
+// UserFromContext returns user dashboard yang login (nil = admin key tanpa session / key node)
+func UserFromContext(ctx context.Context) *models.User {
+	u, _ := ctx.Value(userCtxKey).(*models.User)
+	return u
+}
+
+// Authenticator memisahkan scope admin (NEXUS_API_KEY atau session dashboard)
+// dari scope agent (key per node yang dibuat saat /nodes/register).
+type Authenticator struct {
+	adminKey string
+	sessions *AuthHandler
+	creds    repository.NodeCredentialStore
+
+	mu    sync.Mutex
+	cache map[string]cachedCredential
+}
+
+type cachedCredential struct {
+	cred      *models.NodeCredential
+	fetchedAt time.Time
+}
+
+func NewAuthenticator(adminKey string, sessions *AuthHandler, creds repository.NodeCredentialStore) *Authenticator {
+	if adminKey == "" {
+		log.Println("Warning: NEXUS_API_KEY is not set, admin API only accepts dashboard sessions")
 	}
+	return &Authenticator{
+		adminKey: adminKey,
+		sessions: sessions,
+		creds:    creds,
+		cache:    make(map[string]cachedCredential),
+	}
+}
+
+// Admin menerima admin key atau session login dashboard dengan role apa saja (key node ditolak)
+func (a *Authenticator) Admin(next http.HandlerFunc) http.HandlerFunc {
+	return a.Role(models.RoleAnalyst, models.RoleAnalyst, next)
+}
+
+// Require seperti Admin, tapi role user minimal role (untuk semua method)
+func (a *Authenticator) Require(role string, next http.HandlerFunc) http.HandlerFunc {
+	return a.Role(role, role, next)
+}
+
+// Role cek role user per method: GET/HEAD butuh readRole, method lain butuh writeRole.
+// Admin key tanpa session dianggap owner (automation / BFF lama); admin key + session
+// pakai role user session itu.
+func (a *Authenticator) Role(readRole, writeRole string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		role, user, status := a.identify(r)
+		if status != http.StatusOK {
+			if status == http.StatusUnauthorized && nodeauth.IsNodeKey(r.Header.Get("X-Nexus-Api-Key")) {
+				http.Error(w, "forbidden: node credentials cannot access admin endpoints", http.StatusForbidden)
+				return
+			}
+			http.Error(w, http.StatusText(status), status)
+			return
+		}
+
+		min := writeRole
+		if r.Method == http.MethodGet || r.Method == http.MethodHead {
+			min = readRole
+		}
+		if !models.RoleAtLeast(role, min) {
+			http.Error(w, "forbidden: requires role "+min, http.StatusForbidden)
+			return
+		}
+
+		if user != nil {
+			r = r.WithContext(context.WithValue(r.Context(), userCtxKey, user))
+		}
+		next(w, r)
+	}
+}
 
+// Agent menerima key node (node ID disimpan di context) atau admin key
+func (a *Authenticator) Agent(next http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		apiKey := r.Header.Get("X-Nexus-Api-Key")
-		if apiKey != expectedKey {
-			http.Error(w, "unauthorized", http.StatusUnauthorized)
+		key := r.Header.Get("X-Nexus-Api-Key")
+		if nodeauth.IsNodeKey(key) {
+			nodeID, ok := a.verifyNodeKey(r.Context(), key)
+			if !ok {
+				http.Error(w, "unauthorized", http.StatusUnauthorized)
+				return
+			}
+			next(w, r.WithContext(context.WithValue(r.Context(), nodeIDCtxKey, nodeID)))
+			return
+		}
+
+		a.Require(models.RoleOwner, next)(w, r)
+	}
+}
+
+// Node hanya menerima key node (endpoint yang butuh identitas node, mis. rotasi key)
+func (a *Authenticator) Node(next http.HandlerFunc) http.HandlerFunc {
+	return a.Agent(func(w http.ResponseWriter, r *http.Request) {
+		if NodeIDFromContext(r.Context()) == "" {
+			http.Error(w, "node credential required", http.StatusForbidden)
 			return
 		}
 		next(w, r)
+	})
+}
+
+// Invalidate membuang cache credential node (panggil setelah rotasi/revoke)
+func (a *Authenticator) Invalidate(nodeID string) {
+	a.mu.Lock()
+	delete(a.cache, nodeID)
+	a.mu.Unlock()
+}
+
+// Credential returns credential node (lewat cache)
+func (a *Authenticator) Credential(ctx context.Context, nodeID string) (*models.NodeCredential, error) {
+	a.mu.Lock()
+	c, ok := a.cache[nodeID]
+	a.mu.Unlock()
+	if ok && time.Since(c.fetchedAt) < credentialCacheTTL {
+		return c.cred, nil
+	}
+
+	cred, err := a.creds.Get(ctx, nodeID)
+	if err != nil {
+		return nil, err
+	}
+	// Miss tidak di-cache: node ID dari key yang tidak dikenal tidak boleh membuat cache tumbuh
+	if cred == nil {
+		return nil, nil
+	}
+
+	a.mu.Lock()
+	a.cache[nodeID] = cachedCredential{cred: cred, fetchedAt: time.Now()}
+	a.mu.Unlock()
+	return cred, nil
+}
+
+// cachedBefore true kalau credential node tidak ada di cache atau diambil sebelum t
+func (a *Authenticator) cachedBefore(nodeID string, t time.Time) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	c, ok := a.cache[nodeID]
+	return !ok || c.fetchedAt.Before(t)
+}
+
+// isNodeUUID: key node hanya dibuat saat registrasi, dan node ID registrasi selalu UUID kanonik.
+// Key dengan node ID lain ditolak sebelum menyentuh store.
+func isNodeUUID(nodeID string) bool {
+	id, err := uuid.Parse(nodeID)
+	return err == nil && id.String() == nodeID
+}
+
+func (a *Authenticator) verifyNodeKey(ctx context.Context, key string) (string, bool) {
+	nodeID, ok := nodeauth.ParseKey(key)
+	if !ok || !isNodeUUID(nodeID) {
+		return "", false
+	}
+
+	cred, err := a.Credential(ctx, nodeID)
+	if err != nil {
+		log.Printf("auth: load credential for node %s failed: %v", nodeID, err)
+		return "", false
+	}
+	if cred == nil {
+		return "", false
+	}
+	if !nodeauth.Verify(cred, key) {
+		// Key mungkin baru saja dirotasi di instance lain: cek ulang sekali tanpa cache,
+		// paling sering sekali per credentialRecheckAfter per node
+		if !a.cachedBefore(nodeID, time.Now().Add(-credentialRecheckAfter)) {
+			return "", false
+		}
+		a.Invalidate(nodeID)
+		if cred, err = a.Credential(ctx, nodeID); err != nil || !nodeauth.Verify(cred, key) {
+			return "", false
+		}
+	}
+	return nodeID, true
+}
+
+// identify returns role pemanggil admin API (status 401 kalau tidak terautentikasi)
+func (a *Authenticator) identify(r *http.Request) (string, *models.User, int) {
+	key := r.Header.Get("X-Nexus-Api-Key")
+	keyOK := a.adminKey != "" && key != "" && subtle.ConstantTimeCompare([]byte(key), []byte(a.adminKey)) == 1
+
+	token := bearerToken(r)
+	if token == "" || a.sessions == nil {
+		if keyOK {
+			return models.RoleOwner, nil, http.StatusOK
+		}
+		return "", nil, http.StatusUnauthorized
+	}
+
+	user, err := a.sessions.userForToken(r.Context(), token)
+	if err != nil {
+		log.Printf("auth: session lookup failed: %v", err)
+		return "", nil, http.StatusInternalServerError
+	}
+	if user == nil {
+		return "", nil, http.StatusUnauthorized
 	}
+	return user.Role, user, http.StatusOK
 }
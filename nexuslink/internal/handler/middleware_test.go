@@ -0,0 +1,113 @@
+package handler
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/afuzapratama/nexuslink/internal/models"
+	"github.com/afuzapratama/nexuslink/internal/nodeauth"
+)
+
+// countingCreds: NodeCredentialStore di memory yang menghitung Get
+type countingCreds struct {
+	cred *models.NodeCredential
+	gets int
+}
+
+func (s *countingCreds) Get(ctx context.Context, nodeID string) (*models.NodeCredential, error) {
+	s.gets++
+	return s.cred, nil
+}
+
+func (s *countingCreds) Put(ctx context.Context, cred *models.NodeCredential) error {
+	s.cred = cred
+	return nil
+}
+
+func (s *countingCreds) Delete(ctx context.Context, nodeID string) error {
+	s.cred = nil
+	return nil
+}
+
+func TestVerifyNodeKeyRecheck(t *testing.T) {
+	ctx := context.Background()
+	nodeID := uuid.NewString()
+	oldKey, cred, err := nodeauth.NewKey(nodeID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	store := &countingCreds{cred: cred}
+	a := NewAuthenticator("", nil, store)
+
+	if id, ok := a.verifyNodeKey(ctx, oldKey); !ok || id != nodeID {
+		t.Fatalf("valid key rejected: %q %v", id, ok)
+	}
+
+	// Key salah berulang tidak membaca ulang store selama cache masih baru
+	badKey, _, _ := nodeauth.NewKey(nodeID)
+	for i := 0; i < 10; i++ {
+		if _, ok := a.verifyNodeKey(ctx, badKey); ok {
+			t.Fatal("wrong key accepted")
+		}
+	}
+	if store.gets != 1 {
+		t.Errorf("store gets = %d, want 1", store.gets)
+	}
+
+	// Rotasi di instance lain: setelah credentialRecheckAfter key baru diterima lewat baca ulang
+	newKey, newCred, _ := nodeauth.NewKey(nodeID)
+	store.cred = newCred
+	a.mu.Lock()
+	c := a.cache[nodeID]
+	c.fetchedAt = time.Now().Add(-credentialRecheckAfter - time.Second)
+	a.cache[nodeID] = c
+	a.mu.Unlock()
+
+	if _, ok := a.verifyNodeKey(ctx, newKey); !ok {
+		t.Fatal("rotated key rejected after recheck window")
+	}
+	if store.gets != 2 {
+		t.Errorf("store gets = %d, want 2", store.gets)
+	}
+	if _, ok := a.verifyNodeKey(ctx, oldKey); ok {
+		t.Error("old key still accepted after rotation")
+	}
+	if store.gets != 2 {
+		t.Errorf("store gets = %d after old key, want 2", store.gets)
+	}
+}
+
+func TestVerifyNodeKeyUnknownNodes(t *testing.T) {
+	ctx := context.Background()
+	store := &countingCreds{}
+	a := NewAuthenticator("", nil, store)
+
+	// Node ID bukan UUID ditolak tanpa baca store
+	for _, id := range []string{"x", "node-go.example.com", strings.Repeat("a", 4096)} {
+		key, _, _ := nodeauth.NewKey(id)
+		if _, ok := a.verifyNodeKey(ctx, key); ok {
+			t.Errorf("key for %q accepted", id)
+		}
+	}
+	if store.gets != 0 {
+		t.Errorf("store gets = %d for non-UUID node IDs, want 0", store.gets)
+	}
+
+	// UUID acak yang tidak terdaftar: satu baca store per request, cache tetap kosong
+	for i := 0; i < 50; i++ {
+		key, _, _ := nodeauth.NewKey(uuid.NewString())
+		if _, ok := a.verifyNodeKey(ctx, key); ok {
+			t.Fatal("key for unknown node accepted")
+		}
+	}
+	if store.gets != 50 {
+		t.Errorf("store gets = %d, want 50", store.gets)
+	}
+	if n := len(a.cache); n != 0 {
+		t.Errorf("cache has %d entries for unknown nodes, want 0", n)
+	}
+}
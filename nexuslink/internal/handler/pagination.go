@@ -0,0 +1,49 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/afuzapratama/nexuslink/internal/repository"
+)
+
+// CursorParams membaca query ?cursor=&limit= untuk list endpoint.
+// ok=false kalau client masih pakai ?page= (mode offset lama, dipakai dashboard).
+func CursorParams(r *http.Request, defaultLimit, maxLimit int) (cursor string, limit int, ok bool) {
+	q := r.URL.Query()
+	if q.Get("page") != "" && q.Get("cursor") == "" {
+		return "", 0, false
+	}
+
+	limit = defaultLimit
+	if l := q.Get("limit"); l != "" {
+		if parsed, err := strconv.Atoi(l); err == nil && parsed > 0 && parsed <= maxLimit {
+			limit = parsed
+		}
+	}
+	return q.Get("cursor"), limit, true
+}
+
+// WriteCursorPage menulis response {data, limit, nextCursor}; nextCursor kosong = halaman terakhir.
+// err dari repository ikut ditangani di sini (cursor rusak → 400).
+func WriteCursorPage(w http.ResponseWriter, data interface{}, limit int, nextCursor string, err error) {
+	if err != nil {
+		if errors.Is(err, repository.ErrInvalidCursor) {
+			http.Error(w, "invalid cursor", http.StatusBadRequest)
+			return
+		}
+		log.Printf("list page error: %v", err)
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"data":       data,
+		"limit":      limit,
+		"nextCursor": nextCursor,
+	})
+}
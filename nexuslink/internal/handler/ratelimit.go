@@ -0,0 +1,158 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/afuzapratama/nexuslink/internal/metrics"
+	"github.com/afuzapratama/nexuslink/internal/models"
+	"github.com/afuzapratama/nexuslink/internal/ratelimit"
+)
+
+// blockedNotifier memastikan traffic.blocked webhook hanya dikirim sekali per key per window,
+// bukan sekali per request yang ditolak
+type blockedNotifier struct {
+	mu   sync.Mutex
+	last map[string]time.Time
+}
+
+func (n *blockedNotifier) shouldNotify(key string, window time.Duration, now time.Time) bool {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if n.last == nil {
+		n.last = make(map[string]time.Time)
+	}
+	if t, ok := n.last[key]; ok && now.Sub(t) < window {
+		return false
+	}
+	n.last[key] = now
+
+	// Bersihkan entry lama supaya map tidak tumbuh terus
+	if len(n.last) > 10000 {
+		for k, t := range n.last {
+			if now.Sub(t) >= window {
+				delete(n.last, k)
+			}
+		}
+	}
+	return true
+}
+
+// checkRateLimit menerapkan limit per IP & per link untuk request redirect.
+// Return true kalau request sudah dijawab (limit terlampaui).
+func (h *ResolverHandler) checkRateLimit(w http.ResponseWriter, r *http.Request, nodeID string, link *models.Link, ip string) bool {
+	if h.limiter == nil {
+		return false
+	}
+
+	settings := h.settingsRepo.GetOrDefault(r.Context())
+	policy, ok := ratelimit.ResolvePolicy(settings, link)
+	if !ok {
+		return false
+	}
+
+	now := time.Now()
+	scope, key, limit := "", "", 0
+	var retryAfter time.Duration
+
+	// Visitor yang sudah lolos challenge bebas dari limit per IP (limit per link tetap berlaku)
+	passed := policy.Action == models.RateLimitActionChallenge &&
+		ratelimit.VerifyChallenge(h.challengeSecret, r.Header.Get("X-Visitor-Challenge"), ip, link.DataKey(), now)
+
+	if policy.PerIP > 0 && ip != "" && !passed {
+		k := policy.IPKey(ip, link.DataKey())
+		res, err := h.limiter.Check(r.Context(), policy.Algorithm, k, policy.PerIP, policy.Window)
+		if err != nil {
+			log.Printf("rate limit check failed: key=%s err=%v", k, err)
+		} else {
+			metrics.GetMetrics().RecordRateLimitCheck(res.Allowed)
+			if !res.Allowed {
+				scope, key, limit, retryAfter = "ip", k, policy.PerIP, res.RetryAfter
+			}
+		}
+	}
+
+	if scope == "" && policy.PerLink > 0 {
+		k := policy.LinkKey(link.DataKey())
+		res, err := h.limiter.Check(r.Context(), policy.Algorithm, k, policy.PerLink, policy.Window)
+		if err != nil {
+			log.Printf("rate limit check failed: key=%s err=%v", k, err)
+		} else {
+			metrics.GetMetrics().RecordRateLimitCheck(res.Allowed)
+			if !res.Allowed {
+				scope, key, limit, retryAfter = "link", k, policy.PerLink, res.RetryAfter
+			}
+		}
+	}
+
+	if scope == "" {
+		return false
+	}
+
+	log.Printf("Rate limited: alias=%s, ip=%s, scope=%s, action=%s", link.Alias, ip, scope, policy.Action)
+	metrics.GetMetrics().IncrementBlockedRedirects(nodeID, "rate_limited")
+
+	if h.blocked.shouldNotify(key, policy.Window, now) {
+		h.triggerWebhook(context.Background(), models.EventTrafficBlocked, map[string]interface{}{
+			"linkId":        link.ID,
+			"alias":         link.Alias,
+			"domain":        link.Domain,
+			"groupId":       link.GroupID,
+			"nodeId":        nodeID,
+			"ipAddress":     ip,
+			"reason":        "rate_limited",
+			"scope":         scope,
+			"key":           key,
+			"limit":         limit,
+			"windowSeconds": int(policy.Window.Seconds()),
+			"action":        policy.Action,
+			"algorithm":     policy.Algorithm,
+			"timestamp":     now.Format(time.RFC3339),
+		})
+	}
+
+	retrySeconds := int(math.Ceil(retryAfter.Seconds()))
+	if retrySeconds < 1 {
+		retrySeconds = 1
+	}
+
+	switch {
+	case policy.Action == models.RateLimitActionFallback && strings.TrimSpace(link.FallbackURL) != "":
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]string{
+			"target": link.FallbackURL,
+			"reason": "rate_limited",
+		})
+
+	case policy.Action == models.RateLimitActionChallenge && scope == "ip":
+		// Agent render halaman challenge; token dikirim balik lewat X-Visitor-Challenge
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Retry-After", strconv.Itoa(retrySeconds))
+		w.WriteHeader(http.StatusTooManyRequests)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"reason":     "rate_limited",
+			"challenge":  ratelimit.IssueChallenge(h.challengeSecret, ip, link.DataKey(), now),
+			"retryAfter": retrySeconds,
+		})
+
+	default:
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Retry-After", strconv.Itoa(retrySeconds))
+		w.WriteHeader(http.StatusTooManyRequests)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"reason":     "rate_limited",
+			"message":    "rate limit exceeded",
+			"retryAfter": retrySeconds,
+		})
+	}
+
+	return true
+}
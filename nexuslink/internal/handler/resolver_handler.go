@@ -5,48 +5,83 @@ import (
 	"encoding/json"
 	"log"
 	"net/http"
+	"net/url"
+	"os"
 	"strings"
 	"time"
 
 	"github.com/afuzapratama/nexuslink/internal/geoip"
+	"github.com/afuzapratama/nexuslink/internal/ingest"
 	"github.com/afuzapratama/nexuslink/internal/ipcheck"
+	"github.com/afuzapratama/nexuslink/internal/metrics"
 	"github.com/afuzapratama/nexuslink/internal/models"
+	"github.com/afuzapratama/nexuslink/internal/ratelimit"
 	"github.com/afuzapratama/nexuslink/internal/repository"
+	"github.com/afuzapratama/nexuslink/internal/rules"
 	"github.com/afuzapratama/nexuslink/internal/ua"
 	"github.com/afuzapratama/nexuslink/internal/util"
 	"github.com/afuzapratama/nexuslink/internal/webhook"
 )
 
 type ResolverHandler struct {
-	linkRepo      *repository.LinkRepository
-	statsRepo     *repository.LinkStatsRepository
-	clickRepo     *repository.ClickRepository
-	settingsRepo  *repository.SettingsRepository
-	webhookRepo   *repository.WebhookRepository
-	webhookSender *webhook.Sender
-	variantRepo   *repository.LinkVariantRepository
+	linkRepo     repository.LinkStore
+	statsRepo    repository.StatsStore
+	clicks       ingest.ClickIngester
+	settingsRepo repository.SettingsStore
+	webhooks     *webhook.Dispatcher
+	variantRepo  repository.VariantStore
+	clickDedup   repository.ClickDedupStore // nil = click dari agent tidak di-dedupe
+
+	// Rate limit di redirect path (limiter nil = Redis tidak tersedia, tidak ada enforcement)
+	limiter         *ratelimit.Limiter
+	challengeSecret string
+	blocked         blockedNotifier
 }
 
 func NewResolverHandler(
-	linkRepo *repository.LinkRepository,
-	statsRepo *repository.LinkStatsRepository,
-	clickRepo *repository.ClickRepository,
-	settingsRepo *repository.SettingsRepository,
-	webhookRepo *repository.WebhookRepository,
-	webhookSender *webhook.Sender,
-	variantRepo *repository.LinkVariantRepository,
+	linkRepo repository.LinkStore,
+	statsRepo repository.StatsStore,
+	clicks ingest.ClickIngester,
+	settingsRepo repository.SettingsStore,
+	webhooks *webhook.Dispatcher,
+	variantRepo repository.VariantStore,
+	clickDedup repository.ClickDedupStore,
+	limiter *ratelimit.Limiter,
 ) *ResolverHandler {
+	// Secret untuk token challenge rate limit; default pakai API key
+	challengeSecret := os.Getenv("NEXUS_CHALLENGE_SECRET")
+	if challengeSecret == "" {
+		challengeSecret = os.Getenv("NEXUS_API_KEY")
+	}
+
 	return &ResolverHandler{
-		linkRepo:      linkRepo,
-		statsRepo:     statsRepo,
-		clickRepo:     clickRepo,
-		settingsRepo:  settingsRepo,
-		webhookRepo:   webhookRepo,
-		webhookSender: webhookSender,
-		variantRepo:   variantRepo,
+		linkRepo:        linkRepo,
+		statsRepo:       statsRepo,
+		clicks:          clicks,
+		settingsRepo:    settingsRepo,
+		webhooks:        webhooks,
+		variantRepo:     variantRepo,
+		clickDedup:      clickDedup,
+		limiter:         limiter,
+		challengeSecret: challengeSecret,
 	}
 }
 
+// Provider IP quality (variabel supaya test bisa mengganti tanpa request ke API berbayar)
+var (
+	checkProxyCheck = ipcheck.CheckIPWithProxyCheck
+	checkIPQS       = ipcheck.CheckIPWithIPQS
+)
+
+// blockMessages adalah pesan error per reason kalau link tidak punya FallbackURL
+var blockMessages = map[string]string{
+	"bot_blocked":         "bot access blocked",
+	"os_not_allowed":      "OS not allowed",
+	"device_not_allowed":  "device not allowed",
+	"browser_not_allowed": "browser not allowed",
+	"country_not_allowed": "country not allowed",
+}
+
 // GET /links/resolve - Main resolver with all checks
 func (h *ResolverHandler) HandleResolve(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
@@ -57,16 +92,20 @@ func (h *ResolverHandler) HandleResolve(w http.ResponseWriter, r *http.Request)
 	alias := strings.TrimSpace(r.URL.Query().Get("alias"))
 	nodeID := strings.TrimSpace(r.URL.Query().Get("nodeId"))
 	domain := strings.TrimSpace(r.URL.Query().Get("domain"))
+	// Request dengan key node selalu dicatat atas nama node pemilik key
+	if credNodeID := NodeIDFromContext(r.Context()); credNodeID != "" {
+		nodeID = credNodeID
+	}
 
 	if alias == "" {
 		http.Error(w, "alias is required", http.StatusBadRequest)
 		return
 	}
 
-	// Get link
-	link, err := h.linkRepo.GetByAlias(r.Context(), alias)
+	// Get link: alias di domain request, fallback ke link tanpa domain
+	link, err := h.linkRepo.FindByAlias(r.Context(), domain, alias)
 	if err != nil {
-		log.Printf("linkRepo.GetByAlias error: %v", err)
+		log.Printf("linkRepo.FindByAlias error: %v", err)
 		http.Error(w, "internal error", http.StatusInternalServerError)
 		return
 	}
@@ -75,19 +114,24 @@ func (h *ResolverHandler) HandleResolve(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	// --- Check link disabled (bulk toggle / IsActive) ---
+	if !link.IsActive {
+		log.Printf("Link disabled: alias=%s, behavior=%s", alias, link.DisabledBehavior)
+		h.respondDisabled(w, nodeID, link)
+		return
+	}
+
 	// --- Check domain restriction ---
 	// If link has domain restriction and request domain doesn't match, deny access
-	if link.Domain != "" && domain != "" && !strings.EqualFold(link.Domain, domain) {
-		log.Printf("Domain mismatch: alias=%s, linkDomain=%s, requestDomain=%s", alias, link.Domain, domain)
-		if strings.TrimSpace(link.FallbackURL) != "" {
-			w.Header().Set("Content-Type", "application/json")
-			json.NewEncoder(w).Encode(map[string]string{
-				"target": link.FallbackURL,
-				"reason": "domain_not_allowed",
-			})
-			return
-		}
-		http.Error(w, "link not available on this domain", http.StatusForbidden)
+	if !link.ServesDomain(domain) {
+		log.Printf("Domain mismatch: alias=%s, linkDomains=%v, requestDomain=%s", alias, link.DomainPool(), domain)
+		h.deny(w, nodeID, link, "domain_not_allowed", http.StatusForbidden, "link not available on this domain")
+		return
+	}
+
+	// --- Rate limit (per IP visitor yang di-forward agent & per link) ---
+	ip := visitorIP(r)
+	if h.checkRateLimit(w, r, nodeID, link, ip) {
 		return
 	}
 
@@ -95,29 +139,13 @@ func (h *ResolverHandler) HandleResolve(w http.ResponseWriter, r *http.Request)
 	now := time.Now()
 	if link.ActiveFrom != nil && now.Before(*link.ActiveFrom) {
 		log.Printf("Link not yet active: alias=%s, activeFrom=%v, now=%v", alias, link.ActiveFrom, now)
-		if strings.TrimSpace(link.FallbackURL) != "" {
-			w.Header().Set("Content-Type", "application/json")
-			json.NewEncoder(w).Encode(map[string]string{
-				"target": link.FallbackURL,
-				"reason": "not_yet_active",
-			})
-			return
-		}
-		http.Error(w, "link is not yet active", http.StatusForbidden)
+		h.deny(w, nodeID, link, "not_yet_active", http.StatusForbidden, "link is not yet active")
 		return
 	}
 
 	if link.ActiveUntil != nil && now.After(*link.ActiveUntil) {
 		log.Printf("Link schedule ended: alias=%s, activeUntil=%v, now=%v", alias, link.ActiveUntil, now)
-		if strings.TrimSpace(link.FallbackURL) != "" {
-			w.Header().Set("Content-Type", "application/json")
-			json.NewEncoder(w).Encode(map[string]string{
-				"target": link.FallbackURL,
-				"reason": "schedule_ended",
-			})
-			return
-		}
-		http.Error(w, "link schedule has ended", http.StatusGone)
+		h.deny(w, nodeID, link, "schedule_ended", http.StatusGone, "link schedule has ended")
 		return
 	}
 
@@ -126,72 +154,49 @@ func (h *ResolverHandler) HandleResolve(w http.ResponseWriter, r *http.Request)
 		log.Printf("Link expired: alias=%s, expiresAt=%v", alias, link.ExpiresAt)
 
 		// Trigger link.expired webhook
-		go h.triggerWebhook(r.Context(), models.EventLinkExpired, map[string]interface{}{
+		h.triggerWebhook(r.Context(), models.EventLinkExpired, map[string]interface{}{
 			"linkId":    link.ID,
 			"alias":     link.Alias,
+			"domain":    link.Domain,
+			"groupId":   link.GroupID,
 			"targetUrl": link.TargetURL,
 			"expiresAt": link.ExpiresAt.Format(time.RFC3339),
 			"timestamp": time.Now().Format(time.RFC3339),
 		})
 
-		if strings.TrimSpace(link.FallbackURL) != "" {
-			w.Header().Set("Content-Type", "application/json")
-			json.NewEncoder(w).Encode(map[string]string{
-				"target": link.FallbackURL,
-				"reason": "expired",
-			})
-			return
-		}
-		http.Error(w, "link expired", http.StatusGone)
+		h.deny(w, nodeID, link, "expired", http.StatusGone, "link expired")
 		return
 	}
 
 	// --- Check max clicks limit ---
 	if link.MaxClicks != nil {
-		stat, err := h.statsRepo.Get(r.Context(), nodeID, alias)
+		stat, err := h.statsRepo.Get(r.Context(), nodeID, link.DataKey())
 		if err == nil && stat != nil && stat.HitCount >= int64(*link.MaxClicks) {
 			log.Printf("Link max clicks reached: alias=%s, maxClicks=%d", alias, *link.MaxClicks)
 
 			// Trigger link.maxclicks webhook
-			go h.triggerWebhook(r.Context(), models.EventLinkMaxClicks, map[string]interface{}{
+			h.triggerWebhook(r.Context(), models.EventLinkMaxClicks, map[string]interface{}{
 				"linkId":      link.ID,
 				"alias":       link.Alias,
+				"domain":      link.Domain,
+				"groupId":     link.GroupID,
 				"targetUrl":   link.TargetURL,
 				"maxClicks":   *link.MaxClicks,
 				"totalClicks": stat.HitCount,
 				"timestamp":   time.Now().Format(time.RFC3339),
 			})
 
-			if strings.TrimSpace(link.FallbackURL) != "" {
-				w.Header().Set("Content-Type", "application/json")
-				json.NewEncoder(w).Encode(map[string]string{
-					"target": link.FallbackURL,
-					"reason": "max_clicks_reached",
-				})
-				return
-			}
-			http.Error(w, "link has reached maximum clicks", http.StatusForbidden)
+			h.deny(w, nodeID, link, "max_clicks_reached", http.StatusForbidden, "link has reached maximum clicks")
 			return
 		}
 	}
 
 	// --- Increment hit count ---
-	if err := h.statsRepo.IncrementHit(r.Context(), nodeID, alias); err != nil {
+	if err := h.statsRepo.IncrementHit(r.Context(), nodeID, link.DataKey()); err != nil {
 		log.Printf("increment link stat failed: %v", err)
 	}
 
 	// --- Get visitor info from headers ---
-	ip := r.Header.Get("X-Real-IP")
-	if ip == "" {
-		ip = r.Header.Get("X-Forwarded-For")
-		if idx := strings.Index(ip, ","); idx > 0 {
-			ip = strings.TrimSpace(ip[:idx])
-		}
-	}
-	if ip == "" {
-		ip = strings.Split(r.RemoteAddr, ":")[0]
-	}
-
 	userAgent := r.Header.Get("X-Visitor-User-Agent")
 	if userAgent == "" {
 		userAgent = r.UserAgent()
@@ -202,6 +207,14 @@ func (h *ResolverHandler) HandleResolve(w http.ResponseWriter, r *http.Request)
 		referer = r.Referer()
 	}
 
+	acceptLanguage := r.Header.Get("X-Visitor-Accept-Language")
+	if acceptLanguage == "" {
+		acceptLanguage = r.Header.Get("Accept-Language")
+	}
+
+	// Query string asli dari short URL (e.g. /r/promo?utm_source=ig)
+	visitorQuery, _ := url.ParseQuery(r.Header.Get("X-Visitor-Query"))
+
 	// Parse User-Agent (returns 5 values: os, device, browser, isBot, botType)
 	osName, deviceType, browserName, isBot, botType := ua.Parse(userAgent)
 
@@ -210,7 +223,7 @@ func (h *ResolverHandler) HandleResolve(w http.ResponseWriter, r *http.Request)
 
 	// Initialize click event
 	clickEvent := &models.ClickEvent{
-		Alias:      alias,
+		Alias:      link.DataKey(),
 		NodeID:     nodeID,
 		IP:         ip,
 		UserAgent:  userAgent,
@@ -224,21 +237,13 @@ func (h *ResolverHandler) HandleResolve(w http.ResponseWriter, r *http.Request)
 		RiskScore:  0,
 	}
 
-	// Check if bot should be blocked
+	// Block bot dari UA duluan: tidak perlu bayar ProxyCheck/IPQS untuk traffic yang pasti ditolak
 	if link.BlockBots && isBot {
 		log.Printf("Bot blocked: alias=%s, botType=%s, userAgent=%s", alias, botType, userAgent)
-		if strings.TrimSpace(link.FallbackURL) != "" {
-			w.Header().Set("Content-Type", "application/json")
-			json.NewEncoder(w).Encode(map[string]string{
-				"target": link.FallbackURL,
-				"reason": "bot_blocked",
-			})
-			// Still log the click event
-			h.clickRepo.LogClick(r.Context(), clickEvent)
-			return
-		}
-		http.Error(w, "bot access blocked", http.StatusForbidden)
-		h.clickRepo.LogClick(r.Context(), clickEvent)
+		clickEvent.Country, clickEvent.City = geoip.Lookup(ip)
+		clickEvent.BlockReason = "bot_blocked"
+		h.deny(w, nodeID, link, "bot_blocked", http.StatusForbidden, blockMessages["bot_blocked"])
+		h.clicks.Submit(clickEvent)
 		return
 	}
 
@@ -248,7 +253,7 @@ func (h *ResolverHandler) HandleResolve(w http.ResponseWriter, r *http.Request)
 
 	// ProxyCheck.io
 	if settings.EnableProxyCheck && strings.TrimSpace(settings.ProxyCheckAPIKey) != "" {
-		result, err := ipcheck.CheckIPWithProxyCheck(r.Context(), ip, settings.ProxyCheckAPIKey)
+		result, err := checkProxyCheck(r.Context(), ip, settings.ProxyCheckAPIKey)
 		if err != nil {
 			log.Printf("ProxyCheck failed: %v", err)
 		} else {
@@ -278,7 +283,7 @@ func (h *ResolverHandler) HandleResolve(w http.ResponseWriter, r *http.Request)
 
 	// IPQualityScore (overrides ProxyCheck if enabled)
 	if settings.EnableIPQualityScore && strings.TrimSpace(settings.IPQualityScoreAPIKey) != "" {
-		result, err := ipcheck.CheckIPWithIPQS(r.Context(), ip, settings.IPQualityScoreAPIKey)
+		result, err := checkIPQS(r.Context(), ip, settings.IPQualityScoreAPIKey)
 		if err != nil {
 			log.Printf("IPQS failed: %v", err)
 		} else {
@@ -320,136 +325,91 @@ func (h *ResolverHandler) HandleResolve(w http.ResponseWriter, r *http.Request)
 	// Handle blocking
 	if blocked {
 		log.Printf("Traffic blocked: ip=%s, reason=%s", ip, blockReason)
-		if strings.TrimSpace(link.FallbackURL) != "" {
-			w.Header().Set("Content-Type", "application/json")
-			json.NewEncoder(w).Encode(map[string]string{
-				"target": link.FallbackURL,
-				"reason": blockReason,
-			})
-			h.clickRepo.LogClick(r.Context(), clickEvent)
-			return
-		}
-		http.Error(w, "access blocked", http.StatusForbidden)
-		h.clickRepo.LogClick(r.Context(), clickEvent)
+		clickEvent.BlockReason = blockReason
+		h.deny(w, nodeID, link, blockReason, http.StatusForbidden, "access blocked")
+		h.clicks.Submit(clickEvent)
 		return
 	}
 
-	// Check OS/Device/Browser rules
-	// For OS, use contains check because parser returns "Windows 10", "Windows 11", etc.
-	// but filter is just "Windows"
-	if len(link.AllowedOS) > 0 && !containsOS(link.AllowedOS, osName) {
-		log.Printf("OS mismatch: alias=%s, got=%s, allowed=%v", alias, osName, link.AllowedOS)
-		if strings.TrimSpace(link.FallbackURL) != "" {
-			w.Header().Set("Content-Type", "application/json")
-			json.NewEncoder(w).Encode(map[string]string{
-				"target": link.FallbackURL,
-				"reason": "os_not_allowed",
-			})
-			h.clickRepo.LogClick(r.Context(), clickEvent)
-			return
-		}
-		http.Error(w, "OS not allowed", http.StatusForbidden)
-		h.clickRepo.LogClick(r.Context(), clickEvent)
-		return
+	// --- Evaluate routing rules ---
+	// Allow-list lama (bots/OS/device/browser/country) dievaluasi duluan sebagai block rules,
+	// setelah itu rules milik link sesuai urutan. First match wins.
+	visitor := rules.Visitor{
+		Country:  clickEvent.Country,
+		ASN:      geoip.LookupASN(ip),
+		Device:   deviceType,
+		OS:       osName,
+		Browser:  browserName,
+		Referrer: referer,
+		Language: rules.PrimaryLanguage(acceptLanguage),
+		Query:    visitorQuery,
+		IsBot:    isBot,
+		Time:     now,
 	}
 
-	if len(link.AllowedDevices) > 0 && !contains(link.AllowedDevices, deviceType) {
-		log.Printf("Device mismatch: alias=%s, got=%s, allowed=%v", alias, deviceType, link.AllowedDevices)
-		if strings.TrimSpace(link.FallbackURL) != "" {
-			w.Header().Set("Content-Type", "application/json")
-			json.NewEncoder(w).Encode(map[string]string{
-				"target": link.FallbackURL,
-				"reason": "device_not_allowed",
-			})
-			h.clickRepo.LogClick(r.Context(), clickEvent)
-			return
-		}
-		http.Error(w, "device not allowed", http.StatusForbidden)
-		h.clickRepo.LogClick(r.Context(), clickEvent)
-		return
-	}
+	chain := append(rules.LegacyRules(link), link.Rules...)
+	matched := rules.Evaluate(chain, visitor)
 
-	if len(link.AllowedBrowsers) > 0 && !contains(link.AllowedBrowsers, browserName) {
-		log.Printf("Browser mismatch: alias=%s, got=%s, allowed=%v", alias, browserName, link.AllowedBrowsers)
-		if strings.TrimSpace(link.FallbackURL) != "" {
-			w.Header().Set("Content-Type", "application/json")
-			json.NewEncoder(w).Encode(map[string]string{
-				"target": link.FallbackURL,
-				"reason": "browser_not_allowed",
-			})
-			h.clickRepo.LogClick(r.Context(), clickEvent)
-			return
-		}
-		http.Error(w, "browser not allowed", http.StatusForbidden)
-		h.clickRepo.LogClick(r.Context(), clickEvent)
-		return
-	}
+	targetURL := link.TargetURL
+	selectedVariantID := ""
 
-	// Check country restriction
-	if len(link.AllowedCountries) > 0 && !contains(link.AllowedCountries, clickEvent.Country) {
-		log.Printf("Country mismatch: alias=%s, got=%s, allowed=%v", alias, clickEvent.Country, link.AllowedCountries)
-		if strings.TrimSpace(link.FallbackURL) != "" {
-			w.Header().Set("Content-Type", "application/json")
-			json.NewEncoder(w).Encode(map[string]string{
-				"target": link.FallbackURL,
-				"reason": "country_not_allowed",
-			})
-			h.clickRepo.LogClick(r.Context(), clickEvent)
+	if matched != nil {
+		clickEvent.RuleID = matched.ID
+
+		switch matched.Action.Type {
+		case models.RuleActionBlock:
+			reason := matched.Action.Reason
+			if reason == "" {
+				reason = "blocked_by_rule"
+			}
+			message := blockMessages[reason]
+			if message == "" {
+				message = "access blocked"
+			}
+			log.Printf("Rule blocked: alias=%s, rule=%s, reason=%s", alias, matched.ID, reason)
+			clickEvent.BlockReason = reason
+			h.deny(w, nodeID, link, reason, http.StatusForbidden, message)
+			h.clicks.Submit(clickEvent)
 			return
+
+		case models.RuleActionRedirect:
+			targetURL = matched.Action.URL
+			log.Printf("Rule matched: alias=%s, rule=%s, target=%s", alias, matched.ID, targetURL)
+
+		case models.RuleActionVariant:
+			variant, err := h.variantRepo.GetByID(r.Context(), link.DataKey(), matched.Action.VariantID)
+			if err != nil || variant == nil {
+				log.Printf("Rule %s references missing variant %s for link %s, using default target", matched.ID, matched.Action.VariantID, link.Alias)
+			} else {
+				targetURL = variant.TargetURL
+				selectedVariantID = variant.ID
+			}
 		}
-		http.Error(w, "country not allowed", http.StatusForbidden)
-		h.clickRepo.LogClick(r.Context(), clickEvent)
-		return
 	}
 
-	// Log successful click
-	if err := h.clickRepo.LogClick(r.Context(), clickEvent); err != nil {
-		log.Printf("Failed to log click: %v", err)
+	// Check for A/B testing variants (only when no rule decided the target)
+	if matched == nil {
+		variants, err := h.variantRepo.GetByLinkID(r.Context(), link.DataKey())
+		if err != nil {
+			log.Printf("Error fetching variants for link %s: %v", link.Alias, err)
+		} else if len(variants) > 0 {
+			// Use weighted selection to choose a variant
+			selectedVariant := util.SelectVariantByWeight(variants)
+			if selectedVariant != nil {
+				targetURL = selectedVariant.TargetURL
+				selectedVariantID = selectedVariant.ID
+
+				log.Printf("A/B Test: Selected variant %s (weight: %d) for link %s",
+					selectedVariant.Label, selectedVariant.Weight, link.Alias)
+			}
+		}
 	}
 
-	// Trigger click.created webhook
-	go h.triggerWebhook(r.Context(), models.EventClickCreated, map[string]interface{}{
-		"linkId":      link.ID,
-		"alias":       link.Alias,
-		"targetUrl":   link.TargetURL,
-		"nodeId":      nodeID,
-		"ipAddress":   ip,
-		"userAgent":   userAgent,
-		"referer":     referer,
-		"country":     clickEvent.Country,
-		"city":        clickEvent.City,
-		"deviceType":  deviceType,
-		"osName":      osName,
-		"browserName": browserName,
-		"isBot":       isBot,
-		"timestamp":   time.Now().Format(time.RFC3339),
-	})
+	clickEvent.VariantID = selectedVariantID
 
-	// Check for A/B testing variants
-	targetURL := link.TargetURL
-	selectedVariantID := ""
-
-	variants, err := h.variantRepo.GetByLinkID(r.Context(), link.Alias)
-	if err != nil {
-		log.Printf("Error fetching variants for link %s: %v", link.Alias, err)
-	} else if len(variants) > 0 {
-		// Use weighted selection to choose a variant
-		selectedVariant := util.SelectVariantByWeight(variants)
-		if selectedVariant != nil {
-			targetURL = selectedVariant.TargetURL
-			selectedVariantID = selectedVariant.ID
-
-			// Increment variant click count asynchronously
-			go func(vID string) {
-				if err := h.variantRepo.IncrementClicks(context.Background(), link.Alias, vID); err != nil {
-					log.Printf("Failed to increment variant clicks: %v", err)
-				}
-			}(selectedVariantID)
-
-			log.Printf("A/B Test: Selected variant %s (weight: %d) for link %s",
-				selectedVariant.Label, selectedVariant.Weight, link.Alias)
-		}
-	}
+	// Log click, trigger click.created webhook, increment variant clicks
+	h.recordClick(r.Context(), link, clickEvent)
+	metrics.GetMetrics().IncrementRedirects(nodeID)
 
 	// Return target URL with optional variant ID (for conversion tracking)
 	response := map[string]string{
@@ -458,74 +418,108 @@ func (h *ResolverHandler) HandleResolve(w http.ResponseWriter, r *http.Request)
 	if selectedVariantID != "" {
 		response["variantId"] = selectedVariantID
 	}
+	if matched != nil {
+		response["ruleId"] = matched.ID
+	}
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(response)
 }
 
-func contains(slice []string, item string) bool {
-	for _, s := range slice {
-		if strings.EqualFold(s, item) {
-			return true
+// visitorIP returns IP visitor yang di-forward agent (X-Real-IP), fallback ke X-Forwarded-For / RemoteAddr
+func visitorIP(r *http.Request) string {
+	ip := r.Header.Get("X-Real-IP")
+	if ip == "" {
+		ip = r.Header.Get("X-Forwarded-For")
+		if idx := strings.Index(ip, ","); idx > 0 {
+			ip = strings.TrimSpace(ip[:idx])
 		}
 	}
-	return false
-}
-
-// containsOS checks if OS name matches allowed list
-// Uses partial match: "Windows 10" matches "Windows", "Linux x86_64" matches "Linux"
-func containsOS(allowedOS []string, detectedOS string) bool {
-	if detectedOS == "" {
-		return false
+	if ip == "" {
+		ip = strings.Split(r.RemoteAddr, ":")[0]
 	}
+	return ip
+}
 
-	detectedLower := strings.ToLower(detectedOS)
-	// Normalize: remove spaces and special chars for better matching
-	detectedNormalized := strings.ReplaceAll(strings.ReplaceAll(detectedLower, " ", ""), "_", "")
-
-	for _, allowed := range allowedOS {
-		allowedLower := strings.ToLower(allowed)
-		allowedNormalized := strings.ReplaceAll(strings.ReplaceAll(allowedLower, " ", ""), "_", "")
+// deny mengirim FallbackURL (kalau ada) beserta reason ke agent, kalau tidak ada kirim error status
+func (h *ResolverHandler) deny(w http.ResponseWriter, nodeID string, link *models.Link, reason string, status int, message string) {
+	metrics.GetMetrics().IncrementBlockedRedirects(nodeID, reason)
 
-		// Check if detected OS contains allowed OS name
-		// e.g., "intelmacosх1015_7" contains "macos"
-		// e.g., "windows10" contains "windows"
-		if strings.Contains(detectedNormalized, allowedNormalized) {
-			return true
-		}
+	if strings.TrimSpace(link.FallbackURL) != "" {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]string{
+			"target": link.FallbackURL,
+			"reason": reason,
+		})
+		return
 	}
-	return false
+	http.Error(w, message, status)
 }
 
-// triggerWebhook triggers all active webhooks subscribed to an event
-func (h *ResolverHandler) triggerWebhook(ctx context.Context, event string, data map[string]interface{}) {
-	webhooks, err := h.webhookRepo.GetByEvent(ctx, event)
-	if err != nil {
-		log.Printf("Failed to get webhooks for event %s: %v", event, err)
+// respondDisabled mengirim response untuk link yang di-disable sesuai DisabledBehavior.
+// Body JSON tetap dikirim di response 404/410 supaya agent bisa render landing page dengan DisabledMessage.
+func (h *ResolverHandler) respondDisabled(w http.ResponseWriter, nodeID string, link *models.Link) {
+	metrics.GetMetrics().IncrementBlockedRedirects(nodeID, "link_disabled")
+
+	if link.DisabledBehavior == models.DisabledBehaviorFallback && strings.TrimSpace(link.FallbackURL) != "" {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]string{
+			"target": link.FallbackURL,
+			"reason": "link_disabled",
+		})
 		return
 	}
 
-	if len(webhooks) == 0 {
-		return // No webhooks subscribed to this event
+	status := http.StatusNotFound
+	if link.DisabledBehavior == models.DisabledBehaviorGone {
+		status = http.StatusGone
 	}
 
-	payload := &models.WebhookPayload{
-		Event:     event,
-		Timestamp: time.Now(),
-		Data:      data,
-	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{
+		"reason":  "link_disabled",
+		"message": link.DisabledMessage,
+	})
+}
 
-	// Send webhooks in background
-	for _, wh := range webhooks {
-		go func(w models.Webhook) {
-			result, err := h.webhookSender.SendWebhook(ctx, &w, payload)
-			if err != nil {
-				log.Printf("Webhook error: event=%s url=%s error=%v", event, w.URL, err)
-			} else if !result.Success {
-				log.Printf("Webhook failed: event=%s url=%s status=%d", event, w.URL, result.StatusCode)
-			} else {
-				log.Printf("Webhook sent: event=%s url=%s status=%d", event, w.URL, result.StatusCode)
+// recordClick mengantrikan click event yang lolos ke target, trigger click.created webhook,
+// dan increment click count variant (kalau ada). Dipakai juga untuk click yang dikirim agent lewat /sync/clicks.
+func (h *ResolverHandler) recordClick(ctx context.Context, link *models.Link, clickEvent *models.ClickEvent) {
+	h.clicks.Submit(clickEvent)
+
+	h.triggerWebhook(ctx, models.EventClickCreated, map[string]interface{}{
+		"linkId":      link.ID,
+		"alias":       link.Alias,
+		"domain":      link.Domain,
+		"groupId":     link.GroupID,
+		"targetUrl":   link.TargetURL,
+		"nodeId":      clickEvent.NodeID,
+		"ipAddress":   clickEvent.IP,
+		"userAgent":   clickEvent.UserAgent,
+		"referer":     clickEvent.Referrer,
+		"country":     clickEvent.Country,
+		"city":        clickEvent.City,
+		"deviceType":  clickEvent.Device,
+		"osName":      clickEvent.OS,
+		"browserName": clickEvent.Browser,
+		"isBot":       clickEvent.IsBot,
+		"ruleId":      clickEvent.RuleID,
+		"timestamp":   clickEvent.CreatedAt.Format(time.RFC3339),
+	})
+
+	if clickEvent.VariantID != "" {
+		go func(vID string) {
+			if err := h.variantRepo.IncrementClicks(context.Background(), link.DataKey(), vID); err != nil {
+				log.Printf("Failed to increment variant clicks: %v", err)
 			}
-		}(wh)
+		}(clickEvent.VariantID)
+	}
+}
+
+// triggerWebhook menulis event ke outbox webhook; pengiriman + retry oleh webhook.Dispatcher
+func (h *ResolverHandler) triggerWebhook(ctx context.Context, event string, data map[string]interface{}) {
+	if err := h.webhooks.Publish(ctx, event, data); err != nil {
+		log.Printf("Failed to enqueue webhooks for event %s: %v", event, err)
 	}
 }
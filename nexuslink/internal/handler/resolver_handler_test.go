@@ -0,0 +1,177 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/afuzapratama/nexuslink/internal/ipcheck"
+	"github.com/afuzapratama/nexuslink/internal/models"
+	"github.com/afuzapratama/nexuslink/internal/repository/sqlstore"
+	"github.com/afuzapratama/nexuslink/internal/webhook"
+)
+
+func openTestDB(t *testing.T) *sqlstore.DB {
+	t.Helper()
+
+	db, err := sqlstore.Open(context.Background(), sqlstore.DialectSQLite, filepath.Join(t.TempDir(), "nexus.db"))
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func TestResolveDisabledLink(t *testing.T) {
+	ctx := context.Background()
+	links := sqlstore.NewLinkRepository(openTestDB(t))
+	h := &ResolverHandler{linkRepo: links}
+
+	cases := []struct {
+		alias, behavior, fallback, message string
+		wantStatus                         int
+		wantTarget                         string
+	}{
+		{alias: "default", wantStatus: http.StatusNotFound},
+		{alias: "notfound", behavior: models.DisabledBehaviorNotFound, message: "Campaign ended", wantStatus: http.StatusNotFound},
+		{alias: "gone", behavior: models.DisabledBehaviorGone, message: "Gone for good", wantStatus: http.StatusGone},
+		{alias: "fallback", behavior: models.DisabledBehaviorFallback, fallback: "https://fallback.example", wantStatus: http.StatusOK, wantTarget: "https://fallback.example"},
+		// fallback tanpa FallbackURL jatuh ke 404
+		{alias: "nofallback", behavior: models.DisabledBehaviorFallback, message: "Paused", wantStatus: http.StatusNotFound},
+	}
+	for _, c := range cases {
+		link := &models.Link{Alias: c.alias, TargetURL: "https://target.example", FallbackURL: c.fallback}
+		if err := links.Create(ctx, link); err != nil {
+			t.Fatalf("create %s: %v", c.alias, err)
+		}
+		link.IsActive = false
+		link.DisabledBehavior = c.behavior
+		link.DisabledMessage = c.message
+		if err := links.Update(ctx, link); err != nil {
+			t.Fatalf("update %s: %v", c.alias, err)
+		}
+
+		rec := httptest.NewRecorder()
+		h.HandleResolve(rec, httptest.NewRequest(http.MethodGet, "/links/resolve?alias="+c.alias, nil))
+
+		if rec.Code != c.wantStatus {
+			t.Errorf("%s: status = %d, want %d", c.alias, rec.Code, c.wantStatus)
+			continue
+		}
+		var body map[string]string
+		if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+			t.Errorf("%s: decode body: %v", c.alias, err)
+			continue
+		}
+		if body["reason"] != "link_disabled" {
+			t.Errorf("%s: reason = %q", c.alias, body["reason"])
+		}
+		if c.wantTarget != "" {
+			if body["target"] != c.wantTarget {
+				t.Errorf("%s: target = %q, want %q", c.alias, body["target"], c.wantTarget)
+			}
+		} else if body["message"] != c.message {
+			t.Errorf("%s: message = %q, want %q", c.alias, body["message"], c.message)
+		}
+	}
+}
+
+func TestResolveBlocksUABotBeforeIPChecks(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	links := sqlstore.NewLinkRepository(db)
+	settingsRepo := sqlstore.NewSettingsRepository(db)
+	ingester := &countingIngester{}
+
+	settings := models.DefaultSettings()
+	settings.EnableProxyCheck = true
+	settings.ProxyCheckAPIKey = "pc-key"
+	settings.EnableIPQualityScore = true
+	settings.IPQualityScoreAPIKey = "ipqs-key"
+	if err := settingsRepo.Update(ctx, settings); err != nil {
+		t.Fatal(err)
+	}
+	if err := links.Create(ctx, &models.Link{Alias: "promo", TargetURL: "https://target.example", IsActive: true, BlockBots: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Hitung panggilan ke provider berbayar
+	calls := 0
+	origPC, origIPQS := checkProxyCheck, checkIPQS
+	t.Cleanup(func() { checkProxyCheck, checkIPQS = origPC, origIPQS })
+	checkProxyCheck = func(ctx context.Context, ip, apiKey string) (*ipcheck.ProxyCheckResult, error) {
+		calls++
+		return &ipcheck.ProxyCheckResult{}, nil
+	}
+	checkIPQS = func(ctx context.Context, ip, apiKey string) (*ipcheck.IPQSResult, error) {
+		calls++
+		return &ipcheck.IPQSResult{}, nil
+	}
+
+	h := &ResolverHandler{
+		linkRepo:     links,
+		statsRepo:    sqlstore.NewLinkStatsRepository(db),
+		clicks:       ingester,
+		settingsRepo: settingsRepo,
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/links/resolve?alias=promo", nil)
+	req.Header.Set("X-Visitor-User-Agent", "curl/8.5.0")
+	req.Header.Set("X-Real-IP", "203.0.113.7")
+	rec := httptest.NewRecorder()
+	h.HandleResolve(rec, req)
+
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("status = %d, want 403: %s", rec.Code, rec.Body.String())
+	}
+	if calls != 0 {
+		t.Errorf("IP quality checks called %d times for UA bot, want 0", calls)
+	}
+	if ingester.submitted != 1 {
+		t.Errorf("clicks submitted = %d, want 1", ingester.submitted)
+	}
+}
+
+func TestResolveAliasCaseInsensitive(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	links := sqlstore.NewLinkRepository(db)
+	h := &ResolverHandler{
+		linkRepo:     links,
+		statsRepo:    sqlstore.NewLinkStatsRepository(db),
+		clicks:       &countingIngester{},
+		settingsRepo: sqlstore.NewSettingsRepository(db),
+		variantRepo:  sqlstore.NewLinkVariantRepository(db),
+		webhooks:     webhook.NewDispatcher(nil, sqlstore.NewWebhookRepository(db), sqlstore.NewWebhookOutboxRepository(db), 0, 0, time.Minute),
+	}
+
+	if err := links.Create(ctx, &models.Link{Alias: "Promo", TargetURL: "https://target.example", IsActive: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Reservasi alias case-insensitive, jadi resolve juga harus case-insensitive
+	for _, alias := range []string{"Promo", "promo", "PROMO"} {
+		rec := httptest.NewRecorder()
+		h.HandleResolve(rec, httptest.NewRequest(http.MethodGet, "/links/resolve?alias="+alias, nil))
+		if rec.Code != http.StatusOK {
+			t.Errorf("%s: status = %d, want 200: %s", alias, rec.Code, rec.Body.String())
+			continue
+		}
+		var body map[string]string
+		if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+			t.Errorf("%s: decode body: %v", alias, err)
+			continue
+		}
+		if body["targetUrl"] != "https://target.example" {
+			t.Errorf("%s: targetUrl = %q", alias, body["targetUrl"])
+		}
+	}
+
+	if got, err := links.GetByKey(ctx, "promo"); err != nil || got == nil || got.Alias != "Promo" {
+		t.Errorf("GetByKey(promo) = %+v, err %v", got, err)
+	}
+}
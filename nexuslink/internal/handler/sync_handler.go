@@ -0,0 +1,175 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/afuzapratama/nexuslink/internal/models"
+	"github.com/afuzapratama/nexuslink/internal/repository"
+)
+
+// syncOverlap: delta feed membaca ulang sedikit ke belakang supaya write yang telat commit tidak terlewat
+const syncOverlap = 5 * time.Second
+
+// maxSyncClicks adalah batas jumlah click per request POST /sync/clicks
+const maxSyncClicks = 1000
+
+// GET /sync/links?since=<version> - delta feed definisi link untuk agent
+func (h *ResolverHandler) HandleSyncLinks(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	var since int64
+	if v := strings.TrimSpace(r.URL.Query().Get("since")); v != "" {
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || n < 0 {
+			http.Error(w, "invalid since", http.StatusBadRequest)
+			return
+		}
+		since = n
+	}
+
+	// Version diambil sebelum baca data, jadi perubahan selama request ini ikut di feed berikutnya
+	version := time.Now().UTC().UnixNano()
+
+	// Agent baru atau yang ketinggalan lebih lama dari retensi tombstone dapat snapshot penuh
+	full := since == 0 || time.Since(time.Unix(0, since)) > repository.TombstoneRetention
+
+	query := int64(0)
+	if !full {
+		query = since - int64(syncOverlap)
+	}
+
+	links, err := h.linkRepo.ListChangedSince(r.Context(), query)
+	if err != nil {
+		log.Printf("linkRepo.ListChangedSince error: %v", err)
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+
+	feed := models.LinkFeed{
+		Version: version,
+		Full:    full,
+		Links:   make([]models.LinkSnapshot, 0, len(links)),
+	}
+
+	// Variants semua link di feed dimuat sekaligus (bukan satu query per link)
+	keys := make([]string, len(links))
+	for i := range links {
+		keys[i] = links[i].DataKey()
+	}
+	variants, err := h.variantRepo.GetByLinkIDs(r.Context(), keys)
+	if err != nil {
+		log.Printf("variantRepo.GetByLinkIDs error: %v", err)
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+	for i, link := range links {
+		feed.Links = append(feed.Links, models.LinkSnapshot{Link: link, Variants: variants[keys[i]]})
+	}
+
+	if !full {
+		tombstones, err := h.linkRepo.ListDeletedSince(r.Context(), query)
+		if err != nil {
+			log.Printf("linkRepo.ListDeletedSince error: %v", err)
+			http.Error(w, "internal error", http.StatusInternalServerError)
+			return
+		}
+		for _, t := range tombstones {
+			feed.Deleted = append(feed.Deleted, t.ID)
+		}
+	}
+
+	settings := h.settingsRepo.GetOrDefault(r.Context())
+	feed.IPCheckEnabled = (settings.EnableProxyCheck && strings.TrimSpace(settings.ProxyCheckAPIKey) != "") ||
+		(settings.EnableIPQualityScore && strings.TrimSpace(settings.IPQualityScoreAPIKey) != "")
+	feed.RateLimitEnabled = settings.EnableRateLimit && h.limiter != nil
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(feed)
+}
+
+// POST /sync/clicks - batch click events dari agent yang resolve link secara lokal
+func (h *ResolverHandler) HandleSyncClicks(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	var events []models.ClickEvent
+	if err := json.NewDecoder(r.Body).Decode(&events); err != nil {
+		http.Error(w, "invalid json", http.StatusBadRequest)
+		return
+	}
+	if len(events) > maxSyncClicks {
+		http.Error(w, "too many click events", http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	// Background context: webhook & variant increment tetap jalan setelah response dikirim
+	ctx := context.Background()
+	links := make(map[string]*models.Link)
+	credNodeID := NodeIDFromContext(r.Context())
+
+	for i := range events {
+		ev := &events[i]
+		if ev.Alias == "" {
+			continue
+		}
+		if credNodeID != "" {
+			ev.NodeID = credNodeID
+		}
+
+		link, ok := links[ev.Alias]
+		if !ok {
+			var err error
+			// Agent mengirim key data link (alias polos dari agent lama = link tanpa domain / link lama)
+			link, err = h.linkRepo.GetByKey(r.Context(), ev.Alias)
+			if err != nil {
+				log.Printf("linkRepo.GetByKey error: %v", err)
+				http.Error(w, "internal error", http.StatusInternalServerError)
+				return
+			}
+			links[ev.Alias] = link
+		}
+		if link == nil {
+			// Link sudah dihapus setelah click terjadi
+			continue
+		}
+
+		// Agent retry setelah timeout mengirim ulang click yang sama: ID yang sudah diproses dilewati
+		if h.clickDedup != nil && ev.ID != "" {
+			fresh, err := h.clickDedup.Claim(r.Context(), ev.ID)
+			if err != nil {
+				log.Printf("clickDedup.Claim error: %v", err)
+				http.Error(w, "internal error", http.StatusInternalServerError)
+				return
+			}
+			if !fresh {
+				continue
+			}
+		}
+
+		ev.Alias = link.DataKey()
+		if err := h.statsRepo.IncrementHit(r.Context(), ev.NodeID, ev.Alias); err != nil {
+			log.Printf("increment link stat failed: %v", err)
+		}
+
+		if ev.BlockReason != "" {
+			h.clicks.Submit(ev)
+			continue
+		}
+
+		h.recordClick(ctx, link, ev)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]int{"accepted": len(events)})
+}
@@ -0,0 +1,73 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/afuzapratama/nexuslink/internal/models"
+	"github.com/afuzapratama/nexuslink/internal/repository/sqlstore"
+	"github.com/afuzapratama/nexuslink/internal/webhook"
+)
+
+// countingIngester: ClickIngester yang hanya menghitung Submit
+type countingIngester struct{ submitted int }
+
+func (c *countingIngester) Submit(ev *models.ClickEvent)    { c.submitted++ }
+func (c *countingIngester) Close(ctx context.Context) error { return nil }
+
+func TestSyncClicksDedupesRetries(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	links := sqlstore.NewLinkRepository(db)
+	stats := sqlstore.NewLinkStatsRepository(db)
+	outbox := sqlstore.NewWebhookOutboxRepository(db)
+	webhooks := sqlstore.NewWebhookRepository(db)
+	ingester := &countingIngester{}
+
+	if err := links.Create(ctx, &models.Link{Alias: "promo", TargetURL: "https://target.example", IsActive: true}); err != nil {
+		t.Fatal(err)
+	}
+	if err := webhooks.Create(ctx, &models.Webhook{ID: "wh1", URL: "http://example.invalid", Events: []string{models.EventClickCreated}, IsActive: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	h := &ResolverHandler{
+		linkRepo:   links,
+		statsRepo:  stats,
+		clicks:     ingester,
+		webhooks:   webhook.NewDispatcher(nil, webhooks, outbox, 0, 0, time.Minute),
+		clickDedup: sqlstore.NewClickDedupRepository(db),
+	}
+
+	body, _ := json.Marshal([]models.ClickEvent{
+		{ID: "c1", Alias: "promo", NodeID: "n1"},
+		{ID: "c2", Alias: "promo", NodeID: "n1"},
+	})
+	// Request kedua = retry agent setelah timeout, isi sama persis
+	for i := 0; i < 2; i++ {
+		rec := httptest.NewRecorder()
+		h.HandleSyncClicks(rec, httptest.NewRequest(http.MethodPost, "/sync/clicks", bytes.NewReader(body)))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("attempt %d: status %d: %s", i, rec.Code, rec.Body.String())
+		}
+	}
+
+	if ingester.submitted != 2 {
+		t.Errorf("clicks submitted = %d, want 2", ingester.submitted)
+	}
+	if s, err := stats.Get(ctx, "n1", "promo"); err != nil || s == nil || s.HitCount != 2 {
+		t.Errorf("hit count = %+v (err %v), want 2", s, err)
+	}
+	entries, err := outbox.Claim(ctx, time.Now().Add(time.Hour), time.Minute, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 {
+		t.Errorf("webhook events enqueued = %d, want 2", len(entries))
+	}
+}
@@ -0,0 +1,229 @@
+package handler
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/afuzapratama/nexuslink/internal/models"
+	"github.com/afuzapratama/nexuslink/internal/repository"
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// minPasswordLength untuk user baru / ganti password
+const minPasswordLength = 8
+
+// UserHandler CRUD user admin dashboard (/admin/users, owner only)
+type UserHandler struct {
+	users repository.UserStore
+	auth  *AuthHandler
+	audit *Auditor
+}
+
+type userInput struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+	Role     string `json:"role"`
+}
+
+func NewUserHandler(users repository.UserStore, auth *AuthHandler, auditor *Auditor) *UserHandler {
+	return &UserHandler{users: users, auth: auth, audit: auditor}
+}
+
+// HandleUsers handles GET/POST /admin/users
+func (h *UserHandler) HandleUsers(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		users, err := h.users.List(r.Context())
+		if err != nil {
+			log.Printf("users: list failed: %v", err)
+			http.Error(w, "failed to list users", http.StatusInternalServerError)
+			return
+		}
+		infos := make([]models.UserInfo, 0, len(users))
+		for i := range users {
+			infos = append(infos, users[i].Info())
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(infos)
+
+	case http.MethodPost:
+		var in userInput
+		if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+			http.Error(w, "invalid json", http.StatusBadRequest)
+			return
+		}
+		in.Username = strings.ToLower(strings.TrimSpace(in.Username))
+		if in.Username == "" {
+			http.Error(w, "username is required", http.StatusBadRequest)
+			return
+		}
+		if in.Role == "" {
+			in.Role = models.RoleAnalyst
+		}
+		if !models.ValidRole(in.Role) {
+			http.Error(w, "role must be owner, editor or analyst", http.StatusBadRequest)
+			return
+		}
+		if len(in.Password) < minPasswordLength {
+			http.Error(w, "password must be at least 8 characters", http.StatusBadRequest)
+			return
+		}
+
+		existing, err := h.users.GetByUsername(r.Context(), in.Username)
+		if err != nil {
+			log.Printf("users: lookup %q failed: %v", in.Username, err)
+			http.Error(w, "failed to create user", http.StatusInternalServerError)
+			return
+		}
+		if existing != nil {
+			http.Error(w, "username already exists", http.StatusConflict)
+			return
+		}
+
+		hash, err := bcrypt.GenerateFromPassword([]byte(in.Password), bcrypt.DefaultCost)
+		if err != nil {
+			http.Error(w, "failed to hash password", http.StatusInternalServerError)
+			return
+		}
+
+		now := time.Now().UTC()
+		user := &models.User{
+			ID:           uuid.NewString(),
+			Username:     in.Username,
+			PasswordHash: string(hash),
+			Role:         in.Role,
+			CreatedAt:    now,
+			UpdatedAt:    now,
+		}
+		if err := h.users.Create(r.Context(), user); err != nil {
+			log.Printf("users: create %q failed: %v", in.Username, err)
+			http.Error(w, "failed to create user", http.StatusInternalServerError)
+			return
+		}
+		h.audit.Record(r, "user.create", "user", user.Username, nil, user)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(user.Info())
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// HandleUserByID handles GET/PUT/DELETE /admin/users/:id
+func (h *UserHandler) HandleUserByID(w http.ResponseWriter, r *http.Request) {
+	id := strings.Trim(strings.TrimPrefix(r.URL.Path, "/admin/users/"), "/")
+	if id == "" {
+		http.NotFound(w, r)
+		return
+	}
+
+	user, err := h.users.Get(r.Context(), id)
+	if err != nil {
+		log.Printf("users: get %s failed: %v", id, err)
+		http.Error(w, "failed to load user", http.StatusInternalServerError)
+		return
+	}
+	if user == nil {
+		http.Error(w, "user not found", http.StatusNotFound)
+		return
+	}
+	before := *user
+
+	switch r.Method {
+	case http.MethodGet:
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(user.Info())
+
+	case http.MethodPut:
+		var in userInput
+		if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+			http.Error(w, "invalid json", http.StatusBadRequest)
+			return
+		}
+
+		roleChanged := in.Role != "" && in.Role != user.Role
+		if roleChanged {
+			if !models.ValidRole(in.Role) {
+				http.Error(w, "role must be owner, editor or analyst", http.StatusBadRequest)
+				return
+			}
+			if user.Role == models.RoleOwner && !h.hasOtherOwner(w, r, user.ID) {
+				return
+			}
+			user.Role = in.Role
+		}
+
+		passwordChanged := in.Password != ""
+		if passwordChanged {
+			if len(in.Password) < minPasswordLength {
+				http.Error(w, "password must be at least 8 characters", http.StatusBadRequest)
+				return
+			}
+			hash, err := bcrypt.GenerateFromPassword([]byte(in.Password), bcrypt.DefaultCost)
+			if err != nil {
+				http.Error(w, "failed to hash password", http.StatusInternalServerError)
+				return
+			}
+			user.PasswordHash = string(hash)
+		}
+
+		user.UpdatedAt = time.Now().UTC()
+		if err := h.users.Update(r.Context(), user); err != nil {
+			log.Printf("users: update %s failed: %v", id, err)
+			http.Error(w, "failed to update user", http.StatusInternalServerError)
+			return
+		}
+		h.audit.Record(r, "user.update", "user", user.Username, &before, user)
+
+		// Ganti password = logout semua session; ganti role cukup refresh cache
+		if passwordChanged {
+			if err := h.auth.RevokeUser(r.Context(), user.ID); err != nil {
+				log.Printf("users: revoke sessions of %s failed: %v", id, err)
+			}
+		} else if roleChanged {
+			h.auth.InvalidateUser(user.ID)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(user.Info())
+
+	case http.MethodDelete:
+		if user.Role == models.RoleOwner && !h.hasOtherOwner(w, r, user.ID) {
+			return
+		}
+		if err := h.users.Delete(r.Context(), id); err != nil {
+			log.Printf("users: delete %s failed: %v", id, err)
+			http.Error(w, "failed to delete user", http.StatusInternalServerError)
+			return
+		}
+		h.audit.Record(r, "user.delete", "user", user.Username, user, nil)
+		if err := h.auth.RevokeUser(r.Context(), id); err != nil {
+			log.Printf("users: revoke sessions of %s failed: %v", id, err)
+		}
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// hasOtherOwner mencegah owner terakhir dihapus / diturunkan (response error sudah ditulis kalau false)
+func (h *UserHandler) hasOtherOwner(w http.ResponseWriter, r *http.Request, userID string) bool {
+	users, err := h.users.List(r.Context())
+	if err != nil {
+		log.Printf("users: list failed: %v", err)
+		http.Error(w, "failed to check owners", http.StatusInternalServerError)
+		return false
+	}
+	for _, u := range users {
+		if u.ID != userID && u.Role == models.RoleOwner {
+			return true
+		}
+	}
+	http.Error(w, "cannot remove the last owner", http.StatusConflict)
+	return false
+}
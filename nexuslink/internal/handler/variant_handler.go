@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"context"
 	"encoding/json"
 	"log"
 	"net/http"
@@ -12,14 +13,16 @@ import (
 )
 
 type VariantHandler struct {
-	variantRepo *repository.LinkVariantRepository
-	linkRepo    *repository.LinkRepository
+	variantRepo repository.VariantStore
+	linkRepo    repository.LinkStore
+	audit       *Auditor
 }
 
-func NewVariantHandler(variantRepo *repository.LinkVariantRepository, linkRepo *repository.LinkRepository) *VariantHandler {
+func NewVariantHandler(variantRepo repository.VariantStore, linkRepo repository.LinkStore, auditor *Auditor) *VariantHandler {
 	return &VariantHandler{
 		variantRepo: variantRepo,
 		linkRepo:    linkRepo,
+		audit:       auditor,
 	}
 }
 
@@ -38,15 +41,15 @@ func (h *VariantHandler) HandleGetVariants(w http.ResponseWriter, r *http.Reques
 	}
 	alias := pathParts[1]
 
-	// Verify link exists
-	_, err := h.linkRepo.GetByAlias(r.Context(), alias)
-	if err != nil {
-		http.Error(w, "Link not found", http.StatusNotFound)
+	// Verify link exists (?domain= kalau alias dipakai di beberapa domain)
+	link, ok := linkFromRequest(w, r, h.linkRepo, alias)
+	if !ok {
 		return
 	}
+	key := link.DataKey()
 
 	// Get variants
-	variants, err := h.variantRepo.GetByLinkID(r.Context(), alias)
+	variants, err := h.variantRepo.GetByLinkID(r.Context(), key)
 	if err != nil {
 		log.Printf("Error fetching variants for link %s: %v", alias, err)
 		http.Error(w, "Failed to fetch variants", http.StatusInternalServerError)
@@ -75,12 +78,12 @@ func (h *VariantHandler) HandleCreateVariant(w http.ResponseWriter, r *http.Requ
 	}
 	alias := pathParts[1]
 
-	// Verify link exists
-	_, err := h.linkRepo.GetByAlias(r.Context(), alias)
-	if err != nil {
-		http.Error(w, "Link not found", http.StatusNotFound)
+	// Verify link exists (?domain= kalau alias dipakai di beberapa domain)
+	link, ok := linkFromRequest(w, r, h.linkRepo, alias)
+	if !ok {
 		return
 	}
+	key := link.DataKey()
 
 	// Parse request body
 	var req struct {
@@ -108,7 +111,7 @@ func (h *VariantHandler) HandleCreateVariant(w http.ResponseWriter, r *http.Requ
 	}
 
 	// Check total weight won't exceed 100
-	existingVariants, err := h.variantRepo.GetByLinkID(r.Context(), alias)
+	existingVariants, err := h.variantRepo.GetByLinkID(r.Context(), key)
 	if err != nil {
 		log.Printf("Error checking existing variants: %v", err)
 		http.Error(w, "Failed to validate weight", http.StatusInternalServerError)
@@ -126,7 +129,7 @@ func (h *VariantHandler) HandleCreateVariant(w http.ResponseWriter, r *http.Requ
 	// Create variant
 	variant := &models.LinkVariant{
 		ID:          generateVariantID(),
-		LinkID:      alias,
+		LinkID:      key,
 		Label:       req.Label,
 		TargetURL:   req.TargetURL,
 		Weight:      req.Weight,
@@ -141,6 +144,8 @@ func (h *VariantHandler) HandleCreateVariant(w http.ResponseWriter, r *http.Requ
 		http.Error(w, "Failed to create variant", http.StatusInternalServerError)
 		return
 	}
+	h.touchLink(r.Context(), link)
+	h.audit.Record(r, "variant.create", "variant", key+"/"+variant.ID, nil, variant)
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusCreated)
@@ -163,12 +168,19 @@ func (h *VariantHandler) HandleUpdateVariant(w http.ResponseWriter, r *http.Requ
 	alias := pathParts[1]
 	variantID := pathParts[3]
 
+	link, ok := linkFromRequest(w, r, h.linkRepo, alias)
+	if !ok {
+		return
+	}
+	key := link.DataKey()
+
 	// Get existing variant
-	existing, err := h.variantRepo.GetByID(r.Context(), alias, variantID)
+	existing, err := h.variantRepo.GetByID(r.Context(), key, variantID)
 	if err != nil {
 		http.Error(w, "Variant not found", http.StatusNotFound)
 		return
 	}
+	before := *existing
 
 	// Parse request body
 	var req struct {
@@ -195,7 +207,7 @@ func (h *VariantHandler) HandleUpdateVariant(w http.ResponseWriter, r *http.Requ
 		}
 
 		// Check total weight won't exceed 100
-		allVariants, err := h.variantRepo.GetByLinkID(r.Context(), alias)
+		allVariants, err := h.variantRepo.GetByLinkID(r.Context(), key)
 		if err != nil {
 			log.Printf("Error checking existing variants: %v", err)
 			http.Error(w, "Failed to validate weight", http.StatusInternalServerError)
@@ -222,6 +234,8 @@ func (h *VariantHandler) HandleUpdateVariant(w http.ResponseWriter, r *http.Requ
 		http.Error(w, "Failed to update variant", http.StatusInternalServerError)
 		return
 	}
+	h.touchLink(r.Context(), link)
+	h.audit.Record(r, "variant.update", "variant", key+"/"+variantID, &before, existing)
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(existing)
@@ -243,18 +257,26 @@ func (h *VariantHandler) HandleDeleteVariant(w http.ResponseWriter, r *http.Requ
 	alias := pathParts[1]
 	variantID := pathParts[3]
 
+	link, ok := linkFromRequest(w, r, h.linkRepo, alias)
+	if !ok {
+		return
+	}
+	key := link.DataKey()
+
 	// Verify variant exists
-	_, err := h.variantRepo.GetByID(r.Context(), alias, variantID)
+	existing, err := h.variantRepo.GetByID(r.Context(), key, variantID)
 	if err != nil {
 		http.Error(w, "Variant not found", http.StatusNotFound)
 		return
 	}
 
-	if err := h.variantRepo.Delete(r.Context(), alias, variantID); err != nil {
+	if err := h.variantRepo.Delete(r.Context(), key, variantID); err != nil {
 		log.Printf("Error deleting variant: %v", err)
 		http.Error(w, "Failed to delete variant", http.StatusInternalServerError)
 		return
 	}
+	h.touchLink(r.Context(), link)
+	h.audit.Record(r, "variant.delete", "variant", key+"/"+variantID, existing, nil)
 
 	w.WriteHeader(http.StatusNoContent)
 }
@@ -281,24 +303,37 @@ func (h *VariantHandler) HandleConvert(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	link, ok := linkFromRequest(w, r, h.linkRepo, alias)
+	if !ok {
+		return
+	}
+	key := link.DataKey()
+
 	// Verify variant exists
-	_, err := h.variantRepo.GetByID(r.Context(), alias, variantID)
+	_, err := h.variantRepo.GetByID(r.Context(), key, variantID)
 	if err != nil {
 		http.Error(w, "Variant not found", http.StatusNotFound)
 		return
 	}
 
 	// Increment conversion counter
-	if err := h.variantRepo.IncrementConversions(r.Context(), alias, variantID); err != nil {
+	if err := h.variantRepo.IncrementConversions(r.Context(), key, variantID); err != nil {
 		log.Printf("Error incrementing conversion for variant %s: %v", variantID, err)
 		http.Error(w, "Failed to track conversion", http.StatusInternalServerError)
 		return
 	}
 
-	log.Printf("Conversion tracked for link %s, variant %s", alias, variantID)
+	log.Printf("Conversion tracked for link %s, variant %s", key, variantID)
 	w.WriteHeader(http.StatusNoContent)
 }
 
+// touchLink menaikkan Version link supaya perubahan variant ikut terkirim di delta feed agent
+func (h *VariantHandler) touchLink(ctx context.Context, link *models.Link) {
+	if err := h.linkRepo.Update(ctx, link); err != nil {
+		log.Printf("Error bumping link version for %s: %v", link.DataKey(), err)
+	}
+}
+
 // Helper function to generate variant ID
 func generateVariantID() string {
 	// Simple timestamp-based ID for now
@@ -0,0 +1,235 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/afuzapratama/nexuslink/internal/models"
+	"github.com/afuzapratama/nexuslink/internal/repository"
+	"github.com/afuzapratama/nexuslink/internal/webhook"
+)
+
+const (
+	deliveryDefaultLimit = 50
+	deliveryMaxLimit     = 500
+
+	// replayMaxBatch: batas dead letter yang di-replay sekali panggil
+	replayMaxBatch = 1000
+)
+
+// WebhookDeliveryHandler serves log delivery, dead letter dan replay per webhook
+type WebhookDeliveryHandler struct {
+	webhooks   repository.WebhookStore
+	deliveries repository.WebhookDeliveryStore
+	sender     *webhook.Sender
+	audit      *Auditor
+}
+
+func NewWebhookDeliveryHandler(webhooks repository.WebhookStore, deliveries repository.WebhookDeliveryStore, sender *webhook.Sender, auditor *Auditor) *WebhookDeliveryHandler {
+	return &WebhookDeliveryHandler{
+		webhooks:   webhooks,
+		deliveries: deliveries,
+		sender:     sender,
+		audit:      auditor,
+	}
+}
+
+// Handle handles /admin/webhooks/:id/deliveries/* dan /admin/webhooks/:id/dead-letters/*
+//
+//	GET    /admin/webhooks/:id/deliveries?status=&event=&since=&until=&limit=  - log delivery (terbaru dulu)
+//	GET    /admin/webhooks/:id/deliveries/:deliveryId                           - detail (payload + attempts)
+//	POST   /admin/webhooks/:id/deliveries/:deliveryId/replay                    - kirim ulang satu delivery
+//	GET    /admin/webhooks/:id/dead-letters?event=&since=&until=&limit=         - delivery yang gagal final
+//	POST   /admin/webhooks/:id/dead-letters/replay?event=&since=&until=         - kirim ulang semua dead letter di range
+//	DELETE /admin/webhooks/:id/dead-letters/:deliveryId                         - buang dead letter
+func (h *WebhookDeliveryHandler) Handle(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/admin/webhooks/"), "/"), "/")
+	if len(parts) < 2 {
+		http.NotFound(w, r)
+		return
+	}
+
+	wh, err := h.webhooks.GetByID(r.Context(), parts[0])
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	switch {
+	case parts[1] == "deliveries" && len(parts) == 2 && r.Method == http.MethodGet:
+		h.list(w, r, wh, false)
+	case parts[1] == "deliveries" && len(parts) == 3 && r.Method == http.MethodGet:
+		d, ok := h.loadDelivery(w, r, wh, parts[2])
+		if !ok {
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(d)
+	case parts[1] == "deliveries" && len(parts) == 4 && parts[3] == "replay" && r.Method == http.MethodPost:
+		d, ok := h.loadDelivery(w, r, wh, parts[2])
+		if !ok {
+			return
+		}
+		h.replayOne(w, r, wh, d)
+	case parts[1] == "dead-letters" && len(parts) == 2 && r.Method == http.MethodGet:
+		h.list(w, r, wh, true)
+	case parts[1] == "dead-letters" && len(parts) == 3 && parts[2] == "replay" && r.Method == http.MethodPost:
+		h.replayRange(w, r, wh)
+	case parts[1] == "dead-letters" && len(parts) == 3 && r.Method == http.MethodDelete:
+		if err := h.deliveries.DeleteDeadLetter(r.Context(), wh.ID, parts[2]); err != nil {
+			log.Printf("webhook deliveries: delete dead letter %s failed: %v", parts[2], err)
+			http.Error(w, "failed to delete dead letter", http.StatusInternalServerError)
+			return
+		}
+		h.audit.Record(r, "webhook.deadletter.delete", "webhook", wh.ID+"/"+parts[2], nil, nil)
+		w.WriteHeader(http.StatusNoContent)
+	case parts[1] == "deliveries" || parts[1] == "dead-letters":
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (h *WebhookDeliveryHandler) list(w http.ResponseWriter, r *http.Request, wh *models.Webhook, deadLetters bool) {
+	f, ok := deliveryFilter(w, r, wh.ID, deliveryDefaultLimit)
+	if !ok {
+		return
+	}
+
+	var (
+		deliveries []models.WebhookDelivery
+		err        error
+	)
+	if deadLetters {
+		deliveries, err = h.deliveries.ListDeadLetters(r.Context(), f)
+	} else {
+		deliveries, err = h.deliveries.List(r.Context(), f)
+	}
+	if err != nil {
+		log.Printf("webhook deliveries: list %s failed: %v", wh.ID, err)
+		http.Error(w, "failed to list deliveries", http.StatusInternalServerError)
+		return
+	}
+
+	if deliveries == nil {
+		deliveries = []models.WebhookDelivery{}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(deliveries)
+}
+
+// replayOne mengirim ulang satu delivery secara sinkron dan mengembalikan delivery baru
+func (h *WebhookDeliveryHandler) replayOne(w http.ResponseWriter, r *http.Request, wh *models.Webhook, d *models.WebhookDelivery) {
+	// Replay tetap jalan sampai selesai walau client disconnect
+	result, err := h.sender.Replay(context.WithoutCancel(r.Context()), wh, d)
+	if result == nil {
+		log.Printf("webhook deliveries: replay %s failed: %v", d.ID, err)
+		http.Error(w, "failed to replay delivery", http.StatusInternalServerError)
+		return
+	}
+	h.audit.Record(r, "webhook.replay", "webhook", wh.ID+"/"+d.ID, nil, map[string]interface{}{
+		"replayedAs": result.DeliveryID,
+	})
+
+	replayed, err := h.deliveries.Get(r.Context(), wh.ID, result.DeliveryID)
+	if err != nil || replayed == nil {
+		// Delivery log gagal ditulis: cukup kembalikan hasil attempt terakhir
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"id":         result.DeliveryID,
+			"success":    result.Success,
+			"statusCode": result.StatusCode,
+		})
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(replayed)
+}
+
+// replayRange mengirim ulang dead letter di range waktu di background (berurutan, lama dulu)
+func (h *WebhookDeliveryHandler) replayRange(w http.ResponseWriter, r *http.Request, wh *models.Webhook) {
+	f, ok := deliveryFilter(w, r, wh.ID, replayMaxBatch)
+	if !ok {
+		return
+	}
+
+	deadLetters, err := h.deliveries.ListDeadLetters(r.Context(), f)
+	if err != nil {
+		log.Printf("webhook deliveries: list dead letters %s failed: %v", wh.ID, err)
+		http.Error(w, "failed to list dead letters", http.StatusInternalServerError)
+		return
+	}
+	h.audit.Record(r, "webhook.replay", "webhook", wh.ID, nil, map[string]interface{}{
+		"deadLetters": len(deadLetters),
+		"since":       f.Since,
+		"until":       f.Until,
+	})
+
+	go func(wh models.Webhook) {
+		for i := len(deadLetters) - 1; i >= 0; i-- {
+			if _, err := h.sender.Replay(context.Background(), &wh, &deadLetters[i]); err != nil {
+				log.Printf("webhook deliveries: replay %s failed: %v", deadLetters[i].ID, err)
+			}
+		}
+	}(*wh)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"status": "queued",
+		"queued": len(deadLetters),
+	})
+}
+
+// loadDelivery returns delivery milik webhook; response error sudah ditulis kalau false
+func (h *WebhookDeliveryHandler) loadDelivery(w http.ResponseWriter, r *http.Request, wh *models.Webhook, id string) (*models.WebhookDelivery, bool) {
+	d, err := h.deliveries.Get(r.Context(), wh.ID, id)
+	if err != nil {
+		log.Printf("webhook deliveries: get %s failed: %v", id, err)
+		http.Error(w, "failed to load delivery", http.StatusInternalServerError)
+		return nil, false
+	}
+	if d == nil {
+		http.Error(w, "delivery not found", http.StatusNotFound)
+		return nil, false
+	}
+	return d, true
+}
+
+// deliveryFilter membaca ?status=&event=&since=&until=&limit= (since/until RFC3339)
+func deliveryFilter(w http.ResponseWriter, r *http.Request, webhookID string, defaultLimit int) (models.WebhookDeliveryFilter, bool) {
+	q := r.URL.Query()
+	f := models.WebhookDeliveryFilter{
+		WebhookID: webhookID,
+		Status:    strings.TrimSpace(q.Get("status")),
+		Event:     strings.TrimSpace(q.Get("event")),
+		Limit:     defaultLimit,
+	}
+	for _, p := range []struct {
+		name string
+		dst  *time.Time
+	}{{"since", &f.Since}, {"until", &f.Until}} {
+		if v := q.Get(p.name); v != "" {
+			t, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				http.Error(w, "invalid "+p.name+" format (use RFC3339)", http.StatusBadRequest)
+				return f, false
+			}
+			*p.dst = t
+		}
+	}
+	if v := q.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 || n > deliveryMaxLimit {
+			http.Error(w, "limit must be between 1 and 500", http.StatusBadRequest)
+			return f, false
+		}
+		f.Limit = n
+	}
+	return f, true
+}
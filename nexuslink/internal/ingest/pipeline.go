@@ -0,0 +1,307 @@
+package ingest
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/afuzapratama/nexuslink/internal/metrics"
+	"github.com/afuzapratama/nexuslink/internal/models"
+)
+
+// ClickIngester menerima click event dari request path tanpa blocking
+type ClickIngester interface {
+	Submit(ev *models.ClickEvent)
+	Close(ctx context.Context) error
+}
+
+// ClickWriter menyimpan batch click event ke storage (e.g. ClickRepository.LogClicks)
+type ClickWriter interface {
+	LogClicks(ctx context.Context, events []models.ClickEvent) error
+}
+
+// Options mengatur ukuran queue, batch dan lokasi spill file
+type Options struct {
+	QueueSize      int           // kapasitas queue in-memory
+	BatchSize      int           // jumlah event per flush
+	FlushInterval  time.Duration // flush walaupun batch belum penuh
+	WriteTimeout   time.Duration // timeout per flush
+	MaxRetries     int           // retry flush sebelum batch di-spill ke disk
+	SpillPath      string        // append-only file untuk overflow (kosong = overflow di-drop)
+	ReplayInterval time.Duration // seberapa sering spill file dicoba di-replay
+}
+
+// DefaultOptions returns the default pipeline options
+func DefaultOptions() Options {
+	return Options{
+		QueueSize:      10000,
+		BatchSize:      100,
+		FlushInterval:  time.Second,
+		WriteTimeout:   10 * time.Second,
+		MaxRetries:     3,
+		ReplayInterval: 30 * time.Second,
+	}
+}
+
+// ClickPipeline: Submit → bounded queue → batch writer. Overflow dan batch yang gagal ditulis
+// masuk ke spill file, lalu di-replay saat start dan secara berkala.
+type ClickPipeline struct {
+	writer ClickWriter
+	opts   Options
+	queue  chan models.ClickEvent
+	spill  *spillFile
+
+	// mu: Submit (read lock) tidak boleh mengantrikan event setelah Close mulai drain
+	mu     sync.RWMutex
+	closed bool
+	done   chan struct{}
+	wg     sync.WaitGroup
+}
+
+// NewClickPipeline creates a pipeline; call Start to begin flushing
+func NewClickPipeline(writer ClickWriter, opts Options) *ClickPipeline {
+	def := DefaultOptions()
+	if opts.QueueSize <= 0 {
+		opts.QueueSize = def.QueueSize
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = def.BatchSize
+	}
+	if opts.FlushInterval <= 0 {
+		opts.FlushInterval = def.FlushInterval
+	}
+	if opts.WriteTimeout <= 0 {
+		opts.WriteTimeout = def.WriteTimeout
+	}
+	if opts.MaxRetries < 0 {
+		opts.MaxRetries = 0
+	}
+	if opts.ReplayInterval <= 0 {
+		opts.ReplayInterval = def.ReplayInterval
+	}
+
+	p := &ClickPipeline{
+		writer: writer,
+		opts:   opts,
+		queue:  make(chan models.ClickEvent, opts.QueueSize),
+		done:   make(chan struct{}),
+	}
+	if opts.SpillPath != "" {
+		p.spill = &spillFile{path: opts.SpillPath}
+	}
+	return p
+}
+
+// Start menjalankan flush worker dan replay worker
+func (p *ClickPipeline) Start() {
+	p.wg.Add(1)
+	go p.flushLoop()
+
+	if p.spill != nil {
+		p.wg.Add(1)
+		go p.replayLoop()
+	}
+}
+
+// Submit mengantrikan click event. Kalau queue penuh, event ditulis ke spill file.
+func (p *ClickPipeline) Submit(ev *models.ClickEvent) {
+	if ev.ID == "" {
+		ev.ID = uuid.NewString()
+	}
+	if ev.CreatedAt.IsZero() {
+		ev.CreatedAt = time.Now().UTC()
+	}
+
+	p.mu.RLock()
+	if !p.closed {
+		select {
+		case p.queue <- *ev:
+			p.mu.RUnlock()
+			return
+		default:
+		}
+	}
+	p.mu.RUnlock()
+
+	p.spillEvents([]models.ClickEvent{*ev})
+}
+
+// Close berhenti menerima event, flush sisa queue, dan spill yang tidak sempat ditulis
+func (p *ClickPipeline) Close(ctx context.Context) error {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return nil
+	}
+	// Setelah lock dilepas semua event sudah ada di queue (di-drain flushLoop) atau masuk spill
+	p.closed = true
+	close(p.done)
+	p.mu.Unlock()
+
+	finished := make(chan struct{})
+	go func() {
+		p.wg.Wait()
+		close(finished)
+	}()
+
+	select {
+	case <-finished:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (p *ClickPipeline) flushLoop() {
+	defer p.wg.Done()
+
+	ticker := time.NewTicker(p.opts.FlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]models.ClickEvent, 0, p.opts.BatchSize)
+
+	for {
+		select {
+		case ev := <-p.queue:
+			batch = append(batch, ev)
+			if len(batch) >= p.opts.BatchSize {
+				p.flush(batch)
+				batch = batch[:0]
+			}
+
+		case <-ticker.C:
+			if len(batch) > 0 {
+				p.flush(batch)
+				batch = batch[:0]
+			}
+			metrics.GetMetrics().SetClickQueueDepth(int64(len(p.queue)))
+
+		case <-p.done:
+			// Drain sisa queue
+		drain:
+			for {
+				select {
+				case ev := <-p.queue:
+					batch = append(batch, ev)
+					if len(batch) >= p.opts.BatchSize {
+						p.flush(batch)
+						batch = batch[:0]
+					}
+				default:
+					break drain
+				}
+			}
+			if len(batch) > 0 {
+				p.flush(batch)
+			}
+			metrics.GetMetrics().SetClickQueueDepth(0)
+			return
+		}
+	}
+}
+
+// flush menulis batch dengan retry. Kalau tetap gagal, batch di-spill ke disk.
+func (p *ClickPipeline) flush(batch []models.ClickEvent) {
+	if err := p.write(batch); err != nil {
+		log.Printf("ingest: flush %d clicks failed after retries: %v", len(batch), err)
+		p.spillEvents(batch)
+	}
+}
+
+func (p *ClickPipeline) write(batch []models.ClickEvent) error {
+	backoff := 200 * time.Millisecond
+
+	var err error
+	for attempt := 0; attempt <= p.opts.MaxRetries; attempt++ {
+		if attempt > 0 {
+			time.Sleep(backoff)
+			backoff *= 2
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), p.opts.WriteTimeout)
+		start := time.Now()
+		err = p.writer.LogClicks(ctx, batch)
+		cancel()
+
+		metrics.GetMetrics().RecordClickFlush(len(batch), time.Since(start), err != nil)
+		if err == nil {
+			return nil
+		}
+	}
+	return err
+}
+
+func (p *ClickPipeline) spillEvents(events []models.ClickEvent) {
+	if p.spill == nil {
+		metrics.GetMetrics().AddClicksDropped(len(events))
+		log.Printf("ingest: dropped %d clicks (queue full, no spill file)", len(events))
+		return
+	}
+
+	if err := p.spill.append(events); err != nil {
+		metrics.GetMetrics().AddClicksDropped(len(events))
+		log.Printf("ingest: dropped %d clicks, spill failed: %v", len(events), err)
+		return
+	}
+	metrics.GetMetrics().AddClicksSpilled(len(events))
+}
+
+func (p *ClickPipeline) replayLoop() {
+	defer p.wg.Done()
+
+	p.replay()
+
+	ticker := time.NewTicker(p.opts.ReplayInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			// Jangan replay saat queue masih sibuk (spike belum reda)
+			if len(p.queue) < cap(p.queue)/2 {
+				p.replay()
+			}
+		case <-p.done:
+			return
+		}
+	}
+}
+
+// replay menulis ulang isi spill file ke storage. Event yang gagal dikembalikan ke spill file.
+func (p *ClickPipeline) replay() {
+	events, err := p.spill.takeAll()
+	if err != nil {
+		log.Printf("ingest: read spill file failed: %v", err)
+		return
+	}
+	if len(events) == 0 {
+		p.spill.done()
+		return
+	}
+
+	log.Printf("ingest: replaying %d spilled clicks", len(events))
+
+	for start := 0; start < len(events); start += p.opts.BatchSize {
+		end := start + p.opts.BatchSize
+		if end > len(events) {
+			end = len(events)
+		}
+
+		if err := p.write(events[start:end]); err != nil {
+			log.Printf("ingest: replay failed, %d clicks kept on disk: %v", len(events)-start, err)
+			if err := p.spill.append(events[start:]); err != nil {
+				// .replay file tidak dihapus, dicoba lagi di replay berikutnya
+				log.Printf("ingest: re-spill failed: %v", err)
+				return
+			}
+			break
+		}
+	}
+
+	if err := p.spill.done(); err != nil {
+		log.Printf("ingest: remove replayed spill file failed: %v", err)
+	}
+}
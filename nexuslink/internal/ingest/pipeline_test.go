@@ -0,0 +1,122 @@
+package ingest
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/afuzapratama/nexuslink/internal/models"
+)
+
+type fakeWriter struct {
+	mu      sync.Mutex
+	fail    bool
+	written map[string]bool
+}
+
+func (w *fakeWriter) LogClicks(ctx context.Context, events []models.ClickEvent) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.fail {
+		return errors.New("storage unavailable")
+	}
+	for _, ev := range events {
+		w.written[ev.ID] = true
+	}
+	return nil
+}
+
+func (w *fakeWriter) count() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return len(w.written)
+}
+
+func TestPipelineFlushOnClose(t *testing.T) {
+	w := &fakeWriter{written: map[string]bool{}}
+	p := NewClickPipeline(w, Options{BatchSize: 10, FlushInterval: time.Hour})
+	p.Start()
+
+	for i := 0; i < 25; i++ {
+		p.Submit(&models.ClickEvent{Alias: "promo"})
+	}
+
+	if err := p.Close(context.Background()); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	if got := w.count(); got != 25 {
+		t.Errorf("written mismatch: got %d, want 25", got)
+	}
+}
+
+func TestPipelineCloseWaitsForInflightSubmit(t *testing.T) {
+	w := &fakeWriter{written: map[string]bool{}}
+	p := NewClickPipeline(w, Options{BatchSize: 10, FlushInterval: time.Hour})
+	p.Start()
+
+	// Submit yang sedang berjalan (read lock dipegang) belum sempat masuk queue saat Close dipanggil
+	p.mu.RLock()
+	closed := make(chan error, 1)
+	go func() { closed <- p.Close(context.Background()) }()
+
+	select {
+	case <-closed:
+		t.Fatal("Close returned while a Submit was in flight")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	p.queue <- models.ClickEvent{ID: "inflight", Alias: "promo"}
+	p.mu.RUnlock()
+
+	if err := <-closed; err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	if got := w.count(); got != 1 {
+		t.Errorf("written = %d, want in-flight event flushed", got)
+	}
+
+	// Submit setelah Close tidak masuk queue lagi (tanpa spill file → drop)
+	p.Submit(&models.ClickEvent{Alias: "promo"})
+	if n := len(p.queue); n != 0 {
+		t.Errorf("queue has %d events after Close", n)
+	}
+}
+
+func TestPipelineSpillAndReplay(t *testing.T) {
+	spillPath := filepath.Join(t.TempDir(), "clicks-spill.jsonl")
+
+	// Storage down: semua batch gagal lalu di-spill ke disk
+	w := &fakeWriter{fail: true, written: map[string]bool{}}
+	p := NewClickPipeline(w, Options{QueueSize: 2, BatchSize: 5, FlushInterval: time.Hour, SpillPath: spillPath})
+	for i := 0; i < 5; i++ {
+		// Belum Start: queue penuh setelah 2 event, sisanya langsung di-spill
+		p.Submit(&models.ClickEvent{Alias: "promo"})
+	}
+	p.Start()
+	if err := p.Close(context.Background()); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	if got := w.count(); got != 0 {
+		t.Fatalf("expected nothing written, got %d", got)
+	}
+
+	// Restart dengan storage sehat: spill file di-replay
+	w.fail = false
+	p = NewClickPipeline(w, Options{BatchSize: 2, FlushInterval: time.Hour, SpillPath: spillPath})
+	p.Start()
+	if err := p.Close(context.Background()); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	if got := w.count(); got != 5 {
+		t.Errorf("replayed mismatch: got %d, want 5", got)
+	}
+
+	events, err := p.spill.takeAll()
+	if err != nil || len(events) != 0 {
+		t.Errorf("spill file should be empty after replay, got %d events (err=%v)", len(events), err)
+	}
+}
@@ -0,0 +1,126 @@
+package ingest
+
+import (
+	"bufio"
+	"encoding/json"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/afuzapratama/nexuslink/internal/models"
+)
+
+// spillFile adalah append-only JSON lines file untuk click event yang belum bisa ditulis ke storage
+type spillFile struct {
+	mu   sync.Mutex
+	path string
+}
+
+func (s *spillFile) append(events []models.ClickEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(s.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+
+	w := bufio.NewWriter(f)
+	enc := json.NewEncoder(w)
+	for i := range events {
+		if err := enc.Encode(&events[i]); err != nil {
+			f.Close()
+			return err
+		}
+	}
+	if err := w.Flush(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// takeAll memindahkan spill file ke .replay lalu membaca semua event di dalamnya.
+// File .replay sisa replay sebelumnya (crash) ikut dibaca.
+func (s *spillFile) takeAll() ([]models.ClickEvent, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	replayPath := s.path + ".replay"
+
+	// Pindahkan file aktif ke .replay (append ke .replay lama kalau masih ada)
+	if _, err := os.Stat(s.path); err == nil {
+		if _, err := os.Stat(replayPath); os.IsNotExist(err) {
+			if err := os.Rename(s.path, replayPath); err != nil {
+				return nil, err
+			}
+		} else {
+			data, err := os.ReadFile(s.path)
+			if err != nil {
+				return nil, err
+			}
+			f, err := os.OpenFile(replayPath, os.O_APPEND|os.O_WRONLY, 0o600)
+			if err != nil {
+				return nil, err
+			}
+			if _, err := f.Write(data); err != nil {
+				f.Close()
+				return nil, err
+			}
+			if err := f.Close(); err != nil {
+				return nil, err
+			}
+			if err := os.Remove(s.path); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	f, err := os.Open(replayPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	var events []models.ClickEvent
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var ev models.ClickEvent
+		if err := json.Unmarshal(scanner.Bytes(), &ev); err != nil {
+			// Baris terakhir bisa terpotong kalau proses mati saat append
+			log.Printf("ingest: skipping corrupt spill line: %v", err)
+			continue
+		}
+		events = append(events, ev)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+// done menghapus file .replay setelah semua event hasil takeAll ditulis (atau di-append lagi ke spill file).
+// Kalau proses mati sebelum done, event di .replay dibaca ulang saat start (ClickEvent.ID membuat write idempotent).
+func (s *spillFile) done() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.Remove(s.path + ".replay"); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
@@ -36,6 +36,13 @@ type IPQSAPIResponse struct {
 // CheckIPWithIPQS melakukan pengecekan IP menggunakan IPQualityScore API
 // API doc: https://www.ipqualityscore.com/documentation/proxy-detection/overview
 func CheckIPWithIPQS(ctx context.Context, ip, apiKey string) (*IPQSResult, error) {
+	start := time.Now()
+	result, err := checkIPWithIPQS(ctx, ip, apiKey)
+	observe("ipqualityscore", start, err)
+	return result, err
+}
+
+func checkIPWithIPQS(ctx context.Context, ip, apiKey string) (*IPQSResult, error) {
 	if ip == "" {
 		return nil, fmt.Errorf("ip address is required")
 	}
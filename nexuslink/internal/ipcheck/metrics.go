@@ -0,0 +1,16 @@
+package ipcheck
+
+import (
+	"time"
+
+	"github.com/afuzapratama/nexuslink/internal/metrics"
+)
+
+// observe mencatat hasil dan latency lookup ke provider
+func observe(provider string, start time.Time, err error) {
+	result := "ok"
+	if err != nil {
+		result = "error"
+	}
+	metrics.GetMetrics().RecordIPCheck(provider, result, time.Since(start))
+}
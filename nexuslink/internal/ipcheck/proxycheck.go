@@ -31,6 +31,13 @@ type ProxyCheckAPIResponse struct {
 // CheckIPWithProxyCheck melakukan pengecekan IP menggunakan ProxyCheck.io API
 // API doc: https://proxycheck.io/api/
 func CheckIPWithProxyCheck(ctx context.Context, ip, apiKey string) (*ProxyCheckResult, error) {
+	start := time.Now()
+	result, err := checkIPWithProxyCheck(ctx, ip, apiKey)
+	observe("proxycheck", start, err)
+	return result, err
+}
+
+func checkIPWithProxyCheck(ctx context.Context, ip, apiKey string) (*ProxyCheckResult, error) {
 	if ip == "" {
 		return nil, fmt.Errorf("ip address is required")
 	}
@@ -3,6 +3,7 @@ package metrics
 import (
 	"net/http"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 )
@@ -11,28 +12,44 @@ import (
 type Metrics struct {
 	mu sync.RWMutex
 
-	// Request metrics
-	RequestsTotal   map[string]int64   // by endpoint
-	RequestDuration map[string][]int64 // by endpoint (milliseconds)
-	RequestErrors   map[string]int64   // by endpoint
+	// Request metrics (label endpoint = pattern ServeMux, bukan path mentah)
+	requestsTotal   *counterVec   // endpoint, status
+	requestDuration *histogramVec // endpoint
 
-	// Link metrics
-	LinksTotal       int64
-	LinksActive      int64
-	RedirectsTotal   int64
-	RedirectsBlocked int64
+	// Redirect metrics
+	redirectsTotal   *counterVec // node
+	redirectsBlocked *counterVec // node, reason
 
 	// Rate limit metrics
-	RateLimitHits   int64
-	RateLimitMisses int64
+	rateLimitChecks   *counterVec // result
+	rateLimitFallback *counterVec
+
+	// Dependency metrics
+	ipcheckRequests  *counterVec   // provider, result
+	ipcheckDuration  *histogramVec // provider
+	dynamoRequests   *counterVec   // operation, status
+	dynamoDuration   *histogramVec // operation
+	webhookDelivered *counterVec   // event, status
+	webhookDuration  *histogramVec // event
+
+	// Link metrics
+	LinksTotal  int64
+	LinksActive int64
 
 	// Node metrics
 	NodesOnline  int64
 	NodesOffline int64
 
+	// Click ingestion pipeline metrics
+	ClickQueueDepth    int64
+	ClicksWritten      int64
+	ClicksSpilled      int64
+	ClicksDropped      int64
+	ClickFlushErrors   int64
+	clickFlushDuration *histogramVec
+
 	// System metrics
-	StartTime       time.Time
-	LastRequestTime time.Time
+	StartTime time.Time
 }
 
 var (
@@ -43,64 +60,83 @@ var (
 // GetMetrics returns the global metrics instance
 func GetMetrics() *Metrics {
 	once.Do(func() {
-		globalMetrics = &Metrics{
-			RequestsTotal:   make(map[string]int64),
-			RequestDuration: make(map[string][]int64),
-			RequestErrors:   make(map[string]int64),
-			StartTime:       time.Now(),
-		}
+		globalMetrics = newMetrics()
 	})
 	return globalMetrics
 }
 
-// RecordRequest records a request metric
-func (m *Metrics) RecordRequest(endpoint string, duration time.Duration, isError bool) {
-	m.mu.Lock()
-	defer m.mu.Unlock()
+func newMetrics() *Metrics {
+	return &Metrics{
+		requestsTotal:   newCounterVec("nexus_requests_total", "Total number of HTTP requests by endpoint and status", "endpoint", "status"),
+		requestDuration: newHistogramVec("nexus_request_duration_seconds", "HTTP request duration in seconds by endpoint", DefaultBuckets, "endpoint"),
 
-	m.RequestsTotal[endpoint]++
-	m.LastRequestTime = time.Now()
+		redirectsTotal:   newCounterVec("nexus_redirects_total", "Total number of successful redirects by node", "node"),
+		redirectsBlocked: newCounterVec("nexus_redirects_blocked_total", "Total number of blocked or denied redirects by node and reason", "node", "reason"),
 
-	// Store duration in milliseconds
-	durationMs := duration.Milliseconds()
-	m.RequestDuration[endpoint] = append(m.RequestDuration[endpoint], durationMs)
+		rateLimitChecks:   newCounterVec("nexus_ratelimit_checks_total", "Total number of rate limit checks by result (allowed, limited)", "result"),
+		rateLimitFallback: newCounterVec("nexus_ratelimit_fallback_total", "Total number of rate limit checks decided by the in-process fallback (Redis unavailable)"),
 
-	// Keep only last 1000 durations per endpoint
-	if len(m.RequestDuration[endpoint]) > 1000 {
-		m.RequestDuration[endpoint] = m.RequestDuration[endpoint][1:]
-	}
+		ipcheckRequests:  newCounterVec("nexus_ipcheck_requests_total", "Total number of IP quality lookups by provider and result", "provider", "result"),
+		ipcheckDuration:  newHistogramVec("nexus_ipcheck_duration_seconds", "IP quality lookup duration in seconds by provider", DefaultBuckets, "provider"),
+		dynamoRequests:   newCounterVec("nexus_dynamodb_requests_total", "Total number of DynamoDB calls by operation and status", "operation", "status"),
+		dynamoDuration:   newHistogramVec("nexus_dynamodb_duration_seconds", "DynamoDB call duration in seconds by operation", DefaultBuckets, "operation"),
+		webhookDelivered: newCounterVec("nexus_webhook_deliveries_total", "Total number of webhook deliveries by event and status", "event", "status"),
+		webhookDuration:  newHistogramVec("nexus_webhook_delivery_duration_seconds", "Webhook delivery duration in seconds (including retries) by event", DefaultBuckets, "event"),
 
-	if isError {
-		m.RequestErrors[endpoint]++
+		clickFlushDuration: newHistogramVec("nexus_click_flush_duration_seconds", "Click batch flush duration in seconds", DefaultBuckets),
+
+		StartTime: time.Now(),
 	}
 }
 
+// RecordRequest records a request metric
+func (m *Metrics) RecordRequest(endpoint string, status int, duration time.Duration) {
+	m.requestsTotal.Inc(endpoint, strconv.Itoa(status))
+	m.requestDuration.ObserveDuration(duration, endpoint)
+}
+
 // IncrementRedirects increments redirect counter
-func (m *Metrics) IncrementRedirects() {
-	m.mu.Lock()
-	defer m.mu.Unlock()
-	m.RedirectsTotal++
+func (m *Metrics) IncrementRedirects(node string) {
+	m.redirectsTotal.Inc(node)
 }
 
 // IncrementBlockedRedirects increments blocked redirect counter
-func (m *Metrics) IncrementBlockedRedirects() {
-	m.mu.Lock()
-	defer m.mu.Unlock()
-	m.RedirectsBlocked++
+func (m *Metrics) IncrementBlockedRedirects(node, reason string) {
+	m.redirectsBlocked.Inc(node, reason)
 }
 
 // RecordRateLimitCheck records rate limit check result
 func (m *Metrics) RecordRateLimitCheck(allowed bool) {
-	m.mu.Lock()
-	defer m.mu.Unlock()
-
 	if allowed {
-		m.RateLimitMisses++
+		m.rateLimitChecks.Inc("allowed")
 	} else {
-		m.RateLimitHits++
+		m.rateLimitChecks.Inc("limited")
 	}
 }
 
+// RecordRateLimitFallback records a rate limit check decided without Redis
+func (m *Metrics) RecordRateLimitFallback() {
+	m.rateLimitFallback.Inc()
+}
+
+// RecordIPCheck records one IP quality lookup (result: ok / error)
+func (m *Metrics) RecordIPCheck(provider, result string, duration time.Duration) {
+	m.ipcheckRequests.Inc(provider, result)
+	m.ipcheckDuration.ObserveDuration(duration, provider)
+}
+
+// RecordDynamoCall records one DynamoDB API call (status: ok atau error code dari AWS)
+func (m *Metrics) RecordDynamoCall(operation, status string, duration time.Duration) {
+	m.dynamoRequests.Inc(operation, status)
+	m.dynamoDuration.ObserveDuration(duration, operation)
+}
+
+// RecordWebhookDelivery records the final outcome of one webhook delivery
+func (m *Metrics) RecordWebhookDelivery(event, status string, duration time.Duration) {
+	m.webhookDelivered.Inc(event, status)
+	m.webhookDuration.ObserveDuration(duration, event)
+}
+
 // UpdateNodeCount updates online/offline node counts
 func (m *Metrics) UpdateNodeCount(online, offline int64) {
 	m.mu.Lock()
@@ -119,87 +155,86 @@ func (m *Metrics) UpdateLinkCount(total, active int64) {
 	m.LinksActive = active
 }
 
-// GetPrometheusMetrics returns metrics in Prometheus format
-func (m *Metrics) GetPrometheusMetrics() string {
-	m.mu.RLock()
-	defer m.mu.RUnlock()
+// SetClickQueueDepth updates the click pipeline queue depth gauge
+func (m *Metrics) SetClickQueueDepth(depth int64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.ClickQueueDepth = depth
+}
 
-	var output string
+// RecordClickFlush records one batch flush of the click pipeline
+func (m *Metrics) RecordClickFlush(written int, duration time.Duration, isError bool) {
+	m.clickFlushDuration.ObserveDuration(duration)
 
-	// System metrics
-	uptime := time.Since(m.StartTime).Seconds()
-	output += "# HELP nexus_uptime_seconds Application uptime in seconds\n"
-	output += "# TYPE nexus_uptime_seconds counter\n"
-	output += "nexus_uptime_seconds " + strconv.FormatFloat(uptime, 'f', 2, 64) + "\n\n"
-
-	// Request metrics
-	output += "# HELP nexus_requests_total Total number of requests by endpoint\n"
-	output += "# TYPE nexus_requests_total counter\n"
-	for endpoint, count := range m.RequestsTotal {
-		output += "nexus_requests_total{endpoint=\"" + endpoint + "\"} " + strconv.FormatInt(count, 10) + "\n"
-	}
-	output += "\n"
+	m.mu.Lock()
+	defer m.mu.Unlock()
 
-	// Error metrics
-	output += "# HELP nexus_requests_errors_total Total number of request errors by endpoint\n"
-	output += "# TYPE nexus_requests_errors_total counter\n"
-	for endpoint, count := range m.RequestErrors {
-		output += "nexus_requests_errors_total{endpoint=\"" + endpoint + "\"} " + strconv.FormatInt(count, 10) + "\n"
-	}
-	output += "\n"
-
-	// Average duration per endpoint
-	output += "# HELP nexus_request_duration_avg_ms Average request duration in milliseconds\n"
-	output += "# TYPE nexus_request_duration_avg_ms gauge\n"
-	for endpoint, durations := range m.RequestDuration {
-		if len(durations) > 0 {
-			var sum int64
-			for _, d := range durations {
-				sum += d
-			}
-			avg := sum / int64(len(durations))
-			output += "nexus_request_duration_avg_ms{endpoint=\"" + endpoint + "\"} " + strconv.FormatInt(avg, 10) + "\n"
-		}
+	if isError {
+		m.ClickFlushErrors++
+		return
 	}
-	output += "\n"
-
-	// Link metrics
-	output += "# HELP nexus_links_total Total number of links\n"
-	output += "# TYPE nexus_links_total gauge\n"
-	output += "nexus_links_total " + strconv.FormatInt(m.LinksTotal, 10) + "\n\n"
-
-	output += "# HELP nexus_links_active Number of active links\n"
-	output += "# TYPE nexus_links_active gauge\n"
-	output += "nexus_links_active " + strconv.FormatInt(m.LinksActive, 10) + "\n\n"
+	m.ClicksWritten += int64(written)
+}
 
-	// Redirect metrics
-	output += "# HELP nexus_redirects_total Total number of successful redirects\n"
-	output += "# TYPE nexus_redirects_total counter\n"
-	output += "nexus_redirects_total " + strconv.FormatInt(m.RedirectsTotal, 10) + "\n\n"
+// AddClicksSpilled increments the counter of click events spilled to disk
+func (m *Metrics) AddClicksSpilled(n int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.ClicksSpilled += int64(n)
+}
 
-	output += "# HELP nexus_redirects_blocked_total Total number of blocked redirects\n"
-	output += "# TYPE nexus_redirects_blocked_total counter\n"
-	output += "nexus_redirects_blocked_total " + strconv.FormatInt(m.RedirectsBlocked, 10) + "\n\n"
+// AddClicksDropped increments the counter of click events that were lost
+func (m *Metrics) AddClicksDropped(n int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.ClicksDropped += int64(n)
+}
 
-	// Rate limit metrics
-	output += "# HELP nexus_ratelimit_hits_total Total number of rate limit hits (blocked)\n"
-	output += "# TYPE nexus_ratelimit_hits_total counter\n"
-	output += "nexus_ratelimit_hits_total " + strconv.FormatInt(m.RateLimitHits, 10) + "\n\n"
+// GetPrometheusMetrics returns metrics in Prometheus text exposition format
+func (m *Metrics) GetPrometheusMetrics() string {
+	var b strings.Builder
 
-	output += "# HELP nexus_ratelimit_misses_total Total number of rate limit misses (allowed)\n"
-	output += "# TYPE nexus_ratelimit_misses_total counter\n"
-	output += "nexus_ratelimit_misses_total " + strconv.FormatInt(m.RateLimitMisses, 10) + "\n\n"
+	// System metrics
+	writeGauge(&b, "nexus_uptime_seconds", "Application uptime in seconds", time.Since(m.StartTime).Seconds())
+
+	// Request, redirect, dependency metrics
+	m.requestsTotal.write(&b)
+	m.requestDuration.write(&b)
+	m.redirectsTotal.write(&b)
+	m.redirectsBlocked.write(&b)
+	m.rateLimitChecks.write(&b)
+	m.rateLimitFallback.write(&b)
+	m.ipcheckRequests.write(&b)
+	m.ipcheckDuration.write(&b)
+	m.dynamoRequests.write(&b)
+	m.dynamoDuration.write(&b)
+	m.webhookDelivered.write(&b)
+	m.webhookDuration.write(&b)
 
-	// Node metrics
-	output += "# HELP nexus_nodes_online Number of online nodes\n"
-	output += "# TYPE nexus_nodes_online gauge\n"
-	output += "nexus_nodes_online " + strconv.FormatInt(m.NodesOnline, 10) + "\n\n"
+	m.mu.RLock()
+	defer m.mu.RUnlock()
 
-	output += "# HELP nexus_nodes_offline Number of offline nodes\n"
-	output += "# TYPE nexus_nodes_offline gauge\n"
-	output += "nexus_nodes_offline " + strconv.FormatInt(m.NodesOffline, 10) + "\n\n"
+	// Link & node metrics
+	writeGauge(&b, "nexus_links_total", "Total number of links", float64(m.LinksTotal))
+	writeGauge(&b, "nexus_links_active", "Number of active links", float64(m.LinksActive))
+	writeGauge(&b, "nexus_nodes_online", "Number of online nodes", float64(m.NodesOnline))
+	writeGauge(&b, "nexus_nodes_offline", "Number of offline nodes", float64(m.NodesOffline))
+
+	// Click pipeline metrics
+	writeGauge(&b, "nexus_click_queue_depth", "Number of click events waiting in the in-memory queue", float64(m.ClickQueueDepth))
+	writeCounter(&b, "nexus_clicks_written_total", "Total number of click events written to storage", m.ClicksWritten)
+	writeCounter(&b, "nexus_clicks_spilled_total", "Total number of click events spilled to disk", m.ClicksSpilled)
+	writeCounter(&b, "nexus_clicks_dropped_total", "Total number of click events dropped", m.ClicksDropped)
+	writeCounter(&b, "nexus_click_flush_errors_total", "Total number of failed click batch flushes", m.ClickFlushErrors)
+	m.clickFlushDuration.write(&b)
+
+	return b.String()
+}
 
-	return output
+func writeCounter(b *strings.Builder, name, help string, v int64) {
+	writeHeader(b, name, help, "counter")
+	writeSample(b, name, nil, nil, "", "", float64(v))
+	b.WriteString("\n")
 }
 
 // MetricsHandler returns HTTP handler for /metrics endpoint
@@ -0,0 +1,90 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHistogramExposition(t *testing.T) {
+	h := newHistogramVec("test_duration_seconds", "test", []float64{0.1, 1}, "op")
+	h.Observe(0.05, "get")
+	h.Observe(0.5, "get")
+	h.Observe(3, "get")
+
+	var b strings.Builder
+	h.write(&b)
+	out := b.String()
+
+	for _, want := range []string{
+		"# TYPE test_duration_seconds histogram",
+		`test_duration_seconds_bucket{op="get",le="0.1"} 1`,
+		`test_duration_seconds_bucket{op="get",le="1"} 2`,
+		`test_duration_seconds_bucket{op="get",le="+Inf"} 3`,
+		`test_duration_seconds_sum{op="get"} 3.55`,
+		`test_duration_seconds_count{op="get"} 3`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %q in:\n%s", want, out)
+		}
+	}
+}
+
+func TestCounterLabelEscaping(t *testing.T) {
+	c := newCounterVec("test_total", "test", "reason")
+	c.Inc(`a"b\c`)
+	c.Add(2, `a"b\c`)
+
+	var b strings.Builder
+	c.write(&b)
+
+	if want := `test_total{reason="a\"b\\c"} 3`; !strings.Contains(b.String(), want) {
+		t.Errorf("missing %q in:\n%s", want, b.String())
+	}
+}
+
+func TestMiddlewareUsesMuxPattern(t *testing.T) {
+	m := GetMetrics()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/r/", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "nope", http.StatusForbidden)
+	})
+	h := Middleware(mux)
+
+	for _, path := range []string{"/r/abc", "/r/def"} {
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/nothing", nil))
+
+	out := m.GetPrometheusMetrics()
+	for _, want := range []string{
+		`nexus_requests_total{endpoint="/r/",status="403"} 2`,
+		`nexus_requests_total{endpoint="unmatched",status="404"} 1`,
+		`nexus_request_duration_seconds_count{endpoint="/r/"} 2`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %q in:\n%s", want, out)
+		}
+	}
+}
+
+func TestRecordDependencies(t *testing.T) {
+	m := newMetrics()
+	m.RecordDynamoCall("PutItem", "ok", 20*time.Millisecond)
+	m.RecordIPCheck("proxycheck", "error", time.Second)
+	m.IncrementBlockedRedirects("node-1", "vpn_blocked")
+
+	out := m.GetPrometheusMetrics()
+	for _, want := range []string{
+		`nexus_dynamodb_requests_total{operation="PutItem",status="ok"} 1`,
+		`nexus_ipcheck_requests_total{provider="proxycheck",result="error"} 1`,
+		`nexus_redirects_blocked_total{node="node-1",reason="vpn_blocked"} 1`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %q in:\n%s", want, out)
+		}
+	}
+}
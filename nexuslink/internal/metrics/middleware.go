@@ -0,0 +1,52 @@
+package metrics
+
+import (
+	"net/http"
+	"time"
+)
+
+// statusRecorder menangkap status code yang ditulis handler
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(code int) {
+	if r.status == 0 {
+		r.status = code
+	}
+	r.ResponseWriter.WriteHeader(code)
+}
+
+func (r *statusRecorder) Write(b []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	return r.ResponseWriter.Write(b)
+}
+
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+// Middleware mencatat jumlah request dan latency per endpoint.
+// Endpoint diambil dari pattern ServeMux (r.Pattern) supaya cardinality tetap kecil
+// (/r/{alias} atau /admin/nodes/ tidak pecah per alias/ID).
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rec := &statusRecorder{ResponseWriter: w}
+
+		next.ServeHTTP(rec, r)
+
+		endpoint := r.Pattern
+		if endpoint == "" {
+			endpoint = "unmatched"
+		}
+		status := rec.status
+		if status == 0 {
+			status = http.StatusOK
+		}
+		GetMetrics().RecordRequest(endpoint, status, time.Since(start))
+	})
+}
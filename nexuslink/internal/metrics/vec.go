@@ -0,0 +1,189 @@
+package metrics
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Bucket default (detik) untuk latency HTTP, Dynamo, ipcheck dan webhook
+var DefaultBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// labelSep memisahkan label value di key map (tidak mungkin muncul di label normal)
+const labelSep = "\xff"
+
+// counterVec adalah counter dengan label
+type counterVec struct {
+	name   string
+	help   string
+	labels []string
+
+	mu     sync.Mutex
+	series map[string]*counterSeries
+}
+
+type counterSeries struct {
+	values []string
+	value  float64
+}
+
+func newCounterVec(name, help string, labels ...string) *counterVec {
+	return &counterVec{name: name, help: help, labels: labels, series: make(map[string]*counterSeries)}
+}
+
+func (c *counterVec) Add(delta float64, values ...string) {
+	key := strings.Join(values, labelSep)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	s, ok := c.series[key]
+	if !ok {
+		s = &counterSeries{values: values}
+		c.series[key] = s
+	}
+	s.value += delta
+}
+
+func (c *counterVec) Inc(values ...string) {
+	c.Add(1, values...)
+}
+
+func (c *counterVec) write(b *strings.Builder) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	writeHeader(b, c.name, c.help, "counter")
+	for _, key := range sortedKeys(c.series) {
+		s := c.series[key]
+		writeSample(b, c.name, c.labels, s.values, "", "", s.value)
+	}
+	b.WriteString("\n")
+}
+
+// histogramVec adalah histogram kumulatif (Prometheus) dengan label.
+// Hanya count per bucket yang disimpan, bukan sample mentah.
+type histogramVec struct {
+	name    string
+	help    string
+	labels  []string
+	buckets []float64
+
+	mu     sync.Mutex
+	series map[string]*histogramSeries
+}
+
+type histogramSeries struct {
+	values []string
+	counts []uint64 // per bucket (non-kumulatif), +Inf di index terakhir
+	sum    float64
+	count  uint64
+}
+
+func newHistogramVec(name, help string, buckets []float64, labels ...string) *histogramVec {
+	return &histogramVec{name: name, help: help, labels: labels, buckets: buckets, series: make(map[string]*histogramSeries)}
+}
+
+func (h *histogramVec) Observe(v float64, values ...string) {
+	key := strings.Join(values, labelSep)
+	idx := sort.SearchFloat64s(h.buckets, v) // bucket pertama dengan le >= v
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	s, ok := h.series[key]
+	if !ok {
+		s = &histogramSeries{values: values, counts: make([]uint64, len(h.buckets)+1)}
+		h.series[key] = s
+	}
+	s.counts[idx]++
+	s.sum += v
+	s.count++
+}
+
+func (h *histogramVec) ObserveDuration(d time.Duration, values ...string) {
+	h.Observe(d.Seconds(), values...)
+}
+
+func (h *histogramVec) write(b *strings.Builder) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	writeHeader(b, h.name, h.help, "histogram")
+	for _, key := range sortedKeys(h.series) {
+		s := h.series[key]
+
+		var cumulative uint64
+		for i, le := range h.buckets {
+			cumulative += s.counts[i]
+			writeSample(b, h.name+"_bucket", h.labels, s.values, "le", formatFloat(le), float64(cumulative))
+		}
+		cumulative += s.counts[len(h.buckets)]
+		writeSample(b, h.name+"_bucket", h.labels, s.values, "le", "+Inf", float64(cumulative))
+		writeSample(b, h.name+"_sum", h.labels, s.values, "", "", s.sum)
+		writeSample(b, h.name+"_count", h.labels, s.values, "", "", float64(s.count))
+	}
+	b.WriteString("\n")
+}
+
+func writeHeader(b *strings.Builder, name, help, typ string) {
+	b.WriteString("# HELP " + name + " " + help + "\n")
+	b.WriteString("# TYPE " + name + " " + typ + "\n")
+}
+
+// writeSample menulis satu baris sample; extraName/extraValue dipakai untuk label "le"
+func writeSample(b *strings.Builder, name string, labels, values []string, extraName, extraValue string, v float64) {
+	b.WriteString(name)
+	if len(labels) > 0 || extraName != "" {
+		b.WriteString("{")
+		for i, l := range labels {
+			if i > 0 {
+				b.WriteString(",")
+			}
+			val := ""
+			if i < len(values) {
+				val = values[i]
+			}
+			b.WriteString(l + `="` + escapeLabel(val) + `"`)
+		}
+		if extraName != "" {
+			if len(labels) > 0 {
+				b.WriteString(",")
+			}
+			b.WriteString(extraName + `="` + extraValue + `"`)
+		}
+		b.WriteString("}")
+	}
+	b.WriteString(" " + formatFloat(v) + "\n")
+}
+
+func writeGauge(b *strings.Builder, name, help string, v float64) {
+	writeHeader(b, name, help, "gauge")
+	writeSample(b, name, nil, nil, "", "", v)
+	b.WriteString("\n")
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+
+func escapeLabel(s string) string {
+	return labelEscaper.Replace(s)
+}
+
+func formatFloat(v float64) string {
+	if math.IsInf(v, 1) {
+		return "+Inf"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
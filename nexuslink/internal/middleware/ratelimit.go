@@ -3,10 +3,12 @@ package middleware
 import (
 	"context"
 	"fmt"
+	"math"
 	"net"
 	"net/http"
 	"time"
 
+	"github.com/afuzapratama/nexuslink/internal/metrics"
 	"github.com/afuzapratama/nexuslink/internal/ratelimit"
 )
 
@@ -18,6 +20,8 @@ type RateLimitConfig struct {
 	LinkLimit int
 	// Window duration
 	Window time.Duration
+	// Algoritma limiter (kosong = sliding log)
+	Algorithm string
 }
 
 // DefaultRateLimitConfig returns sensible defaults
@@ -52,23 +56,27 @@ func RateLimitMiddleware(limiter *ratelimit.Limiter, config RateLimitConfig) fun
 
 			// Check IP rate limit
 			ipKey := fmt.Sprintf("ip:%s", ip)
-			allowed, remaining, resetAt, err := limiter.Allow(ctx, ipKey, config.IPLimit, config.Window)
-
+			res, err := limiter.Check(ctx, config.Algorithm, ipKey, config.IPLimit, config.Window)
 			if err != nil {
-				// Redis connection failed - allow request but log error
-				// This prevents rate limit from blocking service when Redis is down
-				w.Header().Set("X-RateLimit-Status", "degraded")
+				// Konfigurasi salah (algoritma tidak dikenal) - jangan blokir request
 				next.ServeHTTP(w, r)
 				return
 			}
 
+			// Redis down → diputuskan limiter in-process, tetap ditegakkan
+			if res.Degraded {
+				w.Header().Set("X-RateLimit-Status", "degraded")
+			}
+
+			metrics.GetMetrics().RecordRateLimitCheck(res.Allowed)
+
 			// Set rate limit headers
 			w.Header().Set("X-RateLimit-Limit", fmt.Sprintf("%d", config.IPLimit))
-			w.Header().Set("X-RateLimit-Remaining", fmt.Sprintf("%d", remaining))
-			w.Header().Set("X-RateLimit-Reset", fmt.Sprintf("%d", resetAt.Unix()))
+			w.Header().Set("X-RateLimit-Remaining", fmt.Sprintf("%d", res.Remaining))
+			w.Header().Set("X-RateLimit-Reset", fmt.Sprintf("%d", res.ResetAt.Unix()))
 
-			if !allowed {
-				w.Header().Set("Retry-After", fmt.Sprintf("%d", int(config.Window.Seconds())))
+			if !res.Allowed {
+				w.Header().Set("Retry-After", fmt.Sprintf("%d", int(math.Ceil(res.RetryAfter.Seconds()))))
 				http.Error(w, "Rate limit exceeded", http.StatusTooManyRequests)
 				return
 			}
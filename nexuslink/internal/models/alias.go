@@ -0,0 +1,38 @@
+package models
+
+import "strings"
+
+// Strategi generate alias otomatis (link dibuat tanpa alias)
+const (
+	AliasStrategyRandom     = "random"     // base62 acak sepanjang Length
+	AliasStrategyWords      = "words"      // suku kata konsonan-vokal yang bisa dieja, mis. "bakimo"
+	AliasStrategySequential = "sequential" // counter per domain di-encode hashids
+)
+
+// AliasPolicy cara generate alias untuk satu domain
+type AliasPolicy struct {
+	Strategy string `json:"strategy" dynamodbav:"strategy"`
+	// random: jumlah karakter; words: jumlah huruf; sequential: panjang minimum hashids
+	Length int    `json:"length,omitempty" dynamodbav:"length,omitempty"`
+	Salt   string `json:"salt,omitempty" dynamodbav:"salt,omitempty"` // sequential: salt hashids
+}
+
+// AliasSettings konfigurasi alias di Settings
+type AliasSettings struct {
+	Default AliasPolicy            `json:"default" dynamodbav:"default"`
+	Domains map[string]AliasPolicy `json:"domains,omitempty" dynamodbav:"domains,omitempty"` // key: domain lowercase
+	// Kata yang tidak boleh dipakai: alias custom ditolak kalau sama persis,
+	// alias hasil generate dibuang kalau mengandung kata ini (case-insensitive)
+	Blocked []string `json:"blocked,omitempty" dynamodbav:"blocked,omitempty"`
+}
+
+// PolicyFor returns policy untuk domain (fallback ke Default)
+func (a *AliasSettings) PolicyFor(domain string) AliasPolicy {
+	if a == nil {
+		return AliasPolicy{Strategy: AliasStrategyRandom}
+	}
+	if p, ok := a.Domains[strings.ToLower(strings.TrimSpace(domain))]; ok {
+		return p
+	}
+	return a.Default
+}
@@ -0,0 +1,55 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// AuditChange satu field yang berubah (nilai sensitif sudah di-redact)
+type AuditChange struct {
+	Field  string      `json:"field" dynamodbav:"field"`
+	Before interface{} `json:"before,omitempty" dynamodbav:"before,omitempty"`
+	After  interface{} `json:"after,omitempty" dynamodbav:"after,omitempty"`
+}
+
+// AuditRecord adalah catatan append-only untuk setiap perubahan administratif
+type AuditRecord struct {
+	ID         string        `json:"id" dynamodbav:"id"`
+	At         time.Time     `json:"at" dynamodbav:"at"`
+	Actor      string        `json:"actor" dynamodbav:"actor"` // username, "api-key" atau "node:<id>"
+	ActorRole  string        `json:"actorRole,omitempty" dynamodbav:"actorRole,omitempty"`
+	SourceIP   string        `json:"sourceIp,omitempty" dynamodbav:"sourceIp,omitempty"`
+	ProxyIP    string        `json:"proxyIp,omitempty" dynamodbav:"proxyIp,omitempty"` // RemoteAddr kalau IP dari X-Forwarded-For
+	Action     string        `json:"action" dynamodbav:"action"`                       // mis. link.update, node.delete
+	TargetType string        `json:"targetType" dynamodbav:"targetType"`
+	TargetID   string        `json:"targetId" dynamodbav:"targetId"`
+	Changes    []AuditChange `json:"changes,omitempty" dynamodbav:"changes,omitempty"`
+}
+
+// AuditFilter untuk query /admin/audit. Field kosong = tidak difilter.
+type AuditFilter struct {
+	Actor      string
+	Action     string // exact, atau prefix: "link" cocok dengan link.update, link.delete, ...
+	TargetType string
+	TargetID   string
+	Since      time.Time
+	Until      time.Time
+	Limit      int // <= 0 = tanpa batas (export)
+}
+
+// Matches true kalau record lolos filter (range waktu dicek terpisah oleh store)
+func (f AuditFilter) Matches(rec *AuditRecord) bool {
+	if f.Actor != "" && !strings.EqualFold(rec.Actor, f.Actor) {
+		return false
+	}
+	if f.Action != "" && rec.Action != f.Action && !strings.HasPrefix(rec.Action, f.Action+".") {
+		return false
+	}
+	if f.TargetType != "" && rec.TargetType != f.TargetType {
+		return false
+	}
+	if f.TargetID != "" && rec.TargetID != f.TargetID {
+		return false
+	}
+	return true
+}
@@ -25,6 +25,13 @@ type ClickEvent struct {
 	RiskScore       int    `json:"riskScore,omitempty" dynamodbav:"riskScore,omitempty"`             // 0-100 from ProxyCheck
 	IPCheckProvider string `json:"ipCheckProvider,omitempty" dynamodbav:"ipCheckProvider,omitempty"` // "proxycheck" or "ipqualityscore"
 
+	// Routing rule yang match (kosong = default target)
+	RuleID    string `json:"ruleId,omitempty" dynamodbav:"ruleId,omitempty"`
+	VariantID string `json:"variantId,omitempty" dynamodbav:"variantId,omitempty"` // A/B variant yang dipilih
+
+	// Reason kalau visitor tidak diredirect ke target (bot_blocked, vpn_blocked, ...)
+	BlockReason string `json:"blockReason,omitempty" dynamodbav:"blockReason,omitempty"`
+
 	UserAgent string    `json:"userAgent" dynamodbav:"userAgent"`
 	Referrer  string    `json:"referrer" dynamodbav:"referrer"`
 	CreatedAt time.Time `json:"createdAt" dynamodbav:"createdAt"`
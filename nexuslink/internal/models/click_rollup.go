@@ -0,0 +1,36 @@
+package models
+
+// ClickRollup adalah counter click pre-aggregated per alias, granularity, bucket waktu dan dimensi.
+// PK = "alias#granularity#dimension", SK = "bucket#value" → series satu dimensi bisa di-Query per range waktu.
+type ClickRollup struct {
+	PK string `json:"-" dynamodbav:"pk"`
+	SK string `json:"-" dynamodbav:"sk"`
+
+	Alias       string `json:"alias" dynamodbav:"alias"`             // "*" = semua link
+	Granularity string `json:"granularity" dynamodbav:"granularity"` // "hour" / "day"
+	Dimension   string `json:"dimension" dynamodbav:"dimension"`     // "total", "country", "device", ...
+	Value       string `json:"value" dynamodbav:"value"`
+	Bucket      string `json:"bucket" dynamodbav:"bucket"` // UTC, "2006-01-02T15" (hour) / "2006-01-02" (day)
+	Count       int64  `json:"count" dynamodbav:"count"`
+}
+
+// Rollup granularities
+const (
+	RollupHour = "hour"
+	RollupDay  = "day"
+)
+
+// Rollup dimensions
+const (
+	RollupDimTotal    = "total"
+	RollupDimCountry  = "country"
+	RollupDimDevice   = "device"
+	RollupDimBrowser  = "browser"
+	RollupDimOS       = "os"
+	RollupDimReferrer = "referrer" // domain referrer, "direct" kalau kosong
+	RollupDimNode     = "node"
+	RollupDimBot      = "bot" // "bot" / "human"
+)
+
+// RollupAllLinks adalah alias untuk rollup gabungan semua link (dashboard)
+const RollupAllLinks = "*"
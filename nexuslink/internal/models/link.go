@@ -1,6 +1,9 @@
 package models
 
-import "time"
+import (
+	"strings"
+	"time"
+)
 
 type Link struct {
 	ID        string `json:"id" dynamodbav:"id"`
@@ -11,6 +14,14 @@ type Link struct {
 	GroupID string `json:"groupId,omitempty" dynamodbav:"groupId,omitempty"` // Link organization
 	Domain  string `json:"domain,omitempty" dynamodbav:"domain,omitempty"`   // Domain restriction (optional, if empty = all domains)
 
+	// Key identitas data per-link (stats, click, variant, rollup): alias untuk link tanpa domain,
+	// "domain/alias" untuk link ber-domain. Kosong = link lama (sebelum alias per domain) → Alias.
+	Key string `json:"key,omitempty" dynamodbav:"key,omitempty"`
+
+	// Pool domain tambahan untuk link ini (bersama Domain). Canonical short URL
+	// dipilih dari domain di pool yang node-nya sehat.
+	Domains []string `json:"domains,omitempty" dynamodbav:"domains,omitempty"`
+
 	// --- NEW: aturan akses berbasis UA/IP ---
 
 	// Allow-list; kalau kosong berarti bebas
@@ -25,6 +36,9 @@ type Link struct {
 	// Target alternatif kalau tidak sesuai rules
 	FallbackURL string `json:"fallbackUrl,omitempty" dynamodbav:"fallbackUrl,omitempty"`
 
+	// Routing rules (dievaluasi berurutan, first match wins)
+	Rules []LinkRule `json:"rules,omitempty" dynamodbav:"rules,omitempty"`
+
 	// Advanced features
 	ExpiresAt *time.Time `json:"expiresAt,omitempty" dynamodbav:"expiresAt,omitempty"` // Link expiration
 	MaxClicks *int       `json:"maxClicks,omitempty" dynamodbav:"maxClicks,omitempty"` // Click limit
@@ -33,7 +47,146 @@ type Link struct {
 	ActiveFrom  *time.Time `json:"activeFrom,omitempty" dynamodbav:"activeFrom,omitempty"`   // Link starts working from this time
 	ActiveUntil *time.Time `json:"activeUntil,omitempty" dynamodbav:"activeUntil,omitempty"` // Link stops working after this time
 
+	// Perilaku saat link di-disable (IsActive = false)
+	DisabledBehavior string `json:"disabledBehavior,omitempty" dynamodbav:"disabledBehavior,omitempty"` // "404" (default), "410", "fallback"
+	DisabledMessage  string `json:"disabledMessage,omitempty" dynamodbav:"disabledMessage,omitempty"`   // pesan landing page di agent
+
+	// Override rate limit global untuk link ini (nil = ikut Settings)
+	RateLimit *LinkRateLimit `json:"rateLimit,omitempty" dynamodbav:"rateLimit,omitempty"`
+
 	IsActive  bool      `json:"isActive" dynamodbav:"isActive"`
 	CreatedAt time.Time `json:"createdAt" dynamodbav:"createdAt"`
 	UpdatedAt time.Time `json:"updatedAt" dynamodbav:"updatedAt"`
+
+	// Version naik setiap kali link berubah (UnixNano dari UpdatedAt), dipakai delta feed agent
+	Version int64 `json:"version" dynamodbav:"version"`
+
+	// Revision nomor versi yang bisa dibaca manusia (1 = versi saat dibuat), naik setiap edit.
+	// Versi sebelumnya disimpan sebagai LinkRevision.
+	Revision     int    `json:"revision,omitempty" dynamodbav:"revision,omitempty"`
+	UpdatedBy    string `json:"updatedBy,omitempty" dynamodbav:"updatedBy,omitempty"`
+	RestoredFrom int    `json:"restoredFrom,omitempty" dynamodbav:"restoredFrom,omitempty"` // revision asal kalau versi ini hasil restore
+}
+
+// CurrentRevision returns nomor revision link; link lama (sebelum ada revision) dianggap revision 1
+func (l *Link) CurrentRevision() int {
+	if l.Revision < 1 {
+		return 1
+	}
+	return l.Revision
+}
+
+// LinkKey returns key data untuk alias di domain (domain kosong = alias saja).
+// Alias tidak boleh mengandung "/", jadi key tidak ambigu.
+func LinkKey(domain, alias string) string {
+	domain = strings.ToLower(strings.TrimSpace(domain))
+	if domain == "" {
+		return alias
+	}
+	return domain + "/" + alias
+}
+
+// SplitLinkKey kebalikan LinkKey
+func SplitLinkKey(key string) (domain, alias string) {
+	if i := strings.LastIndex(key, "/"); i >= 0 {
+		return key[:i], key[i+1:]
+	}
+	return "", key
+}
+
+// DataKey returns key yang dipakai stats, click, variant dan rollup link ini
+func (l *Link) DataKey() string {
+	if l.Key != "" {
+		return l.Key
+	}
+	return l.Alias
 }
+
+// ScopeKey returns kunci unik (domain, alias) case-insensitive; alias yang sama boleh dipakai di domain lain
+func (l *Link) ScopeKey() string {
+	return strings.ToLower(LinkKey(l.Domain, l.Alias))
+}
+
+// DomainRank menilai seberapa cocok link untuk request di domain:
+// 3 = Domain link sama, 2 = domain ada di pool, 1 = link tanpa domain, 0 = tidak melayani domain ini
+func (l *Link) DomainRank(domain string) int {
+	switch {
+	case domain != "" && strings.EqualFold(l.Domain, strings.TrimSpace(domain)):
+		return 3
+	case len(l.DomainPool()) == 0:
+		return 1
+	case domain != "" && l.ServesDomain(domain):
+		return 2
+	}
+	return 0
+}
+
+// PickForDomain memilih link dengan alias sama yang paling cocok untuk domain request.
+// Kalau tidak ada yang melayani domain, link pertama dikembalikan (resolver menolak dengan domain_not_allowed).
+func PickForDomain(links []Link, domain string) *Link {
+	if len(links) == 0 {
+		return nil
+	}
+	best := 0
+	for i := range links {
+		if links[i].DomainRank(domain) > links[best].DomainRank(domain) {
+			best = i
+		}
+	}
+	link := links[best]
+	return &link
+}
+
+// DomainPool returns Domain + Domains tanpa duplikat (kosong = link bisa diakses dari semua domain)
+func (l *Link) DomainPool() []string {
+	var pool []string
+	seen := make(map[string]bool)
+	for _, d := range append([]string{l.Domain}, l.Domains...) {
+		d = strings.ToLower(strings.TrimSpace(d))
+		if d == "" || seen[d] {
+			continue
+		}
+		seen[d] = true
+		pool = append(pool, d)
+	}
+	return pool
+}
+
+// ServesDomain true kalau request di domain ini boleh me-resolve link
+func (l *Link) ServesDomain(domain string) bool {
+	pool := l.DomainPool()
+	if len(pool) == 0 || domain == "" {
+		return true
+	}
+	for _, d := range pool {
+		if strings.EqualFold(d, domain) {
+			return true
+		}
+	}
+	return false
+}
+
+// Disabled link behaviors
+const (
+	DisabledBehaviorNotFound = "404"
+	DisabledBehaviorGone     = "410"
+	DisabledBehaviorFallback = "fallback"
+)
+
+// LinkRateLimit adalah override rate limit per link. Field 0 / kosong = pakai nilai global.
+type LinkRateLimit struct {
+	Disabled bool   `json:"disabled,omitempty" dynamodbav:"disabled,omitempty"` // true = link ini tidak di-rate limit sama sekali
+	PerIP    int    `json:"perIp,omitempty" dynamodbav:"perIp,omitempty"`       // request per IP per window (dihitung khusus link ini)
+	PerLink  int    `json:"perLink,omitempty" dynamodbav:"perLink,omitempty"`   // request total ke link ini per window
+	Window   int    `json:"window,omitempty" dynamodbav:"window,omitempty"`     // detik
+	Action   string `json:"action,omitempty" dynamodbav:"action,omitempty"`     // "reject", "fallback", "challenge"
+
+	Algorithm string `json:"algorithm,omitempty" dynamodbav:"algorithm,omitempty"` // "sliding_log", "sliding_window", "gcra"
+}
+
+// Rate limit actions (response saat limit terlampaui)
+const (
+	RateLimitActionReject    = "reject"    // 429 + Retry-After
+	RateLimitActionFallback  = "fallback"  // redirect ke FallbackURL (429 kalau link tidak punya fallback)
+	RateLimitActionChallenge = "challenge" // halaman JS challenge di agent, lolos = bebas limit per IP sementara
+)
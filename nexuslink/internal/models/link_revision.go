@@ -0,0 +1,33 @@
+package models
+
+import "time"
+
+// LinkRevision adalah snapshot satu versi link beserta konfigurasi variant-nya saat itu.
+// Counter variant (clicks/conversions) tidak ikut di-versi.
+type LinkRevision struct {
+	LinkID       string        `json:"linkId" dynamodbav:"linkId"`
+	Revision     int           `json:"revision" dynamodbav:"revision"`
+	Author       string        `json:"author,omitempty" dynamodbav:"author,omitempty"`
+	CreatedAt    time.Time     `json:"createdAt" dynamodbav:"createdAt"` // kapan versi ini disimpan (UpdatedAt link)
+	RestoredFrom int           `json:"restoredFrom,omitempty" dynamodbav:"restoredFrom,omitempty"`
+	Link         Link          `json:"link" dynamodbav:"link"`
+	Variants     []LinkVariant `json:"variants,omitempty" dynamodbav:"variants,omitempty"`
+}
+
+// NewLinkRevision membuat snapshot dari link dan variant-nya
+func NewLinkRevision(link *Link, variants []LinkVariant) *LinkRevision {
+	rev := &LinkRevision{
+		LinkID:       link.ID,
+		Revision:     link.CurrentRevision(),
+		Author:       link.UpdatedBy,
+		CreatedAt:    link.UpdatedAt,
+		RestoredFrom: link.RestoredFrom,
+		Link:         *link,
+	}
+	rev.Link.Revision = rev.Revision
+	for _, v := range variants {
+		v.Clicks, v.Conversions = 0, 0
+		rev.Variants = append(rev.Variants, v)
+	}
+	return rev
+}
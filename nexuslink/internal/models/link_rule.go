@@ -0,0 +1,66 @@
+package models
+
+// LinkRule adalah satu aturan routing per-link.
+// Rules dievaluasi berurutan; rule pertama yang match menentukan action.
+type LinkRule struct {
+	ID         string          `json:"id" dynamodbav:"id"`
+	Name       string          `json:"name,omitempty" dynamodbav:"name,omitempty"`
+	Match      string          `json:"match" dynamodbav:"match"` // "all" (AND) atau "any" (OR)
+	Conditions []RuleCondition `json:"conditions" dynamodbav:"conditions"`
+	Action     RuleAction      `json:"action" dynamodbav:"action"`
+	Disabled   bool            `json:"disabled,omitempty" dynamodbav:"disabled,omitempty"`
+}
+
+// RuleCondition mencocokkan satu atribut visitor terhadap daftar value
+type RuleCondition struct {
+	Field    string   `json:"field" dynamodbav:"field"`                 // lihat RuleField*
+	Operator string   `json:"operator" dynamodbav:"operator"`           // lihat RuleOp*
+	Key      string   `json:"key,omitempty" dynamodbav:"key,omitempty"` // nama query param, atau timezone untuk field "time"
+	Values   []string `json:"values,omitempty" dynamodbav:"values,omitempty"`
+}
+
+// RuleAction menentukan apa yang terjadi kalau rule match
+type RuleAction struct {
+	Type      string `json:"type" dynamodbav:"type"`                               // redirect, block, variant
+	URL       string `json:"url,omitempty" dynamodbav:"url,omitempty"`             // untuk redirect
+	VariantID string `json:"variantId,omitempty" dynamodbav:"variantId,omitempty"` // untuk variant
+	Reason    string `json:"reason,omitempty" dynamodbav:"reason,omitempty"`       // reason yang dikirim ke agent
+}
+
+// Rule match modes
+const (
+	RuleMatchAll = "all"
+	RuleMatchAny = "any"
+)
+
+// Supported condition fields
+const (
+	RuleFieldCountry  = "country"  // ISO country code
+	RuleFieldASN      = "asn"      // Autonomous system number, e.g. "AS7713" atau "7713"
+	RuleFieldDevice   = "device"   // Desktop, Mobile, Tablet
+	RuleFieldOS       = "os"       // partial match (Windows, iOS, Android)
+	RuleFieldBrowser  = "browser"  // Chrome, Safari, ...
+	RuleFieldReferrer = "referrer" // full referrer URL
+	RuleFieldLanguage = "language" // primary Accept-Language tag, e.g. "id" atau "en-US"
+	RuleFieldTime     = "time"     // time of day "HH:MM", Key = IANA timezone (default UTC)
+	RuleFieldQuery    = "query"    // query param, Key = nama param
+	RuleFieldBot      = "bot"      // "true" / "false"
+)
+
+// Supported condition operators
+const (
+	RuleOpIn          = "in"       // value salah satu dari Values
+	RuleOpNotIn       = "not_in"   // value bukan salah satu dari Values
+	RuleOpContains    = "contains" // value mengandung salah satu dari Values
+	RuleOpNotContains = "not_contains"
+	RuleOpBetween     = "between" // Values[0] <= value < Values[1] (khusus field time)
+	RuleOpExists      = "exists"  // value tidak kosong
+	RuleOpNotExists   = "not_exists"
+)
+
+// Supported actions
+const (
+	RuleActionRedirect = "redirect"
+	RuleActionBlock    = "block"
+	RuleActionVariant  = "variant"
+)
@@ -0,0 +1,29 @@
+package models
+
+// LinkTombstone dicatat saat link dihapus, supaya agent bisa membuang link dari snapshot lokal
+type LinkTombstone struct {
+	ID        string `json:"id" dynamodbav:"id"` // link ID
+	Alias     string `json:"alias" dynamodbav:"alias"`
+	Version   int64  `json:"version" dynamodbav:"version"`
+	ExpiresAt int64  `json:"-" dynamodbav:"expiresAt"` // DynamoDB TTL (unix seconds)
+}
+
+// LinkSnapshot adalah definisi link lengkap yang dibutuhkan agent untuk resolve sendiri
+type LinkSnapshot struct {
+	Link     Link          `json:"link"`
+	Variants []LinkVariant `json:"variants,omitempty"`
+}
+
+// LinkFeed adalah response delta feed GET /sync/links?since=<version>
+type LinkFeed struct {
+	Version int64          `json:"version"`           // kirim balik sebagai ?since= di request berikutnya
+	Full    bool           `json:"full"`              // true = snapshot penuh, agent harus replace semua
+	Links   []LinkSnapshot `json:"links"`             // link baru / berubah
+	Deleted []string       `json:"deleted,omitempty"` // link ID yang dihapus
+
+	// Kalau IP check (ProxyCheck/IPQS) aktif, agent tetap resolve lewat API selama API reachable
+	IPCheckEnabled bool `json:"ipCheckEnabled"`
+
+	// Kalau rate limit global aktif, agent juga resolve lewat API (counter ada di Redis API)
+	RateLimitEnabled bool `json:"rateLimitEnabled"`
+}
@@ -11,4 +11,35 @@ type Node struct {
 	LastSeenAt   time.Time `json:"lastSeenAt" dynamodbav:"lastSeenAt"`
 	IsOnline     bool      `json:"isOnline" dynamodbav:"isOnline"`
 	AgentVersion string    `json:"agentVersion" dynamodbav:"agentVersion"`
+
+	// Bobot node saat memilih canonical short URL (0 = 1). Node dengan bobot lebih besar lebih sering dipilih.
+	Weight int `json:"weight,omitempty" dynamodbav:"weight,omitempty"`
+
+	// DomainsVersion naik setiap Domains berubah (UnixNano), dipakai push domain ke agent
+	DomainsVersion int64 `json:"domainsVersion,omitempty" dynamodbav:"domainsVersion,omitempty"`
+}
+
+// NodeDomainFeed adalah daftar domain node yang di-push API ke agent (ditandatangani HMAC)
+type NodeDomainFeed struct {
+	NodeID   string    `json:"nodeId"`
+	Version  int64     `json:"version"`
+	Domains  []string  `json:"domains"`
+	IssuedAt time.Time `json:"issuedAt"`
+}
+
+// Tipe NodeEvent
+const (
+	NodeEventOnline  = "online"
+	NodeEventOffline = "offline"
+)
+
+// NodeEventRetention: riwayat transisi node disimpan selama ini (cukup untuk uptime 7 hari)
+const NodeEventRetention = 30 * 24 * time.Hour
+
+// NodeEvent mencatat transisi online/offline sebuah node (dipakai untuk riwayat & uptime)
+type NodeEvent struct {
+	NodeID     string    `json:"nodeId"`
+	Type       string    `json:"type"`       // online | offline
+	At         time.Time `json:"at"`         // kapan transisi terdeteksi monitor
+	LastSeenAt time.Time `json:"lastSeenAt"` // heartbeat terakhir saat transisi
 }
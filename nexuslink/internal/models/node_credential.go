@@ -0,0 +1,45 @@
+package models
+
+import "time"
+
+// NodeCredential adalah API key per node (scope agent saja: resolve, heartbeat, sync, domain feed).
+// Yang disimpan hanya hash SHA-256; key plaintext hanya dikirim sekali ke agent.
+type NodeCredential struct {
+	NodeID    string    `json:"nodeId" dynamodbav:"nodeId"`
+	KeyHash   string    `json:"keyHash" dynamodbav:"keyHash"`
+	Prefix    string    `json:"prefix" dynamodbav:"prefix"` // awal key untuk ditampilkan di dashboard
+	CreatedAt time.Time `json:"createdAt" dynamodbav:"createdAt"`
+
+	// RotateRequested di-set admin; agent menerima sinyal lewat heartbeat lalu minta key baru
+	RotateRequested bool      `json:"rotateRequested,omitempty" dynamodbav:"rotateRequested,omitempty"`
+	RevokedAt       time.Time `json:"revokedAt,omitempty" dynamodbav:"revokedAt,omitempty"`
+}
+
+// Revoked true kalau credential sudah dicabut admin
+func (c *NodeCredential) Revoked() bool {
+	return !c.RevokedAt.IsZero()
+}
+
+// NodeCredentialInfo adalah tampilan credential untuk admin (tanpa hash)
+type NodeCredentialInfo struct {
+	NodeID          string     `json:"nodeId"`
+	Prefix          string     `json:"prefix"`
+	CreatedAt       time.Time  `json:"createdAt"`
+	RotateRequested bool       `json:"rotateRequested"`
+	RevokedAt       *time.Time `json:"revokedAt,omitempty"`
+}
+
+// Info returns tampilan credential tanpa hash
+func (c *NodeCredential) Info() NodeCredentialInfo {
+	info := NodeCredentialInfo{
+		NodeID:          c.NodeID,
+		Prefix:          c.Prefix,
+		CreatedAt:       c.CreatedAt,
+		RotateRequested: c.RotateRequested,
+	}
+	if c.Revoked() {
+		t := c.RevokedAt
+		info.RevokedAt = &t
+	}
+	return info
+}
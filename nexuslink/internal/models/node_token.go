@@ -2,6 +2,9 @@ package models
 
 import "time"
 
+// NodeTokenTTL: token registrasi node hanya berlaku sekali dan paling lama selama ini sejak dibuat
+const NodeTokenTTL = 7 * 24 * time.Hour
+
 type NodeToken struct {
 	Token     string    `json:"token" dynamodbav:"token"`
 	Label     string    `json:"label" dynamodbav:"label"`
@@ -9,3 +12,8 @@ type NodeToken struct {
 	IsUsed    bool      `json:"isUsed" dynamodbav:"isUsed"`
 	UsedAt    time.Time `json:"usedAt,omitempty" dynamodbav:"usedAt,omitempty"`
 }
+
+// Usable true kalau token belum dipakai dan belum kedaluwarsa
+func (t *NodeToken) Usable(now time.Time) bool {
+	return !t.IsUsed && now.Before(t.CreatedAt.Add(NodeTokenTTL))
+}
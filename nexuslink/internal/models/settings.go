@@ -30,6 +30,15 @@ type Settings struct {
 	RateLimitPerLink int `json:"rateLimitPerLink" dynamodbav:"rateLimitPerLink"` // requests per minute per link
 	RateLimitWindow  int `json:"rateLimitWindow" dynamodbav:"rateLimitWindow"`   // window in seconds
 
+	// Enforcement di redirect path (/links/resolve). Link bisa override lewat Link.RateLimit.
+	EnableRateLimit bool   `json:"enableRateLimit" dynamodbav:"enableRateLimit"`
+	RateLimitAction string `json:"rateLimitAction,omitempty" dynamodbav:"rateLimitAction,omitempty"` // "reject" (429, default), "fallback", "challenge"
+	// Algoritma limiter: "sliding_log" (default), "sliding_window", "gcra"
+	RateLimitAlgorithm string `json:"rateLimitAlgorithm,omitempty" dynamodbav:"rateLimitAlgorithm,omitempty"`
+
+	// Generate alias otomatis + kata terlarang (nil = random default)
+	Aliases *AliasSettings `json:"aliases,omitempty" dynamodbav:"aliases,omitempty"`
+
 	CreatedAt time.Time `json:"createdAt" dynamodbav:"createdAt"`
 	UpdatedAt time.Time `json:"updatedAt" dynamodbav:"updatedAt"`
 }
@@ -52,6 +61,8 @@ func DefaultSettings() *Settings {
 		RateLimitPerIP:       60,  // 60 requests per minute
 		RateLimitPerLink:     120, // 120 requests per minute
 		RateLimitWindow:      60,  // 60 seconds window
+		EnableRateLimit:      false,
+		RateLimitAction:      RateLimitActionReject,
 		CreatedAt:            now,
 		UpdatedAt:            now,
 	}
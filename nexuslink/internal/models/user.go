@@ -0,0 +1,74 @@
+package models
+
+import "time"
+
+// Role user dashboard / admin API
+const (
+	RoleOwner   = "owner"   // semua, termasuk node, settings & user management
+	RoleEditor  = "editor"  // kelola link, group, variant, webhook
+	RoleAnalyst = "analyst" // read-only: link, analytics, node
+)
+
+var roleRank = map[string]int{
+	RoleAnalyst: 1,
+	RoleEditor:  2,
+	RoleOwner:   3,
+}
+
+// ValidRole true kalau role dikenal
+func ValidRole(role string) bool {
+	return roleRank[role] > 0
+}
+
+// RoleAtLeast true kalau role punya hak minimal sebesar min
+func RoleAtLeast(role, min string) bool {
+	return roleRank[role] > 0 && roleRank[role] >= roleRank[min]
+}
+
+// User adalah akun admin dashboard (password bcrypt)
+type User struct {
+	ID           string    `json:"id" dynamodbav:"id"`
+	Username     string    `json:"username" dynamodbav:"username"`
+	PasswordHash string    `json:"passwordHash" dynamodbav:"passwordHash"`
+	Role         string    `json:"role" dynamodbav:"role"`
+	CreatedAt    time.Time `json:"createdAt" dynamodbav:"createdAt"`
+	UpdatedAt    time.Time `json:"updatedAt" dynamodbav:"updatedAt"`
+	LastLoginAt  time.Time `json:"lastLoginAt,omitempty" dynamodbav:"lastLoginAt,omitempty"`
+}
+
+// UserInfo adalah tampilan user untuk API (tanpa password hash)
+type UserInfo struct {
+	ID          string     `json:"id"`
+	Username    string     `json:"username"`
+	Role        string     `json:"role"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	UpdatedAt   time.Time  `json:"updatedAt"`
+	LastLoginAt *time.Time `json:"lastLoginAt,omitempty"`
+}
+
+// Info returns tampilan user tanpa password hash
+func (u *User) Info() UserInfo {
+	info := UserInfo{
+		ID:        u.ID,
+		Username:  u.Username,
+		Role:      u.Role,
+		CreatedAt: u.CreatedAt,
+		UpdatedAt: u.UpdatedAt,
+	}
+	if !u.LastLoginAt.IsZero() {
+		t := u.LastLoginAt
+		info.LastLoginAt = &t
+	}
+	return info
+}
+
+// SessionTTL: session berlaku 24 jam sejak aktivitas terakhir
+const SessionTTL = 24 * time.Hour
+
+// Session login dashboard. Yang disimpan hanya hash token (SHA-256).
+type Session struct {
+	TokenHash string    `json:"tokenHash" dynamodbav:"tokenHash"`
+	UserID    string    `json:"userId" dynamodbav:"userId"`
+	CreatedAt time.Time `json:"createdAt" dynamodbav:"createdAt"`
+	ExpiresAt time.Time `json:"expiresAt" dynamodbav:"expiresAt"`
+}
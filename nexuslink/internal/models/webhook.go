@@ -1,6 +1,10 @@
 package models
 
-import "time"
+import (
+	"fmt"
+	"strings"
+	"time"
+)
 
 // Webhook represents a webhook endpoint for event notifications
 type Webhook struct {
@@ -11,12 +15,129 @@ type Webhook struct {
 	IsActive  bool      `json:"isActive" dynamodbav:"isActive"`   // Whether webhook is enabled
 	CreatedAt time.Time `json:"createdAt" dynamodbav:"createdAt"` // Creation timestamp
 	UpdatedAt time.Time `json:"updatedAt" dynamodbav:"updatedAt"` // Last update timestamp
+
+	Filter   *WebhookFilter `json:"filter,omitempty" dynamodbav:"filter,omitempty"`     // nil = semua event yang di-subscribe
+	Format   string         `json:"format,omitempty" dynamodbav:"format,omitempty"`     // WebhookFormat*, kosong = json
+	Template string         `json:"template,omitempty" dynamodbav:"template,omitempty"` // Go text/template, untuk format template
+	Batch    *WebhookBatch  `json:"batch,omitempty" dynamodbav:"batch,omitempty"`       // nil = click.created dikirim satu per satu
+
+	// Secret lama selama rotasi: request ditandatangani dengan Secret dan SecondarySecret
+	// sampai SecondarySecretExpiresAt (nil = sampai rotasi berikutnya)
+	SecondarySecret          string     `json:"secondarySecret,omitempty" dynamodbav:"secondarySecret,omitempty"`
+	SecondarySecretExpiresAt *time.Time `json:"secondarySecretExpiresAt,omitempty" dynamodbav:"secondarySecretExpiresAt,omitempty"`
+}
+
+// SigningSecrets returns secret yang dipakai untuk signature saat now (primary dulu)
+func (w *Webhook) SigningSecrets(now time.Time) []string {
+	secrets := []string{w.Secret}
+	if w.SecondarySecret != "" && (w.SecondarySecretExpiresAt == nil || now.Before(*w.SecondarySecretExpiresAt)) {
+		secrets = append(secrets, w.SecondarySecret)
+	}
+	return secrets
+}
+
+// Format body request webhook (semua menghasilkan JSON)
+const (
+	WebhookFormatJSON     = "json"     // WebhookPayload apa adanya
+	WebhookFormatTemplate = "template" // Template dengan WebhookPayload sebagai data, hasil harus JSON valid
+	WebhookFormatSlack    = "slack"    // {"text": ringkasan} untuk Slack incoming webhook
+	WebhookFormatDiscord  = "discord"  // {"content": ringkasan} untuk Discord webhook
+)
+
+// Encoding body batch
+const (
+	WebhookBatchJSON   = "json"   // JSON array, Content-Type application/json
+	WebhookBatchNDJSON = "ndjson" // satu event per baris, Content-Type application/x-ndjson
+)
+
+// Batas konfigurasi batch
+const (
+	DefaultWebhookBatchSize   = 100
+	DefaultWebhookBatchWindow = 10 // detik
+	MaxWebhookBatchSize       = 1000
+	MaxWebhookBatchWindow     = 300 // detik
+)
+
+// WebhookBatch mengumpulkan event click.created lalu mengirimnya sebagai satu request
+// saat Size event terkumpul atau Window detik sejak event pertama, mana yang lebih dulu.
+// Event lain tetap dikirim satu per satu.
+type WebhookBatch struct {
+	Size     int    `json:"size,omitempty" dynamodbav:"size,omitempty"`         // 0 = DefaultWebhookBatchSize
+	Window   int    `json:"window,omitempty" dynamodbav:"window,omitempty"`     // detik, 0 = DefaultWebhookBatchWindow
+	Encoding string `json:"encoding,omitempty" dynamodbav:"encoding,omitempty"` // WebhookBatch*, kosong = json
+}
+
+// MaxSize returns Size atau default
+func (b *WebhookBatch) MaxSize() int {
+	if b.Size <= 0 {
+		return DefaultWebhookBatchSize
+	}
+	return b.Size
+}
+
+// MaxWait returns Window atau default
+func (b *WebhookBatch) MaxWait() time.Duration {
+	if b.Window <= 0 {
+		return DefaultWebhookBatchWindow * time.Second
+	}
+	return time.Duration(b.Window) * time.Second
+}
+
+// WebhookFilter membatasi event yang dikirim berdasarkan field di data event (case-insensitive).
+// List kosong = tidak difilter. Filter hanya berlaku untuk event yang punya field tersebut,
+// jadi filter country tidak menahan node.offline.
+type WebhookFilter struct {
+	Aliases   []string `json:"aliases,omitempty" dynamodbav:"aliases,omitempty"`     // data.alias
+	GroupIDs  []string `json:"groupIds,omitempty" dynamodbav:"groupIds,omitempty"`   // data.groupId
+	Countries []string `json:"countries,omitempty" dynamodbav:"countries,omitempty"` // data.country (ISO 3166 alpha-2)
+	NodeIDs   []string `json:"nodeIds,omitempty" dynamodbav:"nodeIds,omitempty"`     // data.nodeId
+	Reasons   []string `json:"reasons,omitempty" dynamodbav:"reasons,omitempty"`     // data.reason (traffic.blocked)
+	IsBot     *bool    `json:"isBot,omitempty" dynamodbav:"isBot,omitempty"`         // data.isBot
+}
+
+// Matches true kalau data event lolos semua filter
+func (f *WebhookFilter) Matches(data map[string]interface{}) bool {
+	if f == nil {
+		return true
+	}
+	for _, c := range []struct {
+		field   string
+		allowed []string
+	}{
+		{"alias", f.Aliases},
+		{"groupId", f.GroupIDs},
+		{"country", f.Countries},
+		{"nodeId", f.NodeIDs},
+		{"reason", f.Reasons},
+	} {
+		v, ok := data[c.field]
+		if len(c.allowed) == 0 || !ok {
+			continue
+		}
+		if !containsFold(c.allowed, fmt.Sprint(v)) {
+			return false
+		}
+	}
+	if v, ok := data["isBot"].(bool); ok && f.IsBot != nil && v != *f.IsBot {
+		return false
+	}
+	return true
+}
+
+func containsFold(list []string, s string) bool {
+	for _, v := range list {
+		if strings.EqualFold(strings.TrimSpace(v), s) {
+			return true
+		}
+	}
+	return false
 }
 
 // Supported webhook event types
 const (
 	EventClickCreated   = "click.created"   // New click event
 	EventNodeOffline    = "node.offline"    // Node went offline
+	EventNodeOnline     = "node.online"     // Node came back online
 	EventTrafficBlocked = "traffic.blocked" // Traffic blocked by rate limit
 	EventLinkExpired    = "link.expired"    // Link reached expiration
 	EventLinkMaxClicks  = "link.maxclicks"  // Link reached max clicks
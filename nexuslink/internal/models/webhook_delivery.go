@@ -0,0 +1,81 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// WebhookDeliveryRetention: delivery log dihapus otomatis setelah ini (dead letter disimpan sampai di-replay / dihapus)
+const WebhookDeliveryRetention = 30 * 24 * time.Hour
+
+// Status akhir delivery
+const (
+	DeliveryPending  = "pending"  // belum selesai (masih retry)
+	DeliverySuccess  = "success"  // 2xx
+	DeliveryFailed   = "failed"   // retry habis / 4xx / dibatalkan → masuk dead letter
+	DeliveryReplayed = "replayed" // dead letter yang sudah dikirim ulang (lihat delivery ReplayOf)
+)
+
+// WebhookAttempt satu HTTP POST ke endpoint webhook
+type WebhookAttempt struct {
+	Attempt      int       `json:"attempt" dynamodbav:"attempt"`
+	RequestID    string    `json:"requestId" dynamodbav:"requestId"` // header X-Webhook-Request-Id
+	At           time.Time `json:"at" dynamodbav:"at"`
+	StatusCode   int       `json:"statusCode,omitempty" dynamodbav:"statusCode,omitempty"`
+	ResponseBody string    `json:"responseBody,omitempty" dynamodbav:"responseBody,omitempty"` // dipotong
+	Error        string    `json:"error,omitempty" dynamodbav:"error,omitempty"`
+	LatencyMs    int64     `json:"latencyMs" dynamodbav:"latencyMs"`
+}
+
+// WebhookDelivery adalah pengiriman satu event ke satu webhook beserta semua attempt-nya
+type WebhookDelivery struct {
+	ID        string           `json:"id" dynamodbav:"id"` // UUIDv7 (urut waktu)
+	WebhookID string           `json:"webhookId" dynamodbav:"webhookId"`
+	Event     string           `json:"event" dynamodbav:"event"`
+	URL       string           `json:"url" dynamodbav:"url"`
+	Payload   json.RawMessage  `json:"payload" dynamodbav:"payload"`                 // body yang dikirim (batch NDJSON disimpan sebagai JSON array)
+	Batch     int              `json:"batch,omitempty" dynamodbav:"batch,omitempty"` // jumlah event kalau delivery batch
+	Status    string           `json:"status" dynamodbav:"status"`
+	Attempts  []WebhookAttempt `json:"attempts" dynamodbav:"attempts"`
+	ReplayOf  string           `json:"replayOf,omitempty" dynamodbav:"replayOf,omitempty"` // delivery asal kalau ini replay
+	CreatedAt time.Time        `json:"createdAt" dynamodbav:"createdAt"`
+	UpdatedAt time.Time        `json:"updatedAt" dynamodbav:"updatedAt"`
+}
+
+// LastAttempt returns attempt terakhir (nil kalau belum ada)
+func (d *WebhookDelivery) LastAttempt() *WebhookAttempt {
+	if len(d.Attempts) == 0 {
+		return nil
+	}
+	return &d.Attempts[len(d.Attempts)-1]
+}
+
+// WebhookDeliveryFilter untuk list delivery / dead letter satu webhook. Field kosong = tidak difilter.
+type WebhookDeliveryFilter struct {
+	WebhookID string
+	Status    string
+	Event     string
+	Since     time.Time
+	Until     time.Time
+	Limit     int // <= 0 = tanpa batas
+}
+
+// Matches true kalau delivery lolos filter
+func (f WebhookDeliveryFilter) Matches(d *WebhookDelivery) bool {
+	if f.WebhookID != "" && d.WebhookID != f.WebhookID {
+		return false
+	}
+	if f.Status != "" && d.Status != f.Status {
+		return false
+	}
+	if f.Event != "" && d.Event != f.Event {
+		return false
+	}
+	if !f.Since.IsZero() && d.CreatedAt.Before(f.Since) {
+		return false
+	}
+	if !f.Until.IsZero() && d.CreatedAt.After(f.Until) {
+		return false
+	}
+	return true
+}
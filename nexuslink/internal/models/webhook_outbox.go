@@ -0,0 +1,21 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// WebhookOutboxEntry adalah satu event untuk satu webhook yang belum selesai dikirim.
+// Ditulis di request yang memicu event; worker menghapusnya setelah sukses atau gagal final,
+// jadi event tidak hilang walau API restart (at-least-once).
+type WebhookOutboxEntry struct {
+	ID            string          `json:"id" dynamodbav:"id"` // UUIDv7 = ID delivery log = header X-Webhook-Id
+	WebhookID     string          `json:"webhookId" dynamodbav:"webhookId"`
+	Event         string          `json:"event" dynamodbav:"event"`
+	Payload       json.RawMessage `json:"payload" dynamodbav:"payload"`
+	Batch         int             `json:"batch,omitempty" dynamodbav:"batch,omitempty"` // > 0: Payload = JSON array berisi Batch event
+	Attempts      int             `json:"attempts" dynamodbav:"attempts"`
+	NextAttemptAt time.Time       `json:"nextAttemptAt" dynamodbav:"nextAttemptAt"` // juga batas lease saat sedang diproses
+	LastError     string          `json:"lastError,omitempty" dynamodbav:"lastError,omitempty"`
+	CreatedAt     time.Time       `json:"createdAt" dynamodbav:"createdAt"`
+}
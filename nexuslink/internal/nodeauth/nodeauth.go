@@ -0,0 +1,73 @@
+// Package nodeauth membuat dan memverifikasi API key per node.
+//
+// Format key: "nxn_" + base64url(nodeID) + "." + base64url(32 byte acak).
+// Node ID ikut di dalam key supaya API cukup satu lookup credential per request;
+// yang disimpan hanya hash SHA-256 (key sudah acak penuh, tidak perlu bcrypt).
+package nodeauth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"github.com/afuzapratama/nexuslink/internal/models"
+)
+
+// KeyPrefix menandai key milik node (beda dari admin key NEXUS_API_KEY)
+const KeyPrefix = "nxn_"
+
+// NewKey membuat key baru untuk node. Key plaintext hanya dikembalikan di sini.
+func NewKey(nodeID string) (string, *models.NodeCredential, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", nil, err
+	}
+	secret := base64.RawURLEncoding.EncodeToString(b)
+	key := KeyPrefix + base64.RawURLEncoding.EncodeToString([]byte(nodeID)) + "." + secret
+
+	return key, &models.NodeCredential{
+		NodeID:    nodeID,
+		KeyHash:   HashKey(key),
+		Prefix:    KeyPrefix + secret[:8],
+		CreatedAt: time.Now().UTC(),
+	}, nil
+}
+
+// IsNodeKey true kalau key berformat key node
+func IsNodeKey(key string) bool {
+	return strings.HasPrefix(key, KeyPrefix)
+}
+
+// ParseKey returns node ID yang tertanam di key
+func ParseKey(key string) (string, bool) {
+	if !IsNodeKey(key) {
+		return "", false
+	}
+	encoded, secret, ok := strings.Cut(strings.TrimPrefix(key, KeyPrefix), ".")
+	if !ok || secret == "" {
+		return "", false
+	}
+	nodeID, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil || len(nodeID) == 0 {
+		return "", false
+	}
+	return string(nodeID), true
+}
+
+// HashKey returns hash SHA-256 (hex) dari key
+func HashKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// Verify true kalau key cocok dengan credential yang belum dicabut
+func Verify(cred *models.NodeCredential, key string) bool {
+	if cred == nil || cred.Revoked() {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(cred.KeyHash), []byte(HashKey(key))) == 1
+}
@@ -0,0 +1,46 @@
+package nodeauth
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewKeyAndVerify(t *testing.T) {
+	key, cred, err := NewKey("node-go.example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !IsNodeKey(key) || strings.Contains(cred.KeyHash, key) || !strings.HasPrefix(cred.Prefix, KeyPrefix) {
+		t.Fatalf("unexpected key/credential: %s %+v", key, cred)
+	}
+
+	nodeID, ok := ParseKey(key)
+	if !ok || nodeID != "node-go.example.com" {
+		t.Errorf("ParseKey = %q, %v", nodeID, ok)
+	}
+
+	if !Verify(cred, key) {
+		t.Errorf("key should verify against its credential")
+	}
+	other, _, _ := NewKey("node-go.example.com")
+	if Verify(cred, other) {
+		t.Errorf("another key for the same node must not verify")
+	}
+
+	cred.RevokedAt = time.Now()
+	if Verify(cred, key) {
+		t.Errorf("revoked credential must not verify")
+	}
+	if Verify(nil, key) {
+		t.Errorf("missing credential must not verify")
+	}
+}
+
+func TestParseKeyInvalid(t *testing.T) {
+	for _, key := range []string{"", "admin-key", "nxn_", "nxn_bm9kZQ", "nxn_!!!.secret", "nxn_.secret"} {
+		if _, ok := ParseKey(key); ok {
+			t.Errorf("ParseKey(%q) should fail", key)
+		}
+	}
+}
@@ -0,0 +1,205 @@
+// Package nodemonitor mendeteksi node yang berhenti heartbeat, mencatat transisi
+// online/offline sebagai NodeEvent dan menghitung uptime dari riwayat tersebut.
+package nodemonitor
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/afuzapratama/nexuslink/internal/metrics"
+	"github.com/afuzapratama/nexuslink/internal/models"
+	"github.com/afuzapratama/nexuslink/internal/repository"
+)
+
+// Default grace period & interval scan (agent heartbeat tiap 30 detik → 3x interval)
+const (
+	DefaultGracePeriod = 90 * time.Second
+	DefaultInterval    = 30 * time.Second
+)
+
+// Notifier mengirim webhook untuk event node (node.online / node.offline)
+type Notifier func(ctx context.Context, event string, data map[string]interface{})
+
+// Monitor men-scan LastSeenAt semua node secara berkala.
+// Dengan beberapa instance API, setiap instance menjalankan monitor sendiri
+// sehingga transisi bisa tercatat lebih dari sekali.
+type Monitor struct {
+	nodes    repository.NodeStore
+	events   repository.NodeEventStore
+	notify   Notifier
+	grace    time.Duration
+	interval time.Duration
+
+	mu    sync.Mutex
+	state map[string]string // nodeID → tipe event terakhir yang tercatat
+}
+
+// New creates a monitor; grace/interval <= 0 memakai default
+func New(nodes repository.NodeStore, events repository.NodeEventStore, notify Notifier, grace, interval time.Duration) *Monitor {
+	if grace <= 0 {
+		grace = DefaultGracePeriod
+	}
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+	return &Monitor{
+		nodes:    nodes,
+		events:   events,
+		notify:   notify,
+		grace:    grace,
+		interval: interval,
+		state:    make(map[string]string),
+	}
+}
+
+// Run scan setiap interval sampai ctx selesai
+func (m *Monitor) Run(ctx context.Context) {
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+
+	for {
+		m.scan(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (m *Monitor) scan(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	if err := m.Scan(ctx, time.Now().UTC()); err != nil {
+		log.Printf("node monitor: scan failed: %v", err)
+	}
+}
+
+// Scan mengecek semua node sekali pada waktu now
+func (m *Monitor) Scan(ctx context.Context, now time.Time) error {
+	nodes, err := m.nodes.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	var online, offline int64
+	seen := make(map[string]bool, len(nodes))
+
+	for i := range nodes {
+		n := &nodes[i]
+		seen[n.ID] = true
+
+		isOnline := !n.LastSeenAt.IsZero() && now.Sub(n.LastSeenAt) <= m.grace
+		if isOnline {
+			online++
+		} else {
+			offline++
+		}
+
+		// Heartbeat set IsOnline = true; yang set false hanya monitor
+		if !isOnline && n.IsOnline {
+			if err := m.nodes.MarkOffline(ctx, n.ID, n.LastSeenAt); err != nil {
+				log.Printf("node monitor: mark %s offline failed: %v", n.ID, err)
+			}
+		}
+
+		if err := m.observe(ctx, n, isOnline, now); err != nil {
+			log.Printf("node monitor: record event for %s failed: %v", n.ID, err)
+		}
+	}
+
+	// Node yang sudah dihapus tidak perlu diingat lagi
+	m.mu.Lock()
+	for id := range m.state {
+		if !seen[id] {
+			delete(m.state, id)
+		}
+	}
+	m.mu.Unlock()
+
+	metrics.GetMetrics().UpdateNodeCount(online, offline)
+	return nil
+}
+
+// observe mencatat event kalau status node berubah dari event terakhir.
+// Observasi pertama node tanpa riwayat dicatat sebagai baseline tanpa webhook,
+// supaya node yang sudah ada tidak memicu node.online saat monitor pertama kali jalan.
+func (m *Monitor) observe(ctx context.Context, n *models.Node, isOnline bool, now time.Time) error {
+	typ := models.NodeEventOffline
+	if isOnline {
+		typ = models.NodeEventOnline
+	}
+
+	prev, known, err := m.lastState(ctx, n.ID, now)
+	if err != nil {
+		return err
+	}
+	if known && prev == typ {
+		return nil
+	}
+
+	ev := &models.NodeEvent{NodeID: n.ID, Type: typ, At: now, LastSeenAt: n.LastSeenAt}
+	if err := m.events.Add(ctx, ev); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	m.state[n.ID] = typ
+	m.mu.Unlock()
+
+	if !known {
+		return nil
+	}
+
+	log.Printf("node monitor: node %s (%s) is %s, last seen %s", n.ID, n.Name, typ, n.LastSeenAt.Format(time.RFC3339))
+	if m.notify != nil {
+		event := models.EventNodeOffline
+		if isOnline {
+			event = models.EventNodeOnline
+		}
+		m.notify(ctx, event, webhookData(n))
+	}
+	return nil
+}
+
+// lastState returns tipe event terakhir node (dari cache, atau dari store saat pertama kali)
+func (m *Monitor) lastState(ctx context.Context, nodeID string, now time.Time) (string, bool, error) {
+	m.mu.Lock()
+	typ, ok := m.state[nodeID]
+	m.mu.Unlock()
+	if ok {
+		return typ, true, nil
+	}
+
+	last, err := m.events.Latest(ctx, nodeID, now.Add(time.Nanosecond))
+	if err != nil {
+		return "", false, err
+	}
+	if last == nil {
+		return "", false, nil
+	}
+
+	m.mu.Lock()
+	m.state[nodeID] = last.Type
+	m.mu.Unlock()
+	return last.Type, true, nil
+}
+
+func webhookData(n *models.Node) map[string]interface{} {
+	domain := ""
+	if len(n.Domains) > 0 {
+		domain = n.Domains[0]
+	}
+	return map[string]interface{}{
+		"nodeId":   n.ID,
+		"nodeName": n.Name,
+		"domain":   domain,
+		"lastSeen": n.LastSeenAt,
+		"region":   n.Region,
+		"domains":  n.Domains, // offline: domain ini degraded, canonical URL pindah ke node lain
+	}
+}
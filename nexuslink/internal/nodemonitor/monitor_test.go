@@ -0,0 +1,143 @@
+package nodemonitor
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/afuzapratama/nexuslink/internal/models"
+	"github.com/afuzapratama/nexuslink/internal/repository"
+)
+
+// fakeNodes hanya mengimplementasikan method yang dipakai monitor
+type fakeNodes struct {
+	repository.NodeStore
+	nodes map[string]*models.Node
+}
+
+func (f *fakeNodes) List(ctx context.Context) ([]models.Node, error) {
+	var out []models.Node
+	for _, n := range f.nodes {
+		out = append(out, *n)
+	}
+	return out, nil
+}
+
+func (f *fakeNodes) MarkOffline(ctx context.Context, id string, lastSeenAt time.Time) error {
+	if n, ok := f.nodes[id]; ok && n.LastSeenAt.Equal(lastSeenAt) {
+		n.IsOnline = false
+	}
+	return nil
+}
+
+type fakeEvents struct {
+	events []models.NodeEvent
+}
+
+func (f *fakeEvents) Add(ctx context.Context, ev *models.NodeEvent) error {
+	f.events = append(f.events, *ev)
+	sort.Slice(f.events, func(i, j int) bool { return f.events[i].At.Before(f.events[j].At) })
+	return nil
+}
+
+func (f *fakeEvents) ListSince(ctx context.Context, nodeID string, since time.Time) ([]models.NodeEvent, error) {
+	var out []models.NodeEvent
+	for _, ev := range f.events {
+		if ev.NodeID == nodeID && !ev.At.Before(since) {
+			out = append(out, ev)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeEvents) Latest(ctx context.Context, nodeID string, before time.Time) (*models.NodeEvent, error) {
+	var last *models.NodeEvent
+	for i, ev := range f.events {
+		if ev.NodeID == nodeID && ev.At.Before(before) {
+			last = &f.events[i]
+		}
+	}
+	return last, nil
+}
+
+func TestMonitorTransitions(t *testing.T) {
+	ctx := context.Background()
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	nodes := &fakeNodes{nodes: map[string]*models.Node{
+		"n1": {ID: "n1", Name: "edge-1", LastSeenAt: start, IsOnline: true},
+	}}
+	events := &fakeEvents{}
+	var fired []string
+	m := New(nodes, events, func(ctx context.Context, event string, data map[string]interface{}) {
+		fired = append(fired, event)
+	}, time.Minute, time.Second)
+
+	// Observasi pertama = baseline tanpa webhook
+	if err := m.Scan(ctx, start.Add(10*time.Second)); err != nil {
+		t.Fatal(err)
+	}
+	if len(events.events) != 1 || events.events[0].Type != models.NodeEventOnline || len(fired) != 0 {
+		t.Fatalf("baseline: events=%+v fired=%v", events.events, fired)
+	}
+
+	// Heartbeat berhenti → offline
+	if err := m.Scan(ctx, start.Add(2*time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	if nodes.nodes["n1"].IsOnline {
+		t.Errorf("node still marked online")
+	}
+	if len(fired) != 1 || fired[0] != models.EventNodeOffline {
+		t.Fatalf("expected node.offline, fired=%v", fired)
+	}
+
+	// Scan ulang tanpa perubahan tidak mencatat event baru
+	m.Scan(ctx, start.Add(3*time.Minute))
+	if len(events.events) != 2 {
+		t.Errorf("expected 2 events, got %d", len(events.events))
+	}
+
+	// Heartbeat masuk lagi → online
+	nodes.nodes["n1"].LastSeenAt = start.Add(4 * time.Minute)
+	nodes.nodes["n1"].IsOnline = true
+	m.Scan(ctx, start.Add(4*time.Minute+5*time.Second))
+	if len(fired) != 2 || fired[1] != models.EventNodeOnline {
+		t.Fatalf("expected node.online, fired=%v", fired)
+	}
+
+	// Monitor baru (restart API) melanjutkan dari event terakhir di store
+	m2 := New(nodes, events, func(ctx context.Context, event string, data map[string]interface{}) {
+		fired = append(fired, event)
+	}, time.Minute, time.Second)
+	m2.Scan(ctx, start.Add(5*time.Minute))
+	if len(fired) != 2 || len(events.events) != 3 {
+		t.Errorf("restart re-fired: fired=%v events=%d", fired, len(events.events))
+	}
+}
+
+func TestUptime(t *testing.T) {
+	from := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(10 * time.Hour)
+	ev := func(typ string, h int) models.NodeEvent {
+		return models.NodeEvent{Type: typ, At: from.Add(time.Duration(h) * time.Hour)}
+	}
+
+	// Online sejak sebelum window, offline 2 jam di tengah
+	prev := ev(models.NodeEventOnline, -5)
+	events := []models.NodeEvent{ev(models.NodeEventOffline, 4), ev(models.NodeEventOnline, 6)}
+	if p, ok := Uptime(&prev, events, from, to); !ok || p != 80 {
+		t.Errorf("uptime = %v ok=%v, want 80", p, ok)
+	}
+
+	// Tanpa riwayat sebelum window: hanya waktu setelah event pertama yang dihitung
+	events = []models.NodeEvent{ev(models.NodeEventOnline, 5), ev(models.NodeEventOffline, 9)}
+	if p, ok := Uptime(nil, events, from, to); !ok || p != 80 {
+		t.Errorf("uptime = %v ok=%v, want 80", p, ok)
+	}
+
+	if _, ok := Uptime(nil, nil, from, to); ok {
+		t.Errorf("expected no data")
+	}
+}
@@ -0,0 +1,85 @@
+package nodemonitor
+
+import (
+	"context"
+	"time"
+
+	"github.com/afuzapratama/nexuslink/internal/models"
+	"github.com/afuzapratama/nexuslink/internal/repository"
+)
+
+// Uptime menghitung persentase waktu online di [from, to] dari riwayat event.
+// prev adalah event terakhir sebelum from (nil kalau tidak ada), events urut dari yang terlama.
+// Waktu sebelum event pertama yang diketahui tidak ikut dihitung;
+// ok = false kalau sama sekali tidak ada data di window.
+func Uptime(prev *models.NodeEvent, events []models.NodeEvent, from, to time.Time) (percent float64, ok bool) {
+	var onlineDur, knownDur time.Duration
+
+	state := ""
+	cursor := from
+	if prev != nil {
+		state = prev.Type
+	}
+
+	advance := func(until time.Time) {
+		if until.After(to) {
+			until = to
+		}
+		if !until.After(cursor) {
+			return
+		}
+		if state != "" {
+			d := until.Sub(cursor)
+			knownDur += d
+			if state == models.NodeEventOnline {
+				onlineDur += d
+			}
+		}
+		cursor = until
+	}
+
+	for _, ev := range events {
+		if ev.At.Before(from) {
+			state = ev.Type
+			continue
+		}
+		advance(ev.At)
+		state = ev.Type
+	}
+	advance(to)
+
+	if knownDur <= 0 {
+		return 0, false
+	}
+	return float64(onlineDur) / float64(knownDur) * 100, true
+}
+
+// NodeUptime adalah uptime satu node untuk halaman nodes (nil = belum ada data)
+type NodeUptime struct {
+	Uptime24h *float64 `json:"uptime24h"`
+	Uptime7d  *float64 `json:"uptime7d"`
+}
+
+// UptimeFor membaca riwayat 7 hari node lalu menghitung uptime 24 jam & 7 hari
+func UptimeFor(ctx context.Context, store repository.NodeEventStore, nodeID string, now time.Time) (NodeUptime, error) {
+	weekAgo := now.Add(-7 * 24 * time.Hour)
+	dayAgo := now.Add(-24 * time.Hour)
+
+	prev, err := store.Latest(ctx, nodeID, weekAgo)
+	if err != nil {
+		return NodeUptime{}, err
+	}
+	events, err := store.ListSince(ctx, nodeID, weekAgo)
+	if err != nil {
+		return NodeUptime{}, err
+	}
+
+	var out NodeUptime
+	if p, ok := Uptime(prev, events, weekAgo, now); ok {
+		out.Uptime7d = &p
+	}
+	if p, ok := Uptime(prev, events, dayAgo, now); ok {
+		out.Uptime24h = &p
+	}
+	return out, nil
+}
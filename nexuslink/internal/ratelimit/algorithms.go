@@ -0,0 +1,174 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// Algoritma rate limit (dipilih per policy lewat Settings / Link.RateLimit)
+const (
+	AlgorithmSlidingLog    = "sliding_log"    // sorted set, satu member per request (presisi, memory O(limit))
+	AlgorithmSlidingWindow = "sliding_window" // counter window sekarang + sebelumnya (estimasi, memory O(1))
+	AlgorithmGCRA          = "gcra"           // generic cell rate algorithm / token bucket (memory O(1))
+)
+
+// ValidAlgorithm true untuk algoritma yang dikenal (kosong = default / pakai global)
+func ValidAlgorithm(algorithm string) bool {
+	switch algorithm {
+	case "", AlgorithmSlidingLog, AlgorithmSlidingWindow, AlgorithmGCRA:
+		return true
+	}
+	return false
+}
+
+// slidingLog: algoritma lama (ZADD per request). Member diberi suffix unik supaya
+// request dengan timestamp nanosecond yang sama tidak saling menimpa.
+func slidingLog(ctx context.Context, client *redis.Client, key string, limit int, window time.Duration, now time.Time) (Result, error) {
+	windowStart := now.Add(-window)
+
+	pipe := client.Pipeline()
+
+	// Remove old entries outside the window
+	pipe.ZRemRangeByScore(ctx, key, "0", fmt.Sprintf("%d", windowStart.UnixNano()))
+
+	// Add current request with score = current timestamp
+	pipe.ZAdd(ctx, key, redis.Z{
+		Score:  float64(now.UnixNano()),
+		Member: fmt.Sprintf("%d-%s", now.UnixNano(), uuid.NewString()[:8]),
+	})
+
+	// Count entries AFTER adding current request
+	countCmd := pipe.ZCard(ctx, key)
+
+	// Set expiration to window duration
+	pipe.Expire(ctx, key, window)
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return Result{}, fmt.Errorf("redis error: %w", err)
+	}
+
+	count := int(countCmd.Val())
+	res := Result{
+		Allowed:   count <= limit,
+		Remaining: limit - count,
+		ResetAt:   now.Add(window),
+	}
+	if res.Remaining < 0 {
+		res.Remaining = 0
+	}
+	if !res.Allowed {
+		res.RetryAfter = window
+	}
+	return res, nil
+}
+
+// slidingWindowScript: hash {idx, cur, prev}. Estimasi = prev * (sisa porsi window sebelumnya) + cur.
+// Request yang ditolak tidak ikut dihitung.
+var slidingWindowScript = redis.NewScript(`
+local key = KEYS[1]
+local now = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local limit = tonumber(ARGV[3])
+
+local idx = math.floor(now / window)
+local data = redis.call('HMGET', key, 'idx', 'cur', 'prev')
+local cidx = tonumber(data[1]) or idx
+local cur = tonumber(data[2]) or 0
+local prev = tonumber(data[3]) or 0
+
+if cidx ~= idx then
+  if cidx == idx - 1 then prev = cur else prev = 0 end
+  cur = 0
+end
+
+local elapsed = (now - idx * window) / window
+local estimate = prev * (1 - elapsed) + cur
+local allowed = 0
+if estimate + 1 <= limit then
+  allowed = 1
+  cur = cur + 1
+  estimate = estimate + 1
+end
+
+redis.call('HSET', key, 'idx', idx, 'cur', cur, 'prev', prev)
+redis.call('PEXPIRE', key, window * 2)
+
+local remaining = math.floor(limit - estimate)
+if remaining < 0 then remaining = 0 end
+
+-- retry: kapan estimasi turun di bawah limit (porsi prev berkurang linear)
+local retry = 0
+if allowed == 0 then
+  if prev > 0 and cur < limit then
+    retry = math.ceil(((prev + cur + 1 - limit) / prev) * window - (now - idx * window))
+  else
+    retry = (idx + 1) * window - now
+  end
+  if retry < 1 then retry = 1 end
+end
+
+return {allowed, remaining, (idx + 1) * window - now, retry}
+`)
+
+func slidingWindow(ctx context.Context, client *redis.Client, key string, limit int, window time.Duration, now time.Time) (Result, error) {
+	vals, err := slidingWindowScript.Run(ctx, client, []string{key},
+		now.UnixMilli(), window.Milliseconds(), limit).Int64Slice()
+	if err != nil {
+		return Result{}, fmt.Errorf("redis error: %w", err)
+	}
+	return scriptResult(vals, now), nil
+}
+
+// gcraScript: simpan theoretical arrival time (TAT, ms). Burst = limit, refill 1 request tiap window/limit.
+// Request yang ditolak tidak mengubah state.
+var gcraScript = redis.NewScript(`
+local key = KEYS[1]
+local now = tonumber(ARGV[1])
+local interval = tonumber(ARGV[2])
+local limit = tonumber(ARGV[3])
+local tolerance = interval * limit
+
+local tat = tonumber(redis.call('HGET', key, 'tat'))
+if tat == nil or tat < now then tat = now end
+
+local newTat = tat + interval
+local allowAt = newTat - tolerance
+
+if allowAt > now then
+  local remaining = 0
+  return {0, remaining, math.ceil(tat - now), math.ceil(allowAt - now)}
+end
+
+redis.call('HSET', key, 'tat', newTat, 'interval', interval)
+redis.call('PEXPIRE', key, math.ceil(newTat - now))
+
+local remaining = math.floor((now - allowAt) / interval)
+return {1, remaining, math.ceil(newTat - now), 0}
+`)
+
+func gcra(ctx context.Context, client *redis.Client, key string, limit int, window time.Duration, now time.Time) (Result, error) {
+	interval := float64(window.Milliseconds()) / float64(limit)
+	vals, err := gcraScript.Run(ctx, client, []string{key},
+		now.UnixMilli(), interval, limit).Int64Slice()
+	if err != nil {
+		return Result{}, fmt.Errorf("redis error: %w", err)
+	}
+	return scriptResult(vals, now), nil
+}
+
+// scriptResult: {allowed, remaining, resetMs, retryMs}
+func scriptResult(vals []int64, now time.Time) Result {
+	if len(vals) < 4 {
+		return Result{Allowed: true, ResetAt: now}
+	}
+	return Result{
+		Allowed:    vals[0] == 1,
+		Remaining:  int(vals[1]),
+		ResetAt:    now.Add(time.Duration(vals[2]) * time.Millisecond),
+		RetryAfter: time.Duration(vals[3]) * time.Millisecond,
+	}
+}
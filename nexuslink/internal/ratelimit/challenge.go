@@ -0,0 +1,42 @@
+package ratelimit
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ChallengeTTL adalah berapa lama visitor yang lolos challenge bebas dari limit per IP
+const ChallengeTTL = 10 * time.Minute
+
+// IssueChallenge membuat token "<expiry>.<hmac>" yang terikat ke IP dan alias.
+// Agent menaruh token ini di halaman challenge; JS di browser menyimpannya sebagai cookie
+// lalu reload, jadi client tanpa JS (kebanyakan bot) tetap kena limit.
+func IssueChallenge(secret, ip, alias string, now time.Time) string {
+	expiry := strconv.FormatInt(now.Add(ChallengeTTL).Unix(), 10)
+	return expiry + "." + challengeMAC(secret, ip, alias, expiry)
+}
+
+// VerifyChallenge memeriksa token dari IssueChallenge (IP & alias harus sama, belum expired)
+func VerifyChallenge(secret, token, ip, alias string, now time.Time) bool {
+	expiry, mac, ok := strings.Cut(token, ".")
+	if !ok || secret == "" {
+		return false
+	}
+
+	exp, err := strconv.ParseInt(expiry, 10, 64)
+	if err != nil || now.Unix() > exp {
+		return false
+	}
+
+	return hmac.Equal([]byte(mac), []byte(challengeMAC(secret, ip, alias, expiry)))
+}
+
+func challengeMAC(secret, ip, alias, expiry string) string {
+	h := hmac.New(sha256.New, []byte(secret))
+	h.Write([]byte(ip + "|" + alias + "|" + expiry))
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,63 @@
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+// memoryLimiter adalah fallback in-process saat Redis tidak tersedia.
+// Selalu pakai GCRA (state cuma satu timestamp per key), apapun algoritma policy-nya.
+type memoryLimiter struct {
+	mu    sync.Mutex
+	tats  map[string]time.Time // key -> theoretical arrival time
+	calls int
+}
+
+// memorySweepEvery: entry yang sudah lewat TAT-nya dibersihkan tiap N pengecekan
+const memorySweepEvery = 1000
+
+func newMemoryLimiter() *memoryLimiter {
+	return &memoryLimiter{tats: make(map[string]time.Time)}
+}
+
+func (m *memoryLimiter) allow(key string, limit int, window time.Duration, now time.Time) Result {
+	interval := window / time.Duration(limit)
+	tolerance := interval * time.Duration(limit)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.calls++
+	if m.calls%memorySweepEvery == 0 {
+		for k, t := range m.tats {
+			if t.Before(now) {
+				delete(m.tats, k)
+			}
+		}
+	}
+
+	tat, ok := m.tats[key]
+	if !ok || tat.Before(now) {
+		tat = now
+	}
+
+	newTat := tat.Add(interval)
+	allowAt := newTat.Add(-tolerance)
+
+	if allowAt.After(now) {
+		return Result{Allowed: false, ResetAt: tat, RetryAfter: allowAt.Sub(now)}
+	}
+
+	m.tats[key] = newTat
+	return Result{
+		Allowed:   true,
+		Remaining: int(now.Sub(allowAt) / interval),
+		ResetAt:   newTat,
+	}
+}
+
+func (m *memoryLimiter) reset(key string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.tats, key)
+}
@@ -0,0 +1,100 @@
+package ratelimit
+
+import (
+	"time"
+
+	"github.com/afuzapratama/nexuslink/internal/models"
+)
+
+// Policy adalah limit efektif untuk satu link (global Settings + override Link.RateLimit)
+type Policy struct {
+	PerIP     int
+	PerLink   int
+	Window    time.Duration
+	Action    string
+	Algorithm string
+
+	// LinkScopedIP true kalau limit per IP berasal dari override link,
+	// jadi dihitung per IP per link (bukan per IP global)
+	LinkScopedIP bool
+}
+
+// ResolvePolicy menggabungkan setting global dengan override link.
+// ok=false berarti link ini tidak di-rate limit.
+func ResolvePolicy(settings *models.Settings, link *models.Link) (Policy, bool) {
+	override := link.RateLimit
+	if override != nil && override.Disabled {
+		return Policy{}, false
+	}
+	if !settings.EnableRateLimit && override == nil {
+		return Policy{}, false
+	}
+
+	p := Policy{
+		PerIP:     settings.RateLimitPerIP,
+		PerLink:   settings.RateLimitPerLink,
+		Window:    time.Duration(settings.RateLimitWindow) * time.Second,
+		Action:    settings.RateLimitAction,
+		Algorithm: settings.RateLimitAlgorithm,
+	}
+
+	// Override link tanpa global aktif: hanya limit yang diset di link yang berlaku
+	if !settings.EnableRateLimit {
+		p.PerIP, p.PerLink = 0, 0
+	}
+
+	if override != nil {
+		if override.PerIP > 0 {
+			p.PerIP = override.PerIP
+			p.LinkScopedIP = true
+		}
+		if override.PerLink > 0 {
+			p.PerLink = override.PerLink
+		}
+		if override.Window > 0 {
+			p.Window = time.Duration(override.Window) * time.Second
+		}
+		if override.Action != "" {
+			p.Action = override.Action
+		}
+		if override.Algorithm != "" {
+			p.Algorithm = override.Algorithm
+		}
+	}
+
+	if p.Window <= 0 {
+		p.Window = time.Minute
+	}
+	if p.Algorithm == "" || !ValidAlgorithm(p.Algorithm) {
+		p.Algorithm = AlgorithmSlidingLog
+	}
+	switch p.Action {
+	case models.RateLimitActionFallback, models.RateLimitActionChallenge:
+	default:
+		p.Action = models.RateLimitActionReject
+	}
+
+	return p, p.PerIP > 0 || p.PerLink > 0
+}
+
+// ValidAction true untuk action yang dikenal (kosong = pakai global)
+func ValidAction(action string) bool {
+	switch action {
+	case "", models.RateLimitActionReject, models.RateLimitActionFallback, models.RateLimitActionChallenge:
+		return true
+	}
+	return false
+}
+
+// IPKey returns the limiter key untuk limit per IP
+func (p Policy) IPKey(ip, alias string) string {
+	if p.LinkScopedIP {
+		return "link:" + alias + ":ip:" + ip
+	}
+	return "ip:" + ip
+}
+
+// LinkKey returns the limiter key untuk limit per link
+func (p Policy) LinkKey(alias string) string {
+	return "link:" + alias
+}
@@ -0,0 +1,73 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/afuzapratama/nexuslink/internal/models"
+)
+
+func TestResolvePolicy(t *testing.T) {
+	global := models.DefaultSettings()
+
+	// Global nonaktif, link tanpa override → tidak di-limit
+	if _, ok := ResolvePolicy(global, &models.Link{Alias: "a"}); ok {
+		t.Errorf("expected no policy when rate limiting is disabled")
+	}
+
+	// Global nonaktif, override link → hanya limit dari link
+	p, ok := ResolvePolicy(global, &models.Link{Alias: "a", RateLimit: &models.LinkRateLimit{PerLink: 5}})
+	if !ok || p.PerIP != 0 || p.PerLink != 5 || p.Window != time.Minute || p.Action != models.RateLimitActionReject {
+		t.Errorf("unexpected override-only policy: %+v ok=%v", p, ok)
+	}
+
+	enabled := *global
+	enabled.EnableRateLimit = true
+	enabled.RateLimitAction = models.RateLimitActionFallback
+
+	p, ok = ResolvePolicy(&enabled, &models.Link{Alias: "a"})
+	if !ok || p.PerIP != 60 || p.PerLink != 120 || p.Action != models.RateLimitActionFallback {
+		t.Errorf("unexpected global policy: %+v", p)
+	}
+	if p.IPKey("1.2.3.4", "a") != "ip:1.2.3.4" {
+		t.Errorf("global per-IP key should not be link scoped: %s", p.IPKey("1.2.3.4", "a"))
+	}
+
+	// Override per IP dihitung per link
+	p, _ = ResolvePolicy(&enabled, &models.Link{Alias: "a", RateLimit: &models.LinkRateLimit{PerIP: 3, Window: 10, Action: models.RateLimitActionChallenge}})
+	if p.PerIP != 3 || p.PerLink != 120 || p.Window != 10*time.Second || p.Action != models.RateLimitActionChallenge {
+		t.Errorf("unexpected merged policy: %+v", p)
+	}
+	if p.IPKey("1.2.3.4", "a") != "link:a:ip:1.2.3.4" {
+		t.Errorf("override per-IP key should be link scoped: %s", p.IPKey("1.2.3.4", "a"))
+	}
+
+	// Override Disabled mengalahkan global
+	if _, ok := ResolvePolicy(&enabled, &models.Link{Alias: "a", RateLimit: &models.LinkRateLimit{Disabled: true}}); ok {
+		t.Errorf("disabled override should skip rate limiting")
+	}
+}
+
+func TestChallengeToken(t *testing.T) {
+	now := time.Now()
+	token := IssueChallenge("secret", "1.2.3.4", "promo", now)
+
+	if !VerifyChallenge("secret", token, "1.2.3.4", "promo", now) {
+		t.Errorf("valid token rejected")
+	}
+	if VerifyChallenge("secret", token, "5.6.7.8", "promo", now) {
+		t.Errorf("token accepted for another IP")
+	}
+	if VerifyChallenge("secret", token, "1.2.3.4", "other", now) {
+		t.Errorf("token accepted for another alias")
+	}
+	if VerifyChallenge("other-secret", token, "1.2.3.4", "promo", now) {
+		t.Errorf("token accepted with another secret")
+	}
+	if VerifyChallenge("secret", token, "1.2.3.4", "promo", now.Add(ChallengeTTL+time.Second)) {
+		t.Errorf("expired token accepted")
+	}
+	if VerifyChallenge("secret", "garbage", "1.2.3.4", "promo", now) {
+		t.Errorf("malformed token accepted")
+	}
+}
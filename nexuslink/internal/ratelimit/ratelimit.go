@@ -3,81 +3,151 @@ package ratelimit
 import (
 	"context"
 	"fmt"
+	"log"
+	"math"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/redis/go-redis/v9"
+
+	"github.com/afuzapratama/nexuslink/internal/metrics"
 )
 
-// Limiter implements sliding window rate limiting using Redis
+// redisRetryAfter: setelah Redis error, limiter pakai fallback in-process selama ini
+// sebelum mencoba Redis lagi (supaya tiap request tidak menunggu timeout Redis)
+const redisRetryAfter = 5 * time.Second
+
+// Limiter implements rate limiting using Redis, dengan algoritma yang bisa dipilih per policy.
+// Kalau Redis tidak ada / error, limit tetap ditegakkan lewat limiter in-process
+// (per instance API, jadi limit efektif dikali jumlah instance).
 type Limiter struct {
-	client *redis.Client
+	client   *redis.Client
+	fallback *memoryLimiter
+
+	mu        sync.Mutex
+	downUntil time.Time
+}
+
+// Result adalah hasil satu pengecekan rate limit
+type Result struct {
+	Allowed    bool
+	Remaining  int
+	ResetAt    time.Time     // kapan counter kembali penuh
+	RetryAfter time.Duration // kapan request berikutnya boleh (0 kalau allowed)
+	Degraded   bool          // true kalau diputuskan oleh fallback in-process (Redis tidak tersedia)
 }
 
-// NewLimiter creates a new rate limiter
+// NewLimiter creates a new rate limiter (client boleh nil → hanya in-process)
 func NewLimiter(client *redis.Client) *Limiter {
-	return &Limiter{client: client}
+	return &Limiter{client: client, fallback: newMemoryLimiter()}
 }
 
-// Allow checks if a request is allowed based on rate limit
+// Allow checks if a request is allowed based on rate limit (algoritma sliding log)
 // key: unique identifier (e.g., "ip:192.168.1.1" or "link:alias")
 // limit: max requests allowed
 // window: time window duration
 // Returns: allowed (bool), remaining (int), resetAt (time.Time)
 func (l *Limiter) Allow(ctx context.Context, key string, limit int, window time.Duration) (bool, int, time.Time, error) {
-	if l.client == nil {
-		// Redis not available, allow all requests
-		return true, limit, time.Now().Add(window), nil
-	}
+	res, err := l.Check(ctx, AlgorithmSlidingLog, key, limit, window)
+	return res.Allowed, res.Remaining, res.ResetAt, err
+}
 
+// Check menjalankan algoritma yang diminta untuk key ini.
+// Error Redis tidak dikembalikan: request diputuskan oleh fallback in-process (Result.Degraded).
+func (l *Limiter) Check(ctx context.Context, algorithm, key string, limit int, window time.Duration) (Result, error) {
 	now := time.Now()
-	windowStart := now.Add(-window)
-
-	// Redis key for this rate limit
-	redisKey := fmt.Sprintf("ratelimit:%s", key)
-
-	pipe := l.client.Pipeline()
+	if limit <= 0 || window <= 0 {
+		return Result{Allowed: true, Remaining: limit, ResetAt: now}, nil
+	}
+	if !ValidAlgorithm(algorithm) {
+		return Result{}, fmt.Errorf("unknown rate limit algorithm %q", algorithm)
+	}
+	if algorithm == "" {
+		algorithm = AlgorithmSlidingLog
+	}
 
-	// Remove old entries outside the window
-	pipe.ZRemRangeByScore(ctx, redisKey, "0", fmt.Sprintf("%d", windowStart.UnixNano()))
+	if l.redisAvailable(now) {
+		res, err := l.checkRedis(ctx, algorithm, key, limit, window, now)
+		if err == nil {
+			return res, nil
+		}
+		log.Printf("rate limit: redis error, using in-process fallback for %s: %v", redisRetryAfter, err)
+		l.markDown(now)
+	}
 
-	// Add current request with score = current timestamp
-	pipe.ZAdd(ctx, redisKey, redis.Z{
-		Score:  float64(now.UnixNano()),
-		Member: fmt.Sprintf("%d", now.UnixNano()),
-	})
+	metrics.GetMetrics().RecordRateLimitFallback()
+	res := l.fallback.allow(key, limit, window, now)
+	res.Degraded = true
+	return res, nil
+}
 
-	// Count entries AFTER adding current request
-	countCmd := pipe.ZCard(ctx, redisKey)
+func (l *Limiter) redisAvailable(now time.Time) bool {
+	if l.client == nil {
+		return false
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return now.After(l.downUntil)
+}
 
-	// Set expiration to window duration
-	pipe.Expire(ctx, redisKey, window)
+func (l *Limiter) markDown(now time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.downUntil = now.Add(redisRetryAfter)
+}
 
-	_, err := pipe.Exec(ctx)
-	if err != nil {
-		return false, 0, now.Add(window), fmt.Errorf("redis error: %w", err)
+func (l *Limiter) checkRedis(ctx context.Context, algorithm, key string, limit int, window time.Duration, now time.Time) (Result, error) {
+	switch algorithm {
+	case AlgorithmSlidingWindow:
+		return slidingWindow(ctx, l.client, redisKey(key, algorithm), limit, window, now)
+	case AlgorithmGCRA:
+		return gcra(ctx, l.client, redisKey(key, algorithm), limit, window, now)
+	default:
+		return slidingLog(ctx, l.client, redisKey(key, algorithm), limit, window, now)
 	}
+}
 
-	count := int(countCmd.Val())
-	allowed := count <= limit
-	remaining := limit - count
-	if remaining < 0 {
-		remaining = 0
+// redisKey: tiap algoritma punya key sendiri (tipe data Redis-nya beda),
+// jadi policy yang ganti algoritma tidak kena WRONGTYPE
+func redisKey(key, algorithm string) string {
+	switch algorithm {
+	case AlgorithmSlidingWindow:
+		return "ratelimit:" + key + ":sw"
+	case AlgorithmGCRA:
+		return "ratelimit:" + key + ":gcra"
+	default:
+		return "ratelimit:" + key
 	}
+}
 
-	resetAt := now.Add(window)
-
-	return allowed, remaining, resetAt, nil
+// originalKey kebalikan dari redisKey
+func originalKey(rk string) (key, algorithm string) {
+	key = strings.TrimPrefix(rk, "ratelimit:")
+	switch {
+	case strings.HasSuffix(key, ":sw"):
+		return strings.TrimSuffix(key, ":sw"), AlgorithmSlidingWindow
+	case strings.HasSuffix(key, ":gcra"):
+		return strings.TrimSuffix(key, ":gcra"), AlgorithmGCRA
+	default:
+		return key, AlgorithmSlidingLog
+	}
 }
 
-// Reset clears rate limit for a specific key
+// Reset clears rate limit for a specific key (semua algoritma + fallback in-process)
 func (l *Limiter) Reset(ctx context.Context, key string) error {
+	l.fallback.reset(key)
+
 	if l.client == nil {
 		return nil
 	}
 
-	redisKey := fmt.Sprintf("ratelimit:%s", key)
-	return l.client.Del(ctx, redisKey).Err()
+	return l.client.Del(ctx,
+		redisKey(key, AlgorithmSlidingLog),
+		redisKey(key, AlgorithmSlidingWindow),
+		redisKey(key, AlgorithmGCRA),
+	).Err()
 }
 
 // GetCount returns current request count for a key
@@ -86,14 +156,47 @@ func (l *Limiter) GetCount(ctx context.Context, key string) (int, error) {
 		return 0, nil
 	}
 
-	redisKey := fmt.Sprintf("ratelimit:%s", key)
-	count, err := l.client.ZCard(ctx, redisKey).Result()
-	return int(count), err
+	total := 0
+	for _, algo := range []string{AlgorithmSlidingLog, AlgorithmSlidingWindow, AlgorithmGCRA} {
+		n, err := l.count(ctx, redisKey(key, algo), algo, time.Now())
+		if err != nil && err != redis.Nil {
+			return 0, err
+		}
+		total += n
+	}
+	return total, nil
+}
+
+// count membaca pemakaian saat ini dari key Redis sesuai algoritmanya
+func (l *Limiter) count(ctx context.Context, rk, algorithm string, now time.Time) (int, error) {
+	switch algorithm {
+	case AlgorithmSlidingWindow:
+		v, err := l.client.HGet(ctx, rk, "cur").Int()
+		return v, err
+
+	case AlgorithmGCRA:
+		vals, err := l.client.HMGet(ctx, rk, "tat", "interval").Result()
+		if err != nil {
+			return 0, err
+		}
+		tat, _ := strconv.ParseFloat(fmt.Sprint(vals[0]), 64)
+		interval, _ := strconv.ParseFloat(fmt.Sprint(vals[1]), 64)
+		nowMs := float64(now.UnixMilli())
+		if interval <= 0 || tat <= nowMs {
+			return 0, nil
+		}
+		return int(math.Ceil((tat - nowMs) / interval)), nil
+
+	default:
+		n, err := l.client.ZCard(ctx, rk).Result()
+		return int(n), err
+	}
 }
 
 // RateLimitInfo holds information about a rate-limited key
 type RateLimitInfo struct {
 	Key       string    `json:"key"`       // Original key (e.g., "ip:1.2.3.4")
+	Algorithm string    `json:"algorithm"` // sliding_log, sliding_window, gcra
 	Count     int       `json:"count"`     // Current request count
 	ExpiresAt time.Time `json:"expiresAt"` // When the rate limit resets
 }
@@ -124,28 +227,28 @@ func (l *Limiter) GetAllRateLimits(ctx context.Context) ([]RateLimitInfo, error)
 	}
 
 	// Get info for each key
-	var results []RateLimitInfo
+	results := []RateLimitInfo{}
+	now := time.Now()
 
-	for _, redisKey := range keys {
-		// Get count
-		count, err := l.client.ZCard(ctx, redisKey).Result()
+	for _, rk := range keys {
+		key, algo := originalKey(rk)
+
+		count, err := l.count(ctx, rk, algo, now)
 		if err != nil {
 			continue
 		}
 
 		// Get TTL
-		ttl, err := l.client.TTL(ctx, redisKey).Result()
+		ttl, err := l.client.TTL(ctx, rk).Result()
 		if err != nil {
 			continue
 		}
 
-		// Extract original key (remove "ratelimit:" prefix)
-		originalKey := strings.TrimPrefix(redisKey, "ratelimit:")
-
 		results = append(results, RateLimitInfo{
-			Key:       originalKey,
-			Count:     int(count),
-			ExpiresAt: time.Now().Add(ttl),
+			Key:       key,
+			Algorithm: algo,
+			Count:     count,
+			ExpiresAt: now.Add(ttl),
 		})
 	}
 
@@ -0,0 +1,159 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+func TestMemoryLimiter(t *testing.T) {
+	m := newMemoryLimiter()
+	now := time.Unix(1_700_000_000, 0)
+
+	// Burst sampai limit boleh, request berikutnya ditolak
+	for i := 0; i < 5; i++ {
+		if res := m.allow("k", 5, time.Minute, now); !res.Allowed {
+			t.Fatalf("request %d denied, expected burst of 5", i+1)
+		}
+	}
+	res := m.allow("k", 5, time.Minute, now)
+	if res.Allowed {
+		t.Fatalf("6th request allowed")
+	}
+	if res.RetryAfter != 12*time.Second {
+		t.Errorf("RetryAfter = %v, want 12s", res.RetryAfter)
+	}
+
+	// Satu interval kemudian ada satu slot lagi
+	if res := m.allow("k", 5, time.Minute, now.Add(12*time.Second)); !res.Allowed {
+		t.Errorf("request after one interval denied")
+	}
+
+	// Key lain tidak terpengaruh, reset mengosongkan key
+	if res := m.allow("other", 5, time.Minute, now); !res.Allowed {
+		t.Errorf("independent key denied")
+	}
+	m.reset("k")
+	if res := m.allow("k", 5, time.Minute, now); !res.Allowed || res.Remaining != 4 {
+		t.Errorf("after reset: %+v", res)
+	}
+}
+
+func TestCheckWithoutRedis(t *testing.T) {
+	l := NewLimiter(nil)
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		res, err := l.Check(ctx, AlgorithmGCRA, "ip:1.2.3.4", 3, time.Minute)
+		if err != nil || !res.Allowed || !res.Degraded {
+			t.Fatalf("request %d: %+v err=%v", i+1, res, err)
+		}
+	}
+	if res, _ := l.Check(ctx, AlgorithmGCRA, "ip:1.2.3.4", 3, time.Minute); res.Allowed {
+		t.Errorf("fallback did not enforce the limit")
+	}
+
+	if _, err := l.Check(ctx, "leaky", "ip:1.2.3.4", 3, time.Minute); err == nil {
+		t.Errorf("expected error for unknown algorithm")
+	}
+}
+
+func TestRedisKeyRoundTrip(t *testing.T) {
+	for _, algo := range []string{AlgorithmSlidingLog, AlgorithmSlidingWindow, AlgorithmGCRA} {
+		key, got := originalKey(redisKey("link:promo:ip:1.2.3.4", algo))
+		if key != "link:promo:ip:1.2.3.4" || got != algo {
+			t.Errorf("%s: round trip gave key=%q algo=%q", algo, key, got)
+		}
+	}
+}
+
+func newTestRedisLimiter(t *testing.T) *Limiter {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { client.Close() })
+	return NewLimiter(client)
+}
+
+// step: satu request pada offset dari waktu awal beserta hasil yang diharapkan
+type step struct {
+	at         time.Duration
+	allowed    bool
+	retryAfter time.Duration
+}
+
+func runSteps(t *testing.T, l *Limiter, algorithm string, limit int, window time.Duration, steps []step) {
+	t.Helper()
+	ctx := context.Background()
+	// Kelipatan window supaya batas window sliding_window bisa ditebak
+	start := time.UnixMilli(1_700_000_000_000)
+
+	for i, s := range steps {
+		res, err := l.checkRedis(ctx, algorithm, "ip:1.2.3.4", limit, window, start.Add(s.at))
+		if err != nil {
+			t.Fatalf("%s step %d: %v", algorithm, i, err)
+		}
+		if res.Allowed != s.allowed {
+			t.Errorf("%s step %d (+%v): allowed = %v, want %v", algorithm, i, s.at, res.Allowed, s.allowed)
+		}
+		if res.RetryAfter != s.retryAfter {
+			t.Errorf("%s step %d (+%v): retryAfter = %v, want %v", algorithm, i, s.at, res.RetryAfter, s.retryAfter)
+		}
+	}
+}
+
+func TestRedisSlidingLog(t *testing.T) {
+	// Request yang ditolak tetap masuk log, retry selalu satu window penuh
+	runSteps(t, newTestRedisLimiter(t), AlgorithmSlidingLog, 3, 10*time.Second, []step{
+		{at: 0, allowed: true},
+		{at: time.Second, allowed: true},
+		{at: 2 * time.Second, allowed: true},
+		{at: 3 * time.Second, allowed: false, retryAfter: 10 * time.Second},
+		// +11s: request di +0s dan +1s sudah keluar window, tersisa +2s, +3s dan request ini
+		{at: 11 * time.Second, allowed: true},
+		{at: 11 * time.Second, allowed: false, retryAfter: 10 * time.Second},
+	})
+}
+
+func TestRedisSlidingWindow(t *testing.T) {
+	runSteps(t, newTestRedisLimiter(t), AlgorithmSlidingWindow, 4, 10*time.Second, []step{
+		{at: 0, allowed: true},
+		{at: 0, allowed: true},
+		{at: 0, allowed: true},
+		{at: 0, allowed: true},
+		// Window penuh tanpa porsi window sebelumnya: tunggu sampai window berikutnya
+		{at: 0, allowed: false, retryAfter: 10 * time.Second},
+		// 25% masuk window berikutnya: estimasi 4*0.75 = 3, masih ada satu slot
+		{at: 12500 * time.Millisecond, allowed: true},
+		// estimasi 4*(1-e)+1 baru turun ke 3 di e = 0.5 (+15s)
+		{at: 12500 * time.Millisecond, allowed: false, retryAfter: 2500 * time.Millisecond},
+		{at: 15 * time.Second, allowed: true},
+		// Window yang dilewati seluruhnya tidak ikut dihitung
+		{at: 40 * time.Second, allowed: true},
+	})
+}
+
+func TestRedisGCRAMatchesMemory(t *testing.T) {
+	ctx := context.Background()
+	l := newTestRedisLimiter(t)
+	m := newMemoryLimiter()
+	start := time.UnixMilli(1_700_000_000_000)
+	const limit, window = 5, time.Minute
+
+	offsets := []time.Duration{0, 0, 0, 0, 0, 0, 5 * time.Second, 12 * time.Second, 13 * time.Second, 24 * time.Second, 30 * time.Second, 90 * time.Second, 90 * time.Second}
+	for i, at := range offsets {
+		now := start.Add(at)
+		got, err := l.checkRedis(ctx, AlgorithmGCRA, "ip:1.2.3.4", limit, window, now)
+		if err != nil {
+			t.Fatalf("step %d: %v", i, err)
+		}
+		want := m.allow("ip:1.2.3.4", limit, window, now)
+		if got.Allowed != want.Allowed || got.RetryAfter != want.RetryAfter || got.Remaining != want.Remaining {
+			t.Errorf("step %d (+%v): redis = {allowed %v retry %v remaining %d}, memory = {allowed %v retry %v remaining %d}",
+				i, at, got.Allowed, got.RetryAfter, got.Remaining, want.Allowed, want.RetryAfter, want.Remaining)
+		}
+	}
+}
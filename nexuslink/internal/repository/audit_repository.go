@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/afuzapratama/nexuslink/internal/database"
+	"github.com/afuzapratama/nexuslink/internal/models"
+)
+
+// auditDefaultRange: query tanpa Since hanya membaca partisi 30 hari terakhir
+const auditDefaultRange = 30 * 24 * time.Hour
+
+type AuditRepository struct {
+	db *dynamodb.Client
+}
+
+func NewAuditRepository() *AuditRepository {
+	return &AuditRepository{
+		db: database.Client(),
+	}
+}
+
+// auditItem: partisi per hari (UTC), sort key = unix nano + ID supaya urut waktu dan unik
+type auditItem struct {
+	models.AuditRecord
+	Day string `dynamodbav:"day"`
+	SK  string `dynamodbav:"sk"`
+}
+
+func auditDay(t time.Time) string {
+	return t.UTC().Format("2006-01-02")
+}
+
+func auditSK(t time.Time, id string) string {
+	return fmt.Sprintf("%019d#%s", t.UnixNano(), id)
+}
+
+// Append menulis record baru (conditional put: record yang sudah ada tidak bisa ditimpa)
+func (r *AuditRepository) Append(ctx context.Context, rec *models.AuditRecord) error {
+	item, err := attributevalue.MarshalMap(auditItem{
+		AuditRecord: *rec,
+		Day:         auditDay(rec.At),
+		SK:          auditSK(rec.At, rec.ID),
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName:           aws.String(database.AuditLogTableName),
+		Item:                item,
+		ConditionExpression: aws.String("attribute_not_exists(sk)"),
+	})
+	return err
+}
+
+// List query partisi harian dari Until mundur ke Since; filter selain waktu dicek di sini
+func (r *AuditRepository) List(ctx context.Context, f models.AuditFilter) ([]models.AuditRecord, error) {
+	until := f.Until
+	if until.IsZero() {
+		until = time.Now().UTC()
+	}
+	since := f.Since
+	if since.IsZero() {
+		since = until.Add(-auditDefaultRange)
+	}
+
+	var records []models.AuditRecord
+	for day := until.UTC().Truncate(24 * time.Hour); !day.Before(since.UTC().Truncate(24 * time.Hour)); day = day.AddDate(0, 0, -1) {
+		paginator := dynamodb.NewQueryPaginator(r.db, &dynamodb.QueryInput{
+			TableName:              aws.String(database.AuditLogTableName),
+			KeyConditionExpression: aws.String("#d = :day AND sk BETWEEN :from AND :to"),
+			ExpressionAttributeNames: map[string]string{
+				"#d": "day",
+			},
+			ExpressionAttributeValues: map[string]types.AttributeValue{
+				":day":  &types.AttributeValueMemberS{Value: auditDay(day)},
+				":from": &types.AttributeValueMemberS{Value: fmt.Sprintf("%019d", since.UnixNano())},
+				":to":   &types.AttributeValueMemberS{Value: fmt.Sprintf("%019d~", until.UnixNano())},
+			},
+			ScanIndexForward: aws.Bool(false),
+		})
+		for paginator.HasMorePages() {
+			out, err := paginator.NextPage(ctx)
+			if err != nil {
+				return nil, err
+			}
+
+			var page []auditItem
+			if err := attributevalue.UnmarshalListOfMaps(out.Items, &page); err != nil {
+				return nil, err
+			}
+			for i := range page {
+				if !f.Matches(&page[i].AuditRecord) {
+					continue
+				}
+				records = append(records, page[i].AuditRecord)
+				if f.Limit > 0 && len(records) >= f.Limit {
+					return records, nil
+				}
+			}
+		}
+	}
+	return records, nil
+}
@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/afuzapratama/nexuslink/internal/database"
+)
+
+// ClickDedupRetention: berapa lama ID click dari agent diingat. Agent yang retry setelah
+// lebih lama dari ini (API mati berjam-jam) bisa menghitung click dua kali.
+const ClickDedupRetention = 24 * time.Hour
+
+type ClickDedupRepository struct {
+	db *dynamodb.Client
+}
+
+func NewClickDedupRepository() *ClickDedupRepository {
+	return &ClickDedupRepository{
+		db: database.Client(),
+	}
+}
+
+// Claim menandai ID click sudah diproses (conditional put); false = ID sudah pernah di-claim
+func (r *ClickDedupRepository) Claim(ctx context.Context, id string) (bool, error) {
+	_, err := r.db.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(database.ClickDedupTableName),
+		Item: map[string]types.AttributeValue{
+			"id":        &types.AttributeValueMemberS{Value: id},
+			"expiresAt": &types.AttributeValueMemberN{Value: strconv.FormatInt(time.Now().Add(ClickDedupRetention).Unix(), 10)},
+		},
+		ConditionExpression: aws.String("attribute_not_exists(id)"),
+	})
+	var ccfe *types.ConditionalCheckFailedException
+	if errors.As(err, &ccfe) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
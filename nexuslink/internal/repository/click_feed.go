@@ -0,0 +1,301 @@
+package repository
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"hash/fnv"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/afuzapratama/nexuslink/internal/database"
+	"github.com/afuzapratama/nexuslink/internal/models"
+)
+
+// clickFeedShards: click dibagi ke beberapa partition feed-createdAt-index supaya write rate
+// tinggi tidak menumpuk di satu partition GSI. List semua click me-merge semua shard.
+const clickFeedShards = 8
+
+// clickFeedMarker: item di tabel alias (prefix "#" seperti counter sequence) yang menandai
+// backfill feed click lama sudah selesai, supaya tidak di-scan ulang setiap start
+const clickFeedMarker = "#migration:click-feed"
+
+// backfillConcurrency membatasi UpdateItem paralel saat backfill
+const backfillConcurrency = 8
+
+func clickFeed(shard int) string {
+	return "click#" + strconv.Itoa(shard)
+}
+
+// clickFeedFor memilih shard dari ID click (stabil, jadi backfill dan write baru konsisten)
+func clickFeedFor(id string) string {
+	h := fnv.New32a()
+	h.Write([]byte(id))
+	return clickFeed(int(h.Sum32() % clickFeedShards))
+}
+
+// marshalClick menulis click event beserta atribut feed (dipakai semua write ke tabel click events)
+func marshalClick(ev *models.ClickEvent) (map[string]types.AttributeValue, error) {
+	item, err := attributevalue.MarshalMap(ev)
+	if err != nil {
+		return nil, err
+	}
+	item["feed"] = &types.AttributeValueMemberS{Value: clickFeedFor(ev.ID)}
+	return item, nil
+}
+
+// feedShardInput membangun Query ke satu shard feed-createdAt-index (newest first)
+func feedShardInput(shard int) *dynamodb.QueryInput {
+	return &dynamodb.QueryInput{
+		TableName:              aws.String(database.ClickEventsTableName),
+		IndexName:              aws.String(database.ClickEventsFeedIndex),
+		KeyConditionExpression: aws.String("#f = :feed"),
+		ExpressionAttributeNames: map[string]string{
+			"#f": "feed",
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":feed": &types.AttributeValueMemberS{Value: clickFeed(shard)},
+		},
+		ScanIndexForward: aws.Bool(false),
+	}
+}
+
+// BackfillFeed mengisi atribut feed untuk click yang ditulis sebelum feed-createdAt-index ada.
+// Jalan di background; sampai selesai, list semua click tetap pakai Scan (lihat ListAllPage).
+func (r *ClickRepository) BackfillFeed(ctx context.Context) error {
+	markerKey := map[string]types.AttributeValue{
+		"aliasKey": &types.AttributeValueMemberS{Value: clickFeedMarker},
+	}
+	out, err := r.db.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName:      aws.String(database.LinkAliasesTableName),
+		Key:            markerKey,
+		ConsistentRead: aws.Bool(true),
+	})
+	if err != nil {
+		return err
+	}
+	if out.Item != nil {
+		r.feedReady.Store(true)
+		return nil
+	}
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+	)
+	sem := make(chan struct{}, backfillConcurrency)
+
+	paginator := dynamodb.NewScanPaginator(r.db, &dynamodb.ScanInput{
+		TableName:                aws.String(database.ClickEventsTableName),
+		FilterExpression:         aws.String("attribute_not_exists(#f)"),
+		ProjectionExpression:     aws.String("id"),
+		ExpressionAttributeNames: map[string]string{"#f": "feed"},
+	})
+	for paginator.HasMorePages() && firstErr == nil {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return err
+		}
+		for _, item := range page.Items {
+			id, ok := item["id"].(*types.AttributeValueMemberS)
+			if !ok {
+				continue
+			}
+
+			wg.Add(1)
+			sem <- struct{}{}
+			go func(id string) {
+				defer wg.Done()
+				defer func() { <-sem }()
+
+				_, err := r.db.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+					TableName:                aws.String(database.ClickEventsTableName),
+					Key:                      map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: id}},
+					UpdateExpression:         aws.String("SET #f = :feed"),
+					ConditionExpression:      aws.String("attribute_exists(id)"),
+					ExpressionAttributeNames: map[string]string{"#f": "feed"},
+					ExpressionAttributeValues: map[string]types.AttributeValue{
+						":feed": &types.AttributeValueMemberS{Value: clickFeedFor(id)},
+					},
+				})
+				var ccfe *types.ConditionalCheckFailedException
+				if err != nil && !errors.As(err, &ccfe) {
+					mu.Lock()
+					if firstErr == nil {
+						firstErr = err
+					}
+					mu.Unlock()
+				}
+			}(id.Value)
+		}
+		wg.Wait()
+	}
+	if firstErr != nil {
+		return firstErr
+	}
+
+	_, err = r.db.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(database.LinkAliasesTableName),
+		Item:      markerKey,
+	})
+	if err != nil {
+		return err
+	}
+	r.feedReady.Store(true)
+	return nil
+}
+
+// feedShard: posisi baca satu shard feed (bagian dari cursor list semua click)
+type feedShard struct {
+	Start map[string]string `json:"s,omitempty"` // ExclusiveStartKey index: id, feed, createdAt
+	Done  bool              `json:"d,omitempty"`
+}
+
+// readFeed membaca n click terbaru dari gabungan semua shard mulai dari posisi shards.
+// shards diubah in place: posisi tiap shard maju ke item terakhir yang diambil dari shard itu.
+func (r *ClickRepository) readFeed(ctx context.Context, shards []feedShard, n int) ([]models.ClickEvent, error) {
+	type row struct {
+		shard     int
+		createdAt string
+		item      map[string]types.AttributeValue
+	}
+	var rows []row
+	read := make([]int, len(shards))
+	exhausted := make([]bool, len(shards))
+
+	for i := range shards {
+		if shards[i].Done {
+			continue
+		}
+		items, more, err := r.queryShard(ctx, i, shards[i].Start, n)
+		if err != nil {
+			return nil, err
+		}
+		read[i], exhausted[i] = len(items), !more
+		for _, item := range items {
+			rows = append(rows, row{shard: i, createdAt: stringAttr(item, "createdAt"), item: item})
+		}
+	}
+
+	// Urutan sama dengan sort key index (string createdAt); stable supaya item yang diambil
+	// dari tiap shard selalu prefix dari hasil query shard itu
+	sort.SliceStable(rows, func(a, b int) bool { return rows[a].createdAt > rows[b].createdAt })
+	if len(rows) > n {
+		rows = rows[:n]
+	}
+
+	taken := make([]int, len(shards))
+	items := make([]map[string]types.AttributeValue, 0, len(rows))
+	for _, rw := range rows {
+		shards[rw.shard].Start = map[string]string{
+			"id":        stringAttr(rw.item, "id"),
+			"feed":      stringAttr(rw.item, "feed"),
+			"createdAt": rw.createdAt,
+		}
+		taken[rw.shard]++
+		items = append(items, rw.item)
+	}
+	for i := range shards {
+		if !shards[i].Done && exhausted[i] && taken[i] == read[i] {
+			shards[i].Done = true
+		}
+	}
+
+	events := []models.ClickEvent{}
+	if err := attributevalue.UnmarshalListOfMaps(items, &events); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// queryShard membaca sampai n item dari satu shard; more = masih ada item setelahnya
+func (r *ClickRepository) queryShard(ctx context.Context, shard int, start map[string]string, n int) ([]map[string]types.AttributeValue, bool, error) {
+	input := feedShardInput(shard)
+	if len(start) > 0 {
+		key, err := attributevalue.MarshalMap(start)
+		if err != nil {
+			return nil, false, err
+		}
+		input.ExclusiveStartKey = key
+	}
+
+	var items []map[string]types.AttributeValue
+	for len(items) < n {
+		input.Limit = aws.Int32(int32(n - len(items)))
+		out, err := r.db.Query(ctx, input)
+		if err != nil {
+			return nil, false, err
+		}
+		items = append(items, out.Items...)
+		if len(out.LastEvaluatedKey) == 0 {
+			return items, false, nil
+		}
+		input.ExclusiveStartKey = out.LastEvaluatedKey
+	}
+	return items, true, nil
+}
+
+// countFeed menghitung semua click lewat Select COUNT di tiap shard (tanpa baca item)
+func (r *ClickRepository) countFeed(ctx context.Context) (int, error) {
+	total := 0
+	for shard := 0; shard < clickFeedShards; shard++ {
+		input := feedShardInput(shard)
+		input.Select = types.SelectCount
+		paginator := dynamodb.NewQueryPaginator(r.db, input)
+		for paginator.HasMorePages() {
+			out, err := paginator.NextPage(ctx)
+			if err != nil {
+				return 0, err
+			}
+			total += int(out.Count)
+		}
+	}
+	return total, nil
+}
+
+// encodeFeedCursor: posisi semua shard jadi string opaque; semua shard habis → ""
+func encodeFeedCursor(shards []feedShard) (string, error) {
+	done := true
+	for _, s := range shards {
+		done = done && s.Done
+	}
+	if done {
+		return "", nil
+	}
+	b, err := json.Marshal(shards)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// decodeFeedCursor kebalikan encodeFeedCursor; cursor kosong → mulai dari awal semua shard
+func decodeFeedCursor(cursor string) ([]feedShard, error) {
+	if cursor == "" {
+		return make([]feedShard, clickFeedShards), nil
+	}
+	b, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var shards []feedShard
+	if err := json.Unmarshal(b, &shards); err != nil || len(shards) != clickFeedShards {
+		return nil, ErrInvalidCursor
+	}
+	return shards, nil
+}
+
+func stringAttr(item map[string]types.AttributeValue, name string) string {
+	if v, ok := item[name].(*types.AttributeValueMemberS); ok {
+		return v.Value
+	}
+	return ""
+}
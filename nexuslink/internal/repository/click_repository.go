@@ -2,6 +2,8 @@ package repository
 
 import (
 	"context"
+	"fmt"
+	"sync/atomic"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -16,6 +18,9 @@ import (
 
 type ClickRepository struct {
 	db *dynamodb.Client
+
+	// feedReady true setelah BackfillFeed selesai; sebelum itu list semua click pakai Scan
+	feedReady atomic.Bool
 }
 
 func NewClickRepository() *ClickRepository {
@@ -33,7 +38,7 @@ func (r *ClickRepository) LogClick(ctx context.Context, ev *models.ClickEvent) e
 		ev.CreatedAt = time.Now().UTC()
 	}
 
-	item, err := attributevalue.MarshalMap(ev)
+	item, err := marshalClick(ev)
 	if err != nil {
 		return err
 	}
@@ -45,52 +50,202 @@ func (r *ClickRepository) LogClick(ctx context.Context, ev *models.ClickEvent) e
 	return err
 }
 
-// Ambil semua click events untuk alias tertentu (untuk dev masih pakai Scan)
-func (r *ClickRepository) ListByAlias(ctx context.Context, alias string) ([]models.ClickEvent, error) {
-	out, err := r.db.Scan(ctx, &dynamodb.ScanInput{
-		TableName:        aws.String(database.ClickEventsTableName),
-		FilterExpression: aws.String("#a = :alias"),
+// maxBatchWriteItems adalah batas item per BatchWriteItem request DynamoDB
+const maxBatchWriteItems = 25
+
+// LogClicks menyimpan banyak click event sekaligus lewat BatchWriteItem (per 25 item).
+// UnprocessedItems dicoba ulang dengan backoff; kalau masih tersisa, return error.
+func (r *ClickRepository) LogClicks(ctx context.Context, events []models.ClickEvent) error {
+	now := time.Now().UTC()
+
+	for start := 0; start < len(events); start += maxBatchWriteItems {
+		end := start + maxBatchWriteItems
+		if end > len(events) {
+			end = len(events)
+		}
+
+		requests := make([]types.WriteRequest, 0, end-start)
+		for i := start; i < end; i++ {
+			ev := &events[i]
+			if ev.ID == "" {
+				ev.ID = uuid.NewString()
+			}
+			if ev.CreatedAt.IsZero() {
+				ev.CreatedAt = now
+			}
+
+			item, err := marshalClick(ev)
+			if err != nil {
+				return err
+			}
+			requests = append(requests, types.WriteRequest{PutRequest: &types.PutRequest{Item: item}})
+		}
+
+		if err := r.batchWrite(ctx, requests); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *ClickRepository) batchWrite(ctx context.Context, requests []types.WriteRequest) error {
+	backoff := 50 * time.Millisecond
+
+	for attempt := 0; len(requests) > 0; attempt++ {
+		if attempt > 0 {
+			if attempt > 5 {
+				return fmt.Errorf("batch write: %d click events unprocessed after retries", len(requests))
+			}
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(backoff):
+			}
+			backoff *= 2
+		}
+
+		out, err := r.db.BatchWriteItem(ctx, &dynamodb.BatchWriteItemInput{
+			RequestItems: map[string][]types.WriteRequest{
+				database.ClickEventsTableName: requests,
+			},
+		})
+		if err != nil {
+			return err
+		}
+		requests = out.UnprocessedItems[database.ClickEventsTableName]
+	}
+
+	return nil
+}
+
+// queryAliasInput membangun Query ke alias-createdAt-index (newest first)
+func queryAliasInput(alias string) *dynamodb.QueryInput {
+	return &dynamodb.QueryInput{
+		TableName:              aws.String(database.ClickEventsTableName),
+		IndexName:              aws.String(database.ClickEventsAliasIndex),
+		KeyConditionExpression: aws.String("#a = :alias"),
 		ExpressionAttributeNames: map[string]string{
 			"#a": "alias",
 		},
 		ExpressionAttributeValues: map[string]types.AttributeValue{
 			":alias": &types.AttributeValueMemberS{Value: alias},
 		},
-	})
-	if err != nil {
-		return nil, err
+		ScanIndexForward: aws.Bool(false),
 	}
+}
 
+// Ambil semua click events untuk alias tertentu (terbaru dulu)
+func (r *ClickRepository) ListByAlias(ctx context.Context, alias string) ([]models.ClickEvent, error) {
 	var events []models.ClickEvent
-	if err := attributevalue.UnmarshalListOfMaps(out.Items, &events); err != nil {
-		return nil, err
+	paginator := dynamodb.NewQueryPaginator(r.db, queryAliasInput(alias))
+	for paginator.HasMorePages() {
+		out, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		var page []models.ClickEvent
+		if err := attributevalue.UnmarshalListOfMaps(out.Items, &page); err != nil {
+			return nil, err
+		}
+		events = append(events, page...)
 	}
 	return events, nil
 }
 
 // ListByAliasPaginated returns paginated click events for a specific alias
 func (r *ClickRepository) ListByAliasPaginated(ctx context.Context, alias string, page, limit int) ([]models.ClickEvent, int, error) {
-	// Get all events for total count
-	out, err := r.db.Scan(ctx, &dynamodb.ScanInput{
-		TableName:        aws.String(database.ClickEventsTableName),
-		FilterExpression: aws.String("#a = :alias"),
-		ExpressionAttributeNames: map[string]string{
-			"#a": "alias",
-		},
-		ExpressionAttributeValues: map[string]types.AttributeValue{
-			":alias": &types.AttributeValueMemberS{Value: alias},
-		},
-	})
+	// Total dihitung lewat Select COUNT di index (tanpa baca item)
+	total := 0
+	countInput := queryAliasInput(alias)
+	countInput.Select = types.SelectCount
+	paginator := dynamodb.NewQueryPaginator(r.db, countInput)
+	for paginator.HasMorePages() {
+		out, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, 0, err
+		}
+		total += int(out.Count)
+	}
+
+	// Calculate offset
+	offset := (page - 1) * limit
+	if offset < 0 {
+		offset = 0
+	}
+
+	if offset >= total {
+		return []models.ClickEvent{}, total, nil
+	}
+
+	// Query berurutan sampai offset+limit item terkumpul
+	var events []models.ClickEvent
+	input := queryAliasInput(alias)
+	for len(events) < offset+limit {
+		input.Limit = aws.Int32(int32(offset + limit - len(events)))
+
+		out, err := r.db.Query(ctx, input)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		var batch []models.ClickEvent
+		if err := attributevalue.UnmarshalListOfMaps(out.Items, &batch); err != nil {
+			return nil, 0, err
+		}
+		events = append(events, batch...)
+
+		if len(out.LastEvaluatedKey) == 0 {
+			break
+		}
+		input.ExclusiveStartKey = out.LastEvaluatedKey
+	}
+
+	if offset >= len(events) {
+		return []models.ClickEvent{}, total, nil
+	}
+	return events[offset:], total, nil
+}
+
+// ListByAliasPage returns satu halaman click events (terbaru dulu) mulai dari cursor
+func (r *ClickRepository) ListByAliasPage(ctx context.Context, alias string, limit int, cursor string) ([]models.ClickEvent, string, error) {
+	startKey, err := decodeCursor(cursor)
 	if err != nil {
-		return nil, 0, err
+		return nil, "", err
 	}
 
-	var allEvents []models.ClickEvent
-	if err := attributevalue.UnmarshalListOfMaps(out.Items, &allEvents); err != nil {
-		return nil, 0, err
+	input := queryAliasInput(alias)
+	input.Limit = aws.Int32(int32(limit))
+	input.ExclusiveStartKey = startKey
+
+	out, err := r.db.Query(ctx, input)
+	if err != nil {
+		return nil, "", err
 	}
 
-	total := len(allEvents)
+	events := []models.ClickEvent{}
+	if err := attributevalue.UnmarshalListOfMaps(out.Items, &events); err != nil {
+		return nil, "", err
+	}
+
+	next, err := encodeCursor(out.LastEvaluatedKey)
+	if err != nil {
+		return nil, "", err
+	}
+	return events, next, nil
+}
+
+// ListAllPaginated returns paginated click events for all links (for dashboard), terbaru dulu
+func (r *ClickRepository) ListAllPaginated(ctx context.Context, page, limit int) ([]models.ClickEvent, int, error) {
+	if !r.feedReady.Load() {
+		return r.scanAllPaginated(ctx, page, limit)
+	}
+
+	total, err := r.countFeed(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
 
 	// Calculate offset
 	offset := (page - 1) * limit
@@ -98,32 +253,61 @@ func (r *ClickRepository) ListByAliasPaginated(ctx context.Context, alias string
 		offset = 0
 	}
 
-	// Slice for pagination
 	if offset >= total {
 		return []models.ClickEvent{}, total, nil
 	}
 
-	end := offset + limit
-	if end > total {
-		end = total
+	events, err := r.readFeed(ctx, make([]feedShard, clickFeedShards), offset+limit)
+	if err != nil {
+		return nil, 0, err
 	}
-
-	return allEvents[offset:end], total, nil
+	if offset >= len(events) {
+		return []models.ClickEvent{}, total, nil
+	}
+	return events[offset:], total, nil
 }
 
-// ListAllPaginated returns paginated click events for all links (for dashboard)
-func (r *ClickRepository) ListAllPaginated(ctx context.Context, page, limit int) ([]models.ClickEvent, int, error) {
-	// Scan all events (for production, consider GSI or better pagination strategy)
-	out, err := r.db.Scan(ctx, &dynamodb.ScanInput{
-		TableName: aws.String(database.ClickEventsTableName),
-	})
+// ListAllPage returns satu halaman click events semua link (terbaru dulu) mulai dari cursor.
+// Selama backfill feed belum selesai urutan mengikuti Scan (tidak terurut waktu).
+func (r *ClickRepository) ListAllPage(ctx context.Context, limit int, cursor string) ([]models.ClickEvent, string, error) {
+	if !r.feedReady.Load() {
+		return r.scanAllPage(ctx, limit, cursor)
+	}
+
+	shards, err := decodeFeedCursor(cursor)
 	if err != nil {
-		return nil, 0, err
+		return nil, "", err
+	}
+
+	events, err := r.readFeed(ctx, shards, limit)
+	if err != nil {
+		return nil, "", err
 	}
 
+	next, err := encodeFeedCursor(shards)
+	if err != nil {
+		return nil, "", err
+	}
+	return events, next, nil
+}
+
+// scanAllPaginated: ListAllPaginated lewat full Scan, dipakai sampai BackfillFeed selesai
+func (r *ClickRepository) scanAllPaginated(ctx context.Context, page, limit int) ([]models.ClickEvent, int, error) {
 	var allEvents []models.ClickEvent
-	if err := attributevalue.UnmarshalListOfMaps(out.Items, &allEvents); err != nil {
-		return nil, 0, err
+	paginator := dynamodb.NewScanPaginator(r.db, &dynamodb.ScanInput{
+		TableName: aws.String(database.ClickEventsTableName),
+	})
+	for paginator.HasMorePages() {
+		out, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		var batch []models.ClickEvent
+		if err := attributevalue.UnmarshalListOfMaps(out.Items, &batch); err != nil {
+			return nil, 0, err
+		}
+		allEvents = append(allEvents, batch...)
 	}
 
 	total := len(allEvents)
@@ -147,37 +331,63 @@ func (r *ClickRepository) ListAllPaginated(ctx context.Context, page, limit int)
 	return allEvents[offset:end], total, nil
 }
 
-// DeleteByLinkAlias deletes all click events for a given link alias
-func (r *ClickRepository) DeleteByLinkAlias(ctx context.Context, alias string) error {
-	// First, scan to find all items with this alias
+// scanAllPage: ListAllPage lewat Scan, dipakai sampai BackfillFeed selesai
+func (r *ClickRepository) scanAllPage(ctx context.Context, limit int, cursor string) ([]models.ClickEvent, string, error) {
+	startKey, err := decodeCursor(cursor)
+	if err != nil {
+		return nil, "", err
+	}
+
 	out, err := r.db.Scan(ctx, &dynamodb.ScanInput{
-		TableName:        aws.String(database.ClickEventsTableName),
-		FilterExpression: aws.String("#a = :alias"),
-		ExpressionAttributeNames: map[string]string{
-			"#a": "alias",
-		},
-		ExpressionAttributeValues: map[string]types.AttributeValue{
-			":alias": &types.AttributeValueMemberS{Value: alias},
-		},
+		TableName:         aws.String(database.ClickEventsTableName),
+		Limit:             aws.Int32(int32(limit)),
+		ExclusiveStartKey: startKey,
 	})
 	if err != nil {
-		return err
+		return nil, "", err
 	}
 
-	if len(out.Items) == 0 {
-		return nil // No events to delete
+	events := []models.ClickEvent{}
+	if err := attributevalue.UnmarshalListOfMaps(out.Items, &events); err != nil {
+		return nil, "", err
 	}
 
-	// Delete each item
-	for _, item := range out.Items {
-		if idAttr, ok := item["id"]; ok {
-			_, err := r.db.DeleteItem(ctx, &dynamodb.DeleteItemInput{
-				TableName: aws.String(database.ClickEventsTableName),
-				Key: map[string]types.AttributeValue{
-					"id": idAttr,
-				},
-			})
-			if err != nil {
+	next, err := encodeCursor(out.LastEvaluatedKey)
+	if err != nil {
+		return nil, "", err
+	}
+	return events, next, nil
+}
+
+// DeleteByLinkAlias deletes all click events for a given link alias
+func (r *ClickRepository) DeleteByLinkAlias(ctx context.Context, alias string) error {
+	input := queryAliasInput(alias)
+	input.ProjectionExpression = aws.String("id")
+
+	paginator := dynamodb.NewQueryPaginator(r.db, input)
+	for paginator.HasMorePages() {
+		out, err := paginator.NextPage(ctx)
+		if err != nil {
+			return err
+		}
+
+		requests := make([]types.WriteRequest, 0, len(out.Items))
+		for _, item := range out.Items {
+			if idAttr, ok := item["id"]; ok {
+				requests = append(requests, types.WriteRequest{
+					DeleteRequest: &types.DeleteRequest{
+						Key: map[string]types.AttributeValue{"id": idAttr},
+					},
+				})
+			}
+		}
+
+		for start := 0; start < len(requests); start += maxBatchWriteItems {
+			end := start + maxBatchWriteItems
+			if end > len(requests) {
+				end = len(requests)
+			}
+			if err := r.batchWrite(ctx, requests[start:end]); err != nil {
 				return err
 			}
 		}
@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	key := map[string]types.AttributeValue{
+		"id":        &types.AttributeValueMemberS{Value: "c1"},
+		"alias":     &types.AttributeValueMemberS{Value: "promo"},
+		"createdAt": &types.AttributeValueMemberS{Value: "2025-01-02T03:04:05Z"},
+	}
+
+	cursor, err := encodeCursor(key)
+	if err != nil || cursor == "" {
+		t.Fatalf("encode: cursor=%q err=%v", cursor, err)
+	}
+
+	got, err := decodeCursor(cursor)
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	for k, v := range key {
+		want := v.(*types.AttributeValueMemberS).Value
+		s, ok := got[k].(*types.AttributeValueMemberS)
+		if !ok || s.Value != want {
+			t.Errorf("key %s mismatch: got %#v, want %q", k, got[k], want)
+		}
+	}
+}
+
+func TestCursorEmptyAndInvalid(t *testing.T) {
+	if c, _ := encodeCursor(nil); c != "" {
+		t.Errorf("last page should have empty cursor, got %q", c)
+	}
+	if k, err := decodeCursor(""); k != nil || err != nil {
+		t.Errorf("empty cursor should start from beginning, got %v %v", k, err)
+	}
+	if _, err := decodeCursor("not-a-cursor!"); !errors.Is(err, ErrInvalidCursor) {
+		t.Errorf("expected ErrInvalidCursor, got %v", err)
+	}
+}
+
+func TestFeedCursor(t *testing.T) {
+	shards, err := decodeFeedCursor("")
+	if err != nil || len(shards) != clickFeedShards {
+		t.Fatalf("empty cursor should start all shards: %v %v", shards, err)
+	}
+
+	shards[0].Start = map[string]string{"id": "c1", "feed": clickFeed(0), "createdAt": "2025-01-02T03:04:05Z"}
+	shards[1].Done = true
+	cursor, err := encodeFeedCursor(shards)
+	if err != nil || cursor == "" {
+		t.Fatalf("encode: cursor=%q err=%v", cursor, err)
+	}
+	got, err := decodeFeedCursor(cursor)
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if got[0].Start["id"] != "c1" || !got[1].Done || got[2].Done || got[2].Start != nil {
+		t.Errorf("round trip mismatch: %+v", got)
+	}
+
+	// Semua shard habis → halaman terakhir
+	for i := range shards {
+		shards[i].Done = true
+	}
+	if c, _ := encodeFeedCursor(shards); c != "" {
+		t.Errorf("last page should have empty cursor, got %q", c)
+	}
+
+	// Cursor Scan lama / jumlah shard beda ditolak
+	scanCursor, _ := encodeCursor(map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "c1"}})
+	if _, err := decodeFeedCursor(scanCursor); !errors.Is(err, ErrInvalidCursor) {
+		t.Errorf("expected ErrInvalidCursor, got %v", err)
+	}
+}
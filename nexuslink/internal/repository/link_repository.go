@@ -2,11 +2,15 @@ package repository
 
 import (
 	"context"
+	"errors"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 	"github.com/google/uuid"
 
 	"github.com/afuzapratama/nexuslink/internal/database"
@@ -33,56 +37,297 @@ func (r *LinkRepository) Create(ctx context.Context, link *models.Link) error {
 		link.CreatedAt = now
 	}
 	link.UpdatedAt = now
+	link.Version = now.UnixNano()
+	// Link baru selalu pakai key dari (domain, alias) sekarang; Key dari Create yang gagal
+	// (mis. retry generate alias setelah ErrAliasTaken) tidak boleh terbawa
+	link.Key = models.LinkKey(link.Domain, link.Alias)
 
 	if !link.IsActive {
 		link.IsActive = true
 	}
 
-	item, err := attributevalue.MarshalMap(link)
+	item, err := marshalLink(link)
 	if err != nil {
 		return err
 	}
 
-	_, err = r.db.PutItem(ctx, &dynamodb.PutItemInput{
-		TableName: aws.String(database.LinksTableName),
-		Item:      item,
-	})
-
+	// Reservasi alias + link ditulis atomic: (domain, alias) yang sudah dipakai → ErrAliasTaken
+	reservations := AliasReservations(link)
+	items := make([]types.TransactWriteItem, 0, len(reservations)+1)
+	for _, key := range reservations {
+		items = append(items, types.TransactWriteItem{Put: &types.Put{
+			TableName:           aws.String(database.LinkAliasesTableName),
+			Item:                aliasItem(key, link.ID),
+			ConditionExpression: aws.String("attribute_not_exists(aliasKey)"),
+		}})
+	}
+	items = append(items, types.TransactWriteItem{Put: &types.Put{
+		TableName:           aws.String(database.LinksTableName),
+		Item:                item,
+		ConditionExpression: aws.String("attribute_not_exists(id)"),
+	}})
+
+	_, err = r.db.TransactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{TransactItems: items})
+	var tce *types.TransactionCanceledException
+	if errors.As(err, &tce) {
+		for i, reason := range tce.CancellationReasons {
+			if i < len(reservations) && aws.ToString(reason.Code) == "ConditionalCheckFailed" {
+				return ErrAliasTaken
+			}
+		}
+	}
 	return err
 }
 
-func (r *LinkRepository) List(ctx context.Context) ([]models.Link, error) {
-	out, err := r.db.Scan(ctx, &dynamodb.ScanInput{
-		TableName: aws.String(database.LinksTableName),
-	})
+// linkFeed: nilai atribut feed semua link, partition key feed-createdAt-index untuk list semua link
+const linkFeed = "link"
+
+// marshalLink menulis link beserta atribut feed dan aliasLower (dipakai semua write ke tabel links)
+func marshalLink(link *models.Link) (map[string]types.AttributeValue, error) {
+	item, err := attributevalue.MarshalMap(link)
 	if err != nil {
 		return nil, err
 	}
+	item["feed"] = &types.AttributeValueMemberS{Value: linkFeed}
+	item["aliasLower"] = &types.AttributeValueMemberS{Value: strings.ToLower(link.Alias)}
+	return item, nil
+}
 
-	var links []models.Link
-	if err := attributevalue.UnmarshalListOfMaps(out.Items, &links); err != nil {
-		return nil, err
+// linkFeedInput membangun Query ke feed-createdAt-index (newest first)
+func linkFeedInput() *dynamodb.QueryInput {
+	return &dynamodb.QueryInput{
+		TableName:              aws.String(database.LinksTableName),
+		IndexName:              aws.String(database.LinksFeedIndex),
+		KeyConditionExpression: aws.String("#f = :feed"),
+		ExpressionAttributeNames: map[string]string{
+			"#f": "feed",
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":feed": &types.AttributeValueMemberS{Value: linkFeed},
+		},
+		ScanIndexForward: aws.Bool(false),
 	}
+}
 
-	return links, nil
+// ErrAliasTaken: alias sudah dipakai link lain di domain yang sama (perbandingan case-insensitive)
+var ErrAliasTaken = errors.New("alias already taken")
+
+// aliasSequencePrefix: item counter sequential di tabel alias ("#" tidak valid di alias)
+const aliasSequencePrefix = "#seq:"
+
+// AliasReservations returns kunci reservasi link: (domain, alias) dan key data-nya.
+// Link baru keduanya sama; link lama ber-domain memegang alias polos sebagai key data.
+func AliasReservations(link *models.Link) []string {
+	keys := []string{link.ScopeKey()}
+	if k := strings.ToLower(link.DataKey()); k != keys[0] {
+		keys = append(keys, k)
+	}
+	return keys
 }
 
-// ListPaginated returns paginated links
-func (r *LinkRepository) ListPaginated(ctx context.Context, page, limit int) ([]models.Link, int, error) {
-	// Get all links first (for total count)
-	out, err := r.db.Scan(ctx, &dynamodb.ScanInput{
-		TableName: aws.String(database.LinksTableName),
+// LinkForDomain returns link (dari hasil query alias) yang Domain-nya persis domain
+func LinkForDomain(links []models.Link, domain string) *models.Link {
+	domain = strings.TrimSpace(domain)
+	for i := range links {
+		if strings.EqualFold(strings.TrimSpace(links[i].Domain), domain) {
+			link := links[i]
+			return &link
+		}
+	}
+	return nil
+}
+
+// LinkForKey returns link (dari hasil query alias) dengan DataKey = key (case-insensitive seperti ScopeKey)
+func LinkForKey(links []models.Link, key string) *models.Link {
+	key = models.LinkKey(models.SplitLinkKey(key))
+	for i := range links {
+		if strings.EqualFold(links[i].DataKey(), key) {
+			link := links[i]
+			return &link
+		}
+	}
+	return nil
+}
+
+func aliasItem(key, linkID string) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{
+		"aliasKey": &types.AttributeValueMemberS{Value: key},
+		"linkId":   &types.AttributeValueMemberS{Value: linkID},
+	}
+}
+
+// NextAliasSequence menaikkan counter per scope (ADD atomic)
+func (r *LinkRepository) NextAliasSequence(ctx context.Context, scope string) (int64, error) {
+	out, err := r.db.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName: aws.String(database.LinkAliasesTableName),
+		Key: map[string]types.AttributeValue{
+			"aliasKey": &types.AttributeValueMemberS{Value: aliasSequencePrefix + strings.ToLower(scope)},
+		},
+		UpdateExpression: aws.String("ADD seq :one"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":one": &types.AttributeValueMemberN{Value: "1"},
+		},
+		ReturnValues: types.ReturnValueUpdatedNew,
 	})
 	if err != nil {
-		return nil, 0, err
+		return 0, err
 	}
 
-	var allLinks []models.Link
-	if err := attributevalue.UnmarshalListOfMaps(out.Items, &allLinks); err != nil {
-		return nil, 0, err
+	var res struct {
+		Seq int64 `dynamodbav:"seq"`
 	}
+	if err := attributevalue.UnmarshalMap(out.Attributes, &res); err != nil {
+		return 0, err
+	}
+	return res.Seq, nil
+}
 
-	total := len(allLinks)
+// BackfillAliases membuat reservasi alias untuk link lama (sebelum tabel alias / alias per domain ada)
+// dan mengisi aliasLower untuk link yang ditulis sebelum aliasLower-index ada.
+// Aman dijalankan berulang; alias lama yang bentrok case-insensitive dibiarkan (yang pertama menang).
+func (r *LinkRepository) BackfillAliases(ctx context.Context) error {
+	if err := r.backfillAliasLower(ctx); err != nil {
+		return err
+	}
+
+	links, err := r.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	for i := range links {
+		for _, key := range AliasReservations(&links[i]) {
+			_, err := r.db.PutItem(ctx, &dynamodb.PutItemInput{
+				TableName:           aws.String(database.LinkAliasesTableName),
+				Item:                aliasItem(key, links[i].ID),
+				ConditionExpression: aws.String("attribute_not_exists(aliasKey)"),
+			})
+			var ccfe *types.ConditionalCheckFailedException
+			if err != nil && !errors.As(err, &ccfe) {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (r *LinkRepository) backfillAliasLower(ctx context.Context) error {
+	paginator := dynamodb.NewScanPaginator(r.db, &dynamodb.ScanInput{
+		TableName:                aws.String(database.LinksTableName),
+		FilterExpression:         aws.String("attribute_not_exists(aliasLower)"),
+		ProjectionExpression:     aws.String("id, #a"),
+		ExpressionAttributeNames: map[string]string{"#a": "alias"},
+	})
+	for paginator.HasMorePages() {
+		out, err := paginator.NextPage(ctx)
+		if err != nil {
+			return err
+		}
+		for _, item := range out.Items {
+			alias, _ := item["alias"].(*types.AttributeValueMemberS)
+			if alias == nil {
+				continue
+			}
+			_, err := r.db.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+				TableName:           aws.String(database.LinksTableName),
+				Key:                 map[string]types.AttributeValue{"id": item["id"]},
+				UpdateExpression:    aws.String("SET aliasLower = :lower"),
+				ConditionExpression: aws.String("attribute_exists(id) AND #a = :alias"),
+				ExpressionAttributeNames: map[string]string{
+					"#a": "alias",
+				},
+				ExpressionAttributeValues: map[string]types.AttributeValue{
+					":lower": &types.AttributeValueMemberS{Value: strings.ToLower(alias.Value)},
+					":alias": alias,
+				},
+			})
+			var ccfe *types.ConditionalCheckFailedException
+			if err != nil && !errors.As(err, &ccfe) {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// BackfillFeed mengisi atribut feed untuk link dan tombstone yang ditulis sebelum
+// feed-createdAt-index / feed-version-index ada. Aman dijalankan berulang (hanya item tanpa feed yang diubah).
+func (r *LinkRepository) BackfillFeed(ctx context.Context) error {
+	for _, table := range []string{database.LinksTableName, database.LinkTombstonesTableName} {
+		if err := r.backfillFeed(ctx, table); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *LinkRepository) backfillFeed(ctx context.Context, table string) error {
+	paginator := dynamodb.NewScanPaginator(r.db, &dynamodb.ScanInput{
+		TableName:                aws.String(table),
+		FilterExpression:         aws.String("attribute_not_exists(#f)"),
+		ProjectionExpression:     aws.String("id"),
+		ExpressionAttributeNames: map[string]string{"#f": "feed"},
+	})
+	for paginator.HasMorePages() {
+		out, err := paginator.NextPage(ctx)
+		if err != nil {
+			return err
+		}
+		for _, item := range out.Items {
+			_, err := r.db.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+				TableName:                aws.String(table),
+				Key:                      map[string]types.AttributeValue{"id": item["id"]},
+				UpdateExpression:         aws.String("SET #f = :feed"),
+				ConditionExpression:      aws.String("attribute_exists(id)"),
+				ExpressionAttributeNames: map[string]string{"#f": "feed"},
+				ExpressionAttributeValues: map[string]types.AttributeValue{
+					":feed": &types.AttributeValueMemberS{Value: linkFeed},
+				},
+			})
+			var ccfe *types.ConditionalCheckFailedException
+			if err != nil && !errors.As(err, &ccfe) {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (r *LinkRepository) List(ctx context.Context) ([]models.Link, error) {
+	var links []models.Link
+	paginator := dynamodb.NewScanPaginator(r.db, &dynamodb.ScanInput{
+		TableName: aws.String(database.LinksTableName),
+	})
+	for paginator.HasMorePages() {
+		out, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		var page []models.Link
+		if err := attributevalue.UnmarshalListOfMaps(out.Items, &page); err != nil {
+			return nil, err
+		}
+		links = append(links, page...)
+	}
+
+	return links, nil
+}
+
+// ListPaginated returns paginated links terbaru dulu (offset mode, dipakai dashboard yang butuh total)
+func (r *LinkRepository) ListPaginated(ctx context.Context, page, limit int) ([]models.Link, int, error) {
+	// Total dihitung lewat Select COUNT di index (tanpa baca item)
+	total := 0
+	countInput := linkFeedInput()
+	countInput.Select = types.SelectCount
+	paginator := dynamodb.NewQueryPaginator(r.db, countInput)
+	for paginator.HasMorePages() {
+		out, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, 0, err
+		}
+		total += int(out.Count)
+	}
 
 	// Calculate offset
 	offset := (page - 1) * limit
@@ -90,50 +335,90 @@ func (r *LinkRepository) ListPaginated(ctx context.Context, page, limit int) ([]
 		offset = 0
 	}
 
-	// Slice for pagination
 	if offset >= total {
 		return []models.Link{}, total, nil
 	}
 
-	end := offset + limit
-	if end > total {
-		end = total
+	// Query berurutan sampai offset+limit item terkumpul
+	var links []models.Link
+	input := linkFeedInput()
+	for len(links) < offset+limit {
+		input.Limit = aws.Int32(int32(offset + limit - len(links)))
+
+		out, err := r.db.Query(ctx, input)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		var batch []models.Link
+		if err := attributevalue.UnmarshalListOfMaps(out.Items, &batch); err != nil {
+			return nil, 0, err
+		}
+		links = append(links, batch...)
+
+		if len(out.LastEvaluatedKey) == 0 {
+			break
+		}
+		input.ExclusiveStartKey = out.LastEvaluatedKey
 	}
 
-	return allLinks[offset:end], total, nil
+	if offset >= len(links) {
+		return []models.Link{}, total, nil
+	}
+	return links[offset:], total, nil
 }
 
-// Tambahan: cari link by alias (versi simpel)
-func (r *LinkRepository) FindByAlias(ctx context.Context, alias string) (*models.Link, error) {
-	// Untuk tahap awal, kita scan semua lalu filter di Go.
-	// Nanti kalau sudah serius & besar, baru pakai GSI di DynamoDB.
-	out, err := r.db.Scan(ctx, &dynamodb.ScanInput{
-		TableName: aws.String(database.LinksTableName),
-	})
+// ListPage returns satu halaman link (terbaru dulu) mulai dari cursor (kosong = awal).
+// groupID opsional; nextCursor kosong berarti sudah halaman terakhir.
+func (r *LinkRepository) ListPage(ctx context.Context, groupID string, limit int, cursor string) ([]models.Link, string, error) {
+	startKey, err := decodeCursor(cursor)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 
-	var links []models.Link
-	if err := attributevalue.UnmarshalListOfMaps(out.Items, &links); err != nil {
-		return nil, err
+	input := linkFeedInput()
+	input.ExclusiveStartKey = startKey
+	if groupID != "" {
+		input.FilterExpression = aws.String("groupId = :g")
+		input.ExpressionAttributeValues[":g"] = &types.AttributeValueMemberS{Value: groupID}
 	}
 
-	for _, l := range links {
-		if l.Alias == alias && l.IsActive {
-			// return copy
-			link := l
-			return &link, nil
+	// Dengan filter, satu Query bisa kembali kosong padahal masih ada data → lanjut sampai limit terpenuhi
+	links := make([]models.Link, 0, limit)
+	for {
+		input.Limit = aws.Int32(int32(limit - len(links)))
+
+		out, err := r.db.Query(ctx, input)
+		if err != nil {
+			return nil, "", err
 		}
-	}
 
-	return nil, nil // not found, bukan error
+		var page []models.Link
+		if err := attributevalue.UnmarshalListOfMaps(out.Items, &page); err != nil {
+			return nil, "", err
+		}
+		links = append(links, page...)
+
+		if len(out.LastEvaluatedKey) == 0 {
+			return links, "", nil
+		}
+		if len(links) >= limit {
+			next, err := encodeCursor(out.LastEvaluatedKey)
+			return links, next, err
+		}
+		input.ExclusiveStartKey = out.LastEvaluatedKey
+	}
 }
 
-func (r *LinkRepository) GetByAlias(ctx context.Context, alias string) (*models.Link, error) {
-	// Pakai scan semua + filter di aplikasi (lebih reliable untuk debug)
-	out, err := r.db.Scan(ctx, &dynamodb.ScanInput{
-		TableName: aws.String(database.LinksTableName),
+// queryByAlias membaca link dari aliasLower-index (case-insensitive, sama dengan reservasi alias)
+func (r *LinkRepository) queryByAlias(ctx context.Context, alias string) ([]models.Link, error) {
+	out, err := r.db.Query(ctx, &dynamodb.QueryInput{
+		TableName:              aws.String(database.LinksTableName),
+		IndexName:              aws.String(database.LinksAliasLowerIndex),
+		KeyConditionExpression: aws.String("aliasLower = :alias"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":alias": &types.AttributeValueMemberS{Value: strings.ToLower(alias)},
+		},
 	})
 	if err != nil {
 		return nil, err
@@ -143,22 +428,47 @@ func (r *LinkRepository) GetByAlias(ctx context.Context, alias string) (*models.
 	if err := attributevalue.UnmarshalListOfMaps(out.Items, &links); err != nil {
 		return nil, err
 	}
+	return links, nil
+}
 
-	// Filter di aplikasi: cari alias yang match (TIDAK filter isActive untuk bulk operations)
-	for _, l := range links {
-		if l.Alias == alias {
-			link := l
-			return &link, nil
-		}
+// ListByAlias returns semua link dengan alias tersebut (satu per domain)
+func (r *LinkRepository) ListByAlias(ctx context.Context, alias string) ([]models.Link, error) {
+	return r.queryByAlias(ctx, alias)
+}
+
+// FindByAlias returns link yang paling cocok untuk request (domain, alias); nil kalau alias tidak ada
+func (r *LinkRepository) FindByAlias(ctx context.Context, domain, alias string) (*models.Link, error) {
+	links, err := r.queryByAlias(ctx, alias)
+	if err != nil {
+		return nil, err
+	}
+	return models.PickForDomain(links, domain), nil
+}
+
+// GetByAlias returns link dengan alias di domain tersebut (TIDAK filter isActive untuk bulk operations)
+func (r *LinkRepository) GetByAlias(ctx context.Context, domain, alias string) (*models.Link, error) {
+	links, err := r.queryByAlias(ctx, alias)
+	if err != nil {
+		return nil, err
 	}
+	return LinkForDomain(links, domain), nil
+}
 
-	return nil, nil // not found
+// GetByKey returns link dengan DataKey = key (alias diambil dari key lalu query aliasLower-index)
+func (r *LinkRepository) GetByKey(ctx context.Context, key string) (*models.Link, error) {
+	_, alias := models.SplitLinkKey(key)
+	links, err := r.queryByAlias(ctx, alias)
+	if err != nil {
+		return nil, err
+	}
+	return LinkForKey(links, key), nil
 }
 
 func (r *LinkRepository) Update(ctx context.Context, link *models.Link) error {
 	link.UpdatedAt = time.Now().UTC()
+	link.Version = link.UpdatedAt.UnixNano()
 
-	item, err := attributevalue.MarshalMap(link)
+	item, err := marshalLink(link)
 	if err != nil {
 		return err
 	}
@@ -179,10 +489,127 @@ func (r *LinkRepository) Delete(ctx context.Context, id string) error {
 		return err
 	}
 
-	_, err = r.db.DeleteItem(ctx, &dynamodb.DeleteItemInput{
-		TableName: aws.String(database.LinksTableName),
-		Key:       key,
+	out, err := r.db.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName:    aws.String(database.LinksTableName),
+		Key:          key,
+		ReturnValues: types.ReturnValueAllOld,
+	})
+	if err != nil {
+		return err
+	}
+
+	// Catat tombstone supaya agent yang pakai delta feed ikut menghapus link ini
+	var old models.Link
+	if out.Attributes != nil {
+		if err := attributevalue.UnmarshalMap(out.Attributes, &old); err != nil {
+			return err
+		}
+	}
+
+	// Lepas reservasi alias (hanya kalau masih milik link ini)
+	if old.Alias != "" {
+		for _, key := range AliasReservations(&old) {
+			_, err := r.db.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+				TableName: aws.String(database.LinkAliasesTableName),
+				Key: map[string]types.AttributeValue{
+					"aliasKey": &types.AttributeValueMemberS{Value: key},
+				},
+				ConditionExpression: aws.String("linkId = :id"),
+				ExpressionAttributeValues: map[string]types.AttributeValue{
+					":id": &types.AttributeValueMemberS{Value: id},
+				},
+			})
+			var ccfe *types.ConditionalCheckFailedException
+			if err != nil && !errors.As(err, &ccfe) {
+				return err
+			}
+		}
+	}
+
+	now := time.Now().UTC()
+	tombstone := models.LinkTombstone{
+		ID:        id,
+		Alias:     old.Alias,
+		Version:   now.UnixNano(),
+		ExpiresAt: now.Add(TombstoneRetention).Unix(),
+	}
+
+	item, err := attributevalue.MarshalMap(tombstone)
+	if err != nil {
+		return err
+	}
+	item["feed"] = &types.AttributeValueMemberS{Value: linkFeed}
+
+	_, err = r.db.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(database.LinkTombstonesTableName),
+		Item:      item,
 	})
 
 	return err
 }
+
+// TombstoneRetention adalah berapa lama tombstone disimpan.
+// Agent yang ketinggalan lebih lama dari ini harus ambil snapshot penuh.
+const TombstoneRetention = 7 * 24 * time.Hour
+
+// versionFeedInput: Query feed-version-index (links / tombstones) untuk item dengan version > since
+func versionFeedInput(table, index string, since int64) *dynamodb.QueryInput {
+	return &dynamodb.QueryInput{
+		TableName:              aws.String(table),
+		IndexName:              aws.String(index),
+		KeyConditionExpression: aws.String("#f = :feed AND #v > :since"),
+		ExpressionAttributeNames: map[string]string{
+			"#f": "feed",
+			"#v": "version",
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":feed":  &types.AttributeValueMemberS{Value: linkFeed},
+			":since": &types.AttributeValueMemberN{Value: strconv.FormatInt(since, 10)},
+		},
+	}
+}
+
+// ListChangedSince returns links whose version is greater than since (since=0 → semua link).
+// Delta (since > 0) lewat feed-version-index, jadi biayanya sebanding jumlah link yang berubah.
+func (r *LinkRepository) ListChangedSince(ctx context.Context, since int64) ([]models.Link, error) {
+	if since <= 0 {
+		return r.List(ctx)
+	}
+
+	var links []models.Link
+	paginator := dynamodb.NewQueryPaginator(r.db, versionFeedInput(database.LinksTableName, database.LinksVersionIndex, since))
+	for paginator.HasMorePages() {
+		out, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		var page []models.Link
+		if err := attributevalue.UnmarshalListOfMaps(out.Items, &page); err != nil {
+			return nil, err
+		}
+		links = append(links, page...)
+	}
+
+	return links, nil
+}
+
+// ListDeletedSince returns tombstones of links deleted after since
+func (r *LinkRepository) ListDeletedSince(ctx context.Context, since int64) ([]models.LinkTombstone, error) {
+	var tombstones []models.LinkTombstone
+	paginator := dynamodb.NewQueryPaginator(r.db, versionFeedInput(database.LinkTombstonesTableName, database.TombstonesVersionIndex, since))
+	for paginator.HasMorePages() {
+		out, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		var page []models.LinkTombstone
+		if err := attributevalue.UnmarshalListOfMaps(out.Items, &page); err != nil {
+			return nil, err
+		}
+		tombstones = append(tombstones, page...)
+	}
+
+	return tombstones, nil
+}
@@ -0,0 +1,154 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/afuzapratama/nexuslink/internal/database"
+	"github.com/afuzapratama/nexuslink/internal/models"
+)
+
+// ErrRevisionConflict: link sudah diubah request lain sejak dibaca (optimistic locking)
+var ErrRevisionConflict = errors.New("link was modified by another request")
+
+type LinkRevisionRepository struct {
+	db *dynamodb.Client
+}
+
+func NewLinkRevisionRepository() *LinkRevisionRepository {
+	return &LinkRevisionRepository{
+		db: database.Client(),
+	}
+}
+
+// Commit pakai TransactWriteItems: snapshot + link (+ variant) tersimpan semua atau tidak sama sekali
+func (r *LinkRevisionRepository) Commit(ctx context.Context, link *models.Link, prev *models.LinkRevision, variants []models.LinkVariant) error {
+	link.UpdatedAt = time.Now().UTC()
+	link.Version = link.UpdatedAt.UnixNano()
+	link.Revision = prev.Revision + 1
+
+	revItem, err := attributevalue.MarshalMap(prev)
+	if err != nil {
+		return err
+	}
+	linkItem, err := marshalLink(link)
+	if err != nil {
+		return err
+	}
+
+	// Link lama belum punya atribut revision (dianggap revision 1)
+	cond := "revision = :rev"
+	if prev.Revision == 1 {
+		cond = "attribute_not_exists(revision) OR revision = :rev"
+	}
+
+	items := []types.TransactWriteItem{
+		{Put: &types.Put{
+			TableName:           aws.String(database.LinkRevisionsTableName),
+			Item:                revItem,
+			ConditionExpression: aws.String("attribute_not_exists(revision)"),
+		}},
+		{Put: &types.Put{
+			TableName:           aws.String(database.LinksTableName),
+			Item:                linkItem,
+			ConditionExpression: aws.String("attribute_exists(id) AND (" + cond + ")"),
+			ExpressionAttributeValues: map[string]types.AttributeValue{
+				":rev": &types.AttributeValueMemberN{Value: strconv.Itoa(prev.Revision)},
+			},
+		}},
+	}
+
+	if variants != nil {
+		variantItems, err := NewLinkVariantRepository(r.db).configWrites(ctx, link.DataKey(), variants)
+		if err != nil {
+			return err
+		}
+		items = append(items, variantItems...)
+	}
+
+	_, err = r.db.TransactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{TransactItems: items})
+	var tce *types.TransactionCanceledException
+	if errors.As(err, &tce) {
+		for _, reason := range tce.CancellationReasons {
+			if aws.ToString(reason.Code) == "ConditionalCheckFailed" {
+				return ErrRevisionConflict
+			}
+		}
+	}
+	return err
+}
+
+// List returns semua snapshot link, revision terbaru dulu
+func (r *LinkRevisionRepository) List(ctx context.Context, linkID string) ([]models.LinkRevision, error) {
+	var revisions []models.LinkRevision
+	paginator := dynamodb.NewQueryPaginator(r.db, &dynamodb.QueryInput{
+		TableName:              aws.String(database.LinkRevisionsTableName),
+		KeyConditionExpression: aws.String("linkId = :id"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":id": &types.AttributeValueMemberS{Value: linkID},
+		},
+		ScanIndexForward: aws.Bool(false),
+	})
+	for paginator.HasMorePages() {
+		out, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		var page []models.LinkRevision
+		if err := attributevalue.UnmarshalListOfMaps(out.Items, &page); err != nil {
+			return nil, err
+		}
+		revisions = append(revisions, page...)
+	}
+	return revisions, nil
+}
+
+func (r *LinkRevisionRepository) Get(ctx context.Context, linkID string, revision int) (*models.LinkRevision, error) {
+	out, err := r.db.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(database.LinkRevisionsTableName),
+		Key: map[string]types.AttributeValue{
+			"linkId":   &types.AttributeValueMemberS{Value: linkID},
+			"revision": &types.AttributeValueMemberN{Value: strconv.Itoa(revision)},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if out.Item == nil {
+		return nil, nil
+	}
+
+	var rev models.LinkRevision
+	if err := attributevalue.UnmarshalMap(out.Item, &rev); err != nil {
+		return nil, err
+	}
+	return &rev, nil
+}
+
+// DeleteByLink menghapus semua snapshot (dipanggil saat link dihapus)
+func (r *LinkRevisionRepository) DeleteByLink(ctx context.Context, linkID string) error {
+	revisions, err := r.List(ctx, linkID)
+	if err != nil {
+		return err
+	}
+	for _, rev := range revisions {
+		_, err := r.db.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+			TableName: aws.String(database.LinkRevisionsTableName),
+			Key: map[string]types.AttributeValue{
+				"linkId":   &types.AttributeValueMemberS{Value: linkID},
+				"revision": &types.AttributeValueMemberN{Value: strconv.Itoa(rev.Revision)},
+			},
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -2,6 +2,8 @@ package repository
 
 import (
 	"context"
+	"strconv"
+	"sync"
 	"time"
 
 	"github.com/afuzapratama/nexuslink/internal/models"
@@ -46,6 +48,79 @@ func (r *LinkVariantRepository) GetByLinkID(ctx context.Context, linkID string)
 	return variants, nil
 }
 
+// variantScanThreshold: mulai jumlah link ini, GetByLinkIDs membaca seluruh tabel variants dengan satu
+// Scan (tabel konfigurasi, kecil) daripada satu Query per link
+const variantScanThreshold = 100
+
+// variantQueryConcurrency membatasi Query paralel di GetByLinkIDs
+const variantQueryConcurrency = 8
+
+// GetByLinkIDs returns variants untuk banyak link sekaligus (delta / snapshot sync agent)
+func (r *LinkVariantRepository) GetByLinkIDs(ctx context.Context, linkIDs []string) (map[string][]models.LinkVariant, error) {
+	result := make(map[string][]models.LinkVariant)
+	if len(linkIDs) == 0 {
+		return result, nil
+	}
+
+	if len(linkIDs) >= variantScanThreshold {
+		wanted := make(map[string]bool, len(linkIDs))
+		for _, id := range linkIDs {
+			wanted[id] = true
+		}
+
+		paginator := dynamodb.NewScanPaginator(r.client, &dynamodb.ScanInput{TableName: aws.String(r.table)})
+		for paginator.HasMorePages() {
+			out, err := paginator.NextPage(ctx)
+			if err != nil {
+				return nil, err
+			}
+			var page []models.LinkVariant
+			if err := attributevalue.UnmarshalListOfMaps(out.Items, &page); err != nil {
+				return nil, err
+			}
+			for _, v := range page {
+				if wanted[v.LinkID] {
+					result[v.LinkID] = append(result[v.LinkID], v)
+				}
+			}
+		}
+		return result, nil
+	}
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+	)
+	sem := make(chan struct{}, variantQueryConcurrency)
+	for _, id := range linkIDs {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(id string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			variants, err := r.GetByLinkID(ctx, id)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				if firstErr == nil {
+					firstErr = err
+				}
+				return
+			}
+			if len(variants) > 0 {
+				result[id] = variants
+			}
+		}(id)
+	}
+	wg.Wait()
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	return result, nil
+}
+
 // GetByID retrieves a specific variant by linkID and variantID
 func (r *LinkVariantRepository) GetByID(ctx context.Context, linkID, variantID string) (*models.LinkVariant, error) {
 	input := &dynamodb.GetItemInput{
@@ -163,3 +238,66 @@ func (r *LinkVariantRepository) IncrementConversions(ctx context.Context, linkID
 	_, err := r.client.UpdateItem(ctx, input)
 	return err
 }
+
+// configWrites returns item transaksi untuk mengganti konfigurasi variant link: upsert konfigurasi variant (counter dipertahankan) + hapus variant yang tidak ada di snapshot
+func (r *LinkVariantRepository) configWrites(ctx context.Context, alias string, variants []models.LinkVariant) ([]types.TransactWriteItem, error) {
+	existing, err := r.GetByLinkID(ctx, alias)
+	if err != nil {
+		return nil, err
+	}
+
+	now, err := attributevalue.Marshal(time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	var items []types.TransactWriteItem
+	keep := make(map[string]bool)
+	for _, v := range variants {
+		keep[v.ID] = true
+		createdAt, err := attributevalue.Marshal(v.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, types.TransactWriteItem{Update: &types.Update{
+			TableName: aws.String(r.table),
+			Key: map[string]types.AttributeValue{
+				"linkId": &types.AttributeValueMemberS{Value: alias},
+				"id":     &types.AttributeValueMemberS{Value: v.ID},
+			},
+			UpdateExpression: aws.String("SET #target = :target, #weight = :weight, #label = :label, #updatedAt = :now, " +
+				"#createdAt = if_not_exists(#createdAt, :createdAt), #clicks = if_not_exists(#clicks, :zero), " +
+				"#conversions = if_not_exists(#conversions, :zero)"),
+			ExpressionAttributeNames: map[string]string{
+				"#target":      "targetUrl",
+				"#weight":      "weight",
+				"#label":       "label",
+				"#updatedAt":   "updatedAt",
+				"#createdAt":   "createdAt",
+				"#clicks":      "clicks",
+				"#conversions": "conversions",
+			},
+			ExpressionAttributeValues: map[string]types.AttributeValue{
+				":target":    &types.AttributeValueMemberS{Value: v.TargetURL},
+				":weight":    &types.AttributeValueMemberN{Value: strconv.Itoa(v.Weight)},
+				":label":     &types.AttributeValueMemberS{Value: v.Label},
+				":now":       now,
+				":createdAt": createdAt,
+				":zero":      &types.AttributeValueMemberN{Value: "0"},
+			},
+		}})
+	}
+	for _, v := range existing {
+		if keep[v.ID] {
+			continue
+		}
+		items = append(items, types.TransactWriteItem{Delete: &types.Delete{
+			TableName: aws.String(r.table),
+			Key: map[string]types.AttributeValue{
+				"linkId": &types.AttributeValueMemberS{Value: alias},
+				"id":     &types.AttributeValueMemberS{Value: v.ID},
+			},
+		}})
+	}
+	return items, nil
+}
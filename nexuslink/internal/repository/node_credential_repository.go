@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/afuzapratama/nexuslink/internal/database"
+	"github.com/afuzapratama/nexuslink/internal/models"
+)
+
+type NodeCredentialRepository struct {
+	db *dynamodb.Client
+}
+
+func NewNodeCredentialRepository() *NodeCredentialRepository {
+	return &NodeCredentialRepository{
+		db: database.Client(),
+	}
+}
+
+func (r *NodeCredentialRepository) Get(ctx context.Context, nodeID string) (*models.NodeCredential, error) {
+	out, err := r.db.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(database.NodeCredentialsTableName),
+		Key: map[string]types.AttributeValue{
+			"nodeId": &types.AttributeValueMemberS{Value: nodeID},
+		},
+		ConsistentRead: aws.Bool(true),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if out.Item == nil {
+		return nil, nil
+	}
+
+	var cred models.NodeCredential
+	if err := attributevalue.UnmarshalMap(out.Item, &cred); err != nil {
+		return nil, err
+	}
+	return &cred, nil
+}
+
+func (r *NodeCredentialRepository) Put(ctx context.Context, cred *models.NodeCredential) error {
+	item, err := attributevalue.MarshalMap(cred)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(database.NodeCredentialsTableName),
+		Item:      item,
+	})
+	return err
+}
+
+func (r *NodeCredentialRepository) Delete(ctx context.Context, nodeID string) error {
+	_, err := r.db.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName: aws.String(database.NodeCredentialsTableName),
+		Key: map[string]types.AttributeValue{
+			"nodeId": &types.AttributeValueMemberS{Value: nodeID},
+		},
+	})
+	return err
+}
@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/afuzapratama/nexuslink/internal/database"
+	"github.com/afuzapratama/nexuslink/internal/models"
+)
+
+type NodeEventRepository struct {
+	db *dynamodb.Client
+}
+
+func NewNodeEventRepository() *NodeEventRepository {
+	return &NodeEventRepository{
+		db: database.Client(),
+	}
+}
+
+// nodeEventItem adalah item di tabel NodeEvents (nodeId + ts unix nano sebagai sort key)
+type nodeEventItem struct {
+	NodeID     string    `dynamodbav:"nodeId"`
+	TS         int64     `dynamodbav:"ts"`
+	Type       string    `dynamodbav:"type"`
+	LastSeenAt time.Time `dynamodbav:"lastSeenAt"`
+	ExpiresAt  int64     `dynamodbav:"expiresAt"` // TTL
+}
+
+func (it nodeEventItem) event() models.NodeEvent {
+	return models.NodeEvent{
+		NodeID:     it.NodeID,
+		Type:       it.Type,
+		At:         time.Unix(0, it.TS).UTC(),
+		LastSeenAt: it.LastSeenAt,
+	}
+}
+
+// Add menyimpan satu transisi node
+func (r *NodeEventRepository) Add(ctx context.Context, ev *models.NodeEvent) error {
+	item, err := attributevalue.MarshalMap(nodeEventItem{
+		NodeID:     ev.NodeID,
+		TS:         ev.At.UnixNano(),
+		Type:       ev.Type,
+		LastSeenAt: ev.LastSeenAt,
+		ExpiresAt:  ev.At.Add(models.NodeEventRetention).Unix(),
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(database.NodeEventsTableName),
+		Item:      item,
+	})
+	return err
+}
+
+// ListSince returns event node sejak waktu tertentu, urut dari yang terlama
+func (r *NodeEventRepository) ListSince(ctx context.Context, nodeID string, since time.Time) ([]models.NodeEvent, error) {
+	var events []models.NodeEvent
+	paginator := dynamodb.NewQueryPaginator(r.db, &dynamodb.QueryInput{
+		TableName:              aws.String(database.NodeEventsTableName),
+		KeyConditionExpression: aws.String("nodeId = :n AND ts >= :since"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":n":     &types.AttributeValueMemberS{Value: nodeID},
+			":since": &types.AttributeValueMemberN{Value: strconv.FormatInt(since.UnixNano(), 10)},
+		},
+	})
+	for paginator.HasMorePages() {
+		out, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		var page []nodeEventItem
+		if err := attributevalue.UnmarshalListOfMaps(out.Items, &page); err != nil {
+			return nil, err
+		}
+		for _, it := range page {
+			events = append(events, it.event())
+		}
+	}
+
+	return events, nil
+}
+
+// Latest returns event terakhir sebelum waktu tertentu (nil kalau belum ada)
+func (r *NodeEventRepository) Latest(ctx context.Context, nodeID string, before time.Time) (*models.NodeEvent, error) {
+	out, err := r.db.Query(ctx, &dynamodb.QueryInput{
+		TableName:              aws.String(database.NodeEventsTableName),
+		KeyConditionExpression: aws.String("nodeId = :n AND ts < :before"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":n":      &types.AttributeValueMemberS{Value: nodeID},
+			":before": &types.AttributeValueMemberN{Value: strconv.FormatInt(before.UnixNano(), 10)},
+		},
+		ScanIndexForward: aws.Bool(false),
+		Limit:            aws.Int32(1),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(out.Items) == 0 {
+		return nil, nil
+	}
+
+	var it nodeEventItem
+	if err := attributevalue.UnmarshalMap(out.Items[0], &it); err != nil {
+		return nil, err
+	}
+	ev := it.event()
+	return &ev, nil
+}
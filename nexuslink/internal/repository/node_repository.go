@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -29,6 +30,7 @@ func (r *NodeRepository) UpsertNode(ctx context.Context, n *models.Node) error {
 	now := time.Now().UTC()
 	n.LastSeenAt = now
 	n.IsOnline = true
+	n.DomainsVersion = now.UnixNano()
 
 	item, err := attributevalue.MarshalMap(n)
 	if err != nil {
@@ -39,8 +41,16 @@ func (r *NodeRepository) UpsertNode(ctx context.Context, n *models.Node) error {
 		TableName: aws.String(database.NodesTableName),
 		Item:      item,
 	})
+	if err != nil {
+		return err
+	}
 
-	return err
+	for _, d := range n.Domains {
+		if err := r.mapDomain(ctx, d, n.ID); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 // UpdateHeartbeat updates only the heartbeat fields of a node to avoid overwriting other fields like Domains
@@ -73,6 +83,65 @@ func (r *NodeRepository) UpdateHeartbeat(ctx context.Context, id string, agentVe
 	return err
 }
 
+// MarkOffline set isOnline = false, tapi hanya kalau belum ada heartbeat baru sejak lastSeenAt
+// (heartbeat yang masuk bersamaan dengan monitor tidak boleh ditimpa)
+func (r *NodeRepository) MarkOffline(ctx context.Context, id string, lastSeenAt time.Time) error {
+	key, err := attributevalue.MarshalMap(map[string]string{
+		"id": id,
+	})
+	if err != nil {
+		return err
+	}
+
+	seen, _ := attributevalue.Marshal(lastSeenAt)
+	o, _ := attributevalue.Marshal(false)
+
+	_, err = r.db.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName:           aws.String(database.NodesTableName),
+		Key:                 key,
+		UpdateExpression:    aws.String("SET isOnline = :o"),
+		ConditionExpression: aws.String("lastSeenAt = :seen"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":o":    o,
+			":seen": seen,
+		},
+	})
+
+	var ccfe *types.ConditionalCheckFailedException
+	if errors.As(err, &ccfe) {
+		return nil
+	}
+	return err
+}
+
+// SetWeight mengubah bobot steering node (node harus sudah ada)
+func (r *NodeRepository) SetWeight(ctx context.Context, id string, weight int) error {
+	key, err := attributevalue.MarshalMap(map[string]string{
+		"id": id,
+	})
+	if err != nil {
+		return err
+	}
+
+	w, _ := attributevalue.Marshal(weight)
+
+	_, err = r.db.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName:           aws.String(database.NodesTableName),
+		Key:                 key,
+		UpdateExpression:    aws.String("SET weight = :w"),
+		ConditionExpression: aws.String("attribute_exists(id)"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":w": w,
+		},
+	})
+
+	var ccfe *types.ConditionalCheckFailedException
+	if errors.As(err, &ccfe) {
+		return nil // Node not found
+	}
+	return err
+}
+
 // Helper untuk bikin Node dari env Agent (dipakai di API nanti juga kalau perlu)
 func NodeFromEnv() *models.Node {
 	config.Init()
@@ -86,16 +155,21 @@ func NodeFromEnv() *models.Node {
 
 // 🔹 Tambahan: list semua node
 func (r *NodeRepository) List(ctx context.Context) ([]models.Node, error) {
-	out, err := r.db.Scan(ctx, &dynamodb.ScanInput{
+	var nodes []models.Node
+	paginator := dynamodb.NewScanPaginator(r.db, &dynamodb.ScanInput{
 		TableName: aws.String(database.NodesTableName),
 	})
-	if err != nil {
-		return nil, err
-	}
+	for paginator.HasMorePages() {
+		out, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
 
-	var nodes []models.Node
-	if err := attributevalue.UnmarshalListOfMaps(out.Items, &nodes); err != nil {
-		return nil, err
+		var page []models.Node
+		if err := attributevalue.UnmarshalListOfMaps(out.Items, &page); err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, page...)
 	}
 
 	return nodes, nil
@@ -132,6 +206,18 @@ func (r *NodeRepository) GetByID(ctx context.Context, id string) (*models.Node,
 
 // Delete removes a node from the database
 func (r *NodeRepository) Delete(ctx context.Context, id string) error {
+	node, err := r.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if node != nil {
+		for _, d := range node.Domains {
+			if err := r.unmapDomain(ctx, d, id); err != nil {
+				return err
+			}
+		}
+	}
+
 	key, err := attributevalue.MarshalMap(map[string]string{
 		"id": id,
 	})
@@ -166,6 +252,7 @@ func (r *NodeRepository) AddDomain(ctx context.Context, nodeID, domain string) e
 
 	// Add domain to list
 	node.Domains = append(node.Domains, domain)
+	node.DomainsVersion = time.Now().UTC().UnixNano()
 
 	// Update node
 	item, err := attributevalue.MarshalMap(node)
@@ -177,8 +264,11 @@ func (r *NodeRepository) AddDomain(ctx context.Context, nodeID, domain string) e
 		TableName: aws.String(database.NodesTableName),
 		Item:      item,
 	})
+	if err != nil {
+		return err
+	}
 
-	return err
+	return r.mapDomain(ctx, domain, nodeID)
 }
 
 // RemoveDomain removes a domain from a node's domain list
@@ -199,6 +289,7 @@ func (r *NodeRepository) RemoveDomain(ctx context.Context, nodeID, domain string
 		}
 	}
 	node.Domains = newDomains
+	node.DomainsVersion = time.Now().UTC().UnixNano()
 
 	// Update node
 	item, err := attributevalue.MarshalMap(node)
@@ -210,29 +301,110 @@ func (r *NodeRepository) RemoveDomain(ctx context.Context, nodeID, domain string
 		TableName: aws.String(database.NodesTableName),
 		Item:      item,
 	})
+	if err != nil {
+		return err
+	}
 
-	return err
+	return r.unmapDomain(ctx, domain, nodeID)
 }
 
-// GetNodeByDomain finds a node that serves a specific domain
+// GetNodeByDomain finds a node that serves a specific domain (lewat tabel NodeDomains)
 func (r *NodeRepository) GetNodeByDomain(ctx context.Context, domain string) (*models.Node, error) {
-	nodes, err := r.List(ctx)
+	key, err := attributevalue.MarshalMap(map[string]string{
+		"domain": domain,
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	// Check each node's domain list
+	out, err := r.db.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(database.NodeDomainsTableName),
+		Key:       key,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if out.Item == nil {
+		return nil, nil // No node found for this domain
+	}
+
+	var m nodeDomain
+	if err := attributevalue.UnmarshalMap(out.Item, &m); err != nil {
+		return nil, err
+	}
+
+	return r.GetByID(ctx, m.NodeID)
+}
+
+// nodeDomain adalah item di tabel NodeDomains (domain → nodeId)
+type nodeDomain struct {
+	Domain string `dynamodbav:"domain"`
+	NodeID string `dynamodbav:"nodeId"`
+}
+
+func (r *NodeRepository) mapDomain(ctx context.Context, domain, nodeID string) error {
+	item, err := attributevalue.MarshalMap(nodeDomain{Domain: domain, NodeID: nodeID})
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(database.NodeDomainsTableName),
+		Item:      item,
+	})
+	return err
+}
+
+// unmapDomain hanya menghapus mapping kalau masih milik node ini
+// (domain bisa saja sudah dipindah ke node lain)
+func (r *NodeRepository) unmapDomain(ctx context.Context, domain, nodeID string) error {
+	key, err := attributevalue.MarshalMap(map[string]string{
+		"domain": domain,
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName:           aws.String(database.NodeDomainsTableName),
+		Key:                 key,
+		ConditionExpression: aws.String("nodeId = :n"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":n": &types.AttributeValueMemberS{Value: nodeID},
+		},
+	})
+
+	var ccfe *types.ConditionalCheckFailedException
+	if errors.As(err, &ccfe) {
+		return nil
+	}
+	return err
+}
+
+// BackfillDomains mengisi tabel NodeDomains dari Node.Domains yang sudah ada
+// (data lama sebelum tabel mapping dibuat). Aman dijalankan berulang.
+func (r *NodeRepository) BackfillDomains(ctx context.Context) error {
+	nodes, err := r.List(ctx)
+	if err != nil {
+		return err
+	}
+
 	for _, node := range nodes {
-		// Check additional domains
 		for _, d := range node.Domains {
-			if d == domain {
-				n := node
-				return &n, nil
+			existing, err := r.GetNodeByDomain(ctx, d)
+			if err != nil {
+				return err
+			}
+			if existing != nil {
+				continue
+			}
+			if err := r.mapDomain(ctx, d, node.ID); err != nil {
+				return err
 			}
 		}
 	}
 
-	return nil, nil // No node found for this domain
+	return nil
 }
 
 // extractDomain extracts domain from URL (e.g., "https://example.com/path" -> "example.com")
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -13,6 +14,9 @@ import (
 	"github.com/afuzapratama/nexuslink/internal/models"
 )
 
+// ErrTokenUnavailable: token registrasi tidak ada, sudah dipakai, atau kedaluwarsa
+var ErrTokenUnavailable = errors.New("node token already used or expired")
+
 type NodeTokenRepository struct {
 	db *dynamodb.Client
 }
@@ -85,19 +89,35 @@ func (r *NodeTokenRepository) List(ctx context.Context) ([]models.NodeToken, err
 	return tokens, nil
 }
 
+// MarkUsed meng-klaim token sekali pakai secara atomic (conditional update);
+// token yang sudah dipakai / kedaluwarsa / tidak ada → ErrTokenUnavailable
 func (r *NodeTokenRepository) MarkUsed(ctx context.Context, token string) error {
-	now := time.Now().UTC().Format(time.RFC3339)
+	now := time.Now().UTC()
+	cutoff, err := attributevalue.Marshal(now.Add(-models.NodeTokenTTL))
+	if err != nil {
+		return err
+	}
 
-	_, err := r.db.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+	_, err = r.db.UpdateItem(ctx, &dynamodb.UpdateItemInput{
 		TableName: aws.String(database.NodeTokensTableName),
 		Key: map[string]types.AttributeValue{
 			"token": &types.AttributeValueMemberS{Value: token},
 		},
-		UpdateExpression: aws.String("SET isUsed = :used, usedAt = :usedAt"),
+		UpdateExpression:    aws.String("SET isUsed = :used, usedAt = :usedAt"),
+		ConditionExpression: aws.String("attribute_exists(#t) AND isUsed = :unused AND createdAt > :cutoff"),
+		ExpressionAttributeNames: map[string]string{
+			"#t": "token",
+		},
 		ExpressionAttributeValues: map[string]types.AttributeValue{
 			":used":   &types.AttributeValueMemberBOOL{Value: true},
-			":usedAt": &types.AttributeValueMemberS{Value: now},
+			":unused": &types.AttributeValueMemberBOOL{Value: false},
+			":usedAt": &types.AttributeValueMemberS{Value: now.Format(time.RFC3339)},
+			":cutoff": cutoff,
 		},
 	})
+	var ccfe *types.ConditionalCheckFailedException
+	if errors.As(err, &ccfe) {
+		return ErrTokenUnavailable
+	}
 	return err
 }
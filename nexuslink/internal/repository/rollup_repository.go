@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"context"
+	"strconv"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/afuzapratama/nexuslink/internal/database"
+	"github.com/afuzapratama/nexuslink/internal/models"
+)
+
+// rollupWriteConcurrency membatasi UpdateItem paralel per batch
+const rollupWriteConcurrency = 8
+
+type RollupRepository struct {
+	db *dynamodb.Client
+}
+
+func NewRollupRepository() *RollupRepository {
+	return &RollupRepository{
+		db: database.Client(),
+	}
+}
+
+// Add menambahkan Count setiap rollup ke counter di DynamoDB (ADD, atomic).
+// Return error pertama yang terjadi; increment lain tetap dijalankan.
+func (r *RollupRepository) Add(ctx context.Context, rollups []models.ClickRollup) error {
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+	)
+	sem := make(chan struct{}, rollupWriteConcurrency)
+
+	for i := range rollups {
+		wg.Add(1)
+		sem <- struct{}{}
+
+		go func(ru *models.ClickRollup) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			if err := r.add(ctx, ru); err != nil {
+				mu.Lock()
+				if firstErr == nil {
+					firstErr = err
+				}
+				mu.Unlock()
+			}
+		}(&rollups[i])
+	}
+
+	wg.Wait()
+	return firstErr
+}
+
+func (r *RollupRepository) add(ctx context.Context, ru *models.ClickRollup) error {
+	_, err := r.db.UpdateItem(ctx, rollupUpdateInput(ru))
+	return err
+}
+
+// rollupUpdateInput: semua nama atribut lewat placeholder "#" karena sebagian
+// (bucket, value, count) adalah reserved word DynamoDB
+func rollupUpdateInput(ru *models.ClickRollup) *dynamodb.UpdateItemInput {
+	return &dynamodb.UpdateItemInput{
+		TableName: aws.String(database.ClickRollupsTableName),
+		Key: map[string]types.AttributeValue{
+			"pk": &types.AttributeValueMemberS{Value: ru.PK},
+			"sk": &types.AttributeValueMemberS{Value: ru.SK},
+		},
+		UpdateExpression: aws.String("SET #a = :alias, #g = :gran, #d = :dim, #v = :value, #b = :bucket ADD #c :inc"),
+		ExpressionAttributeNames: map[string]string{
+			"#a": "alias",
+			"#g": "granularity",
+			"#d": "dimension",
+			"#v": "value",
+			"#b": "bucket",
+			"#c": "count",
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":alias":  &types.AttributeValueMemberS{Value: ru.Alias},
+			":gran":   &types.AttributeValueMemberS{Value: ru.Granularity},
+			":dim":    &types.AttributeValueMemberS{Value: ru.Dimension},
+			":value":  &types.AttributeValueMemberS{Value: ru.Value},
+			":bucket": &types.AttributeValueMemberS{Value: ru.Bucket},
+			":inc":    &types.AttributeValueMemberN{Value: strconv.FormatInt(ru.Count, 10)},
+		},
+	}
+}
+
+// Query returns rollup rows satu dimensi dengan bucket di antara fromBucket dan toBucket (inklusif)
+func (r *RollupRepository) Query(ctx context.Context, pk, fromBucket, toBucket string) ([]models.ClickRollup, error) {
+	input := &dynamodb.QueryInput{
+		TableName:              aws.String(database.ClickRollupsTableName),
+		KeyConditionExpression: aws.String("pk = :pk AND sk BETWEEN :from AND :to"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":pk":   &types.AttributeValueMemberS{Value: pk},
+			":from": &types.AttributeValueMemberS{Value: fromBucket + "#"},
+			// "$" adalah karakter setelah "#", jadi semua value di bucket terakhir ikut
+			":to": &types.AttributeValueMemberS{Value: toBucket + "$"},
+		},
+	}
+
+	var rollups []models.ClickRollup
+	paginator := dynamodb.NewQueryPaginator(r.db, input)
+	for paginator.HasMorePages() {
+		out, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		var page []models.ClickRollup
+		if err := attributevalue.UnmarshalListOfMaps(out.Items, &page); err != nil {
+			return nil, err
+		}
+		rollups = append(rollups, page...)
+	}
+
+	return rollups, nil
+}
@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/afuzapratama/nexuslink/internal/models"
+)
+
+// Semua nama atribut di UpdateExpression harus lewat placeholder: nama polos seperti
+// "bucket" adalah reserved word dan membuat UpdateItem gagal dengan ValidationException.
+func TestRollupUpdateInputEscapesNames(t *testing.T) {
+	in := rollupUpdateInput(&models.ClickRollup{
+		PK: "promo#day#country", SK: "2025-01-02#ID", Alias: "promo",
+		Granularity: "day", Dimension: "country", Value: "ID", Bucket: "2025-01-02", Count: 3,
+	})
+
+	keywords := map[string]bool{"SET": true, "ADD": true, "=": true}
+	names := map[string]bool{}
+	for _, tok := range strings.Fields(strings.ReplaceAll(*in.UpdateExpression, ",", " ")) {
+		switch {
+		case keywords[tok]:
+		case strings.HasPrefix(tok, "#"):
+			name, ok := in.ExpressionAttributeNames[tok]
+			if !ok {
+				t.Errorf("placeholder %s has no ExpressionAttributeNames entry", tok)
+			}
+			names[name] = true
+		case strings.HasPrefix(tok, ":"):
+			if _, ok := in.ExpressionAttributeValues[tok]; !ok {
+				t.Errorf("value %s has no ExpressionAttributeValues entry", tok)
+			}
+		default:
+			t.Errorf("unescaped attribute name %q in %q", tok, *in.UpdateExpression)
+		}
+	}
+	for _, want := range []string{"alias", "granularity", "dimension", "value", "bucket", "count"} {
+		if !names[want] {
+			t.Errorf("attribute %s not written", want)
+		}
+	}
+	if v, ok := in.ExpressionAttributeValues[":inc"].(*types.AttributeValueMemberN); !ok || v.Value != "3" {
+		t.Errorf(":inc = %#v, want N 3", in.ExpressionAttributeValues[":inc"])
+	}
+}
@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/afuzapratama/nexuslink/internal/database"
+	"github.com/afuzapratama/nexuslink/internal/models"
+)
+
+type SessionRepository struct {
+	db *dynamodb.Client
+}
+
+func NewSessionRepository() *SessionRepository {
+	return &SessionRepository{
+		db: database.Client(),
+	}
+}
+
+// sessionItem adalah item di tabel Sessions (expiresAtUnix = atribut TTL DynamoDB)
+type sessionItem struct {
+	models.Session
+	ExpiresAtUnix int64 `dynamodbav:"expiresAtUnix"`
+}
+
+func (r *SessionRepository) Create(ctx context.Context, s *models.Session) error {
+	item, err := attributevalue.MarshalMap(sessionItem{Session: *s, ExpiresAtUnix: s.ExpiresAt.Unix()})
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(database.SessionsTableName),
+		Item:      item,
+	})
+	return err
+}
+
+// Get returns nil untuk session yang tidak ada atau sudah expired (TTL DynamoDB bisa telat menghapus)
+func (r *SessionRepository) Get(ctx context.Context, tokenHash string) (*models.Session, error) {
+	out, err := r.db.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(database.SessionsTableName),
+		Key: map[string]types.AttributeValue{
+			"tokenHash": &types.AttributeValueMemberS{Value: tokenHash},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if out.Item == nil {
+		return nil, nil
+	}
+
+	var it sessionItem
+	if err := attributevalue.UnmarshalMap(out.Item, &it); err != nil {
+		return nil, err
+	}
+	if time.Now().After(it.ExpiresAt) {
+		return nil, nil
+	}
+	return &it.Session, nil
+}
+
+func (r *SessionRepository) Touch(ctx context.Context, tokenHash string, expiresAt time.Time) error {
+	expiresAtAV, err := attributevalue.Marshal(expiresAt)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName: aws.String(database.SessionsTableName),
+		Key: map[string]types.AttributeValue{
+			"tokenHash": &types.AttributeValueMemberS{Value: tokenHash},
+		},
+		UpdateExpression:    aws.String("SET expiresAt = :exp, expiresAtUnix = :ttl"),
+		ConditionExpression: aws.String("attribute_exists(tokenHash)"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":exp": expiresAtAV,
+			":ttl": &types.AttributeValueMemberN{Value: strconv.FormatInt(expiresAt.Unix(), 10)},
+		},
+	})
+	var ccfe *types.ConditionalCheckFailedException
+	if errors.As(err, &ccfe) {
+		return nil // Session sudah dihapus
+	}
+	return err
+}
+
+func (r *SessionRepository) Delete(ctx context.Context, tokenHash string) error {
+	_, err := r.db.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName: aws.String(database.SessionsTableName),
+		Key: map[string]types.AttributeValue{
+			"tokenHash": &types.AttributeValueMemberS{Value: tokenHash},
+		},
+	})
+	return err
+}
+
+// DeleteByUser menghapus semua session user (scan; dipakai saat user dihapus / ganti password)
+func (r *SessionRepository) DeleteByUser(ctx context.Context, userID string) error {
+	paginator := dynamodb.NewScanPaginator(r.db, &dynamodb.ScanInput{
+		TableName:            aws.String(database.SessionsTableName),
+		FilterExpression:     aws.String("userId = :u"),
+		ProjectionExpression: aws.String("tokenHash"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":u": &types.AttributeValueMemberS{Value: userID},
+		},
+	})
+	for paginator.HasMorePages() {
+		out, err := paginator.NextPage(ctx)
+		if err != nil {
+			return err
+		}
+		for _, item := range out.Items {
+			if _, err := r.db.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+				TableName: aws.String(database.SessionsTableName),
+				Key:       map[string]types.AttributeValue{"tokenHash": item["tokenHash"]},
+			}); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
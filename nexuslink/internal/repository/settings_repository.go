@@ -7,6 +7,7 @@ import (
 
 	"github.com/afuzapratama/nexuslink/internal/database"
 	"github.com/afuzapratama/nexuslink/internal/models"
+	"github.com/afuzapratama/nexuslink/internal/ratelimit"
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
@@ -85,21 +86,33 @@ func (r *SettingsRepository) Update(ctx context.Context, settings *models.Settin
 }
 
 // GetRateLimitConfig returns current rate limit configuration
-func (r *SettingsRepository) GetRateLimitConfig() map[string]int {
+func GetRateLimitConfig(store SettingsStore) map[string]interface{} {
 	ctx := context.Background()
-	settings := r.GetOrDefault(ctx)
+	settings := store.GetOrDefault(ctx)
 
-	return map[string]int{
+	action := settings.RateLimitAction
+	if action == "" {
+		action = models.RateLimitActionReject
+	}
+	algorithm := settings.RateLimitAlgorithm
+	if algorithm == "" {
+		algorithm = ratelimit.AlgorithmSlidingLog
+	}
+
+	return map[string]interface{}{
+		"enabled":        settings.EnableRateLimit,
+		"action":         action,
+		"algorithm":      algorithm,
 		"ip_limit":       settings.RateLimitPerIP,
 		"link_limit":     settings.RateLimitPerLink,
 		"window_seconds": settings.RateLimitWindow,
 	}
 }
 
-// UpdateRateLimitConfig updates rate limit settings
-func (r *SettingsRepository) UpdateRateLimitConfig(ipLimit, linkLimit, window int) error {
+// UpdateRateLimitConfig updates rate limit settings (nilai 0 / kosong / nil = tidak diubah)
+func UpdateRateLimitConfig(store SettingsStore, ipLimit, linkLimit, window int, enabled *bool, action, algorithm string) error {
 	ctx := context.Background()
-	settings := r.GetOrDefault(ctx)
+	settings := store.GetOrDefault(ctx)
 
 	if ipLimit > 0 {
 		settings.RateLimitPerIP = ipLimit
@@ -110,6 +123,15 @@ func (r *SettingsRepository) UpdateRateLimitConfig(ipLimit, linkLimit, window in
 	if window > 0 {
 		settings.RateLimitWindow = window
 	}
+	if enabled != nil {
+		settings.EnableRateLimit = *enabled
+	}
+	if action != "" {
+		settings.RateLimitAction = action
+	}
+	if algorithm != "" {
+		settings.RateLimitAlgorithm = algorithm
+	}
 
-	return r.Update(ctx, settings)
+	return store.Update(ctx, settings)
 }
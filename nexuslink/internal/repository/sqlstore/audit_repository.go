@@ -0,0 +1,69 @@
+package sqlstore
+
+import (
+	"context"
+	"strings"
+
+	"github.com/afuzapratama/nexuslink/internal/models"
+)
+
+type AuditRepository struct {
+	db *DB
+}
+
+func NewAuditRepository(db *DB) *AuditRepository {
+	return &AuditRepository{db: db}
+}
+
+func (r *AuditRepository) Append(ctx context.Context, rec *models.AuditRecord) error {
+	data, err := encode(rec)
+	if err != nil {
+		return err
+	}
+	_, err = r.db.exec(ctx, `
+		INSERT INTO audit_log (id, at, actor, action, target_type, target_id, data) VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		rec.ID, rec.At.UnixNano(), strings.ToLower(rec.Actor), rec.Action, rec.TargetType, rec.TargetID, data)
+	return err
+}
+
+func (r *AuditRepository) List(ctx context.Context, f models.AuditFilter) ([]models.AuditRecord, error) {
+	var where []string
+	var args []interface{}
+
+	if !f.Since.IsZero() {
+		where = append(where, "at >= ?")
+		args = append(args, f.Since.UnixNano())
+	}
+	if !f.Until.IsZero() {
+		where = append(where, "at <= ?")
+		args = append(args, f.Until.UnixNano())
+	}
+	if f.Actor != "" {
+		where = append(where, "actor = ?")
+		args = append(args, strings.ToLower(f.Actor))
+	}
+	if f.Action != "" {
+		where = append(where, "(action = ? OR action LIKE ?)")
+		args = append(args, f.Action, f.Action+".%")
+	}
+	if f.TargetType != "" {
+		where = append(where, "target_type = ?")
+		args = append(args, f.TargetType)
+	}
+	if f.TargetID != "" {
+		where = append(where, "target_id = ?")
+		args = append(args, f.TargetID)
+	}
+
+	query := `SELECT data FROM audit_log`
+	if len(where) > 0 {
+		query += ` WHERE ` + strings.Join(where, " AND ")
+	}
+	query += ` ORDER BY at DESC, id DESC`
+	if f.Limit > 0 {
+		query += ` LIMIT ?`
+		args = append(args, f.Limit)
+	}
+
+	return queryData[models.AuditRecord](ctx, r.db, query, args...)
+}
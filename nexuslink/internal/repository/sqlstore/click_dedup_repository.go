@@ -0,0 +1,59 @@
+package sqlstore
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/afuzapratama/nexuslink/internal/repository"
+)
+
+// clickDedupCleanupEvery: ID kedaluwarsa dibersihkan paling sering sekali per interval ini (pengganti TTL DynamoDB)
+const clickDedupCleanupEvery = time.Minute
+
+type ClickDedupRepository struct {
+	db *DB
+
+	mu          sync.Mutex
+	lastCleanup time.Time
+}
+
+func NewClickDedupRepository(db *DB) *ClickDedupRepository {
+	return &ClickDedupRepository{db: db}
+}
+
+// Claim menandai ID click sudah diproses; false = ID sudah pernah di-claim (dan belum kedaluwarsa)
+func (r *ClickDedupRepository) Claim(ctx context.Context, id string) (bool, error) {
+	now := time.Now()
+	r.cleanup(ctx, now)
+
+	res, err := r.db.exec(ctx, `
+		INSERT INTO click_dedup (id, expires_at) VALUES (?, ?)
+		ON CONFLICT (id) DO UPDATE SET expires_at = excluded.expires_at WHERE click_dedup.expires_at < ?`,
+		id, now.Add(repository.ClickDedupRetention).Unix(), now.Unix())
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
+}
+
+func (r *ClickDedupRepository) cleanup(ctx context.Context, now time.Time) {
+	r.mu.Lock()
+	due := now.Sub(r.lastCleanup) >= clickDedupCleanupEvery
+	if due {
+		r.lastCleanup = now
+	}
+	r.mu.Unlock()
+	if !due {
+		return
+	}
+
+	if _, err := r.db.exec(ctx, `DELETE FROM click_dedup WHERE expires_at < ?`, now.Unix()); err != nil {
+		log.Printf("sqlstore: click dedup cleanup failed: %v", err)
+	}
+}
@@ -0,0 +1,164 @@
+package sqlstore
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/afuzapratama/nexuslink/internal/models"
+)
+
+type ClickRepository struct {
+	db *DB
+}
+
+func NewClickRepository(db *DB) *ClickRepository {
+	return &ClickRepository{db: db}
+}
+
+// Log satu event klik
+func (r *ClickRepository) LogClick(ctx context.Context, ev *models.ClickEvent) error {
+	return r.LogClicks(ctx, []models.ClickEvent{*ev})
+}
+
+// LogClicks menyimpan banyak click event dalam satu transaksi.
+// Insert idempotent per ID, jadi replay dari spill file tidak membuat duplikat.
+func (r *ClickRepository) LogClicks(ctx context.Context, events []models.ClickEvent) error {
+	now := time.Now().UTC()
+
+	return r.db.withTx(ctx, func(q querier) error {
+		for i := range events {
+			ev := &events[i]
+			if ev.ID == "" {
+				ev.ID = uuid.NewString()
+			}
+			if ev.CreatedAt.IsZero() {
+				ev.CreatedAt = now
+			}
+
+			data, err := encode(ev)
+			if err != nil {
+				return err
+			}
+			_, err = q.ExecContext(ctx, `
+				INSERT INTO click_events (id, alias, created_at, data) VALUES (?, ?, ?, ?)
+				ON CONFLICT (id) DO UPDATE SET alias = excluded.alias, created_at = excluded.created_at, data = excluded.data`,
+				ev.ID, ev.Alias, ev.CreatedAt.UnixNano(), data)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Ambil semua click events untuk alias tertentu (terbaru dulu)
+func (r *ClickRepository) ListByAlias(ctx context.Context, alias string) ([]models.ClickEvent, error) {
+	return queryData[models.ClickEvent](ctx, r.db, `
+		SELECT data FROM click_events WHERE alias = ? ORDER BY created_at DESC, id DESC`, alias)
+}
+
+// ListByAliasPaginated returns paginated click events for a specific alias
+func (r *ClickRepository) ListByAliasPaginated(ctx context.Context, alias string, page, limit int) ([]models.ClickEvent, int, error) {
+	var total int
+	if err := r.db.queryRow(ctx, `SELECT COUNT(*) FROM click_events WHERE alias = ?`, alias).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	offset, end := offsetBounds(page, limit, total)
+	if offset >= end {
+		return []models.ClickEvent{}, total, nil
+	}
+
+	events, err := queryData[models.ClickEvent](ctx, r.db, `
+		SELECT data FROM click_events WHERE alias = ? ORDER BY created_at DESC, id DESC LIMIT ? OFFSET ?`,
+		alias, end-offset, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	return events, total, nil
+}
+
+// ListByAliasPage returns satu halaman click events (terbaru dulu) mulai dari cursor
+func (r *ClickRepository) ListByAliasPage(ctx context.Context, alias string, limit int, cur string) ([]models.ClickEvent, string, error) {
+	c, err := decodeCursor(cur)
+	if err != nil {
+		return nil, "", err
+	}
+
+	query := `SELECT data FROM click_events WHERE alias = ?`
+	args := []interface{}{alias}
+	if c != nil {
+		query += ` AND (created_at < ? OR (created_at = ? AND id < ?))`
+		args = append(args, c.CreatedAt, c.CreatedAt, c.ID)
+	}
+	query += ` ORDER BY created_at DESC, id DESC LIMIT ?`
+	args = append(args, limit+1)
+
+	return r.page(ctx, query, args, limit)
+}
+
+// ListAllPaginated returns paginated click events for all links (for dashboard)
+func (r *ClickRepository) ListAllPaginated(ctx context.Context, page, limit int) ([]models.ClickEvent, int, error) {
+	var total int
+	if err := r.db.queryRow(ctx, `SELECT COUNT(*) FROM click_events`).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	offset, end := offsetBounds(page, limit, total)
+	if offset >= end {
+		return []models.ClickEvent{}, total, nil
+	}
+
+	events, err := queryData[models.ClickEvent](ctx, r.db, `
+		SELECT data FROM click_events ORDER BY created_at DESC, id DESC LIMIT ? OFFSET ?`,
+		end-offset, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	return events, total, nil
+}
+
+// ListAllPage returns satu halaman click events semua link (terbaru dulu) mulai dari cursor
+func (r *ClickRepository) ListAllPage(ctx context.Context, limit int, cur string) ([]models.ClickEvent, string, error) {
+	c, err := decodeCursor(cur)
+	if err != nil {
+		return nil, "", err
+	}
+
+	query := `SELECT data FROM click_events`
+	var args []interface{}
+	if c != nil {
+		query += ` WHERE created_at < ? OR (created_at = ? AND id < ?)`
+		args = append(args, c.CreatedAt, c.CreatedAt, c.ID)
+	}
+	query += ` ORDER BY created_at DESC, id DESC LIMIT ?`
+	args = append(args, limit+1)
+
+	return r.page(ctx, query, args, limit)
+}
+
+// page menjalankan query limit+1 lalu membentuk nextCursor dari baris terakhir
+func (r *ClickRepository) page(ctx context.Context, query string, args []interface{}, limit int) ([]models.ClickEvent, string, error) {
+	events, err := queryData[models.ClickEvent](ctx, r.db, query, args...)
+	if err != nil {
+		return nil, "", err
+	}
+	if events == nil {
+		events = []models.ClickEvent{}
+	}
+
+	if len(events) <= limit {
+		return events, "", nil
+	}
+	events = events[:limit]
+	last := events[limit-1]
+	return events, encodeCursor(cursor{ID: last.ID, CreatedAt: last.CreatedAt.UnixNano()}), nil
+}
+
+// DeleteByLinkAlias deletes all click events for a given link alias
+func (r *ClickRepository) DeleteByLinkAlias(ctx context.Context, alias string) error {
+	_, err := r.db.exec(ctx, `DELETE FROM click_events WHERE alias = ?`, alias)
+	return err
+}
@@ -0,0 +1,53 @@
+package sqlstore
+
+import (
+	"encoding/base64"
+	"encoding/json"
+
+	"github.com/afuzapratama/nexuslink/internal/repository"
+)
+
+// cursor adalah posisi keyset pagination: baris terakhir yang sudah dikirim ke client
+type cursor struct {
+	ID        string `json:"id"`
+	CreatedAt int64  `json:"createdAt,omitempty"`
+}
+
+func encodeCursor(c cursor) string {
+	b, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// decodeCursor: cursor kosong → nil (mulai dari awal)
+func decodeCursor(s string) (*cursor, error) {
+	if s == "" {
+		return nil, nil
+	}
+
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, repository.ErrInvalidCursor
+	}
+
+	var c cursor
+	if err := json.Unmarshal(b, &c); err != nil || c.ID == "" {
+		return nil, repository.ErrInvalidCursor
+	}
+	return &c, nil
+}
+
+// offsetBounds menghitung slice [offset:end] untuk mode page/limit
+func offsetBounds(page, limit, total int) (int, int) {
+	offset := (page - 1) * limit
+	if offset < 0 {
+		offset = 0
+	}
+	if offset > total {
+		offset = total
+	}
+	end := offset + limit
+	if end > total {
+		end = total
+	}
+	return offset, end
+}
@@ -0,0 +1,207 @@
+// Package sqlstore mengimplementasikan interface storage di package repository
+// di atas database/sql, untuk self-host tanpa DynamoDB (SQLite embedded atau PostgreSQL).
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	_ "github.com/jackc/pgx/v5/stdlib" // driver "pgx"
+	_ "modernc.org/sqlite"             // driver "sqlite" (pure Go, tanpa cgo)
+)
+
+const (
+	DialectSQLite   = "sqlite"
+	DialectPostgres = "postgres"
+)
+
+// DB membungkus *sql.DB plus dialect-nya.
+// Query ditulis dengan placeholder "?" lalu di-rebind ke "$n" untuk PostgreSQL.
+type DB struct {
+	sql     *sql.DB
+	dialect string
+}
+
+// Open membuka koneksi dan menjalankan migrasi schema.
+// dialect "sqlite": dsn adalah path file; "postgres": dsn adalah connection URL.
+func Open(ctx context.Context, dialect, dsn string) (*DB, error) {
+	var (
+		conn *sql.DB
+		err  error
+	)
+
+	switch dialect {
+	case DialectSQLite:
+		if dir := filepath.Dir(dsn); dir != "" {
+			if err := os.MkdirAll(dir, 0o755); err != nil {
+				return nil, err
+			}
+		}
+		conn, err = sql.Open("sqlite", dsn+"?_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)")
+		if err != nil {
+			return nil, err
+		}
+		// SQLite cuma boleh satu writer; satu koneksi menghindari SQLITE_BUSY
+		conn.SetMaxOpenConns(1)
+	case DialectPostgres:
+		conn, err = sql.Open("pgx", dsn)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("sqlstore: unknown dialect %q", dialect)
+	}
+
+	if err := conn.PingContext(ctx); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	db := &DB{sql: conn, dialect: dialect}
+	if err := db.migrate(ctx); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("sqlstore: migrate: %w", err)
+	}
+	return db, nil
+}
+
+// Close menutup koneksi database
+func (db *DB) Close() error {
+	return db.sql.Close()
+}
+
+// rebind mengganti placeholder "?" jadi "$1, $2, ..." untuk PostgreSQL
+func (db *DB) rebind(query string) string {
+	if db.dialect != DialectPostgres {
+		return query
+	}
+
+	var b strings.Builder
+	n := 0
+	for _, c := range query {
+		if c == '?' {
+			n++
+			b.WriteString("$" + strconv.Itoa(n))
+			continue
+		}
+		b.WriteRune(c)
+	}
+	return b.String()
+}
+
+// querier adalah bagian dari *sql.DB / *sql.Tx yang dipakai repository
+type querier interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+func (db *DB) exec(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return db.conn().ExecContext(ctx, query, args...)
+}
+
+func (db *DB) queryRow(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return db.conn().QueryRowContext(ctx, query, args...)
+}
+
+// conn returns querier di luar transaksi (placeholder otomatis di-rebind)
+func (db *DB) conn() querier {
+	return &rebinder{raw: db.sql, db: db}
+}
+
+// withTx menjalankan fn di dalam transaksi; rollback kalau fn return error
+func (db *DB) withTx(ctx context.Context, fn func(q querier) error) error {
+	tx, err := db.sql.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if err := fn(&rebinder{raw: tx, db: db}); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// rebinder membungkus *sql.DB / *sql.Tx supaya query dengan "?" jalan di semua dialect
+type rebinder struct {
+	raw querier
+	db  *DB
+}
+
+func (r *rebinder) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return r.raw.ExecContext(ctx, r.db.rebind(query), args...)
+}
+
+func (r *rebinder) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return r.raw.QueryContext(ctx, r.db.rebind(query), args...)
+}
+
+func (r *rebinder) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return r.raw.QueryRowContext(ctx, r.db.rebind(query), args...)
+}
+
+// queryData menjalankan query yang return satu kolom JSON "data" per baris,
+// lalu decode tiap baris ke T. Rows ditutup sebelum return (aman dengan satu koneksi SQLite).
+func queryData[T any](ctx context.Context, db *DB, query string, args ...interface{}) ([]T, error) {
+	rows, err := db.conn().QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []T
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return nil, err
+		}
+		var v T
+		if err := decode(data, &v); err != nil {
+			return nil, err
+		}
+		out = append(out, v)
+	}
+	return out, rows.Err()
+}
+
+// getData sama seperti queryData tapi untuk satu baris; not found → nil, nil
+func getData[T any](ctx context.Context, db *DB, query string, args ...interface{}) (*T, error) {
+	var data string
+	err := db.queryRow(ctx, query, args...).Scan(&data)
+	if isNoRows(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var v T
+	if err := decode(data, &v); err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
+
+// Model disimpan utuh sebagai JSON di kolom "data"; kolom lain hanya untuk key, index & counter.
+func encode(v interface{}) (string, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+func decode(data string, v interface{}) error {
+	return json.Unmarshal([]byte(data), v)
+}
+
+func isNoRows(err error) bool {
+	return errors.Is(err, sql.ErrNoRows)
+}
@@ -0,0 +1,58 @@
+package sqlstore
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/afuzapratama/nexuslink/internal/models"
+)
+
+type LinkGroupRepository struct {
+	db *DB
+}
+
+func NewLinkGroupRepository(db *DB) *LinkGroupRepository {
+	return &LinkGroupRepository{db: db}
+}
+
+func (r *LinkGroupRepository) Create(ctx context.Context, group *models.LinkGroup) error {
+	now := time.Now().UTC()
+	group.ID = uuid.NewString()
+	group.CreatedAt = now
+	group.UpdatedAt = now
+
+	return r.put(ctx, group)
+}
+
+func (r *LinkGroupRepository) Update(ctx context.Context, group *models.LinkGroup) error {
+	group.UpdatedAt = time.Now().UTC()
+
+	return r.put(ctx, group)
+}
+
+func (r *LinkGroupRepository) put(ctx context.Context, group *models.LinkGroup) error {
+	data, err := encode(group)
+	if err != nil {
+		return err
+	}
+	_, err = r.db.exec(ctx, `
+		INSERT INTO link_groups (id, sort_order, data) VALUES (?, ?, ?)
+		ON CONFLICT (id) DO UPDATE SET sort_order = excluded.sort_order, data = excluded.data`,
+		group.ID, group.SortOrder, data)
+	return err
+}
+
+func (r *LinkGroupRepository) Delete(ctx context.Context, id string) error {
+	_, err := r.db.exec(ctx, `DELETE FROM link_groups WHERE id = ?`, id)
+	return err
+}
+
+func (r *LinkGroupRepository) Get(ctx context.Context, id string) (*models.LinkGroup, error) {
+	return getData[models.LinkGroup](ctx, r.db, `SELECT data FROM link_groups WHERE id = ?`, id)
+}
+
+func (r *LinkGroupRepository) List(ctx context.Context) ([]models.LinkGroup, error) {
+	return queryData[models.LinkGroup](ctx, r.db, `SELECT data FROM link_groups ORDER BY sort_order, id`)
+}
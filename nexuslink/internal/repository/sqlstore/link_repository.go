@@ -0,0 +1,267 @@
+package sqlstore
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/afuzapratama/nexuslink/internal/models"
+	"github.com/afuzapratama/nexuslink/internal/repository"
+)
+
+type LinkRepository struct {
+	db *DB
+}
+
+func NewLinkRepository(db *DB) *LinkRepository {
+	return &LinkRepository{db: db}
+}
+
+func (r *LinkRepository) Create(ctx context.Context, link *models.Link) error {
+	now := time.Now().UTC()
+
+	if link.ID == "" {
+		link.ID = uuid.NewString()
+	}
+	if link.CreatedAt.IsZero() {
+		link.CreatedAt = now
+	}
+	link.UpdatedAt = now
+	link.Version = now.UnixNano()
+	// Link baru selalu pakai key dari (domain, alias) sekarang; Key dari Create yang gagal
+	// (mis. retry generate alias setelah ErrAliasTaken) tidak boleh terbawa
+	link.Key = models.LinkKey(link.Domain, link.Alias)
+
+	if !link.IsActive {
+		link.IsActive = true
+	}
+
+	data, err := encode(link)
+	if err != nil {
+		return err
+	}
+
+	// Reservasi alias + insert link dalam satu transaksi (lihat repository.LinkRepository.Create)
+	return r.db.withTx(ctx, func(q querier) error {
+		for _, key := range repository.AliasReservations(link) {
+			res, err := q.ExecContext(ctx, `INSERT INTO link_aliases (alias_key, link_id) VALUES (?, ?) ON CONFLICT (alias_key) DO NOTHING`,
+				key, link.ID)
+			if err != nil {
+				return err
+			}
+			if n, err := res.RowsAffected(); err != nil {
+				return err
+			} else if n == 0 {
+				return repository.ErrAliasTaken
+			}
+		}
+
+		_, err = q.ExecContext(ctx, `INSERT INTO links (id, alias, alias_lower, group_id, version, data) VALUES (?, ?, ?, ?, ?, ?)`,
+			link.ID, link.Alias, strings.ToLower(link.Alias), link.GroupID, link.Version, data)
+		return err
+	})
+}
+
+// BackfillAliases membuat reservasi (domain, alias) untuk link ber-domain yang dibuat sebelum alias per domain,
+// dan menyamakan alias_lower untuk alias non-ASCII. Aman dijalankan berulang (lihat repository.LinkRepository.BackfillAliases).
+func (r *LinkRepository) BackfillAliases(ctx context.Context) error {
+	links, err := r.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	for i := range links {
+		lower := strings.ToLower(links[i].Alias)
+		if _, err := r.db.exec(ctx, `UPDATE links SET alias_lower = ? WHERE id = ? AND alias_lower <> ?`,
+			lower, links[i].ID, lower); err != nil {
+			return err
+		}
+		for _, key := range repository.AliasReservations(&links[i]) {
+			if _, err := r.db.exec(ctx, `INSERT INTO link_aliases (alias_key, link_id) VALUES (?, ?) ON CONFLICT (alias_key) DO NOTHING`,
+				key, links[i].ID); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// NextAliasSequence menaikkan counter per scope secara atomic
+func (r *LinkRepository) NextAliasSequence(ctx context.Context, scope string) (int64, error) {
+	var n int64
+	err := r.db.queryRow(ctx, `
+		INSERT INTO alias_sequences (scope, value) VALUES (?, 1)
+		ON CONFLICT (scope) DO UPDATE SET value = alias_sequences.value + 1
+		RETURNING value`, strings.ToLower(scope)).Scan(&n)
+	return n, err
+}
+
+func (r *LinkRepository) Update(ctx context.Context, link *models.Link) error {
+	link.UpdatedAt = time.Now().UTC()
+	link.Version = link.UpdatedAt.UnixNano()
+
+	return r.put(ctx, link)
+}
+
+func (r *LinkRepository) put(ctx context.Context, link *models.Link) error {
+	data, err := encode(link)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.exec(ctx, `
+		INSERT INTO links (id, alias, alias_lower, group_id, version, data) VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT (id) DO UPDATE SET alias = excluded.alias, alias_lower = excluded.alias_lower,
+			group_id = excluded.group_id, version = excluded.version, data = excluded.data`,
+		link.ID, link.Alias, strings.ToLower(link.Alias), link.GroupID, link.Version, data)
+	return err
+}
+
+func (r *LinkRepository) Delete(ctx context.Context, id string) error {
+	return r.db.withTx(ctx, func(q querier) error {
+		var alias string
+		err := q.QueryRowContext(ctx, `SELECT alias FROM links WHERE id = ?`, id).Scan(&alias)
+		if err != nil && !isNoRows(err) {
+			return err
+		}
+
+		if _, err := q.ExecContext(ctx, `DELETE FROM links WHERE id = ?`, id); err != nil {
+			return err
+		}
+		if _, err := q.ExecContext(ctx, `DELETE FROM link_aliases WHERE link_id = ?`, id); err != nil {
+			return err
+		}
+
+		// Tombstone untuk delta feed agent (lihat repository.LinkRepository.Delete)
+		now := time.Now().UTC()
+		_, err = q.ExecContext(ctx, `
+			INSERT INTO link_tombstones (id, alias, version, expires_at) VALUES (?, ?, ?, ?)
+			ON CONFLICT (id) DO UPDATE SET alias = excluded.alias, version = excluded.version, expires_at = excluded.expires_at`,
+			id, alias, now.UnixNano(), now.Add(repository.TombstoneRetention).Unix())
+		return err
+	})
+}
+
+func (r *LinkRepository) List(ctx context.Context) ([]models.Link, error) {
+	return queryData[models.Link](ctx, r.db, `SELECT data FROM links ORDER BY id`)
+}
+
+// ListPaginated returns paginated links (offset mode)
+func (r *LinkRepository) ListPaginated(ctx context.Context, page, limit int) ([]models.Link, int, error) {
+	var total int
+	if err := r.db.queryRow(ctx, `SELECT COUNT(*) FROM links`).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	offset, end := offsetBounds(page, limit, total)
+	if offset >= end {
+		return []models.Link{}, total, nil
+	}
+
+	links, err := queryData[models.Link](ctx, r.db, `SELECT data FROM links ORDER BY id LIMIT ? OFFSET ?`, end-offset, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	return links, total, nil
+}
+
+// ListPage returns satu halaman link (urut id) mulai dari cursor
+func (r *LinkRepository) ListPage(ctx context.Context, groupID string, limit int, cur string) ([]models.Link, string, error) {
+	c, err := decodeCursor(cur)
+	if err != nil {
+		return nil, "", err
+	}
+
+	after := ""
+	if c != nil {
+		after = c.ID
+	}
+
+	// Ambil limit+1 untuk tahu apakah masih ada halaman berikutnya
+	query := `SELECT data FROM links WHERE id > ?`
+	args := []interface{}{after}
+	if groupID != "" {
+		query += ` AND group_id = ?`
+		args = append(args, groupID)
+	}
+	query += ` ORDER BY id LIMIT ?`
+	args = append(args, limit+1)
+
+	links, err := queryData[models.Link](ctx, r.db, query, args...)
+	if err != nil {
+		return nil, "", err
+	}
+	if links == nil {
+		links = []models.Link{}
+	}
+
+	if len(links) <= limit {
+		return links, "", nil
+	}
+	links = links[:limit]
+	return links, encodeCursor(cursor{ID: links[limit-1].ID}), nil
+}
+
+// ListByAlias returns semua link dengan alias tersebut, case-insensitive (satu per domain)
+func (r *LinkRepository) ListByAlias(ctx context.Context, alias string) ([]models.Link, error) {
+	return queryData[models.Link](ctx, r.db, `SELECT data FROM links WHERE alias_lower = ? ORDER BY id`, strings.ToLower(alias))
+}
+
+// FindByAlias returns link yang paling cocok untuk request (domain, alias)
+func (r *LinkRepository) FindByAlias(ctx context.Context, domain, alias string) (*models.Link, error) {
+	links, err := r.ListByAlias(ctx, alias)
+	if err != nil {
+		return nil, err
+	}
+	return models.PickForDomain(links, domain), nil
+}
+
+// GetByAlias returns link dengan alias di domain tersebut (tanpa filter isActive)
+func (r *LinkRepository) GetByAlias(ctx context.Context, domain, alias string) (*models.Link, error) {
+	links, err := r.ListByAlias(ctx, alias)
+	if err != nil {
+		return nil, err
+	}
+	return repository.LinkForDomain(links, domain), nil
+}
+
+// GetByKey returns link dengan DataKey = key
+func (r *LinkRepository) GetByKey(ctx context.Context, key string) (*models.Link, error) {
+	_, alias := models.SplitLinkKey(key)
+	links, err := r.ListByAlias(ctx, alias)
+	if err != nil {
+		return nil, err
+	}
+	return repository.LinkForKey(links, key), nil
+}
+
+// ListChangedSince returns links whose version is greater than since (since=0 → semua link)
+func (r *LinkRepository) ListChangedSince(ctx context.Context, since int64) ([]models.Link, error) {
+	return queryData[models.Link](ctx, r.db, `SELECT data FROM links WHERE version > ?`, since)
+}
+
+// ListDeletedSince returns tombstones of links deleted after since
+func (r *LinkRepository) ListDeletedSince(ctx context.Context, since int64) ([]models.LinkTombstone, error) {
+	// Tombstone kadaluarsa dibersihkan di sini (pengganti TTL DynamoDB)
+	if _, err := r.db.exec(ctx, `DELETE FROM link_tombstones WHERE expires_at < ?`, time.Now().Unix()); err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.conn().QueryContext(ctx, `SELECT id, alias, version, expires_at FROM link_tombstones WHERE version > ?`, since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tombstones []models.LinkTombstone
+	for rows.Next() {
+		var t models.LinkTombstone
+		if err := rows.Scan(&t.ID, &t.Alias, &t.Version, &t.ExpiresAt); err != nil {
+			return nil, err
+		}
+		tombstones = append(tombstones, t)
+	}
+	return tombstones, rows.Err()
+}
@@ -0,0 +1,118 @@
+package sqlstore
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/afuzapratama/nexuslink/internal/models"
+	"github.com/afuzapratama/nexuslink/internal/repository"
+)
+
+type LinkRevisionRepository struct {
+	db *DB
+}
+
+func NewLinkRevisionRepository(db *DB) *LinkRevisionRepository {
+	return &LinkRevisionRepository{db: db}
+}
+
+// Commit: cek revision link di store, simpan snapshot, tulis link (+ variant) dalam satu transaksi
+func (r *LinkRevisionRepository) Commit(ctx context.Context, link *models.Link, prev *models.LinkRevision, variants []models.LinkVariant) error {
+	link.UpdatedAt = time.Now().UTC()
+	link.Version = link.UpdatedAt.UnixNano()
+	link.Revision = prev.Revision + 1
+
+	revData, err := encode(prev)
+	if err != nil {
+		return err
+	}
+	linkData, err := encode(link)
+	if err != nil {
+		return err
+	}
+
+	return r.db.withTx(ctx, func(q querier) error {
+		var data string
+		err := q.QueryRowContext(ctx, `SELECT data FROM links WHERE id = ?`, link.ID).Scan(&data)
+		if isNoRows(err) {
+			return repository.ErrRevisionConflict
+		}
+		if err != nil {
+			return err
+		}
+		var stored models.Link
+		if err := decode(data, &stored); err != nil {
+			return err
+		}
+		if stored.CurrentRevision() != prev.Revision {
+			return repository.ErrRevisionConflict
+		}
+
+		if _, err := q.ExecContext(ctx, `INSERT INTO link_revisions (link_id, revision, data) VALUES (?, ?, ?)`,
+			prev.LinkID, prev.Revision, revData); err != nil {
+			return err
+		}
+		if _, err := q.ExecContext(ctx, `UPDATE links SET alias = ?, alias_lower = ?, group_id = ?, version = ?, data = ? WHERE id = ?`,
+			link.Alias, strings.ToLower(link.Alias), link.GroupID, link.Version, linkData, link.ID); err != nil {
+			return err
+		}
+
+		if variants == nil {
+			return nil
+		}
+		return replaceVariantConfigs(ctx, q, link.DataKey(), variants)
+	})
+}
+
+// replaceVariantConfigs mengganti konfigurasi variant link; kolom clicks/conversions tidak disentuh
+func replaceVariantConfigs(ctx context.Context, q querier, alias string, variants []models.LinkVariant) error {
+	now := time.Now()
+	keep := make([]interface{}, 0, len(variants)+1)
+	keep = append(keep, alias)
+	placeholders := ""
+	for _, v := range variants {
+		v.LinkID = alias
+		v.Clicks, v.Conversions = 0, 0
+		v.UpdatedAt = now
+		data, err := encode(v)
+		if err != nil {
+			return err
+		}
+		if _, err := q.ExecContext(ctx, `
+			INSERT INTO link_variants (link_id, id, clicks, conversions, data) VALUES (?, ?, 0, 0, ?)
+			ON CONFLICT (link_id, id) DO UPDATE SET data = excluded.data`,
+			alias, v.ID, data); err != nil {
+			return err
+		}
+		keep = append(keep, v.ID)
+		if placeholders != "" {
+			placeholders += ", "
+		}
+		placeholders += "?"
+	}
+
+	query := `DELETE FROM link_variants WHERE link_id = ?`
+	if placeholders != "" {
+		query += ` AND id NOT IN (` + placeholders + `)`
+	}
+	_, err := q.ExecContext(ctx, query, keep...)
+	return err
+}
+
+// List returns semua snapshot link, revision terbaru dulu
+func (r *LinkRevisionRepository) List(ctx context.Context, linkID string) ([]models.LinkRevision, error) {
+	return queryData[models.LinkRevision](ctx, r.db,
+		`SELECT data FROM link_revisions WHERE link_id = ? ORDER BY revision DESC`, linkID)
+}
+
+func (r *LinkRevisionRepository) Get(ctx context.Context, linkID string, revision int) (*models.LinkRevision, error) {
+	return getData[models.LinkRevision](ctx, r.db,
+		`SELECT data FROM link_revisions WHERE link_id = ? AND revision = ?`, linkID, revision)
+}
+
+// DeleteByLink menghapus semua snapshot (dipanggil saat link dihapus)
+func (r *LinkRevisionRepository) DeleteByLink(ctx context.Context, linkID string) error {
+	_, err := r.db.exec(ctx, `DELETE FROM link_revisions WHERE link_id = ?`, linkID)
+	return err
+}
@@ -0,0 +1,69 @@
+package sqlstore
+
+import (
+	"context"
+	"time"
+
+	"github.com/afuzapratama/nexuslink/internal/models"
+)
+
+type LinkStatsRepository struct {
+	db *DB
+}
+
+func NewLinkStatsRepository(db *DB) *LinkStatsRepository {
+	return &LinkStatsRepository{db: db}
+}
+
+// IncrementHit menambah hitCount untuk kombinasi nodeId + alias (key "nodeId#alias")
+func (r *LinkStatsRepository) IncrementHit(ctx context.Context, nodeID, alias string) error {
+	if nodeID == "" || alias == "" {
+		return nil
+	}
+
+	_, err := r.db.exec(ctx, `
+		INSERT INTO link_stats (id, node_id, alias, hit_count, last_hit_at) VALUES (?, ?, ?, 1, ?)
+		ON CONFLICT (id) DO UPDATE SET hit_count = link_stats.hit_count + 1, last_hit_at = excluded.last_hit_at`,
+		nodeID+"#"+alias, nodeID, alias, time.Now().UTC().Format(time.RFC3339))
+	return err
+}
+
+func (r *LinkStatsRepository) Get(ctx context.Context, nodeID, alias string) (*models.LinkStat, error) {
+	if nodeID == "" || alias == "" {
+		return nil, nil
+	}
+
+	stats, err := r.query(ctx, `WHERE id = ?`, nodeID+"#"+alias)
+	if err != nil || len(stats) == 0 {
+		return nil, err
+	}
+	return &stats[0], nil
+}
+
+func (r *LinkStatsRepository) ListAll(ctx context.Context) ([]models.LinkStat, error) {
+	return r.query(ctx, ``)
+}
+
+// DeleteByLinkAlias deletes all stats records for a given link alias
+func (r *LinkStatsRepository) DeleteByLinkAlias(ctx context.Context, alias string) error {
+	_, err := r.db.exec(ctx, `DELETE FROM link_stats WHERE alias = ?`, alias)
+	return err
+}
+
+func (r *LinkStatsRepository) query(ctx context.Context, where string, args ...interface{}) ([]models.LinkStat, error) {
+	rows, err := r.db.conn().QueryContext(ctx, `SELECT id, node_id, alias, hit_count, last_hit_at FROM link_stats `+where, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var stats []models.LinkStat
+	for rows.Next() {
+		var s models.LinkStat
+		if err := rows.Scan(&s.ID, &s.NodeID, &s.Alias, &s.HitCount, &s.LastHitAt); err != nil {
+			return nil, err
+		}
+		stats = append(stats, s)
+	}
+	return stats, rows.Err()
+}
@@ -0,0 +1,136 @@
+package sqlstore
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/afuzapratama/nexuslink/internal/models"
+)
+
+// Clicks/Conversions disimpan di kolom sendiri supaya increment atomic;
+// nilai di JSON "data" diabaikan saat baca.
+type LinkVariantRepository struct {
+	db *DB
+}
+
+func NewLinkVariantRepository(db *DB) *LinkVariantRepository {
+	return &LinkVariantRepository{db: db}
+}
+
+// GetByLinkID returns all variants for a given link alias
+func (r *LinkVariantRepository) GetByLinkID(ctx context.Context, linkID string) ([]models.LinkVariant, error) {
+	return r.query(ctx, `WHERE link_id = ? ORDER BY id`, linkID)
+}
+
+// maxInArgs: batas jumlah parameter per query IN (aman untuk SQLite dan Postgres)
+const maxInArgs = 500
+
+// GetByLinkIDs returns variants untuk banyak link sekaligus (query IN per 500 link)
+func (r *LinkVariantRepository) GetByLinkIDs(ctx context.Context, linkIDs []string) (map[string][]models.LinkVariant, error) {
+	result := make(map[string][]models.LinkVariant)
+	for start := 0; start < len(linkIDs); start += maxInArgs {
+		end := start + maxInArgs
+		if end > len(linkIDs) {
+			end = len(linkIDs)
+		}
+		chunk := linkIDs[start:end]
+
+		args := make([]interface{}, len(chunk))
+		for i, id := range chunk {
+			args[i] = id
+		}
+		placeholders := strings.TrimSuffix(strings.Repeat("?,", len(chunk)), ",")
+
+		variants, err := r.query(ctx, `WHERE link_id IN (`+placeholders+`) ORDER BY link_id, id`, args...)
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range variants {
+			result[v.LinkID] = append(result[v.LinkID], v)
+		}
+	}
+	return result, nil
+}
+
+// GetByID retrieves a specific variant by linkID and variantID
+func (r *LinkVariantRepository) GetByID(ctx context.Context, linkID, variantID string) (*models.LinkVariant, error) {
+	variants, err := r.query(ctx, `WHERE link_id = ? AND id = ?`, linkID, variantID)
+	if err != nil || len(variants) == 0 {
+		return nil, err
+	}
+	return &variants[0], nil
+}
+
+// Create adds a new variant
+func (r *LinkVariantRepository) Create(ctx context.Context, variant *models.LinkVariant) error {
+	variant.CreatedAt = time.Now()
+	variant.UpdatedAt = time.Now()
+
+	return r.put(ctx, variant)
+}
+
+// Update modifies an existing variant
+func (r *LinkVariantRepository) Update(ctx context.Context, variant *models.LinkVariant) error {
+	variant.UpdatedAt = time.Now()
+
+	return r.put(ctx, variant)
+}
+
+func (r *LinkVariantRepository) put(ctx context.Context, variant *models.LinkVariant) error {
+	data, err := encode(variant)
+	if err != nil {
+		return err
+	}
+	_, err = r.db.exec(ctx, `
+		INSERT INTO link_variants (link_id, id, clicks, conversions, data) VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT (link_id, id) DO UPDATE SET clicks = excluded.clicks, conversions = excluded.conversions, data = excluded.data`,
+		variant.LinkID, variant.ID, variant.Clicks, variant.Conversions, data)
+	return err
+}
+
+// Delete removes a variant
+func (r *LinkVariantRepository) Delete(ctx context.Context, linkID, variantID string) error {
+	_, err := r.db.exec(ctx, `DELETE FROM link_variants WHERE link_id = ? AND id = ?`, linkID, variantID)
+	return err
+}
+
+// IncrementClicks atomically increments the click count for a variant
+func (r *LinkVariantRepository) IncrementClicks(ctx context.Context, linkID, variantID string) error {
+	_, err := r.db.exec(ctx, `UPDATE link_variants SET clicks = clicks + 1 WHERE link_id = ? AND id = ?`, linkID, variantID)
+	return err
+}
+
+// IncrementConversions atomically increments the conversion count for a variant
+func (r *LinkVariantRepository) IncrementConversions(ctx context.Context, linkID, variantID string) error {
+	_, err := r.db.exec(ctx, `UPDATE link_variants SET conversions = conversions + 1 WHERE link_id = ? AND id = ?`, linkID, variantID)
+	return err
+}
+
+func (r *LinkVariantRepository) query(ctx context.Context, where string, args ...interface{}) ([]models.LinkVariant, error) {
+	rows, err := r.db.conn().QueryContext(ctx, `SELECT clicks, conversions, data FROM link_variants `+where, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var variants []models.LinkVariant
+	for rows.Next() {
+		var (
+			clicks, conversions int64
+			data                string
+		)
+		if err := rows.Scan(&clicks, &conversions, &data); err != nil {
+			return nil, err
+		}
+
+		var v models.LinkVariant
+		if err := decode(data, &v); err != nil {
+			return nil, err
+		}
+		v.Clicks = clicks
+		v.Conversions = conversions
+		variants = append(variants, v)
+	}
+	return variants, rows.Err()
+}
@@ -0,0 +1,258 @@
+package sqlstore
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+)
+
+// migrations dijalankan berurutan; index di slice + 1 adalah nomor versi.
+// Jangan ubah migrasi yang sudah rilis, tambahkan migrasi baru di akhir.
+// SQL di sini harus jalan di SQLite dan PostgreSQL.
+var migrations = []string{
+	// 1: schema awal
+	`
+CREATE TABLE links (
+	id       TEXT PRIMARY KEY,
+	alias    TEXT NOT NULL,
+	group_id TEXT NOT NULL DEFAULT '',
+	version  BIGINT NOT NULL,
+	data     TEXT NOT NULL
+);
+CREATE INDEX links_alias_idx ON links (alias);
+CREATE INDEX links_version_idx ON links (version);
+
+CREATE TABLE link_tombstones (
+	id         TEXT PRIMARY KEY,
+	alias      TEXT NOT NULL,
+	version    BIGINT NOT NULL,
+	expires_at BIGINT NOT NULL
+);
+CREATE INDEX link_tombstones_version_idx ON link_tombstones (version);
+
+CREATE TABLE nodes (
+	id   TEXT PRIMARY KEY,
+	data TEXT NOT NULL
+);
+
+CREATE TABLE node_domains (
+	domain  TEXT PRIMARY KEY,
+	node_id TEXT NOT NULL
+);
+
+CREATE TABLE node_tokens (
+	token TEXT PRIMARY KEY,
+	data  TEXT NOT NULL
+);
+
+CREATE TABLE click_events (
+	id         TEXT PRIMARY KEY,
+	alias      TEXT NOT NULL,
+	created_at BIGINT NOT NULL,
+	data       TEXT NOT NULL
+);
+CREATE INDEX click_events_alias_created_idx ON click_events (alias, created_at, id);
+
+CREATE TABLE link_stats (
+	id          TEXT PRIMARY KEY,
+	node_id     TEXT NOT NULL,
+	alias       TEXT NOT NULL,
+	hit_count   BIGINT NOT NULL DEFAULT 0,
+	last_hit_at TEXT NOT NULL
+);
+CREATE INDEX link_stats_alias_idx ON link_stats (alias);
+
+CREATE TABLE settings (
+	id   TEXT PRIMARY KEY,
+	data TEXT NOT NULL
+);
+
+CREATE TABLE link_groups (
+	id         TEXT PRIMARY KEY,
+	sort_order INTEGER NOT NULL DEFAULT 0,
+	data       TEXT NOT NULL
+);
+
+CREATE TABLE webhooks (
+	id   TEXT PRIMARY KEY,
+	data TEXT NOT NULL
+);
+
+CREATE TABLE link_variants (
+	link_id     TEXT NOT NULL,
+	id          TEXT NOT NULL,
+	clicks      BIGINT NOT NULL DEFAULT 0,
+	conversions BIGINT NOT NULL DEFAULT 0,
+	data        TEXT NOT NULL,
+	PRIMARY KEY (link_id, id)
+);
+
+CREATE TABLE click_rollups (
+	pk              TEXT NOT NULL,
+	sk              TEXT NOT NULL,
+	alias           TEXT NOT NULL,
+	granularity     TEXT NOT NULL,
+	dimension       TEXT NOT NULL,
+	dimension_value TEXT NOT NULL,
+	bucket          TEXT NOT NULL,
+	hit_count       BIGINT NOT NULL DEFAULT 0,
+	PRIMARY KEY (pk, sk)
+);
+`,
+	// 2: riwayat online/offline node
+	`
+CREATE TABLE node_events (
+	node_id TEXT NOT NULL,
+	at      BIGINT NOT NULL,
+	data    TEXT NOT NULL,
+	PRIMARY KEY (node_id, at)
+);
+`,
+	// 3: API key per node
+	`
+CREATE TABLE node_credentials (
+	node_id TEXT PRIMARY KEY,
+	data    TEXT NOT NULL
+);
+`,
+	// 4: user admin multi-akun + session persisten
+	`
+CREATE TABLE users (
+	id       TEXT PRIMARY KEY,
+	username TEXT NOT NULL UNIQUE,
+	data     TEXT NOT NULL
+);
+
+CREATE TABLE sessions (
+	token_hash TEXT PRIMARY KEY,
+	user_id    TEXT NOT NULL,
+	expires_at BIGINT NOT NULL,
+	data       TEXT NOT NULL
+);
+CREATE INDEX sessions_user_idx ON sessions (user_id);
+`,
+	// 5: audit log perubahan admin (append-only)
+	`
+CREATE TABLE audit_log (
+	id          TEXT PRIMARY KEY,
+	at          BIGINT NOT NULL,
+	actor       TEXT NOT NULL,
+	action      TEXT NOT NULL,
+	target_type TEXT NOT NULL,
+	target_id   TEXT NOT NULL,
+	data        TEXT NOT NULL
+);
+CREATE INDEX audit_log_at_idx ON audit_log (at);
+CREATE INDEX audit_log_target_idx ON audit_log (target_type, target_id, at);
+`,
+	// 6: snapshot versi lama link
+	`
+CREATE TABLE link_revisions (
+	link_id  TEXT NOT NULL,
+	revision INTEGER NOT NULL,
+	data     TEXT NOT NULL,
+	PRIMARY KEY (link_id, revision)
+);
+`,
+	// 7: reservasi alias unik (case-insensitive) + counter alias sequential.
+	// Alias lama yang bentrok case-insensitive: yang pertama menang.
+	`
+CREATE TABLE link_aliases (
+	alias_key TEXT PRIMARY KEY,
+	link_id   TEXT NOT NULL
+);
+INSERT INTO link_aliases (alias_key, link_id)
+	SELECT LOWER(alias), id FROM links WHERE alias <> '' ORDER BY id
+	ON CONFLICT (alias_key) DO NOTHING;
+CREATE TABLE alias_sequences (
+	scope TEXT PRIMARY KEY,
+	value BIGINT NOT NULL
+);
+`,
+	// 8: log delivery webhook + dead letter
+	`
+CREATE TABLE webhook_deliveries (
+	id         TEXT PRIMARY KEY,
+	webhook_id TEXT NOT NULL,
+	created_at BIGINT NOT NULL,
+	status     TEXT NOT NULL,
+	data       TEXT NOT NULL
+);
+CREATE INDEX webhook_deliveries_webhook_idx ON webhook_deliveries (webhook_id, created_at);
+CREATE INDEX webhook_deliveries_created_idx ON webhook_deliveries (created_at);
+
+CREATE TABLE webhook_dead_letters (
+	id         TEXT PRIMARY KEY,
+	webhook_id TEXT NOT NULL,
+	created_at BIGINT NOT NULL,
+	data       TEXT NOT NULL
+);
+CREATE INDEX webhook_dead_letters_webhook_idx ON webhook_dead_letters (webhook_id, created_at);
+`,
+	// 9: outbox event webhook (dikirim worker, tahan restart)
+	`
+CREATE TABLE webhook_outbox (
+	id              TEXT PRIMARY KEY,
+	next_attempt_at BIGINT NOT NULL,
+	data            TEXT NOT NULL
+);
+CREATE INDEX webhook_outbox_due_idx ON webhook_outbox (next_attempt_at);
+`,
+	// 10: ID click dari agent yang sudah diproses (retry /sync/clicks tidak dihitung dua kali)
+	`
+CREATE TABLE click_dedup (
+	id         TEXT PRIMARY KEY,
+	expires_at BIGINT NOT NULL
+);
+CREATE INDEX click_dedup_expires_idx ON click_dedup (expires_at);
+`,
+	// 11: alias lowercase untuk lookup case-insensitive (sama dengan reservasi alias).
+	// LOWER() hanya ASCII di SQLite; BackfillAliases menyamakan sisanya dengan strings.ToLower.
+	`
+ALTER TABLE links ADD COLUMN alias_lower TEXT NOT NULL DEFAULT '';
+UPDATE links SET alias_lower = LOWER(alias);
+CREATE INDEX links_alias_lower_idx ON links (alias_lower);
+`,
+}
+
+func (db *DB) migrate(ctx context.Context) error {
+	if _, err := db.exec(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (version INTEGER PRIMARY KEY)`); err != nil {
+		return err
+	}
+
+	var current int
+	if err := db.queryRow(ctx, `SELECT COALESCE(MAX(version), 0) FROM schema_migrations`).Scan(&current); err != nil {
+		return err
+	}
+
+	for i := current; i < len(migrations); i++ {
+		version := i + 1
+		err := db.withTx(ctx, func(q querier) error {
+			for _, stmt := range splitStatements(migrations[i]) {
+				if _, err := q.ExecContext(ctx, stmt); err != nil {
+					return err
+				}
+			}
+			_, err := q.ExecContext(ctx, `INSERT INTO schema_migrations (version) VALUES (?)`, version)
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("migration %d: %w", version, err)
+		}
+		log.Printf("sqlstore: applied migration %d", version)
+	}
+
+	return nil
+}
+
+// splitStatements memecah migrasi per ";" (migrasi kita tidak punya ";" di dalam string)
+func splitStatements(migration string) []string {
+	var stmts []string
+	for _, s := range strings.Split(migration, ";") {
+		if s = strings.TrimSpace(s); s != "" {
+			stmts = append(stmts, s)
+		}
+	}
+	return stmts
+}
@@ -0,0 +1,35 @@
+package sqlstore
+
+import (
+	"context"
+
+	"github.com/afuzapratama/nexuslink/internal/models"
+)
+
+type NodeCredentialRepository struct {
+	db *DB
+}
+
+func NewNodeCredentialRepository(db *DB) *NodeCredentialRepository {
+	return &NodeCredentialRepository{db: db}
+}
+
+func (r *NodeCredentialRepository) Get(ctx context.Context, nodeID string) (*models.NodeCredential, error) {
+	return getData[models.NodeCredential](ctx, r.db, `SELECT data FROM node_credentials WHERE node_id = ?`, nodeID)
+}
+
+func (r *NodeCredentialRepository) Put(ctx context.Context, cred *models.NodeCredential) error {
+	data, err := encode(cred)
+	if err != nil {
+		return err
+	}
+	_, err = r.db.exec(ctx, `
+		INSERT INTO node_credentials (node_id, data) VALUES (?, ?)
+		ON CONFLICT (node_id) DO UPDATE SET data = excluded.data`, cred.NodeID, data)
+	return err
+}
+
+func (r *NodeCredentialRepository) Delete(ctx context.Context, nodeID string) error {
+	_, err := r.db.exec(ctx, `DELETE FROM node_credentials WHERE node_id = ?`, nodeID)
+	return err
+}
@@ -0,0 +1,50 @@
+package sqlstore
+
+import (
+	"context"
+	"time"
+
+	"github.com/afuzapratama/nexuslink/internal/models"
+)
+
+type NodeEventRepository struct {
+	db *DB
+}
+
+func NewNodeEventRepository(db *DB) *NodeEventRepository {
+	return &NodeEventRepository{db: db}
+}
+
+// Add menyimpan satu transisi node; event yang lewat retensi ikut dibersihkan
+// (transisi jarang terjadi, jadi prune di sini cukup murah)
+func (r *NodeEventRepository) Add(ctx context.Context, ev *models.NodeEvent) error {
+	data, err := encode(ev)
+	if err != nil {
+		return err
+	}
+
+	return r.db.withTx(ctx, func(q querier) error {
+		_, err := q.ExecContext(ctx, `
+			INSERT INTO node_events (node_id, at, data) VALUES (?, ?, ?)
+			ON CONFLICT (node_id, at) DO UPDATE SET data = excluded.data`,
+			ev.NodeID, ev.At.UnixNano(), data)
+		if err != nil {
+			return err
+		}
+		_, err = q.ExecContext(ctx, `DELETE FROM node_events WHERE node_id = ? AND at < ?`,
+			ev.NodeID, ev.At.Add(-models.NodeEventRetention).UnixNano())
+		return err
+	})
+}
+
+// ListSince returns event node sejak waktu tertentu, urut dari yang terlama
+func (r *NodeEventRepository) ListSince(ctx context.Context, nodeID string, since time.Time) ([]models.NodeEvent, error) {
+	return queryData[models.NodeEvent](ctx, r.db,
+		`SELECT data FROM node_events WHERE node_id = ? AND at >= ? ORDER BY at`, nodeID, since.UnixNano())
+}
+
+// Latest returns event terakhir sebelum waktu tertentu (nil kalau belum ada)
+func (r *NodeEventRepository) Latest(ctx context.Context, nodeID string, before time.Time) (*models.NodeEvent, error) {
+	return getData[models.NodeEvent](ctx, r.db,
+		`SELECT data FROM node_events WHERE node_id = ? AND at < ? ORDER BY at DESC LIMIT 1`, nodeID, before.UnixNano())
+}
@@ -0,0 +1,192 @@
+package sqlstore
+
+import (
+	"context"
+	"time"
+
+	"github.com/afuzapratama/nexuslink/internal/models"
+)
+
+type NodeRepository struct {
+	db *DB
+}
+
+func NewNodeRepository(db *DB) *NodeRepository {
+	return &NodeRepository{db: db}
+}
+
+// UpsertNode: dipakai untuk heartbeat/registrasi, sekaligus update mapping domain
+func (r *NodeRepository) UpsertNode(ctx context.Context, n *models.Node) error {
+	n.LastSeenAt = time.Now().UTC()
+	n.IsOnline = true
+	n.DomainsVersion = n.LastSeenAt.UnixNano()
+
+	return r.db.withTx(ctx, func(q querier) error {
+		if err := putNode(ctx, q, n); err != nil {
+			return err
+		}
+		for _, d := range n.Domains {
+			if err := mapDomain(ctx, q, d, n.ID); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// UpdateHeartbeat updates only the heartbeat fields of a node
+func (r *NodeRepository) UpdateHeartbeat(ctx context.Context, id string, agentVersion string, ipAddress string) error {
+	return r.db.withTx(ctx, func(q querier) error {
+		node, err := getNode(ctx, q, id)
+		if err != nil {
+			return err
+		}
+		if node == nil {
+			// Sama seperti UpdateItem DynamoDB: item baru hanya berisi field heartbeat
+			node = &models.Node{ID: id}
+		}
+
+		node.LastSeenAt = time.Now().UTC()
+		node.IsOnline = true
+		node.AgentVersion = agentVersion
+		node.IPAddress = ipAddress
+		return putNode(ctx, q, node)
+	})
+}
+
+// MarkOffline set IsOnline = false kalau belum ada heartbeat baru sejak lastSeenAt
+func (r *NodeRepository) MarkOffline(ctx context.Context, id string, lastSeenAt time.Time) error {
+	return r.db.withTx(ctx, func(q querier) error {
+		node, err := getNode(ctx, q, id)
+		if err != nil || node == nil {
+			return err
+		}
+		if !node.LastSeenAt.Equal(lastSeenAt) || !node.IsOnline {
+			return nil
+		}
+
+		node.IsOnline = false
+		return putNode(ctx, q, node)
+	})
+}
+
+// SetWeight mengubah bobot steering node
+func (r *NodeRepository) SetWeight(ctx context.Context, id string, weight int) error {
+	return r.db.withTx(ctx, func(q querier) error {
+		node, err := getNode(ctx, q, id)
+		if err != nil || node == nil {
+			return err
+		}
+
+		node.Weight = weight
+		return putNode(ctx, q, node)
+	})
+}
+
+func (r *NodeRepository) List(ctx context.Context) ([]models.Node, error) {
+	return queryData[models.Node](ctx, r.db, `SELECT data FROM nodes ORDER BY id`)
+}
+
+func (r *NodeRepository) GetByID(ctx context.Context, id string) (*models.Node, error) {
+	return getData[models.Node](ctx, r.db, `SELECT data FROM nodes WHERE id = ?`, id)
+}
+
+func (r *NodeRepository) Delete(ctx context.Context, id string) error {
+	return r.db.withTx(ctx, func(q querier) error {
+		if _, err := q.ExecContext(ctx, `DELETE FROM node_domains WHERE node_id = ?`, id); err != nil {
+			return err
+		}
+		_, err := q.ExecContext(ctx, `DELETE FROM nodes WHERE id = ?`, id)
+		return err
+	})
+}
+
+// AddDomain adds a new domain to a node's domain list
+func (r *NodeRepository) AddDomain(ctx context.Context, nodeID, domain string) error {
+	return r.db.withTx(ctx, func(q querier) error {
+		node, err := getNode(ctx, q, nodeID)
+		if err != nil || node == nil {
+			return err
+		}
+
+		for _, d := range node.Domains {
+			if d == domain {
+				return nil // Already exists
+			}
+		}
+		node.Domains = append(node.Domains, domain)
+		node.DomainsVersion = time.Now().UTC().UnixNano()
+
+		if err := putNode(ctx, q, node); err != nil {
+			return err
+		}
+		return mapDomain(ctx, q, domain, nodeID)
+	})
+}
+
+// RemoveDomain removes a domain from a node's domain list
+func (r *NodeRepository) RemoveDomain(ctx context.Context, nodeID, domain string) error {
+	return r.db.withTx(ctx, func(q querier) error {
+		node, err := getNode(ctx, q, nodeID)
+		if err != nil || node == nil {
+			return err
+		}
+
+		newDomains := []string{}
+		for _, d := range node.Domains {
+			if d != domain {
+				newDomains = append(newDomains, d)
+			}
+		}
+		node.Domains = newDomains
+		node.DomainsVersion = time.Now().UTC().UnixNano()
+
+		if err := putNode(ctx, q, node); err != nil {
+			return err
+		}
+		// Hanya hapus mapping kalau masih milik node ini
+		_, err = q.ExecContext(ctx, `DELETE FROM node_domains WHERE domain = ? AND node_id = ?`, domain, nodeID)
+		return err
+	})
+}
+
+// GetNodeByDomain finds a node that serves a specific domain
+func (r *NodeRepository) GetNodeByDomain(ctx context.Context, domain string) (*models.Node, error) {
+	return getData[models.Node](ctx, r.db, `
+		SELECT n.data FROM node_domains d JOIN nodes n ON n.id = d.node_id WHERE d.domain = ?`, domain)
+}
+
+func getNode(ctx context.Context, q querier, id string) (*models.Node, error) {
+	var data string
+	err := q.QueryRowContext(ctx, `SELECT data FROM nodes WHERE id = ?`, id).Scan(&data)
+	if isNoRows(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var node models.Node
+	if err := decode(data, &node); err != nil {
+		return nil, err
+	}
+	return &node, nil
+}
+
+func putNode(ctx context.Context, q querier, n *models.Node) error {
+	data, err := encode(n)
+	if err != nil {
+		return err
+	}
+	_, err = q.ExecContext(ctx, `
+		INSERT INTO nodes (id, data) VALUES (?, ?)
+		ON CONFLICT (id) DO UPDATE SET data = excluded.data`, n.ID, data)
+	return err
+}
+
+func mapDomain(ctx context.Context, q querier, domain, nodeID string) error {
+	_, err := q.ExecContext(ctx, `
+		INSERT INTO node_domains (domain, node_id) VALUES (?, ?)
+		ON CONFLICT (domain) DO UPDATE SET node_id = excluded.node_id`, domain, nodeID)
+	return err
+}
@@ -0,0 +1,91 @@
+package sqlstore
+
+import (
+	"context"
+	"time"
+
+	"github.com/afuzapratama/nexuslink/internal/models"
+	"github.com/afuzapratama/nexuslink/internal/repository"
+)
+
+type NodeTokenRepository struct {
+	db *DB
+}
+
+func NewNodeTokenRepository(db *DB) *NodeTokenRepository {
+	return &NodeTokenRepository{db: db}
+}
+
+func (r *NodeTokenRepository) Create(ctx context.Context, label string, token string) (*models.NodeToken, error) {
+	nt := &models.NodeToken{
+		Token:     token,
+		Label:     label,
+		CreatedAt: time.Now().UTC(),
+		IsUsed:    false,
+	}
+	if err := r.put(ctx, r.db.conn(), nt); err != nil {
+		return nil, err
+	}
+	return nt, nil
+}
+
+func (r *NodeTokenRepository) Get(ctx context.Context, token string) (*models.NodeToken, error) {
+	return getData[models.NodeToken](ctx, r.db, `SELECT data FROM node_tokens WHERE token = ?`, token)
+}
+
+func (r *NodeTokenRepository) List(ctx context.Context) ([]models.NodeToken, error) {
+	return queryData[models.NodeToken](ctx, r.db, `SELECT data FROM node_tokens`)
+}
+
+// MarkUsed meng-klaim token sekali pakai: UPDATE bersyarat data lama (compare-and-swap),
+// jadi dua registrasi paralel dengan token yang sama hanya satu yang berhasil.
+// Token yang sudah dipakai / kedaluwarsa / tidak ada → repository.ErrTokenUnavailable
+func (r *NodeTokenRepository) MarkUsed(ctx context.Context, token string) error {
+	var data string
+	err := r.db.queryRow(ctx, `SELECT data FROM node_tokens WHERE token = ?`, token).Scan(&data)
+	if isNoRows(err) {
+		return repository.ErrTokenUnavailable
+	}
+	if err != nil {
+		return err
+	}
+
+	var nt models.NodeToken
+	if err := decode(data, &nt); err != nil {
+		return err
+	}
+	now := time.Now().UTC()
+	if !nt.Usable(now) {
+		return repository.ErrTokenUnavailable
+	}
+	nt.IsUsed = true
+	nt.UsedAt = now
+
+	updated, err := encode(&nt)
+	if err != nil {
+		return err
+	}
+	res, err := r.db.exec(ctx, `UPDATE node_tokens SET data = ? WHERE token = ? AND data = ?`, updated, token, data)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n != 1 {
+		return repository.ErrTokenUnavailable
+	}
+	return nil
+}
+
+func (r *NodeTokenRepository) put(ctx context.Context, q querier, nt *models.NodeToken) error {
+	data, err := encode(nt)
+	if err != nil {
+		return err
+	}
+	_, err = q.ExecContext(ctx, `
+		INSERT INTO node_tokens (token, data) VALUES (?, ?)
+		ON CONFLICT (token) DO UPDATE SET data = excluded.data`, nt.Token, data)
+	return err
+}
@@ -0,0 +1,54 @@
+package sqlstore
+
+import (
+	"context"
+
+	"github.com/afuzapratama/nexuslink/internal/models"
+)
+
+type RollupRepository struct {
+	db *DB
+}
+
+func NewRollupRepository(db *DB) *RollupRepository {
+	return &RollupRepository{db: db}
+}
+
+// Add menambahkan Count setiap rollup ke counter (upsert, satu transaksi)
+func (r *RollupRepository) Add(ctx context.Context, rollups []models.ClickRollup) error {
+	return r.db.withTx(ctx, func(q querier) error {
+		for _, ru := range rollups {
+			_, err := q.ExecContext(ctx, `
+				INSERT INTO click_rollups (pk, sk, alias, granularity, dimension, dimension_value, bucket, hit_count)
+				VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+				ON CONFLICT (pk, sk) DO UPDATE SET hit_count = click_rollups.hit_count + excluded.hit_count`,
+				ru.PK, ru.SK, ru.Alias, ru.Granularity, ru.Dimension, ru.Value, ru.Bucket, ru.Count)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Query returns rollup rows satu dimensi dengan bucket di antara fromBucket dan toBucket (inklusif)
+func (r *RollupRepository) Query(ctx context.Context, pk, fromBucket, toBucket string) ([]models.ClickRollup, error) {
+	rows, err := r.db.conn().QueryContext(ctx, `
+		SELECT pk, sk, alias, granularity, dimension, dimension_value, bucket, hit_count
+		FROM click_rollups WHERE pk = ? AND bucket BETWEEN ? AND ? ORDER BY sk`,
+		pk, fromBucket, toBucket)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var rollups []models.ClickRollup
+	for rows.Next() {
+		var ru models.ClickRollup
+		if err := rows.Scan(&ru.PK, &ru.SK, &ru.Alias, &ru.Granularity, &ru.Dimension, &ru.Value, &ru.Bucket, &ru.Count); err != nil {
+			return nil, err
+		}
+		rollups = append(rollups, ru)
+	}
+	return rollups, rows.Err()
+}
@@ -0,0 +1,68 @@
+package sqlstore
+
+import (
+	"context"
+	"time"
+
+	"github.com/afuzapratama/nexuslink/internal/models"
+)
+
+type SessionRepository struct {
+	db *DB
+}
+
+func NewSessionRepository(db *DB) *SessionRepository {
+	return &SessionRepository{db: db}
+}
+
+// Create menyimpan session baru; session yang sudah expired ikut dibersihkan
+// (login jarang terjadi, jadi prune di sini cukup murah)
+func (r *SessionRepository) Create(ctx context.Context, s *models.Session) error {
+	data, err := encode(s)
+	if err != nil {
+		return err
+	}
+
+	return r.db.withTx(ctx, func(q querier) error {
+		_, err := q.ExecContext(ctx, `
+			INSERT INTO sessions (token_hash, user_id, expires_at, data) VALUES (?, ?, ?, ?)
+			ON CONFLICT (token_hash) DO UPDATE SET user_id = excluded.user_id, expires_at = excluded.expires_at, data = excluded.data`,
+			s.TokenHash, s.UserID, s.ExpiresAt.Unix(), data)
+		if err != nil {
+			return err
+		}
+		_, err = q.ExecContext(ctx, `DELETE FROM sessions WHERE expires_at < ?`, time.Now().Unix())
+		return err
+	})
+}
+
+// Get returns nil untuk session yang tidak ada atau sudah expired
+func (r *SessionRepository) Get(ctx context.Context, tokenHash string) (*models.Session, error) {
+	return getData[models.Session](ctx, r.db,
+		`SELECT data FROM sessions WHERE token_hash = ? AND expires_at > ?`, tokenHash, time.Now().Unix())
+}
+
+func (r *SessionRepository) Touch(ctx context.Context, tokenHash string, expiresAt time.Time) error {
+	s, err := getData[models.Session](ctx, r.db, `SELECT data FROM sessions WHERE token_hash = ?`, tokenHash)
+	if err != nil || s == nil {
+		return err
+	}
+	s.ExpiresAt = expiresAt
+	data, err := encode(s)
+	if err != nil {
+		return err
+	}
+	_, err = r.db.exec(ctx, `UPDATE sessions SET expires_at = ?, data = ? WHERE token_hash = ?`,
+		expiresAt.Unix(), data, tokenHash)
+	return err
+}
+
+func (r *SessionRepository) Delete(ctx context.Context, tokenHash string) error {
+	_, err := r.db.exec(ctx, `DELETE FROM sessions WHERE token_hash = ?`, tokenHash)
+	return err
+}
+
+func (r *SessionRepository) DeleteByUser(ctx context.Context, userID string) error {
+	_, err := r.db.exec(ctx, `DELETE FROM sessions WHERE user_id = ?`, userID)
+	return err
+}
@@ -0,0 +1,53 @@
+package sqlstore
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/afuzapratama/nexuslink/internal/models"
+)
+
+type SettingsRepository struct {
+	db *DB
+}
+
+func NewSettingsRepository(db *DB) *SettingsRepository {
+	return &SettingsRepository{db: db}
+}
+
+// Get mengambil settings global (ID = "global-settings")
+func (r *SettingsRepository) Get(ctx context.Context) (*models.Settings, error) {
+	return getData[models.Settings](ctx, r.db, `SELECT data FROM settings WHERE id = ?`, "global-settings")
+}
+
+// GetOrDefault mengambil settings, jika tidak ada return default
+func (r *SettingsRepository) GetOrDefault(ctx context.Context) *models.Settings {
+	s, err := r.Get(ctx)
+	if err != nil || s == nil {
+		return models.DefaultSettings()
+	}
+	return s
+}
+
+// Update menyimpan/update settings global
+func (r *SettingsRepository) Update(ctx context.Context, settings *models.Settings) error {
+	if settings == nil {
+		return errors.New("settings cannot be nil")
+	}
+
+	settings.ID = "global-settings"
+	settings.UpdatedAt = time.Now().UTC()
+	if settings.CreatedAt.IsZero() {
+		settings.CreatedAt = settings.UpdatedAt
+	}
+
+	data, err := encode(settings)
+	if err != nil {
+		return err
+	}
+	_, err = r.db.exec(ctx, `
+		INSERT INTO settings (id, data) VALUES (?, ?)
+		ON CONFLICT (id) DO UPDATE SET data = excluded.data`, settings.ID, data)
+	return err
+}
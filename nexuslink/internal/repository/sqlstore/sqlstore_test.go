@@ -0,0 +1,710 @@
+package sqlstore
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/afuzapratama/nexuslink/internal/models"
+	"github.com/afuzapratama/nexuslink/internal/nodeauth"
+	"github.com/afuzapratama/nexuslink/internal/repository"
+)
+
+func openTestDB(t *testing.T) *DB {
+	t.Helper()
+
+	db, err := Open(context.Background(), DialectSQLite, filepath.Join(t.TempDir(), "nexus.db"))
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func TestMigrateIdempotent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nexus.db")
+	for i := 0; i < 2; i++ {
+		db, err := Open(context.Background(), DialectSQLite, path)
+		if err != nil {
+			t.Fatalf("open #%d: %v", i+1, err)
+		}
+		db.Close()
+	}
+}
+
+func TestLinkRepository(t *testing.T) {
+	ctx := context.Background()
+	repo := NewLinkRepository(openTestDB(t))
+
+	for _, alias := range []string{"a", "b", "c"} {
+		if err := repo.Create(ctx, &models.Link{Alias: alias, TargetURL: "https://example.com/" + alias, GroupID: "g1"}); err != nil {
+			t.Fatalf("create %s: %v", alias, err)
+		}
+	}
+
+	link, err := repo.GetByAlias(ctx, "", "b")
+	if err != nil || link == nil {
+		t.Fatalf("GetByAlias: link=%v err=%v", link, err)
+	}
+	if !link.IsActive || link.Version == 0 {
+		t.Errorf("expected active link with version, got %+v", link)
+	}
+
+	// Cursor pagination
+	var seen []string
+	cursor := ""
+	for {
+		page, next, err := repo.ListPage(ctx, "g1", 2, cursor)
+		if err != nil {
+			t.Fatalf("ListPage: %v", err)
+		}
+		for _, l := range page {
+			seen = append(seen, l.Alias)
+		}
+		if next == "" {
+			break
+		}
+		cursor = next
+	}
+	if len(seen) != 3 {
+		t.Errorf("paged through %d links, want 3", len(seen))
+	}
+	if _, _, err := repo.ListPage(ctx, "", 2, "garbage!"); !errors.Is(err, repository.ErrInvalidCursor) {
+		t.Errorf("expected ErrInvalidCursor, got %v", err)
+	}
+
+	// Delete menulis tombstone untuk delta feed
+	since := time.Now().UTC().UnixNano()
+	if err := repo.Delete(ctx, link.ID); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	tombstones, err := repo.ListDeletedSince(ctx, since)
+	if err != nil || len(tombstones) != 1 || tombstones[0].Alias != "b" {
+		t.Errorf("tombstones mismatch: %+v err=%v", tombstones, err)
+	}
+	if got, _ := repo.GetByAlias(ctx, "", "b"); got != nil {
+		t.Errorf("deleted link still returned: %+v", got)
+	}
+}
+
+func TestClickRepositoryPaging(t *testing.T) {
+	ctx := context.Background()
+	repo := NewClickRepository(openTestDB(t))
+
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	var events []models.ClickEvent
+	for i := 0; i < 5; i++ {
+		events = append(events, models.ClickEvent{Alias: "promo", CreatedAt: base.Add(time.Duration(i) * time.Minute)})
+	}
+	events = append(events, models.ClickEvent{Alias: "other", CreatedAt: base})
+	if err := repo.LogClicks(ctx, events); err != nil {
+		t.Fatalf("LogClicks: %v", err)
+	}
+	// Replay batch yang sama tidak boleh menggandakan data
+	if err := repo.LogClicks(ctx, events); err != nil {
+		t.Fatalf("LogClicks replay: %v", err)
+	}
+
+	page, next, err := repo.ListByAliasPage(ctx, "promo", 3, "")
+	if err != nil || len(page) != 3 || next == "" {
+		t.Fatalf("first page: len=%d next=%q err=%v", len(page), next, err)
+	}
+	if !page[0].CreatedAt.After(page[1].CreatedAt) {
+		t.Errorf("expected newest first")
+	}
+
+	page, next, err = repo.ListByAliasPage(ctx, "promo", 3, next)
+	if err != nil || len(page) != 2 || next != "" {
+		t.Fatalf("second page: len=%d next=%q err=%v", len(page), next, err)
+	}
+
+	_, total, err := repo.ListAllPaginated(ctx, 1, 10)
+	if err != nil || total != 6 {
+		t.Errorf("total mismatch: got %d, want 6 (err=%v)", total, err)
+	}
+
+	if err := repo.DeleteByLinkAlias(ctx, "promo"); err != nil {
+		t.Fatalf("DeleteByLinkAlias: %v", err)
+	}
+	if rest, _ := repo.ListByAlias(ctx, "promo"); len(rest) != 0 {
+		t.Errorf("expected promo clicks deleted, got %d", len(rest))
+	}
+}
+
+func TestNodeDomainsAndCounters(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+
+	nodes := NewNodeRepository(db)
+	if err := nodes.UpsertNode(ctx, &models.Node{ID: "n1", Domains: []string{"go.example.com"}}); err != nil {
+		t.Fatalf("UpsertNode: %v", err)
+	}
+	if err := nodes.AddDomain(ctx, "n1", "s.example.com"); err != nil {
+		t.Fatalf("AddDomain: %v", err)
+	}
+	if n, err := nodes.GetNodeByDomain(ctx, "s.example.com"); err != nil || n == nil || n.ID != "n1" {
+		t.Errorf("GetNodeByDomain: node=%v err=%v", n, err)
+	}
+	if err := nodes.RemoveDomain(ctx, "n1", "s.example.com"); err != nil {
+		t.Fatalf("RemoveDomain: %v", err)
+	}
+	if n, _ := nodes.GetNodeByDomain(ctx, "s.example.com"); n != nil {
+		t.Errorf("domain should be unmapped, got %v", n)
+	}
+
+	stats := NewLinkStatsRepository(db)
+	for i := 0; i < 3; i++ {
+		if err := stats.IncrementHit(ctx, "n1", "promo"); err != nil {
+			t.Fatalf("IncrementHit: %v", err)
+		}
+	}
+	if s, err := stats.Get(ctx, "n1", "promo"); err != nil || s == nil || s.HitCount != 3 {
+		t.Errorf("hit count mismatch: %+v err=%v", s, err)
+	}
+
+	variants := NewLinkVariantRepository(db)
+	if err := variants.Create(ctx, &models.LinkVariant{ID: "v1", LinkID: "promo", TargetURL: "https://a.example.com", Weight: 50}); err != nil {
+		t.Fatalf("variant create: %v", err)
+	}
+	variants.IncrementClicks(ctx, "promo", "v1")
+	variants.IncrementClicks(ctx, "promo", "v1")
+	variants.IncrementConversions(ctx, "promo", "v1")
+	if v, err := variants.GetByID(ctx, "promo", "v1"); err != nil || v == nil || v.Clicks != 2 || v.Conversions != 1 {
+		t.Errorf("variant counters mismatch: %+v err=%v", v, err)
+	}
+	variants.Create(ctx, &models.LinkVariant{ID: "v2", LinkID: "promo", TargetURL: "https://b.example.com", Weight: 50})
+	variants.Create(ctx, &models.LinkVariant{ID: "v1", LinkID: "other", TargetURL: "https://c.example.com", Weight: 100})
+	byLink, err := variants.GetByLinkIDs(ctx, []string{"promo", "none"})
+	if err != nil || len(byLink) != 1 || len(byLink["promo"]) != 2 || byLink["promo"][0].Clicks != 2 {
+		t.Errorf("GetByLinkIDs mismatch: %+v err=%v", byLink, err)
+	}
+
+	rollups := NewRollupRepository(db)
+	ru := models.ClickRollup{PK: "promo#hour#total", SK: "2025010100#", Alias: "promo", Granularity: "hour", Dimension: "total", Bucket: "2025010100", Count: 2}
+	if err := rollups.Add(ctx, []models.ClickRollup{ru, ru}); err != nil {
+		t.Fatalf("rollup add: %v", err)
+	}
+	rows, err := rollups.Query(ctx, ru.PK, "2025010100", "2025010100")
+	if err != nil || len(rows) != 1 || rows[0].Count != 4 {
+		t.Errorf("rollup mismatch: %+v err=%v", rows, err)
+	}
+}
+
+func TestNodeLiveness(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+
+	nodes := NewNodeRepository(db)
+	if err := nodes.UpsertNode(ctx, &models.Node{ID: "n1"}); err != nil {
+		t.Fatalf("UpsertNode: %v", err)
+	}
+	n, _ := nodes.GetByID(ctx, "n1")
+
+	// lastSeenAt yang sudah basi tidak boleh menimpa heartbeat baru
+	if err := nodes.MarkOffline(ctx, "n1", n.LastSeenAt.Add(-time.Minute)); err != nil {
+		t.Fatalf("MarkOffline: %v", err)
+	}
+	if n, _ := nodes.GetByID(ctx, "n1"); !n.IsOnline {
+		t.Errorf("stale MarkOffline flipped the node")
+	}
+	if err := nodes.MarkOffline(ctx, "n1", n.LastSeenAt); err != nil {
+		t.Fatalf("MarkOffline: %v", err)
+	}
+	if n, _ := nodes.GetByID(ctx, "n1"); n.IsOnline {
+		t.Errorf("node still online after MarkOffline")
+	}
+
+	events := NewNodeEventRepository(db)
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i, typ := range []string{models.NodeEventOnline, models.NodeEventOffline, models.NodeEventOnline} {
+		if err := events.Add(ctx, &models.NodeEvent{NodeID: "n1", Type: typ, At: base.Add(time.Duration(i) * time.Hour)}); err != nil {
+			t.Fatalf("Add: %v", err)
+		}
+	}
+
+	list, err := events.ListSince(ctx, "n1", base.Add(30*time.Minute))
+	if err != nil || len(list) != 2 || list[0].Type != models.NodeEventOffline {
+		t.Errorf("ListSince: %+v err=%v", list, err)
+	}
+	last, err := events.Latest(ctx, "n1", base.Add(90*time.Minute))
+	if err != nil || last == nil || last.Type != models.NodeEventOffline {
+		t.Errorf("Latest: %+v err=%v", last, err)
+	}
+	if last, _ := events.Latest(ctx, "n1", base); last != nil {
+		t.Errorf("expected no event before the first one, got %+v", last)
+	}
+}
+
+func TestNodeCredentials(t *testing.T) {
+	ctx := context.Background()
+	repo := NewNodeCredentialRepository(openTestDB(t))
+
+	if cred, err := repo.Get(ctx, "n1"); err != nil || cred != nil {
+		t.Fatalf("missing credential: %+v %v", cred, err)
+	}
+
+	key, cred, err := nodeauth.NewKey("n1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.Put(ctx, cred); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	got, _ := repo.Get(ctx, "n1")
+	if !nodeauth.Verify(got, key) {
+		t.Fatalf("stored credential does not verify: %+v", got)
+	}
+
+	// Rotasi: Put menimpa, key lama tidak berlaku lagi
+	newKey, rotated, _ := nodeauth.NewKey("n1")
+	if err := repo.Put(ctx, rotated); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	got, _ = repo.Get(ctx, "n1")
+	if nodeauth.Verify(got, key) || !nodeauth.Verify(got, newKey) {
+		t.Errorf("rotation did not replace the key")
+	}
+
+	if err := repo.Delete(ctx, "n1"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if got, _ := repo.Get(ctx, "n1"); got != nil {
+		t.Errorf("credential still present after Delete")
+	}
+}
+
+func TestNodeTokenSingleUse(t *testing.T) {
+	ctx := context.Background()
+	repo := NewNodeTokenRepository(openTestDB(t))
+
+	if _, err := repo.Create(ctx, "edge-1", "tok-1"); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if err := repo.MarkUsed(ctx, "tok-1"); err != nil {
+		t.Fatalf("first claim: %v", err)
+	}
+	if err := repo.MarkUsed(ctx, "tok-1"); !errors.Is(err, repository.ErrTokenUnavailable) {
+		t.Errorf("second claim: expected ErrTokenUnavailable, got %v", err)
+	}
+	if err := repo.MarkUsed(ctx, "missing"); !errors.Is(err, repository.ErrTokenUnavailable) {
+		t.Errorf("missing token: expected ErrTokenUnavailable, got %v", err)
+	}
+
+	// Token kedaluwarsa tidak bisa diklaim
+	old := &models.NodeToken{Token: "tok-old", Label: "old", CreatedAt: time.Now().UTC().Add(-models.NodeTokenTTL - time.Hour)}
+	if err := repo.put(ctx, repo.db.conn(), old); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.MarkUsed(ctx, "tok-old"); !errors.Is(err, repository.ErrTokenUnavailable) {
+		t.Errorf("expired token: expected ErrTokenUnavailable, got %v", err)
+	}
+}
+
+func TestUsersAndSessions(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	users := NewUserRepository(db)
+	sessions := NewSessionRepository(db)
+
+	now := time.Now().UTC()
+	u := &models.User{ID: "u1", Username: " Intern ", PasswordHash: "x", Role: models.RoleAnalyst, CreatedAt: now}
+	if err := users.Create(ctx, u); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if err := users.Create(ctx, &models.User{ID: "u2", Username: "intern", Role: models.RoleEditor}); err == nil {
+		t.Error("expected duplicate username to fail")
+	}
+
+	got, err := users.GetByUsername(ctx, "INTERN")
+	if err != nil || got == nil || got.ID != "u1" {
+		t.Fatalf("GetByUsername: user=%v err=%v", got, err)
+	}
+
+	got.Role = models.RoleEditor
+	if err := users.Update(ctx, got); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	if got, _ = users.Get(ctx, "u1"); got == nil || got.Role != models.RoleEditor {
+		t.Errorf("expected editor after update, got %+v", got)
+	}
+
+	// Session expired tidak dikembalikan
+	live := &models.Session{TokenHash: "live", UserID: "u1", CreatedAt: now, ExpiresAt: now.Add(time.Hour)}
+	expired := &models.Session{TokenHash: "old", UserID: "u1", CreatedAt: now, ExpiresAt: now.Add(-time.Minute)}
+	for _, s := range []*models.Session{expired, live} {
+		if err := sessions.Create(ctx, s); err != nil {
+			t.Fatalf("create session: %v", err)
+		}
+	}
+	if s, _ := sessions.Get(ctx, "old"); s != nil {
+		t.Errorf("expected expired session to be hidden, got %+v", s)
+	}
+
+	extended := now.Add(models.SessionTTL)
+	if err := sessions.Touch(ctx, "live", extended); err != nil {
+		t.Fatalf("touch: %v", err)
+	}
+	if s, _ := sessions.Get(ctx, "live"); s == nil || !s.ExpiresAt.Equal(extended) {
+		t.Errorf("expected extended session, got %+v", s)
+	}
+
+	if err := sessions.DeleteByUser(ctx, "u1"); err != nil {
+		t.Fatalf("DeleteByUser: %v", err)
+	}
+	if s, _ := sessions.Get(ctx, "live"); s != nil {
+		t.Errorf("expected session deleted, got %+v", s)
+	}
+
+	if err := users.Delete(ctx, "u1"); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if list, _ := users.List(ctx); len(list) != 0 {
+		t.Errorf("expected no users, got %d", len(list))
+	}
+}
+
+func TestAuditLog(t *testing.T) {
+	ctx := context.Background()
+	repo := NewAuditRepository(openTestDB(t))
+
+	base := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	records := []models.AuditRecord{
+		{ID: "a1", At: base, Actor: "Intern", Action: "link.create", TargetType: "link", TargetID: "promo"},
+		{ID: "a2", At: base.Add(time.Minute), Actor: "intern", Action: "link.update", TargetType: "link", TargetID: "promo",
+			Changes: []models.AuditChange{{Field: "targetUrl", Before: "https://a.example", After: "https://b.example"}}},
+		{ID: "a3", At: base.Add(2 * time.Minute), Actor: "boss", Action: "linkset.update", TargetType: "settings", TargetID: "global"},
+	}
+	for i := range records {
+		if err := repo.Append(ctx, &records[i]); err != nil {
+			t.Fatalf("append %s: %v", records[i].ID, err)
+		}
+	}
+
+	// Action "link" cocok dengan link.* saja, bukan linkset.*; urutan terbaru dulu
+	got, err := repo.List(ctx, models.AuditFilter{Actor: "INTERN", Action: "link"})
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(got) != 2 || got[0].ID != "a2" || got[1].ID != "a1" {
+		t.Fatalf("unexpected records: %+v", got)
+	}
+	if len(got[0].Changes) != 1 || got[0].Changes[0].After != "https://b.example" {
+		t.Errorf("changes not round-tripped: %+v", got[0].Changes)
+	}
+
+	got, _ = repo.List(ctx, models.AuditFilter{Since: base.Add(30 * time.Second), Limit: 1})
+	if len(got) != 1 || got[0].ID != "a3" {
+		t.Errorf("since+limit: %+v", got)
+	}
+	got, _ = repo.List(ctx, models.AuditFilter{TargetType: "link", TargetID: "promo", Until: base})
+	if len(got) != 1 || got[0].ID != "a1" {
+		t.Errorf("until: %+v", got)
+	}
+}
+
+func TestLinkRevisions(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	links := NewLinkRepository(db)
+	variants := NewLinkVariantRepository(db)
+	revisions := NewLinkRevisionRepository(db)
+
+	link := &models.Link{Alias: "promo", TargetURL: "https://a.example", Revision: 1, UpdatedBy: "alice"}
+	if err := links.Create(ctx, link); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	v1 := &models.LinkVariant{ID: "v1", LinkID: "promo", TargetURL: "https://v1.example", Weight: 100}
+	if err := variants.Create(ctx, v1); err != nil {
+		t.Fatalf("create variant: %v", err)
+	}
+	variants.IncrementClicks(ctx, "promo", "v1")
+
+	// Edit: revision 1 disnapshot, link jadi revision 2
+	prev := *link
+	current, _ := variants.GetByLinkID(ctx, "promo")
+	link.TargetURL = "https://b.example"
+	if err := revisions.Commit(ctx, link, models.NewLinkRevision(&prev, current), nil); err != nil {
+		t.Fatalf("commit: %v", err)
+	}
+	if link.Revision != 2 {
+		t.Errorf("expected revision 2, got %d", link.Revision)
+	}
+
+	// Commit dari versi basi ditolak
+	stale := prev
+	stale.TargetURL = "https://stale.example"
+	if err := revisions.Commit(ctx, &stale, models.NewLinkRevision(&prev, current), nil); !errors.Is(err, repository.ErrRevisionConflict) {
+		t.Errorf("expected ErrRevisionConflict, got %v", err)
+	}
+
+	rev, err := revisions.Get(ctx, link.ID, 1)
+	if err != nil || rev == nil {
+		t.Fatalf("get revision 1: rev=%v err=%v", rev, err)
+	}
+	if rev.Link.TargetURL != "https://a.example" || rev.Author != "alice" || len(rev.Variants) != 1 || rev.Variants[0].Clicks != 0 {
+		t.Errorf("unexpected snapshot: %+v", rev)
+	}
+
+	// Restore dengan variant set baru: v1 diganti config-nya (clicks tetap), v2 dibuat
+	restorePrev := *link
+	restored := rev.Link
+	restored.RestoredFrom = 1
+	newVariants := []models.LinkVariant{
+		{ID: "v1", LinkID: "promo", TargetURL: "https://v1b.example", Weight: 50},
+		{ID: "v2", LinkID: "promo", TargetURL: "https://v2.example", Weight: 50},
+	}
+	if err := revisions.Commit(ctx, &restored, models.NewLinkRevision(&restorePrev, current), newVariants); err != nil {
+		t.Fatalf("restore commit: %v", err)
+	}
+	got, _ := variants.GetByID(ctx, "promo", "v1")
+	if got == nil || got.TargetURL != "https://v1b.example" || got.Clicks != 1 {
+		t.Errorf("variant config not replaced or clicks lost: %+v", got)
+	}
+
+	// Restore ke snapshot tanpa variant menghapus semua variant
+	emptyPrev := restored
+	cleared := restored
+	if err := revisions.Commit(ctx, &cleared, models.NewLinkRevision(&emptyPrev, nil), []models.LinkVariant{}); err != nil {
+		t.Fatalf("clear commit: %v", err)
+	}
+	if all, _ := variants.GetByLinkID(ctx, "promo"); len(all) != 0 {
+		t.Errorf("expected variants cleared, got %d", len(all))
+	}
+
+	list, _ := revisions.List(ctx, link.ID)
+	if len(list) != 3 || list[0].Revision != 3 || list[2].Revision != 1 {
+		t.Errorf("unexpected revision list: %+v", list)
+	}
+	if err := revisions.DeleteByLink(ctx, link.ID); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if list, _ := revisions.List(ctx, link.ID); len(list) != 0 {
+		t.Errorf("expected revisions deleted, got %d", len(list))
+	}
+}
+
+func TestAliasReservation(t *testing.T) {
+	ctx := context.Background()
+	repo := NewLinkRepository(openTestDB(t))
+
+	link := &models.Link{Alias: "Promo", TargetURL: "https://a.example"}
+	if err := repo.Create(ctx, link); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	// Alias case-insensitive: "promo" bentrok dengan "Promo"
+	dup := &models.Link{Alias: "promo", TargetURL: "https://b.example"}
+	if err := repo.Create(ctx, dup); !errors.Is(err, repository.ErrAliasTaken) {
+		t.Fatalf("expected ErrAliasTaken, got %v", err)
+	}
+
+	// Delete melepas reservasi alias
+	if err := repo.Delete(ctx, link.ID); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if err := repo.Create(ctx, dup); err != nil {
+		t.Fatalf("create after delete: %v", err)
+	}
+
+	// Sequence per scope, mulai dari 1
+	for want := int64(1); want <= 3; want++ {
+		got, err := repo.NextAliasSequence(ctx, "go.example")
+		if err != nil || got != want {
+			t.Fatalf("sequence: got %d (%v), want %d", got, err, want)
+		}
+	}
+	if got, _ := repo.NextAliasSequence(ctx, "other.example"); got != 1 {
+		t.Errorf("expected independent scope to start at 1, got %d", got)
+	}
+}
+
+func TestAliasPerDomain(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	repo := NewLinkRepository(db)
+
+	global := &models.Link{Alias: "promo", TargetURL: "https://global.example"}
+	brandA := &models.Link{Alias: "promo", Domain: "go.brand-a.com", TargetURL: "https://a.example"}
+	brandB := &models.Link{Alias: "Promo", Domain: "GO.brand-b.com", TargetURL: "https://b.example"}
+	for _, l := range []*models.Link{global, brandA, brandB} {
+		if err := repo.Create(ctx, l); err != nil {
+			t.Fatalf("create %s/%s: %v", l.Domain, l.Alias, err)
+		}
+	}
+	if brandA.Key != "go.brand-a.com/promo" || global.Key != "promo" {
+		t.Errorf("unexpected keys: %q %q", brandA.Key, global.Key)
+	}
+
+	// Alias yang sama di domain yang sama tetap ditolak (case-insensitive)
+	dup := &models.Link{Alias: "PROMO", Domain: "go.brand-a.com", TargetURL: "https://dup.example"}
+	if err := repo.Create(ctx, dup); !errors.Is(err, repository.ErrAliasTaken) {
+		t.Fatalf("expected ErrAliasTaken, got %v", err)
+	}
+
+	// Retry dengan alias lain memakai struct yang sama (seperti generate alias): Key lama tidak terbawa
+	dup.Alias = "promo-2"
+	if err := repo.Create(ctx, dup); err != nil {
+		t.Fatalf("retry with free alias: %v", err)
+	}
+	if dup.Key != "go.brand-a.com/promo-2" {
+		t.Errorf("retry key = %q", dup.Key)
+	}
+
+	if got, _ := repo.FindByAlias(ctx, "go.brand-a.com", "promo"); got == nil || got.ID != brandA.ID {
+		t.Errorf("FindByAlias brand-a: got %+v", got)
+	}
+	if got, _ := repo.FindByAlias(ctx, "other.example", "promo"); got == nil || got.ID != global.ID {
+		t.Errorf("FindByAlias should fall back to domain-less link, got %+v", got)
+	}
+	if got, _ := repo.GetByAlias(ctx, "", "promo"); got == nil || got.ID != global.ID {
+		t.Errorf("GetByAlias without domain: got %+v", got)
+	}
+	if got, _ := repo.GetByKey(ctx, "go.brand-a.com/promo"); got == nil || got.ID != brandA.ID {
+		t.Errorf("GetByKey: got %+v", got)
+	}
+
+	// Link lama ber-domain (tanpa Key) memegang alias polos sebagai key data
+	legacy := models.Link{ID: "legacy", Alias: "docs", Domain: "go.brand-a.com", TargetURL: "https://docs.example"}
+	if err := repo.put(ctx, &legacy); err != nil {
+		t.Fatalf("put legacy: %v", err)
+	}
+	if err := repo.BackfillAliases(ctx); err != nil {
+		t.Fatalf("backfill: %v", err)
+	}
+	if got, _ := repo.GetByKey(ctx, "docs"); got == nil || got.ID != "legacy" {
+		t.Errorf("legacy link should be addressable by bare alias, got %+v", got)
+	}
+	for _, l := range []*models.Link{
+		{Alias: "docs", TargetURL: "https://x.example"},
+		{Alias: "docs", Domain: "go.brand-a.com", TargetURL: "https://x.example"},
+	} {
+		if err := repo.Create(ctx, l); !errors.Is(err, repository.ErrAliasTaken) {
+			t.Errorf("create %q/%q should collide with legacy link, got %v", l.Domain, l.Alias, err)
+		}
+	}
+}
+
+func TestWebhookDeliveries(t *testing.T) {
+	ctx := context.Background()
+	repo := NewWebhookDeliveryRepository(openTestDB(t))
+
+	base := time.Now().Add(-time.Hour).Truncate(time.Second)
+	deliveries := []models.WebhookDelivery{
+		{ID: "d1", WebhookID: "wh1", Event: "link.created", Payload: []byte(`{"event":"link.created"}`), Status: models.DeliveryPending, CreatedAt: base},
+		{ID: "d2", WebhookID: "wh1", Event: "click.created", Payload: []byte(`{"event":"click.created"}`), Status: models.DeliveryPending, CreatedAt: base.Add(time.Minute)},
+		{ID: "d3", WebhookID: "wh2", Event: "link.created", Payload: []byte(`{}`), Status: models.DeliveryPending, CreatedAt: base.Add(2 * time.Minute)},
+		// Lewat retensi: dihapus saat delivery baru dibuat
+		{ID: "old", WebhookID: "wh1", Event: "link.created", Payload: []byte(`{}`), Status: models.DeliverySuccess, CreatedAt: base.Add(-models.WebhookDeliveryRetention)},
+	}
+	for i := range deliveries {
+		if err := repo.Create(ctx, &deliveries[i]); err != nil {
+			t.Fatalf("create %s: %v", deliveries[i].ID, err)
+		}
+	}
+
+	d1 := deliveries[0]
+	d1.Status = models.DeliveryFailed
+	d1.Attempts = []models.WebhookAttempt{{Attempt: 1, RequestID: "r1", StatusCode: 500, ResponseBody: "boom", LatencyMs: 12}}
+	if err := repo.Update(ctx, &d1); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	if err := repo.PutDeadLetter(ctx, &d1); err != nil {
+		t.Fatalf("dead letter: %v", err)
+	}
+
+	got, err := repo.Get(ctx, "wh1", "d1")
+	if err != nil || got == nil {
+		t.Fatalf("get: %v %v", got, err)
+	}
+	if got.Status != models.DeliveryFailed || len(got.Attempts) != 1 || got.Attempts[0].RequestID != "r1" || string(got.Payload) != `{"event":"link.created"}` {
+		t.Errorf("delivery not round-tripped: %+v", got)
+	}
+	if other, _ := repo.Get(ctx, "wh2", "d1"); other != nil {
+		t.Errorf("delivery visible under another webhook")
+	}
+
+	list, err := repo.List(ctx, models.WebhookDeliveryFilter{WebhookID: "wh1"})
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(list) != 2 || list[0].ID != "d2" || list[1].ID != "d1" {
+		t.Fatalf("unexpected deliveries (newest first, expired pruned): %+v", list)
+	}
+	list, _ = repo.List(ctx, models.WebhookDeliveryFilter{WebhookID: "wh1", Status: models.DeliveryFailed})
+	if len(list) != 1 || list[0].ID != "d1" {
+		t.Errorf("status filter: %+v", list)
+	}
+	list, _ = repo.List(ctx, models.WebhookDeliveryFilter{WebhookID: "wh1", Event: "click.created"})
+	if len(list) != 1 || list[0].ID != "d2" {
+		t.Errorf("event filter: %+v", list)
+	}
+
+	dead, _ := repo.ListDeadLetters(ctx, models.WebhookDeliveryFilter{WebhookID: "wh1", Since: base.Add(-time.Second), Until: base.Add(time.Second)})
+	if len(dead) != 1 || dead[0].ID != "d1" {
+		t.Fatalf("dead letters: %+v", dead)
+	}
+	if err := repo.DeleteDeadLetter(ctx, "wh1", "d1"); err != nil {
+		t.Fatalf("delete dead letter: %v", err)
+	}
+	if dead, _ = repo.ListDeadLetters(ctx, models.WebhookDeliveryFilter{WebhookID: "wh1"}); len(dead) != 0 {
+		t.Errorf("dead letter not deleted: %+v", dead)
+	}
+}
+
+func TestWebhookOutbox(t *testing.T) {
+	ctx := context.Background()
+	repo := NewWebhookOutboxRepository(openTestDB(t))
+
+	now := time.Now()
+	entries := []models.WebhookOutboxEntry{
+		{ID: "e1", WebhookID: "wh1", Event: "link.created", Payload: []byte(`{}`), NextAttemptAt: now.Add(-time.Second), CreatedAt: now},
+		{ID: "e2", WebhookID: "wh1", Event: "link.created", Payload: []byte(`{}`), NextAttemptAt: now, CreatedAt: now},
+		{ID: "later", WebhookID: "wh2", Event: "link.created", Payload: []byte(`{}`), NextAttemptAt: now.Add(time.Hour), CreatedAt: now},
+	}
+	if err := repo.Enqueue(ctx, entries); err != nil {
+		t.Fatalf("enqueue: %v", err)
+	}
+
+	claimed, err := repo.Claim(ctx, now, time.Minute, 1)
+	if err != nil || len(claimed) != 1 || claimed[0].ID != "e1" {
+		t.Fatalf("claim: %+v %v", claimed, err)
+	}
+	if !claimed[0].NextAttemptAt.Equal(now.Add(time.Minute)) {
+		t.Errorf("lease not applied: %v", claimed[0].NextAttemptAt)
+	}
+
+	// e1 sedang di-lease: claim berikutnya hanya dapat e2
+	claimed, err = repo.Claim(ctx, now, time.Minute, 10)
+	if err != nil || len(claimed) != 1 || claimed[0].ID != "e2" {
+		t.Fatalf("second claim: %+v %v", claimed, err)
+	}
+
+	// Lease habis tanpa Reschedule/Delete (mis. proses mati): e1 diambil ulang
+	claimed, err = repo.Claim(ctx, now.Add(2*time.Minute), time.Minute, 10)
+	if err != nil || len(claimed) != 2 {
+		t.Fatalf("reclaim after lease: %+v %v", claimed, err)
+	}
+
+	e := claimed[0]
+	e.Attempts = 1
+	e.LastError = "503"
+	e.NextAttemptAt = now.Add(10 * time.Minute)
+	if err := repo.Reschedule(ctx, &e); err != nil {
+		t.Fatalf("reschedule: %v", err)
+	}
+	if err := repo.Delete(ctx, claimed[1].ID); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+
+	claimed, err = repo.Claim(ctx, now.Add(2*time.Hour), time.Minute, 10)
+	if err != nil || len(claimed) != 2 {
+		t.Fatalf("final claim: %+v %v", claimed, err)
+	}
+	for _, c := range claimed {
+		if c.ID == e.ID && (c.Attempts != 1 || c.LastError != "503") {
+			t.Errorf("rescheduled entry not persisted: %+v", c)
+		}
+	}
+}
@@ -0,0 +1,26 @@
+package sqlstore
+
+import "github.com/afuzapratama/nexuslink/internal/repository"
+
+// Pastikan implementasi SQL memenuhi interface storage
+var (
+	_ repository.LinkStore            = (*LinkRepository)(nil)
+	_ repository.NodeStore            = (*NodeRepository)(nil)
+	_ repository.NodeEventStore       = (*NodeEventRepository)(nil)
+	_ repository.ClickStore           = (*ClickRepository)(nil)
+	_ repository.StatsStore           = (*LinkStatsRepository)(nil)
+	_ repository.SettingsStore        = (*SettingsRepository)(nil)
+	_ repository.GroupStore           = (*LinkGroupRepository)(nil)
+	_ repository.WebhookStore         = (*WebhookRepository)(nil)
+	_ repository.VariantStore         = (*LinkVariantRepository)(nil)
+	_ repository.WebhookDeliveryStore = (*WebhookDeliveryRepository)(nil)
+	_ repository.WebhookOutboxStore   = (*WebhookOutboxRepository)(nil)
+	_ repository.TokenStore           = (*NodeTokenRepository)(nil)
+	_ repository.RollupStore          = (*RollupRepository)(nil)
+	_ repository.NodeCredentialStore  = (*NodeCredentialRepository)(nil)
+	_ repository.UserStore            = (*UserRepository)(nil)
+	_ repository.SessionStore         = (*SessionRepository)(nil)
+	_ repository.AuditStore           = (*AuditRepository)(nil)
+	_ repository.LinkRevisionStore    = (*LinkRevisionRepository)(nil)
+	_ repository.ClickDedupStore      = (*ClickDedupRepository)(nil)
+)
@@ -0,0 +1,54 @@
+package sqlstore
+
+import (
+	"context"
+	"strings"
+
+	"github.com/afuzapratama/nexuslink/internal/models"
+)
+
+type UserRepository struct {
+	db *DB
+}
+
+func NewUserRepository(db *DB) *UserRepository {
+	return &UserRepository{db: db}
+}
+
+func (r *UserRepository) Create(ctx context.Context, u *models.User) error {
+	u.Username = strings.ToLower(strings.TrimSpace(u.Username))
+	data, err := encode(u)
+	if err != nil {
+		return err
+	}
+	_, err = r.db.exec(ctx, `INSERT INTO users (id, username, data) VALUES (?, ?, ?)`, u.ID, u.Username, data)
+	return err
+}
+
+func (r *UserRepository) Get(ctx context.Context, id string) (*models.User, error) {
+	return getData[models.User](ctx, r.db, `SELECT data FROM users WHERE id = ?`, id)
+}
+
+func (r *UserRepository) GetByUsername(ctx context.Context, username string) (*models.User, error) {
+	return getData[models.User](ctx, r.db, `SELECT data FROM users WHERE username = ?`,
+		strings.ToLower(strings.TrimSpace(username)))
+}
+
+func (r *UserRepository) List(ctx context.Context) ([]models.User, error) {
+	return queryData[models.User](ctx, r.db, `SELECT data FROM users ORDER BY username`)
+}
+
+func (r *UserRepository) Update(ctx context.Context, u *models.User) error {
+	u.Username = strings.ToLower(strings.TrimSpace(u.Username))
+	data, err := encode(u)
+	if err != nil {
+		return err
+	}
+	_, err = r.db.exec(ctx, `UPDATE users SET username = ?, data = ? WHERE id = ?`, u.Username, data, u.ID)
+	return err
+}
+
+func (r *UserRepository) Delete(ctx context.Context, id string) error {
+	_, err := r.db.exec(ctx, `DELETE FROM users WHERE id = ?`, id)
+	return err
+}
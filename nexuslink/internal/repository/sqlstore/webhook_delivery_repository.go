@@ -0,0 +1,119 @@
+package sqlstore
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/afuzapratama/nexuslink/internal/models"
+)
+
+type WebhookDeliveryRepository struct {
+	db *DB
+}
+
+func NewWebhookDeliveryRepository(db *DB) *WebhookDeliveryRepository {
+	return &WebhookDeliveryRepository{db: db}
+}
+
+// Create menyimpan delivery baru; delivery yang lewat retensi ikut dibersihkan
+func (r *WebhookDeliveryRepository) Create(ctx context.Context, d *models.WebhookDelivery) error {
+	data, err := encode(d)
+	if err != nil {
+		return err
+	}
+
+	return r.db.withTx(ctx, func(q querier) error {
+		_, err := q.ExecContext(ctx, `
+			INSERT INTO webhook_deliveries (id, webhook_id, created_at, status, data) VALUES (?, ?, ?, ?, ?)`,
+			d.ID, d.WebhookID, d.CreatedAt.UnixNano(), d.Status, data)
+		if err != nil {
+			return err
+		}
+		_, err = q.ExecContext(ctx, `DELETE FROM webhook_deliveries WHERE created_at < ?`,
+			time.Now().Add(-models.WebhookDeliveryRetention).UnixNano())
+		return err
+	})
+}
+
+func (r *WebhookDeliveryRepository) Update(ctx context.Context, d *models.WebhookDelivery) error {
+	data, err := encode(d)
+	if err != nil {
+		return err
+	}
+	_, err = r.db.exec(ctx, `UPDATE webhook_deliveries SET status = ?, data = ? WHERE id = ?`, d.Status, data, d.ID)
+	return err
+}
+
+func (r *WebhookDeliveryRepository) Get(ctx context.Context, webhookID, id string) (*models.WebhookDelivery, error) {
+	return getData[models.WebhookDelivery](ctx, r.db,
+		`SELECT data FROM webhook_deliveries WHERE webhook_id = ? AND id = ?`, webhookID, id)
+}
+
+func (r *WebhookDeliveryRepository) List(ctx context.Context, f models.WebhookDeliveryFilter) ([]models.WebhookDelivery, error) {
+	return r.list(ctx, "webhook_deliveries", f)
+}
+
+func (r *WebhookDeliveryRepository) PutDeadLetter(ctx context.Context, d *models.WebhookDelivery) error {
+	data, err := encode(d)
+	if err != nil {
+		return err
+	}
+	_, err = r.db.exec(ctx, `
+		INSERT INTO webhook_dead_letters (id, webhook_id, created_at, data) VALUES (?, ?, ?, ?)
+		ON CONFLICT (id) DO UPDATE SET data = excluded.data`,
+		d.ID, d.WebhookID, d.CreatedAt.UnixNano(), data)
+	return err
+}
+
+func (r *WebhookDeliveryRepository) ListDeadLetters(ctx context.Context, f models.WebhookDeliveryFilter) ([]models.WebhookDelivery, error) {
+	f.Status = "" // dead letter selalu failed
+	return r.list(ctx, "webhook_dead_letters", f)
+}
+
+func (r *WebhookDeliveryRepository) DeleteDeadLetter(ctx context.Context, webhookID, id string) error {
+	_, err := r.db.exec(ctx, `DELETE FROM webhook_dead_letters WHERE webhook_id = ? AND id = ?`, webhookID, id)
+	return err
+}
+
+// list: filter waktu dan status di SQL, event dicek setelah decode
+func (r *WebhookDeliveryRepository) list(ctx context.Context, table string, f models.WebhookDeliveryFilter) ([]models.WebhookDelivery, error) {
+	where := []string{"webhook_id = ?"}
+	args := []interface{}{f.WebhookID}
+
+	if !f.Since.IsZero() {
+		where = append(where, "created_at >= ?")
+		args = append(args, f.Since.UnixNano())
+	}
+	if !f.Until.IsZero() {
+		where = append(where, "created_at <= ?")
+		args = append(args, f.Until.UnixNano())
+	}
+	if f.Status != "" {
+		where = append(where, "status = ?")
+		args = append(args, f.Status)
+	}
+
+	query := `SELECT data FROM ` + table + ` WHERE ` + strings.Join(where, " AND ") + ` ORDER BY created_at DESC, id DESC`
+	if f.Limit > 0 && f.Event == "" {
+		query += ` LIMIT ?`
+		args = append(args, f.Limit)
+	}
+
+	all, err := queryData[models.WebhookDelivery](ctx, r.db, query, args...)
+	if err != nil || f.Event == "" {
+		return all, err
+	}
+
+	deliveries := make([]models.WebhookDelivery, 0, len(all))
+	for i := range all {
+		if !f.Matches(&all[i]) {
+			continue
+		}
+		deliveries = append(deliveries, all[i])
+		if f.Limit > 0 && len(deliveries) >= f.Limit {
+			break
+		}
+	}
+	return deliveries, nil
+}
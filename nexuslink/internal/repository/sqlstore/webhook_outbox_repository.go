@@ -0,0 +1,110 @@
+package sqlstore
+
+import (
+	"context"
+	"time"
+
+	"github.com/afuzapratama/nexuslink/internal/models"
+)
+
+type WebhookOutboxRepository struct {
+	db *DB
+}
+
+func NewWebhookOutboxRepository(db *DB) *WebhookOutboxRepository {
+	return &WebhookOutboxRepository{db: db}
+}
+
+func (r *WebhookOutboxRepository) Enqueue(ctx context.Context, entries []models.WebhookOutboxEntry) error {
+	return r.db.withTx(ctx, func(q querier) error {
+		for i := range entries {
+			data, err := encode(&entries[i])
+			if err != nil {
+				return err
+			}
+			_, err = q.ExecContext(ctx, `INSERT INTO webhook_outbox (id, next_attempt_at, data) VALUES (?, ?, ?)`,
+				entries[i].ID, entries[i].NextAttemptAt.UnixNano(), data)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Claim: UPDATE bersyarat pada next_attempt_at lama, jadi replica lain yang membaca baris yang sama
+// tidak ikut mengambilnya (PostgreSQL re-check WHERE setelah lock, SQLite cuma satu writer)
+func (r *WebhookOutboxRepository) Claim(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]models.WebhookOutboxEntry, error) {
+	var claimed []models.WebhookOutboxEntry
+	err := r.db.withTx(ctx, func(q querier) error {
+		rows, err := q.QueryContext(ctx, `
+			SELECT next_attempt_at, data FROM webhook_outbox
+			WHERE next_attempt_at <= ? ORDER BY next_attempt_at LIMIT ?`, now.UnixNano(), limit)
+		if err != nil {
+			return err
+		}
+
+		type due struct {
+			at    int64
+			entry models.WebhookOutboxEntry
+		}
+		var candidates []due
+		for rows.Next() {
+			var (
+				d    due
+				data string
+			)
+			if err := rows.Scan(&d.at, &data); err != nil {
+				rows.Close()
+				return err
+			}
+			if err := decode(data, &d.entry); err != nil {
+				rows.Close()
+				return err
+			}
+			candidates = append(candidates, d)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return err
+		}
+
+		for _, d := range candidates {
+			e := d.entry
+			e.NextAttemptAt = now.Add(lease)
+			data, err := encode(&e)
+			if err != nil {
+				return err
+			}
+			res, err := q.ExecContext(ctx, `
+				UPDATE webhook_outbox SET next_attempt_at = ?, data = ? WHERE id = ? AND next_attempt_at = ?`,
+				e.NextAttemptAt.UnixNano(), data, e.ID, d.at)
+			if err != nil {
+				return err
+			}
+			if n, err := res.RowsAffected(); err == nil && n == 1 {
+				claimed = append(claimed, e)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return claimed, nil
+}
+
+func (r *WebhookOutboxRepository) Reschedule(ctx context.Context, e *models.WebhookOutboxEntry) error {
+	data, err := encode(e)
+	if err != nil {
+		return err
+	}
+	_, err = r.db.exec(ctx, `UPDATE webhook_outbox SET next_attempt_at = ?, data = ? WHERE id = ?`,
+		e.NextAttemptAt.UnixNano(), data, e.ID)
+	return err
+}
+
+func (r *WebhookOutboxRepository) Delete(ctx context.Context, id string) error {
+	_, err := r.db.exec(ctx, `DELETE FROM webhook_outbox WHERE id = ?`, id)
+	return err
+}
@@ -0,0 +1,102 @@
+package sqlstore
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/afuzapratama/nexuslink/internal/models"
+	"github.com/afuzapratama/nexuslink/internal/repository"
+)
+
+type WebhookRepository struct {
+	db *DB
+}
+
+func NewWebhookRepository(db *DB) *WebhookRepository {
+	return &WebhookRepository{db: db}
+}
+
+// GetAll returns all webhooks
+func (r *WebhookRepository) GetAll(ctx context.Context) ([]models.Webhook, error) {
+	webhooks, err := queryData[models.Webhook](ctx, r.db, `SELECT data FROM webhooks`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhooks: %w", err)
+	}
+	return webhooks, nil
+}
+
+// GetByID returns a webhook by ID
+func (r *WebhookRepository) GetByID(ctx context.Context, id string) (*models.Webhook, error) {
+	webhook, err := getData[models.Webhook](ctx, r.db, `SELECT data FROM webhooks WHERE id = ?`, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhook: %w", err)
+	}
+	if webhook == nil {
+		return nil, repository.ErrWebhookNotFound
+	}
+	return webhook, nil
+}
+
+// GetByEvent returns all active webhooks subscribed to a specific event
+func (r *WebhookRepository) GetByEvent(ctx context.Context, event string) ([]models.Webhook, error) {
+	allWebhooks, err := r.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var matchingWebhooks []models.Webhook
+	for _, webhook := range allWebhooks {
+		if !webhook.IsActive {
+			continue
+		}
+		for _, e := range webhook.Events {
+			if e == event {
+				matchingWebhooks = append(matchingWebhooks, webhook)
+				break
+			}
+		}
+	}
+	return matchingWebhooks, nil
+}
+
+// Create creates a new webhook
+func (r *WebhookRepository) Create(ctx context.Context, webhook *models.Webhook) error {
+	if err := r.put(ctx, webhook); err != nil {
+		return fmt.Errorf("failed to create webhook: %w", err)
+	}
+
+	log.Printf("Webhook created: %s (URL: %s, Events: %v)", webhook.ID, webhook.URL, webhook.Events)
+	return nil
+}
+
+// Update updates an existing webhook
+func (r *WebhookRepository) Update(ctx context.Context, webhook *models.Webhook) error {
+	if err := r.put(ctx, webhook); err != nil {
+		return fmt.Errorf("failed to update webhook: %w", err)
+	}
+
+	log.Printf("Webhook updated: %s", webhook.ID)
+	return nil
+}
+
+func (r *WebhookRepository) put(ctx context.Context, webhook *models.Webhook) error {
+	data, err := encode(webhook)
+	if err != nil {
+		return err
+	}
+	_, err = r.db.exec(ctx, `
+		INSERT INTO webhooks (id, data) VALUES (?, ?)
+		ON CONFLICT (id) DO UPDATE SET data = excluded.data`, webhook.ID, data)
+	return err
+}
+
+// Delete deletes a webhook
+func (r *WebhookRepository) Delete(ctx context.Context, id string) error {
+	if _, err := r.db.exec(ctx, `DELETE FROM webhooks WHERE id = ?`, id); err != nil {
+		return fmt.Errorf("failed to delete webhook: %w", err)
+	}
+
+	log.Printf("Webhook deleted: %s", id)
+	return nil
+}
@@ -0,0 +1,220 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/afuzapratama/nexuslink/internal/models"
+)
+
+// Interface storage per aggregate. Implementasi DynamoDB ada di package ini
+// (XRepository), implementasi SQL (SQLite/PostgreSQL) di internal/repository/sqlstore.
+
+type LinkStore interface {
+	// Create gagal dengan ErrAliasTaken kalau (domain, alias) atau key data (case-insensitive) sudah dipakai link lain
+	Create(ctx context.Context, link *models.Link) error
+	Update(ctx context.Context, link *models.Link) error
+	Delete(ctx context.Context, id string) error
+	List(ctx context.Context) ([]models.Link, error)
+	ListPaginated(ctx context.Context, page, limit int) ([]models.Link, int, error)
+	ListPage(ctx context.Context, groupID string, limit int, cursor string) ([]models.Link, string, error)
+	// ListByAlias returns semua link dengan alias ini, case-insensitive (alias unik per domain, bukan global)
+	ListByAlias(ctx context.Context, alias string) ([]models.Link, error)
+	// FindByAlias memilih link untuk request di domain: Domain sama, lalu pool, lalu link tanpa domain
+	// (lihat models.PickForDomain). Tidak filter isActive.
+	FindByAlias(ctx context.Context, domain, alias string) (*models.Link, error)
+	// GetByAlias returns link dengan alias di domain persis (domain kosong = link tanpa domain)
+	GetByAlias(ctx context.Context, domain, alias string) (*models.Link, error)
+	// GetByKey returns link berdasarkan Link.DataKey()
+	GetByKey(ctx context.Context, key string) (*models.Link, error)
+	ListChangedSince(ctx context.Context, since int64) ([]models.Link, error)
+	ListDeletedSince(ctx context.Context, since int64) ([]models.LinkTombstone, error)
+	// NextAliasSequence menaikkan counter alias sequential per scope (domain) secara atomic
+	NextAliasSequence(ctx context.Context, scope string) (int64, error)
+}
+
+type NodeStore interface {
+	UpsertNode(ctx context.Context, n *models.Node) error
+	UpdateHeartbeat(ctx context.Context, id string, agentVersion string, ipAddress string) error
+	MarkOffline(ctx context.Context, id string, lastSeenAt time.Time) error
+	SetWeight(ctx context.Context, id string, weight int) error
+	List(ctx context.Context) ([]models.Node, error)
+	GetByID(ctx context.Context, id string) (*models.Node, error)
+	Delete(ctx context.Context, id string) error
+	AddDomain(ctx context.Context, nodeID, domain string) error
+	RemoveDomain(ctx context.Context, nodeID, domain string) error
+	GetNodeByDomain(ctx context.Context, domain string) (*models.Node, error)
+}
+
+type NodeEventStore interface {
+	Add(ctx context.Context, ev *models.NodeEvent) error
+	ListSince(ctx context.Context, nodeID string, since time.Time) ([]models.NodeEvent, error)
+	Latest(ctx context.Context, nodeID string, before time.Time) (*models.NodeEvent, error)
+}
+
+type ClickStore interface {
+	LogClick(ctx context.Context, ev *models.ClickEvent) error
+	LogClicks(ctx context.Context, events []models.ClickEvent) error
+	ListByAlias(ctx context.Context, alias string) ([]models.ClickEvent, error)
+	ListByAliasPaginated(ctx context.Context, alias string, page, limit int) ([]models.ClickEvent, int, error)
+	ListByAliasPage(ctx context.Context, alias string, limit int, cursor string) ([]models.ClickEvent, string, error)
+	ListAllPaginated(ctx context.Context, page, limit int) ([]models.ClickEvent, int, error)
+	ListAllPage(ctx context.Context, limit int, cursor string) ([]models.ClickEvent, string, error)
+	DeleteByLinkAlias(ctx context.Context, alias string) error
+}
+
+// ClickDedupStore mengingat ID click dari agent yang sudah diproses (idempotency /sync/clicks)
+type ClickDedupStore interface {
+	// Claim returns false kalau ID sudah pernah di-claim dalam ClickDedupRetention
+	Claim(ctx context.Context, id string) (bool, error)
+}
+
+type StatsStore interface {
+	IncrementHit(ctx context.Context, nodeID, alias string) error
+	Get(ctx context.Context, nodeID, alias string) (*models.LinkStat, error)
+	ListAll(ctx context.Context) ([]models.LinkStat, error)
+	DeleteByLinkAlias(ctx context.Context, alias string) error
+}
+
+type SettingsStore interface {
+	Get(ctx context.Context) (*models.Settings, error)
+	GetOrDefault(ctx context.Context) *models.Settings
+	Update(ctx context.Context, settings *models.Settings) error
+}
+
+type GroupStore interface {
+	Create(ctx context.Context, group *models.LinkGroup) error
+	Update(ctx context.Context, group *models.LinkGroup) error
+	Delete(ctx context.Context, id string) error
+	Get(ctx context.Context, id string) (*models.LinkGroup, error)
+	List(ctx context.Context) ([]models.LinkGroup, error)
+}
+
+type WebhookStore interface {
+	GetAll(ctx context.Context) ([]models.Webhook, error)
+	GetByID(ctx context.Context, id string) (*models.Webhook, error)
+	GetByEvent(ctx context.Context, event string) ([]models.Webhook, error)
+	Create(ctx context.Context, webhook *models.Webhook) error
+	Update(ctx context.Context, webhook *models.Webhook) error
+	Delete(ctx context.Context, id string) error
+}
+
+// WebhookDeliveryStore menyimpan log delivery webhook (retensi models.WebhookDeliveryRetention)
+// dan dead letter (delivery gagal final, disimpan sampai di-replay / dihapus)
+type WebhookDeliveryStore interface {
+	Create(ctx context.Context, d *models.WebhookDelivery) error
+	Update(ctx context.Context, d *models.WebhookDelivery) error
+	Get(ctx context.Context, webhookID, id string) (*models.WebhookDelivery, error)
+	// List returns delivery terbaru dulu (WebhookID wajib)
+	List(ctx context.Context, f models.WebhookDeliveryFilter) ([]models.WebhookDelivery, error)
+
+	PutDeadLetter(ctx context.Context, d *models.WebhookDelivery) error
+	// ListDeadLetters returns dead letter terbaru dulu (WebhookID wajib)
+	ListDeadLetters(ctx context.Context, f models.WebhookDeliveryFilter) ([]models.WebhookDelivery, error)
+	DeleteDeadLetter(ctx context.Context, webhookID, id string) error
+}
+
+// WebhookOutboxStore antrian durable event webhook yang belum terkirim
+type WebhookOutboxStore interface {
+	// Enqueue menulis semua entry dalam satu operasi
+	Enqueue(ctx context.Context, entries []models.WebhookOutboxEntry) error
+	// Claim mengambil maksimal limit entry yang jatuh tempo (NextAttemptAt <= now) dan menggeser
+	// NextAttemptAt ke now+lease supaya tidak diambil worker / replica lain. Entry yang tidak
+	// di-Reschedule / Delete sebelum lease habis (mis. proses mati) diambil ulang.
+	Claim(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]models.WebhookOutboxEntry, error)
+	// Reschedule menyimpan Attempts, NextAttemptAt dan LastError entry
+	Reschedule(ctx context.Context, e *models.WebhookOutboxEntry) error
+	Delete(ctx context.Context, id string) error
+}
+
+type VariantStore interface {
+	GetByLinkID(ctx context.Context, linkID string) ([]models.LinkVariant, error)
+	// GetByLinkIDs memuat variants banyak link sekaligus (key map = linkID; link tanpa variant tidak ada di map)
+	GetByLinkIDs(ctx context.Context, linkIDs []string) (map[string][]models.LinkVariant, error)
+	GetByID(ctx context.Context, linkID, variantID string) (*models.LinkVariant, error)
+	Create(ctx context.Context, variant *models.LinkVariant) error
+	Update(ctx context.Context, variant *models.LinkVariant) error
+	Delete(ctx context.Context, linkID, variantID string) error
+	IncrementClicks(ctx context.Context, linkID, variantID string) error
+	IncrementConversions(ctx context.Context, linkID, variantID string) error
+}
+
+type TokenStore interface {
+	Create(ctx context.Context, label string, token string) (*models.NodeToken, error)
+	Get(ctx context.Context, token string) (*models.NodeToken, error)
+	List(ctx context.Context) ([]models.NodeToken, error)
+	// MarkUsed meng-klaim token (sekali pakai, atomic); sudah dipakai / kedaluwarsa → ErrTokenUnavailable
+	MarkUsed(ctx context.Context, token string) error
+}
+
+// NodeCredentialStore menyimpan satu credential aktif per node (Put menimpa = rotasi)
+type NodeCredentialStore interface {
+	Get(ctx context.Context, nodeID string) (*models.NodeCredential, error)
+	Put(ctx context.Context, cred *models.NodeCredential) error
+	Delete(ctx context.Context, nodeID string) error
+}
+
+// UserStore menyimpan akun admin (username disimpan lowercase, unik)
+type UserStore interface {
+	Create(ctx context.Context, u *models.User) error
+	Get(ctx context.Context, id string) (*models.User, error)
+	GetByUsername(ctx context.Context, username string) (*models.User, error)
+	List(ctx context.Context) ([]models.User, error)
+	Update(ctx context.Context, u *models.User) error
+	Delete(ctx context.Context, id string) error
+}
+
+// SessionStore menyimpan session login (key = hash token), dibagi antar replica API
+type SessionStore interface {
+	Create(ctx context.Context, s *models.Session) error
+	Get(ctx context.Context, tokenHash string) (*models.Session, error)
+	Touch(ctx context.Context, tokenHash string, expiresAt time.Time) error
+	Delete(ctx context.Context, tokenHash string) error
+	DeleteByUser(ctx context.Context, userID string) error
+}
+
+// AuditStore append-only: tidak ada update / delete
+type AuditStore interface {
+	Append(ctx context.Context, rec *models.AuditRecord) error
+	// List returns record terbaru dulu dalam range [Since, Until]
+	List(ctx context.Context, f models.AuditFilter) ([]models.AuditRecord, error)
+}
+
+// LinkRevisionStore menyimpan versi lama link (key: link ID + nomor revision)
+type LinkRevisionStore interface {
+	// Commit menulis link (revision prev+1) dan snapshot prev dalam satu transaksi.
+	// variants non-nil = konfigurasi variant link ikut diganti (counter dipertahankan).
+	// Return ErrRevisionConflict kalau link di store sudah bukan revision prev.
+	Commit(ctx context.Context, link *models.Link, prev *models.LinkRevision, variants []models.LinkVariant) error
+	// List returns snapshot terbaru dulu
+	List(ctx context.Context, linkID string) ([]models.LinkRevision, error)
+	Get(ctx context.Context, linkID string, revision int) (*models.LinkRevision, error)
+	DeleteByLink(ctx context.Context, linkID string) error
+}
+
+type RollupStore interface {
+	Add(ctx context.Context, rollups []models.ClickRollup) error
+	Query(ctx context.Context, pk, fromBucket, toBucket string) ([]models.ClickRollup, error)
+}
+
+// Pastikan implementasi DynamoDB memenuhi interface
+var (
+	_ LinkStore            = (*LinkRepository)(nil)
+	_ NodeStore            = (*NodeRepository)(nil)
+	_ NodeEventStore       = (*NodeEventRepository)(nil)
+	_ ClickStore           = (*ClickRepository)(nil)
+	_ StatsStore           = (*LinkStatsRepository)(nil)
+	_ SettingsStore        = (*SettingsRepository)(nil)
+	_ GroupStore           = (*LinkGroupRepository)(nil)
+	_ WebhookStore         = (*WebhookRepository)(nil)
+	_ VariantStore         = (*LinkVariantRepository)(nil)
+	_ WebhookDeliveryStore = (*WebhookDeliveryRepository)(nil)
+	_ WebhookOutboxStore   = (*WebhookOutboxRepository)(nil)
+	_ TokenStore           = (*NodeTokenRepository)(nil)
+	_ RollupStore          = (*RollupRepository)(nil)
+	_ NodeCredentialStore  = (*NodeCredentialRepository)(nil)
+	_ UserStore            = (*UserRepository)(nil)
+	_ SessionStore         = (*SessionRepository)(nil)
+	_ AuditStore           = (*AuditRepository)(nil)
+	_ LinkRevisionStore    = (*LinkRevisionRepository)(nil)
+)
@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"context"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/afuzapratama/nexuslink/internal/database"
+	"github.com/afuzapratama/nexuslink/internal/models"
+)
+
+type UserRepository struct {
+	db *dynamodb.Client
+}
+
+func NewUserRepository() *UserRepository {
+	return &UserRepository{
+		db: database.Client(),
+	}
+}
+
+func (r *UserRepository) Create(ctx context.Context, u *models.User) error {
+	return r.put(ctx, u, aws.String("attribute_not_exists(id)"))
+}
+
+func (r *UserRepository) Update(ctx context.Context, u *models.User) error {
+	return r.put(ctx, u, nil)
+}
+
+func (r *UserRepository) put(ctx context.Context, u *models.User, condition *string) error {
+	u.Username = strings.ToLower(strings.TrimSpace(u.Username))
+
+	item, err := attributevalue.MarshalMap(u)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName:           aws.String(database.UsersTableName),
+		Item:                item,
+		ConditionExpression: condition,
+	})
+	return err
+}
+
+func (r *UserRepository) Get(ctx context.Context, id string) (*models.User, error) {
+	out, err := r.db.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(database.UsersTableName),
+		Key: map[string]types.AttributeValue{
+			"id": &types.AttributeValueMemberS{Value: id},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if out.Item == nil {
+		return nil, nil
+	}
+
+	var u models.User
+	if err := attributevalue.UnmarshalMap(out.Item, &u); err != nil {
+		return nil, err
+	}
+	return &u, nil
+}
+
+// GetByUsername scan tabel user (jumlah user admin kecil, tidak perlu index)
+func (r *UserRepository) GetByUsername(ctx context.Context, username string) (*models.User, error) {
+	users, err := r.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	username = strings.ToLower(strings.TrimSpace(username))
+	for i := range users {
+		if users[i].Username == username {
+			return &users[i], nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *UserRepository) List(ctx context.Context) ([]models.User, error) {
+	var users []models.User
+	paginator := dynamodb.NewScanPaginator(r.db, &dynamodb.ScanInput{
+		TableName: aws.String(database.UsersTableName),
+	})
+	for paginator.HasMorePages() {
+		out, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		var page []models.User
+		if err := attributevalue.UnmarshalListOfMaps(out.Items, &page); err != nil {
+			return nil, err
+		}
+		users = append(users, page...)
+	}
+	return users, nil
+}
+
+func (r *UserRepository) Delete(ctx context.Context, id string) error {
+	_, err := r.db.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName: aws.String(database.UsersTableName),
+		Key: map[string]types.AttributeValue{
+			"id": &types.AttributeValueMemberS{Value: id},
+		},
+	})
+	return err
+}
@@ -0,0 +1,136 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/afuzapratama/nexuslink/internal/database"
+	"github.com/afuzapratama/nexuslink/internal/models"
+)
+
+type WebhookDeliveryRepository struct {
+	db *dynamodb.Client
+}
+
+func NewWebhookDeliveryRepository() *WebhookDeliveryRepository {
+	return &WebhookDeliveryRepository{
+		db: database.Client(),
+	}
+}
+
+// webhookDeliveryItem: key (webhookId, id); ID UUIDv7 jadi sort key urut waktu.
+// expiresAtUnix = atribut TTL (tidak dipakai di tabel dead letter).
+type webhookDeliveryItem struct {
+	models.WebhookDelivery
+	ExpiresAtUnix int64 `dynamodbav:"expiresAtUnix,omitempty"`
+}
+
+func (r *WebhookDeliveryRepository) Create(ctx context.Context, d *models.WebhookDelivery) error {
+	return r.put(ctx, database.WebhookDeliveriesTableName, d, d.CreatedAt.Add(models.WebhookDeliveryRetention).Unix())
+}
+
+func (r *WebhookDeliveryRepository) Update(ctx context.Context, d *models.WebhookDelivery) error {
+	return r.put(ctx, database.WebhookDeliveriesTableName, d, d.CreatedAt.Add(models.WebhookDeliveryRetention).Unix())
+}
+
+func (r *WebhookDeliveryRepository) Get(ctx context.Context, webhookID, id string) (*models.WebhookDelivery, error) {
+	out, err := r.db.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(database.WebhookDeliveriesTableName),
+		Key:       deliveryKey(webhookID, id),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if out.Item == nil {
+		return nil, nil
+	}
+
+	var it webhookDeliveryItem
+	if err := attributevalue.UnmarshalMap(out.Item, &it); err != nil {
+		return nil, err
+	}
+	return &it.WebhookDelivery, nil
+}
+
+func (r *WebhookDeliveryRepository) List(ctx context.Context, f models.WebhookDeliveryFilter) ([]models.WebhookDelivery, error) {
+	return r.query(ctx, database.WebhookDeliveriesTableName, f)
+}
+
+func (r *WebhookDeliveryRepository) PutDeadLetter(ctx context.Context, d *models.WebhookDelivery) error {
+	return r.put(ctx, database.WebhookDeadLettersTableName, d, 0)
+}
+
+func (r *WebhookDeliveryRepository) ListDeadLetters(ctx context.Context, f models.WebhookDeliveryFilter) ([]models.WebhookDelivery, error) {
+	f.Status = ""
+	return r.query(ctx, database.WebhookDeadLettersTableName, f)
+}
+
+func (r *WebhookDeliveryRepository) DeleteDeadLetter(ctx context.Context, webhookID, id string) error {
+	_, err := r.db.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName: aws.String(database.WebhookDeadLettersTableName),
+		Key:       deliveryKey(webhookID, id),
+	})
+	return err
+}
+
+func (r *WebhookDeliveryRepository) put(ctx context.Context, table string, d *models.WebhookDelivery, expiresAt int64) error {
+	item, err := attributevalue.MarshalMap(webhookDeliveryItem{WebhookDelivery: *d, ExpiresAtUnix: expiresAt})
+	if err != nil {
+		return err
+	}
+	_, err = r.db.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(table),
+		Item:      item,
+	})
+	return err
+}
+
+// query membaca partisi webhook dari yang terbaru; berhenti begitu lewat Since atau Limit tercapai
+func (r *WebhookDeliveryRepository) query(ctx context.Context, table string, f models.WebhookDeliveryFilter) ([]models.WebhookDelivery, error) {
+	paginator := dynamodb.NewQueryPaginator(r.db, &dynamodb.QueryInput{
+		TableName:              aws.String(table),
+		KeyConditionExpression: aws.String("webhookId = :wid"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":wid": &types.AttributeValueMemberS{Value: f.WebhookID},
+		},
+		ScanIndexForward: aws.Bool(false),
+	})
+
+	var deliveries []models.WebhookDelivery
+	for paginator.HasMorePages() {
+		out, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		var page []webhookDeliveryItem
+		if err := attributevalue.UnmarshalListOfMaps(out.Items, &page); err != nil {
+			return nil, err
+		}
+		for i := range page {
+			d := &page[i].WebhookDelivery
+			if !f.Since.IsZero() && d.CreatedAt.Before(f.Since) {
+				return deliveries, nil
+			}
+			if !f.Matches(d) {
+				continue
+			}
+			deliveries = append(deliveries, *d)
+			if f.Limit > 0 && len(deliveries) >= f.Limit {
+				return deliveries, nil
+			}
+		}
+	}
+	return deliveries, nil
+}
+
+func deliveryKey(webhookID, id string) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{
+		"webhookId": &types.AttributeValueMemberS{Value: webhookID},
+		"id":        &types.AttributeValueMemberS{Value: id},
+	}
+}
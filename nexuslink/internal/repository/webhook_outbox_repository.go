@@ -0,0 +1,149 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/afuzapratama/nexuslink/internal/database"
+	"github.com/afuzapratama/nexuslink/internal/models"
+)
+
+// maxTransactItems: batas item per TransactWriteItems DynamoDB
+const maxTransactItems = 100
+
+type WebhookOutboxRepository struct {
+	db *dynamodb.Client
+}
+
+func NewWebhookOutboxRepository() *WebhookOutboxRepository {
+	return &WebhookOutboxRepository{
+		db: database.Client(),
+	}
+}
+
+// webhookOutboxItem: dueAtMs = NextAttemptAt dalam unix millis, untuk filter dan claim bersyarat.
+// Outbox normalnya kecil (entry dihapus setelah terkirim), jadi Claim cukup Scan.
+type webhookOutboxItem struct {
+	models.WebhookOutboxEntry
+	DueAtMs int64 `dynamodbav:"dueAtMs"`
+}
+
+func (r *WebhookOutboxRepository) Enqueue(ctx context.Context, entries []models.WebhookOutboxEntry) error {
+	for start := 0; start < len(entries); start += maxTransactItems {
+		end := min(start+maxTransactItems, len(entries))
+
+		items := make([]types.TransactWriteItem, 0, end-start)
+		for i := start; i < end; i++ {
+			item, err := outboxItem(&entries[i])
+			if err != nil {
+				return err
+			}
+			items = append(items, types.TransactWriteItem{Put: &types.Put{
+				TableName: aws.String(database.WebhookOutboxTableName),
+				Item:      item,
+			}})
+		}
+		if _, err := r.db.TransactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{TransactItems: items}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *WebhookOutboxRepository) Claim(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]models.WebhookOutboxEntry, error) {
+	paginator := dynamodb.NewScanPaginator(r.db, &dynamodb.ScanInput{
+		TableName:        aws.String(database.WebhookOutboxTableName),
+		FilterExpression: aws.String("dueAtMs <= :now"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":now": &types.AttributeValueMemberN{Value: strconv.FormatInt(now.UnixMilli(), 10)},
+		},
+	})
+
+	var claimed []models.WebhookOutboxEntry
+	for paginator.HasMorePages() && len(claimed) < limit {
+		out, err := paginator.NextPage(ctx)
+		if err != nil {
+			return claimed, err
+		}
+
+		var page []webhookOutboxItem
+		if err := attributevalue.UnmarshalListOfMaps(out.Items, &page); err != nil {
+			return claimed, err
+		}
+		for i := range page {
+			if len(claimed) >= limit {
+				break
+			}
+			e := page[i].WebhookOutboxEntry
+			e.NextAttemptAt = now.Add(lease)
+			ok, err := r.claim(ctx, &e, page[i].DueAtMs)
+			if err != nil {
+				return claimed, err
+			}
+			if ok {
+				claimed = append(claimed, e)
+			}
+		}
+	}
+	return claimed, nil
+}
+
+// claim menulis lease baru hanya kalau dueAtMs belum diubah worker lain
+func (r *WebhookOutboxRepository) claim(ctx context.Context, e *models.WebhookOutboxEntry, dueAtMs int64) (bool, error) {
+	item, err := outboxItem(e)
+	if err != nil {
+		return false, err
+	}
+	_, err = r.db.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName:           aws.String(database.WebhookOutboxTableName),
+		Item:                item,
+		ConditionExpression: aws.String("dueAtMs = :due"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":due": &types.AttributeValueMemberN{Value: strconv.FormatInt(dueAtMs, 10)},
+		},
+	})
+	var ccfe *types.ConditionalCheckFailedException
+	if errors.As(err, &ccfe) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (r *WebhookOutboxRepository) Reschedule(ctx context.Context, e *models.WebhookOutboxEntry) error {
+	item, err := outboxItem(e)
+	if err != nil {
+		return err
+	}
+	// Jangan menghidupkan lagi entry yang sudah dihapus
+	_, err = r.db.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName:           aws.String(database.WebhookOutboxTableName),
+		Item:                item,
+		ConditionExpression: aws.String("attribute_exists(id)"),
+	})
+	var ccfe *types.ConditionalCheckFailedException
+	if errors.As(err, &ccfe) {
+		return nil
+	}
+	return err
+}
+
+func (r *WebhookOutboxRepository) Delete(ctx context.Context, id string) error {
+	_, err := r.db.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName: aws.String(database.WebhookOutboxTableName),
+		Key: map[string]types.AttributeValue{
+			"id": &types.AttributeValueMemberS{Value: id},
+		},
+	})
+	return err
+}
+
+func outboxItem(e *models.WebhookOutboxEntry) (map[string]types.AttributeValue, error) {
+	return attributevalue.MarshalMap(webhookOutboxItem{WebhookOutboxEntry: *e, DueAtMs: e.NextAttemptAt.UnixMilli()})
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 
@@ -12,6 +13,9 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 )
 
+// ErrWebhookNotFound dikembalikan GetByID kalau webhook tidak ada
+var ErrWebhookNotFound = errors.New("webhook not found")
+
 type WebhookRepository struct {
 	client    *dynamodb.Client
 	tableName string
@@ -54,7 +58,7 @@ func (r *WebhookRepository) GetByID(ctx context.Context, id string) (*models.Web
 	}
 
 	if result.Item == nil {
-		return nil, fmt.Errorf("webhook not found")
+		return nil, ErrWebhookNotFound
 	}
 
 	var webhook models.Webhook
@@ -0,0 +1,206 @@
+package rollup
+
+import (
+	"context"
+	"log"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/afuzapratama/nexuslink/internal/models"
+)
+
+// Bucket formats (UTC)
+const (
+	hourLayout = "2006-01-02T15"
+	dayLayout  = "2006-01-02"
+)
+
+// Dimensions yang di-maintain untuk setiap click
+var Dimensions = []string{
+	models.RollupDimTotal,
+	models.RollupDimCountry,
+	models.RollupDimDevice,
+	models.RollupDimBrowser,
+	models.RollupDimOS,
+	models.RollupDimReferrer,
+	models.RollupDimNode,
+	models.RollupDimBot,
+}
+
+// ValidGranularity checks granularity value
+func ValidGranularity(g string) bool {
+	return g == models.RollupHour || g == models.RollupDay
+}
+
+// ValidDimension checks dimension value
+func ValidDimension(d string) bool {
+	for _, dim := range Dimensions {
+		if dim == d {
+			return true
+		}
+	}
+	return false
+}
+
+// Bucket returns the bucket string of t for a granularity
+func Bucket(t time.Time, granularity string) string {
+	t = t.UTC()
+	if granularity == models.RollupDay {
+		return t.Format(dayLayout)
+	}
+	return t.Format(hourLayout)
+}
+
+// Truncate returns the start of the bucket containing t
+func Truncate(t time.Time, granularity string) time.Time {
+	t = t.UTC()
+	if granularity == models.RollupDay {
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	}
+	return t.Truncate(time.Hour)
+}
+
+// Step returns the duration of one bucket
+func Step(granularity string) time.Duration {
+	if granularity == models.RollupDay {
+		return 24 * time.Hour
+	}
+	return time.Hour
+}
+
+// PK/SK builder untuk tabel rollup
+func PK(alias, granularity, dimension string) string {
+	return alias + "#" + granularity + "#" + dimension
+}
+
+func SK(bucket, value string) string {
+	return bucket + "#" + value
+}
+
+// dimensionValue mengambil value dimensi dari click event ("unknown" kalau kosong)
+func dimensionValue(ev *models.ClickEvent, dim string) string {
+	var v string
+	switch dim {
+	case models.RollupDimTotal:
+		return "all"
+	case models.RollupDimCountry:
+		v = strings.ToUpper(ev.Country)
+	case models.RollupDimDevice:
+		v = ev.Device
+	case models.RollupDimBrowser:
+		v = ev.Browser
+	case models.RollupDimOS:
+		v = ev.OS
+	case models.RollupDimReferrer:
+		return ReferrerDomain(ev.Referrer)
+	case models.RollupDimNode:
+		v = ev.NodeID
+	case models.RollupDimBot:
+		if ev.IsBot {
+			return "bot"
+		}
+		return "human"
+	}
+	if v == "" {
+		return "unknown"
+	}
+	return v
+}
+
+// ReferrerDomain returns host referrer tanpa "www." ("direct" kalau tidak ada referrer)
+func ReferrerDomain(referrer string) string {
+	referrer = strings.TrimSpace(referrer)
+	if referrer == "" {
+		return "direct"
+	}
+	u, err := url.Parse(referrer)
+	if err != nil || u.Hostname() == "" {
+		return "unknown"
+	}
+	return strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+}
+
+// Aggregate menjumlahkan batch click event menjadi increment rollup
+// (per alias + gabungan semua link, per hour + day, per dimensi).
+func Aggregate(events []models.ClickEvent) []models.ClickRollup {
+	counts := make(map[[2]string]*models.ClickRollup)
+
+	for i := range events {
+		ev := &events[i]
+		if ev.Alias == "" {
+			continue
+		}
+		created := ev.CreatedAt
+		if created.IsZero() {
+			created = time.Now()
+		}
+
+		for _, alias := range []string{ev.Alias, models.RollupAllLinks} {
+			for _, gran := range []string{models.RollupHour, models.RollupDay} {
+				bucket := Bucket(created, gran)
+				for _, dim := range Dimensions {
+					value := dimensionValue(ev, dim)
+					key := [2]string{PK(alias, gran, dim), SK(bucket, value)}
+
+					if r, ok := counts[key]; ok {
+						r.Count++
+						continue
+					}
+					counts[key] = &models.ClickRollup{
+						PK:          key[0],
+						SK:          key[1],
+						Alias:       alias,
+						Granularity: gran,
+						Dimension:   dim,
+						Value:       value,
+						Bucket:      bucket,
+						Count:       1,
+					}
+				}
+			}
+		}
+	}
+
+	out := make([]models.ClickRollup, 0, len(counts))
+	for _, r := range counts {
+		out = append(out, *r)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].PK != out[j].PK {
+			return out[i].PK < out[j].PK
+		}
+		return out[i].SK < out[j].SK
+	})
+	return out
+}
+
+// Store menambahkan increment rollup ke storage (e.g. RollupRepository)
+type Store interface {
+	Add(ctx context.Context, rollups []models.ClickRollup) error
+}
+
+// ClickWriter sama dengan ingest.ClickWriter
+type ClickWriter interface {
+	LogClicks(ctx context.Context, events []models.ClickEvent) error
+}
+
+// Writer menulis click event lalu meng-update rollup untuk batch yang sama.
+// Error rollup hanya di-log: kalau dikembalikan, pipeline akan retry batch dan counter bisa dobel.
+type Writer struct {
+	Clicks  ClickWriter
+	Rollups Store
+}
+
+// LogClicks implements ingest.ClickWriter
+func (w *Writer) LogClicks(ctx context.Context, events []models.ClickEvent) error {
+	if err := w.Clicks.LogClicks(ctx, events); err != nil {
+		return err
+	}
+
+	if err := w.Rollups.Add(ctx, Aggregate(events)); err != nil {
+		log.Printf("rollup: update for %d clicks failed: %v", len(events), err)
+	}
+	return nil
+}
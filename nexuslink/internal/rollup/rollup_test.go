@@ -0,0 +1,53 @@
+package rollup
+
+import (
+	"testing"
+	"time"
+
+	"github.com/afuzapratama/nexuslink/internal/models"
+)
+
+func TestAggregate(t *testing.T) {
+	at := time.Date(2025, 3, 1, 13, 45, 0, 0, time.UTC)
+	events := []models.ClickEvent{
+		{Alias: "promo", Country: "id", Referrer: "https://www.instagram.com/p/1", CreatedAt: at},
+		{Alias: "promo", Country: "ID", IsBot: true, CreatedAt: at.Add(5 * time.Minute)},
+		{Alias: "docs", Country: "SG", CreatedAt: at.Add(time.Hour)},
+	}
+
+	counts := make(map[string]int64)
+	for _, r := range Aggregate(events) {
+		counts[r.PK+"|"+r.SK] = r.Count
+	}
+
+	testCases := []struct {
+		key    string
+		expect int64
+	}{
+		{"promo#hour#total|2025-03-01T13#all", 2},
+		{"promo#day#country|2025-03-01#ID", 2},
+		{"promo#hour#referrer|2025-03-01T13#instagram.com", 1},
+		{"promo#hour#referrer|2025-03-01T13#direct", 1},
+		{"promo#hour#bot|2025-03-01T13#bot", 1},
+		{"*#hour#total|2025-03-01T14#all", 1},
+		{"*#day#total|2025-03-01#all", 3},
+		{"docs#hour#device|2025-03-01T14#unknown", 1},
+	}
+
+	for _, tc := range testCases {
+		if got := counts[tc.key]; got != tc.expect {
+			t.Errorf("%s: got %d, want %d", tc.key, got, tc.expect)
+		}
+	}
+}
+
+func TestBucketTruncate(t *testing.T) {
+	at := time.Date(2025, 3, 1, 23, 30, 0, 0, time.FixedZone("WIB", 7*3600))
+
+	if got := Bucket(at, models.RollupHour); got != "2025-03-01T16" {
+		t.Errorf("hour bucket mismatch: got %s", got)
+	}
+	if got := Truncate(at, models.RollupDay); !got.Equal(time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("day truncate mismatch: got %s", got)
+	}
+}
@@ -0,0 +1,358 @@
+package rules
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/afuzapratama/nexuslink/internal/models"
+)
+
+// Visitor berisi semua atribut visitor yang bisa dipakai oleh rule conditions
+type Visitor struct {
+	Country  string
+	ASN      string
+	Device   string
+	OS       string
+	Browser  string
+	Referrer string
+	Language string // primary tag dari Accept-Language
+	Query    url.Values
+	IsBot    bool
+	Time     time.Time
+}
+
+// Evaluate mengembalikan rule pertama yang match (nil kalau tidak ada)
+func Evaluate(linkRules []models.LinkRule, v Visitor) *models.LinkRule {
+	for i := range linkRules {
+		if linkRules[i].Disabled {
+			continue
+		}
+		if Matches(linkRules[i], v) {
+			return &linkRules[i]
+		}
+	}
+	return nil
+}
+
+// Matches mengecek apakah semua (match=all) atau salah satu (match=any) condition terpenuhi.
+// Rule tanpa condition selalu match (berguna sebagai "catch-all" di akhir list).
+func Matches(rule models.LinkRule, v Visitor) bool {
+	if len(rule.Conditions) == 0 {
+		return true
+	}
+
+	matchAny := strings.EqualFold(rule.Match, models.RuleMatchAny)
+	for _, c := range rule.Conditions {
+		ok := matchCondition(c, v)
+		if matchAny && ok {
+			return true
+		}
+		if !matchAny && !ok {
+			return false
+		}
+	}
+	return !matchAny
+}
+
+func matchCondition(c models.RuleCondition, v Visitor) bool {
+	if c.Field == models.RuleFieldTime {
+		return matchTime(c, v.Time)
+	}
+
+	value := fieldValue(c, v)
+
+	switch c.Operator {
+	case models.RuleOpIn:
+		return inValues(c.Field, value, c.Values)
+	case models.RuleOpNotIn:
+		return !inValues(c.Field, value, c.Values)
+	case models.RuleOpContains:
+		return containsAny(value, c.Values)
+	case models.RuleOpNotContains:
+		return !containsAny(value, c.Values)
+	case models.RuleOpExists:
+		return value != ""
+	case models.RuleOpNotExists:
+		return value == ""
+	}
+	return false
+}
+
+func fieldValue(c models.RuleCondition, v Visitor) string {
+	switch c.Field {
+	case models.RuleFieldCountry:
+		return v.Country
+	case models.RuleFieldASN:
+		return v.ASN
+	case models.RuleFieldDevice:
+		return v.Device
+	case models.RuleFieldOS:
+		return v.OS
+	case models.RuleFieldBrowser:
+		return v.Browser
+	case models.RuleFieldReferrer:
+		return v.Referrer
+	case models.RuleFieldLanguage:
+		return v.Language
+	case models.RuleFieldQuery:
+		return v.Query.Get(c.Key)
+	case models.RuleFieldBot:
+		return strconv.FormatBool(v.IsBot)
+	}
+	return ""
+}
+
+// inValues membandingkan value dengan list secara case-insensitive.
+// OS pakai partial match ("Windows 10" cocok dengan "Windows"), ASN menerima "AS7713" maupun "7713",
+// language cocok juga dengan prefix ("en-US" cocok dengan "en").
+func inValues(field, value string, values []string) bool {
+	if value == "" {
+		return false
+	}
+
+	switch field {
+	case models.RuleFieldOS:
+		return ContainsOS(values, value)
+	case models.RuleFieldASN:
+		value = normalizeASN(value)
+		for _, s := range values {
+			if normalizeASN(s) == value {
+				return true
+			}
+		}
+		return false
+	case models.RuleFieldLanguage:
+		lang := strings.ToLower(value)
+		for _, s := range values {
+			s = strings.ToLower(strings.TrimSpace(s))
+			if lang == s || strings.HasPrefix(lang, s+"-") {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, s := range values {
+		if strings.EqualFold(strings.TrimSpace(s), value) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsAny(value string, values []string) bool {
+	lower := strings.ToLower(value)
+	for _, s := range values {
+		if s != "" && strings.Contains(lower, strings.ToLower(s)) {
+			return true
+		}
+	}
+	return false
+}
+
+func normalizeASN(s string) string {
+	s = strings.ToUpper(strings.TrimSpace(s))
+	return strings.TrimPrefix(s, "AS")
+}
+
+// matchTime mengecek jam visitor (di timezone c.Key) terhadap range "HH:MM".
+// Range yang melewati tengah malam (mis. 22:00-06:00) juga didukung.
+func matchTime(c models.RuleCondition, t time.Time) bool {
+	if c.Operator != models.RuleOpBetween || len(c.Values) != 2 {
+		return false
+	}
+
+	loc := time.UTC
+	if c.Key != "" {
+		// Timezone yang tidak bisa di-load tidak match (bukan diam-diam pakai UTC)
+		l, err := loadLocation(c.Key)
+		if err != nil {
+			return false
+		}
+		loc = l
+	}
+
+	start, err1 := parseClock(c.Values[0])
+	end, err2 := parseClock(c.Values[1])
+	if err1 != nil || err2 != nil {
+		return false
+	}
+
+	local := t.In(loc)
+	now := local.Hour()*60 + local.Minute()
+
+	if start <= end {
+		return now >= start && now < end
+	}
+	return now >= start || now < end
+}
+
+// locations cache hasil time.LoadLocation per zone (termasuk error), supaya evaluasi
+// rule di redirect path tidak membaca tzdata setiap request
+var locations sync.Map // zone -> locationResult
+
+type locationResult struct {
+	loc *time.Location
+	err error
+}
+
+func loadLocation(zone string) (*time.Location, error) {
+	if v, ok := locations.Load(zone); ok {
+		r := v.(locationResult)
+		return r.loc, r.err
+	}
+	loc, err := time.LoadLocation(zone)
+	locations.Store(zone, locationResult{loc: loc, err: err})
+	return loc, err
+}
+
+func parseClock(s string) (int, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(s))
+	if err != nil {
+		return 0, err
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// ContainsOS checks if OS name matches allowed list
+// Uses partial match: "Windows 10" matches "Windows", "Linux x86_64" matches "Linux"
+func ContainsOS(allowedOS []string, detectedOS string) bool {
+	if detectedOS == "" {
+		return false
+	}
+
+	detectedLower := strings.ToLower(detectedOS)
+	// Normalize: remove spaces and special chars for better matching
+	detectedNormalized := strings.ReplaceAll(strings.ReplaceAll(detectedLower, " ", ""), "_", "")
+
+	for _, allowed := range allowedOS {
+		allowedLower := strings.ToLower(allowed)
+		allowedNormalized := strings.ReplaceAll(strings.ReplaceAll(allowedLower, " ", ""), "_", "")
+
+		// Check if detected OS contains allowed OS name
+		// e.g., "intelmacosх1015_7" contains "macos"
+		// e.g., "windows10" contains "windows"
+		if strings.Contains(detectedNormalized, allowedNormalized) {
+			return true
+		}
+	}
+	return false
+}
+
+// LegacyRules mengubah allow-list lama di models.Link (BlockBots, AllowedOS, AllowedDevices,
+// AllowedBrowsers, AllowedCountries) menjadi block rules, supaya resolver cukup punya satu jalur evaluasi.
+// Urutannya sama dengan urutan check lama: bot, OS, device, browser, country.
+func LegacyRules(link *models.Link) []models.LinkRule {
+	var out []models.LinkRule
+
+	if link.BlockBots {
+		out = append(out, legacyBlock("legacy-bots", models.RuleFieldBot, models.RuleOpIn, []string{"true"}, "bot_blocked"))
+	}
+	if len(link.AllowedOS) > 0 {
+		out = append(out, legacyBlock("legacy-os", models.RuleFieldOS, models.RuleOpNotIn, link.AllowedOS, "os_not_allowed"))
+	}
+	if len(link.AllowedDevices) > 0 {
+		out = append(out, legacyBlock("legacy-devices", models.RuleFieldDevice, models.RuleOpNotIn, link.AllowedDevices, "device_not_allowed"))
+	}
+	if len(link.AllowedBrowsers) > 0 {
+		out = append(out, legacyBlock("legacy-browsers", models.RuleFieldBrowser, models.RuleOpNotIn, link.AllowedBrowsers, "browser_not_allowed"))
+	}
+	if len(link.AllowedCountries) > 0 {
+		out = append(out, legacyBlock("legacy-countries", models.RuleFieldCountry, models.RuleOpNotIn, link.AllowedCountries, "country_not_allowed"))
+	}
+
+	return out
+}
+
+func legacyBlock(id, field, op string, values []string, reason string) models.LinkRule {
+	return models.LinkRule{
+		ID:    id,
+		Match: models.RuleMatchAll,
+		Conditions: []models.RuleCondition{
+			{Field: field, Operator: op, Values: values},
+		},
+		Action: models.RuleAction{Type: models.RuleActionBlock, Reason: reason},
+	}
+}
+
+// Validate memastikan rules yang dikirim dari dashboard bisa dievaluasi
+func Validate(linkRules []models.LinkRule) error {
+	for i, r := range linkRules {
+		if r.Match != "" && r.Match != models.RuleMatchAll && r.Match != models.RuleMatchAny {
+			return fmt.Errorf("rule %d: match must be %q or %q", i+1, models.RuleMatchAll, models.RuleMatchAny)
+		}
+
+		for j, c := range r.Conditions {
+			if err := validateCondition(c); err != nil {
+				return fmt.Errorf("rule %d condition %d: %w", i+1, j+1, err)
+			}
+		}
+
+		switch r.Action.Type {
+		case models.RuleActionRedirect:
+			u, err := url.Parse(r.Action.URL)
+			if err != nil || u.Scheme == "" || u.Host == "" {
+				return fmt.Errorf("rule %d: redirect action requires an absolute url", i+1)
+			}
+		case models.RuleActionVariant:
+			if strings.TrimSpace(r.Action.VariantID) == "" {
+				return fmt.Errorf("rule %d: variant action requires variantId", i+1)
+			}
+		case models.RuleActionBlock:
+		default:
+			return fmt.Errorf("rule %d: unknown action type %q", i+1, r.Action.Type)
+		}
+	}
+	return nil
+}
+
+func validateCondition(c models.RuleCondition) error {
+	switch c.Field {
+	case models.RuleFieldCountry, models.RuleFieldASN, models.RuleFieldDevice, models.RuleFieldOS,
+		models.RuleFieldBrowser, models.RuleFieldReferrer, models.RuleFieldLanguage, models.RuleFieldBot:
+	case models.RuleFieldQuery:
+		if strings.TrimSpace(c.Key) == "" {
+			return fmt.Errorf("query condition requires key")
+		}
+	case models.RuleFieldTime:
+		if c.Operator != models.RuleOpBetween || len(c.Values) != 2 {
+			return fmt.Errorf("time condition requires operator %q with 2 values", models.RuleOpBetween)
+		}
+		for _, v := range c.Values {
+			if _, err := parseClock(v); err != nil {
+				return fmt.Errorf("invalid time %q (use HH:MM)", v)
+			}
+		}
+		if c.Key != "" {
+			if _, err := loadLocation(c.Key); err != nil {
+				return fmt.Errorf("invalid timezone %q", c.Key)
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown field %q", c.Field)
+	}
+
+	switch c.Operator {
+	case models.RuleOpIn, models.RuleOpNotIn, models.RuleOpContains, models.RuleOpNotContains:
+		if len(c.Values) == 0 {
+			return fmt.Errorf("operator %q requires values", c.Operator)
+		}
+	case models.RuleOpExists, models.RuleOpNotExists:
+	default:
+		return fmt.Errorf("unknown operator %q", c.Operator)
+	}
+	return nil
+}
+
+// PrimaryLanguage mengambil tag bahasa pertama dari header Accept-Language
+// e.g. "id-ID,id;q=0.9,en;q=0.8" -> "id-ID"
+func PrimaryLanguage(acceptLanguage string) string {
+	first := strings.Split(acceptLanguage, ",")[0]
+	first = strings.Split(first, ";")[0]
+	return strings.TrimSpace(first)
+}
@@ -0,0 +1,131 @@
+package rules
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/afuzapratama/nexuslink/internal/models"
+)
+
+func TestEvaluateStoreRouting(t *testing.T) {
+	linkRules := []models.LinkRule{
+		{
+			ID:    "play-store",
+			Match: models.RuleMatchAll,
+			Conditions: []models.RuleCondition{
+				{Field: models.RuleFieldCountry, Operator: models.RuleOpIn, Values: []string{"ID"}},
+				{Field: models.RuleFieldDevice, Operator: models.RuleOpIn, Values: []string{"Mobile"}},
+				{Field: models.RuleFieldOS, Operator: models.RuleOpIn, Values: []string{"Android"}},
+			},
+			Action: models.RuleAction{Type: models.RuleActionRedirect, URL: "https://play.google.com/store"},
+		},
+		{
+			ID:    "app-store",
+			Match: models.RuleMatchAny,
+			Conditions: []models.RuleCondition{
+				{Field: models.RuleFieldOS, Operator: models.RuleOpIn, Values: []string{"iOS"}},
+				{Field: models.RuleFieldOS, Operator: models.RuleOpIn, Values: []string{"iPadOS"}},
+			},
+			Action: models.RuleAction{Type: models.RuleActionRedirect, URL: "https://apps.apple.com"},
+		},
+	}
+
+	testCases := []struct {
+		name    string
+		visitor Visitor
+		expect  string
+	}{
+		{"android indonesia", Visitor{Country: "ID", Device: "Mobile", OS: "Android 14"}, "play-store"},
+		{"android singapore", Visitor{Country: "SG", Device: "Mobile", OS: "Android 14"}, ""},
+		{"iphone", Visitor{Country: "ID", Device: "Mobile", OS: "iOS 17.1"}, "app-store"},
+		{"desktop", Visitor{Country: "ID", Device: "Desktop", OS: "Windows 10"}, ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := ""
+			if r := Evaluate(linkRules, tc.visitor); r != nil {
+				got = r.ID
+			}
+			if got != tc.expect {
+				t.Errorf("rule mismatch: got '%s', want '%s'", got, tc.expect)
+			}
+		})
+	}
+}
+
+func TestMatchConditions(t *testing.T) {
+	noon := time.Date(2025, 1, 1, 5, 0, 0, 0, time.UTC) // 12:00 di Asia/Jakarta
+
+	testCases := []struct {
+		name      string
+		condition models.RuleCondition
+		visitor   Visitor
+		expect    bool
+	}{
+		{"asn with prefix", models.RuleCondition{Field: models.RuleFieldASN, Operator: models.RuleOpIn, Values: []string{"7713"}}, Visitor{ASN: "AS7713"}, true},
+		{"language prefix", models.RuleCondition{Field: models.RuleFieldLanguage, Operator: models.RuleOpIn, Values: []string{"en"}}, Visitor{Language: "en-US"}, true},
+		{"language other", models.RuleCondition{Field: models.RuleFieldLanguage, Operator: models.RuleOpIn, Values: []string{"en"}}, Visitor{Language: "id-ID"}, false},
+		{"referrer contains", models.RuleCondition{Field: models.RuleFieldReferrer, Operator: models.RuleOpContains, Values: []string{"instagram.com"}}, Visitor{Referrer: "https://l.instagram.com/?u=x"}, true},
+		{"query param", models.RuleCondition{Field: models.RuleFieldQuery, Key: "utm_source", Operator: models.RuleOpIn, Values: []string{"tiktok"}}, Visitor{Query: url.Values{"utm_source": {"TikTok"}}}, true},
+		{"query missing", models.RuleCondition{Field: models.RuleFieldQuery, Key: "ref", Operator: models.RuleOpExists}, Visitor{Query: url.Values{}}, false},
+		{"office hours jakarta", models.RuleCondition{Field: models.RuleFieldTime, Key: "Asia/Jakarta", Operator: models.RuleOpBetween, Values: []string{"09:00", "17:00"}}, Visitor{Time: noon}, true},
+		{"invalid timezone", models.RuleCondition{Field: models.RuleFieldTime, Key: "Mars/Olympus", Operator: models.RuleOpBetween, Values: []string{"00:00", "23:59"}}, Visitor{Time: noon}, false},
+		{"night window", models.RuleCondition{Field: models.RuleFieldTime, Operator: models.RuleOpBetween, Values: []string{"22:00", "06:00"}}, Visitor{Time: noon}, true},
+		{"bot", models.RuleCondition{Field: models.RuleFieldBot, Operator: models.RuleOpIn, Values: []string{"true"}}, Visitor{IsBot: true}, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := matchCondition(tc.condition, tc.visitor); got != tc.expect {
+				t.Errorf("match mismatch: got %v, want %v", got, tc.expect)
+			}
+		})
+	}
+}
+
+func TestLegacyRulesOrder(t *testing.T) {
+	link := &models.Link{
+		BlockBots:        true,
+		AllowedOS:        []string{"Windows"},
+		AllowedCountries: []string{"ID"},
+	}
+
+	// Bot dari negara lain harus kena bot_blocked dulu (sama seperti urutan check lama)
+	matched := Evaluate(LegacyRules(link), Visitor{IsBot: true, OS: "Linux", Country: "US"})
+	if matched == nil || matched.Action.Reason != "bot_blocked" {
+		t.Fatalf("expected bot_blocked, got %+v", matched)
+	}
+
+	matched = Evaluate(LegacyRules(link), Visitor{OS: "Windows 10", Country: "US"})
+	if matched == nil || matched.Action.Reason != "country_not_allowed" {
+		t.Fatalf("expected country_not_allowed, got %+v", matched)
+	}
+
+	if matched := Evaluate(LegacyRules(link), Visitor{OS: "Windows 10", Country: "ID"}); matched != nil {
+		t.Fatalf("expected no match, got %+v", matched)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	valid := []models.LinkRule{{
+		Conditions: []models.RuleCondition{{Field: models.RuleFieldCountry, Operator: models.RuleOpIn, Values: []string{"ID"}}},
+		Action:     models.RuleAction{Type: models.RuleActionRedirect, URL: "https://example.com"},
+	}}
+	if err := Validate(valid); err != nil {
+		t.Errorf("expected valid rules, got %v", err)
+	}
+
+	invalid := [][]models.LinkRule{
+		{{Action: models.RuleAction{Type: models.RuleActionRedirect, URL: "/relative"}}},
+		{{Action: models.RuleAction{Type: "teleport"}}},
+		{{Conditions: []models.RuleCondition{{Field: "planet", Operator: models.RuleOpIn, Values: []string{"mars"}}}, Action: models.RuleAction{Type: models.RuleActionBlock}}},
+		{{Conditions: []models.RuleCondition{{Field: models.RuleFieldTime, Operator: models.RuleOpBetween, Values: []string{"25:00", "26:00"}}}, Action: models.RuleAction{Type: models.RuleActionBlock}}},
+	}
+	for i, rs := range invalid {
+		if err := Validate(rs); err == nil {
+			t.Errorf("case %d: expected validation error", i)
+		}
+	}
+}
@@ -0,0 +1,162 @@
+// Package steering memilih domain/node sehat untuk canonical short URL sebuah link,
+// supaya QR code dan share URL yang dibagikan tidak mengarah ke node yang sedang down.
+package steering
+
+import (
+	"math/rand"
+	"sort"
+	"strings"
+
+	"github.com/afuzapratama/nexuslink/internal/models"
+)
+
+// Domain status
+const (
+	StatusHealthy  = "healthy"
+	StatusDegraded = "degraded" // node offline (ditandai node monitor)
+	StatusUnknown  = "unknown"  // domain tidak terdaftar di node mana pun
+)
+
+// Candidate adalah satu domain di pool link beserta kondisi node-nya
+type Candidate struct {
+	Domain   string `json:"domain"`
+	NodeID   string `json:"nodeId,omitempty"`
+	NodeName string `json:"nodeName,omitempty"`
+	Region   string `json:"region,omitempty"`
+	Weight   int    `json:"weight"`
+	Status   string `json:"status"`
+}
+
+// Healthy true kalau node domain ini online
+func (c Candidate) Healthy() bool {
+	return c.Status == StatusHealthy
+}
+
+// DomainStatuses returns status semua domain yang dilayani node (urut per domain)
+func DomainStatuses(nodes []models.Node) []Candidate {
+	var out []Candidate
+	for i := range nodes {
+		for _, d := range nodes[i].Domains {
+			out = append(out, candidate(strings.ToLower(d), &nodes[i]))
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Domain < out[j].Domain })
+	return out
+}
+
+// Candidates membangun pool kandidat untuk link:
+// Domain/Domains link → domain milik NodeID link → semua domain semua node.
+func Candidates(link *models.Link, nodes []models.Node) []Candidate {
+	byDomain := make(map[string]*models.Node)
+	for i := range nodes {
+		for _, d := range nodes[i].Domains {
+			byDomain[strings.ToLower(d)] = &nodes[i]
+		}
+	}
+
+	pool := link.DomainPool()
+	if len(pool) == 0 && link.NodeID != "" {
+		for i := range nodes {
+			if nodes[i].ID == link.NodeID {
+				for _, d := range nodes[i].Domains {
+					pool = append(pool, strings.ToLower(d))
+				}
+			}
+		}
+	}
+	if len(pool) == 0 {
+		for d := range byDomain {
+			pool = append(pool, d)
+		}
+		sort.Strings(pool)
+	}
+
+	out := make([]Candidate, 0, len(pool))
+	for _, d := range pool {
+		out = append(out, candidate(d, byDomain[d]))
+	}
+	return out
+}
+
+func candidate(domain string, n *models.Node) Candidate {
+	c := Candidate{Domain: domain, Weight: 1, Status: StatusUnknown}
+	if n == nil {
+		return c
+	}
+	c.NodeID, c.NodeName, c.Region = n.ID, n.Name, n.Region
+	if n.Weight > 0 {
+		c.Weight = n.Weight
+	}
+	if n.IsOnline {
+		c.Status = StatusHealthy
+	} else {
+		c.Status = StatusDegraded
+	}
+	return c
+}
+
+// Pick memilih satu kandidat: hanya yang sehat, utamakan region yang cocok, lalu acak sesuai bobot.
+// Kalau tidak ada yang sehat, kandidat pertama dikembalikan dengan ok = false.
+func Pick(cands []Candidate, region string, rnd *rand.Rand) (Candidate, bool) {
+	if len(cands) == 0 {
+		return Candidate{}, false
+	}
+
+	var healthy, local []Candidate
+	for _, c := range cands {
+		if !c.Healthy() {
+			continue
+		}
+		healthy = append(healthy, c)
+		if region != "" && MatchRegion(c.Region, region) {
+			local = append(local, c)
+		}
+	}
+	if len(healthy) == 0 {
+		return cands[0], false
+	}
+	if len(local) > 0 {
+		healthy = local
+	}
+
+	total := 0
+	for _, c := range healthy {
+		total += c.Weight
+	}
+	n := rand.Intn
+	if rnd != nil {
+		n = rnd.Intn
+	}
+	x := n(total)
+	for _, c := range healthy {
+		if x < c.Weight {
+			return c, true
+		}
+		x -= c.Weight
+	}
+	return healthy[len(healthy)-1], true
+}
+
+// MatchRegion mencocokkan region node ("Jakarta, ID", "ID-JKT", "ID") dengan region yang diminta
+// (kode negara atau region persis)
+func MatchRegion(nodeRegion, want string) bool {
+	nodeRegion, want = strings.TrimSpace(nodeRegion), strings.TrimSpace(want)
+	if nodeRegion == "" || want == "" {
+		return false
+	}
+	if strings.EqualFold(nodeRegion, want) {
+		return true
+	}
+	if i := strings.LastIndex(nodeRegion, ", "); i >= 0 && strings.EqualFold(nodeRegion[i+2:], want) {
+		return true
+	}
+	if i := strings.Index(nodeRegion, "-"); i > 0 && strings.EqualFold(nodeRegion[:i], want) {
+		return true
+	}
+	return false
+}
+
+// ShortURL builds URL publik link di domain tertentu
+func ShortURL(domain, alias string) string {
+	return "https://" + domain + "/r/" + alias
+}
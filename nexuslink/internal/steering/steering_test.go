@@ -0,0 +1,95 @@
+package steering
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/afuzapratama/nexuslink/internal/models"
+)
+
+func testNodes() []models.Node {
+	return []models.Node{
+		{ID: "sg", Region: "Singapore, SG", Domains: []string{"sg.example.com"}, IsOnline: true},
+		{ID: "id", Region: "Jakarta, ID", Domains: []string{"id.example.com", "go.example.com"}, IsOnline: true, Weight: 3},
+		{ID: "us", Region: "US-EAST", Domains: []string{"us.example.com"}, IsOnline: false},
+	}
+}
+
+func TestCandidates(t *testing.T) {
+	nodes := testNodes()
+
+	// Pool eksplisit dari Domain + Domains
+	link := &models.Link{Alias: "a", Domain: "US.example.com", Domains: []string{"sg.example.com", "unknown.example.com"}}
+	cands := Candidates(link, nodes)
+	if len(cands) != 3 || cands[0].Domain != "us.example.com" || cands[0].Status != StatusDegraded ||
+		cands[1].Status != StatusHealthy || cands[2].Status != StatusUnknown {
+		t.Errorf("unexpected candidates: %+v", cands)
+	}
+
+	// Tanpa domain: pakai domain milik NodeID
+	cands = Candidates(&models.Link{Alias: "a", NodeID: "id"}, nodes)
+	if len(cands) != 2 || cands[0].Weight != 3 {
+		t.Errorf("node pool: %+v", cands)
+	}
+
+	// Tanpa domain & node: semua domain
+	if cands = Candidates(&models.Link{Alias: "a"}, nodes); len(cands) != 4 {
+		t.Errorf("expected all 4 domains, got %+v", cands)
+	}
+}
+
+func TestPick(t *testing.T) {
+	nodes := testNodes()
+	rnd := rand.New(rand.NewSource(1))
+
+	// Node US offline → tidak pernah dipilih
+	link := &models.Link{Alias: "a", Domains: []string{"us.example.com", "sg.example.com"}}
+	for i := 0; i < 20; i++ {
+		c, ok := Pick(Candidates(link, nodes), "", rnd)
+		if !ok || c.Domain != "sg.example.com" {
+			t.Fatalf("picked %+v ok=%v", c, ok)
+		}
+	}
+
+	// Region cocok diutamakan
+	all := Candidates(&models.Link{Alias: "a"}, nodes)
+	for i := 0; i < 20; i++ {
+		if c, _ := Pick(all, "SG", rnd); c.NodeID != "sg" {
+			t.Fatalf("region SG picked %+v", c)
+		}
+	}
+
+	// Bobot: node id (2 domain x bobot 3) jauh lebih sering dari sg
+	counts := map[string]int{}
+	for i := 0; i < 1000; i++ {
+		c, _ := Pick(all, "", rnd)
+		counts[c.NodeID]++
+	}
+	if counts["id"] < 3*counts["sg"] {
+		t.Errorf("weights not respected: %v", counts)
+	}
+
+	// Semua offline → degraded, tetap ada domain
+	link = &models.Link{Alias: "a", Domain: "us.example.com"}
+	if c, ok := Pick(Candidates(link, nodes), "", rnd); ok || c.Domain != "us.example.com" {
+		t.Errorf("all down: %+v ok=%v", c, ok)
+	}
+}
+
+func TestMatchRegion(t *testing.T) {
+	cases := []struct {
+		node, want string
+		match      bool
+	}{
+		{"Jakarta, ID", "ID", true},
+		{"ID-JKT", "id", true},
+		{"SG", "SG", true},
+		{"Jakarta, ID", "SG", false},
+		{"", "ID", false},
+	}
+	for _, c := range cases {
+		if got := MatchRegion(c.node, c.want); got != c.match {
+			t.Errorf("MatchRegion(%q, %q) = %v", c.node, c.want, got)
+		}
+	}
+}
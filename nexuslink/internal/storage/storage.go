@@ -0,0 +1,179 @@
+// Package storage memilih backend penyimpanan (DynamoDB atau SQL) berdasarkan NEXUS_STORAGE.
+package storage
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"path/filepath"
+	"strings"
+
+	"github.com/afuzapratama/nexuslink/internal/config"
+	"github.com/afuzapratama/nexuslink/internal/database"
+	"github.com/afuzapratama/nexuslink/internal/repository"
+	"github.com/afuzapratama/nexuslink/internal/repository/sqlstore"
+)
+
+const (
+	BackendDynamo   = "dynamodb"
+	BackendSQLite   = "sqlite"
+	BackendPostgres = "postgres"
+)
+
+// Stores berisi semua repository untuk backend yang dipilih
+type Stores struct {
+	Backend string
+
+	Links           repository.LinkStore
+	Nodes           repository.NodeStore
+	NodeEvents      repository.NodeEventStore
+	Clicks          repository.ClickStore
+	Stats           repository.StatsStore
+	Settings        repository.SettingsStore
+	Groups          repository.GroupStore
+	Webhooks        repository.WebhookStore
+	Deliveries      repository.WebhookDeliveryStore
+	Outbox          repository.WebhookOutboxStore
+	Variants        repository.VariantStore
+	Tokens          repository.TokenStore
+	Rollups         repository.RollupStore
+	NodeCredentials repository.NodeCredentialStore
+	Users           repository.UserStore
+	Sessions        repository.SessionStore
+	Audit           repository.AuditStore
+	Revisions       repository.LinkRevisionStore
+	ClickDedup      repository.ClickDedupStore
+
+	close func() error
+}
+
+// Close menutup koneksi database (no-op untuk DynamoDB)
+func (s *Stores) Close() error {
+	if s.close == nil {
+		return nil
+	}
+	return s.close()
+}
+
+// Open membuka backend dari env:
+//
+//	NEXUS_STORAGE=dynamodb (default) → DynamoDB / DynamoDB Local (NEXUS_DYNAMO_ENDPOINT)
+//	NEXUS_STORAGE=sqlite             → file SQLite di NEXUS_SQLITE_PATH (default ./data/nexuslink.db)
+//	NEXUS_STORAGE=postgres           → PostgreSQL di NEXUS_DATABASE_URL
+func Open(ctx context.Context) (*Stores, error) {
+	backend := strings.ToLower(strings.TrimSpace(config.GetEnv("NEXUS_STORAGE", BackendDynamo)))
+
+	switch backend {
+	case BackendDynamo:
+		return openDynamo(ctx)
+	case BackendSQLite:
+		path := config.GetEnv("NEXUS_SQLITE_PATH", filepath.Join("data", "nexuslink.db"))
+		return OpenSQL(ctx, sqlstore.DialectSQLite, path)
+	case BackendPostgres:
+		dsn := config.GetEnv("NEXUS_DATABASE_URL", "")
+		if dsn == "" {
+			return nil, fmt.Errorf("NEXUS_DATABASE_URL is required when NEXUS_STORAGE=postgres")
+		}
+		return OpenSQL(ctx, sqlstore.DialectPostgres, dsn)
+	default:
+		return nil, fmt.Errorf("unknown NEXUS_STORAGE %q (use dynamodb, sqlite or postgres)", backend)
+	}
+}
+
+func openDynamo(ctx context.Context) (*Stores, error) {
+	log.Println("Ensuring DynamoDB tables...")
+	if err := database.EnsureTables(ctx); err != nil {
+		return nil, err
+	}
+	log.Println("DynamoDB tables ready")
+
+	nodeRepo := repository.NewNodeRepository()
+
+	// Isi tabel mapping domain → node dari data node lama
+	if err := nodeRepo.BackfillDomains(ctx); err != nil {
+		log.Printf("warning: node domain backfill failed: %v", err)
+	}
+
+	linkRepo := repository.NewLinkRepository()
+
+	// Reservasi alias untuk link yang dibuat sebelum tabel alias / alias per domain ada
+	if err := linkRepo.BackfillAliases(ctx); err != nil {
+		log.Printf("warning: link alias backfill failed: %v", err)
+	}
+	// Atribut feed untuk link & tombstone lama (list semua link dan delta feed agent lewat index)
+	if err := linkRepo.BackfillFeed(ctx); err != nil {
+		log.Printf("warning: link feed backfill failed: %v", err)
+	}
+
+	// Click lama bisa banyak sekali, jadi backfill feed jalan di background;
+	// sampai selesai list semua click tetap pakai Scan
+	clickRepo := repository.NewClickRepository()
+	go func() {
+		if err := clickRepo.BackfillFeed(context.Background()); err != nil {
+			log.Printf("warning: click feed backfill failed: %v", err)
+		}
+	}()
+
+	return &Stores{
+		Backend:         BackendDynamo,
+		Links:           linkRepo,
+		Nodes:           nodeRepo,
+		NodeEvents:      repository.NewNodeEventRepository(),
+		Clicks:          clickRepo,
+		Stats:           repository.NewLinkStatsRepository(),
+		Settings:        repository.NewSettingsRepository(),
+		Groups:          repository.NewLinkGroupRepository(),
+		Webhooks:        repository.NewWebhookRepository(database.Client(), database.WebhooksTableName),
+		Deliveries:      repository.NewWebhookDeliveryRepository(),
+		Outbox:          repository.NewWebhookOutboxRepository(),
+		Variants:        repository.NewLinkVariantRepository(database.Client()),
+		Tokens:          repository.NewNodeTokenRepository(),
+		Rollups:         repository.NewRollupRepository(),
+		NodeCredentials: repository.NewNodeCredentialRepository(),
+		Users:           repository.NewUserRepository(),
+		Sessions:        repository.NewSessionRepository(),
+		Audit:           repository.NewAuditRepository(),
+		Revisions:       repository.NewLinkRevisionRepository(),
+		ClickDedup:      repository.NewClickDedupRepository(),
+	}, nil
+}
+
+// OpenSQL membuka backend SQL (schema di-migrate otomatis)
+func OpenSQL(ctx context.Context, dialect, dsn string) (*Stores, error) {
+	db, err := sqlstore.Open(ctx, dialect, dsn)
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("SQL storage ready (%s)", dialect)
+
+	linkRepo := sqlstore.NewLinkRepository(db)
+
+	// Reservasi (domain, alias) untuk link ber-domain yang dibuat sebelum alias per domain
+	if err := linkRepo.BackfillAliases(ctx); err != nil {
+		log.Printf("warning: link alias backfill failed: %v", err)
+	}
+
+	return &Stores{
+		Backend:         dialect,
+		Links:           linkRepo,
+		Nodes:           sqlstore.NewNodeRepository(db),
+		NodeEvents:      sqlstore.NewNodeEventRepository(db),
+		Clicks:          sqlstore.NewClickRepository(db),
+		Stats:           sqlstore.NewLinkStatsRepository(db),
+		Settings:        sqlstore.NewSettingsRepository(db),
+		Groups:          sqlstore.NewLinkGroupRepository(db),
+		Webhooks:        sqlstore.NewWebhookRepository(db),
+		Deliveries:      sqlstore.NewWebhookDeliveryRepository(db),
+		Outbox:          sqlstore.NewWebhookOutboxRepository(db),
+		Variants:        sqlstore.NewLinkVariantRepository(db),
+		Tokens:          sqlstore.NewNodeTokenRepository(db),
+		Rollups:         sqlstore.NewRollupRepository(db),
+		NodeCredentials: sqlstore.NewNodeCredentialRepository(db),
+		Users:           sqlstore.NewUserRepository(db),
+		Sessions:        sqlstore.NewSessionRepository(db),
+		Audit:           sqlstore.NewAuditRepository(db),
+		Revisions:       sqlstore.NewLinkRevisionRepository(db),
+		ClickDedup:      sqlstore.NewClickDedupRepository(db),
+		close:           db.Close,
+	}, nil
+}
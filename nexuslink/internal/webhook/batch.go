@@ -0,0 +1,95 @@
+package webhook
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/afuzapratama/nexuslink/internal/models"
+)
+
+// pendingBatch: event click.created (body hasil Render) yang belum masuk outbox untuk satu webhook
+type pendingBatch struct {
+	items [][]byte
+	timer *time.Timer
+}
+
+// batched true kalau event dikumpulkan dulu untuk webhook ini
+func batched(wh *models.Webhook, event string) bool {
+	return wh.Batch != nil && event == models.EventClickCreated
+}
+
+// addToBatch menambahkan event ke batch webhook. Batch masuk outbox sebagai satu entry
+// saat penuh (langsung, di request ini) atau saat window sejak event pertama habis.
+// Sebelum itu event hanya ada di memory: crash (bukan shutdown biasa) kehilangan paling banyak satu window.
+func (d *Dispatcher) addToBatch(wh *models.Webhook, body []byte) {
+	d.bmu.Lock()
+	b := d.batches[wh.ID]
+	if b == nil {
+		b = &pendingBatch{}
+		d.batches[wh.ID] = b
+		webhookID := wh.ID
+		b.timer = time.AfterFunc(wh.Batch.MaxWait(), func() { d.flushBatch(webhookID, b) })
+	}
+	b.items = append(b.items, body)
+
+	var full [][]byte
+	if len(b.items) >= wh.Batch.MaxSize() {
+		b.timer.Stop()
+		delete(d.batches, wh.ID)
+		full = b.items
+	}
+	d.bmu.Unlock()
+
+	if full != nil {
+		d.enqueueBatch(wh.ID, full)
+	}
+}
+
+// flushBatch dipanggil timer window; no-op kalau batch sudah dikirim karena penuh atau shutdown
+func (d *Dispatcher) flushBatch(webhookID string, b *pendingBatch) {
+	d.bmu.Lock()
+	if d.batches[webhookID] != b {
+		d.bmu.Unlock()
+		return
+	}
+	delete(d.batches, webhookID)
+	d.bmu.Unlock()
+
+	d.enqueueBatch(webhookID, b.items)
+}
+
+// flushBatches memasukkan semua batch yang belum penuh ke outbox (saat shutdown)
+func (d *Dispatcher) flushBatches() {
+	d.bmu.Lock()
+	batches := d.batches
+	d.batches = map[string]*pendingBatch{}
+	d.bmu.Unlock()
+
+	for webhookID, b := range batches {
+		b.timer.Stop()
+		d.enqueueBatch(webhookID, b.items)
+	}
+}
+
+// enqueueBatch menulis batch sebagai satu entry outbox: retry, signature dan delivery log
+// berlaku untuk seluruh batch sekaligus
+func (d *Dispatcher) enqueueBatch(webhookID string, items [][]byte) {
+	now := time.Now()
+	entry := models.WebhookOutboxEntry{
+		ID:            uuid.Must(uuid.NewV7()).String(),
+		WebhookID:     webhookID,
+		Event:         models.EventClickCreated,
+		Payload:       encodeBatch(items),
+		Batch:         len(items),
+		NextAttemptAt: now,
+		CreatedAt:     now,
+	}
+	if err := d.outbox.Enqueue(context.Background(), []models.WebhookOutboxEntry{entry}); err != nil {
+		log.Printf("Webhook batch enqueue failed: id=%s events=%d error=%v", webhookID, len(items), err)
+		return
+	}
+	d.notify()
+}
@@ -0,0 +1,331 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/afuzapratama/nexuslink/internal/models"
+	"github.com/afuzapratama/nexuslink/internal/repository"
+)
+
+const (
+	DefaultWorkers             = 8
+	DefaultEndpointConcurrency = 2
+	DefaultRetryHorizon        = 24 * time.Hour
+
+	// claimLease harus lebih lama dari satu attempt (http timeout 10s)
+	claimLease   = 2 * time.Minute
+	pollInterval = 2 * time.Second
+	maxBackoff   = time.Hour
+)
+
+// Dispatcher menulis event webhook ke outbox di request yang memicunya, lalu worker pool
+// mengirimnya di background: paling banyak workers delivery jalan bersamaan dan paling banyak
+// perEndpoint per webhook, retry dengan exponential backoff + jitter sampai horizon habis.
+// Entry baru dihapus dari outbox setelah hasil attempt tercatat, jadi restart tidak menghilangkan
+// event (at-least-once; receiver dedupe lewat header X-Webhook-Id).
+type Dispatcher struct {
+	sender   *Sender
+	webhooks repository.WebhookStore
+	outbox   repository.WebhookOutboxStore
+
+	workers     int
+	perEndpoint int
+	horizon     time.Duration
+	backoff     time.Duration // backoff attempt pertama
+	poll        time.Duration
+
+	wake     chan struct{}
+	wg       sync.WaitGroup
+	mu       sync.Mutex
+	busy     int
+	inflight map[string]int // per webhook ID
+
+	bmu     sync.Mutex
+	batches map[string]*pendingBatch // per webhook ID, lihat batch.go
+
+	smu  sync.Mutex
+	subs map[string]*subscription // per event, lihat subscriptions.go
+}
+
+// NewDispatcher creates a dispatcher; nilai <= 0 pakai default
+func NewDispatcher(sender *Sender, webhooks repository.WebhookStore, outbox repository.WebhookOutboxStore, workers, perEndpoint int, horizon time.Duration) *Dispatcher {
+	if workers <= 0 {
+		workers = DefaultWorkers
+	}
+	if perEndpoint <= 0 {
+		perEndpoint = DefaultEndpointConcurrency
+	}
+	if horizon <= 0 {
+		horizon = DefaultRetryHorizon
+	}
+	return &Dispatcher{
+		sender:      sender,
+		webhooks:    webhooks,
+		outbox:      outbox,
+		workers:     workers,
+		perEndpoint: perEndpoint,
+		horizon:     horizon,
+		backoff:     initialBackoff,
+		poll:        pollInterval,
+		wake:        make(chan struct{}, 1),
+		inflight:    map[string]int{},
+		batches:     map[string]*pendingBatch{},
+		subs:        map[string]*subscription{},
+	}
+}
+
+// Publish menulis event ke outbox untuk semua webhook aktif yang subscribe dan filter-nya cocok,
+// dengan body yang sudah di-render sesuai format masing-masing webhook.
+// click.created untuk webhook dengan Batch dikumpulkan dulu (lihat addToBatch).
+// Dipanggil sinkron di request; daftar webhook diambil dari cache (lihat subscribers) dan
+// penulisan tidak ikut batal kalau client disconnect.
+func (d *Dispatcher) Publish(ctx context.Context, event string, data map[string]interface{}) error {
+	ctx = context.WithoutCancel(ctx)
+
+	webhooks, err := d.subscribers(ctx, event)
+	if err != nil {
+		return fmt.Errorf("get webhooks: %w", err)
+	}
+	if len(webhooks) == 0 {
+		return nil // No webhooks subscribed to this event
+	}
+
+	now := time.Now()
+	payload := &models.WebhookPayload{
+		Event:     event,
+		Timestamp: now,
+		Data:      data,
+	}
+
+	entries := make([]models.WebhookOutboxEntry, 0, len(webhooks))
+	for i := range webhooks {
+		wh := &webhooks[i]
+		if !wh.Filter.Matches(data) {
+			continue
+		}
+		body, err := Render(wh, payload)
+		if err != nil {
+			// Template rusak tidak boleh menahan webhook lain
+			log.Printf("Webhook render failed: id=%s event=%s error=%v", wh.ID, event, err)
+			continue
+		}
+		if batched(wh, event) {
+			d.addToBatch(wh, body)
+			continue
+		}
+		entries = append(entries, models.WebhookOutboxEntry{
+			ID:            uuid.Must(uuid.NewV7()).String(),
+			WebhookID:     wh.ID,
+			Event:         event,
+			Payload:       body,
+			NextAttemptAt: now,
+			CreatedAt:     now,
+		})
+	}
+	if len(entries) == 0 {
+		return nil
+	}
+	if err := d.outbox.Enqueue(ctx, entries); err != nil {
+		return fmt.Errorf("enqueue: %w", err)
+	}
+	d.notify()
+	return nil
+}
+
+// Run mengosongkan outbox sampai ctx selesai, lalu memasukkan batch yang belum penuh ke outbox
+// (dikirim setelah start berikutnya) dan menunggu delivery yang sedang jalan
+func (d *Dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.poll)
+	defer ticker.Stop()
+
+	for {
+		d.drain(ctx)
+		select {
+		case <-ctx.Done():
+			d.flushBatches()
+			d.wg.Wait()
+			return
+		case <-ticker.C:
+		case <-d.wake:
+		}
+	}
+}
+
+// drain meng-claim entry jatuh tempo sebanyak slot worker yang kosong
+func (d *Dispatcher) drain(ctx context.Context) {
+	for ctx.Err() == nil {
+		d.mu.Lock()
+		free := d.workers - d.busy
+		d.mu.Unlock()
+		if free <= 0 {
+			return
+		}
+
+		now := time.Now()
+		entries, err := d.outbox.Claim(ctx, now, claimLease, free)
+		if err != nil {
+			log.Printf("Webhook outbox claim failed: %v", err)
+		}
+		for _, e := range entries {
+			if !d.acquire(e.WebhookID) {
+				// Endpoint sudah penuh: kembalikan ke antrian tanpa menghitung attempt
+				e.NextAttemptAt = now.Add(d.poll)
+				if err := d.outbox.Reschedule(ctx, &e); err != nil {
+					log.Printf("Webhook outbox reschedule failed: id=%s error=%v", e.ID, err)
+				}
+				continue
+			}
+
+			d.wg.Add(1)
+			go func(e models.WebhookOutboxEntry) {
+				defer d.wg.Done()
+				defer d.release(e.WebhookID)
+				// Attempt yang sudah jalan diselesaikan walau shutdown
+				d.process(context.WithoutCancel(ctx), &e)
+			}(e)
+		}
+		if len(entries) < free {
+			return
+		}
+	}
+}
+
+func (d *Dispatcher) acquire(webhookID string) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.busy >= d.workers || d.inflight[webhookID] >= d.perEndpoint {
+		return false
+	}
+	d.busy++
+	d.inflight[webhookID]++
+	return true
+}
+
+func (d *Dispatcher) release(webhookID string) {
+	d.mu.Lock()
+	d.busy--
+	if d.inflight[webhookID]--; d.inflight[webhookID] <= 0 {
+		delete(d.inflight, webhookID)
+	}
+	d.mu.Unlock()
+	d.notify()
+}
+
+func (d *Dispatcher) notify() {
+	select {
+	case d.wake <- struct{}{}:
+	default:
+	}
+}
+
+// process menjalankan satu attempt untuk entry lalu menghapus atau menjadwalkan ulang entry
+func (d *Dispatcher) process(ctx context.Context, e *models.WebhookOutboxEntry) {
+	wh, err := d.webhooks.GetByID(ctx, e.WebhookID)
+	if errors.Is(err, repository.ErrWebhookNotFound) {
+		log.Printf("Webhook outbox: webhook %s deleted, dropping event %s", e.WebhookID, e.ID)
+		d.remove(ctx, e)
+		return
+	}
+	if err != nil {
+		e.LastError = err.Error()
+		d.reschedule(ctx, e, d.backoffFor(e.Attempts+1))
+		return
+	}
+
+	delivery := d.openDelivery(ctx, wh, e)
+	if !wh.IsActive {
+		log.Printf("Webhook outbox: webhook %s disabled, dead-lettering event %s", wh.ID, e.ID)
+		d.sender.finishDelivery(ctx, delivery, models.DeliveryFailed)
+		d.remove(ctx, e)
+		return
+	}
+
+	e.Attempts++
+	result := d.sender.attemptDelivery(ctx, wh, e.Payload, e.Batch, e.ID, e.Attempts)
+	result.DeliveryID = e.ID
+
+	switch {
+	case result.Success:
+		log.Printf("Webhook delivered successfully: id=%s url=%s event=%s attempt=%d status=%d",
+			wh.ID, wh.URL, e.Event, e.Attempts, result.StatusCode)
+		d.sender.recordAttempt(ctx, delivery, result, models.DeliverySuccess)
+		recordDelivery(e.Event, "success", e.CreatedAt)
+		d.remove(ctx, e)
+		return
+	case isClientError(result.StatusCode):
+		// Don't retry on client errors (4xx)
+		log.Printf("Webhook delivery aborted due to client error: id=%s status=%d", wh.ID, result.StatusCode)
+		d.sender.recordAttempt(ctx, delivery, result, models.DeliveryFailed)
+		recordDelivery(e.Event, "client_error", e.CreatedAt)
+		d.remove(ctx, e)
+		return
+	}
+
+	backoff := d.backoffFor(e.Attempts)
+	if time.Since(e.CreatedAt)+backoff > d.horizon {
+		log.Printf("Webhook delivery exhausted retries: id=%s url=%s event=%s attempts=%d", wh.ID, wh.URL, e.Event, e.Attempts)
+		d.sender.recordAttempt(ctx, delivery, result, models.DeliveryFailed)
+		recordDelivery(e.Event, "failed", e.CreatedAt)
+		d.remove(ctx, e)
+		return
+	}
+
+	log.Printf("Webhook delivery failed: id=%s url=%s event=%s attempt=%d retry_in=%s error=%v",
+		wh.ID, wh.URL, e.Event, e.Attempts, backoff.Round(time.Second), result.Error)
+	d.sender.recordAttempt(ctx, delivery, result, models.DeliveryPending)
+	e.LastError = result.Error.Error()
+	d.reschedule(ctx, e, backoff)
+}
+
+// openDelivery returns record delivery log untuk entry (dibuat saat attempt pertama, ID sama dengan entry)
+func (d *Dispatcher) openDelivery(ctx context.Context, wh *models.Webhook, e *models.WebhookOutboxEntry) *models.WebhookDelivery {
+	if e.Attempts > 0 && d.sender.deliveries != nil {
+		delivery, err := d.sender.deliveries.Get(ctx, wh.ID, e.ID)
+		if err != nil {
+			log.Printf("Webhook delivery log get failed: id=%s error=%v", e.ID, err)
+		}
+		if delivery != nil {
+			return delivery
+		}
+	}
+	return d.sender.startDelivery(ctx, wh, &models.WebhookDelivery{
+		ID:        e.ID,
+		Event:     e.Event,
+		Payload:   e.Payload,
+		Batch:     e.Batch,
+		CreatedAt: e.CreatedAt,
+	})
+}
+
+func (d *Dispatcher) reschedule(ctx context.Context, e *models.WebhookOutboxEntry, backoff time.Duration) {
+	e.NextAttemptAt = time.Now().Add(backoff)
+	if err := d.outbox.Reschedule(ctx, e); err != nil {
+		// Entry tetap di-claim ulang setelah lease habis
+		log.Printf("Webhook outbox reschedule failed: id=%s error=%v", e.ID, err)
+	}
+}
+
+func (d *Dispatcher) remove(ctx context.Context, e *models.WebhookOutboxEntry) {
+	if err := d.outbox.Delete(ctx, e.ID); err != nil {
+		log.Printf("Webhook outbox delete failed: id=%s error=%v", e.ID, err)
+	}
+}
+
+// backoffFor: backoff * 2^(attempt-1) (maks maxBackoff), dengan jitter 50-100% supaya retry
+// ke endpoint yang sama tidak serempak
+func (d *Dispatcher) backoffFor(attempt int) time.Duration {
+	backoff := d.backoff
+	for i := 1; i < attempt && backoff < maxBackoff; i++ {
+		backoff *= 2
+	}
+	backoff = min(backoff, maxBackoff)
+	return backoff/2 + rand.N(backoff/2+1)
+}
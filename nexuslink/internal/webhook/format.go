@@ -0,0 +1,216 @@
+package webhook
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/afuzapratama/nexuslink/internal/models"
+)
+
+// maxDiscordContent: batas panjang field content Discord
+const maxDiscordContent = 2000
+
+var templateFuncs = template.FuncMap{
+	"json": func(v interface{}) (string, error) {
+		b, err := json.Marshal(v)
+		return string(b), err
+	},
+	"summary": Summary,
+}
+
+// Validate memeriksa Format, Template dan Batch webhook sebelum disimpan
+func Validate(wh *models.Webhook) error {
+	switch wh.Format {
+	case "", models.WebhookFormatJSON, models.WebhookFormatSlack, models.WebhookFormatDiscord:
+	case models.WebhookFormatTemplate:
+		if strings.TrimSpace(wh.Template) == "" {
+			return errors.New("template is required for format template")
+		}
+		if _, err := parseTemplate(wh.Template); err != nil {
+			return fmt.Errorf("invalid template: %w", err)
+		}
+	default:
+		return fmt.Errorf("unknown format %q", wh.Format)
+	}
+
+	b := wh.Batch
+	if b == nil {
+		return nil
+	}
+	// Pesan chat tidak bisa berisi array event
+	if wh.Format == models.WebhookFormatSlack || wh.Format == models.WebhookFormatDiscord {
+		return fmt.Errorf("batch delivery is not supported for format %s", wh.Format)
+	}
+	if b.Size < 0 || b.Size > models.MaxWebhookBatchSize {
+		return fmt.Errorf("batch size must be between 1 and %d", models.MaxWebhookBatchSize)
+	}
+	if b.Window < 0 || b.Window > models.MaxWebhookBatchWindow {
+		return fmt.Errorf("batch window must be between 1 and %d seconds", models.MaxWebhookBatchWindow)
+	}
+	switch b.Encoding {
+	case "", models.WebhookBatchJSON, models.WebhookBatchNDJSON:
+		return nil
+	default:
+		return fmt.Errorf("unknown batch encoding %q", b.Encoding)
+	}
+}
+
+// Render membuat body request untuk webhook sesuai Format-nya
+func Render(wh *models.Webhook, payload *models.WebhookPayload) ([]byte, error) {
+	switch wh.Format {
+	case "", models.WebhookFormatJSON:
+		return json.Marshal(payload)
+	case models.WebhookFormatSlack:
+		return json.Marshal(map[string]string{"text": Summary(payload)})
+	case models.WebhookFormatDiscord:
+		return json.Marshal(map[string]string{"content": truncate(Summary(payload), maxDiscordContent-len("…"))})
+	case models.WebhookFormatTemplate:
+		tmpl, err := compiledTemplate(wh)
+		if err != nil {
+			return nil, err
+		}
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, payload); err != nil {
+			return nil, err
+		}
+		// Signature & Content-Type tetap JSON, jadi hasil template harus JSON valid
+		if !json.Valid(buf.Bytes()) {
+			return nil, errors.New("template output is not valid JSON")
+		}
+		return buf.Bytes(), nil
+	default:
+		return nil, fmt.Errorf("unknown format %q", wh.Format)
+	}
+}
+
+// encodeBatch menggabungkan body event (hasil Render) menjadi JSON array
+func encodeBatch(items [][]byte) []byte {
+	var buf bytes.Buffer
+	buf.WriteByte('[')
+	for i, item := range items {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		buf.Write(item)
+	}
+	buf.WriteByte(']')
+	return buf.Bytes()
+}
+
+// wireBody returns body dan Content-Type yang dikirim. Batch disimpan sebagai JSON array;
+// untuk encoding ndjson array dipecah jadi satu event per baris (signature dihitung dari hasil ini).
+func wireBody(wh *models.Webhook, payload []byte, batch int) ([]byte, string, error) {
+	if batch == 0 || wh.Batch == nil || wh.Batch.Encoding != models.WebhookBatchNDJSON {
+		return payload, "application/json", nil
+	}
+	var items []json.RawMessage
+	if err := json.Unmarshal(payload, &items); err != nil {
+		return nil, "", fmt.Errorf("batch payload is not a JSON array: %w", err)
+	}
+	var buf bytes.Buffer
+	for _, item := range items {
+		// Output template bisa multi-baris
+		if err := json.Compact(&buf, item); err != nil {
+			return nil, "", err
+		}
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes(), "application/x-ndjson", nil
+}
+
+// templates: template yang sudah di-compile per webhook ID, dipakai ulang selama UpdatedAt
+// (dan teksnya) sama. text/template aman dieksekusi bersamaan.
+var templates sync.Map // webhook ID -> cachedTemplate
+
+type cachedTemplate struct {
+	updatedAt time.Time
+	text      string
+	tmpl      *template.Template
+}
+
+func compiledTemplate(wh *models.Webhook) (*template.Template, error) {
+	if wh.ID == "" {
+		return parseTemplate(wh.Template)
+	}
+	if v, ok := templates.Load(wh.ID); ok {
+		if c := v.(cachedTemplate); c.updatedAt.Equal(wh.UpdatedAt) && c.text == wh.Template {
+			return c.tmpl, nil
+		}
+	}
+	tmpl, err := parseTemplate(wh.Template)
+	if err != nil {
+		return nil, err
+	}
+	templates.Store(wh.ID, cachedTemplate{updatedAt: wh.UpdatedAt, text: wh.Template, tmpl: tmpl})
+	return tmpl, nil
+}
+
+func parseTemplate(text string) (*template.Template, error) {
+	return template.New("webhook").Funcs(templateFuncs).Option("missingkey=zero").Parse(text)
+}
+
+// Summary returns satu baris teks yang bisa dibaca manusia untuk event (dipakai format Slack/Discord)
+func Summary(payload *models.WebhookPayload) string {
+	d := payload.Data
+	str := func(key string) string {
+		if v, ok := d[key]; ok && v != nil {
+			return fmt.Sprint(v)
+		}
+		return ""
+	}
+	link := str("alias")
+	if domain := str("domain"); domain != "" {
+		link = domain + "/" + link
+	}
+
+	switch payload.Event {
+	case models.EventClickCreated:
+		who := "visitor"
+		if bot, _ := d["isBot"].(bool); bot {
+			who = "bot"
+		}
+		s := fmt.Sprintf("🔗 Click on %s by %s", link, who)
+		if c := str("country"); c != "" {
+			s += " from " + c
+		}
+		if n := str("nodeId"); n != "" {
+			s += " via node " + n
+		}
+		return s
+	case models.EventTrafficBlocked:
+		return fmt.Sprintf("🚫 Traffic blocked on %s (%s) from %s", link, str("reason"), str("ipAddress"))
+	case models.EventLinkExpired:
+		return fmt.Sprintf("⏰ Link %s expired", link)
+	case models.EventLinkMaxClicks:
+		return fmt.Sprintf("🛑 Link %s reached max clicks (%s)", link, str("maxClicks"))
+	case models.EventLinkCreated:
+		return fmt.Sprintf("✨ Link %s created → %s", link, str("targetUrl"))
+	case models.EventLinkUpdated:
+		return fmt.Sprintf("✏️ Link %s updated → %s", link, str("targetUrl"))
+	case models.EventLinkDeleted:
+		return fmt.Sprintf("🗑️ Link %s deleted", link)
+	case models.EventNodeOffline:
+		return fmt.Sprintf("🔴 Node %s (%s) is offline", str("nodeName"), str("nodeId"))
+	case models.EventNodeOnline:
+		return fmt.Sprintf("🟢 Node %s (%s) is back online", str("nodeName"), str("nodeId"))
+	}
+
+	// Event lain: nama event + field data urut key
+	keys := make([]string, 0, len(d))
+	for k := range d {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	parts := []string{payload.Event}
+	for _, k := range keys {
+		parts = append(parts, fmt.Sprintf("%s=%v", k, d[k]))
+	}
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,84 @@
+package webhook
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/afuzapratama/nexuslink/internal/models"
+)
+
+// subscriptionTTL: umur cache webhook per event di Publish. Perubahan dari instance API lain
+// terlihat paling lama setelah ini; perubahan di instance sendiri langsung lewat Refresh.
+const subscriptionTTL = 30 * time.Second
+
+// subscription: hasil GetByEvent yang di-cache supaya Publish di redirect path tidak query store
+type subscription struct {
+	webhooks   []models.Webhook
+	fetchedAt  time.Time
+	refreshing bool
+}
+
+// subscribers returns webhook aktif yang subscribe event. Hanya load pertama per event yang menunggu
+// store; setelah itu cache yang kedaluwarsa di-refresh di background dan Publish memakai data lama.
+func (d *Dispatcher) subscribers(ctx context.Context, event string) ([]models.Webhook, error) {
+	d.smu.Lock()
+	sub, ok := d.subs[event]
+	if ok && time.Since(sub.fetchedAt) > subscriptionTTL && !sub.refreshing {
+		sub.refreshing = true
+		go func() {
+			if err := d.load(context.WithoutCancel(ctx), event); err != nil {
+				log.Printf("Webhook subscriptions refresh failed: event=%s error=%v", event, err)
+			}
+		}()
+	}
+	d.smu.Unlock()
+
+	if ok {
+		return sub.webhooks, nil
+	}
+	if err := d.load(ctx, event); err != nil {
+		return nil, err
+	}
+	d.smu.Lock()
+	defer d.smu.Unlock()
+	return d.subs[event].webhooks, nil
+}
+
+// load membaca webhook untuk event dari store ke cache
+func (d *Dispatcher) load(ctx context.Context, event string) error {
+	webhooks, err := d.webhooks.GetByEvent(ctx, event)
+
+	d.smu.Lock()
+	defer d.smu.Unlock()
+	if err != nil {
+		if sub := d.subs[event]; sub != nil {
+			sub.refreshing = false
+		}
+		return err
+	}
+	d.subs[event] = &subscription{webhooks: webhooks, fetchedAt: time.Now()}
+	return nil
+}
+
+// Refresh membaca ulang webhook untuk semua event yang sudah di-cache. Dipanggil setelah webhook
+// dibuat, diubah atau dihapus supaya event berikutnya langsung memakai konfigurasi baru.
+func (d *Dispatcher) Refresh(ctx context.Context) error {
+	d.smu.Lock()
+	events := make([]string, 0, len(d.subs))
+	for event := range d.subs {
+		events = append(events, event)
+	}
+	d.smu.Unlock()
+
+	for _, event := range events {
+		if err := d.load(ctx, event); err != nil {
+			// Paksa load ulang di Publish berikutnya daripada memakai data lama
+			d.smu.Lock()
+			delete(d.subs, event)
+			d.smu.Unlock()
+			return err
+		}
+	}
+	return nil
+}
@@ -6,87 +6,167 @@ import (
 	"crypto/hmac"
 	"crypto/sha256"
 	"encoding/hex"
-	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
+	"unicode/utf8"
 
+	"github.com/google/uuid"
+
+	"github.com/afuzapratama/nexuslink/internal/metrics"
 	"github.com/afuzapratama/nexuslink/internal/models"
+	"github.com/afuzapratama/nexuslink/internal/repository"
 )
 
 const (
 	maxRetries     = 3
 	initialBackoff = 1 * time.Second
+
+	// maxLoggedResponse: response body yang disimpan di delivery log
+	maxLoggedResponse = 2048
+
+	// DefaultSignatureTolerance: selisih maksimal timestamp signature dengan jam receiver
+	DefaultSignatureTolerance = 5 * time.Minute
+)
+
+var (
+	ErrSignatureFormat   = errors.New("webhook: malformed signature header")
+	ErrSignatureExpired  = errors.New("webhook: signature timestamp outside tolerance")
+	ErrSignatureMismatch = errors.New("webhook: no matching signature")
 )
 
 // DeliveryResult represents the result of a webhook delivery attempt
 type DeliveryResult struct {
+	DeliveryID   string
+	RequestID    string
 	Success      bool
 	StatusCode   int
 	ResponseBody string
 	Error        error
 	Attempt      int
+	Latency      time.Duration
 }
 
 // Sender handles webhook delivery with retry logic
 type Sender struct {
 	httpClient *http.Client
+	deliveries repository.WebhookDeliveryStore
+	backoff    time.Duration
 }
 
-// NewSender creates a new webhook sender
-func NewSender() *Sender {
+// NewSender creates a new webhook sender. Setiap attempt dicatat ke deliveries
+// dan delivery yang gagal final masuk dead letter (nil = tidak dicatat).
+func NewSender(deliveries repository.WebhookDeliveryStore) *Sender {
 	return &Sender{
 		httpClient: &http.Client{
 			Timeout: 10 * time.Second,
 		},
+		deliveries: deliveries,
+		backoff:    initialBackoff,
 	}
 }
 
 // SendWebhook sends a webhook payload to the specified URL with HMAC signature
 func (s *Sender) SendWebhook(ctx context.Context, webhook *models.Webhook, payload *models.WebhookPayload) (*DeliveryResult, error) {
-	// Marshal payload to JSON
-	payloadBytes, err := json.Marshal(payload)
+	// Body sesuai format webhook (json, template, slack, discord)
+	payloadBytes, err := Render(webhook, payload)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal payload: %w", err)
+		return nil, fmt.Errorf("failed to render payload: %w", err)
+	}
+
+	return s.deliver(ctx, webhook, payload.Event, payloadBytes, 0, "")
+}
+
+// Replay mengirim ulang payload delivery lama (persis byte yang sama, signature dengan secret sekarang)
+// sebagai delivery baru. Delivery asal yang gagal ditandai replayed dan dikeluarkan dari dead letter.
+// ReplayOf dan X-Webhook-Id selalu menunjuk delivery pertama, jadi receiver bisa dedupe.
+func (s *Sender) Replay(ctx context.Context, webhook *models.Webhook, orig *models.WebhookDelivery) (*DeliveryResult, error) {
+	first := orig.ID
+	if orig.ReplayOf != "" {
+		first = orig.ReplayOf
+	}
+	result, err := s.deliver(ctx, webhook, orig.Event, orig.Payload, orig.Batch, first)
+	if s.deliveries == nil || result == nil {
+		return result, err
+	}
+
+	logCtx := context.WithoutCancel(ctx)
+	if orig.Status == models.DeliveryFailed {
+		orig.Status = models.DeliveryReplayed
+		orig.UpdatedAt = time.Now()
+		if err := s.deliveries.Update(logCtx, orig); err != nil {
+			log.Printf("Webhook delivery log update failed: id=%s error=%v", orig.ID, err)
+		}
+	}
+	if err := s.deliveries.DeleteDeadLetter(logCtx, orig.WebhookID, orig.ID); err != nil {
+		log.Printf("Webhook dead letter delete failed: id=%s error=%v", orig.ID, err)
 	}
+	return result, err
+}
 
-	// Generate HMAC signature
-	signature := generateSignature(payloadBytes, webhook.Secret)
+// deliver mengirim body dengan retry + exponential backoff; setiap attempt dicatat ke delivery log
+func (s *Sender) deliver(ctx context.Context, webhook *models.Webhook, event string, payloadBytes []byte, batch int, replayOf string) (*DeliveryResult, error) {
+	// Log tetap ditulis walaupun ctx (mis. request HTTP) sudah selesai
+	logCtx := context.WithoutCancel(ctx)
+	delivery := s.startDelivery(logCtx, webhook, &models.WebhookDelivery{
+		Event:    event,
+		Payload:  payloadBytes,
+		Batch:    batch,
+		ReplayOf: replayOf,
+	})
+	idempotencyKey := delivery.ID
+	if replayOf != "" {
+		idempotencyKey = replayOf
+	}
 
 	// Retry logic with exponential backoff
-	backoff := initialBackoff
+	start := time.Now()
+	backoff := s.backoff
 	for attempt := 1; attempt <= maxRetries; attempt++ {
-		result := s.attemptDelivery(ctx, webhook.URL, payloadBytes, signature, attempt)
+		result := s.attemptDelivery(ctx, webhook, payloadBytes, batch, idempotencyKey, attempt)
+		result.DeliveryID = delivery.ID
 
 		if result.Success {
 			log.Printf("Webhook delivered successfully: id=%s url=%s event=%s attempt=%d status=%d",
-				webhook.ID, webhook.URL, payload.Event, attempt, result.StatusCode)
+				webhook.ID, webhook.URL, event, attempt, result.StatusCode)
+			s.recordAttempt(logCtx, delivery, result, models.DeliverySuccess)
+			recordDelivery(event, "success", start)
 			return result, nil
 		}
 
 		// Log failed attempt
 		log.Printf("Webhook delivery failed: id=%s url=%s event=%s attempt=%d/%d error=%v",
-			webhook.ID, webhook.URL, payload.Event, attempt, maxRetries, result.Error)
+			webhook.ID, webhook.URL, event, attempt, maxRetries, result.Error)
 
 		// Don't retry on client errors (4xx)
-		if result.StatusCode >= 400 && result.StatusCode < 500 {
+		if isClientError(result.StatusCode) {
 			log.Printf("Webhook delivery aborted due to client error: id=%s status=%d", webhook.ID, result.StatusCode)
+			s.recordAttempt(logCtx, delivery, result, models.DeliveryFailed)
+			recordDelivery(event, "client_error", start)
 			return result, nil
 		}
 
 		// Wait before next retry (except on last attempt)
 		if attempt < maxRetries {
+			s.recordAttempt(logCtx, delivery, result, models.DeliveryPending)
 			select {
 			case <-ctx.Done():
+				s.finishDelivery(logCtx, delivery, models.DeliveryFailed)
+				recordDelivery(event, "canceled", start)
 				return result, ctx.Err()
 			case <-time.After(backoff):
 				backoff *= 2 // Exponential backoff: 1s, 2s, 4s
 			}
 		} else {
 			// Last attempt failed
-			log.Printf("Webhook delivery exhausted retries: id=%s url=%s event=%s", webhook.ID, webhook.URL, payload.Event)
+			log.Printf("Webhook delivery exhausted retries: id=%s url=%s event=%s", webhook.ID, webhook.URL, event)
+			s.recordAttempt(logCtx, delivery, result, models.DeliveryFailed)
+			recordDelivery(event, "failed", start)
 			return result, nil
 		}
 	}
@@ -94,23 +174,103 @@ func (s *Sender) SendWebhook(ctx context.Context, webhook *models.Webhook, paylo
 	return nil, fmt.Errorf("unexpected error: retry loop ended without result")
 }
 
-// attemptDelivery makes a single HTTP POST attempt to deliver the webhook
-func (s *Sender) attemptDelivery(ctx context.Context, url string, payloadBytes []byte, signature string, attempt int) *DeliveryResult {
+// startDelivery melengkapi delivery (Event, Payload, ReplayOf dari caller; ID dan CreatedAt
+// diisi kalau kosong) lalu menyimpannya dengan status pending sebelum attempt pertama
+func (s *Sender) startDelivery(ctx context.Context, webhook *models.Webhook, delivery *models.WebhookDelivery) *models.WebhookDelivery {
+	now := time.Now()
+	if delivery.ID == "" {
+		delivery.ID = uuid.Must(uuid.NewV7()).String()
+	}
+	if delivery.CreatedAt.IsZero() {
+		delivery.CreatedAt = now
+	}
+	delivery.WebhookID = webhook.ID
+	delivery.URL = webhook.URL
+	delivery.Status = models.DeliveryPending
+	delivery.Attempts = []models.WebhookAttempt{}
+	delivery.UpdatedAt = now
+	if s.deliveries != nil {
+		if err := s.deliveries.Create(ctx, delivery); err != nil {
+			log.Printf("Webhook delivery log create failed: id=%s error=%v", delivery.ID, err)
+		}
+	}
+	return delivery
+}
+
+// recordAttempt menambahkan attempt ke delivery lalu menyimpan status barunya
+func (s *Sender) recordAttempt(ctx context.Context, delivery *models.WebhookDelivery, result *DeliveryResult, status string) {
+	attempt := models.WebhookAttempt{
+		Attempt:      result.Attempt,
+		RequestID:    result.RequestID,
+		At:           time.Now().Add(-result.Latency),
+		StatusCode:   result.StatusCode,
+		ResponseBody: truncate(result.ResponseBody, maxLoggedResponse),
+		LatencyMs:    result.Latency.Milliseconds(),
+	}
+	if result.Error != nil {
+		attempt.Error = result.Error.Error()
+	}
+	delivery.Attempts = append(delivery.Attempts, attempt)
+	s.finishDelivery(ctx, delivery, status)
+}
+
+// finishDelivery menyimpan status delivery; status failed = masuk dead letter
+func (s *Sender) finishDelivery(ctx context.Context, delivery *models.WebhookDelivery, status string) {
+	delivery.Status = status
+	delivery.UpdatedAt = time.Now()
+	if s.deliveries == nil {
+		return
+	}
+
+	if err := s.deliveries.Update(ctx, delivery); err != nil {
+		log.Printf("Webhook delivery log update failed: id=%s error=%v", delivery.ID, err)
+	}
+	if status == models.DeliveryFailed {
+		if err := s.deliveries.PutDeadLetter(ctx, delivery); err != nil {
+			log.Printf("Webhook dead letter write failed: id=%s error=%v", delivery.ID, err)
+		}
+	}
+}
+
+// recordDelivery mencatat hasil akhir delivery (setelah retry) ke /metrics
+func recordDelivery(event, status string, start time.Time) {
+	metrics.GetMetrics().RecordWebhookDelivery(event, status, time.Since(start))
+}
+
+// attemptDelivery makes a single HTTP POST attempt to deliver the webhook.
+// idempotencyKey (header X-Webhook-Id) sama untuk semua attempt satu event, untuk dedupe di receiver.
+// Signature dibuat ulang tiap attempt, jadi timestamp-nya selalu baru.
+// batch > 0: payloadBytes adalah JSON array berisi batch event (lihat wireBody).
+func (s *Sender) attemptDelivery(ctx context.Context, webhook *models.Webhook, payloadBytes []byte, batch int, idempotencyKey string, attempt int) *DeliveryResult {
 	result := &DeliveryResult{
-		Attempt: attempt,
+		RequestID: uuid.NewString(),
+		Attempt:   attempt,
+	}
+	start := time.Now()
+	defer func() { result.Latency = time.Since(start) }()
+
+	body, contentType, err := wireBody(webhook, payloadBytes, batch)
+	if err != nil {
+		result.Error = err
+		return result
 	}
 
 	// Create HTTP request
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payloadBytes))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(body))
 	if err != nil {
 		result.Error = fmt.Errorf("failed to create request: %w", err)
 		return result
 	}
 
 	// Set headers
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("X-Webhook-Signature", signature)
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("X-Webhook-Signature", SignatureHeader(body, start, webhook.SigningSecrets(start)...))
+	req.Header.Set("X-Webhook-Id", idempotencyKey)
+	req.Header.Set("X-Webhook-Request-Id", result.RequestID)
 	req.Header.Set("User-Agent", "NexusLink-Webhook/1.0")
+	if batch > 0 {
+		req.Header.Set("X-Webhook-Batch-Size", strconv.Itoa(batch))
+	}
 
 	// Send request
 	resp, err := s.httpClient.Do(req)
@@ -140,15 +300,88 @@ func (s *Sender) attemptDelivery(ctx context.Context, url string, payloadBytes [
 	return result
 }
 
-// generateSignature creates an HMAC-SHA256 signature of the payload
-func generateSignature(payload []byte, secret string) string {
+// isClientError: 4xx tidak di-retry, kecuali 408 (timeout) dan 429 (rate limited)
+func isClientError(status int) bool {
+	return status >= 400 && status < 500 && status != http.StatusRequestTimeout && status != http.StatusTooManyRequests
+}
+
+// truncate memotong s ke maksimal n byte (tanpa memotong di tengah rune UTF-8)
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n] + "…"
+}
+
+// SignatureHeader membuat nilai X-Webhook-Signature: "t=<unix>,v1=<hex>[,v1=<hex>...]".
+// Tiap v1 = HMAC-SHA256 dari "<t>.<body>" dengan satu secret (primary dulu, lalu secondary saat rotasi).
+func SignatureHeader(payload []byte, t time.Time, secrets ...string) string {
+	ts := t.Unix()
+	parts := make([]string, 0, len(secrets)+1)
+	parts = append(parts, "t="+strconv.FormatInt(ts, 10))
+	for _, secret := range secrets {
+		parts = append(parts, "v1="+sign(payload, ts, secret))
+	}
+	return strings.Join(parts, ",")
+}
+
+// sign creates an HMAC-SHA256 signature of timestamp + payload
+func sign(payload []byte, ts int64, secret string) string {
 	h := hmac.New(sha256.New, []byte(secret))
+	h.Write([]byte(strconv.FormatInt(ts, 10) + "."))
 	h.Write(payload)
 	return hex.EncodeToString(h.Sum(nil))
 }
 
-// VerifySignature verifies an HMAC-SHA256 signature
-func VerifySignature(payload []byte, signature, secret string) bool {
-	expectedSignature := generateSignature(payload, secret)
-	return hmac.Equal([]byte(signature), []byte(expectedSignature))
+// VerifySignature memeriksa header X-Webhook-Signature untuk payload: salah satu v1 harus cocok
+// dengan secret dan timestamp tidak berselisih lebih dari tolerance dengan sekarang
+// (tolerance <= 0 = DefaultSignatureTolerance). Versi selain v1 diabaikan.
+func VerifySignature(payload []byte, header, secret string, tolerance time.Duration) error {
+	return verifySignature(payload, header, secret, tolerance, time.Now())
+}
+
+func verifySignature(payload []byte, header, secret string, tolerance time.Duration, now time.Time) error {
+	if tolerance <= 0 {
+		tolerance = DefaultSignatureTolerance
+	}
+
+	var (
+		ts         int64
+		haveTS     bool
+		signatures []string
+	)
+	for _, part := range strings.Split(header, ",") {
+		k, v, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			return ErrSignatureFormat
+		}
+		switch k {
+		case "t":
+			n, err := strconv.ParseInt(v, 10, 64)
+			if err != nil {
+				return ErrSignatureFormat
+			}
+			ts, haveTS = n, true
+		case "v1":
+			signatures = append(signatures, v)
+		}
+	}
+	if !haveTS || len(signatures) == 0 {
+		return ErrSignatureFormat
+	}
+
+	if age := now.Sub(time.Unix(ts, 0)); age > tolerance || age < -tolerance {
+		return ErrSignatureExpired
+	}
+
+	expected := []byte(sign(payload, ts, secret))
+	for _, sig := range signatures {
+		if hmac.Equal([]byte(sig), expected) {
+			return nil
+		}
+	}
+	return ErrSignatureMismatch
 }
@@ -0,0 +1,593 @@
+package webhook
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/afuzapratama/nexuslink/internal/models"
+	"github.com/afuzapratama/nexuslink/internal/repository"
+)
+
+// fakeDeliveries menyimpan delivery log dan dead letter di memory
+type fakeDeliveries struct {
+	repository.WebhookDeliveryStore
+	mu          sync.Mutex
+	deliveries  map[string]models.WebhookDelivery
+	deadLetters map[string]models.WebhookDelivery
+}
+
+func newFakeDeliveries() *fakeDeliveries {
+	return &fakeDeliveries{
+		deliveries:  map[string]models.WebhookDelivery{},
+		deadLetters: map[string]models.WebhookDelivery{},
+	}
+}
+
+func (f *fakeDeliveries) Create(ctx context.Context, d *models.WebhookDelivery) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.deliveries[d.ID] = *d
+	return nil
+}
+
+func (f *fakeDeliveries) Update(ctx context.Context, d *models.WebhookDelivery) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	d2 := *d
+	d2.Attempts = append([]models.WebhookAttempt(nil), d.Attempts...)
+	f.deliveries[d.ID] = d2
+	return nil
+}
+
+func (f *fakeDeliveries) PutDeadLetter(ctx context.Context, d *models.WebhookDelivery) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.deadLetters[d.ID] = *d
+	return nil
+}
+
+func (f *fakeDeliveries) DeleteDeadLetter(ctx context.Context, webhookID, id string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.deadLetters, id)
+	return nil
+}
+
+func (f *fakeDeliveries) Get(ctx context.Context, webhookID, id string) (*models.WebhookDelivery, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	d, ok := f.deliveries[id]
+	if !ok {
+		return nil, nil
+	}
+	d.Attempts = append([]models.WebhookAttempt(nil), d.Attempts...)
+	return &d, nil
+}
+
+func (f *fakeDeliveries) get(id string) (models.WebhookDelivery, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	d, ok := f.deliveries[id]
+	return d, ok
+}
+
+func newTestSender(store repository.WebhookDeliveryStore) *Sender {
+	s := NewSender(store)
+	s.backoff = time.Millisecond
+	return s
+}
+
+func TestSendWebhookLogsAttempts(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Webhook-Request-Id") == "" {
+			t.Errorf("missing request id header")
+		}
+		if atomic.AddInt32(&calls, 1) == 1 {
+			http.Error(w, strings.Repeat("x", 3*maxLoggedResponse), http.StatusBadGateway)
+			return
+		}
+		w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+
+	store := newFakeDeliveries()
+	wh := &models.Webhook{ID: "wh1", URL: srv.URL, Secret: "s3cret"}
+	result, err := newTestSender(store).SendWebhook(context.Background(), wh, &models.WebhookPayload{Event: models.EventLinkCreated})
+	if err != nil || !result.Success || result.Attempt != 2 {
+		t.Fatalf("unexpected result: %+v err=%v", result, err)
+	}
+
+	d, ok := store.deliveries[result.DeliveryID]
+	if !ok {
+		t.Fatalf("delivery %s not logged", result.DeliveryID)
+	}
+	if d.Status != models.DeliverySuccess || len(d.Attempts) != 2 {
+		t.Fatalf("unexpected delivery: %+v", d)
+	}
+	first := d.Attempts[0]
+	if first.StatusCode != http.StatusBadGateway || first.RequestID == "" || first.RequestID == d.Attempts[1].RequestID {
+		t.Errorf("unexpected first attempt: %+v", first)
+	}
+	if len(first.ResponseBody) > maxLoggedResponse+len("…") {
+		t.Errorf("response body not truncated: %d bytes", len(first.ResponseBody))
+	}
+	if len(store.deadLetters) != 0 {
+		t.Errorf("successful delivery dead-lettered")
+	}
+}
+
+func TestSendWebhookDeadLetterAndReplay(t *testing.T) {
+	var healthy atomic.Bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !healthy.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	store := newFakeDeliveries()
+	sender := newTestSender(store)
+	wh := &models.Webhook{ID: "wh1", URL: srv.URL, Secret: "s3cret"}
+
+	result, err := sender.SendWebhook(context.Background(), wh, &models.WebhookPayload{Event: models.EventClickCreated})
+	if err != nil || result.Success {
+		t.Fatalf("expected failed delivery: %+v err=%v", result, err)
+	}
+	dead, ok := store.deadLetters[result.DeliveryID]
+	if !ok || dead.Status != models.DeliveryFailed || len(dead.Attempts) != maxRetries {
+		t.Fatalf("exhausted delivery not dead-lettered: %+v", dead)
+	}
+
+	healthy.Store(true)
+	replay, err := sender.Replay(context.Background(), wh, &dead)
+	if err != nil || !replay.Success {
+		t.Fatalf("replay failed: %+v err=%v", replay, err)
+	}
+	if len(store.deadLetters) != 0 {
+		t.Errorf("replayed dead letter not removed")
+	}
+	if got := store.deliveries[dead.ID].Status; got != models.DeliveryReplayed {
+		t.Errorf("original status = %q, want replayed", got)
+	}
+	newDelivery := store.deliveries[replay.DeliveryID]
+	if newDelivery.ReplayOf != dead.ID || string(newDelivery.Payload) != string(dead.Payload) {
+		t.Errorf("unexpected replay delivery: %+v", newDelivery)
+	}
+}
+
+// fakeWebhooks: satu webhook yang subscribe semua event
+type fakeWebhooks struct {
+	repository.WebhookStore
+	wh models.Webhook
+}
+
+func (f *fakeWebhooks) GetByEvent(ctx context.Context, event string) ([]models.Webhook, error) {
+	return []models.Webhook{f.wh}, nil
+}
+
+func (f *fakeWebhooks) GetByID(ctx context.Context, id string) (*models.Webhook, error) {
+	if id != f.wh.ID {
+		return nil, repository.ErrWebhookNotFound
+	}
+	wh := f.wh
+	return &wh, nil
+}
+
+// fakeOutbox: outbox in-memory dengan semantik lease yang sama dengan store asli
+type fakeOutbox struct {
+	mu      sync.Mutex
+	entries map[string]models.WebhookOutboxEntry
+}
+
+func (f *fakeOutbox) Enqueue(ctx context.Context, entries []models.WebhookOutboxEntry) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, e := range entries {
+		f.entries[e.ID] = e
+	}
+	return nil
+}
+
+func (f *fakeOutbox) Claim(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]models.WebhookOutboxEntry, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var claimed []models.WebhookOutboxEntry
+	for id, e := range f.entries {
+		if len(claimed) >= limit || e.NextAttemptAt.After(now) {
+			continue
+		}
+		e.NextAttemptAt = now.Add(lease)
+		f.entries[id] = e
+		claimed = append(claimed, e)
+	}
+	return claimed, nil
+}
+
+func (f *fakeOutbox) Reschedule(ctx context.Context, e *models.WebhookOutboxEntry) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.entries[e.ID]; ok {
+		f.entries[e.ID] = *e
+	}
+	return nil
+}
+
+func (f *fakeOutbox) Delete(ctx context.Context, id string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.entries, id)
+	return nil
+}
+
+func (f *fakeOutbox) len() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.entries)
+}
+
+func TestDispatcherRetriesFromOutbox(t *testing.T) {
+	var (
+		mu  sync.Mutex
+		ids []string
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		ids = append(ids, r.Header.Get("X-Webhook-Id"))
+		n := len(ids)
+		mu.Unlock()
+		if n < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	deliveries := newFakeDeliveries()
+	outbox := &fakeOutbox{entries: map[string]models.WebhookOutboxEntry{}}
+	webhooks := &fakeWebhooks{wh: models.Webhook{ID: "wh1", URL: srv.URL, Secret: "s3cret", IsActive: true}}
+
+	d := NewDispatcher(newTestSender(deliveries), webhooks, outbox, 0, 0, time.Minute)
+	d.backoff = time.Millisecond
+	d.poll = 5 * time.Millisecond
+
+	// Publish sebelum worker jalan: event harus tetap menunggu di outbox
+	if err := d.Publish(context.Background(), models.EventLinkCreated, map[string]interface{}{"alias": "promo"}); err != nil {
+		t.Fatalf("publish: %v", err)
+	}
+	if outbox.len() != 1 {
+		t.Fatalf("outbox has %d entries, want 1", outbox.len())
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		d.Run(ctx)
+		close(done)
+	}()
+	deadline := time.Now().Add(5 * time.Second)
+	for outbox.len() > 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	cancel()
+	<-done
+
+	if outbox.len() != 0 {
+		t.Fatalf("outbox not drained")
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if len(ids) != 3 || ids[0] == "" || ids[0] != ids[1] || ids[1] != ids[2] {
+		t.Fatalf("X-Webhook-Id not stable across retries: %v", ids)
+	}
+	delivery, ok := deliveries.get(ids[0])
+	if !ok || delivery.Status != models.DeliverySuccess || len(delivery.Attempts) != 3 {
+		t.Fatalf("unexpected delivery log: %+v", delivery)
+	}
+}
+
+func TestDispatcherBackoff(t *testing.T) {
+	d := NewDispatcher(NewSender(nil), nil, nil, 0, 0, 0)
+	for attempt, want := range map[int]time.Duration{1: time.Second, 3: 4 * time.Second, 40: maxBackoff} {
+		got := d.backoffFor(attempt)
+		if got < want/2 || got > want {
+			t.Errorf("backoffFor(%d) = %s, want in [%s, %s]", attempt, got, want/2, want)
+		}
+	}
+}
+
+func TestSignatureVerify(t *testing.T) {
+	body := []byte(`{"event":"link.created"}`)
+	now := time.Unix(1_700_000_000, 0)
+	header := SignatureHeader(body, now, "new-secret", "old-secret")
+
+	if !strings.HasPrefix(header, "t=1700000000,v1=") || strings.Count(header, "v1=") != 2 {
+		t.Fatalf("unexpected header: %s", header)
+	}
+	for _, secret := range []string{"new-secret", "old-secret"} {
+		if err := verifySignature(body, header, secret, 0, now.Add(time.Minute)); err != nil {
+			t.Errorf("verify with %s: %v", secret, err)
+		}
+	}
+
+	cases := []struct {
+		name   string
+		body   []byte
+		header string
+		secret string
+		now    time.Time
+		want   error
+	}{
+		{"wrong secret", body, header, "other", now, ErrSignatureMismatch},
+		{"tampered body", []byte(`{"event":"link.deleted"}`), header, "new-secret", now, ErrSignatureMismatch},
+		{"replayed later", body, header, "new-secret", now.Add(DefaultSignatureTolerance + time.Second), ErrSignatureExpired},
+		{"from the future", body, header, "new-secret", now.Add(-DefaultSignatureTolerance - time.Second), ErrSignatureExpired},
+		{"legacy header", body, sign(body, now.Unix(), "new-secret"), "new-secret", now, ErrSignatureFormat},
+		{"missing timestamp", body, "v1=" + sign(body, now.Unix(), "new-secret"), "new-secret", now, ErrSignatureFormat},
+	}
+	for _, tc := range cases {
+		if err := verifySignature(tc.body, tc.header, tc.secret, 0, tc.now); err != tc.want {
+			t.Errorf("%s: got %v, want %v", tc.name, err, tc.want)
+		}
+	}
+}
+
+func TestSendWebhookSignsWithRotatingSecrets(t *testing.T) {
+	expired := time.Now().Add(-time.Minute)
+	for _, tc := range []struct {
+		name      string
+		expiresAt *time.Time
+		oldValid  bool
+	}{
+		{"during overlap", nil, true},
+		{"after overlap", &expired, false},
+	} {
+		var header string
+		var body []byte
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			header = r.Header.Get("X-Webhook-Signature")
+			body, _ = io.ReadAll(r.Body)
+		}))
+
+		wh := &models.Webhook{ID: "wh1", URL: srv.URL, Secret: "new", SecondarySecret: "old", SecondarySecretExpiresAt: tc.expiresAt}
+		if _, err := newTestSender(nil).SendWebhook(context.Background(), wh, &models.WebhookPayload{Event: models.EventLinkCreated}); err != nil {
+			t.Fatalf("%s: send: %v", tc.name, err)
+		}
+		srv.Close()
+
+		if err := VerifySignature(body, header, "new", 0); err != nil {
+			t.Errorf("%s: primary secret rejected: %v", tc.name, err)
+		}
+		if err := VerifySignature(body, header, "old", 0); (err == nil) != tc.oldValid {
+			t.Errorf("%s: old secret verify = %v, want valid=%v", tc.name, err, tc.oldValid)
+		}
+	}
+}
+
+func TestPublishFilter(t *testing.T) {
+	humans := false
+	outbox := &fakeOutbox{entries: map[string]models.WebhookOutboxEntry{}}
+	webhooks := &fakeWebhooks{wh: models.Webhook{
+		ID: "wh1", URL: "http://example.invalid", Secret: "s3cret", IsActive: true,
+		Filter: &models.WebhookFilter{Aliases: []string{"Promo"}, Countries: []string{"id", "sg"}, IsBot: &humans},
+	}}
+	d := NewDispatcher(newTestSender(nil), webhooks, outbox, 0, 0, time.Minute)
+
+	cases := []struct {
+		event string
+		data  map[string]interface{}
+		want  bool
+	}{
+		{models.EventClickCreated, map[string]interface{}{"alias": "promo", "country": "ID", "isBot": false}, true},
+		{models.EventClickCreated, map[string]interface{}{"alias": "promo", "country": "US", "isBot": false}, false},
+		{models.EventClickCreated, map[string]interface{}{"alias": "promo", "country": "SG", "isBot": true}, false},
+		{models.EventClickCreated, map[string]interface{}{"alias": "other", "country": "ID", "isBot": false}, false},
+		// Event tanpa field country/isBot hanya difilter alias
+		{models.EventLinkDeleted, map[string]interface{}{"alias": "promo"}, true},
+		{models.EventNodeOffline, map[string]interface{}{"nodeId": "n1"}, true},
+	}
+	for _, c := range cases {
+		before := outbox.len()
+		if err := d.Publish(context.Background(), c.event, c.data); err != nil {
+			t.Fatalf("publish: %v", err)
+		}
+		if got := outbox.len() > before; got != c.want {
+			t.Errorf("%s %v: enqueued=%v, want %v", c.event, c.data, got, c.want)
+		}
+	}
+}
+
+// countingWebhooks menghitung query GetByEvent ke store
+type countingWebhooks struct {
+	fakeWebhooks
+	gets int
+}
+
+func (c *countingWebhooks) GetByEvent(ctx context.Context, event string) ([]models.Webhook, error) {
+	c.gets++
+	return c.fakeWebhooks.GetByEvent(ctx, event)
+}
+
+func TestPublishCachesSubscriptions(t *testing.T) {
+	ctx := context.Background()
+	outbox := &fakeOutbox{entries: map[string]models.WebhookOutboxEntry{}}
+	webhooks := &countingWebhooks{fakeWebhooks: fakeWebhooks{wh: models.Webhook{
+		ID: "wh1", URL: "http://example.invalid", Secret: "s3cret", IsActive: true,
+		Format: models.WebhookFormatTemplate, Template: `{"alias": {{json .Data.alias}}}`,
+	}}}
+	d := NewDispatcher(newTestSender(nil), webhooks, outbox, 0, 0, time.Minute)
+
+	// Redirect berikutnya memakai cache, bukan query store
+	for i := 0; i < 10; i++ {
+		if err := d.Publish(ctx, models.EventClickCreated, map[string]interface{}{"alias": "promo"}); err != nil {
+			t.Fatalf("publish: %v", err)
+		}
+	}
+	if webhooks.gets != 1 {
+		t.Errorf("store queried %d times for 10 events, want 1", webhooks.gets)
+	}
+	if outbox.len() != 10 {
+		t.Fatalf("outbox has %d entries, want 10", outbox.len())
+	}
+
+	// Setelah CRUD, Refresh membaca ulang webhook dan template baru (UpdatedAt berubah) dipakai
+	webhooks.wh.Template = `{"changed": true}`
+	webhooks.wh.UpdatedAt = time.Now()
+	if err := d.Refresh(ctx); err != nil {
+		t.Fatalf("refresh: %v", err)
+	}
+	if err := d.Publish(ctx, models.EventClickCreated, map[string]interface{}{"alias": "promo"}); err != nil {
+		t.Fatalf("publish: %v", err)
+	}
+	if webhooks.gets != 2 {
+		t.Errorf("store queried %d times after refresh, want 2", webhooks.gets)
+	}
+	changed := 0
+	for _, e := range outbox.entries {
+		if string(e.Payload) == `{"changed": true}` {
+			changed++
+		}
+	}
+	if changed != 1 {
+		t.Errorf("%d entries rendered with the updated template, want 1", changed)
+	}
+}
+
+func TestRender(t *testing.T) {
+	payload := &models.WebhookPayload{
+		Event:     models.EventClickCreated,
+		Timestamp: time.Unix(1700000000, 0).UTC(),
+		Data:      map[string]interface{}{"alias": "promo", "domain": "go.example.com", "country": "ID", "isBot": false},
+	}
+
+	body, err := Render(&models.Webhook{Format: models.WebhookFormatSlack}, payload)
+	if err != nil {
+		t.Fatalf("slack: %v", err)
+	}
+	if string(body) != `{"text":"🔗 Click on go.example.com/promo by visitor from ID"}` {
+		t.Fatalf("slack body = %s", body)
+	}
+
+	tmpl := &models.Webhook{Format: models.WebhookFormatTemplate, Template: `{"event": {{json .Event}}, "alias": {{json .Data.alias}}, "msg": {{json (summary .)}}}`}
+	if err := Validate(tmpl); err != nil {
+		t.Fatalf("validate: %v", err)
+	}
+	body, err = Render(tmpl, payload)
+	if err != nil {
+		t.Fatalf("template: %v", err)
+	}
+	if !strings.Contains(string(body), `"alias": "promo"`) || !strings.Contains(string(body), `"event": "click.created"`) {
+		t.Fatalf("template body = %s", body)
+	}
+
+	if _, err := Render(&models.Webhook{Format: models.WebhookFormatTemplate, Template: `alias={{.Data.alias}}`}, payload); err == nil {
+		t.Fatal("expected error for non-JSON template output")
+	}
+	for _, wh := range []models.Webhook{
+		{Format: "xml"},
+		{Format: models.WebhookFormatTemplate},
+		{Format: models.WebhookFormatTemplate, Template: `{{.Event`},
+	} {
+		if err := Validate(&wh); err == nil {
+			t.Errorf("Validate(%q, %q) = nil, want error", wh.Format, wh.Template)
+		}
+	}
+}
+
+func TestDispatcherBatchesClicks(t *testing.T) {
+	type request struct {
+		contentType, batchSize string
+		body                   []byte
+		verify                 error
+	}
+	var (
+		mu   sync.Mutex
+		reqs []request
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		reqs = append(reqs, request{
+			contentType: r.Header.Get("Content-Type"),
+			batchSize:   r.Header.Get("X-Webhook-Batch-Size"),
+			body:        body,
+			verify:      VerifySignature(body, r.Header.Get("X-Webhook-Signature"), "s3cret", DefaultSignatureTolerance),
+		})
+		mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	deliveries := newFakeDeliveries()
+	outbox := &fakeOutbox{entries: map[string]models.WebhookOutboxEntry{}}
+	webhooks := &fakeWebhooks{wh: models.Webhook{
+		ID: "wh1", URL: srv.URL, Secret: "s3cret", IsActive: true,
+		Batch: &models.WebhookBatch{Size: 3, Window: 60, Encoding: models.WebhookBatchNDJSON},
+	}}
+	d := NewDispatcher(newTestSender(deliveries), webhooks, outbox, 0, 0, time.Minute)
+	d.poll = 5 * time.Millisecond
+
+	ctx := context.Background()
+	for _, alias := range []string{"a", "b", "c", "d"} {
+		if err := d.Publish(ctx, models.EventClickCreated, map[string]interface{}{"alias": alias}); err != nil {
+			t.Fatalf("publish: %v", err)
+		}
+	}
+	// Event selain click.created tidak di-batch
+	if err := d.Publish(ctx, models.EventLinkCreated, map[string]interface{}{"alias": "e"}); err != nil {
+		t.Fatalf("publish: %v", err)
+	}
+	if outbox.len() != 2 {
+		t.Fatalf("outbox has %d entries, want 2 (full batch + link.created)", outbox.len())
+	}
+
+	runCtx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		d.Run(runCtx)
+		close(done)
+	}()
+	deadline := time.Now().Add(5 * time.Second)
+	for outbox.len() > 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	cancel()
+	<-done
+
+	// Batch yang belum penuh masuk outbox saat shutdown
+	if outbox.len() != 1 {
+		t.Fatalf("outbox has %d entries after shutdown, want 1 pending batch", outbox.len())
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(reqs) != 2 {
+		t.Fatalf("got %d requests, want 2", len(reqs))
+	}
+	var batch *request
+	for i := range reqs {
+		if reqs[i].verify != nil {
+			t.Fatalf("signature verify: %v", reqs[i].verify)
+		}
+		if reqs[i].batchSize != "" {
+			batch = &reqs[i]
+		}
+	}
+	if batch == nil || batch.batchSize != "3" || batch.contentType != "application/x-ndjson" {
+		t.Fatalf("unexpected batch request: %+v", batch)
+	}
+	lines := strings.Split(strings.TrimSuffix(string(batch.body), "\n"), "\n")
+	if len(lines) != 3 || !strings.Contains(lines[0], `"alias":"a"`) || !strings.Contains(lines[2], `"alias":"c"`) {
+		t.Fatalf("unexpected ndjson body: %q", batch.body)
+	}
+}